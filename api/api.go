@@ -22,6 +22,7 @@ type API struct {
 	encoders   *httpstream.Registry
 	wsOptions  httpstream.WSOptions
 	wsDisabled bool
+	resume     *httpstream.ResumeStore
 
 	// shutdownOnce + baseCtx mirror the proxy package's pattern: every
 	// streaming request derives its ctx from baseCtx via streamContext, so
@@ -56,6 +57,12 @@ func WithoutWebSocket() Option {
 	return func(a *API) { a.wsDisabled = true }
 }
 
+// WithResume makes streaming completions resumable via Last-Event-ID or
+// `?resume=<request_id>`; see proxy.WithResume.
+func WithResume(store *httpstream.ResumeStore) Option {
+	return func(a *API) { a.resume = store }
+}
+
 // New creates a new API handler set.
 func New(gw *nexus.Gateway, opts ...Option) *API {
 	baseCtx, baseCancel := context.WithCancel(context.Background())
@@ -108,10 +115,19 @@ func (a *API) streamContext(reqCtx context.Context) (context.Context, context.Ca
 func (a *API) registerRoutes() {
 	// Completion routes
 	a.mux.HandleFunc("POST /v1/chat/completions", a.handleCreateCompletion)
+	if a.resume != nil {
+		a.mux.HandleFunc("GET /v1/chat/completions", a.handleResumeCompletion)
+	}
 
 	// Embedding routes
 	a.mux.HandleFunc("POST /v1/embeddings", a.handleCreateEmbedding)
 
+	// Image routes
+	a.mux.HandleFunc("POST /v1/images/generations", a.handleCreateImage)
+
+	// Rerank routes
+	a.mux.HandleFunc("POST /v1/rerank", a.handleRerank)
+
 	// Model routes
 	a.mux.HandleFunc("GET /v1/models", a.handleListModels)
 	a.mux.HandleFunc("GET /v1/models/{model}", a.handleGetModel)
@@ -131,6 +147,18 @@ func (a *API) registerRoutes() {
 	// Admin: Usage routes
 	a.mux.HandleFunc("GET /admin/usage", a.handleGetUsage)
 
+	// Admin: RAG document routes
+	a.mux.HandleFunc("POST /admin/rag/documents", a.handleIngestDocuments)
+	a.mux.HandleFunc("DELETE /admin/rag/documents/{id}", a.handleDeleteDocument)
+
+	// Admin: Prompt template routes
+	a.mux.HandleFunc("POST /admin/prompts", a.handleCreatePrompt)
+	a.mux.HandleFunc("GET /admin/prompts", a.handleListPrompts)
+	a.mux.HandleFunc("GET /admin/prompts/{name}", a.handleGetPrompt)
+	a.mux.HandleFunc("GET /admin/prompts/{name}/versions", a.handleListPromptVersions)
+	a.mux.HandleFunc("GET /admin/prompts/{name}/labels", a.handleListPromptLabels)
+	a.mux.HandleFunc("PUT /admin/prompts/{name}/labels/{label}", a.handleSetPromptLabel)
+
 	// Admin: Provider routes
 	a.mux.HandleFunc("GET /admin/providers", a.handleListProviders)
 
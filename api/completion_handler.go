@@ -7,11 +7,19 @@ import (
 	"net/http"
 
 	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/id"
 	"github.com/xraph/nexus/pipeline"
 	"github.com/xraph/nexus/provider"
 )
 
 func (a *API) handleCreateCompletion(w http.ResponseWriter, r *http.Request) {
+	if a.resume != nil {
+		if _, _, ok := httpstream.ResumePoint(r); ok {
+			a.handleResumeCompletion(w, r)
+			return
+		}
+	}
+
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "failed to read request body")
@@ -50,20 +58,76 @@ func (a *API) handleCreateCompletion(w http.ResponseWriter, r *http.Request) {
 func (a *API) handleStreamCompletion(_ context.Context, w http.ResponseWriter, r *http.Request, req *provider.CompletionRequest) {
 	ctx, cancel := a.streamContext(r.Context())
 	defer cancel()
-	stream, err := a.gw.Engine().CompleteStream(ctx, req)
+
+	encoder := httpstream.Negotiate(r, a.encoders)
+	if encoder == nil {
+		writeError(w, http.StatusInternalServerError, "no stream encoder available")
+		return
+	}
+
+	requestID := pipeline.RequestID(ctx)
+	var stream provider.Stream
+	var err error
+	if a.resume != nil {
+		stream, requestID, err = a.openResumable(r.Context(), req)
+	} else {
+		stream, err = a.gw.Engine().CompleteStream(ctx, req)
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	httpstream.Run(ctx, w, stream, encoder, httpstream.RunOptions{
+		RequestID: requestID,
+	})
+}
+
+// openResumable starts a completion stream that keeps draining into the
+// resume store after the client disconnects, minting a request ID to
+// resume by when the caller did not provide one.
+func (a *API) openResumable(reqCtx context.Context, req *provider.CompletionRequest) (provider.Stream, string, error) {
+	requestID := pipeline.RequestID(reqCtx)
+	if requestID == "" {
+		requestID = id.NewRequestID().String()
+		reqCtx = pipeline.WithRequestID(reqCtx, requestID)
+	}
+
+	upCtx, upCancel := a.streamContext(context.WithoutCancel(reqCtx))
+	stream, err := a.gw.Engine().CompleteStream(upCtx, req)
+	if err != nil {
+		upCancel()
+		return nil, "", err
+	}
+	return a.resume.Track(upCtx, requestID, pipeline.TenantID(reqCtx), stream, upCancel), requestID, nil
+}
+
+// handleResumeCompletion reattaches a reconnecting client to a buffered
+// stream, replaying the events after its Last-Event-ID.
+func (a *API) handleResumeCompletion(w http.ResponseWriter, r *http.Request) {
+	requestID, after, ok := httpstream.ResumePoint(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Last-Event-ID or resume is required")
+		return
+	}
+
+	ctx, cancel := a.streamContext(r.Context())
+	defer cancel()
+
 	encoder := httpstream.Negotiate(r, a.encoders)
 	if encoder == nil {
-		_ = stream.Close()
 		writeError(w, http.StatusInternalServerError, "no stream encoder available")
 		return
 	}
 
+	stream, ok := a.resume.Resume(ctx, requestID, pipeline.TenantID(ctx), after)
+	if !ok {
+		writeError(w, http.StatusNotFound, "no such stream: "+requestID)
+		return
+	}
+
 	httpstream.Run(ctx, w, stream, encoder, httpstream.RunOptions{
-		RequestID: pipeline.RequestID(ctx),
+		RequestID:   requestID,
+		LastEventID: after,
 	})
 }
@@ -17,23 +17,22 @@ func (a *API) handleCreateEmbedding(w http.ResponseWriter, r *http.Request) {
 	defer func() { _ = r.Body.Close() }()
 
 	// Parse request — accept string or array for "input"
-	var raw map[string]json.RawMessage
+	var raw struct {
+		provider.EmbeddingRequest
+		Input json.RawMessage `json:"input"`
+	}
 	if unmarshalErr := json.Unmarshal(body, &raw); unmarshalErr != nil {
 		writeError(w, http.StatusBadRequest, "invalid JSON")
 		return
 	}
 
-	var req provider.EmbeddingRequest
-	if modelRaw, ok := raw["model"]; ok {
-		_ = json.Unmarshal(modelRaw, &req.Model) //nolint:errcheck // best-effort; validated below
-	}
-
-	if inputRaw, ok := raw["input"]; ok {
+	req := raw.EmbeddingRequest
+	if len(raw.Input) > 0 {
 		var single string
-		if unmarshalErr := json.Unmarshal(inputRaw, &single); unmarshalErr == nil {
+		if unmarshalErr := json.Unmarshal(raw.Input, &single); unmarshalErr == nil {
 			req.Input = []string{single}
 		} else {
-			_ = json.Unmarshal(inputRaw, &req.Input) //nolint:errcheck // best-effort; validated below
+			_ = json.Unmarshal(raw.Input, &req.Input) //nolint:errcheck // best-effort; validated below
 		}
 	}
 
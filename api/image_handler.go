@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+)
+
+func (a *API) handleCreateImage(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	var req provider.ImageRequest
+	if unmarshalErr := json.Unmarshal(body, &req); unmarshalErr != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+unmarshalErr.Error())
+		return
+	}
+
+	if req.Prompt == "" {
+		writeError(w, http.StatusBadRequest, "prompt is required")
+		return
+	}
+
+	resp, err := a.gw.Engine().GenerateImage(r.Context(), &req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
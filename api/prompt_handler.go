@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/prompt"
+)
+
+// setLabelRequest is the body of PUT /admin/prompts/{name}/labels/{label}.
+type setLabelRequest struct {
+	TenantID string `json:"tenant_id,omitempty"`
+	Version  int    `json:"version"`
+}
+
+func (a *API) handleCreatePrompt(w http.ResponseWriter, r *http.Request) {
+	if a.gw.Prompts() == nil {
+		writeError(w, http.StatusNotImplemented, "prompt registry not configured")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	var input prompt.CreateInput
+	if unmarshalErr := json.Unmarshal(body, &input); unmarshalErr != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+unmarshalErr.Error())
+		return
+	}
+	if input.Name == "" || input.Content == "" {
+		writeError(w, http.StatusBadRequest, "name and content are required")
+		return
+	}
+
+	t, err := a.gw.Prompts().Create(r.Context(), &input)
+	if err != nil {
+		writePromptError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, t)
+}
+
+func (a *API) handleListPrompts(w http.ResponseWriter, r *http.Request) {
+	if a.gw.Prompts() == nil {
+		writeError(w, http.StatusNotImplemented, "prompt registry not configured")
+		return
+	}
+
+	templates, err := a.gw.Prompts().List(r.Context(), r.URL.Query().Get("tenant_id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": templates,
+	})
+}
+
+// handleGetPrompt resolves ?version= (a number or label) like a request
+// referencing the template would.
+func (a *API) handleGetPrompt(w http.ResponseWriter, r *http.Request) {
+	if a.gw.Prompts() == nil {
+		writeError(w, http.StatusNotImplemented, "prompt registry not configured")
+		return
+	}
+
+	q := r.URL.Query()
+	t, err := a.gw.Prompts().Get(r.Context(), q.Get("tenant_id"), r.PathValue("name"), q.Get("version"))
+	if err != nil {
+		writePromptError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (a *API) handleListPromptVersions(w http.ResponseWriter, r *http.Request) {
+	if a.gw.Prompts() == nil {
+		writeError(w, http.StatusNotImplemented, "prompt registry not configured")
+		return
+	}
+
+	versions, err := a.gw.Prompts().Versions(r.Context(), r.URL.Query().Get("tenant_id"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": versions,
+	})
+}
+
+func (a *API) handleListPromptLabels(w http.ResponseWriter, r *http.Request) {
+	if a.gw.Prompts() == nil {
+		writeError(w, http.StatusNotImplemented, "prompt registry not configured")
+		return
+	}
+
+	labels, err := a.gw.Prompts().Labels(r.Context(), r.URL.Query().Get("tenant_id"), r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": labels,
+	})
+}
+
+func (a *API) handleSetPromptLabel(w http.ResponseWriter, r *http.Request) {
+	if a.gw.Prompts() == nil {
+		writeError(w, http.StatusNotImplemented, "prompt registry not configured")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	var input setLabelRequest
+	if unmarshalErr := json.Unmarshal(body, &input); unmarshalErr != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+unmarshalErr.Error())
+		return
+	}
+
+	l, err := a.gw.Prompts().SetLabel(r.Context(), input.TenantID, r.PathValue("name"), r.PathValue("label"), input.Version)
+	if err != nil {
+		writePromptError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, l)
+}
+
+func writePromptError(w http.ResponseWriter, err error) {
+	if errors.Is(err, prompt.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"net/http"
+
+	"github.com/xraph/nexus/provider"
 )
 
 func (a *API) handleListProviders(w http.ResponseWriter, r *http.Request) {
@@ -9,6 +11,7 @@ func (a *API) handleListProviders(w http.ResponseWriter, r *http.Request) {
 
 	type providerInfo struct {
 		Name         string `json:"name"`
+		Type         string `json:"type"`
 		Healthy      bool   `json:"healthy"`
 		Capabilities any    `json:"capabilities"`
 	}
@@ -17,6 +20,7 @@ func (a *API) handleListProviders(w http.ResponseWriter, r *http.Request) {
 	for _, p := range providers {
 		data = append(data, providerInfo{
 			Name:         p.Name(),
+			Type:         provider.TypeOf(p),
 			Healthy:      p.Healthy(r.Context()),
 			Capabilities: p.Capabilities(),
 		})
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/rag"
+)
+
+// ingestDocumentsRequest is the body of POST /admin/rag/documents.
+type ingestDocumentsRequest struct {
+	Documents []rag.Document `json:"documents"`
+}
+
+func (a *API) handleIngestDocuments(w http.ResponseWriter, r *http.Request) {
+	if a.gw.RAG() == nil {
+		writeError(w, http.StatusNotImplemented, "rag not configured")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	var input ingestDocumentsRequest
+	if unmarshalErr := json.Unmarshal(body, &input); unmarshalErr != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+unmarshalErr.Error())
+		return
+	}
+	if len(input.Documents) == 0 {
+		writeError(w, http.StatusBadRequest, "documents is required")
+		return
+	}
+
+	docs, chunks, err := a.gw.RAG().Ingest(r.Context(), input.Documents...)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"data":   docs,
+		"chunks": chunks,
+	})
+}
+
+func (a *API) handleDeleteDocument(w http.ResponseWriter, r *http.Request) {
+	if a.gw.RAG() == nil {
+		writeError(w, http.StatusNotImplemented, "rag not configured")
+		return
+	}
+
+	id := r.PathValue("id")
+	tenantID := r.URL.Query().Get("tenant_id")
+	if err := a.gw.RAG().Delete(r.Context(), tenantID, id); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
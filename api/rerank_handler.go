@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+)
+
+func (a *API) handleRerank(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	var req provider.RerankRequest
+	if unmarshalErr := json.Unmarshal(body, &req); unmarshalErr != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+unmarshalErr.Error())
+		return
+	}
+
+	if req.Query == "" || len(req.Documents) == 0 {
+		writeError(w, http.StatusBadRequest, "query and documents are required")
+		return
+	}
+
+	resp, err := a.gw.Engine().Rerank(r.Context(), &req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
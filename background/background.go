@@ -0,0 +1,165 @@
+// Package background runs chat completions asynchronously, for calls that
+// outlive an HTTP connection (long o-series or extended-thinking answers
+// behind a load balancer's idle timeout).
+//
+// Creating a background response returns its ID immediately. The gateway
+// streams the completion on a managed worker, persisting the stream frames
+// as they arrive and the merged result when it finishes, so clients can
+// poll, fetch the result later or replay the stream from any frame —
+// matching the background mode of OpenAI's Responses API.
+package background
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// Status is the lifecycle state of a background response.
+type Status string
+
+const (
+	StatusQueued     Status = "queued"
+	StatusInProgress Status = "in_progress"
+	StatusCompleted  Status = "completed"
+	StatusFailed     Status = "failed"
+	StatusCancelled  Status = "cancelled"
+	StatusIncomplete Status = "incomplete" // stopped early by timeout or shutdown; partial frames kept
+)
+
+// Done reports whether the status is terminal.
+func (s Status) Done() bool {
+	switch s {
+	case StatusCompleted, StatusFailed, StatusCancelled, StatusIncomplete:
+		return true
+	}
+	return false
+}
+
+// Endpoints a response can be created through.
+const (
+	// EndpointChatCompletions marks responses created through
+	// /v1/chat/completions, which are rendered as chat completions.
+	EndpointChatCompletions = "/v1/chat/completions"
+
+	// EndpointResponses marks responses created through /v1/responses,
+	// which are rendered as Responses API objects.
+	EndpointResponses = "/v1/responses"
+)
+
+var (
+	// ErrNotFound is returned when a background response does not exist.
+	ErrNotFound = errors.New("nexus: background response not found")
+
+	// ErrClosed is returned by Create once the service is shutting down.
+	ErrClosed = errors.New("nexus: background service is shutting down")
+)
+
+// Response is a background completion and its outcome.
+type Response struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenant_id,omitempty"`
+	KeyID    string `json:"key_id,omitempty"`
+	Status   Status `json:"status"`
+	Model    string `json:"model"`
+
+	// Endpoint is the API the response was created through; it decides
+	// how the response is rendered when fetched.
+	Endpoint string `json:"endpoint"`
+
+	Request *provider.CompletionRequest  `json:"request"`
+	Result  *provider.CompletionResponse `json:"result,omitempty"`
+	Error   *Error                       `json:"error,omitempty"`
+
+	// Frames counts the stream frames persisted so far.
+	Frames int `json:"frames"`
+
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	StartedAt   *time.Time        `json:"started_at,omitempty"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+// Error describes why a background response failed or stopped early.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Frame is one persisted stream chunk. Seq numbers start at 1.
+type Frame struct {
+	ResponseID string                `json:"response_id"`
+	Seq        int                   `json:"seq"`
+	Chunk      *provider.StreamChunk `json:"chunk"`
+}
+
+// CreateInput defines a new background response.
+type CreateInput struct {
+	Request  *provider.CompletionRequest `json:"request"`
+	Endpoint string                      `json:"endpoint,omitempty"` // default /v1/chat/completions
+	Metadata map[string]string           `json:"metadata,omitempty"`
+}
+
+// Service manages background responses.
+type Service interface {
+	// Create persists the request and starts it on a worker. The tenant
+	// and key are taken from the request.
+	Create(ctx context.Context, input *CreateInput) (*Response, error)
+
+	// Record stores a response that was served in the foreground, so it
+	// can be fetched and continued later. It must already be finished.
+	Record(ctx context.Context, resp *Response) error
+
+	// Get returns a background response, or nil if it does not exist.
+	Get(ctx context.Context, responseID string) (*Response, error)
+
+	// Cancel stops a queued or running response. Frames already
+	// produced are kept.
+	Cancel(ctx context.Context, responseID string) (*Response, error)
+
+	// Frames returns the persisted frames after sequence number after.
+	Frames(ctx context.Context, responseID string, after int) ([]Frame, error)
+
+	// Start picks up responses persisted by a previous process: queued
+	// ones are run, interrupted ones are marked incomplete.
+	Start(ctx context.Context) error
+
+	// Shutdown stops accepting work and waits for running responses to
+	// finish. When ctx ends first, the rest are stopped and persisted as
+	// incomplete with the frames produced so far.
+	Shutdown(ctx context.Context) error
+
+	// Close stops every running response immediately.
+	Close() error
+}
+
+// Executor streams a completion, normally through the gateway pipeline.
+type Executor interface {
+	ExecuteStream(ctx context.Context, req *provider.CompletionRequest) (provider.Stream, error)
+}
+
+// Store is the persistence interface for background responses and their
+// stream frames.
+type Store interface {
+	InsertResponse(ctx context.Context, r *Response) error
+	UpdateResponse(ctx context.Context, r *Response) error
+
+	// FindResponse returns nil, nil when the response does not exist.
+	FindResponse(ctx context.Context, responseID string) (*Response, error)
+
+	// ListResponsesByStatus returns every response in one of the given
+	// statuses, oldest first.
+	ListResponsesByStatus(ctx context.Context, statuses ...Status) ([]*Response, error)
+
+	AppendFrames(ctx context.Context, frames []Frame) error
+
+	// ListFrames returns a response's frames with Seq greater than after,
+	// in order.
+	ListFrames(ctx context.Context, responseID string, after int) ([]Frame, error)
+
+	// DeleteResponsesBefore deletes responses that finished before the
+	// given time, with their frames. Unfinished responses are kept.
+	DeleteResponsesBefore(ctx context.Context, before time.Time) error
+}
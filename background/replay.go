@@ -0,0 +1,103 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// DefaultReplayInterval is how often a replay polls for new frames while
+// the response is still running.
+const DefaultReplayInterval = 250 * time.Millisecond
+
+// Replay returns a stream of a response's persisted frames after sequence
+// number after. While the response is running it polls for new frames
+// every interval, so it follows the response live (a flush behind); it
+// ends with io.EOF once the response completes, or with the response's
+// error if it stopped any other way.
+func Replay(svc Service, responseID string, after int, interval time.Duration) provider.Stream {
+	if interval <= 0 {
+		interval = DefaultReplayInterval
+	}
+	return &replayStream{svc: svc, id: responseID, after: after, interval: interval}
+}
+
+type replayStream struct {
+	svc      Service
+	id       string
+	after    int
+	interval time.Duration
+	pending  []Frame
+	usage    *provider.Usage
+	final    *Response // set once the response is seen in a terminal state
+}
+
+func (r *replayStream) Next(ctx context.Context) (*provider.StreamChunk, error) {
+	for {
+		if len(r.pending) > 0 {
+			f := r.pending[0]
+			r.pending = r.pending[1:]
+			r.after = f.Seq
+			if f.Chunk != nil && f.Chunk.Usage != nil {
+				r.usage = f.Chunk.Usage
+			}
+			return f.Chunk, nil
+		}
+		if r.final != nil {
+			return nil, r.end()
+		}
+
+		// Check the status before listing, so a response that finishes in
+		// between still has its last frames read.
+		resp, err := r.svc.Get(ctx, r.id)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return nil, ErrNotFound
+		}
+		frames, err := r.svc.Frames(ctx, r.id, r.after)
+		if err != nil {
+			return nil, err
+		}
+		r.pending = frames
+		if resp.Status.Done() {
+			r.final = resp
+			continue
+		}
+		if len(frames) > 0 {
+			continue
+		}
+
+		t := time.NewTimer(r.interval)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// end is the error that closes a replay of a finished response.
+func (r *replayStream) end() error {
+	if r.final.Status == StatusCompleted {
+		return io.EOF
+	}
+	if r.final.Error != nil {
+		return errors.New(r.final.Error.Message)
+	}
+	return errors.New("background response " + string(r.final.Status))
+}
+
+func (r *replayStream) Close() error { return nil }
+
+func (r *replayStream) Usage() *provider.Usage {
+	if r.usage == nil && r.final != nil && r.final.Result != nil {
+		return &r.final.Result.Usage
+	}
+	return r.usage
+}
@@ -0,0 +1,489 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xraph/nexus/id"
+	"github.com/xraph/nexus/provider"
+)
+
+// Default service settings.
+const (
+	DefaultConcurrency   = 32
+	DefaultTimeout       = time.Hour
+	DefaultFlushEvery    = 16
+	DefaultFlushInterval = time.Second
+	DefaultRetention     = 30 * 24 * time.Hour
+
+	// sweepEvery is the least time between two deletions of expired
+	// responses.
+	sweepEvery = time.Hour
+)
+
+// Option configures the background service.
+type Option func(*service)
+
+// WithConcurrency caps how many responses run at once (default 32).
+// Further responses wait in the queued state.
+func WithConcurrency(n int) Option {
+	return func(s *service) {
+		if n > 0 {
+			s.concurrency = n
+		}
+	}
+}
+
+// WithTimeout caps how long one response may run (default 1h). Responses
+// that hit it end incomplete.
+func WithTimeout(d time.Duration) Option {
+	return func(s *service) {
+		if d > 0 {
+			s.timeout = d
+		}
+	}
+}
+
+// WithFlush sets how often stream frames are persisted: after every n
+// frames or interval, whichever comes first (default 16 frames / 1s).
+func WithFlush(n int, interval time.Duration) Option {
+	return func(s *service) {
+		if n > 0 {
+			s.flushEvery = n
+		}
+		if interval > 0 {
+			s.flushInterval = interval
+		}
+	}
+}
+
+// WithRetention sets how long finished responses, background and stored
+// foreground ones alike, are kept with their frames (default 30 days).
+// Expired responses are deleted at most hourly, as new ones arrive.
+func WithRetention(d time.Duration) Option {
+	return func(s *service) {
+		if d > 0 {
+			s.retention = d
+		}
+	}
+}
+
+// service is the default background service implementation.
+type service struct {
+	store         Store
+	executor      Executor
+	concurrency   int
+	timeout       time.Duration
+	flushEvery    int
+	flushInterval time.Duration
+	retention     time.Duration
+
+	// ctx is cancelled by Close, or by Shutdown once its deadline passes;
+	// it parents every running response.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	slots  chan struct{}
+
+	// mu guards running, closed and lastSweep, and serialises updates to
+	// the in-memory copy of running responses.
+	mu        sync.Mutex
+	running   map[string]*runningResponse
+	closed    bool
+	lastSweep time.Time
+}
+
+type runningResponse struct {
+	resp   *Response
+	cancel context.CancelFunc
+}
+
+// NewService creates a background service. executor streams each
+// response, normally through the gateway pipeline.
+func NewService(store Store, executor Executor, opts ...Option) Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &service{
+		store:         store,
+		executor:      executor,
+		concurrency:   DefaultConcurrency,
+		timeout:       DefaultTimeout,
+		flushEvery:    DefaultFlushEvery,
+		flushInterval: DefaultFlushInterval,
+		retention:     DefaultRetention,
+		ctx:           ctx,
+		cancel:        cancel,
+		running:       make(map[string]*runningResponse),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.slots = make(chan struct{}, s.concurrency)
+	return s
+}
+
+func (s *service) Create(ctx context.Context, input *CreateInput) (*Response, error) {
+	if input == nil || input.Request == nil {
+		return nil, errors.New("nexus: background request is required")
+	}
+	if input.Request.Model == "" {
+		return nil, errors.New("nexus: model is required")
+	}
+	endpoint := input.Endpoint
+	if endpoint == "" {
+		endpoint = EndpointChatCompletions
+	}
+
+	req := *input.Request
+	req.Stream = true
+	metadata := input.Metadata
+	if metadata == nil {
+		metadata = req.Metadata
+	}
+	resp := &Response{
+		ID:        id.NewResponseID().String(),
+		TenantID:  req.TenantID,
+		KeyID:     req.KeyID,
+		Status:    StatusQueued,
+		Model:     req.Model,
+		Endpoint:  endpoint,
+		Request:   &req,
+		Metadata:  metadata,
+		CreatedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return nil, ErrClosed
+	}
+	if err := s.store.InsertResponse(ctx, resp); err != nil {
+		return nil, fmt.Errorf("nexus: insert background response: %w", err)
+	}
+	s.sweep()
+	return s.launch(resp)
+}
+
+func (s *service) Record(ctx context.Context, resp *Response) error {
+	if resp == nil || resp.ID == "" {
+		return errors.New("nexus: response id is required")
+	}
+	if !resp.Status.Done() {
+		return fmt.Errorf("nexus: cannot record a %s response", resp.Status)
+	}
+	if resp.Endpoint == "" {
+		resp.Endpoint = EndpointChatCompletions
+	}
+	if resp.CreatedAt.IsZero() {
+		resp.CreatedAt = time.Now()
+	}
+	if err := s.store.InsertResponse(ctx, resp); err != nil {
+		return fmt.Errorf("nexus: insert response: %w", err)
+	}
+	s.sweep()
+	return nil
+}
+
+// sweep deletes responses that finished before the retention window, at
+// most once per sweepEvery. It runs on the wait group so Shutdown waits
+// for a sweep in progress.
+func (s *service) sweep() {
+	now := time.Now()
+	s.mu.Lock()
+	if s.closed || now.Sub(s.lastSweep) < sweepEvery {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		_ = s.store.DeleteResponsesBefore(s.ctx, now.Add(-s.retention)) //nolint:errcheck // retried by the next sweep
+	}()
+}
+
+// launch starts resp on a worker and returns a snapshot of it. Once the
+// service is closed it returns ErrClosed and the response stays queued for
+// the next Start; the closed check and wg.Add share the lock so Shutdown
+// never waits on a worker that is still being added.
+func (s *service) launch(resp *Response) (*Response, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		cancel()
+		return nil, ErrClosed
+	}
+	s.running[resp.ID] = &runningResponse{resp: resp, cancel: cancel}
+	snapshot := *resp
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		s.run(ctx, resp)
+	}()
+	return &snapshot, nil
+}
+
+func (s *service) Get(ctx context.Context, responseID string) (*Response, error) {
+	s.mu.Lock()
+	if rr, ok := s.running[responseID]; ok {
+		snapshot := *rr.resp
+		s.mu.Unlock()
+		return &snapshot, nil
+	}
+	s.mu.Unlock()
+
+	resp, err := s.store.FindResponse(ctx, responseID)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: find background response: %w", err)
+	}
+	return resp, nil
+}
+
+func (s *service) Cancel(ctx context.Context, responseID string) (*Response, error) {
+	s.mu.Lock()
+	if rr, ok := s.running[responseID]; ok {
+		rr.cancel()
+		snapshot := *rr.resp
+		s.mu.Unlock()
+		return &snapshot, nil
+	}
+	s.mu.Unlock()
+
+	resp, err := s.store.FindResponse(ctx, responseID)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: find background response: %w", err)
+	}
+	if resp == nil {
+		return nil, ErrNotFound
+	}
+	if resp.Status.Done() {
+		return resp, nil
+	}
+
+	// Left queued or running by another process; it can no longer
+	// produce frames here, so record the cancellation.
+	now := time.Now()
+	resp.Status = StatusCancelled
+	resp.CompletedAt = &now
+	if err := s.store.UpdateResponse(ctx, resp); err != nil {
+		return nil, fmt.Errorf("nexus: cancel background response: %w", err)
+	}
+	return resp, nil
+}
+
+func (s *service) Frames(ctx context.Context, responseID string, after int) ([]Frame, error) {
+	frames, err := s.store.ListFrames(ctx, responseID, after)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: list background frames: %w", err)
+	}
+	return frames, nil
+}
+
+func (s *service) Start(ctx context.Context) error {
+	resps, err := s.store.ListResponsesByStatus(ctx, StatusQueued, StatusInProgress)
+	if err != nil {
+		return fmt.Errorf("nexus: list unfinished background responses: %w", err)
+	}
+	for _, resp := range resps {
+		s.mu.Lock()
+		_, inFlight := s.running[resp.ID]
+		s.mu.Unlock()
+		switch {
+		case inFlight:
+		case resp.Status == StatusQueued:
+			// Nothing ran yet, so nothing was billed: run it now.
+			if _, err := s.launch(resp); err != nil {
+				return err
+			}
+		default:
+			// The frames produced before the restart are kept; the
+			// upstream call itself cannot be resumed.
+			now := time.Now()
+			resp.Status = StatusIncomplete
+			resp.CompletedAt = &now
+			resp.Error = &Error{Code: "interrupted", Message: "gateway restarted while the response was running"}
+			if err := s.store.UpdateResponse(ctx, resp); err != nil {
+				return fmt.Errorf("nexus: mark interrupted background response: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *service) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// Out of time: stop the rest, which persist as incomplete.
+		s.cancel()
+		<-done
+		return nil
+	}
+}
+
+func (s *service) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// ──────────────────────────────────────────────────
+// Processing
+// ──────────────────────────────────────────────────
+
+func (s *service) run(ctx context.Context, resp *Response) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		s.finish(resp, nil, s.stopped(ctx))
+		return
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	resp.Status = StatusInProgress
+	resp.StartedAt = &now
+	snapshot := *resp
+	s.mu.Unlock()
+	_ = s.store.UpdateResponse(context.Background(), &snapshot) //nolint:errcheck // persisted again on completion
+
+	// The pipeline may rewrite messages in place; the stored request stays
+	// as submitted.
+	req := *resp.Request
+	req.Messages = slices.Clone(req.Messages)
+	// Fields the store does not persist with the request.
+	req.TenantID = resp.TenantID
+	req.KeyID = resp.KeyID
+	req.Metadata = resp.Metadata
+	stream, err := s.executor.ExecuteStream(ctx, &req)
+	if err != nil {
+		if ctx.Err() != nil {
+			s.finish(resp, nil, s.stopped(ctx))
+			return
+		}
+		s.finish(resp, nil, &Error{Code: "execution_error", Message: err.Error()})
+		return
+	}
+	defer func() { _ = stream.Close() }()
+
+	rec := &recorder{Stream: stream, s: s, resp: resp, lastFlush: time.Now()}
+	result, err := provider.Accumulate(ctx, rec)
+	rec.flush()
+
+	switch {
+	case err == nil:
+		s.finish(resp, result, nil)
+	case ctx.Err() != nil:
+		// Keep what was merged so far alongside the partial frames.
+		s.finish(resp, result, s.stopped(ctx))
+	default:
+		s.finish(resp, nil, &Error{Code: "execution_error", Message: err.Error()})
+	}
+}
+
+// stopped explains why a response's context ended: cancelled by the
+// caller, timed out, or the gateway shut down.
+func (s *service) stopped(ctx context.Context) *Error {
+	switch {
+	case s.ctx.Err() != nil:
+		return &Error{Code: "shutdown", Message: "gateway shut down before the response finished"}
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &Error{Code: "timeout", Message: "response did not finish within the background timeout"}
+	default:
+		return &Error{Code: "cancelled", Message: "response was cancelled"}
+	}
+}
+
+// finish moves resp to its terminal status and persists it.
+func (s *service) finish(resp *Response, result *provider.CompletionResponse, stopErr *Error) {
+	s.mu.Lock()
+	now := time.Now()
+	resp.CompletedAt = &now
+	resp.Result = result
+	resp.Error = stopErr
+	switch {
+	case stopErr == nil:
+		resp.Status = StatusCompleted
+	case stopErr.Code == "cancelled":
+		resp.Status = StatusCancelled
+	case stopErr.Code == "shutdown" || stopErr.Code == "timeout":
+		resp.Status = StatusIncomplete
+	default:
+		resp.Status = StatusFailed
+	}
+	snapshot := *resp
+	delete(s.running, resp.ID)
+	s.mu.Unlock()
+
+	_ = s.store.UpdateResponse(context.Background(), &snapshot) //nolint:errcheck // nothing left to retry with
+}
+
+// recorder tees a stream's chunks into the store while Accumulate merges
+// them, flushing every flushEvery frames or flushInterval.
+type recorder struct {
+	provider.Stream
+	s         *service
+	resp      *Response
+	pending   []Frame
+	lastFlush time.Time
+}
+
+func (r *recorder) Next(ctx context.Context) (*provider.StreamChunk, error) {
+	c, err := r.Stream.Next(ctx)
+	if err != nil || c == nil {
+		return c, err
+	}
+	r.s.mu.Lock()
+	r.resp.Frames++
+	seq := r.resp.Frames
+	r.s.mu.Unlock()
+
+	r.pending = append(r.pending, Frame{ResponseID: r.resp.ID, Seq: seq, Chunk: c})
+	if len(r.pending) >= r.s.flushEvery || time.Since(r.lastFlush) >= r.s.flushInterval {
+		r.flush()
+	}
+	return c, nil
+}
+
+// flush persists pending frames along with the response's frame count.
+func (r *recorder) flush() {
+	r.lastFlush = time.Now()
+	if len(r.pending) == 0 {
+		return
+	}
+	ctx := context.Background()
+	if err := r.s.store.AppendFrames(ctx, r.pending); err != nil {
+		// Keep them for the next attempt rather than leave a gap.
+		return
+	}
+	r.pending = r.pending[:0]
+
+	r.s.mu.Lock()
+	snapshot := *r.resp
+	r.s.mu.Unlock()
+	_ = r.s.store.UpdateResponse(ctx, &snapshot) //nolint:errcheck // the count is persisted again on completion
+}
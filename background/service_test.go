@@ -0,0 +1,301 @@
+package background_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/store"
+)
+
+// streamExecutor streams the given parts, then blocks until gate closes
+// (if set) before ending.
+type streamExecutor struct {
+	parts []string
+	gate  chan struct{}
+}
+
+func (e *streamExecutor) ExecuteStream(_ context.Context, req *provider.CompletionRequest) (provider.Stream, error) {
+	if req.Model == "broken" {
+		return nil, errors.New("upstream error")
+	}
+	return &gatedStream{parts: e.parts, gate: e.gate}, nil
+}
+
+type gatedStream struct {
+	parts []string
+	gate  chan struct{}
+	sent  int
+}
+
+func (s *gatedStream) Next(ctx context.Context) (*provider.StreamChunk, error) {
+	if s.sent == len(s.parts) {
+		if s.gate != nil {
+			select {
+			case <-s.gate:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		return nil, io.EOF
+	}
+	s.sent++
+	return &provider.StreamChunk{ID: "c", Model: "m", Delta: provider.Delta{Content: s.parts[s.sent-1]}}, nil
+}
+
+func (s *gatedStream) Close() error           { return nil }
+func (s *gatedStream) Usage() *provider.Usage { return nil }
+
+func newRequest(model string) *provider.CompletionRequest {
+	return &provider.CompletionRequest{
+		Model:    model,
+		Messages: []provider.Message{{Role: "user", Content: "think hard"}},
+		TenantID: "t1",
+	}
+}
+
+// waitFor polls until the response satisfies cond.
+func waitFor(t *testing.T, svc background.Service, id string, cond func(*background.Response) bool) *background.Response {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := svc.Get(context.Background(), id)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if resp != nil && cond(resp) {
+			return resp
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("response %s never reached the expected state: %+v", id, resp)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func isDone(r *background.Response) bool { return r.Status.Done() }
+
+func content(r *provider.CompletionResponse) string {
+	if r == nil || len(r.Choices) == 0 {
+		return ""
+	}
+	s, _ := r.Choices[0].Message.Content.(string)
+	return s
+}
+
+func TestService_CompletesAndPersistsFrames(t *testing.T) {
+	t.Parallel()
+	st := store.NewMemory().Background()
+	svc := background.NewService(st, &streamExecutor{parts: []string{"a", "b", "c"}}, background.WithFlush(2, time.Hour))
+	t.Cleanup(func() { _ = svc.Close() })
+
+	created, err := svc.Create(context.Background(), &background.CreateInput{Request: newRequest("m")})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.HasPrefix(created.ID, "resp_") || created.TenantID != "t1" {
+		t.Fatalf("unexpected response: %+v", created)
+	}
+
+	done := waitFor(t, svc, created.ID, isDone)
+	if done.Status != background.StatusCompleted || content(done.Result) != "abc" || done.Frames != 3 {
+		t.Fatalf("unexpected result: status=%s content=%q frames=%d", done.Status, content(done.Result), done.Frames)
+	}
+
+	stored, _ := st.FindResponse(context.Background(), created.ID)
+	if stored.Status != background.StatusCompleted || content(stored.Result) != "abc" {
+		t.Fatalf("store not updated: %+v", stored)
+	}
+	frames, _ := svc.Frames(context.Background(), created.ID, 1)
+	if len(frames) != 2 || frames[0].Seq != 2 || frames[1].Chunk.Delta.Content != "c" {
+		t.Fatalf("frames after 1 = %+v", frames)
+	}
+}
+
+func TestService_ExecutionError(t *testing.T) {
+	t.Parallel()
+	svc := background.NewService(store.NewMemory().Background(), &streamExecutor{})
+	t.Cleanup(func() { _ = svc.Close() })
+
+	created, err := svc.Create(context.Background(), &background.CreateInput{Request: newRequest("broken")})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	done := waitFor(t, svc, created.ID, isDone)
+	if done.Status != background.StatusFailed || done.Error == nil || done.Error.Code != "execution_error" {
+		t.Fatalf("unexpected outcome: %+v", done)
+	}
+}
+
+func TestService_Cancel(t *testing.T) {
+	t.Parallel()
+	svc := background.NewService(store.NewMemory().Background(), &streamExecutor{parts: []string{"a"}, gate: make(chan struct{})})
+	t.Cleanup(func() { _ = svc.Close() })
+
+	created, _ := svc.Create(context.Background(), &background.CreateInput{Request: newRequest("m")})
+	waitFor(t, svc, created.ID, func(r *background.Response) bool { return r.Frames == 1 })
+
+	if _, err := svc.Cancel(context.Background(), created.ID); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	done := waitFor(t, svc, created.ID, isDone)
+	if done.Status != background.StatusCancelled || content(done.Result) != "a" {
+		t.Fatalf("unexpected outcome: status=%s content=%q", done.Status, content(done.Result))
+	}
+	if _, err := svc.Cancel(context.Background(), "resp_missing"); !errors.Is(err, background.ErrNotFound) {
+		t.Fatalf("cancel missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestService_ShutdownPersistsIncomplete(t *testing.T) {
+	t.Parallel()
+	st := store.NewMemory().Background()
+	svc := background.NewService(st, &streamExecutor{parts: []string{"a", "b"}, gate: make(chan struct{})})
+
+	created, _ := svc.Create(context.Background(), &background.CreateInput{Request: newRequest("m")})
+	waitFor(t, svc, created.ID, func(r *background.Response) bool { return r.Frames == 2 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := svc.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	stored, _ := st.FindResponse(context.Background(), created.ID)
+	if stored.Status != background.StatusIncomplete || stored.Error == nil || stored.Error.Code != "shutdown" {
+		t.Fatalf("unexpected stored response: %+v", stored)
+	}
+	frames, _ := st.ListFrames(context.Background(), created.ID, 0)
+	if len(frames) != 2 {
+		t.Fatalf("persisted %d frames, want 2", len(frames))
+	}
+	if _, err := svc.Create(context.Background(), &background.CreateInput{Request: newRequest("m")}); !errors.Is(err, background.ErrClosed) {
+		t.Fatalf("create after shutdown: err = %v, want ErrClosed", err)
+	}
+}
+
+func TestService_CreateRacingClose(t *testing.T) {
+	t.Parallel()
+	svc := background.NewService(store.NewMemory().Background(), &streamExecutor{parts: []string{"a"}})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				_, err := svc.Create(context.Background(), &background.CreateInput{Request: newRequest("m")})
+				if err != nil && !errors.Is(err, background.ErrClosed) {
+					t.Errorf("create: %v", err)
+				}
+			}
+		}()
+	}
+	_ = svc.Close()
+	wg.Wait()
+}
+
+func TestService_DeletesExpiredResponses(t *testing.T) {
+	t.Parallel()
+	st := store.NewMemory().Background()
+	svc := background.NewService(st, &streamExecutor{}, background.WithRetention(time.Hour))
+	t.Cleanup(func() { _ = svc.Close() })
+
+	old, recent := time.Now().Add(-2*time.Hour), time.Now().Add(-time.Minute)
+	for _, r := range []*background.Response{
+		{ID: "resp_old", Status: background.StatusCompleted, CompletedAt: &old},
+		{ID: "resp_recent", Status: background.StatusCompleted, CompletedAt: &recent},
+		{ID: "resp_running", Status: background.StatusInProgress, CreatedAt: old},
+	} {
+		if err := st.InsertResponse(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = st.AppendFrames(context.Background(), []background.Frame{{ResponseID: "resp_old", Seq: 1}})
+
+	if err := svc.Record(context.Background(), &background.Response{ID: "resp_new", Status: background.StatusCompleted}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if r, _ := st.FindResponse(context.Background(), "resp_old"); r == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired response was not deleted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if frames, _ := st.ListFrames(context.Background(), "resp_old", 0); len(frames) != 0 {
+		t.Errorf("expired response kept %d frames", len(frames))
+	}
+	for _, id := range []string{"resp_recent", "resp_running", "resp_new"} {
+		if r, _ := st.FindResponse(context.Background(), id); r == nil {
+			t.Errorf("%s was deleted", id)
+		}
+	}
+}
+
+func TestService_StartRecoversPersistedResponses(t *testing.T) {
+	t.Parallel()
+	st := store.NewMemory().Background()
+	queued := &background.Response{ID: "resp_queued", Status: background.StatusQueued, Model: "m", Request: newRequest("m"), CreatedAt: time.Now()}
+	running := &background.Response{ID: "resp_running", Status: background.StatusInProgress, Model: "m", Request: newRequest("m"), CreatedAt: time.Now()}
+	for _, r := range []*background.Response{queued, running} {
+		if err := st.InsertResponse(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := background.NewService(st, &streamExecutor{parts: []string{"ok"}})
+	t.Cleanup(func() { _ = svc.Close() })
+	if err := svc.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	if done := waitFor(t, svc, queued.ID, isDone); done.Status != background.StatusCompleted {
+		t.Fatalf("queued response ended %s", done.Status)
+	}
+	interrupted := waitFor(t, svc, running.ID, isDone)
+	if interrupted.Status != background.StatusIncomplete || interrupted.Error.Code != "interrupted" {
+		t.Fatalf("running response = %+v", interrupted)
+	}
+}
+
+func TestReplay_FollowsUntilDone(t *testing.T) {
+	t.Parallel()
+	gate := make(chan struct{})
+	svc := background.NewService(store.NewMemory().Background(), &streamExecutor{parts: []string{"a", "b"}, gate: gate},
+		background.WithFlush(1, time.Millisecond))
+	t.Cleanup(func() { _ = svc.Close() })
+
+	created, _ := svc.Create(context.Background(), &background.CreateInput{Request: newRequest("m")})
+	replay := background.Replay(svc, created.ID, 1, 5*time.Millisecond)
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		close(gate)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var got strings.Builder
+	for {
+		c, err := replay.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("replay: %v", err)
+		}
+		got.WriteString(c.Delta.Content)
+	}
+	if got.String() != "b" {
+		t.Fatalf("replay after 1 = %q, want \"b\"", got.String())
+	}
+}
@@ -1,7 +1,15 @@
 // Package batch provides batch processing for multiple LLM requests.
+//
+// Jobs read their requests from an uploaded JSONL file (or an inline list)
+// and write results to an output file, matching OpenAI's /v1/files and
+// /v1/batches formats. A job is submitted to the provider's native batch
+// API when one serves its model, and otherwise runs on the gateway's
+// rate-limited worker pool.
 package batch
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/xraph/nexus/provider"
@@ -11,20 +19,51 @@ import (
 type JobStatus string
 
 const (
-	JobPending   JobStatus = "pending"
-	JobRunning   JobStatus = "running"
-	JobCompleted JobStatus = "completed"
-	JobFailed    JobStatus = "failed"
-	JobCancelled JobStatus = "cancelled"
+	JobPending    JobStatus = "pending"
+	JobRunning    JobStatus = "running"
+	JobFinalizing JobStatus = "finalizing" // collecting results into the output file
+	JobCompleted  JobStatus = "completed"
+	JobFailed     JobStatus = "failed"
+	JobCancelling JobStatus = "cancelling"
+	JobCancelled  JobStatus = "cancelled"
+	JobExpired    JobStatus = "expired"
+)
+
+// Done reports whether the status is terminal.
+func (s JobStatus) Done() bool {
+	switch s {
+	case JobCompleted, JobFailed, JobCancelled, JobExpired:
+		return true
+	}
+	return false
+}
+
+// File purposes.
+const (
+	PurposeBatch       = "batch"        // JSONL input uploaded by the caller
+	PurposeBatchOutput = "batch_output" // results and errors written by the gateway
+)
+
+// EndpointChatCompletions is the only endpoint batch lines may target.
+const EndpointChatCompletions = "/v1/chat/completions"
+
+// MaxInputs caps the number of requests in one job.
+const MaxInputs = 50_000
+
+var (
+	// ErrNotFound is returned when a job does not exist.
+	ErrNotFound = errors.New("nexus: batch not found")
+
+	// ErrFileNotFound is returned when a file does not exist.
+	ErrFileNotFound = errors.New("nexus: file not found")
 )
 
 // Job represents a batch processing job.
 type Job struct {
 	ID          string         `json:"id"`
 	TenantID    string         `json:"tenant_id,omitempty"`
+	KeyID       string         `json:"key_id,omitempty"`
 	Status      JobStatus      `json:"status"`
-	Inputs      []Input        `json:"inputs"`
-	Results     []Result       `json:"results,omitempty"`
 	TotalItems  int            `json:"total_items"`
 	Completed   int            `json:"completed"`
 	Failed      int            `json:"failed"`
@@ -32,6 +71,22 @@ type Job struct {
 	StartedAt   *time.Time     `json:"started_at,omitempty"`
 	CompletedAt *time.Time     `json:"completed_at,omitempty"`
 	Metadata    map[string]any `json:"metadata,omitempty"`
+
+	// Files holding the requests, the successful results and the failed
+	// results, all JSONL in OpenAI's batch format.
+	Endpoint         string `json:"endpoint"`
+	InputFileID      string `json:"input_file_id"`
+	OutputFileID     string `json:"output_file_id,omitempty"`
+	ErrorFileID      string `json:"error_file_id,omitempty"`
+	CompletionWindow string `json:"completion_window"`
+
+	// Provider and ProviderBatchID identify a native provider batch; both
+	// are empty when the job runs on the gateway worker pool.
+	Provider        string `json:"provider,omitempty"`
+	ProviderBatchID string `json:"provider_batch_id,omitempty"`
+
+	// Errors lists input lines that failed validation.
+	Errors []Error `json:"errors,omitempty"`
 }
 
 // Input is a single item in a batch job.
@@ -51,12 +106,106 @@ type Result struct {
 type Error struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"` // 1-based input line, for validation errors
 }
 
-// CreateInput defines a new batch job.
+// File is an uploaded batch input or a gateway-written output file.
+type File struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	Filename  string    `json:"filename"`
+	Purpose   string    `json:"purpose"`
+	Bytes     int       `json:"bytes"`
+	Content   []byte    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateInput defines a new batch job. Set either InputFileID, naming an
+// uploaded JSONL file, or Inputs.
 type CreateInput struct {
-	TenantID    string         `json:"tenant_id,omitempty"`
-	Inputs      []Input        `json:"inputs"`
-	Metadata    map[string]any `json:"metadata,omitempty"`
-	Concurrency int            `json:"concurrency,omitempty"` // max parallel requests
+	TenantID         string         `json:"tenant_id,omitempty"`
+	KeyID            string         `json:"key_id,omitempty"`
+	InputFileID      string         `json:"input_file_id,omitempty"`
+	Inputs           []Input        `json:"inputs,omitempty"`
+	Endpoint         string         `json:"endpoint,omitempty"`          // default /v1/chat/completions
+	CompletionWindow string         `json:"completion_window,omitempty"` // default "24h"
+	Metadata         map[string]any `json:"metadata,omitempty"`
+	Concurrency      int            `json:"concurrency,omitempty"` // max parallel requests
+}
+
+// Service manages batch processing jobs.
+type Service interface {
+	// Create validates the input and starts the job in the background.
+	Create(ctx context.Context, input *CreateInput) (*Job, error)
+
+	// Get returns a batch job by ID.
+	Get(ctx context.Context, jobID string) (*Job, error)
+
+	// Cancel cancels a running batch job. Items already finished are
+	// still written to the output file.
+	Cancel(ctx context.Context, jobID string) error
+
+	// List returns batch jobs for a tenant, newest first.
+	List(ctx context.Context, tenantID string) ([]*Job, error)
+
+	// Results reads a finished job's output and error files.
+	Results(ctx context.Context, jobID string) ([]Result, error)
+
+	// UploadFile stores a file. Files with purpose "batch" must hold one
+	// JSON request per line.
+	UploadFile(ctx context.Context, f *File) (*File, error)
+
+	// GetFile returns a file, including its content.
+	GetFile(ctx context.Context, fileID string) (*File, error)
+
+	// ListFiles returns a tenant's files (all files for ""), newest
+	// first, without content.
+	ListFiles(ctx context.Context, tenantID, purpose string) ([]*File, error)
+
+	DeleteFile(ctx context.Context, fileID string) error
+
+	// Start resumes jobs persisted by a previous process: native batches
+	// are polled again and interrupted worker-pool jobs are failed.
+	Start(ctx context.Context) error
+
+	// Close stops polling and cancels worker-pool jobs in flight.
+	Close() error
+}
+
+// Executor processes individual batch requests.
+type Executor interface {
+	Execute(ctx context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error)
+}
+
+// Preparer is implemented by executors that can run a request's input
+// phase (guardrails, transforms, alias resolution) without calling a
+// provider. Native batches submit the prepared requests; a Prepare error
+// sends the job to the worker pool instead.
+type Preparer interface {
+	Prepare(ctx context.Context, req *provider.CompletionRequest) (*provider.CompletionRequest, error)
+}
+
+// Store is the persistence interface for batch jobs and files.
+type Store interface {
+	InsertJob(ctx context.Context, job *Job) error
+	UpdateJob(ctx context.Context, job *Job) error
+
+	// FindJob returns nil, nil when the job does not exist.
+	FindJob(ctx context.Context, jobID string) (*Job, error)
+
+	// ListJobs returns a tenant's jobs (all jobs for ""), newest first.
+	ListJobs(ctx context.Context, tenantID string) ([]*Job, error)
+
+	// ListJobsByStatus returns every job in one of the given statuses.
+	ListJobsByStatus(ctx context.Context, statuses ...JobStatus) ([]*Job, error)
+
+	InsertFile(ctx context.Context, f *File) error
+
+	// FindFile returns nil, nil when the file does not exist.
+	FindFile(ctx context.Context, fileID string) (*File, error)
+
+	// ListFiles returns files without content, newest first. An empty
+	// tenant or purpose matches every file.
+	ListFiles(ctx context.Context, tenantID, purpose string) ([]*File, error)
+	DeleteFile(ctx context.Context, fileID string) error
 }
@@ -0,0 +1,248 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// inputLine is one line of an OpenAI batch input file.
+type inputLine struct {
+	CustomID string          `json:"custom_id"`
+	Method   string          `json:"method"`
+	URL      string          `json:"url"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// outputLine is one line of an OpenAI batch output or error file.
+type outputLine struct {
+	ID       string          `json:"id"`
+	CustomID string          `json:"custom_id"`
+	Response *outputResponse `json:"response"`
+	Error    *Error          `json:"error"`
+}
+
+type outputResponse struct {
+	StatusCode int       `json:"status_code"`
+	RequestID  string    `json:"request_id"`
+	Body       *chatBody `json:"body"`
+}
+
+// chatBody is an OpenAI chat.completion object.
+type chatBody struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+	Usage   chatUsage    `json:"usage"`
+}
+
+type chatChoice struct {
+	Index        int              `json:"index"`
+	Message      provider.Message `json:"message"`
+	FinishReason string           `json:"finish_reason"`
+}
+
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// ParseInputs decodes a JSONL batch input file. Every line must POST a
+// chat completion request to /v1/chat/completions with a unique
+// custom_id; lines that do not are reported as errors.
+func ParseInputs(content []byte) ([]Input, []Error) {
+	var (
+		inputs []Input
+		errs   []Error
+		seen   = make(map[string]bool)
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		in, err := parseInputLine(raw)
+		if err == nil && seen[in.CustomID] {
+			err = fmt.Errorf("duplicate custom_id %q", in.CustomID)
+		}
+		if err != nil {
+			errs = append(errs, Error{Code: "invalid_request", Message: err.Error(), Line: lineNo})
+			continue
+		}
+		seen[in.CustomID] = true
+		inputs = append(inputs, in)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, Error{Code: "invalid_file", Message: err.Error()})
+	}
+	return inputs, errs
+}
+
+func parseInputLine(raw []byte) (Input, error) {
+	var line inputLine
+	if err := json.Unmarshal(raw, &line); err != nil {
+		return Input{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if line.CustomID == "" {
+		return Input{}, fmt.Errorf("custom_id is required")
+	}
+	if line.Method != "" && line.Method != http.MethodPost {
+		return Input{}, fmt.Errorf("method must be POST")
+	}
+	if line.URL != EndpointChatCompletions {
+		return Input{}, fmt.Errorf("url must be %s", EndpointChatCompletions)
+	}
+
+	var req provider.CompletionRequest
+	if err := json.Unmarshal(line.Body, &req); err != nil {
+		return Input{}, fmt.Errorf("invalid body: %w", err)
+	}
+	if err := validateRequest(&req); err != nil {
+		return Input{}, err
+	}
+	return Input{CustomID: line.CustomID, Request: &req}, nil
+}
+
+func validateRequest(req *provider.CompletionRequest) error {
+	switch {
+	case req == nil:
+		return fmt.Errorf("request is required")
+	case req.Model == "":
+		return fmt.Errorf("model is required")
+	case len(req.Messages) == 0:
+		return fmt.Errorf("messages is required")
+	case req.Stream:
+		return fmt.Errorf("streaming is not supported in batches")
+	}
+	return nil
+}
+
+// EncodeInputs writes inputs as a JSONL batch input file.
+func EncodeInputs(inputs []Input) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, in := range inputs {
+		body, err := json.Marshal(in.Request)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: marshal batch input %q: %w", in.CustomID, err)
+		}
+		if err := enc.Encode(inputLine{
+			CustomID: in.CustomID,
+			Method:   http.MethodPost,
+			URL:      EndpointChatCompletions,
+			Body:     body,
+		}); err != nil {
+			return nil, fmt.Errorf("nexus: encode batch input %q: %w", in.CustomID, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeResults writes results as JSONL output lines. lineIDs must yield a
+// unique ID per line.
+func EncodeResults(results []Result, lineID func() string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range results {
+		line := outputLine{ID: lineID(), CustomID: r.CustomID, Error: r.Error}
+		if r.Response != nil {
+			line.Response = &outputResponse{
+				StatusCode: http.StatusOK,
+				RequestID:  r.Response.ID,
+				Body:       toChatBody(r.Response),
+			}
+		}
+		if err := enc.Encode(line); err != nil {
+			return nil, fmt.Errorf("nexus: encode batch result %q: %w", r.CustomID, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseResults decodes JSONL output or error file content.
+func ParseResults(content []byte) ([]Result, error) {
+	var results []Result
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var line outputLine
+		if err := json.Unmarshal(raw, &line); err != nil {
+			return nil, fmt.Errorf("nexus: decode batch result: %w", err)
+		}
+		r := Result{CustomID: line.CustomID, Error: line.Error}
+		if line.Response != nil && line.Response.Body != nil {
+			r.Response = fromChatBody(line.Response.Body)
+		}
+		results = append(results, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("nexus: read batch results: %w", err)
+	}
+	return results, nil
+}
+
+func toChatBody(resp *provider.CompletionResponse) *chatBody {
+	choices := make([]chatChoice, len(resp.Choices))
+	for i, c := range resp.Choices {
+		choices[i] = chatChoice{
+			Index: c.Index,
+			Message: provider.Message{
+				Role:      c.Message.Role,
+				Content:   c.Message.Content,
+				ToolCalls: c.Message.ToolCalls,
+			},
+			FinishReason: c.FinishReason,
+		}
+	}
+	created := resp.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	return &chatBody{
+		ID:      resp.ID,
+		Object:  "chat.completion",
+		Created: created.Unix(),
+		Model:   resp.Model,
+		Choices: choices,
+		Usage: chatUsage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
+	}
+}
+
+func fromChatBody(body *chatBody) *provider.CompletionResponse {
+	choices := make([]provider.Choice, len(body.Choices))
+	for i, c := range body.Choices {
+		choices[i] = provider.Choice{Index: c.Index, Message: c.Message, FinishReason: c.FinishReason}
+	}
+	return &provider.CompletionResponse{
+		ID:      body.ID,
+		Model:   body.Model,
+		Created: time.Unix(body.Created, 0),
+		Choices: choices,
+		Usage: provider.Usage{
+			PromptTokens:     body.Usage.PromptTokens,
+			CompletionTokens: body.Usage.CompletionTokens,
+			TotalTokens:      body.Usage.TotalTokens,
+		},
+	}
+}
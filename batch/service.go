@@ -2,62 +2,171 @@ package batch
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
-	"sync/atomic"
 	"time"
 
+	"golang.org/x/time/rate"
+
+	"github.com/xraph/nexus/id"
+	"github.com/xraph/nexus/model"
+	"github.com/xraph/nexus/pipeline"
 	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/usage"
+)
+
+// Default service settings.
+const (
+	DefaultConcurrency      = 10
+	DefaultPollInterval     = 30 * time.Second
+	DefaultCompletionWindow = "24h"
+
+	// progressEvery is how many finished items a worker-pool job waits
+	// between persisting its counts.
+	progressEvery = 50
 )
 
-// Service manages batch processing jobs.
-type Service interface {
-	// Create creates a new batch job.
-	Create(ctx context.Context, input *CreateInput) (*Job, error)
+// Option configures the batch service.
+type Option func(*service)
+
+// WithConcurrency sets the worker-pool size per job (default 10).
+func WithConcurrency(n int) Option {
+	return func(s *service) {
+		if n > 0 {
+			s.concurrency = n
+		}
+	}
+}
 
-	// Get returns a batch job by ID.
-	Get(ctx context.Context, jobID string) (*Job, error)
+// WithRateLimit caps worker-pool requests per minute across all jobs.
+// Zero means unlimited.
+func WithRateLimit(rpm int) Option {
+	return func(s *service) {
+		if rpm > 0 {
+			s.limiter = rate.NewLimiter(rate.Limit(float64(rpm)/60), 1)
+		}
+	}
+}
 
-	// Cancel cancels a running batch job.
-	Cancel(ctx context.Context, jobID string) error
+// WithProviders enables native batch submission to registered providers
+// that implement provider.BatchProvider.
+func WithProviders(reg provider.Registry) Option {
+	return func(s *service) { s.providers = reg }
+}
 
-	// List returns batch jobs for a tenant.
-	List(ctx context.Context, tenantID string) ([]*Job, error)
+// WithUsage records a usage entry for every finished batch item.
+func WithUsage(u usage.Service) Option {
+	return func(s *service) { s.usage = u }
 }
 
-// Executor processes individual batch requests.
-type Executor interface {
-	Execute(ctx context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error)
+// WithPollInterval sets how often native batches are polled (default 30s).
+func WithPollInterval(d time.Duration) Option {
+	return func(s *service) {
+		if d > 0 {
+			s.pollInterval = d
+		}
+	}
 }
 
 // service is the default batch service implementation.
 type service struct {
-	executor    Executor
-	concurrency int
-	mu          sync.RWMutex
-	jobs        map[string]*Job
+	store        Store
+	executor     Executor
+	providers    provider.Registry
+	usage        usage.Service
+	concurrency  int
+	limiter      *rate.Limiter
+	pollInterval time.Duration
+
+	// ctx is cancelled by Close; it parents every job and the poller.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// mu serialises job updates between workers, the poller and Cancel.
+	mu      sync.Mutex
+	running map[string]*runningJob // worker-pool jobs in flight
+	polling sync.Once
+}
+
+type runningJob struct {
+	job    *Job
+	cancel context.CancelFunc
 }
 
-// NewService creates a new batch service.
-func NewService(executor Executor, concurrency int) Service {
-	if concurrency <= 0 {
-		concurrency = 10
+// NewService creates a new batch service. executor runs worker-pool items,
+// normally through the gateway pipeline.
+func NewService(store Store, executor Executor, opts ...Option) Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &service{
+		store:        store,
+		executor:     executor,
+		concurrency:  DefaultConcurrency,
+		pollInterval: DefaultPollInterval,
+		ctx:          ctx,
+		cancel:       cancel,
+		running:      make(map[string]*runningJob),
 	}
-	return &service{
-		executor:    executor,
-		concurrency: concurrency,
-		jobs:        make(map[string]*Job),
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-func (s *service) Create(_ context.Context, input *CreateInput) (*Job, error) {
+func (s *service) Create(ctx context.Context, input *CreateInput) (*Job, error) {
+	endpoint := input.Endpoint
+	if endpoint == "" {
+		endpoint = EndpointChatCompletions
+	}
+	if endpoint != EndpointChatCompletions {
+		return nil, fmt.Errorf("nexus: unsupported batch endpoint %q", endpoint)
+	}
+	window := input.CompletionWindow
+	if window == "" {
+		window = DefaultCompletionWindow
+	}
+	windowDur, err := time.ParseDuration(window)
+	if err != nil || windowDur <= 0 {
+		return nil, fmt.Errorf("nexus: invalid completion window %q", window)
+	}
+
+	inputs, fileID, lineErrs, err := s.resolveInputs(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(inputs) == 0 && len(lineErrs) == 0 {
+		return nil, errors.New("nexus: batch input is empty")
+	}
+	if len(inputs)+len(lineErrs) > MaxInputs {
+		return nil, fmt.Errorf("nexus: batch exceeds %d requests", MaxInputs)
+	}
+
 	job := &Job{
-		ID:         generateID(),
-		TenantID:   input.TenantID,
-		Status:     JobPending,
-		Inputs:     input.Inputs,
-		TotalItems: len(input.Inputs),
-		CreatedAt:  time.Now(),
-		Metadata:   input.Metadata,
+		ID:               id.NewBatchID().String(),
+		TenantID:         input.TenantID,
+		KeyID:            input.KeyID,
+		Status:           JobPending,
+		TotalItems:       len(inputs) + len(lineErrs),
+		CreatedAt:        time.Now(),
+		Metadata:         input.Metadata,
+		Endpoint:         endpoint,
+		InputFileID:      fileID,
+		CompletionWindow: window,
+	}
+
+	// Like OpenAI, a file with invalid lines fails validation as a whole.
+	if len(lineErrs) > 0 {
+		now := time.Now()
+		job.Status = JobFailed
+		job.Errors = lineErrs
+		job.CompletedAt = &now
+	}
+	if err := s.store.InsertJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("nexus: insert batch: %w", err)
+	}
+	if job.Status == JobFailed {
+		return job, nil
 	}
 
 	concurrency := s.concurrency
@@ -65,107 +174,664 @@ func (s *service) Create(_ context.Context, input *CreateInput) (*Job, error) {
 		concurrency = input.Concurrency
 	}
 
+	jobCtx, cancel := context.WithTimeout(s.ctx, windowDur)
 	s.mu.Lock()
-	s.jobs[job.ID] = job
+	s.running[job.ID] = &runningJob{job: job, cancel: cancel}
+	snapshot := *job
 	s.mu.Unlock()
 
-	// Start processing in background.
-	go s.process(context.Background(), job, concurrency)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		s.process(jobCtx, job, inputs, concurrency)
+	}()
 
-	return job, nil
+	return &snapshot, nil
 }
 
-func (s *service) Get(_ context.Context, jobID string) (*Job, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	job, ok := s.jobs[jobID]
-	if !ok {
-		return nil, nil
+// resolveInputs returns the job's requests and the ID of the file holding
+// them, writing inline inputs to a new file.
+func (s *service) resolveInputs(ctx context.Context, input *CreateInput) ([]Input, string, []Error, error) {
+	if len(input.Inputs) > 0 {
+		seen := make(map[string]bool, len(input.Inputs))
+		for i, in := range input.Inputs {
+			if in.CustomID == "" || seen[in.CustomID] {
+				return nil, "", nil, fmt.Errorf("nexus: batch input %d: custom_id must be set and unique", i)
+			}
+			seen[in.CustomID] = true
+			if err := validateRequest(in.Request); err != nil {
+				return nil, "", nil, fmt.Errorf("nexus: batch input %q: %w", in.CustomID, err)
+			}
+		}
+		content, err := EncodeInputs(input.Inputs)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		f, err := s.UploadFile(ctx, &File{
+			TenantID: input.TenantID,
+			Filename: "batch_input.jsonl",
+			Purpose:  PurposeBatch,
+			Content:  content,
+		})
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return input.Inputs, f.ID, nil, nil
+	}
+
+	if input.InputFileID == "" {
+		return nil, "", nil, errors.New("nexus: input_file_id or inputs is required")
+	}
+	f, err := s.store.FindFile(ctx, input.InputFileID)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("nexus: find batch input file: %w", err)
+	}
+	if f == nil || f.TenantID != input.TenantID {
+		return nil, "", nil, ErrFileNotFound
+	}
+	if f.Purpose != PurposeBatch {
+		return nil, "", nil, fmt.Errorf("nexus: file %s has purpose %q, want %q", f.ID, f.Purpose, PurposeBatch)
+	}
+	inputs, lineErrs := ParseInputs(f.Content)
+	return inputs, f.ID, lineErrs, nil
+}
+
+func (s *service) Get(ctx context.Context, jobID string) (*Job, error) {
+	s.mu.Lock()
+	if rj, ok := s.running[jobID]; ok {
+		snapshot := *rj.job
+		s.mu.Unlock()
+		return &snapshot, nil
+	}
+	s.mu.Unlock()
+
+	job, err := s.store.FindJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: find batch: %w", err)
 	}
 	return job, nil
 }
 
-func (s *service) Cancel(_ context.Context, jobID string) error {
+func (s *service) Cancel(ctx context.Context, jobID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	job, ok := s.jobs[jobID]
-	if !ok {
+
+	if rj, ok := s.running[jobID]; ok {
+		if !rj.job.Status.Done() {
+			rj.job.Status = JobCancelling
+			rj.cancel()
+		}
 		return nil
 	}
-	if job.Status == JobRunning || job.Status == JobPending {
-		job.Status = JobCancelled
+
+	job, err := s.store.FindJob(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("nexus: find batch: %w", err)
+	}
+	if job == nil {
+		return ErrNotFound
+	}
+	if job.Status.Done() || job.Status == JobCancelling || job.ProviderBatchID == "" {
+		return nil
+	}
+
+	// Native batch: the poller collects partial results once the provider
+	// reports the batch has stopped.
+	bp, err := s.batchProvider(job.Provider)
+	if err != nil {
+		return err
+	}
+	if err := bp.CancelBatch(ctx, job.ProviderBatchID); err != nil {
+		return fmt.Errorf("nexus: cancel provider batch: %w", err)
+	}
+	job.Status = JobCancelling
+	return s.store.UpdateJob(ctx, job)
+}
+
+func (s *service) List(ctx context.Context, tenantID string) ([]*Job, error) {
+	jobs, err := s.store.ListJobs(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: list batches: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, job := range jobs {
+		if rj, ok := s.running[job.ID]; ok {
+			snapshot := *rj.job
+			jobs[i] = &snapshot
+		}
+	}
+	return jobs, nil
+}
+
+func (s *service) Results(ctx context.Context, jobID string) ([]Result, error) {
+	job, err := s.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrNotFound
+	}
+
+	var results []Result
+	for _, fileID := range []string{job.OutputFileID, job.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		f, err := s.store.FindFile(ctx, fileID)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: find batch output file: %w", err)
+		}
+		if f == nil {
+			continue
+		}
+		rs, err := ParseResults(f.Content)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, rs...)
+	}
+	return results, nil
+}
+
+func (s *service) UploadFile(ctx context.Context, f *File) (*File, error) {
+	if f.Purpose == "" {
+		return nil, errors.New("nexus: file purpose is required")
+	}
+	if len(f.Content) == 0 {
+		return nil, errors.New("nexus: file is empty")
+	}
+	stored := *f
+	stored.ID = id.NewFileID().String()
+	stored.Bytes = len(f.Content)
+	stored.CreatedAt = time.Now()
+	if err := s.store.InsertFile(ctx, &stored); err != nil {
+		return nil, fmt.Errorf("nexus: insert file: %w", err)
+	}
+	return &stored, nil
+}
+
+func (s *service) GetFile(ctx context.Context, fileID string) (*File, error) {
+	f, err := s.store.FindFile(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: find file: %w", err)
+	}
+	if f == nil {
+		return nil, ErrFileNotFound
+	}
+	return f, nil
+}
+
+func (s *service) ListFiles(ctx context.Context, tenantID, purpose string) ([]*File, error) {
+	files, err := s.store.ListFiles(ctx, tenantID, purpose)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: list files: %w", err)
+	}
+	return files, nil
+}
+
+func (s *service) DeleteFile(ctx context.Context, fileID string) error {
+	if _, err := s.GetFile(ctx, fileID); err != nil {
+		return err
+	}
+	if err := s.store.DeleteFile(ctx, fileID); err != nil {
+		return fmt.Errorf("nexus: delete file: %w", err)
 	}
 	return nil
 }
 
-func (s *service) List(_ context.Context, tenantID string) ([]*Job, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	var result []*Job
-	for _, job := range s.jobs {
-		if tenantID == "" || job.TenantID == tenantID {
-			result = append(result, job)
+func (s *service) Start(ctx context.Context) error {
+	jobs, err := s.store.ListJobsByStatus(ctx, JobPending, JobRunning, JobFinalizing, JobCancelling)
+	if err != nil {
+		return fmt.Errorf("nexus: list unfinished batches: %w", err)
+	}
+
+	native := false
+	for _, job := range jobs {
+		s.mu.Lock()
+		_, inFlight := s.running[job.ID]
+		s.mu.Unlock()
+		switch {
+		case inFlight:
+		case job.ProviderBatchID != "":
+			native = true
+		default:
+			// Worker-pool progress lives in memory, so a job interrupted
+			// by a restart cannot resume without re-running finished
+			// items.
+			now := time.Now()
+			job.Status = JobFailed
+			job.CompletedAt = &now
+			job.Errors = append(job.Errors, Error{Code: "interrupted", Message: "gateway restarted while the batch was running"})
+			if err := s.store.UpdateJob(ctx, job); err != nil {
+				return fmt.Errorf("nexus: fail interrupted batch: %w", err)
+			}
+		}
+	}
+	if native {
+		s.startPoller()
+	}
+	return nil
+}
+
+func (s *service) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// ──────────────────────────────────────────────────
+// Processing
+// ──────────────────────────────────────────────────
+
+func (s *service) process(ctx context.Context, job *Job, inputs []Input, concurrency int) {
+	if prepared := s.prepare(ctx, job, inputs); prepared != nil {
+		if bp, name := s.nativeProvider(ctx, prepared); bp != nil {
+			if s.submitNative(ctx, job, prepared, bp, name) {
+				return
+			}
+		}
+	}
+	s.runPool(ctx, job, inputs, concurrency)
+}
+
+// prepare runs every input through the executor's input phase so a native
+// batch sends what the pipeline would have; inputs are returned as given
+// when the executor is not a Preparer. It returns nil when the job must
+// run on the worker pool: no batch provider is registered, or a line was
+// rejected, in which case the pool reports the rejection per item.
+func (s *service) prepare(ctx context.Context, job *Job, inputs []Input) []Input {
+	if s.providers == nil || len(s.providers.WithCapability("batch")) == 0 {
+		return nil
+	}
+	p, ok := s.executor.(Preparer)
+	if !ok {
+		return inputs
+	}
+	prepared := make([]Input, len(inputs))
+	for i, in := range inputs {
+		req := *in.Request
+		req.TenantID = job.TenantID
+		req.KeyID = job.KeyID
+		out, err := p.Prepare(ctx, &req)
+		if err != nil {
+			return nil
+		}
+		prepared[i] = Input{CustomID: in.CustomID, Request: out}
+	}
+	return prepared
+}
+
+// nativeProvider returns the batch provider serving every input's model,
+// or nil when the inputs must run on the worker pool.
+func (s *service) nativeProvider(ctx context.Context, inputs []Input) (provider.BatchProvider, string) {
+	if s.providers == nil || len(inputs) == 0 {
+		return nil, ""
+	}
+	modelID, forced := inputs[0].Request.Model, inputs[0].Request.Provider
+	for _, in := range inputs[1:] {
+		if in.Request.Model != modelID || in.Request.Provider != forced {
+			return nil, ""
+		}
+	}
+
+	for _, p := range s.providers.WithCapability("batch") {
+		bp, ok := p.(provider.BatchProvider)
+		if !ok || (forced != "" && p.Name() != forced) {
+			continue
+		}
+		if _, ok := model.FindPricing(ctx, p, modelID); ok {
+			return bp, p.Name()
 		}
 	}
-	return result, nil
+	return nil, ""
 }
 
-func (s *service) process(ctx context.Context, job *Job, concurrency int) {
+// submitNative starts a provider batch. It reports false when submission
+// failed and the job should fall back to the worker pool.
+func (s *service) submitNative(ctx context.Context, job *Job, inputs []Input, bp provider.BatchProvider, name string) bool {
+	reqs := make([]provider.BatchRequest, len(inputs))
+	for i, in := range inputs {
+		reqs[i] = provider.BatchRequest{CustomID: in.CustomID, Request: in.Request}
+	}
+	batchID, err := bp.SubmitBatch(ctx, reqs)
+	if err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	cancelled := job.Status == JobCancelling
 	now := time.Now()
-	job.StartedAt = &now
+	job.Provider = name
+	job.ProviderBatchID = batchID
 	job.Status = JobRunning
-	job.Results = make([]Result, len(job.Inputs))
+	job.StartedAt = &now
+	if cancelled {
+		job.Status = JobCancelling
+	}
+	snapshot := *job
+	delete(s.running, job.ID)
+	s.mu.Unlock()
 
-	var completed atomic.Int64
-	var failed atomic.Int64
+	if cancelled {
+		_ = bp.CancelBatch(context.Background(), batchID) //nolint:errcheck // the poller still collects whatever the provider finished
+	}
+	_ = s.store.UpdateJob(context.Background(), &snapshot) //nolint:errcheck // retried by the next poll
+	s.startPoller()
+	return true
+}
 
+func (s *service) runPool(ctx context.Context, job *Job, inputs []Input, concurrency int) {
+	s.mu.Lock()
+	now := time.Now()
+	job.Status = JobRunning
+	job.StartedAt = &now
+	snapshot := *job
+	s.mu.Unlock()
+	_ = s.store.UpdateJob(context.Background(), &snapshot) //nolint:errcheck // progress is persisted again on completion
+
+	results := make([]Result, len(inputs))
 	sem := make(chan struct{}, concurrency)
 	var wg sync.WaitGroup
 
-	for i, input := range job.Inputs {
-		if job.Status == JobCancelled {
-			break
+	for i, input := range inputs {
+		if ctx.Err() != nil {
+			results[i] = Result{CustomID: input.CustomID, Error: stoppedError(ctx, s.ctx)}
+			continue
 		}
-
-		wg.Add(1)
 		sem <- struct{}{} // acquire semaphore
+		wg.Add(1)
 		go func(idx int, inp Input) {
 			defer wg.Done()
 			defer func() { <-sem }() // release semaphore
+			results[idx] = s.executeItem(ctx, job, inp)
+			s.itemDone(job, results[idx].Error == nil)
+		}(i, input)
+	}
+	wg.Wait()
 
-			resp, err := s.executor.Execute(ctx, inp.Request)
-			if err != nil {
-				job.Results[idx] = Result{
-					CustomID: inp.CustomID,
-					Error:    &Error{Code: "execution_error", Message: err.Error()},
-				}
-				failed.Add(1)
-			} else {
-				job.Results[idx] = Result{
-					CustomID: inp.CustomID,
-					Response: resp,
+	var stopped JobStatus
+	switch {
+	case s.ctx.Err() != nil:
+		stopped = JobFailed
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		stopped = JobExpired
+	case ctx.Err() != nil:
+		stopped = JobCancelled
+	}
+	s.finalize(job, results, stopped, "", 0)
+}
+
+func (s *service) executeItem(ctx context.Context, job *Job, inp Input) Result {
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return Result{CustomID: inp.CustomID, Error: stoppedError(ctx, s.ctx)}
+		}
+	}
+
+	req := *inp.Request
+	req.TenantID = job.TenantID
+	req.KeyID = job.KeyID
+	// Marked so the pipeline leaves billing to finalize, which records the
+	// item once with the job ID.
+	resp, err := s.executor.Execute(pipeline.WithBatchID(ctx, job.ID), &req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Result{CustomID: inp.CustomID, Error: stoppedError(ctx, s.ctx)}
+		}
+		return Result{
+			CustomID: inp.CustomID,
+			Error:    &Error{Code: "execution_error", Message: err.Error()},
+		}
+	}
+	return Result{CustomID: inp.CustomID, Response: resp}
+}
+
+// itemDone updates a worker-pool job's counts, persisting them every
+// progressEvery items.
+func (s *service) itemDone(job *Job, ok bool) {
+	s.mu.Lock()
+	if ok {
+		job.Completed++
+	} else {
+		job.Failed++
+	}
+	persist := (job.Completed+job.Failed)%progressEvery == 0
+	snapshot := *job
+	s.mu.Unlock()
+	if persist {
+		_ = s.store.UpdateJob(context.Background(), &snapshot) //nolint:errcheck // best-effort progress update
+	}
+}
+
+// stoppedError explains why an item was not run: the job was cancelled,
+// its completion window elapsed, or the gateway shut down.
+func stoppedError(jobCtx, serviceCtx context.Context) *Error {
+	switch {
+	case serviceCtx.Err() != nil:
+		return &Error{Code: "shutdown", Message: "gateway shut down before the request ran"}
+	case errors.Is(jobCtx.Err(), context.DeadlineExceeded):
+		return &Error{Code: "batch_expired", Message: "request was not run within the completion window"}
+	default:
+		return &Error{Code: "batch_cancelled", Message: "batch was cancelled before the request ran"}
+	}
+}
+
+// finalize writes the output and error files, records usage for every
+// successful item and moves the job to its terminal status: stopped when
+// the job ended early, otherwise completed unless every item failed.
+// discount is the provider's batch discount for native results.
+func (s *service) finalize(job *Job, results []Result, stopped JobStatus, providerName string, discount float64) {
+	ctx := context.Background()
+
+	s.mu.Lock()
+	job.Status = JobFinalizing
+	snapshot := *job
+	s.mu.Unlock()
+	_ = s.store.UpdateJob(ctx, &snapshot) //nolint:errcheck // terminal status is persisted below
+
+	var succeeded, failed []Result
+	for _, r := range results {
+		if r.Error != nil || r.Response == nil {
+			failed = append(failed, r)
+			continue
+		}
+		s.recordUsage(ctx, job, r.Response, providerName, discount)
+		succeeded = append(succeeded, r)
+	}
+
+	outputID, outErr := s.writeResults(ctx, job, "output", succeeded)
+	errorID, errErr := s.writeResults(ctx, job, "error", failed)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	job.OutputFileID = outputID
+	job.ErrorFileID = errorID
+	job.Completed = len(succeeded)
+	job.Failed = len(failed)
+	job.CompletedAt = &now
+	switch {
+	case outErr != nil || errErr != nil:
+		job.Status = JobFailed
+		job.Errors = append(job.Errors, Error{Code: "output_error", Message: errors.Join(outErr, errErr).Error()})
+	case stopped != "":
+		job.Status = stopped
+	case len(results) > 0 && len(failed) == len(results):
+		job.Status = JobFailed
+	default:
+		job.Status = JobCompleted
+	}
+	delete(s.running, job.ID)
+	_ = s.store.UpdateJob(ctx, job) //nolint:errcheck // nothing left to retry with
+}
+
+func (s *service) writeResults(ctx context.Context, job *Job, kind string, results []Result) (string, error) {
+	if len(results) == 0 {
+		return "", nil
+	}
+	n := 0
+	content, err := EncodeResults(results, func() string {
+		n++
+		return fmt.Sprintf("%s_req_%d", job.ID, n)
+	})
+	if err != nil {
+		return "", err
+	}
+	f, err := s.UploadFile(ctx, &File{
+		TenantID: job.TenantID,
+		Filename: fmt.Sprintf("%s_%s.jsonl", job.ID, kind),
+		Purpose:  PurposeBatchOutput,
+		Content:  content,
+	})
+	if err != nil {
+		return "", err
+	}
+	return f.ID, nil
+}
+
+// recordUsage attributes one item's usage to the job, priced at the
+// serving provider's rates less its batch discount.
+func (s *service) recordUsage(ctx context.Context, job *Job, resp *provider.CompletionResponse, providerName string, discount float64) {
+	if providerName == "" {
+		providerName = resp.Provider
+	}
+	cost := resp.Cost
+	if cost == 0 && s.providers != nil {
+		if p, ok := s.providers.Get(providerName); ok {
+			if pricing, ok := model.FindPricing(ctx, p, resp.Model); ok {
+				cost = model.EstimateCost(resp.Usage, pricing).TotalCost
+			}
+		}
+	}
+	resp.Cost = cost * (1 - discount)
+
+	if s.usage == nil {
+		return
+	}
+	tenantID, _ := id.ParseTenantID(job.TenantID) //nolint:errcheck // non-ID tenants are recorded as Nil
+	keyID, _ := id.ParseKeyID(job.KeyID)          //nolint:errcheck // likewise
+	total := resp.Usage.TotalTokens
+	if total == 0 {
+		total = resp.Usage.PromptTokens + resp.Usage.CompletionTokens
+	}
+	_ = s.usage.Record(ctx, &usage.Record{ //nolint:errcheck // best-effort usage recording
+		ID:               id.NewUsageID(),
+		TenantID:         tenantID,
+		KeyID:            keyID,
+		RequestID:        id.NewRequestID(),
+		Provider:         providerName,
+		Model:            resp.Model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		TotalTokens:      total,
+		CostUSD:          resp.Cost,
+		Latency:          resp.Latency,
+		StatusCode:       200,
+		BatchID:          job.ID,
+		CreatedAt:        time.Now(),
+	})
+}
+
+// ──────────────────────────────────────────────────
+// Native batch polling
+// ──────────────────────────────────────────────────
+
+func (s *service) startPoller() {
+	s.polling.Do(func() {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			ticker := time.NewTicker(s.pollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-s.ctx.Done():
+					return
+				case <-ticker.C:
+					s.poll(s.ctx)
 				}
 			}
-			c := int(completed.Add(1))
-			job.Completed = c
-			job.Failed = int(failed.Load())
-		}(i, input)
+		}()
+	})
+}
+
+// poll checks every unfinished native batch once.
+func (s *service) poll(ctx context.Context) {
+	jobs, err := s.store.ListJobsByStatus(ctx, JobRunning, JobCancelling, JobFinalizing)
+	if err != nil {
+		return
 	}
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if job.ProviderBatchID == "" {
+			continue
+		}
+		s.pollJob(ctx, job)
+	}
+}
 
-	wg.Wait()
+func (s *service) pollJob(ctx context.Context, job *Job) {
+	bp, err := s.batchProvider(job.Provider)
+	if err != nil {
+		return
+	}
+	status, err := bp.BatchStatus(ctx, job.ProviderBatchID)
+	if err != nil {
+		return
+	}
 
-	completedAt := time.Now()
-	job.CompletedAt = &completedAt
-	if job.Status != JobCancelled {
-		if job.Failed > 0 && job.Failed == job.TotalItems {
-			job.Status = JobFailed
-		} else {
-			job.Status = JobCompleted
+	if !status.Ended() {
+		job.Completed = status.Completed
+		job.Failed = status.Failed
+		_ = s.store.UpdateJob(ctx, job) //nolint:errcheck // retried by the next poll
+		return
+	}
+
+	results, err := bp.BatchResults(ctx, job.ProviderBatchID)
+	if err != nil {
+		return
+	}
+	out := make([]Result, 0, len(results))
+	for _, r := range results {
+		res := Result{CustomID: r.CustomID, Response: r.Response}
+		if r.Error != "" || r.Response == nil {
+			res.Response = nil
+			code := "provider_error"
+			if r.StatusCode != 0 {
+				code = fmt.Sprintf("provider_error_%d", r.StatusCode)
+			}
+			res.Error = &Error{Code: code, Message: r.Error}
 		}
+		out = append(out, res)
 	}
+	var stopped JobStatus
+	switch status.State {
+	case provider.BatchStateCancelled:
+		stopped = JobCancelled
+	case provider.BatchStateExpired:
+		stopped = JobExpired
+	case provider.BatchStateFailed:
+		stopped = JobFailed
+		job.Errors = append(job.Errors, Error{Code: "provider_failed", Message: "provider rejected the batch"})
+	}
+	s.finalize(job, out, stopped, job.Provider, bp.BatchDiscount())
 }
 
-// generateID creates a simple batch job ID.
-func generateID() string {
-	return "batch_" + time.Now().Format("20060102150405")
+func (s *service) batchProvider(name string) (provider.BatchProvider, error) {
+	if s.providers == nil {
+		return nil, fmt.Errorf("nexus: batch provider %q is not registered", name)
+	}
+	p, ok := s.providers.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("nexus: batch provider %q is not registered", name)
+	}
+	bp, ok := p.(provider.BatchProvider)
+	if !ok {
+		return nil, fmt.Errorf("nexus: provider %q does not support batches", name)
+	}
+	return bp, nil
 }
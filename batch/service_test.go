@@ -0,0 +1,432 @@
+package batch_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xraph/nexus/batch"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/store"
+	"github.com/xraph/nexus/usage"
+)
+
+// echoExecutor answers every request with its first message, failing
+// requests whose content is "fail".
+type echoExecutor struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (e *echoExecutor) Execute(_ context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	e.mu.Lock()
+	e.calls++
+	e.mu.Unlock()
+	content, _ := req.Messages[0].Content.(string)
+	if content == "fail" {
+		return nil, errors.New("upstream error")
+	}
+	return &provider.CompletionResponse{
+		ID:       "resp-" + content,
+		Provider: "pool",
+		Model:    req.Model,
+		Choices:  []provider.Choice{{Message: provider.Message{Role: "assistant", Content: content}, FinishReason: "stop"}},
+		Usage:    provider.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+// nativeProvider is a batch-capable provider that finishes a batch on the
+// first status check.
+type nativeProvider struct {
+	mu        sync.Mutex
+	submitted []provider.BatchRequest
+}
+
+func (p *nativeProvider) Name() string { return "native" }
+func (p *nativeProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Chat: true, Batch: true}
+}
+func (p *nativeProvider) Models(_ context.Context) ([]provider.Model, error) {
+	return []provider.Model{{
+		ID:       "batch-model",
+		Provider: "native",
+		Pricing:  provider.Pricing{InputPerMillion: 1_000_000, OutputPerMillion: 2_000_000},
+	}}, nil
+}
+func (p *nativeProvider) Complete(_ context.Context, _ *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *nativeProvider) CompleteStream(_ context.Context, _ *provider.CompletionRequest) (provider.Stream, error) {
+	return nil, errors.New("not used")
+}
+func (p *nativeProvider) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *nativeProvider) Healthy(_ context.Context) bool { return true }
+
+func (p *nativeProvider) SubmitBatch(_ context.Context, reqs []provider.BatchRequest) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.submitted = reqs
+	return "native-batch-1", nil
+}
+
+func (p *nativeProvider) BatchStatus(_ context.Context, _ string) (*provider.BatchStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return &provider.BatchStatus{State: provider.BatchStateEnded, Total: len(p.submitted), Completed: len(p.submitted)}, nil
+}
+
+func (p *nativeProvider) BatchResults(_ context.Context, _ string) ([]provider.BatchResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	results := make([]provider.BatchResult, len(p.submitted))
+	for i, r := range p.submitted {
+		results[i] = provider.BatchResult{
+			CustomID:   r.CustomID,
+			StatusCode: 200,
+			Response: &provider.CompletionResponse{
+				ID:      "msg-" + r.CustomID,
+				Model:   r.Request.Model,
+				Choices: []provider.Choice{{Message: provider.Message{Role: "assistant", Content: "ok"}}},
+				Usage:   provider.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+			},
+		}
+	}
+	return results, nil
+}
+
+func (p *nativeProvider) CancelBatch(_ context.Context, _ string) error { return nil }
+func (p *nativeProvider) BatchDiscount() float64                        { return 0.5 }
+
+func chatInput(customID, modelID, content string) batch.Input {
+	return batch.Input{
+		CustomID: customID,
+		Request: &provider.CompletionRequest{
+			Model:    modelID,
+			Messages: []provider.Message{{Role: "user", Content: content}},
+		},
+	}
+}
+
+func waitDone(t *testing.T, svc batch.Service, jobID string) *batch.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := svc.Get(context.Background(), jobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status.Done() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("batch %s did not finish", jobID)
+	return nil
+}
+
+func TestServiceWorkerPool(t *testing.T) {
+	st := store.NewMemory()
+	exec := &echoExecutor{}
+	svc := batch.NewService(st.Batches(), exec, batch.WithUsage(usage.NewService(st.Usage())))
+	t.Cleanup(func() { _ = svc.Close() })
+
+	job, err := svc.Create(context.Background(), &batch.CreateInput{
+		TenantID: "tenant-a",
+		Inputs: []batch.Input{
+			chatInput("a", "m", "hello"),
+			chatInput("b", "m", "fail"),
+			chatInput("c", "m", "world"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.InputFileID == "" {
+		t.Fatal("inline inputs were not written to an input file")
+	}
+
+	job = waitDone(t, svc, job.ID)
+	if job.Status != batch.JobCompleted || job.Completed != 2 || job.Failed != 1 {
+		t.Fatalf("job = %s %d/%d, want completed 2/1", job.Status, job.Completed, job.Failed)
+	}
+
+	results, err := svc.Results(context.Background(), job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := make(map[string]batch.Result)
+	for _, r := range results {
+		byID[r.CustomID] = r
+	}
+	if r := byID["a"]; r.Response == nil || r.Response.Choices[0].Message.Content != "hello" {
+		t.Errorf("result a = %+v", r)
+	}
+	if r := byID["b"]; r.Error == nil || r.Error.Code != "execution_error" {
+		t.Errorf("result b = %+v", r)
+	}
+
+	out, err := svc.GetFile(context.Background(), job.OutputFileID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Purpose != batch.PurposeBatchOutput || strings.Count(string(out.Content), "\n") != 2 {
+		t.Errorf("output file = %q", out.Content)
+	}
+
+	records, _, err := st.Usage().Query(context.Background(), &usage.QueryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("usage records = %d, want 2", len(records))
+	}
+	for _, rec := range records {
+		if rec.BatchID != job.ID || rec.TotalTokens != 15 {
+			t.Errorf("usage record = %+v", rec)
+		}
+	}
+}
+
+func TestServiceNativeBatch(t *testing.T) {
+	st := store.NewMemory()
+	reg := provider.NewRegistry()
+	np := &nativeProvider{}
+	reg.Register(np)
+	exec := &echoExecutor{}
+	svc := batch.NewService(st.Batches(), exec,
+		batch.WithProviders(reg),
+		batch.WithUsage(usage.NewService(st.Usage())),
+		batch.WithPollInterval(10*time.Millisecond),
+	)
+	t.Cleanup(func() { _ = svc.Close() })
+
+	job, err := svc.Create(context.Background(), &batch.CreateInput{
+		Inputs: []batch.Input{chatInput("a", "batch-model", "x"), chatInput("b", "batch-model", "y")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job = waitDone(t, svc, job.ID)
+	if job.Status != batch.JobCompleted || job.Completed != 2 {
+		t.Fatalf("job = %s %d completed, want completed 2", job.Status, job.Completed)
+	}
+	if job.Provider != "native" || job.ProviderBatchID != "native-batch-1" {
+		t.Errorf("job provider = %q/%q", job.Provider, job.ProviderBatchID)
+	}
+	if exec.calls != 0 {
+		t.Errorf("worker pool ran %d requests for a native batch", exec.calls)
+	}
+
+	// 10 input + 5 output tokens at $1/$2 per token, halved.
+	records, _, err := st.Usage().Query(context.Background(), &usage.QueryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("usage records = %d, want 2", len(records))
+	}
+	for _, rec := range records {
+		if math.Abs(rec.CostUSD-10) > 1e-9 || rec.Provider != "native" {
+			t.Errorf("usage record = provider %q cost %v, want native 10", rec.Provider, rec.CostUSD)
+		}
+	}
+}
+
+// preparingExecutor resolves the "alias" model to batch-model and rejects
+// requests whose content is "blocked", like a pipeline input phase.
+type preparingExecutor struct{ echoExecutor }
+
+func (e *preparingExecutor) Prepare(_ context.Context, req *provider.CompletionRequest) (*provider.CompletionRequest, error) {
+	if req.Messages[0].Content == "blocked" {
+		return nil, errors.New("content blocked")
+	}
+	if req.Model == "alias" {
+		req.Model = "batch-model"
+	}
+	req.System = "prepared for " + req.TenantID
+	return req, nil
+}
+
+func TestServiceNativeBatchSubmitsPreparedRequests(t *testing.T) {
+	reg := provider.NewRegistry()
+	np := &nativeProvider{}
+	reg.Register(np)
+	exec := &preparingExecutor{}
+	svc := batch.NewService(store.NewMemory().Batches(), exec,
+		batch.WithProviders(reg), batch.WithPollInterval(10*time.Millisecond))
+	t.Cleanup(func() { _ = svc.Close() })
+
+	job, err := svc.Create(context.Background(), &batch.CreateInput{
+		TenantID: "acme",
+		Inputs:   []batch.Input{chatInput("a", "alias", "x"), chatInput("b", "alias", "y")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job = waitDone(t, svc, job.ID)
+	if job.ProviderBatchID == "" || exec.calls != 0 {
+		t.Fatalf("aliased batch did not go native (%q, %d pool calls)", job.ProviderBatchID, exec.calls)
+	}
+	np.mu.Lock()
+	defer np.mu.Unlock()
+	for _, r := range np.submitted {
+		if r.Request.Model != "batch-model" || r.Request.System != "prepared for acme" {
+			t.Errorf("submitted %s = %+v, want the prepared request", r.CustomID, r.Request)
+		}
+	}
+}
+
+func TestServiceRejectedLineUsesWorkerPool(t *testing.T) {
+	reg := provider.NewRegistry()
+	reg.Register(&nativeProvider{})
+	exec := &preparingExecutor{}
+	svc := batch.NewService(store.NewMemory().Batches(), exec, batch.WithProviders(reg))
+	t.Cleanup(func() { _ = svc.Close() })
+
+	job, err := svc.Create(context.Background(), &batch.CreateInput{
+		Inputs: []batch.Input{chatInput("a", "batch-model", "x"), chatInput("b", "batch-model", "blocked")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job = waitDone(t, svc, job.ID)
+	if job.ProviderBatchID != "" || exec.calls != 2 {
+		t.Fatalf("batch with a rejected line went native (%q) or skipped the pool (%d calls)", job.ProviderBatchID, exec.calls)
+	}
+}
+
+func TestServiceMixedModelsUseWorkerPool(t *testing.T) {
+	st := store.NewMemory()
+	reg := provider.NewRegistry()
+	reg.Register(&nativeProvider{})
+	exec := &echoExecutor{}
+	svc := batch.NewService(st.Batches(), exec, batch.WithProviders(reg))
+	t.Cleanup(func() { _ = svc.Close() })
+
+	job, err := svc.Create(context.Background(), &batch.CreateInput{
+		Inputs: []batch.Input{chatInput("a", "batch-model", "x"), chatInput("b", "other-model", "y")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job = waitDone(t, svc, job.ID)
+	if job.ProviderBatchID != "" || exec.calls != 2 {
+		t.Fatalf("mixed-model batch went native (%q) or skipped the pool (%d calls)", job.ProviderBatchID, exec.calls)
+	}
+}
+
+func TestServiceInvalidFileFailsJob(t *testing.T) {
+	st := store.NewMemory()
+	svc := batch.NewService(st.Batches(), &echoExecutor{})
+	t.Cleanup(func() { _ = svc.Close() })
+
+	content := `{"custom_id":"a","method":"POST","url":"/v1/chat/completions","body":{"model":"m","messages":[{"role":"user","content":"hi"}]}}
+{"custom_id":"a","method":"POST","url":"/v1/chat/completions","body":{"model":"m","messages":[{"role":"user","content":"hi"}]}}
+{"custom_id":"c","method":"POST","url":"/v1/embeddings","body":{}}
+`
+	f, err := svc.UploadFile(context.Background(), &batch.File{Filename: "in.jsonl", Purpose: batch.PurposeBatch, Content: []byte(content)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := svc.Create(context.Background(), &batch.CreateInput{InputFileID: f.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != batch.JobFailed || len(job.Errors) != 2 {
+		t.Fatalf("job = %s with %d errors, want failed with 2", job.Status, len(job.Errors))
+	}
+	if job.Errors[0].Line != 2 || job.Errors[1].Line != 3 {
+		t.Errorf("error lines = %d, %d, want 2, 3", job.Errors[0].Line, job.Errors[1].Line)
+	}
+}
+
+func TestServiceInputFileIsTenantScoped(t *testing.T) {
+	st := store.NewMemory()
+	svc := batch.NewService(st.Batches(), &echoExecutor{})
+	t.Cleanup(func() { _ = svc.Close() })
+
+	content, err := batch.EncodeInputs([]batch.Input{chatInput("a", "m", "hi")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := svc.UploadFile(context.Background(), &batch.File{TenantID: "tenant-a", Purpose: batch.PurposeBatch, Content: content})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.Create(context.Background(), &batch.CreateInput{TenantID: "tenant-b", InputFileID: f.ID})
+	if !errors.Is(err, batch.ErrFileNotFound) {
+		t.Fatalf("err = %v, want ErrFileNotFound", err)
+	}
+}
+
+// blockingExecutor holds every request until its context ends.
+type blockingExecutor struct{ started chan struct{} }
+
+func (e *blockingExecutor) Execute(ctx context.Context, _ *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	e.started <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestServiceCancel(t *testing.T) {
+	st := store.NewMemory()
+	exec := &blockingExecutor{started: make(chan struct{}, 10)}
+	svc := batch.NewService(st.Batches(), exec, batch.WithConcurrency(1))
+	t.Cleanup(func() { _ = svc.Close() })
+
+	inputs := make([]batch.Input, 5)
+	for i := range inputs {
+		inputs[i] = chatInput(fmt.Sprintf("r%d", i), "m", "hi")
+	}
+	job, err := svc.Create(context.Background(), &batch.CreateInput{Inputs: inputs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-exec.started
+	if err := svc.Cancel(context.Background(), job.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	job = waitDone(t, svc, job.ID)
+	if job.Status != batch.JobCancelled || job.Failed != 5 {
+		t.Fatalf("job = %s with %d failed, want cancelled with 5", job.Status, job.Failed)
+	}
+	results, err := svc.Results(context.Background(), job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Error == nil || r.Error.Code != "batch_cancelled" {
+			t.Errorf("result %s error = %+v, want batch_cancelled", r.CustomID, r.Error)
+		}
+	}
+}
+
+func TestServiceStartFailsInterruptedJobs(t *testing.T) {
+	st := store.NewMemory()
+	ctx := context.Background()
+	if err := st.Batches().InsertJob(ctx, &batch.Job{ID: "batch_old", Status: batch.JobRunning, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := batch.NewService(st.Batches(), &echoExecutor{})
+	t.Cleanup(func() { _ = svc.Close() })
+	if err := svc.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	job, err := svc.Get(ctx, "batch_old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != batch.JobFailed || len(job.Errors) != 1 || job.Errors[0].Code != "interrupted" {
+		t.Fatalf("job = %s %+v, want failed/interrupted", job.Status, job.Errors)
+	}
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	nexus "github.com/xraph/nexus"
 	"github.com/xraph/nexus/cache/stores"
@@ -40,10 +44,12 @@ import (
 	"github.com/xraph/nexus/providers/voyageai"
 	"github.com/xraph/nexus/providers/xai"
 	"github.com/xraph/nexus/router/strategies"
+	"github.com/xraph/nexus/transform"
 )
 
-// Apply converts a GatewayConfig into Gateway options.
-func Apply(cfg *GatewayConfig) []nexus.Option {
+// Apply converts a GatewayConfig into Gateway options. It fails on guard
+// settings it does not recognize rather than run without them.
+func Apply(cfg *GatewayConfig) ([]nexus.Option, error) {
 	var opts []nexus.Option
 
 	// Server settings
@@ -84,6 +90,9 @@ func Apply(cfg *GatewayConfig) []nexus.Option {
 		}
 		opts = append(opts, nexus.WithCache(stores.NewMemory(cacheOpts...)))
 	}
+	if cfg.Cache.Coalesce {
+		opts = append(opts, nexus.WithRequestCoalescing())
+	}
 
 	// Guardrails
 	if cfg.Guardrails.PII.Enabled {
@@ -102,6 +111,55 @@ func Apply(cfg *GatewayConfig) []nexus.Option {
 	if len(cfg.Guardrails.Blocklist) > 0 {
 		opts = append(opts, nexus.WithGuard(guards.NewContentFilter(guard.ActionBlock, cfg.Guardrails.Blocklist...)))
 	}
+	if j := cfg.Guardrails.Judge; j != nil && j.Provider != "" && j.Model != "" {
+		phase, err := parsePhase(j.Phase)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: judge guard: %w", err)
+		}
+		action, err := parseAction(j.Action, guard.ActionBlock, guard.ActionBlock, guard.ActionWarn)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: judge guard: %w", err)
+		}
+		opts = append(opts, nexus.WithJudgeGuard(j.Provider, j.Model, func(g *guards.JudgeGuard) {
+			if phase != "" {
+				g.WithPhase(phase)
+			}
+			g.WithAction(action)
+			if j.Threshold > 0 {
+				g.WithThreshold(j.Threshold)
+			}
+			if j.Timeout > 0 {
+				g.WithTimeout(j.Timeout)
+			}
+			if j.CacheTTL > 0 {
+				g.WithCacheTTL(j.CacheTTL)
+			}
+			if j.Prompt != "" {
+				g.WithPrompt(j.Prompt)
+			}
+			g.WithFailOpen(j.FailOpen)
+		}))
+	}
+	for name, policy := range cfg.Guardrails.Policies {
+		var policyGuards []guard.Guard
+		for i, gc := range policy.Guards {
+			g, err := buildGuard(gc)
+			if err != nil {
+				return nil, fmt.Errorf("nexus: guardrail policy %q guard %d: %w", name, i, err)
+			}
+			policyGuards = append(policyGuards, g)
+		}
+		opts = append(opts, nexus.WithGuardrailPolicy(name, policyGuards...))
+	}
+	if cfg.Guardrails.DefaultPolicy != "" {
+		opts = append(opts, nexus.WithDefaultGuardrailPolicy(cfg.Guardrails.DefaultPolicy))
+	}
+	if cfg.Guardrails.Stream.Strategy != "" || cfg.Guardrails.Stream.WindowSize > 0 {
+		opts = append(opts, nexus.WithStreamGuardStrategy(
+			guard.StreamStrategy(cfg.Guardrails.Stream.Strategy),
+			cfg.Guardrails.Stream.WindowSize,
+		))
+	}
 
 	// Aliases
 	for _, alias := range cfg.Aliases {
@@ -116,12 +174,98 @@ func Apply(cfg *GatewayConfig) []nexus.Option {
 		opts = append(opts, nexus.WithAlias(alias.Name, targets...))
 	}
 
-	return opts
+	// Context-window management
+	if cfg.Context.Overflow != "" {
+		opts = append(opts, nexus.WithContextOverflow(model.OverflowStrategy(cfg.Context.Overflow)))
+	}
+	for name, path := range cfg.Context.Encodings {
+		// Unreadable rank files fall back to approximate counting.
+		if t, err := model.LoadBPEFile(path); err == nil {
+			opts = append(opts, nexus.WithTokenizer(name, t))
+		}
+	}
+	if s := cfg.Context.Summarizer; s != nil && s.Provider != "" && s.Model != "" {
+		opts = append(opts, nexus.WithContextSummarizer(s.Provider, s.Model))
+	}
+
+	// Retrieval-augmented generation
+	if r := cfg.RAG; r != nil && r.EmbeddingModel != "" {
+		opts = append(opts, nexus.WithRAG(r.EmbeddingModel, nil, func(t *transform.RAGTransform) {
+			if r.MaxResults > 0 {
+				t.WithMaxResults(r.MaxResults)
+			}
+			if r.TokenBudget > 0 {
+				t.WithTokenBudget(r.TokenBudget)
+			}
+			t.WithRequired(r.Required)
+		}))
+	}
+
+	return opts, nil
+}
+
+// buildGuard creates a guard.Guard from a GuardConfig, rejecting unknown
+// types, phases and actions the guard does not implement.
+func buildGuard(gc GuardConfig) (guard.Guard, error) {
+	phase, err := parsePhase(gc.Phase)
+	if err != nil {
+		return nil, err
+	}
+	var g guard.Guard
+	switch gc.Type {
+	case "pii":
+		action, err := parseAction(gc.Action, guard.ActionRedact, guard.ActionBlock, guard.ActionRedact, guard.ActionWarn)
+		if err != nil {
+			return nil, err
+		}
+		g = guards.NewPII(action)
+	case "injection":
+		action, err := parseAction(gc.Action, guard.ActionBlock, guard.ActionBlock, guard.ActionWarn)
+		if err != nil {
+			return nil, err
+		}
+		g = guards.NewInjection().WithAction(action)
+	case "blocklist":
+		action, err := parseAction(gc.Action, guard.ActionBlock, guard.ActionBlock, guard.ActionWarn)
+		if err != nil {
+			return nil, err
+		}
+		g = guards.NewContentFilter(action, gc.Blocklist...)
+	default:
+		return nil, fmt.Errorf("unknown guard type %q", gc.Type)
+	}
+	if phase != "" {
+		g = guard.WithPhase(g, phase)
+	}
+	return g, nil
+}
+
+// parseAction returns the configured action, def when unset, or an error
+// when it is not one of allowed.
+func parseAction(s string, def guard.Action, allowed ...guard.Action) (guard.Action, error) {
+	if s == "" {
+		return def, nil
+	}
+	if !slices.Contains(allowed, guard.Action(s)) {
+		return "", fmt.Errorf("unsupported action %q", s)
+	}
+	return guard.Action(s), nil
+}
+
+// parsePhase returns the configured phase, empty when unset.
+func parsePhase(s string) (guard.Phase, error) {
+	switch p := guard.Phase(s); p {
+	case "", guard.PhaseInput, guard.PhaseOutput, guard.PhaseBoth:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown phase %q", s)
+	}
 }
 
 // buildProvider creates a provider.Provider from a ProviderConfig.
 // Returns nil if the provider type is unknown.
 func buildProvider(pc ProviderConfig) provider.Provider {
+	cp := credentialsFor(pc)
 	switch pc.Type {
 	// Original providers
 	case "openai":
@@ -129,15 +273,44 @@ func buildProvider(pc ProviderConfig) provider.Provider {
 		if pc.BaseURL != "" {
 			opts = append(opts, openai.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, openai.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, openai.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, openai.WithCredentials(cp))
+		}
 		return openai.New(pc.APIKey, opts...)
 	case "anthropic":
 		var opts []anthropic.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, anthropic.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, anthropic.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, anthropic.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, anthropic.WithCredentials(cp))
+		}
 		return anthropic.New(pc.APIKey, opts...)
 	case "opencompat":
-		return opencompat.New(pc.Name, pc.BaseURL, pc.APIKey)
+		var opts []opencompat.Option
+		if len(pc.Models) > 0 {
+			models := make([]provider.Model, len(pc.Models))
+			for i, id := range pc.Models {
+				models[i] = provider.Model{ID: id, Provider: pc.Name, Name: id}
+			}
+			opts = append(opts, opencompat.WithModels(models))
+		}
+		if cp != nil {
+			opts = append(opts, opencompat.WithCredentials(cp))
+		}
+		return opencompat.New(pc.Name, pc.BaseURL, pc.APIKey, opts...)
 
 	// OpenAI-compatible providers (Phase 1)
 	case "groq":
@@ -145,48 +318,120 @@ func buildProvider(pc ProviderConfig) provider.Provider {
 		if pc.BaseURL != "" {
 			opts = append(opts, groq.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, groq.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, groq.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, groq.WithCredentials(cp))
+		}
 		return groq.New(pc.APIKey, opts...)
 	case "together":
 		var opts []together.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, together.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, together.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, together.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, together.WithCredentials(cp))
+		}
 		return together.New(pc.APIKey, opts...)
 	case "mistral":
 		var opts []mistral.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, mistral.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, mistral.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, mistral.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, mistral.WithCredentials(cp))
+		}
 		return mistral.New(pc.APIKey, opts...)
 	case "deepseek":
 		var opts []deepseek.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, deepseek.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, deepseek.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, deepseek.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, deepseek.WithCredentials(cp))
+		}
 		return deepseek.New(pc.APIKey, opts...)
 	case "xai":
 		var opts []xai.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, xai.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, xai.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, xai.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, xai.WithCredentials(cp))
+		}
 		return xai.New(pc.APIKey, opts...)
 	case "openrouter":
 		var opts []openrouter.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, openrouter.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, openrouter.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, openrouter.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, openrouter.WithCredentials(cp))
+		}
 		return openrouter.New(pc.APIKey, opts...)
 	case "ollama":
 		var opts []ollama.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, ollama.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, ollama.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, ollama.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, ollama.WithCredentials(cp))
+		}
 		return ollama.New(opts...)
 	case "lmstudio":
 		var opts []lmstudio.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, lmstudio.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, lmstudio.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, lmstudio.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, lmstudio.WithCredentials(cp))
+		}
 		return lmstudio.New(opts...)
 
 	// OpenAI-compatible providers (Phase 2)
@@ -195,66 +440,165 @@ func buildProvider(pc ProviderConfig) provider.Provider {
 		if pc.BaseURL != "" {
 			opts = append(opts, fireworks.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, fireworks.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, fireworks.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, fireworks.WithCredentials(cp))
+		}
 		return fireworks.New(pc.APIKey, opts...)
 	case "perplexity":
 		var opts []perplexity.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, perplexity.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, perplexity.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, perplexity.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, perplexity.WithCredentials(cp))
+		}
 		return perplexity.New(pc.APIKey, opts...)
 	case "cerebras":
 		var opts []cerebras.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, cerebras.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, cerebras.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, cerebras.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, cerebras.WithCredentials(cp))
+		}
 		return cerebras.New(pc.APIKey, opts...)
 	case "sambanova":
 		var opts []sambanova.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, sambanova.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, sambanova.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, sambanova.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, sambanova.WithCredentials(cp))
+		}
 		return sambanova.New(pc.APIKey, opts...)
 	case "deepinfra":
 		var opts []deepinfra.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, deepinfra.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, deepinfra.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, deepinfra.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, deepinfra.WithCredentials(cp))
+		}
 		return deepinfra.New(pc.APIKey, opts...)
 	case "lepton":
 		var opts []lepton.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, lepton.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, lepton.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, lepton.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, lepton.WithCredentials(cp))
+		}
 		return lepton.New(pc.APIKey, opts...)
 	case "novita":
 		var opts []novita.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, novita.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, novita.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, novita.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, novita.WithCredentials(cp))
+		}
 		return novita.New(pc.APIKey, opts...)
 	case "nvidia":
 		var opts []nvidia.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, nvidia.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, nvidia.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, nvidia.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, nvidia.WithCredentials(cp))
+		}
 		return nvidia.New(pc.APIKey, opts...)
 	case "anyscale":
 		var opts []anyscale.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, anyscale.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, anyscale.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, anyscale.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, anyscale.WithCredentials(cp))
+		}
 		return anyscale.New(pc.APIKey, opts...)
 	case "hyperbolic":
 		var opts []hyperbolic.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, hyperbolic.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, hyperbolic.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, hyperbolic.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, hyperbolic.WithCredentials(cp))
+		}
 		return hyperbolic.New(pc.APIKey, opts...)
 	case "nebius":
 		var opts []nebius.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, nebius.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, nebius.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, nebius.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, nebius.WithCredentials(cp))
+		}
 		return nebius.New(pc.APIKey, opts...)
 
 	// Custom API format providers (Phase 3)
@@ -263,18 +607,45 @@ func buildProvider(pc ProviderConfig) provider.Provider {
 		if pc.BaseURL != "" {
 			opts = append(opts, gemini.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, gemini.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, gemini.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, gemini.WithCredentials(cp))
+		}
 		return gemini.New(pc.APIKey, opts...)
 	case "cohere":
 		var opts []cohere.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, cohere.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, cohere.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, cohere.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, cohere.WithCredentials(cp))
+		}
 		return cohere.New(pc.APIKey, opts...)
 	case "ai21":
 		var opts []ai21.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, ai21.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, ai21.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, ai21.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, ai21.WithCredentials(cp))
+		}
 		return ai21.New(pc.APIKey, opts...)
 	case "bedrock":
 		var opts []bedrock.Option
@@ -284,6 +655,18 @@ func buildProvider(pc ProviderConfig) provider.Provider {
 		if pc.SessionToken != "" {
 			opts = append(opts, bedrock.WithSessionToken(pc.SessionToken))
 		}
+		if pc.Profile != "" {
+			opts = append(opts, bedrock.WithProfile(pc.Profile))
+		}
+		if pc.Name != "" {
+			opts = append(opts, bedrock.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, bedrock.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, bedrock.WithCredentials(cp))
+		}
 		return bedrock.New(pc.AccessKeyID, pc.SecretAccessKey, pc.Region, opts...)
 
 	// Cloud-hosted providers (Phase 4)
@@ -301,6 +684,15 @@ func buildProvider(pc ProviderConfig) provider.Provider {
 		if pc.BaseURL != "" {
 			opts = append(opts, azureopenai.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, azureopenai.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, azureopenai.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, azureopenai.WithCredentials(cp))
+		}
 		return azureopenai.New(pc.APIKey, opts...)
 	case "vertex":
 		var opts []vertex.Option
@@ -322,6 +714,15 @@ func buildProvider(pc ProviderConfig) provider.Provider {
 		if pc.BaseURL != "" {
 			opts = append(opts, vertex.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, vertex.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, vertex.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, vertex.WithCredentials(cp))
+		}
 		return vertex.New(opts...)
 
 	// Embeddings-only providers (Phase 4)
@@ -330,15 +731,58 @@ func buildProvider(pc ProviderConfig) provider.Provider {
 		if pc.BaseURL != "" {
 			opts = append(opts, voyageai.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, voyageai.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, voyageai.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, voyageai.WithCredentials(cp))
+		}
 		return voyageai.New(pc.APIKey, opts...)
 	case "jinaai":
 		var opts []jinaai.Option
 		if pc.BaseURL != "" {
 			opts = append(opts, jinaai.WithBaseURL(pc.BaseURL))
 		}
+		if pc.Name != "" {
+			opts = append(opts, jinaai.WithName(pc.Name))
+		}
+		if len(pc.Models) > 0 {
+			opts = append(opts, jinaai.WithModelIDs(pc.Models...))
+		}
+		if cp != nil {
+			opts = append(opts, jinaai.WithCredentials(cp))
+		}
 		return jinaai.New(pc.APIKey, opts...)
 
 	default:
 		return nil
 	}
 }
+
+// credentialsFor builds the rotating credential source for pc, or nil when
+// it only has a static key.
+func credentialsFor(pc ProviderConfig) provider.CredentialProvider {
+	var src provider.CredentialProvider
+	switch {
+	case pc.CredentialsDir != "":
+		file := pc.Name
+		if file == "" {
+			file = pc.Type
+		}
+		fc := provider.NewFileCredential(pc.CredentialsDir)
+		src = provider.CredentialFunc(func(ctx context.Context, _ string) (string, error) {
+			return fc.GetCredential(ctx, file)
+		})
+	case len(pc.APIKeys) > 0:
+		keys := strings.Join(pc.APIKeys, "\n")
+		src = provider.CredentialFunc(func(context.Context, string) (string, error) {
+			return keys, nil
+		})
+	default:
+		return nil
+	}
+	return provider.NewKeyPool(src)
+}
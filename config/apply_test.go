@@ -0,0 +1,36 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/xraph/nexus/config"
+)
+
+func TestApply_ValidatesPolicyGuards(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		guard   config.GuardConfig
+		wantErr bool
+	}{
+		{name: "valid", guard: config.GuardConfig{Type: "injection", Action: "warn", Phase: "input"}},
+		{name: "defaults", guard: config.GuardConfig{Type: "pii"}},
+		{name: "unknown type", guard: config.GuardConfig{Type: "injektion"}, wantErr: true},
+		{name: "misspelled action", guard: config.GuardConfig{Type: "blocklist", Action: "blok"}, wantErr: true},
+		{name: "unsupported action", guard: config.GuardConfig{Type: "injection", Action: "redact"}, wantErr: true},
+		{name: "unknown phase", guard: config.GuardConfig{Type: "pii", Phase: "inptu"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &config.GatewayConfig{}
+			cfg.Guardrails.Policies = map[string]config.GuardrailPolicyConfig{
+				"strict": {Guards: []config.GuardConfig{tt.guard}},
+			}
+			_, err := config.Apply(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -35,9 +35,11 @@ func applyEnvOverrides(cfg *GatewayConfig) {
 		cfg.Server.LogLevel = v
 	}
 
-	// Provider API keys from env: NEXUS_PROVIDER_<NAME>_API_KEY
+	// Provider API keys from env: NEXUS_PROVIDER_<NAME>_API_KEY, with
+	// hyphens in instance names ("openai-eu") written as underscores.
 	for i := range cfg.Providers {
-		envKey := fmt.Sprintf("NEXUS_PROVIDER_%s_API_KEY", strings.ToUpper(cfg.Providers[i].Name))
+		name := strings.ReplaceAll(strings.ToUpper(cfg.Providers[i].Name), "-", "_")
+		envKey := fmt.Sprintf("NEXUS_PROVIDER_%s_API_KEY", name)
 		if v := os.Getenv(envKey); v != "" {
 			cfg.Providers[i].APIKey = v
 		}
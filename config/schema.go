@@ -26,6 +26,33 @@ type GatewayConfig struct {
 
 	// Resilience
 	Resilience ResilienceConfig `json:"resilience" yaml:"resilience"`
+
+	// Context-window management
+	Context ContextConfig `json:"context" yaml:"context"`
+
+	// Retrieval-augmented generation
+	RAG *RAGConfig `json:"rag,omitempty" yaml:"rag"`
+}
+
+// RAGConfig enables retrieval-augmented generation with an in-memory index.
+type RAGConfig struct {
+	EmbeddingModel string `json:"embedding_model" yaml:"embedding_model"`
+	MaxResults     int    `json:"max_results,omitempty" yaml:"max_results"`   // default 5
+	TokenBudget    int    `json:"token_budget,omitempty" yaml:"token_budget"` // default 2000
+	Required       bool   `json:"required,omitempty" yaml:"required"`         // fail requests when retrieval fails
+}
+
+// ContextConfig configures context-window management.
+type ContextConfig struct {
+	Overflow   string            `json:"overflow,omitempty" yaml:"overflow"`     // "error", "truncate_oldest", "truncate_middle"; empty passes through
+	Encodings  map[string]string `json:"encodings,omitempty" yaml:"encodings"`   // encoding name → tiktoken rank file path; counts are estimates without one
+	Summarizer *SummarizerConfig `json:"summarizer,omitempty" yaml:"summarizer"` // summarize truncated turns
+}
+
+// SummarizerConfig selects the model that summarizes truncated turns.
+type SummarizerConfig struct {
+	Provider string `json:"provider" yaml:"provider"`
+	Model    string `json:"model" yaml:"model"`
 }
 
 // ServerConfig configures the HTTP server.
@@ -37,22 +64,32 @@ type ServerConfig struct {
 
 // ProviderConfig configures a single provider.
 type ProviderConfig struct {
-	Name    string   `json:"name" yaml:"name"`
+	Name    string   `json:"name" yaml:"name"` // instance name; several instances may share a type
 	Type    string   `json:"type" yaml:"type"` // "openai", "anthropic", "opencompat", "groq", "gemini", etc.
 	APIKey  string   `json:"api_key" yaml:"api_key"`
 	BaseURL string   `json:"base_url,omitempty" yaml:"base_url"`
-	Models  []string `json:"models,omitempty" yaml:"models"`
+	Models  []string `json:"models,omitempty" yaml:"models"` // limit this instance to these model IDs
+
+	// Key rotation. APIKeys are used round-robin, failing over to the next
+	// key on 401/403/429. CredentialsDir is a mounted secret directory with a
+	// file named after the provider (its name, else its type) holding one
+	// key per line; the file is re-read when it changes. Either overrides
+	// APIKey. For bedrock each key is "ACCESS_KEY_ID:SECRET[:SESSION_TOKEN]".
+	APIKeys        []string `json:"api_keys,omitempty" yaml:"api_keys"`
+	CredentialsDir string   `json:"credentials_dir,omitempty" yaml:"credentials_dir"`
 
 	// Azure OpenAI specific
 	ResourceName string `json:"resource_name,omitempty" yaml:"resource_name"`
 	DeploymentID string `json:"deployment_id,omitempty" yaml:"deployment_id"`
 	APIVersion   string `json:"api_version,omitempty" yaml:"api_version"`
 
-	// AWS Bedrock specific
+	// AWS Bedrock specific. Empty credentials or region are resolved from
+	// the AWS environment variables and shared config files.
 	Region          string `json:"region,omitempty" yaml:"region"`
 	AccessKeyID     string `json:"access_key_id,omitempty" yaml:"access_key_id"`
 	SecretAccessKey string `json:"secret_access_key,omitempty" yaml:"secret_access_key"`
 	SessionToken    string `json:"session_token,omitempty" yaml:"session_token"`
+	Profile         string `json:"profile,omitempty" yaml:"profile"` // shared config profile
 
 	// Google Vertex AI specific
 	ProjectID      string `json:"project_id,omitempty" yaml:"project_id"`
@@ -74,13 +111,57 @@ type CacheConfig struct {
 	Type    string        `json:"type" yaml:"type"` // "memory", "redis"
 	TTL     time.Duration `json:"ttl" yaml:"ttl"`
 	MaxSize int           `json:"max_size" yaml:"max_size"`
+
+	// Coalesce shares one provider call between concurrent identical
+	// requests. Independent of Enabled.
+	Coalesce bool `json:"coalesce" yaml:"coalesce"`
 }
 
 // GuardrailConfig configures guardrails.
 type GuardrailConfig struct {
-	PII       PIIConfig `json:"pii,omitempty" yaml:"pii"`
-	Injection bool      `json:"injection" yaml:"injection"`
-	Blocklist []string  `json:"blocklist,omitempty" yaml:"blocklist"`
+	PII       PIIConfig         `json:"pii,omitempty" yaml:"pii"`
+	Injection bool              `json:"injection" yaml:"injection"`
+	Blocklist []string          `json:"blocklist,omitempty" yaml:"blocklist"`
+	Stream    StreamGuardConfig `json:"stream,omitempty" yaml:"stream"`
+	Judge     *JudgeConfig      `json:"judge,omitempty" yaml:"judge"`
+
+	// Policies are named guard sets selected per tenant via
+	// tenant.Config.GuardrailPolicy. DefaultPolicy applies to tenants
+	// without one; the guards above remain the last-resort fallback.
+	Policies      map[string]GuardrailPolicyConfig `json:"policies,omitempty" yaml:"policies"`
+	DefaultPolicy string                           `json:"default_policy,omitempty" yaml:"default_policy"`
+}
+
+// JudgeConfig configures an LLM-as-judge guard.
+type JudgeConfig struct {
+	Provider  string        `json:"provider" yaml:"provider"`             // registered provider name
+	Model     string        `json:"model" yaml:"model"`                   // judge model
+	Phase     string        `json:"phase,omitempty" yaml:"phase"`         // "input" (default), "output", "both"
+	Action    string        `json:"action,omitempty" yaml:"action"`       // "block" (default), "warn"
+	Threshold float64       `json:"threshold,omitempty" yaml:"threshold"` // category score threshold (default 0.5)
+	Timeout   time.Duration `json:"timeout,omitempty" yaml:"timeout"`     // per-call timeout (default 5s)
+	FailOpen  bool          `json:"fail_open,omitempty" yaml:"fail_open"` // allow content when the judge fails
+	CacheTTL  time.Duration `json:"cache_ttl,omitempty" yaml:"cache_ttl"` // verdict cache TTL (default 5m)
+	Prompt    string        `json:"prompt,omitempty" yaml:"prompt"`       // custom prompt; {{text}} marks the content
+}
+
+// GuardrailPolicyConfig configures one named guardrail policy.
+type GuardrailPolicyConfig struct {
+	Guards []GuardConfig `json:"guards" yaml:"guards"`
+}
+
+// GuardConfig configures a single guard within a policy.
+type GuardConfig struct {
+	Type      string   `json:"type" yaml:"type"`                     // "pii", "injection", "blocklist"
+	Action    string   `json:"action,omitempty" yaml:"action"`       // "block", "redact", "warn"
+	Phase     string   `json:"phase,omitempty" yaml:"phase"`         // "input", "output", "both"; guard default when empty
+	Blocklist []string `json:"blocklist,omitempty" yaml:"blocklist"` // words for type "blocklist"
+}
+
+// StreamGuardConfig configures how output guards apply to streamed responses.
+type StreamGuardConfig struct {
+	Strategy   string `json:"strategy,omitempty" yaml:"strategy"`       // "sliding_window" (default), "chunkwise", "buffer", "passthrough"
+	WindowSize int    `json:"window_size,omitempty" yaml:"window_size"` // bytes held back by sliding_window
 }
 
 // PIIConfig configures PII detection.
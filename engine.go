@@ -2,7 +2,12 @@ package nexus
 
 import (
 	"context"
+	"errors"
 
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/batch"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
 	"github.com/xraph/nexus/provider"
 )
 
@@ -49,6 +54,151 @@ func (e *Engine) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*pr
 	return e.gw.pipeline.ExecuteEmbedding(ctx, req)
 }
 
+// GenerateImage sends an image generation request.
+func (e *Engine) GenerateImage(ctx context.Context, req *provider.ImageRequest) (*provider.ImageResponse, error) {
+	if e.gw.pipeline == nil {
+		return nil, ErrProviderNotFound
+	}
+	return e.gw.pipeline.ExecuteImage(ctx, req)
+}
+
+// Transcribe sends a speech-to-text request. Set req.Translate to translate
+// the audio to English.
+func (e *Engine) Transcribe(ctx context.Context, req *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error) {
+	if e.gw.pipeline == nil {
+		return nil, ErrProviderNotFound
+	}
+	return e.gw.pipeline.ExecuteTranscription(ctx, req)
+}
+
+// Speech sends a text-to-speech request. The caller must close the
+// response's Audio.
+func (e *Engine) Speech(ctx context.Context, req *provider.SpeechRequest) (*provider.SpeechResponse, error) {
+	if e.gw.pipeline == nil {
+		return nil, ErrProviderNotFound
+	}
+	return e.gw.pipeline.ExecuteSpeech(ctx, req)
+}
+
+// Rerank scores documents by relevance to a query.
+func (e *Engine) Rerank(ctx context.Context, req *provider.RerankRequest) (*provider.RerankResponse, error) {
+	if e.gw.pipeline == nil {
+		return nil, ErrProviderNotFound
+	}
+	return e.gw.pipeline.ExecuteRerank(ctx, req)
+}
+
+// CreateBatch starts an asynchronous batch of chat completions, read from
+// an uploaded JSONL file or input.Inputs. Poll GetBatch for progress and
+// read BatchResults once it is done.
+func (e *Engine) CreateBatch(ctx context.Context, input *batch.CreateInput) (*batch.Job, error) {
+	return e.gw.batch.Create(ctx, input)
+}
+
+// GetBatch returns a batch job, or nil if it does not exist.
+func (e *Engine) GetBatch(ctx context.Context, jobID string) (*batch.Job, error) {
+	return e.gw.batch.Get(ctx, jobID)
+}
+
+// CancelBatch stops a running batch. Finished items are kept.
+func (e *Engine) CancelBatch(ctx context.Context, jobID string) error {
+	return e.gw.batch.Cancel(ctx, jobID)
+}
+
+// BatchResults returns the results of a finished batch.
+func (e *Engine) BatchResults(ctx context.Context, jobID string) ([]batch.Result, error) {
+	return e.gw.batch.Results(ctx, jobID)
+}
+
+// batchExecutor runs worker-pool batch items through the full pipeline.
+type batchExecutor struct{ engine *Engine }
+
+func (x batchExecutor) Execute(ctx context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	return x.engine.Complete(requestContext(ctx, req), req)
+}
+
+// Prepare runs a native batch item through the default pipeline's input
+// phase. A custom pipeline has no separable input phase, so its items
+// always run on the worker pool.
+func (x batchExecutor) Prepare(ctx context.Context, req *provider.CompletionRequest) (*provider.CompletionRequest, error) {
+	in := x.engine.gw.inputPipeline
+	if in == nil {
+		return nil, errors.New("nexus: batch requests cannot be prepared outside the default pipeline")
+	}
+	if _, err := in.Execute(requestContext(ctx, req), req); !errors.Is(err, errPrepared) {
+		return nil, err
+	}
+	return req, nil
+}
+
+// requestContext restores the tenant and key of a request run outside the
+// HTTP handler that authenticated it.
+func requestContext(ctx context.Context, req *provider.CompletionRequest) context.Context {
+	if req.TenantID != "" {
+		ctx = pipeline.WithTenantID(ctx, req.TenantID)
+	}
+	if req.KeyID != "" {
+		ctx = pipeline.WithKeyID(ctx, req.KeyID)
+	}
+	return ctx
+}
+
+// errPrepared ends the input pipeline where the provider call would run,
+// skipping the output phase of everything before it.
+var errPrepared = errors.New("nexus: request prepared")
+
+// preparedRequest takes the provider call's place in the input pipeline.
+type preparedRequest struct{}
+
+func (preparedRequest) Name() string  { return "prepared_request" }
+func (preparedRequest) Priority() int { return 350 }
+
+func (preparedRequest) Process(context.Context, *pipeline.Request, pipeline.NextFunc) (*pipeline.Response, error) {
+	return nil, errPrepared
+}
+
+// CountTokens estimates the prompt tokens of a completion request with the
+// gateway's token counter, without calling a provider.
+func (e *Engine) CountTokens(ctx context.Context, req *provider.CompletionRequest) (int, error) {
+	return middlewares.CountRequestTokens(ctx, e.gw.tokenCounter, req)
+}
+
+// CreateBackground starts a chat completion on a background worker and
+// returns immediately. Poll GetBackground for the result, or replay the
+// stream with BackgroundFrames.
+func (e *Engine) CreateBackground(ctx context.Context, input *background.CreateInput) (*background.Response, error) {
+	return e.gw.background.Create(ctx, input)
+}
+
+// GetBackground returns a background response, or nil if it does not exist.
+func (e *Engine) GetBackground(ctx context.Context, responseID string) (*background.Response, error) {
+	return e.gw.background.Get(ctx, responseID)
+}
+
+// RecordResponse stores a finished foreground response so it can be
+// fetched, and continued with previous_response_id, later.
+func (e *Engine) RecordResponse(ctx context.Context, resp *background.Response) error {
+	return e.gw.background.Record(ctx, resp)
+}
+
+// CancelBackground stops a queued or running background response.
+func (e *Engine) CancelBackground(ctx context.Context, responseID string) (*background.Response, error) {
+	return e.gw.background.Cancel(ctx, responseID)
+}
+
+// BackgroundFrames returns the stream frames a background response has
+// persisted after sequence number after.
+func (e *Engine) BackgroundFrames(ctx context.Context, responseID string, after int) ([]background.Frame, error) {
+	return e.gw.background.Frames(ctx, responseID, after)
+}
+
+// backgroundExecutor streams background responses through the full pipeline.
+type backgroundExecutor struct{ engine *Engine }
+
+func (x backgroundExecutor) ExecuteStream(ctx context.Context, req *provider.CompletionRequest) (provider.Stream, error) {
+	return x.engine.CompleteStream(requestContext(ctx, req), req)
+}
+
 // ListModels returns available models across all providers.
 func (e *Engine) ListModels(ctx context.Context) ([]provider.Model, error) {
 	if e.gw.model != nil {
@@ -126,6 +126,20 @@ func (e *Extension) Start(ctx context.Context) error {
 		}
 	}
 
+	// Resume native provider batches left running by a previous process.
+	if b := gw.Batches(); b != nil {
+		if err := b.Start(ctx); err != nil {
+			e.Logger().Warn("nexus: failed to resume batches", forge.F("error", err))
+		}
+	}
+
+	// Run queued background responses and close out interrupted ones.
+	if b := gw.Background(); b != nil {
+		if err := b.Start(ctx); err != nil {
+			e.Logger().Warn("nexus: failed to resume background responses", forge.F("error", err))
+		}
+	}
+
 	e.Logger().Info("nexus: gateway started",
 		forge.F("providers", gw.Providers().Count()),
 		forge.F("extensions", gw.Extensions().Count()),
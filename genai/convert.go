@@ -0,0 +1,459 @@
+package genai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// ToCompletionRequest translates a generateContent request for model
+// into a unified completion request.
+//
+// Gemini function calls need not carry IDs, so calls without one get
+// "call_<n>" and each functionResponse answers the oldest open call of
+// the same name.
+func ToCompletionRequest(model string, req *Request) (*provider.CompletionRequest, error) {
+	if model == "" {
+		return nil, fmt.Errorf("model is required")
+	}
+	if len(req.Contents) == 0 {
+		return nil, fmt.Errorf("contents is not specified")
+	}
+
+	creq := &provider.CompletionRequest{
+		Model:         model,
+		CachedContent: req.CachedContent,
+	}
+	if req.SystemInstruction != nil {
+		for i, p := range req.SystemInstruction.Parts {
+			if p.FunctionCall != nil || p.FunctionResponse != nil || p.InlineData != nil || p.FileData != nil {
+				return nil, fmt.Errorf("systemInstruction.parts[%d]: only text parts are supported", i)
+			}
+		}
+		creq.System = joinText(req.SystemInstruction.Parts, "\n")
+	}
+
+	calls := &callIDs{open: make(map[string][]string)}
+	for i, c := range req.Contents {
+		var (
+			msgs []provider.Message
+			err  error
+		)
+		switch c.Role {
+		case RoleUser, "":
+			msgs, err = userMessages(c.Parts, calls)
+		case RoleModel:
+			msgs, err = modelMessages(c.Parts, calls)
+		default:
+			err = fmt.Errorf("unsupported role %q", c.Role)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("contents[%d]: %w", i, err)
+		}
+		creq.Messages = append(creq.Messages, msgs...)
+	}
+
+	for i, t := range req.Tools {
+		if t.GoogleSearch != nil || t.GoogleSearchRetrieval != nil || t.CodeExecution != nil || t.URLContext != nil {
+			return nil, fmt.Errorf("tools[%d]: built-in tools are not supported: only functionDeclarations", i)
+		}
+		for _, d := range t.FunctionDeclarations {
+			params := d.ParametersJSONSchema
+			if params == nil {
+				params = JSONSchema(d.Parameters)
+			}
+			creq.Tools = append(creq.Tools, provider.Tool{
+				Type: "function",
+				Function: provider.ToolFunction{
+					Name:        d.Name,
+					Description: d.Description,
+					Parameters:  params,
+				},
+			})
+		}
+	}
+	if req.ToolConfig != nil && req.ToolConfig.FunctionCallingConfig != nil {
+		choice, err := toolChoice(req.ToolConfig.FunctionCallingConfig)
+		if err != nil {
+			return nil, err
+		}
+		creq.ToolChoice = choice
+	}
+
+	if gc := req.GenerationConfig; gc != nil {
+		if err := applyGenerationConfig(creq, gc); err != nil {
+			return nil, err
+		}
+	}
+	return creq, nil
+}
+
+// callIDs pairs function calls with their responses.
+type callIDs struct {
+	n    int
+	open map[string][]string // function name → IDs awaiting a response
+}
+
+func (c *callIDs) call(id, name string) string {
+	if id == "" {
+		c.n++
+		id = fmt.Sprintf("call_%d", c.n)
+	}
+	c.open[name] = append(c.open[name], id)
+	return id
+}
+
+func (c *callIDs) response(id, name string) string {
+	open := c.open[name]
+	if id != "" {
+		for i, o := range open {
+			if o == id {
+				c.open[name] = append(open[:i:i], open[i+1:]...)
+				break
+			}
+		}
+		return id
+	}
+	if len(open) == 0 {
+		c.n++
+		return fmt.Sprintf("call_%d", c.n)
+	}
+	c.open[name] = open[1:]
+	return open[0]
+}
+
+// userMessages converts a user turn. Function responses become tool
+// messages; the remaining parts become one user message after them.
+func userMessages(parts []Part, calls *callIDs) ([]provider.Message, error) {
+	var out []provider.Message
+	var content []provider.ContentPart
+	for i, p := range parts {
+		switch {
+		case p.FunctionResponse != nil:
+			fr := p.FunctionResponse
+			if fr.Name == "" {
+				return nil, fmt.Errorf("parts[%d]: functionResponse.name is required", i)
+			}
+			result, err := json.Marshal(fr.Response)
+			if err != nil {
+				return nil, fmt.Errorf("parts[%d]: functionResponse.response: %w", i, err)
+			}
+			out = append(out, provider.Message{
+				Role:       "tool",
+				Content:    string(result),
+				Name:       fr.Name,
+				ToolCallID: calls.response(fr.ID, fr.Name),
+			})
+		case p.InlineData != nil:
+			if !strings.HasPrefix(p.InlineData.MimeType, "image/") {
+				return nil, fmt.Errorf("parts[%d]: unsupported inlineData mimeType %q: only images are supported", i, p.InlineData.MimeType)
+			}
+			content = append(content, provider.ContentPart{Type: "image_base64", Data: p.InlineData.Data, MimeType: p.InlineData.MimeType})
+		case p.FileData != nil:
+			if p.FileData.MimeType != "" && !strings.HasPrefix(p.FileData.MimeType, "image/") {
+				return nil, fmt.Errorf("parts[%d]: unsupported fileData mimeType %q: only images are supported", i, p.FileData.MimeType)
+			}
+			content = append(content, provider.ContentPart{Type: "image_url", ImageURL: p.FileData.FileURI, MimeType: p.FileData.MimeType})
+		case p.FunctionCall != nil:
+			return nil, fmt.Errorf("parts[%d]: functionCall is only valid in model turns", i)
+		default:
+			content = append(content, provider.ContentPart{Type: "text", Text: p.Text})
+		}
+	}
+	if len(content) > 0 {
+		out = append(out, contentMessage(content))
+	}
+	return out, nil
+}
+
+// modelMessages converts a model turn: text parts become the content and
+// function calls its tool calls. Thought parts are dropped.
+func modelMessages(parts []Part, calls *callIDs) ([]provider.Message, error) {
+	var text strings.Builder
+	var toolCalls []provider.ToolCall
+	for i, p := range parts {
+		switch {
+		case p.FunctionCall != nil:
+			fc := p.FunctionCall
+			if fc.Name == "" {
+				return nil, fmt.Errorf("parts[%d]: functionCall.name is required", i)
+			}
+			args := "{}"
+			if len(fc.Args) > 0 {
+				raw, err := json.Marshal(fc.Args)
+				if err != nil {
+					return nil, fmt.Errorf("parts[%d]: functionCall.args: %w", i, err)
+				}
+				args = string(raw)
+			}
+			toolCalls = append(toolCalls, provider.ToolCall{
+				ID:       calls.call(fc.ID, fc.Name),
+				Type:     "function",
+				Function: provider.ToolCallFunc{Name: fc.Name, Arguments: args},
+			})
+		case p.Thought:
+		case p.FunctionResponse != nil, p.InlineData != nil, p.FileData != nil:
+			return nil, fmt.Errorf("parts[%d]: only text and functionCall parts are supported in model turns", i)
+		default:
+			text.WriteString(p.Text)
+		}
+	}
+	return []provider.Message{{Role: "assistant", Content: text.String(), ToolCalls: toolCalls}}, nil
+}
+
+// contentMessage flattens text-only parts to a string.
+func contentMessage(parts []provider.ContentPart) provider.Message {
+	for _, p := range parts {
+		if p.Type != "text" {
+			return provider.Message{Role: "user", Content: parts}
+		}
+	}
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(p.Text)
+	}
+	return provider.Message{Role: "user", Content: b.String()}
+}
+
+// toolChoice converts functionCallingConfig to the chat form. ANY with a
+// single allowed function forces that function.
+func toolChoice(fc *FunctionCallingConfig) (any, error) {
+	switch strings.ToUpper(fc.Mode) {
+	case "", "MODE_UNSPECIFIED", "AUTO":
+		return "auto", nil
+	case "ANY":
+		if len(fc.AllowedFunctionNames) == 1 {
+			return map[string]any{"type": "function", "function": map[string]any{"name": fc.AllowedFunctionNames[0]}}, nil
+		}
+		return "required", nil
+	case "NONE":
+		return "none", nil
+	default:
+		return nil, fmt.Errorf("toolConfig.functionCallingConfig.mode: unsupported mode %q", fc.Mode)
+	}
+}
+
+func applyGenerationConfig(creq *provider.CompletionRequest, gc *GenerationConfig) error {
+	if gc.CandidateCount > 1 {
+		return fmt.Errorf("generationConfig.candidateCount: only 1 candidate is supported")
+	}
+	creq.MaxTokens = gc.MaxOutputTokens
+	creq.Temperature = gc.Temperature
+	creq.TopP = gc.TopP
+	creq.Stop = gc.StopSequences
+
+	switch gc.ResponseMimeType {
+	case "", "text/plain":
+	case "application/json":
+		schema := gc.ResponseJSONSchema
+		if schema == nil && gc.ResponseSchema != nil {
+			schema = JSONSchema(gc.ResponseSchema)
+		}
+		if schema == nil {
+			creq.ResponseFormat = &provider.ResponseFormat{Type: "json_object"}
+		} else {
+			creq.ResponseFormat = &provider.ResponseFormat{
+				Type:       "json_schema",
+				JSONSchema: &provider.JSONSchemaDef{Name: "response", Schema: schema},
+			}
+		}
+	default:
+		return fmt.Errorf("generationConfig.responseMimeType: unsupported type %q", gc.ResponseMimeType)
+	}
+
+	if tc := gc.ThinkingConfig; tc != nil && tc.ThinkingBudget != nil && *tc.ThinkingBudget > 0 {
+		creq.Thinking = &provider.ThinkingConfig{
+			Enabled:         true,
+			BudgetTokens:    *tc.ThinkingBudget,
+			IncludeThinking: tc.IncludeThoughts,
+		}
+	}
+	return nil
+}
+
+// JSONSchema converts a Gemini OpenAPI schema to JSON Schema by
+// lower-casing its type names (OBJECT → object); other keywords are
+// shared. The input is not modified.
+func JSONSchema(schema any) any {
+	switch v := schema.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			if s, ok := val.(string); ok && k == "type" {
+				out[k] = strings.ToLower(s)
+				continue
+			}
+			out[k] = JSONSchema(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = JSONSchema(val)
+		}
+		return out
+	default:
+		return schema
+	}
+}
+
+// FromCompletion converts a completion into a GenerateContentResponse
+// with one candidate: a thought part when thinking was returned, the
+// text, then one functionCall part per tool call.
+func FromCompletion(resp *provider.CompletionResponse) *Response {
+	out := &Response{
+		Candidates:    []Candidate{},
+		UsageMetadata: NewUsage(&resp.Usage),
+		ModelVersion:  resp.Model,
+		ResponseID:    resp.ID,
+	}
+	if len(resp.Choices) == 0 {
+		return out
+	}
+	choice := resp.Choices[0]
+	var parts []Part
+	if resp.ThinkingContent != "" {
+		parts = append(parts, Part{Text: resp.ThinkingContent, Thought: true})
+	}
+	if text := messageText(choice.Message.Content); text != "" {
+		parts = append(parts, Part{Text: text})
+	}
+	for _, tc := range choice.Message.ToolCalls {
+		parts = append(parts, Part{FunctionCall: &FunctionCall{ID: tc.ID, Name: tc.Function.Name, Args: FunctionArgs(tc.Function.Arguments)}})
+	}
+	if parts == nil {
+		parts = []Part{}
+	}
+	out.Candidates = append(out.Candidates, Candidate{
+		Content:      Content{Role: RoleModel, Parts: parts},
+		FinishReason: FinishReason(choice.FinishReason),
+	})
+	return out
+}
+
+// FunctionArgs decodes tool-call arguments; arguments that are empty or
+// not a JSON object become an empty object.
+func FunctionArgs(arguments string) map[string]any {
+	args := map[string]any{}
+	if arguments != "" {
+		if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+			return map[string]any{}
+		}
+	}
+	return args
+}
+
+// FinishReason maps a unified finish reason to a Gemini finish reason.
+// Gemini reports STOP for turns that end in function calls. Reasons
+// already in Gemini form pass through.
+func FinishReason(finishReason string) string {
+	switch finishReason {
+	case "":
+		return ""
+	case "stop", "tool_calls", "function_call":
+		return FinishStop
+	case "length":
+		return FinishMaxTokens
+	case "content_filter":
+		return FinishSafety
+	default:
+		if strings.ToUpper(finishReason) == finishReason {
+			return finishReason
+		}
+		return FinishOther
+	}
+}
+
+// NewUsage converts unified usage to Gemini usage metadata.
+func NewUsage(u *provider.Usage) *UsageMetadata {
+	if u == nil {
+		return nil
+	}
+	total := u.TotalTokens
+	if total == 0 {
+		total = u.PromptTokens + u.CompletionTokens
+	}
+	return &UsageMetadata{
+		PromptTokenCount:        u.PromptTokens,
+		CandidatesTokenCount:    u.CompletionTokens,
+		TotalTokenCount:         total,
+		CachedContentTokenCount: u.CacheReadTokens,
+		ThoughtsTokenCount:      u.ThinkingTokens,
+	}
+}
+
+// ToEmbeddingRequest translates embedContent requests for model into one
+// unified embedding request, one input per request. The requests must
+// agree on taskType and outputDimensionality.
+func ToEmbeddingRequest(model string, reqs []EmbedContentRequest) (*provider.EmbeddingRequest, error) {
+	if model == "" {
+		return nil, fmt.Errorf("model is required")
+	}
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("requests is not specified")
+	}
+	ereq := &provider.EmbeddingRequest{
+		Model:      model,
+		Dimensions: reqs[0].OutputDimensionality,
+		InputType:  inputType(reqs[0].TaskType),
+	}
+	for i, r := range reqs {
+		if m := strings.TrimPrefix(r.Model, "models/"); m != "" && m != model {
+			return nil, fmt.Errorf("requests[%d].model: %q does not match %q", i, m, model)
+		}
+		if r.TaskType != reqs[0].TaskType || r.OutputDimensionality != reqs[0].OutputDimensionality {
+			return nil, fmt.Errorf("requests[%d]: taskType and outputDimensionality must match across requests", i)
+		}
+		text := joinText(r.Content.Parts, "\n")
+		if text == "" {
+			return nil, fmt.Errorf("requests[%d].content: a text part is required", i)
+		}
+		ereq.Input = append(ereq.Input, text)
+	}
+	return ereq, nil
+}
+
+// inputType maps a Gemini task type to a unified embedding input type.
+func inputType(taskType string) string {
+	switch taskType {
+	case "RETRIEVAL_QUERY", "QUESTION_ANSWERING", "CODE_RETRIEVAL_QUERY":
+		return provider.EmbeddingInputQuery
+	case "RETRIEVAL_DOCUMENT", "FACT_VERIFICATION":
+		return provider.EmbeddingInputDocument
+	case "CLASSIFICATION":
+		return provider.EmbeddingInputClassification
+	case "CLUSTERING":
+		return provider.EmbeddingInputClustering
+	default:
+		return ""
+	}
+}
+
+// joinText concatenates the text of non-thought parts.
+func joinText(parts []Part, sep string) string {
+	texts := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p.Text != "" && !p.Thought {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, sep)
+}
+
+// messageText flattens message content to its text.
+func messageText(content any) string {
+	switch v := content.(type) {
+	case string:
+		return v
+	case []provider.ContentPart:
+		var b strings.Builder
+		for _, p := range v {
+			b.WriteString(p.Text)
+		}
+		return b.String()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,157 @@
+package genai_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xraph/nexus/genai"
+	"github.com/xraph/nexus/provider"
+)
+
+func decodeRequest(t *testing.T, body string) *genai.Request {
+	t.Helper()
+	var req genai.Request
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return &req
+}
+
+func TestToCompletionRequest(t *testing.T) {
+	t.Parallel()
+	req := decodeRequest(t, `{
+		"systemInstruction": {"parts": [{"text": "You are terse."}, {"text": "Use tools."}]},
+		"contents": [
+			{"role": "user", "parts": [
+				{"text": "What is in this image?"},
+				{"inlineData": {"mimeType": "image/png", "data": "iVBOR"}}
+			]},
+			{"role": "model", "parts": [
+				{"text": "thinking it over", "thought": true},
+				{"text": "Checking."},
+				{"functionCall": {"name": "lookup", "args": {"q": "png"}}}
+			]},
+			{"role": "user", "parts": [
+				{"functionResponse": {"name": "lookup", "response": {"result": "a cat"}}},
+				{"text": "Thanks"}
+			]}
+		],
+		"tools": [{"functionDeclarations": [{"name": "lookup", "description": "Search",
+			"parameters": {"type": "OBJECT", "properties": {"q": {"type": "STRING"}}, "required": ["q"]}}]}],
+		"toolConfig": {"functionCallingConfig": {"mode": "ANY", "allowedFunctionNames": ["lookup"]}},
+		"generationConfig": {"maxOutputTokens": 1024, "stopSequences": ["END"], "temperature": 0.2,
+			"responseMimeType": "application/json", "responseSchema": {"type": "ARRAY", "items": {"type": "STRING"}},
+			"thinkingConfig": {"thinkingBudget": 512, "includeThoughts": true}},
+		"cachedContent": "cachedContents/abc"
+	}`)
+
+	creq, err := genai.ToCompletionRequest("gemini-2.5-flash", req)
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	if creq.Model != "gemini-2.5-flash" || creq.System != "You are terse.\nUse tools." || creq.CachedContent != "cachedContents/abc" {
+		t.Fatalf("request = %+v", creq)
+	}
+	if len(creq.Messages) != 4 {
+		t.Fatalf("messages = %+v", creq.Messages)
+	}
+	parts, ok := creq.Messages[0].Content.([]provider.ContentPart)
+	if !ok || len(parts) != 2 || parts[1].Type != "image_base64" || parts[1].MimeType != "image/png" {
+		t.Fatalf("image message = %#v", creq.Messages[0].Content)
+	}
+	m := creq.Messages[1]
+	if m.Role != "assistant" || m.Content != "Checking." || len(m.ToolCalls) != 1 || m.ToolCalls[0].Function.Arguments != `{"q":"png"}` {
+		t.Fatalf("model message = %+v", m)
+	}
+	if tr := creq.Messages[2]; tr.Role != "tool" || tr.Name != "lookup" || tr.ToolCallID != m.ToolCalls[0].ID || tr.Content != `{"result":"a cat"}` {
+		t.Fatalf("tool result = %+v (call %q)", tr, m.ToolCalls[0].ID)
+	}
+	if u := creq.Messages[3]; u.Role != "user" || u.Content != "Thanks" {
+		t.Fatalf("trailing user text = %+v", u)
+	}
+
+	params := creq.Tools[0].Function.Parameters.(map[string]any)
+	if params["type"] != "object" || params["properties"].(map[string]any)["q"].(map[string]any)["type"] != "string" {
+		t.Fatalf("tool parameters = %v", params)
+	}
+	if choice, ok := creq.ToolChoice.(map[string]any); !ok || choice["function"].(map[string]any)["name"] != "lookup" {
+		t.Fatalf("tool choice = %v", creq.ToolChoice)
+	}
+	if rf := creq.ResponseFormat; rf == nil || rf.Type != "json_schema" || rf.JSONSchema.Schema.(map[string]any)["type"] != "array" {
+		t.Fatalf("response format = %+v", creq.ResponseFormat)
+	}
+	if th := creq.Thinking; th == nil || th.BudgetTokens != 512 || !th.IncludeThinking {
+		t.Fatalf("thinking = %+v", creq.Thinking)
+	}
+	if creq.MaxTokens != 1024 || creq.Stop[0] != "END" || *creq.Temperature != 0.2 {
+		t.Fatalf("generation config = %+v", creq)
+	}
+}
+
+func TestToCompletionRequest_Errors(t *testing.T) {
+	t.Parallel()
+	for name, body := range map[string]string{
+		"no contents":   `{}`,
+		"built-in tool": `{"contents":[{"parts":[{"text":"hi"}]}],"tools":[{"googleSearch":{}}]}`,
+		"bad role":      `{"contents":[{"role":"system","parts":[{"text":"hi"}]}]}`,
+		"audio":         `{"contents":[{"parts":[{"inlineData":{"mimeType":"audio/wav","data":"AA"}}]}]}`,
+		"bad mode":      `{"contents":[{"parts":[{"text":"hi"}]}],"toolConfig":{"functionCallingConfig":{"mode":"SOMETIMES"}}}`,
+		"candidates":    `{"contents":[{"parts":[{"text":"hi"}]}],"generationConfig":{"candidateCount":2}}`,
+	} {
+		if _, err := genai.ToCompletionRequest("m", decodeRequest(t, body)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestFromCompletion(t *testing.T) {
+	t.Parallel()
+	resp := genai.FromCompletion(&provider.CompletionResponse{
+		ID:              "chatcmpl-abc",
+		Model:           "gpt-4o",
+		ThinkingContent: "hmm",
+		Choices: []provider.Choice{{
+			Message: provider.Message{Role: "assistant", Content: "Let me check.", ToolCalls: []provider.ToolCall{
+				{ID: "call_1", Type: "function", Function: provider.ToolCallFunc{Name: "lookup", Arguments: `{"q":1}`}},
+			}},
+			FinishReason: "tool_calls",
+		}},
+		Usage: provider.Usage{PromptTokens: 10, CompletionTokens: 4, TotalTokens: 14, CacheReadTokens: 2},
+	})
+
+	raw, _ := json.Marshal(resp)
+	want := `{"candidates":[{"content":{"role":"model","parts":[` +
+		`{"text":"hmm","thought":true},` +
+		`{"text":"Let me check."},` +
+		`{"functionCall":{"id":"call_1","name":"lookup","args":{"q":1}}}]},` +
+		`"finishReason":"STOP","index":0}],` +
+		`"usageMetadata":{"promptTokenCount":10,"candidatesTokenCount":4,"totalTokenCount":14,"cachedContentTokenCount":2},` +
+		`"modelVersion":"gpt-4o","responseId":"chatcmpl-abc"}`
+	if string(raw) != want {
+		t.Fatalf("response =\n%s\nwant\n%s", raw, want)
+	}
+}
+
+func TestToEmbeddingRequest(t *testing.T) {
+	t.Parallel()
+	reqs := []genai.EmbedContentRequest{
+		{Model: "models/text-embedding-004", Content: genai.Content{Parts: []genai.Part{{Text: "first"}}}, TaskType: "RETRIEVAL_DOCUMENT", OutputDimensionality: 256},
+		{Content: genai.Content{Parts: []genai.Part{{Text: "second"}, {Text: "half"}}}, TaskType: "RETRIEVAL_DOCUMENT", OutputDimensionality: 256},
+	}
+	ereq, err := genai.ToEmbeddingRequest("text-embedding-004", reqs)
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	if ereq.Model != "text-embedding-004" || len(ereq.Input) != 2 || ereq.Input[1] != "second\nhalf" ||
+		ereq.Dimensions != 256 || ereq.InputType != provider.EmbeddingInputDocument {
+		t.Fatalf("embedding request = %+v", ereq)
+	}
+
+	reqs[1].TaskType = "RETRIEVAL_QUERY"
+	if _, err := genai.ToEmbeddingRequest("text-embedding-004", reqs); err == nil {
+		t.Fatal("mixed task types: expected an error")
+	}
+	if _, err := genai.ToEmbeddingRequest("other-model", reqs[:1]); err == nil {
+		t.Fatal("model mismatch: expected an error")
+	}
+}
@@ -0,0 +1,196 @@
+// Package genai translates the Gemini API (generateContent,
+// streamGenerateContent, embedContent and batchEmbedContents) to and from
+// the unified provider types, so clients built on the Google GenAI SDKs
+// can reach any provider the gateway serves.
+//
+// Contents, parts, function declarations, the tool config and the system
+// instruction map onto a provider.CompletionRequest; a
+// provider.CompletionResponse maps back onto a GenerateContentResponse
+// with thought, text and functionCall parts.
+package genai
+
+// Roles.
+const (
+	RoleUser  = "user"
+	RoleModel = "model"
+)
+
+// Finish reasons.
+const (
+	FinishStop      = "STOP"
+	FinishMaxTokens = "MAX_TOKENS"
+	FinishSafety    = "SAFETY"
+	FinishOther     = "OTHER"
+)
+
+// Request is the body of :generateContent and :streamGenerateContent.
+// The model comes from the URL path.
+type Request struct {
+	Contents          []Content         `json:"contents"`
+	SystemInstruction *Content          `json:"systemInstruction,omitempty"`
+	Tools             []Tool            `json:"tools,omitempty"`
+	ToolConfig        *ToolConfig       `json:"toolConfig,omitempty"`
+	GenerationConfig  *GenerationConfig `json:"generationConfig,omitempty"`
+	SafetySettings    []any             `json:"safetySettings,omitempty"` // accepted, not forwarded
+	CachedContent     string            `json:"cachedContent,omitempty"`
+}
+
+// Content is one conversation turn.
+type Content struct {
+	Role  string `json:"role,omitempty"` // user, model
+	Parts []Part `json:"parts"`
+}
+
+// Part is one piece of a turn. Exactly one of the data fields is set.
+type Part struct {
+	Text             string            `json:"text,omitempty"`
+	Thought          bool              `json:"thought,omitempty"`
+	InlineData       *Blob             `json:"inlineData,omitempty"`
+	FileData         *FileData         `json:"fileData,omitempty"`
+	FunctionCall     *FunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *FunctionResponse `json:"functionResponse,omitempty"`
+}
+
+// Blob is inline base64 data.
+type Blob struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+// FileData references data by URI.
+type FileData struct {
+	MimeType string `json:"mimeType,omitempty"`
+	FileURI  string `json:"fileUri"`
+}
+
+// FunctionCall is a call the model asks the client to make.
+type FunctionCall struct {
+	ID   string         `json:"id,omitempty"`
+	Name string         `json:"name"`
+	Args map[string]any `json:"args"`
+}
+
+// FunctionResponse is the client's result for a FunctionCall.
+type FunctionResponse struct {
+	ID       string         `json:"id,omitempty"`
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+// Tool holds function declarations. Gemini's built-in tools are decoded
+// only so they can be rejected.
+type Tool struct {
+	FunctionDeclarations  []FunctionDeclaration `json:"functionDeclarations,omitempty"`
+	GoogleSearch          any                   `json:"googleSearch,omitempty"`
+	GoogleSearchRetrieval any                   `json:"googleSearchRetrieval,omitempty"`
+	CodeExecution         any                   `json:"codeExecution,omitempty"`
+	URLContext            any                   `json:"urlContext,omitempty"`
+}
+
+// FunctionDeclaration describes a function tool. Parameters is an OpenAPI
+// schema; ParametersJSONSchema a JSON Schema.
+type FunctionDeclaration struct {
+	Name                 string `json:"name"`
+	Description          string `json:"description,omitempty"`
+	Parameters           any    `json:"parameters,omitempty"`
+	ParametersJSONSchema any    `json:"parametersJsonSchema,omitempty"`
+}
+
+// ToolConfig controls function calling.
+type ToolConfig struct {
+	FunctionCallingConfig *FunctionCallingConfig `json:"functionCallingConfig,omitempty"`
+}
+
+// FunctionCallingConfig selects the calling mode: AUTO, ANY or NONE.
+type FunctionCallingConfig struct {
+	Mode                 string   `json:"mode,omitempty"`
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
+}
+
+// GenerationConfig holds the sampling and output parameters.
+type GenerationConfig struct {
+	StopSequences      []string        `json:"stopSequences,omitempty"`
+	ResponseMimeType   string          `json:"responseMimeType,omitempty"`
+	ResponseSchema     any             `json:"responseSchema,omitempty"`
+	ResponseJSONSchema any             `json:"responseJsonSchema,omitempty"`
+	CandidateCount     int             `json:"candidateCount,omitempty"`
+	MaxOutputTokens    int             `json:"maxOutputTokens,omitempty"`
+	Temperature        *float64        `json:"temperature,omitempty"`
+	TopP               *float64        `json:"topP,omitempty"`
+	TopK               *int            `json:"topK,omitempty"` // accepted, not forwarded
+	ThinkingConfig     *ThinkingConfig `json:"thinkingConfig,omitempty"`
+}
+
+// ThinkingConfig enables thinking. A positive ThinkingBudget enables it
+// with that budget; 0 and -1 (dynamic) leave the provider default.
+type ThinkingConfig struct {
+	IncludeThoughts bool `json:"includeThoughts,omitempty"`
+	ThinkingBudget  *int `json:"thinkingBudget,omitempty"`
+}
+
+// Response is a GenerateContentResponse, and one chunk of a stream.
+type Response struct {
+	Candidates    []Candidate    `json:"candidates"`
+	UsageMetadata *UsageMetadata `json:"usageMetadata,omitempty"`
+	ModelVersion  string         `json:"modelVersion,omitempty"`
+	ResponseID    string         `json:"responseId,omitempty"`
+}
+
+// Candidate is one generated answer.
+type Candidate struct {
+	Content      Content `json:"content"`
+	FinishReason string  `json:"finishReason,omitempty"`
+	Index        int     `json:"index"`
+}
+
+// UsageMetadata reports token usage.
+type UsageMetadata struct {
+	PromptTokenCount        int `json:"promptTokenCount"`
+	CandidatesTokenCount    int `json:"candidatesTokenCount"`
+	TotalTokenCount         int `json:"totalTokenCount"`
+	CachedContentTokenCount int `json:"cachedContentTokenCount,omitempty"`
+	ThoughtsTokenCount      int `json:"thoughtsTokenCount,omitempty"`
+}
+
+// EmbedContentRequest is the body of :embedContent, and one entry of
+// :batchEmbedContents.
+type EmbedContentRequest struct {
+	Model                string  `json:"model,omitempty"`
+	Content              Content `json:"content"`
+	TaskType             string  `json:"taskType,omitempty"`
+	Title                string  `json:"title,omitempty"` // accepted, not forwarded
+	OutputDimensionality int     `json:"outputDimensionality,omitempty"`
+}
+
+// EmbedContentResponse is the :embedContent answer.
+type EmbedContentResponse struct {
+	Embedding ContentEmbedding `json:"embedding"`
+}
+
+// BatchEmbedContentsRequest is the body of :batchEmbedContents.
+type BatchEmbedContentsRequest struct {
+	Requests []EmbedContentRequest `json:"requests"`
+}
+
+// BatchEmbedContentsResponse is the :batchEmbedContents answer.
+type BatchEmbedContentsResponse struct {
+	Embeddings []ContentEmbedding `json:"embeddings"`
+}
+
+// ContentEmbedding is one embedding vector.
+type ContentEmbedding struct {
+	Values []float64 `json:"values"`
+}
+
+// ErrorResponse is the Gemini API error envelope.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes an error: the HTTP status code and its gRPC status
+// name (INVALID_ARGUMENT, UNAUTHENTICATED, …).
+type ErrorBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
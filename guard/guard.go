@@ -28,9 +28,12 @@ const (
 	PhaseBoth   Phase = "both"
 )
 
-// CheckInput is the content to evaluate.
+// CheckInput is the content to evaluate. Use Segments or RewriteText to
+// inspect it: message content may be a string or content parts, and text
+// also lives in System, tool-call arguments and tool results.
 type CheckInput struct {
 	Messages []provider.Message
+	System   string // system prompt (request phase only)
 	TenantID string
 	Metadata map[string]string
 }
@@ -43,6 +46,7 @@ type CheckResult struct {
 	Reason   string             // human-readable explanation
 	Modified bool               // true if messages were altered (e.g., PII redacted)
 	Messages []provider.Message // modified messages (if Modified)
+	System   *string            // modified system prompt (if Modified and non-nil)
 	Details  map[string]any     // guard-specific details
 }
 
@@ -99,6 +103,10 @@ func (s *guardService) Check(ctx context.Context, input *CheckInput) (*CheckResu
 			input.Messages = r.Messages
 			result.Modified = true
 			result.Messages = r.Messages
+			if r.System != nil {
+				input.System = *r.System
+				result.System = r.System
+			}
 		}
 	}
 	return result, nil
@@ -121,6 +129,10 @@ func (s *guardService) CheckPhase(ctx context.Context, phase Phase, input *Check
 			input.Messages = r.Messages
 			result.Modified = true
 			result.Messages = r.Messages
+			if r.System != nil {
+				input.System = *r.System
+				result.System = r.System
+			}
 		}
 	}
 	return result, nil
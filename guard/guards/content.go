@@ -29,12 +29,8 @@ func (g *ContentFilterGuard) Name() string       { return "content_filter" }
 func (g *ContentFilterGuard) Phase() guard.Phase { return guard.PhaseBoth }
 
 func (g *ContentFilterGuard) Check(_ context.Context, input *guard.CheckInput) (*guard.CheckResult, error) {
-	for _, msg := range input.Messages {
-		content, ok := msg.Content.(string)
-		if !ok {
-			continue
-		}
-		lower := strings.ToLower(content)
+	for _, seg := range input.Segments() {
+		lower := strings.ToLower(seg.Text)
 		for _, word := range g.blocklist {
 			if strings.Contains(lower, word) {
 				return &guard.CheckResult{
@@ -10,11 +10,12 @@ import (
 // InjectionGuard detects prompt injection attempts.
 type InjectionGuard struct {
 	patterns []*regexp.Regexp
+	action   guard.Action
 }
 
 // NewInjection creates a prompt injection detection guard.
 func NewInjection() *InjectionGuard {
-	g := &InjectionGuard{}
+	g := &InjectionGuard{action: guard.ActionBlock}
 
 	// Common injection patterns
 	patterns := []string{
@@ -41,18 +42,24 @@ func (g *InjectionGuard) Name() string       { return "injection" }
 func (g *InjectionGuard) Phase() guard.Phase { return guard.PhaseInput }
 
 func (g *InjectionGuard) Check(_ context.Context, input *guard.CheckInput) (*guard.CheckResult, error) {
-	for _, msg := range input.Messages {
-		// Only check user messages for injection
-		if msg.Role != "user" {
-			continue
-		}
-		content, ok := msg.Content.(string)
-		if !ok {
+	for _, seg := range input.Segments() {
+		// Only check text supplied by users or returned by tools, which can
+		// carry indirect injections from fetched documents.
+		if seg.Role != "user" && seg.Kind != guard.SegmentToolResult {
 			continue
 		}
+		content := seg.Text
 
 		for _, p := range g.patterns {
 			if p.MatchString(content) {
+				if g.action == guard.ActionWarn {
+					return &guard.CheckResult{
+						Passed:  true,
+						Action:  guard.ActionWarn,
+						Reason:  "prompt injection detected but allowed",
+						Details: map[string]any{"pattern": p.String()},
+					}, nil
+				}
 				return &guard.CheckResult{
 					Passed:  false,
 					Blocked: true,
@@ -69,6 +76,13 @@ func (g *InjectionGuard) Check(_ context.Context, input *guard.CheckInput) (*gua
 	return &guard.CheckResult{Passed: true, Action: guard.ActionAllow}, nil
 }
 
+// WithAction sets what happens on a match: guard.ActionBlock (default) or
+// guard.ActionWarn to allow the request and only flag it.
+func (g *InjectionGuard) WithAction(action guard.Action) *InjectionGuard {
+	g.action = action
+	return g
+}
+
 // AddPattern adds a custom injection detection regex pattern.
 func (g *InjectionGuard) AddPattern(pattern string) {
 	g.patterns = append(g.patterns, regexp.MustCompile(pattern))
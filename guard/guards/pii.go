@@ -6,7 +6,6 @@ import (
 	"regexp"
 
 	"github.com/xraph/nexus/guard"
-	"github.com/xraph/nexus/provider"
 )
 
 // PIIGuard detects and redacts personally identifiable information.
@@ -49,13 +48,9 @@ func (g *PIIGuard) Check(_ context.Context, input *guard.CheckInput) (*guard.Che
 	detected := false
 	var findings []string
 
-	for _, msg := range input.Messages {
-		content, ok := msg.Content.(string)
-		if !ok {
-			continue
-		}
+	for _, seg := range input.Segments() {
 		for _, p := range g.patterns {
-			if p.regex.MatchString(content) {
+			if p.regex.MatchString(seg.Text) {
 				detected = true
 				findings = append(findings, p.name)
 			}
@@ -77,24 +72,20 @@ func (g *PIIGuard) Check(_ context.Context, input *guard.CheckInput) (*guard.Che
 		}, nil
 
 	case guard.ActionRedact:
-		modified := make([]provider.Message, len(input.Messages))
-		copy(modified, input.Messages)
-		for i := range modified {
-			content, ok := modified[i].Content.(string)
-			if !ok {
-				continue
-			}
+		modified, system, _ := guard.RewriteText(input, func(seg guard.TextSegment) string {
+			content := seg.Text
 			for _, p := range g.patterns {
 				content = p.regex.ReplaceAllString(content, p.replacement)
 			}
-			modified[i].Content = content
-		}
+			return content
+		})
 		return &guard.CheckResult{
 			Passed:   true,
 			Action:   guard.ActionRedact,
 			Reason:   "PII redacted",
 			Modified: true,
 			Messages: modified,
+			System:   &system,
 			Details:  map[string]any{"types": findings},
 		}, nil
 
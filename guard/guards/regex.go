@@ -5,7 +5,6 @@ import (
 	"regexp"
 
 	"github.com/xraph/nexus/guard"
-	"github.com/xraph/nexus/provider"
 )
 
 // RegexGuard applies custom regex rules to content.
@@ -59,47 +58,49 @@ func (g *RegexGuard) Phase() guard.Phase { return g.phase }
 
 func (g *RegexGuard) Check(_ context.Context, input *guard.CheckInput) (*guard.CheckResult, error) {
 	var modified bool
-	messages := input.Messages
+	current := input
 
 	for _, rule := range g.rules {
-		for i, msg := range messages {
-			content, ok := msg.Content.(string)
-			if !ok {
-				continue
+		matched := false
+		for _, seg := range current.Segments() {
+			if rule.Pattern.MatchString(seg.Text) {
+				matched = true
+				break
 			}
+		}
+		if !matched {
+			continue
+		}
 
-			if !rule.Pattern.MatchString(content) {
-				continue
-			}
-
-			switch rule.Action {
-			case guard.ActionBlock:
-				return &guard.CheckResult{
-					Passed:  false,
-					Blocked: true,
-					Action:  guard.ActionBlock,
-					Reason:  rule.Reason,
-					Details: map[string]any{"rule": rule.Name},
-				}, nil
-
-			case guard.ActionRedact:
-				if !modified {
-					// Copy on first modification
-					messages = make([]provider.Message, len(input.Messages))
-					copy(messages, input.Messages)
-					modified = true
-				}
-				messages[i].Content = rule.Pattern.ReplaceAllString(content, rule.Replacement)
-
-			case guard.ActionWarn:
-				// Allow but flag
-				return &guard.CheckResult{
-					Passed:  true,
-					Action:  guard.ActionWarn,
-					Reason:  rule.Reason,
-					Details: map[string]any{"rule": rule.Name},
-				}, nil
-			}
+		switch rule.Action {
+		case guard.ActionBlock:
+			return &guard.CheckResult{
+				Passed:  false,
+				Blocked: true,
+				Action:  guard.ActionBlock,
+				Reason:  rule.Reason,
+				Details: map[string]any{"rule": rule.Name},
+			}, nil
+
+		case guard.ActionRedact:
+			// RewriteText copies, so the caller's input is never modified.
+			messages, system, _ := guard.RewriteText(current, func(seg guard.TextSegment) string {
+				return rule.Pattern.ReplaceAllString(seg.Text, rule.Replacement)
+			})
+			next := *current
+			next.Messages = messages
+			next.System = system
+			current = &next
+			modified = true
+
+		case guard.ActionWarn:
+			// Allow but flag
+			return &guard.CheckResult{
+				Passed:  true,
+				Action:  guard.ActionWarn,
+				Reason:  rule.Reason,
+				Details: map[string]any{"rule": rule.Name},
+			}, nil
 		}
 	}
 
@@ -108,7 +109,8 @@ func (g *RegexGuard) Check(_ context.Context, input *guard.CheckInput) (*guard.C
 			Passed:   true,
 			Action:   guard.ActionRedact,
 			Modified: true,
-			Messages: messages,
+			Messages: current.Messages,
+			System:   &current.System,
 		}, nil
 	}
 
@@ -0,0 +1,82 @@
+package guard
+
+import "sync"
+
+// PolicyRegistry holds named guardrail policies. A policy is a set of
+// guards — each carrying its own action and phase — applied together, so
+// tenants with different compliance needs can run different guards.
+type PolicyRegistry struct {
+	mu          sync.RWMutex
+	policies    map[string]Service
+	defaultName string
+}
+
+// NewPolicyRegistry creates an empty policy registry.
+func NewPolicyRegistry() *PolicyRegistry {
+	return &PolicyRegistry{policies: make(map[string]Service)}
+}
+
+// Register adds guards to the named policy, creating it if needed.
+func (r *PolicyRegistry) Register(name string, guards ...Guard) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	svc, ok := r.policies[name]
+	if !ok {
+		svc = NewService()
+		r.policies[name] = svc
+	}
+	for _, g := range guards {
+		svc.Register(g)
+	}
+}
+
+// SetDefault names the policy used when a tenant has none or names an
+// unknown policy.
+func (r *PolicyRegistry) SetDefault(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultName = name
+}
+
+// Get returns the named policy.
+func (r *PolicyRegistry) Get(name string) (Service, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	svc, ok := r.policies[name]
+	return svc, ok
+}
+
+// Resolve returns the named policy, falling back to the default policy.
+// It returns nil when neither exists.
+func (r *PolicyRegistry) Resolve(name string) Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if svc, ok := r.policies[name]; ok && name != "" {
+		return svc
+	}
+	return r.policies[r.defaultName]
+}
+
+// Names returns the registered policy names.
+func (r *PolicyRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.policies))
+	for name := range r.policies {
+		names = append(names, name)
+	}
+	return names
+}
+
+// WithPhase returns g overridden to run in phase, e.g. a PII guard that a
+// policy only applies to model output.
+func WithPhase(g Guard, phase Phase) Guard {
+	return &phasedGuard{Guard: g, phase: phase}
+}
+
+type phasedGuard struct {
+	Guard
+	phase Phase
+}
+
+func (g *phasedGuard) Phase() Phase { return g.phase }
@@ -53,13 +53,8 @@ func (a *Adapter) Phase() guard.Phase { return guard.PhaseBoth }
 
 func (a *Adapter) Check(ctx context.Context, input *guard.CheckInput) (*guard.CheckResult, error) {
 	// Extract text content from messages
-	for _, msg := range input.Messages {
-		content, ok := msg.Content.(string)
-		if !ok {
-			continue
-		}
-
-		result, err := a.engine.Check(ctx, content)
+	for _, seg := range input.Segments() {
+		result, err := a.engine.Check(ctx, seg.Text)
 		if err != nil {
 			return nil, err
 		}
@@ -86,10 +81,8 @@ func (a *Adapter) Check(ctx context.Context, input *guard.CheckInput) (*guard.Ch
 // WrapMessages extracts text from provider messages for Shield checking.
 func WrapMessages(messages []provider.Message) string {
 	var text string
-	for _, msg := range messages {
-		if content, ok := msg.Content.(string); ok {
-			text += content + "\n"
-		}
+	for _, seg := range (&guard.CheckInput{Messages: messages}).Segments() {
+		text += seg.Text + "\n"
 	}
 	return text
 }
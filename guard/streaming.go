@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/xraph/nexus/provider"
 )
@@ -12,10 +14,23 @@ import (
 type StreamGuard interface {
 	Guard
 
-	// CheckChunk evaluates a single stream chunk.
+	// CheckChunk evaluates a single stream chunk. Guards that redact
+	// rewrite chunk.Delta.Content in place.
 	CheckChunk(ctx context.Context, chunk *provider.StreamChunk) (*CheckResult, error)
 }
 
+// StreamEndGuard is a StreamGuard too costly to run on every chunk, such
+// as a model-backed judge. GuardedStream skips its CheckChunk and checks
+// the full streamed text once, when the upstream finishes: before replay
+// under StrategyBuffer, before the final held-back text is released under
+// StrategySlidingWindow, and before EOF under StrategyChunkwise.
+// StrategyPassthrough checks nothing.
+type StreamEndGuard interface {
+	StreamGuard
+
+	CheckStreamEnd(ctx context.Context, text string) (*CheckResult, error)
+}
+
 // StreamStrategy controls how guards are applied to streams.
 type StreamStrategy string
 
@@ -28,27 +43,62 @@ const (
 
 	// StrategyChunkwise checks each chunk individually as it arrives.
 	StrategyChunkwise StreamStrategy = "chunkwise"
+
+	// StrategySlidingWindow checks a rolling window of recent text and holds
+	// back its tail, so patterns split across chunk boundaries are caught
+	// without buffering the whole response.
+	StrategySlidingWindow StreamStrategy = "sliding_window"
 )
 
+// DefaultWindowSize is the sliding-window hold-back in bytes. It must be at
+// least as long as the longest pattern a guard needs to see whole.
+const DefaultWindowSize = 128
+
 // GuardedStream wraps a provider.Stream and applies guardrails.
 type GuardedStream struct {
 	inner    provider.Stream
 	guards   []StreamGuard
 	strategy StreamStrategy
 
+	// StreamEndGuards and the text they will check.
+	endGuards  []StreamEndGuard
+	text       strings.Builder
+	endChecked bool
+
 	// For buffer strategy
 	chunks   []*provider.StreamChunk
 	buffered bool
 	idx      int
+
+	// For sliding window strategy
+	window int
+	tail   string // checked text not yet emitted
+	done   bool
 }
 
 // NewGuardedStream creates a new guarded stream wrapper.
 func NewGuardedStream(inner provider.Stream, guards []StreamGuard, strategy StreamStrategy) *GuardedStream {
-	return &GuardedStream{
+	gs := &GuardedStream{
 		inner:    inner,
-		guards:   guards,
 		strategy: strategy,
+		window:   DefaultWindowSize,
+	}
+	for _, g := range guards {
+		if eg, ok := g.(StreamEndGuard); ok {
+			gs.endGuards = append(gs.endGuards, eg)
+			continue
+		}
+		gs.guards = append(gs.guards, g)
 	}
+	return gs
+}
+
+// WithWindowSize sets the hold-back used by StrategySlidingWindow.
+func (gs *GuardedStream) WithWindowSize(n int) *GuardedStream {
+	if n > 0 {
+		gs.window = n
+	}
+	return gs
 }
 
 // Next returns the next chunk, applying the configured guard strategy.
@@ -58,6 +108,8 @@ func (gs *GuardedStream) Next(ctx context.Context) (*provider.StreamChunk, error
 		return gs.nextBuffered(ctx)
 	case StrategyChunkwise:
 		return gs.nextChunkwise(ctx)
+	case StrategySlidingWindow:
+		return gs.nextWindowed(ctx)
 	default: // passthrough
 		return gs.inner.Next(ctx)
 	}
@@ -85,7 +137,8 @@ func (gs *GuardedStream) nextBuffered(ctx context.Context) (*provider.StreamChun
 			if err != nil {
 				return nil, err
 			}
-			gs.chunks = append(gs.chunks, chunk)
+			cp := *chunk // guards may redact in place
+			gs.chunks = append(gs.chunks, &cp)
 		}
 
 		// Run guards on buffered content
@@ -100,6 +153,12 @@ func (gs *GuardedStream) nextBuffered(ctx context.Context) (*provider.StreamChun
 				}
 			}
 		}
+		for _, chunk := range gs.chunks {
+			gs.record(chunk.Delta.Content)
+		}
+		if err := gs.checkEnd(ctx); err != nil {
+			return nil, err
+		}
 		gs.buffered = true
 	}
 
@@ -114,10 +173,24 @@ func (gs *GuardedStream) nextBuffered(ctx context.Context) (*provider.StreamChun
 
 // nextChunkwise checks each chunk as it arrives.
 func (gs *GuardedStream) nextChunkwise(ctx context.Context) (*provider.StreamChunk, error) {
+	if gs.done {
+		return nil, io.EOF
+	}
 	chunk, err := gs.inner.Next(ctx)
+	if errors.Is(err, io.EOF) {
+		gs.done = true
+		if err := gs.checkEnd(ctx); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
 	if err != nil {
 		return nil, err
 	}
+	// Guards may redact in place; never modify the upstream's chunk, which
+	// a cache or coalesced stream may share with other readers.
+	cp := *chunk
+	chunk = &cp
 
 	for _, g := range gs.guards {
 		result, err := g.CheckChunk(ctx, chunk)
@@ -129,10 +202,161 @@ func (gs *GuardedStream) nextChunkwise(ctx context.Context) (*provider.StreamChu
 			return nil, &BlockedError{Guard: g.Name(), Reason: result.Reason}
 		}
 	}
+	gs.record(chunk.Delta.Content)
 
 	return chunk, nil
 }
 
+// nextWindowed runs guards over the held-back tail plus each new delta and
+// emits only the text that has left the window. A match can therefore be
+// redacted even when its first half arrived in an earlier chunk.
+func (gs *GuardedStream) nextWindowed(ctx context.Context) (*provider.StreamChunk, error) {
+	if gs.done {
+		return nil, io.EOF
+	}
+
+	chunk, err := gs.inner.Next(ctx)
+	if errors.Is(err, io.EOF) {
+		gs.done = true
+		gs.record(gs.tail)
+		if err := gs.checkEnd(ctx); err != nil {
+			return nil, err
+		}
+		if gs.tail == "" {
+			return nil, io.EOF
+		}
+		// Flush what is still held back.
+		flush := &provider.StreamChunk{Delta: provider.Delta{Content: gs.tail}}
+		gs.tail = ""
+		return flush, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	text := gs.tail + chunk.Delta.Content
+	if text == "" {
+		return chunk, nil
+	}
+	window := &provider.StreamChunk{Delta: provider.Delta{Content: text}}
+	for _, g := range gs.guards {
+		result, err := g.CheckChunk(ctx, window)
+		if err != nil {
+			return nil, err
+		}
+		if result.Blocked {
+			_ = gs.inner.Close()
+			return nil, &BlockedError{Guard: g.Name(), Reason: result.Reason}
+		}
+	}
+	text = window.Delta.Content
+
+	out := *chunk
+	if chunk.FinishReason != "" || len(chunk.Delta.ToolCalls) > 0 {
+		// Nothing more will extend the text; release it all.
+		gs.record(text)
+		if chunk.FinishReason != "" {
+			if err := gs.checkEnd(ctx); err != nil {
+				_ = gs.inner.Close()
+				return nil, err
+			}
+		}
+		out.Delta.Content = text
+		gs.tail = ""
+		return &out, nil
+	}
+
+	cut := len(text) - gs.window
+	if cut <= 0 {
+		out.Delta.Content = ""
+		gs.tail = text
+		return &out, nil
+	}
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	out.Delta.Content = text[:cut]
+	gs.tail = text[cut:]
+	gs.record(out.Delta.Content)
+	return &out, nil
+}
+
+// record keeps emitted text for the StreamEndGuards.
+func (gs *GuardedStream) record(text string) {
+	if len(gs.endGuards) > 0 {
+		gs.text.WriteString(text)
+	}
+}
+
+// checkEnd runs the StreamEndGuards over the full streamed text, once.
+func (gs *GuardedStream) checkEnd(ctx context.Context) error {
+	if gs.endChecked {
+		return nil
+	}
+	gs.endChecked = true
+	for _, g := range gs.endGuards {
+		result, err := g.CheckStreamEnd(ctx, gs.text.String())
+		if err != nil {
+			return err
+		}
+		if result.Blocked {
+			return &BlockedError{Guard: g.Name(), Reason: result.Reason}
+		}
+	}
+	return nil
+}
+
+// OutputStreamGuards returns the output-phase guards registered on svc as
+// StreamGuards. Guards that already implement StreamGuard are used as-is;
+// others are adapted to check each chunk's text as an assistant message.
+func OutputStreamGuards(svc Service) []StreamGuard {
+	if svc == nil {
+		return nil
+	}
+	var out []StreamGuard
+	for _, g := range svc.List() {
+		if g.Phase() != PhaseOutput && g.Phase() != PhaseBoth {
+			continue
+		}
+		if sg, ok := g.(StreamGuard); ok {
+			out = append(out, sg)
+			continue
+		}
+		out = append(out, &chunkGuard{Guard: g})
+	}
+	return out
+}
+
+// chunkGuard adapts a message-level Guard to StreamGuard.
+type chunkGuard struct {
+	Guard
+}
+
+func (g *chunkGuard) CheckChunk(ctx context.Context, chunk *provider.StreamChunk) (*CheckResult, error) {
+	if chunk.Delta.Content == "" && len(chunk.Delta.ToolCalls) == 0 {
+		return &CheckResult{Passed: true, Action: ActionAllow}, nil
+	}
+	result, err := g.Check(ctx, &CheckInput{
+		Messages: []provider.Message{{
+			Role:      "assistant",
+			Content:   chunk.Delta.Content,
+			ToolCalls: chunk.Delta.ToolCalls,
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Modified && len(result.Messages) > 0 {
+		if text, ok := result.Messages[0].Content.(string); ok {
+			chunk.Delta.Content = text
+		}
+		if len(result.Messages[0].ToolCalls) == len(chunk.Delta.ToolCalls) {
+			chunk.Delta.ToolCalls = result.Messages[0].ToolCalls
+		}
+	}
+	return result, nil
+}
+
 // BlockedError is returned when a stream guard blocks content.
 type BlockedError struct {
 	Guard  string
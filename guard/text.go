@@ -0,0 +1,192 @@
+package guard
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// SegmentKind identifies where a TextSegment came from.
+type SegmentKind string
+
+const (
+	SegmentSystem        SegmentKind = "system"         // CheckInput.System
+	SegmentContent       SegmentKind = "content"        // string message content
+	SegmentPart          SegmentKind = "part"           // text content part of a non-tool message
+	SegmentToolArguments SegmentKind = "tool_arguments" // string values in tool-call arguments
+	SegmentToolResult    SegmentKind = "tool_result"    // content of a tool-role message
+)
+
+// TextSegment is one inspectable piece of text in a request or response.
+// Guards should inspect Segments rather than type-asserting message content,
+// so text sent as content parts, system prompts, tool-call arguments or tool
+// results cannot bypass them.
+type TextSegment struct {
+	Kind SegmentKind
+	Role string // role of the owning message; "system" for CheckInput.System
+	Text string
+}
+
+// Segments returns every textual surface of the input: the system prompt,
+// string and content-part message text, tool results and the string values
+// of tool-call arguments.
+func (in *CheckInput) Segments() []TextSegment {
+	var segs []TextSegment
+	collect := func(s TextSegment) string {
+		if s.Text != "" {
+			segs = append(segs, s)
+		}
+		return s.Text
+	}
+	walkText(in.System, in.Messages, collect)
+	return segs
+}
+
+// Text returns every textual surface of the input joined by newlines.
+func (in *CheckInput) Text() string {
+	segs := in.Segments()
+	texts := make([]string, len(segs))
+	for i, s := range segs {
+		texts[i] = s.Text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// RewriteText applies fn to every textual surface of the input and returns
+// copies of the messages and system prompt with the results written back
+// into the field, content part or tool argument they came from. The input
+// is not modified. changed reports whether fn altered anything.
+func RewriteText(in *CheckInput, fn func(seg TextSegment) string) (messages []provider.Message, system string, changed bool) {
+	messages = make([]provider.Message, len(in.Messages))
+	copy(messages, in.Messages)
+	system = in.System
+	rewrite := func(s TextSegment) string {
+		out := fn(s)
+		if out != s.Text {
+			changed = true
+		}
+		return out
+	}
+
+	if system != "" {
+		system = rewrite(TextSegment{Kind: SegmentSystem, Role: "system", Text: system})
+	}
+	for i := range messages {
+		rewriteMessage(&messages[i], rewrite)
+	}
+	return messages, system, changed
+}
+
+// walkText calls fn for every textual surface without rewriting anything.
+func walkText(system string, messages []provider.Message, fn func(TextSegment) string) {
+	if system != "" {
+		fn(TextSegment{Kind: SegmentSystem, Role: "system", Text: system})
+	}
+	for i := range messages {
+		m := messages[i] // rewriteMessage works on a copy here
+		rewriteMessage(&m, fn)
+	}
+}
+
+// rewriteMessage replaces every textual surface of m with fn's result.
+// Slices and maps are copied before being written so the caller's message
+// is never aliased.
+func rewriteMessage(m *provider.Message, fn func(TextSegment) string) {
+	kind, partKind := SegmentContent, SegmentPart
+	if m.Role == "tool" {
+		kind, partKind = SegmentToolResult, SegmentToolResult
+	}
+
+	switch c := m.Content.(type) {
+	case string:
+		if c != "" {
+			m.Content = fn(TextSegment{Kind: kind, Role: m.Role, Text: c})
+		}
+	case []provider.ContentPart:
+		parts := make([]provider.ContentPart, len(c))
+		for j, p := range c {
+			if p.Text != "" {
+				p.Text = fn(TextSegment{Kind: partKind, Role: m.Role, Text: p.Text})
+			}
+			parts[j] = p
+		}
+		m.Content = parts
+	case []any:
+		parts := make([]any, len(c))
+		for j, p := range c {
+			if pm, ok := p.(map[string]any); ok {
+				if text, ok := pm["text"].(string); ok && text != "" {
+					cp := make(map[string]any, len(pm))
+					for k, v := range pm {
+						cp[k] = v
+					}
+					cp["text"] = fn(TextSegment{Kind: partKind, Role: m.Role, Text: text})
+					p = cp
+				}
+			}
+			parts[j] = p
+		}
+		m.Content = parts
+	}
+
+	if len(m.ToolCalls) > 0 {
+		calls := make([]provider.ToolCall, len(m.ToolCalls))
+		for j, tc := range m.ToolCalls {
+			tc.Function.Arguments = rewriteArguments(tc.Function.Arguments, m.Role, fn)
+			calls[j] = tc
+		}
+		m.ToolCalls = calls
+	}
+}
+
+// rewriteArguments applies fn to each string value in a JSON arguments
+// document, re-encoding it only when something changed. Arguments that are
+// not valid JSON are treated as a single string.
+func rewriteArguments(args, role string, fn func(TextSegment) string) string {
+	if args == "" {
+		return args
+	}
+	var doc any
+	if err := json.Unmarshal([]byte(args), &doc); err != nil {
+		return fn(TextSegment{Kind: SegmentToolArguments, Role: role, Text: args})
+	}
+	changed := false
+	doc = rewriteJSONStrings(doc, func(s string) string {
+		out := fn(TextSegment{Kind: SegmentToolArguments, Role: role, Text: s})
+		if out != s {
+			changed = true
+		}
+		return out
+	})
+	if !changed {
+		return args
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return args
+	}
+	return string(b)
+}
+
+func rewriteJSONStrings(v any, fn func(string) string) any {
+	switch t := v.(type) {
+	case string:
+		if t == "" {
+			return t
+		}
+		return fn(t)
+	case map[string]any:
+		for k, val := range t {
+			t[k] = rewriteJSONStrings(val, fn)
+		}
+		return t
+	case []any:
+		for i, val := range t {
+			t[i] = rewriteJSONStrings(val, fn)
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,170 @@
+package httpstream
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// audioChunkSize is the read size for synthesized audio. Small enough to
+// start playback quickly, large enough to keep per-event overhead low.
+const audioChunkSize = 16 * 1024
+
+// AudioStream adapts a synthesized audio body into a provider.Stream of
+// EventAudio chunks, so speech output can run through Run with any
+// encoder.
+type AudioStream struct {
+	body   io.ReadCloser
+	model  string
+	format string
+	buf    []byte
+}
+
+// NewAudioStream wraps body, which AudioStream closes.
+func NewAudioStream(body io.ReadCloser, model, format string) *AudioStream {
+	return &AudioStream{
+		body:   body,
+		model:  model,
+		format: format,
+		buf:    make([]byte, audioChunkSize),
+	}
+}
+
+// Next returns the next audio chunk, or io.EOF once the body is drained.
+func (s *AudioStream) Next(ctx context.Context) (*provider.StreamChunk, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	n, err := io.ReadFull(s.body, s.buf)
+	if n == 0 {
+		if err == nil || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = io.EOF
+		}
+		return nil, err
+	}
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	data := make([]byte, n)
+	copy(data, s.buf[:n])
+	return &provider.StreamChunk{
+		Model: s.model,
+		Kind:  provider.EventAudio,
+		Delta: provider.Delta{Audio: &provider.AudioChunk{Format: s.format, Data: data}},
+	}, nil
+}
+
+// Close closes the audio body.
+func (s *AudioStream) Close() error { return s.body.Close() }
+
+// Usage returns nil; speech is billed on input characters.
+func (s *AudioStream) Usage() *provider.Usage { return nil }
+
+// WriteAudio copies a raw audio body to w, flushing after every read so
+// clients can start playback before synthesis finishes.
+func WriteAudio(w http.ResponseWriter, body io.Reader, contentType string) error {
+	flusher, _ := w.(http.Flusher) //nolint:errcheck // optional; absence degrades to buffered writes
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	buf := make([]byte, audioChunkSize)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			flush(w, flusher)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// SSESpeechEncoder emits audio events in the shape of OpenAI's
+// /v1/audio/speech stream_format "sse":
+//
+//	data: {"type":"speech.audio.delta","audio":"<base64>"}
+//	data: {"type":"speech.audio.done"}
+//
+// Non-audio events are dropped.
+type SSESpeechEncoder struct{}
+
+// NewSSESpeechEncoder returns the OpenAI-compatible speech SSE encoder.
+func NewSSESpeechEncoder() *SSESpeechEncoder { return &SSESpeechEncoder{} }
+
+func (e *SSESpeechEncoder) ContentType() string { return "text/event-stream" }
+
+func (e *SSESpeechEncoder) WriteHeaders(w http.ResponseWriter) {
+	h := w.Header()
+	h.Set("Content-Type", e.ContentType())
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+}
+
+type speechEvent struct {
+	Type  string `json:"type"`
+	Audio string `json:"audio,omitempty"`
+}
+
+func (e *SSESpeechEncoder) EncodeEvent(w io.Writer, ev *StreamEvent) error {
+	if ev == nil {
+		return nil
+	}
+	if ev.Type == EventTypeError {
+		return e.EncodeError(w, ev.Err)
+	}
+	if ev.Type != EventTypeAudio || ev.Audio == nil {
+		return nil
+	}
+	return writeSpeechEvent(w, speechEvent{
+		Type:  "speech.audio.delta",
+		Audio: base64.StdEncoding.EncodeToString(ev.Audio.Data),
+	})
+}
+
+func (e *SSESpeechEncoder) EncodeError(w io.Writer, werr *WireError) error {
+	if werr == nil {
+		return nil
+	}
+	data, err := json.Marshal(struct {
+		Type  string     `json:"type"`
+		Error *WireError `json:"error"`
+	}{Type: "error", Error: werr})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
+
+func (e *SSESpeechEncoder) Heartbeat(w io.Writer) error {
+	_, err := fmt.Fprintf(w, ": ping\n\n")
+	return err
+}
+
+func (e *SSESpeechEncoder) End(w io.Writer) error {
+	return writeSpeechEvent(w, speechEvent{Type: "speech.audio.done"})
+}
+
+func writeSpeechEvent(w io.Writer, ev speechEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
@@ -42,6 +42,23 @@ type StreamEncoder interface {
 	End(w io.Writer) error
 }
 
+// StreamScoped is implemented by encoders that keep per-stream state,
+// such as the index of the open content block. NewStream returns a fresh
+// encoder for one stream, so a single registered instance can serve
+// concurrent streams; Negotiate and ForStream call it.
+type StreamScoped interface {
+	NewStream() StreamEncoder
+}
+
+// ForStream returns the encoder to use for one stream: enc itself, or a
+// fresh copy when enc is StreamScoped.
+func ForStream(enc StreamEncoder) StreamEncoder {
+	if s, ok := enc.(StreamScoped); ok {
+		return s.NewStream()
+	}
+	return enc
+}
+
 // Registry maps content types to encoders, plus aliases for convenience
 // (e.g. "sse" → "text/event-stream"). Safe for concurrent registration
 // during construction; treat as read-only after first request handled.
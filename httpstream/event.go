@@ -37,8 +37,11 @@ const (
 //
 // Encoders should populate only the fields relevant to Type. Unset fields
 // are omitted on the wire. ID/Model/RequestID are echoed when known.
+// Seq is the per-stream sequence number assigned by Run; SSE encoders emit
+// it as the event id so clients can reconnect with Last-Event-ID.
 type StreamEvent struct {
 	Type         EventType            `json:"type"`
+	Seq          uint64               `json:"seq,omitempty"`
 	ID           string               `json:"id,omitempty"`
 	Model        string               `json:"model,omitempty"`
 	RequestID    string               `json:"request_id,omitempty"`
@@ -15,11 +15,20 @@ import (
 //
 // The matcher is case-insensitive and accepts both content-type values
 // ("application/x-ndjson") and aliases ("ndjson", "sse"). Unknown values
-// fall through to the next step.
+// fall through to the next step. A StreamScoped encoder is returned as a
+// fresh per-stream copy.
 func Negotiate(r *http.Request, reg *Registry) StreamEncoder {
 	if reg == nil {
 		return nil
 	}
+	enc := negotiate(r, reg)
+	if enc == nil {
+		return nil
+	}
+	return ForStream(enc)
+}
+
+func negotiate(r *http.Request, reg *Registry) StreamEncoder {
 	if r != nil {
 		if q := r.URL.Query().Get("stream_format"); q != "" {
 			if enc := reg.Lookup(q); enc != nil {
@@ -8,9 +8,16 @@ package httpstream
 //   - application/vnd.nexus.events+sse — SSE with named events,
 //     surfaces every kind (reasoning, tool_call, …) for nexus-aware clients.
 //   - application/x-ndjson — line-delimited JSON of native StreamEvent.
+//   - application/vnd.anthropic.events+sse — SSE in the Anthropic Messages
+//     event vocabulary (message_start, content_block_delta, …); the wire
+//     Content-Type is text/event-stream.
+//   - application/vnd.gemini.events+sse — SSE of Gemini
+//     GenerateContentResponse chunks, as streamGenerateContent?alt=sse
+//     sends them; the wire Content-Type is text/event-stream.
 //
 // Aliases registered: "sse"/"openai" → OpenAI SSE; "nexus"/"nexus-sse" →
-// native SSE; "ndjson"/"jsonl" → NDJSON.
+// native SSE; "ndjson"/"jsonl" → NDJSON; "anthropic" → Anthropic SSE;
+// "gemini" → Gemini SSE.
 func DefaultRegistry() *Registry {
 	r := NewRegistry()
 
@@ -30,5 +37,13 @@ func DefaultRegistry() *Registry {
 	r.RegisterAlias("ndjson", "application/x-ndjson")
 	r.RegisterAlias("jsonl", "application/x-ndjson")
 
+	anthropic := NewSSEAnthropicEncoder()
+	r.Register("application/vnd.anthropic.events+sse", anthropic)
+	r.RegisterAlias("anthropic", "application/vnd.anthropic.events+sse")
+
+	gemini := NewSSEGeminiEncoder()
+	r.Register("application/vnd.gemini.events+sse", gemini)
+	r.RegisterAlias("gemini", "application/vnd.gemini.events+sse")
+
 	return r
 }
@@ -0,0 +1,372 @@
+package httpstream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xraph/nexus/cache"
+	"github.com/xraph/nexus/provider"
+)
+
+// ErrResumeWindow is returned by a resumed stream when the frames the client
+// is missing have already been trimmed from the buffer.
+var ErrResumeWindow = errors.New("httpstream: resume point is outside the buffered window")
+
+// errResumeIncomplete ends a replay served from the persisted copy of a
+// stream that had not finished when it was last written — typically one
+// still being drained on another replica.
+var errResumeIncomplete = errors.New("httpstream: resumed stream is incomplete")
+
+// ResumeStore buffers the frames of in-flight streams by request ID so a
+// client whose connection drops can reconnect with Last-Event-ID and pick up
+// where it left off instead of paying for the answer twice.
+//
+// Tracked upstreams keep draining into the buffer after the client goes
+// away, bounded by the drain timeout. Finished streams stay resumable for
+// the TTL. With WithResumeCache the buffer is also mirrored into a
+// cache.StreamCache (e.g. stores.RedisStreamCache) so a reconnect landing on
+// another replica can still replay what was produced.
+type ResumeStore struct {
+	ttl       time.Duration
+	drain     time.Duration
+	maxFrames int
+	persist   cache.StreamCache
+
+	mu      sync.Mutex
+	streams map[string]*resumable
+}
+
+// ResumeOption configures a ResumeStore.
+type ResumeOption func(*ResumeStore)
+
+// WithResumeTTL sets how long a finished stream stays resumable. Default 2m.
+func WithResumeTTL(d time.Duration) ResumeOption {
+	return func(s *ResumeStore) { s.ttl = d }
+}
+
+// WithResumeDrainTimeout caps how long an upstream keeps draining after
+// the client disconnects. Default 10m.
+func WithResumeDrainTimeout(d time.Duration) ResumeOption {
+	return func(s *ResumeStore) { s.drain = d }
+}
+
+// WithResumeMaxFrames caps the frames buffered per stream. Older frames are
+// trimmed once the cap is reached; a client resuming from before the window
+// gets ErrResumeWindow. Default 4096.
+func WithResumeMaxFrames(n int) ResumeOption {
+	return func(s *ResumeStore) { s.maxFrames = n }
+}
+
+// WithResumeCache mirrors buffered frames into sc so streams can be replayed
+// from any replica sharing it. Live continuation is only possible on the
+// replica draining the upstream.
+func WithResumeCache(sc cache.StreamCache) ResumeOption {
+	return func(s *ResumeStore) { s.persist = sc }
+}
+
+// NewResumeStore creates an in-memory resume buffer.
+func NewResumeStore(opts ...ResumeOption) *ResumeStore {
+	s := &ResumeStore{
+		ttl:       2 * time.Minute,
+		drain:     10 * time.Minute,
+		maxFrames: 4096,
+		streams:   make(map[string]*resumable),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// resumable is the shared buffer of one tracked stream.
+type resumable struct {
+	key string
+
+	mu        sync.Mutex
+	frames    []*provider.StreamChunk
+	base      uint64 // number of frames trimmed from the front
+	done      bool
+	err       error
+	usage     *provider.Usage
+	notify    chan struct{} // closed and replaced on every change
+	expiresAt time.Time
+}
+
+// persistEvery is how many frames accumulate between writes to the
+// persistent cache while a stream is still running.
+const persistEvery = 32
+
+// Track starts draining upstream into the buffer for requestID and returns a
+// stream that delivers it from the first frame. Closing the returned stream
+// detaches the reader only; the upstream keeps draining until it ends, ctx
+// is canceled or the drain timeout passes, and release is then called.
+//
+// ctx must not be tied to the client connection — use
+// context.WithoutCancel on the request context. tenant scopes the stream:
+// only a Resume with the same tenant can attach to it.
+func (s *ResumeStore) Track(ctx context.Context, requestID, tenant string, upstream provider.Stream, release func()) provider.Stream {
+	s.sweep()
+
+	e := &resumable{
+		key:    resumeKey(tenant, requestID),
+		notify: make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.streams[e.key] = e
+	s.mu.Unlock()
+
+	go s.pump(ctx, e, upstream, release)
+	return &resumeReader{e: e, next: 1}
+}
+
+// Resume attaches to the stream for requestID, delivering every frame after
+// sequence number after and then following it live. ok is false when the
+// stream is unknown, expired, or belongs to another tenant.
+func (s *ResumeStore) Resume(ctx context.Context, requestID, tenant string, after uint64) (provider.Stream, bool) {
+	s.sweep()
+
+	key := resumeKey(tenant, requestID)
+	s.mu.Lock()
+	e, ok := s.streams[key]
+	s.mu.Unlock()
+	if ok {
+		return &resumeReader{e: e, next: after + 1}, true
+	}
+
+	if s.persist == nil {
+		return nil, false
+	}
+	frames, err := s.persist.GetStream(ctx, key)
+	if err != nil || len(frames) == 0 {
+		return nil, false
+	}
+	e = &resumable{key: key, done: true, notify: make(chan struct{})}
+	for _, f := range frames {
+		if f.Chunk == nil {
+			// Trailing nil frame marks a copy written after the stream
+			// finished cleanly.
+			e.err = nil
+			break
+		}
+		e.frames = append(e.frames, f.Chunk)
+		e.err = errResumeIncomplete
+	}
+	return &resumeReader{e: e, next: after + 1}, true
+}
+
+// pump drains upstream into e until it ends.
+func (s *ResumeStore) pump(ctx context.Context, e *resumable, upstream provider.Stream, release func()) {
+	ctx, cancel := context.WithTimeout(ctx, s.drain)
+	defer func() {
+		cancel()
+		_ = upstream.Close()
+		if release != nil {
+			release()
+		}
+	}()
+
+	for {
+		c, err := upstream.Next(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			s.finish(ctx, e, upstream.Usage(), err)
+			return
+		}
+		if c == nil {
+			continue
+		}
+		if s.append(e, c) && s.persist != nil {
+			s.save(ctx, e)
+		}
+	}
+}
+
+// append adds c to e's buffer, trimming the oldest frame once the cap is
+// reached. It reports whether the buffer is due to be persisted.
+func (s *ResumeStore) append(e *resumable, c *provider.StreamChunk) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.frames = append(e.frames, c)
+	if s.maxFrames > 0 && len(e.frames) > s.maxFrames {
+		e.frames[0] = nil
+		e.frames = e.frames[1:]
+		e.base++
+	}
+	if c.Usage != nil {
+		e.usage = c.Usage
+	}
+	e.wake()
+	return e.base == 0 && len(e.frames)%persistEvery == 0
+}
+
+// finish marks e complete and starts its TTL.
+func (s *ResumeStore) finish(ctx context.Context, e *resumable, usage *provider.Usage, err error) {
+	e.mu.Lock()
+	e.done = true
+	e.err = err
+	if usage != nil {
+		e.usage = usage
+	}
+	e.expiresAt = time.Now().Add(s.ttl)
+	e.wake()
+	e.mu.Unlock()
+
+	if s.persist != nil {
+		s.save(context.WithoutCancel(ctx), e)
+	}
+}
+
+// save writes e's buffer to the persistent cache. A trimmed buffer is no
+// longer complete from the first frame, so the last full copy is kept.
+func (s *ResumeStore) save(ctx context.Context, e *resumable) {
+	e.mu.Lock()
+	if e.base > 0 {
+		e.mu.Unlock()
+		return
+	}
+	frames := make([]cache.StreamFrame, 0, len(e.frames)+1)
+	for _, c := range e.frames {
+		frames = append(frames, cache.StreamFrame{Chunk: c})
+	}
+	if e.done && e.err == nil {
+		frames = append(frames, cache.StreamFrame{})
+	}
+	e.mu.Unlock()
+
+	_ = s.persist.SetStream(ctx, e.key, frames, s.drain+s.ttl) //nolint:errcheck // best-effort mirror; memory stays authoritative
+}
+
+// sweep drops finished streams whose TTL has passed.
+func (s *ResumeStore) sweep() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, e := range s.streams {
+		e.mu.Lock()
+		expired := e.done && now.After(e.expiresAt)
+		e.mu.Unlock()
+		if expired {
+			delete(s.streams, k)
+		}
+	}
+}
+
+// wake notifies readers waiting on e. Callers hold e.mu.
+func (e *resumable) wake() {
+	close(e.notify)
+	e.notify = make(chan struct{})
+}
+
+// resumeReader is one client's cursor over a resumable buffer.
+type resumeReader struct {
+	e    *resumable
+	next uint64 // sequence number of the next frame to deliver
+}
+
+func (r *resumeReader) Next(ctx context.Context) (*provider.StreamChunk, error) {
+	for {
+		r.e.mu.Lock()
+		if r.next <= r.e.base {
+			r.e.mu.Unlock()
+			return nil, ErrResumeWindow
+		}
+		if idx := r.next - r.e.base - 1; idx < uint64(len(r.e.frames)) {
+			c := r.e.frames[idx]
+			r.next++
+			r.e.mu.Unlock()
+			return c, nil
+		}
+		if r.e.done {
+			err := r.e.err
+			r.e.mu.Unlock()
+			if err == nil {
+				err = io.EOF
+			}
+			return nil, err
+		}
+		wait := r.e.notify
+		r.e.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Close detaches the reader; the upstream keeps draining.
+func (r *resumeReader) Close() error { return nil }
+
+func (r *resumeReader) Usage() *provider.Usage {
+	r.e.mu.Lock()
+	defer r.e.mu.Unlock()
+	return r.e.usage
+}
+
+func resumeKey(tenant, requestID string) string {
+	return "resume:" + tenant + ":" + requestID
+}
+
+// ResumePoint reports which stream a reconnecting client wants to resume:
+// the request ID and the sequence number of the last event it received.
+//
+// It reads the Last-Event-ID header, in the "<request_id>:<seq>" form the
+// SSE encoders write, and the `?resume=<request_id>` query. A bare resume
+// query replays from the first event unless Last-Event-ID (or
+// `?last_event_id=`) carries a sequence number for the same request.
+func ResumePoint(r *http.Request) (requestID string, after uint64, ok bool) {
+	last := r.Header.Get("Last-Event-ID")
+	if last == "" {
+		last = r.URL.Query().Get("last_event_id")
+	}
+	lastID, lastSeq := splitEventID(last)
+
+	if q := r.URL.Query().Get("resume"); q != "" {
+		if lastID == "" || lastID == q {
+			return q, lastSeq, true
+		}
+		return q, 0, true
+	}
+	if lastID == "" {
+		return "", 0, false
+	}
+	return lastID, lastSeq, true
+}
+
+// splitEventID parses "<request_id>:<seq>" or a bare "<seq>".
+func splitEventID(s string) (requestID string, seq uint64) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", 0
+	}
+	idPart, seqPart := "", s
+	if i := strings.LastIndexByte(s, ':'); i >= 0 {
+		idPart, seqPart = s[:i], s[i+1:]
+	}
+	n, err := strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return "", 0
+	}
+	return idPart, n
+}
+
+// eventID renders the SSE id for ev, or "" when it carries no sequence.
+func eventID(ev *StreamEvent) string {
+	if ev.Seq == 0 {
+		return ""
+	}
+	seq := strconv.FormatUint(ev.Seq, 10)
+	if ev.RequestID == "" {
+		return seq
+	}
+	return ev.RequestID + ":" + seq
+}
@@ -0,0 +1,244 @@
+package httpstream_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xraph/nexus/cache/stores"
+	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/testutil"
+)
+
+// feedStream yields chunks pushed onto its channel and ends when the
+// channel closes, so tests control when the upstream produces.
+type feedStream struct {
+	ch     chan *provider.StreamChunk
+	closed chan struct{}
+}
+
+func newFeedStream() *feedStream {
+	return &feedStream{ch: make(chan *provider.StreamChunk, 16), closed: make(chan struct{})}
+}
+
+func (s *feedStream) Next(ctx context.Context) (*provider.StreamChunk, error) {
+	select {
+	case c, ok := <-s.ch:
+		if !ok {
+			return nil, io.EOF
+		}
+		return c, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *feedStream) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func (s *feedStream) Usage() *provider.Usage { return nil }
+
+func textChunk(s string) *provider.StreamChunk {
+	return &provider.StreamChunk{ID: "c", Model: "m", Delta: provider.Delta{Content: s}}
+}
+
+func readAll(t *testing.T, s provider.Stream) (string, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var out strings.Builder
+	for {
+		c, err := s.Next(ctx)
+		if err != nil {
+			return out.String(), err
+		}
+		out.WriteString(c.Delta.Content)
+	}
+}
+
+func TestRun_SSEEmitsSequentialEventIDs(t *testing.T) {
+	t.Parallel()
+	stream := testutil.NewFakeStream([]*provider.StreamChunk{textChunk("a"), textChunk("b")}, nil)
+	rec := httptest.NewRecorder()
+
+	httpstream.Run(context.Background(), rec, stream, httpstream.NewSSEOpenAIEncoder(), httpstream.RunOptions{
+		RequestID:   "req_1",
+		LastEventID: 4,
+	})
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "id: req_1:5\ndata: ") || !strings.Contains(body, "id: req_1:6\ndata: ") {
+		t.Fatalf("event ids missing or not continuing after LastEventID: %q", body)
+	}
+}
+
+func TestSSENativeEncoder_WritesEventID(t *testing.T) {
+	t.Parallel()
+	rec := httptest.NewRecorder()
+	ev := &httpstream.StreamEvent{Type: httpstream.EventTypeDelta, Seq: 3, Delta: &provider.Delta{Content: "x"}}
+	if err := httpstream.NewSSENativeEncoder().EncodeEvent(rec.Body, ev); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if !strings.HasPrefix(rec.Body.String(), "id: 3\nevent: delta\n") {
+		t.Fatalf("unexpected frame: %q", rec.Body.String())
+	}
+}
+
+func TestResumeStore_DrainsAfterDisconnectAndReplays(t *testing.T) {
+	t.Parallel()
+	store := httpstream.NewResumeStore()
+	up := newFeedStream()
+	released := make(chan struct{})
+
+	live := store.Track(context.Background(), "req_1", "t1", up, func() { close(released) })
+	up.ch <- textChunk("a")
+	c, err := live.Next(context.Background())
+	if err != nil || c.Delta.Content != "a" {
+		t.Fatalf("first chunk = %v, %v", c, err)
+	}
+	// Client goes away; the upstream must keep draining.
+	_ = live.Close()
+	up.ch <- textChunk("b")
+	up.ch <- textChunk("c")
+	close(up.ch)
+
+	select {
+	case <-released:
+	case <-time.After(2 * time.Second):
+		t.Fatal("upstream was not drained to completion")
+	}
+	select {
+	case <-up.closed:
+	default:
+		t.Fatal("upstream not closed after draining")
+	}
+
+	resumed, ok := store.Resume(context.Background(), "req_1", "t1", 1)
+	if !ok {
+		t.Fatal("stream not resumable")
+	}
+	got, err := readAll(t, resumed)
+	if !errors.Is(err, io.EOF) || got != "bc" {
+		t.Fatalf("replay = %q, %v; want \"bc\", EOF", got, err)
+	}
+}
+
+func TestResumeStore_ResumeFollowsLive(t *testing.T) {
+	t.Parallel()
+	store := httpstream.NewResumeStore()
+	up := newFeedStream()
+	_ = store.Track(context.Background(), "req_1", "", up, nil)
+	up.ch <- textChunk("a")
+
+	resumed, ok := store.Resume(context.Background(), "req_1", "", 0)
+	if !ok {
+		t.Fatal("stream not resumable")
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		up.ch <- textChunk("b")
+		close(up.ch)
+	}()
+	got, err := readAll(t, resumed)
+	if !errors.Is(err, io.EOF) || got != "ab" {
+		t.Fatalf("replay = %q, %v; want \"ab\", EOF", got, err)
+	}
+}
+
+func TestResumeStore_ScopedByTenant(t *testing.T) {
+	t.Parallel()
+	store := httpstream.NewResumeStore()
+	_ = store.Track(context.Background(), "req_1", "t1", newFeedStream(), nil)
+
+	if _, ok := store.Resume(context.Background(), "req_1", "t2", 0); ok {
+		t.Fatal("another tenant resumed the stream")
+	}
+	if _, ok := store.Resume(context.Background(), "req_missing", "t1", 0); ok {
+		t.Fatal("unknown request resumed")
+	}
+}
+
+func TestResumeStore_TrimmedWindow(t *testing.T) {
+	t.Parallel()
+	store := httpstream.NewResumeStore(httpstream.WithResumeMaxFrames(2))
+	up := newFeedStream()
+	live := store.Track(context.Background(), "req_1", "", up, nil)
+	for _, s := range []string{"a", "b", "c", "d"} {
+		up.ch <- textChunk(s)
+	}
+	close(up.ch)
+	_, _ = readAll(t, live) //nolint:errcheck // only draining
+
+	resumed, _ := store.Resume(context.Background(), "req_1", "", 1)
+	if _, err := resumed.Next(context.Background()); !errors.Is(err, httpstream.ErrResumeWindow) {
+		t.Fatalf("err = %v, want ErrResumeWindow", err)
+	}
+	resumed, _ = store.Resume(context.Background(), "req_1", "", 2)
+	if got, _ := readAll(t, resumed); got != "cd" {
+		t.Fatalf("replay = %q, want \"cd\"", got)
+	}
+}
+
+func TestResumeStore_ReplaysFromSharedCache(t *testing.T) {
+	t.Parallel()
+	shared := stores.NewMemoryStream()
+	a := httpstream.NewResumeStore(httpstream.WithResumeCache(shared))
+	up := newFeedStream()
+	released := make(chan struct{})
+	_ = a.Track(context.Background(), "req_1", "t1", up, func() { close(released) })
+	up.ch <- textChunk("a")
+	up.ch <- textChunk("b")
+	close(up.ch)
+	<-released
+
+	// A reconnect landing on another replica replays the mirrored copy.
+	b := httpstream.NewResumeStore(httpstream.WithResumeCache(shared))
+	resumed, ok := b.Resume(context.Background(), "req_1", "t1", 1)
+	if !ok {
+		t.Fatal("stream not found in shared cache")
+	}
+	got, err := readAll(t, resumed)
+	if !errors.Is(err, io.EOF) || got != "b" {
+		t.Fatalf("replay = %q, %v; want \"b\", EOF", got, err)
+	}
+}
+
+func TestResumePoint(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name      string
+		target    string
+		lastID    string
+		wantID    string
+		wantAfter uint64
+		wantOK    bool
+	}{
+		{name: "none", target: "/v1/chat/completions"},
+		{name: "last event id", target: "/v1/chat/completions", lastID: "req_1:7", wantID: "req_1", wantAfter: 7, wantOK: true},
+		{name: "resume query", target: "/v1/chat/completions?resume=req_1", wantID: "req_1", wantOK: true},
+		{name: "resume with bare seq", target: "/v1/chat/completions?resume=req_1", lastID: "3", wantID: "req_1", wantAfter: 3, wantOK: true},
+		{name: "resume query with seq param", target: "/v1/chat/completions?resume=req_1&last_event_id=req_1:9", wantID: "req_1", wantAfter: 9, wantOK: true},
+		{name: "mismatched last event id", target: "/v1/chat/completions?resume=req_1", lastID: "req_2:3", wantID: "req_1", wantOK: true},
+		{name: "malformed", target: "/v1/chat/completions", lastID: "req_1:x"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest("GET", tc.target, nil)
+			if tc.lastID != "" {
+				r.Header.Set("Last-Event-ID", tc.lastID)
+			}
+			id, after, ok := httpstream.ResumePoint(r)
+			if id != tc.wantID || after != tc.wantAfter || ok != tc.wantOK {
+				t.Fatalf("ResumePoint = (%q, %d, %v), want (%q, %d, %v)", id, after, ok, tc.wantID, tc.wantAfter, tc.wantOK)
+			}
+		})
+	}
+}
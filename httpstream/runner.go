@@ -23,6 +23,11 @@ type RunOptions struct {
 	// RequestID is echoed into wire events for client-side correlation.
 	RequestID string
 
+	// LastEventID is the sequence number of the last event the client
+	// already holds when resuming; numbering continues after it. Zero for
+	// a fresh stream.
+	LastEventID uint64
+
 	// OnError is called once when the stream terminates with an error
 	// (after the encoder has emitted the typed error event but before
 	// End is called). Useful for recording mid-stream failures upstream.
@@ -40,6 +45,9 @@ type RunOptions struct {
 // closes the stream. The provider stream's own ctx-AfterFunc tears the
 // upstream connection.
 //
+// Each event is numbered from opts.LastEventID+1; SSE encoders write the
+// number as the event id so a client can resume via a ResumeStore.
+//
 // Concurrency: a single goroutine drives the loop; heartbeats are
 // scheduled with a time.Ticker on the same goroutine using a
 // non-blocking select. Encoder writes are not concurrent with each other.
@@ -93,6 +101,7 @@ func Run(ctx context.Context, w http.ResponseWriter, stream provider.Stream, enc
 	}
 	startNextRead()
 
+	seq := opts.LastEventID
 	for {
 		select {
 		case <-ctx.Done():
@@ -119,6 +128,8 @@ func Run(ctx context.Context, w http.ResponseWriter, stream provider.Stream, enc
 			}
 			if res.chunk != nil {
 				ev := FromChunk(res.chunk, opts.RequestID)
+				seq++
+				ev.Seq = seq
 				if err := encoder.EncodeEvent(w, ev); err != nil {
 					if opts.OnError != nil {
 						opts.OnError(err)
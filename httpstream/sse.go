@@ -98,6 +98,10 @@ func (e *SSEOpenAIEncoder) EncodeEvent(w io.Writer, ev *StreamEvent) error {
 	if err != nil {
 		return fmt.Errorf("httpstream: marshal sse chunk: %w", err)
 	}
+	if id := eventID(ev); id != "" {
+		_, err = fmt.Fprintf(w, "id: %s\ndata: %s\n\n", id, data)
+		return err
+	}
 	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
 	return err
 }
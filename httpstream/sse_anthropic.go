@@ -0,0 +1,274 @@
+package httpstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/messages"
+	"github.com/xraph/nexus/provider"
+)
+
+// SSEAnthropicEncoder emits a stream in the Anthropic Messages API event
+// vocabulary, so Anthropic SDK clients can consume any provider:
+//
+//	event: message_start
+//	event: content_block_start   (thinking, text, tool_use)
+//	event: content_block_delta   (thinking_delta, text_delta, input_json_delta)
+//	event: content_block_stop
+//	event: message_delta         (stop_reason, usage)
+//	event: message_stop
+//
+// It tracks the open content block, so it is StreamScoped: the registered
+// instance hands each stream a fresh encoder.
+type SSEAnthropicEncoder struct {
+	started bool
+	failed  bool
+
+	blocks  int // content blocks started so far
+	open    string
+	toolIdx map[int]int    // chunk tool-call index → block index
+	toolID  map[string]int // tool-call ID → block index
+
+	usage        *provider.Usage
+	finishReason string
+}
+
+// NewSSEAnthropicEncoder returns an Anthropic-events SSE encoder.
+func NewSSEAnthropicEncoder() *SSEAnthropicEncoder {
+	return &SSEAnthropicEncoder{
+		toolIdx: make(map[int]int),
+		toolID:  make(map[string]int),
+	}
+}
+
+// NewStream returns a fresh encoder for one stream.
+func (e *SSEAnthropicEncoder) NewStream() StreamEncoder { return NewSSEAnthropicEncoder() }
+
+func (e *SSEAnthropicEncoder) ContentType() string { return "text/event-stream" }
+
+func (e *SSEAnthropicEncoder) WriteHeaders(w http.ResponseWriter) {
+	h := w.Header()
+	h.Set("Content-Type", e.ContentType())
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+}
+
+func (e *SSEAnthropicEncoder) EncodeEvent(w io.Writer, ev *StreamEvent) error {
+	if ev == nil {
+		return nil
+	}
+	switch ev.Type {
+	case EventTypeHeartbeat:
+		return e.Heartbeat(w)
+	case EventTypeError:
+		return e.EncodeError(w, ev.Err)
+	}
+	if err := e.start(w, ev); err != nil {
+		return err
+	}
+	if ev.Usage != nil {
+		e.usage = ev.Usage
+	}
+	if ev.FinishReason != "" {
+		e.finishReason = ev.FinishReason
+	}
+	if ev.Delta == nil {
+		return nil
+	}
+
+	d := ev.Delta
+	if d.Reasoning != "" {
+		if err := e.delta(w, messages.BlockThinking, map[string]any{"type": "thinking_delta", "thinking": d.Reasoning}); err != nil {
+			return err
+		}
+	}
+	if d.ReasoningSignature != "" {
+		// The signature ends its thinking block; more thinking opens another.
+		if err := e.delta(w, messages.BlockThinking, map[string]any{"type": "signature_delta", "signature": d.ReasoningSignature}); err != nil {
+			return err
+		}
+		if err := e.closeBlock(w); err != nil {
+			return err
+		}
+	}
+	if d.Content != "" {
+		if err := e.delta(w, messages.BlockText, map[string]any{"type": "text_delta", "text": d.Content}); err != nil {
+			return err
+		}
+	}
+	for i := range d.ToolCalls {
+		if err := e.toolDelta(w, i, &d.ToolCalls[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodeError writes an error event. The stream ends after it; End
+// writes nothing more.
+func (e *SSEAnthropicEncoder) EncodeError(w io.Writer, werr *WireError) error {
+	if werr == nil {
+		return nil
+	}
+	e.failed = true
+	errType := "api_error"
+	switch werr.Type {
+	case "timeout":
+		errType = "timeout_error"
+	case "rate_limit":
+		errType = "rate_limit_error"
+	}
+	return e.write(w, "error", map[string]any{
+		"type":  "error",
+		"error": map[string]any{"type": errType, "message": werr.Message},
+	})
+}
+
+func (e *SSEAnthropicEncoder) Heartbeat(w io.Writer) error {
+	return e.write(w, "ping", map[string]any{"type": "ping"})
+}
+
+// End closes the open block and writes message_delta and message_stop.
+func (e *SSEAnthropicEncoder) End(w io.Writer) error {
+	if e.failed {
+		return nil
+	}
+	if err := e.start(w, &StreamEvent{}); err != nil {
+		return err
+	}
+	if err := e.closeBlock(w); err != nil {
+		return err
+	}
+	usage := messages.NewUsage(e.usage)
+	var stopReason any
+	if r := messages.StopReason(e.finishReason); r != "" {
+		stopReason = r
+	} else {
+		stopReason = messages.StopEndTurn
+	}
+	if err := e.write(w, "message_delta", map[string]any{
+		"type":  "message_delta",
+		"delta": map[string]any{"stop_reason": stopReason, "stop_sequence": nil},
+		"usage": usage,
+	}); err != nil {
+		return err
+	}
+	return e.write(w, "message_stop", map[string]any{"type": "message_stop"})
+}
+
+// start emits message_start once, from the first event's ID and model.
+func (e *SSEAnthropicEncoder) start(w io.Writer, ev *StreamEvent) error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	id := ev.ID
+	if id == "" {
+		id = ev.RequestID
+	}
+	return e.write(w, "message_start", map[string]any{
+		"type": "message_start",
+		"message": messages.Response{
+			ID:      messages.MessageID(id),
+			Type:    "message",
+			Role:    "assistant",
+			Model:   ev.Model,
+			Content: []messages.Block{},
+		},
+	})
+}
+
+// delta appends to the open block of kind, starting one when another kind
+// (or nothing) is open.
+func (e *SSEAnthropicEncoder) delta(w io.Writer, kind string, delta map[string]any) error {
+	if e.open != kind {
+		block := map[string]any{"type": kind}
+		switch kind {
+		case messages.BlockThinking:
+			block["thinking"] = ""
+			block["signature"] = ""
+		case messages.BlockText:
+			block["text"] = ""
+		}
+		if _, err := e.startBlock(w, kind, block); err != nil {
+			return err
+		}
+	}
+	return e.write(w, "content_block_delta", map[string]any{
+		"type":  "content_block_delta",
+		"index": e.blocks - 1,
+		"delta": delta,
+	})
+}
+
+// toolDelta merges a tool-call fragment the way provider.Accumulator does:
+// by call ID, else by its index within the chunk.
+func (e *SSEAnthropicEncoder) toolDelta(w io.Writer, idx int, tc *provider.ToolCall) error {
+	index, ok := e.toolID[tc.ID]
+	if !ok {
+		index, ok = e.toolIdx[idx]
+	}
+	if !ok {
+		var err error
+		index, err = e.startBlock(w, messages.BlockToolUse, map[string]any{
+			"type":  messages.BlockToolUse,
+			"id":    tc.ID,
+			"name":  tc.Function.Name,
+			"input": map[string]any{},
+		})
+		if err != nil {
+			return err
+		}
+		e.toolIdx[idx] = index
+		if tc.ID != "" {
+			e.toolID[tc.ID] = index
+		}
+	}
+	if tc.Function.Arguments == "" {
+		return nil
+	}
+	return e.write(w, "content_block_delta", map[string]any{
+		"type":  "content_block_delta",
+		"index": index,
+		"delta": map[string]any{"type": "input_json_delta", "partial_json": tc.Function.Arguments},
+	})
+}
+
+// startBlock closes the open block and starts a new one, returning its
+// index.
+func (e *SSEAnthropicEncoder) startBlock(w io.Writer, kind string, block map[string]any) (int, error) {
+	if err := e.closeBlock(w); err != nil {
+		return 0, err
+	}
+	index := e.blocks
+	e.blocks++
+	e.open = kind
+	return index, e.write(w, "content_block_start", map[string]any{
+		"type":          "content_block_start",
+		"index":         index,
+		"content_block": block,
+	})
+}
+
+func (e *SSEAnthropicEncoder) closeBlock(w io.Writer) error {
+	if e.open == "" {
+		return nil
+	}
+	e.open = ""
+	return e.write(w, "content_block_stop", map[string]any{
+		"type":  "content_block_stop",
+		"index": e.blocks - 1,
+	})
+}
+
+func (e *SSEAnthropicEncoder) write(w io.Writer, event string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("httpstream: marshal anthropic event: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
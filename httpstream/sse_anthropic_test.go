@@ -0,0 +1,130 @@
+package httpstream_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/provider"
+)
+
+func anthropicEvents(t *testing.T, raw string) ([]string, []map[string]any) {
+	t.Helper()
+	var names []string
+	var data []map[string]any
+	for _, block := range strings.Split(strings.TrimSpace(raw), "\n\n") {
+		lines := strings.SplitN(block, "\n", 2)
+		names = append(names, strings.TrimPrefix(lines[0], "event: "))
+		var d map[string]any
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &d); err != nil {
+			t.Fatalf("bad event %q: %v", block, err)
+		}
+		data = append(data, d)
+	}
+	return names, data
+}
+
+func TestSSEAnthropicEncoder_EventSequence(t *testing.T) {
+	t.Parallel()
+	enc := httpstream.ForStream(httpstream.DefaultRegistry().Lookup("anthropic"))
+	if _, ok := enc.(*httpstream.SSEAnthropicEncoder); !ok || enc.ContentType() != "text/event-stream" {
+		t.Fatalf("registry encoder = %T", enc)
+	}
+
+	var buf bytes.Buffer
+	for _, ev := range []*httpstream.StreamEvent{
+		{Type: httpstream.EventTypeReasoning, ID: "chatcmpl-1", Model: "m", Delta: &provider.Delta{Reasoning: "hmm"}},
+		{Type: httpstream.EventTypeDelta, Delta: &provider.Delta{Content: "Hi"}},
+		{Type: httpstream.EventTypeToolCall, Delta: &provider.Delta{ToolCalls: []provider.ToolCall{
+			{ID: "call_1", Function: provider.ToolCallFunc{Name: "lookup", Arguments: `{"q"`}},
+		}}},
+		{Type: httpstream.EventTypeToolCall, Delta: &provider.Delta{ToolCalls: []provider.ToolCall{
+			{Function: provider.ToolCallFunc{Arguments: `:1}`}},
+		}}},
+		{Type: httpstream.EventTypeUsage, FinishReason: "tool_calls", Usage: &provider.Usage{PromptTokens: 5, CompletionTokens: 3}},
+	} {
+		if err := enc.EncodeEvent(&buf, ev); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}
+	if err := enc.End(&buf); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+
+	names, data := anthropicEvents(t, buf.String())
+	want := []string{
+		"message_start",
+		"content_block_start", "content_block_delta", "content_block_stop",
+		"content_block_start", "content_block_delta", "content_block_stop",
+		"content_block_start", "content_block_delta", "content_block_delta", "content_block_stop",
+		"message_delta", "message_stop",
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("events:\n%v\nwant:\n%v", names, want)
+	}
+	if msg := data[0]["message"].(map[string]any); msg["id"] != "msg_1" || msg["model"] != "m" {
+		t.Fatalf("message_start = %v", msg)
+	}
+	if block := data[7]["content_block"].(map[string]any); block["type"] != "tool_use" || block["id"] != "call_1" || data[7]["index"] != float64(2) {
+		t.Fatalf("tool_use start = %v", data[7])
+	}
+	if delta := data[9]["delta"].(map[string]any); delta["type"] != "input_json_delta" || delta["partial_json"] != ":1}" || data[9]["index"] != float64(2) {
+		t.Fatalf("tool delta = %v", data[9])
+	}
+	md := data[11]
+	if md["delta"].(map[string]any)["stop_reason"] != "tool_use" || md["usage"].(map[string]any)["output_tokens"] != float64(3) {
+		t.Fatalf("message_delta = %v", md)
+	}
+}
+
+func TestSSEAnthropicEncoder_ErrorEndsStream(t *testing.T) {
+	t.Parallel()
+	enc := httpstream.NewSSEAnthropicEncoder()
+	var buf bytes.Buffer
+	_ = enc.EncodeEvent(&buf, &httpstream.StreamEvent{Type: httpstream.EventTypeDelta, Delta: &provider.Delta{Content: "Hi"}})
+	_ = enc.EncodeError(&buf, &httpstream.WireError{Type: "upstream", Message: "boom"})
+	_ = enc.End(&buf)
+
+	names, data := anthropicEvents(t, buf.String())
+	if names[len(names)-1] != "error" {
+		t.Fatalf("events = %v", names)
+	}
+	if e := data[len(data)-1]["error"].(map[string]any); e["type"] != "api_error" || e["message"] != "boom" {
+		t.Fatalf("error event = %v", e)
+	}
+}
+
+func TestSSEAnthropicEncoder_ThinkingSignature(t *testing.T) {
+	t.Parallel()
+	enc := httpstream.NewSSEAnthropicEncoder()
+	var buf bytes.Buffer
+	for _, d := range []*provider.Delta{
+		{Reasoning: "hmm"},
+		{ReasoningSignature: "sig"},
+		{Reasoning: "more"},
+	} {
+		if err := enc.EncodeEvent(&buf, &httpstream.StreamEvent{Type: httpstream.EventTypeReasoning, Delta: d}); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}
+	_ = enc.End(&buf)
+
+	names, data := anthropicEvents(t, buf.String())
+	want := []string{
+		"message_start",
+		"content_block_start", "content_block_delta", "content_block_delta", "content_block_stop",
+		"content_block_start", "content_block_delta", "content_block_stop",
+		"message_delta", "message_stop",
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("events:\n%v\nwant:\n%v", names, want)
+	}
+	if delta := data[3]["delta"].(map[string]any); delta["type"] != "signature_delta" || delta["signature"] != "sig" || data[3]["index"] != float64(0) {
+		t.Fatalf("signature delta = %v", data[3])
+	}
+	if data[5]["index"] != float64(1) {
+		t.Fatalf("thinking after a signature did not open a new block: %v", data[5])
+	}
+}
@@ -0,0 +1,189 @@
+package httpstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/genai"
+	"github.com/xraph/nexus/provider"
+)
+
+// SSEGeminiEncoder emits a stream the way Gemini's streamGenerateContent
+// does with alt=sse, so Google GenAI SDK clients can consume any
+// provider: every event is an unnamed `data:` line holding a
+// GenerateContentResponse chunk.
+//
+// Text and thought deltas are sent as they arrive. Gemini sends function
+// calls whole, so tool-call fragments are buffered and sent with the
+// final chunk, which also carries finishReason and usageMetadata. The
+// encoder is StreamScoped: the registered instance hands each stream a
+// fresh encoder.
+type SSEGeminiEncoder struct {
+	failed bool
+
+	id    string
+	model string
+
+	calls   []*provider.ToolCall
+	toolIdx map[int]int    // chunk tool-call index → calls index
+	toolID  map[string]int // tool-call ID → calls index
+
+	usage        *provider.Usage
+	finishReason string
+}
+
+// NewSSEGeminiEncoder returns a Gemini SSE encoder.
+func NewSSEGeminiEncoder() *SSEGeminiEncoder {
+	return &SSEGeminiEncoder{
+		toolIdx: make(map[int]int),
+		toolID:  make(map[string]int),
+	}
+}
+
+// NewStream returns a fresh encoder for one stream.
+func (e *SSEGeminiEncoder) NewStream() StreamEncoder { return NewSSEGeminiEncoder() }
+
+func (e *SSEGeminiEncoder) ContentType() string { return "text/event-stream" }
+
+func (e *SSEGeminiEncoder) WriteHeaders(w http.ResponseWriter) {
+	h := w.Header()
+	h.Set("Content-Type", e.ContentType())
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+}
+
+func (e *SSEGeminiEncoder) EncodeEvent(w io.Writer, ev *StreamEvent) error {
+	if ev == nil {
+		return nil
+	}
+	switch ev.Type {
+	case EventTypeHeartbeat:
+		return e.Heartbeat(w)
+	case EventTypeError:
+		return e.EncodeError(w, ev.Err)
+	}
+	if e.id == "" {
+		e.id = ev.ID
+		if e.id == "" {
+			e.id = ev.RequestID
+		}
+	}
+	if e.model == "" {
+		e.model = ev.Model
+	}
+	if ev.Usage != nil {
+		e.usage = ev.Usage
+	}
+	if ev.FinishReason != "" {
+		e.finishReason = ev.FinishReason
+	}
+	if ev.Delta == nil {
+		return nil
+	}
+
+	d := ev.Delta
+	for i := range d.ToolCalls {
+		e.toolDelta(i, &d.ToolCalls[i])
+	}
+	var parts []genai.Part
+	if d.Reasoning != "" {
+		parts = append(parts, genai.Part{Text: d.Reasoning, Thought: true})
+	}
+	if d.Content != "" {
+		parts = append(parts, genai.Part{Text: d.Content})
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return e.write(w, e.chunk(parts, ""))
+}
+
+// EncodeError writes a Gemini error object. The stream ends after it;
+// End writes nothing more.
+func (e *SSEGeminiEncoder) EncodeError(w io.Writer, werr *WireError) error {
+	if werr == nil {
+		return nil
+	}
+	e.failed = true
+	body := genai.ErrorBody{Code: http.StatusInternalServerError, Message: werr.Message, Status: "INTERNAL"}
+	switch werr.Type {
+	case "timeout":
+		body.Code, body.Status = http.StatusGatewayTimeout, "DEADLINE_EXCEEDED"
+	case "rate_limit":
+		body.Code, body.Status = http.StatusTooManyRequests, "RESOURCE_EXHAUSTED"
+	}
+	return e.write(w, genai.ErrorResponse{Error: body})
+}
+
+func (e *SSEGeminiEncoder) Heartbeat(w io.Writer) error {
+	_, err := fmt.Fprintf(w, ": ping\n\n")
+	return err
+}
+
+// End writes the final chunk: buffered function calls, finishReason and
+// usageMetadata.
+func (e *SSEGeminiEncoder) End(w io.Writer) error {
+	if e.failed {
+		return nil
+	}
+	parts := make([]genai.Part, 0, len(e.calls))
+	for _, tc := range e.calls {
+		parts = append(parts, genai.Part{FunctionCall: &genai.FunctionCall{
+			ID:   tc.ID,
+			Name: tc.Function.Name,
+			Args: genai.FunctionArgs(tc.Function.Arguments),
+		}})
+	}
+	reason := genai.FinishReason(e.finishReason)
+	if reason == "" {
+		reason = genai.FinishStop
+	}
+	resp := e.chunk(parts, reason)
+	resp.UsageMetadata = genai.NewUsage(e.usage)
+	return e.write(w, resp)
+}
+
+// toolDelta merges a tool-call fragment the way provider.Accumulator does:
+// by call ID, else by its index within the chunk.
+func (e *SSEGeminiEncoder) toolDelta(idx int, tc *provider.ToolCall) {
+	i, ok := e.toolID[tc.ID]
+	if !ok {
+		i, ok = e.toolIdx[idx]
+	}
+	if !ok {
+		i = len(e.calls)
+		e.calls = append(e.calls, &provider.ToolCall{ID: tc.ID, Type: "function", Function: provider.ToolCallFunc{Name: tc.Function.Name}})
+		e.toolIdx[idx] = i
+		if tc.ID != "" {
+			e.toolID[tc.ID] = i
+		}
+	}
+	call := e.calls[i]
+	if call.Function.Name == "" {
+		call.Function.Name = tc.Function.Name
+	}
+	call.Function.Arguments += tc.Function.Arguments
+}
+
+func (e *SSEGeminiEncoder) chunk(parts []genai.Part, finishReason string) *genai.Response {
+	return &genai.Response{
+		Candidates: []genai.Candidate{{
+			Content:      genai.Content{Role: genai.RoleModel, Parts: parts},
+			FinishReason: finishReason,
+		}},
+		ModelVersion: e.model,
+		ResponseID:   e.id,
+	}
+}
+
+func (e *SSEGeminiEncoder) write(w io.Writer, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("httpstream: marshal gemini chunk: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
@@ -0,0 +1,95 @@
+package httpstream_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/genai"
+	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/provider"
+)
+
+func geminiChunks(t *testing.T, raw string) []map[string]any {
+	t.Helper()
+	var chunks []map[string]any
+	for _, block := range strings.Split(strings.TrimSpace(raw), "\n\n") {
+		var c map[string]any
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(block, "data: ")), &c); err != nil {
+			t.Fatalf("bad chunk %q: %v", block, err)
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks
+}
+
+func TestSSEGeminiEncoder_Chunks(t *testing.T) {
+	t.Parallel()
+	enc := httpstream.ForStream(httpstream.DefaultRegistry().Lookup("gemini"))
+	if _, ok := enc.(*httpstream.SSEGeminiEncoder); !ok || enc.ContentType() != "text/event-stream" {
+		t.Fatalf("registry encoder = %T", enc)
+	}
+
+	var buf bytes.Buffer
+	for _, ev := range []*httpstream.StreamEvent{
+		{Type: httpstream.EventTypeReasoning, ID: "chatcmpl-1", Model: "m", Delta: &provider.Delta{Reasoning: "hmm"}},
+		{Type: httpstream.EventTypeDelta, Delta: &provider.Delta{Content: "Hi"}},
+		{Type: httpstream.EventTypeToolCall, Delta: &provider.Delta{ToolCalls: []provider.ToolCall{
+			{ID: "call_1", Function: provider.ToolCallFunc{Name: "lookup", Arguments: `{"q"`}},
+		}}},
+		{Type: httpstream.EventTypeToolCall, Delta: &provider.Delta{ToolCalls: []provider.ToolCall{
+			{Function: provider.ToolCallFunc{Arguments: `:1}`}},
+		}}},
+		{Type: httpstream.EventTypeUsage, FinishReason: "tool_calls", Usage: &provider.Usage{PromptTokens: 5, CompletionTokens: 3}},
+	} {
+		if err := enc.EncodeEvent(&buf, ev); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}
+	if err := enc.End(&buf); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+
+	var chunks []genai.Response
+	for _, c := range geminiChunks(t, buf.String()) {
+		raw, _ := json.Marshal(c)
+		var r genai.Response
+		_ = json.Unmarshal(raw, &r)
+		chunks = append(chunks, r)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("chunks = %s", buf.String())
+	}
+	if p := chunks[0].Candidates[0].Content.Parts[0]; !p.Thought || p.Text != "hmm" || chunks[0].ResponseID != "chatcmpl-1" || chunks[0].ModelVersion != "m" {
+		t.Fatalf("thought chunk = %+v", chunks[0])
+	}
+	if p := chunks[1].Candidates[0].Content.Parts[0]; p.Thought || p.Text != "Hi" {
+		t.Fatalf("text chunk = %+v", chunks[1])
+	}
+	last := chunks[2]
+	fc := last.Candidates[0].Content.Parts[0].FunctionCall
+	if fc == nil || fc.ID != "call_1" || fc.Name != "lookup" || fc.Args["q"] != float64(1) {
+		t.Fatalf("function call = %+v", fc)
+	}
+	if last.Candidates[0].FinishReason != "STOP" || last.UsageMetadata == nil || last.UsageMetadata.TotalTokenCount != 8 {
+		t.Fatalf("final chunk = %+v", last)
+	}
+}
+
+func TestSSEGeminiEncoder_ErrorEndsStream(t *testing.T) {
+	t.Parallel()
+	enc := httpstream.NewSSEGeminiEncoder()
+	var buf bytes.Buffer
+	_ = enc.EncodeEvent(&buf, &httpstream.StreamEvent{Type: httpstream.EventTypeDelta, Delta: &provider.Delta{Content: "Hi"}})
+	_ = enc.EncodeError(&buf, &httpstream.WireError{Type: "rate_limit", Message: "slow down"})
+	_ = enc.End(&buf)
+
+	chunks := geminiChunks(t, buf.String())
+	if len(chunks) != 2 {
+		t.Fatalf("chunks = %s", buf.String())
+	}
+	if e := chunks[1]["error"].(map[string]any); e["status"] != "RESOURCE_EXHAUSTED" || e["code"] != float64(429) || e["message"] != "slow down" {
+		t.Fatalf("error chunk = %v", e)
+	}
+}
@@ -41,6 +41,10 @@ func (e *SSENativeEncoder) EncodeEvent(w io.Writer, ev *StreamEvent) error {
 	if err != nil {
 		return fmt.Errorf("httpstream: marshal native sse event: %w", err)
 	}
+	if id := eventID(ev); id != "" {
+		_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, ev.Type, data)
+		return err
+	}
 	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
 	return err
 }
@@ -0,0 +1,394 @@
+package httpstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/responses"
+)
+
+// SSEResponsesEncoder emits a stream in the OpenAI Responses API event
+// vocabulary, so Responses SDK clients can consume any provider:
+//
+//	event: response.created
+//	event: response.in_progress
+//	event: response.output_item.added          (reasoning, message, function_call)
+//	event: response.reasoning_summary_text.delta
+//	event: response.output_text.delta
+//	event: response.function_call_arguments.delta
+//	event: response.output_item.done           (…and the matching .done events)
+//	event: response.completed                  (or response.incomplete / response.failed)
+//
+// Unlike the chat encoders it is stateful — it tracks the open output
+// items and the sequence_number every event carries — so each stream
+// needs its own encoder from NewSSEResponsesEncoder. It is not registered
+// for negotiation; the /v1/responses handler selects it explicitly.
+type SSEResponsesEncoder struct {
+	resp    responses.Response
+	seq     int
+	started bool
+
+	reasoning *responsesOpenItem
+	message   *responsesOpenItem
+	calls     []*responsesOpenItem
+	callByIdx map[int]*responsesOpenItem
+	callByID  map[string]*responsesOpenItem
+
+	usage        *provider.Usage
+	finishReason string
+	failed       *responses.Error
+}
+
+// responsesOpenItem is an output item still receiving deltas.
+type responsesOpenItem struct {
+	index int
+	item  responses.Item
+	text  string
+	done  bool
+}
+
+// NewSSEResponsesEncoder returns an encoder for one stream. base is the
+// in-progress response the events describe; its ID, model and echoed
+// request parameters appear in the lifecycle events.
+func NewSSEResponsesEncoder(base *responses.Response) *SSEResponsesEncoder {
+	e := &SSEResponsesEncoder{
+		resp:      *base,
+		callByIdx: make(map[int]*responsesOpenItem),
+		callByID:  make(map[string]*responsesOpenItem),
+	}
+	e.resp.Output = []responses.Item{}
+	return e
+}
+
+func (e *SSEResponsesEncoder) ContentType() string { return "text/event-stream" }
+
+func (e *SSEResponsesEncoder) WriteHeaders(w http.ResponseWriter) {
+	h := w.Header()
+	h.Set("Content-Type", e.ContentType())
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+}
+
+func (e *SSEResponsesEncoder) EncodeEvent(w io.Writer, ev *StreamEvent) error {
+	if ev == nil {
+		return nil
+	}
+	switch ev.Type {
+	case EventTypeHeartbeat:
+		return e.Heartbeat(w)
+	case EventTypeError:
+		return e.EncodeError(w, ev.Err)
+	}
+	if err := e.start(w); err != nil {
+		return err
+	}
+	if ev.Model != "" {
+		e.resp.Model = ev.Model
+	}
+	if ev.Usage != nil {
+		e.usage = ev.Usage
+	}
+	if ev.FinishReason != "" {
+		e.finishReason = ev.FinishReason
+	}
+	if ev.Delta == nil {
+		return nil
+	}
+
+	d := ev.Delta
+	if d.Reasoning != "" {
+		if err := e.reasoningDelta(w, d.Reasoning); err != nil {
+			return err
+		}
+	}
+	if d.Content != "" {
+		if err := e.textDelta(w, d.Content); err != nil {
+			return err
+		}
+	}
+	for i := range d.ToolCalls {
+		if err := e.toolCallDelta(w, i, &d.ToolCalls[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *SSEResponsesEncoder) EncodeError(w io.Writer, werr *WireError) error {
+	if werr == nil {
+		return nil
+	}
+	if err := e.start(w); err != nil {
+		return err
+	}
+	code := werr.Code
+	if code == "" {
+		code = werr.Type
+	}
+	e.failed = &responses.Error{Code: code, Message: werr.Message}
+	return e.write(w, "error", map[string]any{
+		"code":    code,
+		"message": werr.Message,
+		"param":   nil,
+	})
+}
+
+func (e *SSEResponsesEncoder) Heartbeat(w io.Writer) error {
+	_, err := fmt.Fprintf(w, ": ping\n\n")
+	return err
+}
+
+// End closes the open output items and writes the terminal lifecycle
+// event. The Responses protocol has no [DONE] sentinel.
+func (e *SSEResponsesEncoder) End(w io.Writer) error {
+	if err := e.start(w); err != nil {
+		return err
+	}
+	if err := e.closeReasoning(w); err != nil {
+		return err
+	}
+	if err := e.closeMessage(w); err != nil {
+		return err
+	}
+	if err := e.closeCalls(w); err != nil {
+		return err
+	}
+
+	e.resp.Usage = responses.NewUsage(e.usage)
+	event := "response.completed"
+	if e.failed != nil {
+		e.resp.Status = responses.StatusFailed
+		e.resp.Error = e.failed
+		event = "response.failed"
+	} else {
+		e.resp.Status, e.resp.IncompleteDetails = responses.FinishStatus(e.finishReason)
+		if e.resp.Status == responses.StatusIncomplete {
+			event = "response.incomplete"
+		}
+	}
+	return e.write(w, event, map[string]any{"response": e.snapshot()})
+}
+
+// start emits response.created and response.in_progress once.
+func (e *SSEResponsesEncoder) start(w io.Writer) error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	e.resp.Status = responses.StatusInProgress
+	if err := e.write(w, "response.created", map[string]any{"response": e.snapshot()}); err != nil {
+		return err
+	}
+	return e.write(w, "response.in_progress", map[string]any{"response": e.snapshot()})
+}
+
+func (e *SSEResponsesEncoder) reasoningDelta(w io.Writer, delta string) error {
+	if e.reasoning == nil {
+		e.reasoning = e.open(responses.Item{
+			Type:    responses.ItemReasoning,
+			ID:      responses.ItemID("rs", e.resp.ID, 0),
+			Summary: []responses.ContentPart{},
+		})
+		if err := e.write(w, "response.output_item.added", e.itemPayload(e.reasoning)); err != nil {
+			return err
+		}
+		if err := e.write(w, "response.reasoning_summary_part.added", e.summaryPayload(e.reasoning, "part", "")); err != nil {
+			return err
+		}
+	}
+	e.reasoning.text += delta
+	return e.write(w, "response.reasoning_summary_text.delta", e.summaryPayload(e.reasoning, "delta", delta))
+}
+
+func (e *SSEResponsesEncoder) textDelta(w io.Writer, delta string) error {
+	if e.message == nil {
+		if err := e.closeReasoning(w); err != nil {
+			return err
+		}
+		e.message = e.open(responses.Item{
+			Type:    responses.ItemMessage,
+			ID:      responses.ItemID("msg", e.resp.ID, 0),
+			Status:  responses.StatusInProgress,
+			Role:    "assistant",
+			Content: responses.Content{},
+		})
+		if err := e.write(w, "response.output_item.added", e.itemPayload(e.message)); err != nil {
+			return err
+		}
+		if err := e.write(w, "response.content_part.added", e.contentPayload(e.message, "part", "")); err != nil {
+			return err
+		}
+	}
+	e.message.text += delta
+	return e.write(w, "response.output_text.delta", e.contentPayload(e.message, "delta", delta))
+}
+
+// toolCallDelta merges a tool-call fragment the way provider.Accumulator
+// does — by call ID, else by its index within the chunk — so the streamed
+// items line up with the stored output.
+func (e *SSEResponsesEncoder) toolCallDelta(w io.Writer, idx int, tc *provider.ToolCall) error {
+	call := e.callByID[tc.ID]
+	if call == nil {
+		call = e.callByIdx[idx]
+	}
+	if call == nil {
+		if err := e.closeReasoning(w); err != nil {
+			return err
+		}
+		if err := e.closeMessage(w); err != nil {
+			return err
+		}
+		call = e.open(responses.Item{
+			Type:   responses.ItemFunctionCall,
+			ID:     responses.ItemID("fc", e.resp.ID, len(e.calls)),
+			Status: responses.StatusInProgress,
+			CallID: tc.ID,
+			Name:   tc.Function.Name,
+		})
+		e.calls = append(e.calls, call)
+		e.callByIdx[idx] = call
+		if tc.ID != "" {
+			e.callByID[tc.ID] = call
+		}
+		if err := e.write(w, "response.output_item.added", e.itemPayload(call)); err != nil {
+			return err
+		}
+	} else {
+		if call.item.CallID == "" && tc.ID != "" {
+			call.item.CallID = tc.ID
+			e.callByID[tc.ID] = call
+		}
+		if call.item.Name == "" {
+			call.item.Name = tc.Function.Name
+		}
+	}
+	if tc.Function.Arguments == "" {
+		return nil
+	}
+	call.text += tc.Function.Arguments
+	return e.write(w, "response.function_call_arguments.delta", map[string]any{
+		"item_id":      call.item.ID,
+		"output_index": call.index,
+		"delta":        tc.Function.Arguments,
+	})
+}
+
+func (e *SSEResponsesEncoder) closeReasoning(w io.Writer) error {
+	r := e.reasoning
+	if r == nil || r.done {
+		return nil
+	}
+	r.done = true
+	if err := e.write(w, "response.reasoning_summary_text.done", e.summaryPayload(r, "text", r.text)); err != nil {
+		return err
+	}
+	if err := e.write(w, "response.reasoning_summary_part.done", e.summaryPayload(r, "part", r.text)); err != nil {
+		return err
+	}
+	r.item.Summary = []responses.ContentPart{{Type: responses.PartSummaryText, Text: r.text}}
+	return e.finishItem(w, r)
+}
+
+func (e *SSEResponsesEncoder) closeMessage(w io.Writer) error {
+	m := e.message
+	if m == nil || m.done {
+		return nil
+	}
+	m.done = true
+	if err := e.write(w, "response.output_text.done", e.contentPayload(m, "text", m.text)); err != nil {
+		return err
+	}
+	if err := e.write(w, "response.content_part.done", e.contentPayload(m, "part", m.text)); err != nil {
+		return err
+	}
+	m.item.Content = responses.Content{{Type: responses.PartOutputText, Text: m.text}}
+	return e.finishItem(w, m)
+}
+
+func (e *SSEResponsesEncoder) closeCalls(w io.Writer) error {
+	for _, c := range e.calls {
+		if c.done {
+			continue
+		}
+		c.done = true
+		if err := e.write(w, "response.function_call_arguments.done", map[string]any{
+			"item_id":      c.item.ID,
+			"output_index": c.index,
+			"arguments":    c.text,
+		}); err != nil {
+			return err
+		}
+		c.item.Arguments = c.text
+		if err := e.finishItem(w, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// open appends an in-progress item to the output.
+func (e *SSEResponsesEncoder) open(item responses.Item) *responsesOpenItem {
+	it := &responsesOpenItem{index: len(e.resp.Output), item: item}
+	e.resp.Output = append(e.resp.Output, item)
+	return it
+}
+
+// finishItem marks an item completed and emits output_item.done.
+func (e *SSEResponsesEncoder) finishItem(w io.Writer, it *responsesOpenItem) error {
+	if it.item.Type != responses.ItemReasoning {
+		it.item.Status = responses.StatusCompleted
+	}
+	e.resp.Output[it.index] = it.item
+	return e.write(w, "response.output_item.done", e.itemPayload(it))
+}
+
+func (e *SSEResponsesEncoder) itemPayload(it *responsesOpenItem) map[string]any {
+	return map[string]any{"output_index": it.index, "item": it.item}
+}
+
+// contentPayload describes the message's single output_text part. key is
+// "delta", "text" or "part".
+func (e *SSEResponsesEncoder) contentPayload(it *responsesOpenItem, key, text string) map[string]any {
+	p := map[string]any{"item_id": it.item.ID, "output_index": it.index, "content_index": 0}
+	if key == "part" {
+		p[key] = responses.ContentPart{Type: responses.PartOutputText, Text: text}
+	} else {
+		p[key] = text
+	}
+	return p
+}
+
+// summaryPayload describes the reasoning item's single summary part.
+func (e *SSEResponsesEncoder) summaryPayload(it *responsesOpenItem, key, text string) map[string]any {
+	p := map[string]any{"item_id": it.item.ID, "output_index": it.index, "summary_index": 0}
+	if key == "part" {
+		p[key] = responses.ContentPart{Type: responses.PartSummaryText, Text: text}
+	} else {
+		p[key] = text
+	}
+	return p
+}
+
+// snapshot copies the response so later mutation doesn't alias events.
+func (e *SSEResponsesEncoder) snapshot() responses.Response {
+	r := e.resp
+	r.Output = append([]responses.Item{}, e.resp.Output...)
+	return r
+}
+
+// write emits one named event; payload gains the type and sequence number.
+func (e *SSEResponsesEncoder) write(w io.Writer, event string, payload map[string]any) error {
+	payload["type"] = event
+	payload["sequence_number"] = e.seq
+	e.seq++
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("httpstream: marshal responses event: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
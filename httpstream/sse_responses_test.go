@@ -0,0 +1,128 @@
+package httpstream_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/responses"
+)
+
+type responsesEvent struct {
+	name string
+	data map[string]any
+}
+
+func parseResponsesEvents(t *testing.T, raw string) []responsesEvent {
+	t.Helper()
+	var out []responsesEvent
+	for _, block := range strings.Split(strings.TrimSpace(raw), "\n\n") {
+		var ev responsesEvent
+		for _, line := range strings.Split(block, "\n") {
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				ev.name = name
+			}
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				if err := json.Unmarshal([]byte(data), &ev.data); err != nil {
+					t.Fatalf("bad data %q: %v", data, err)
+				}
+			}
+		}
+		if ev.name != "" {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+func TestSSEResponsesEncoder_EventSequence(t *testing.T) {
+	t.Parallel()
+	enc := httpstream.NewSSEResponsesEncoder(&responses.Response{ID: "resp_abc", Object: "response", Model: "m"})
+
+	var buf bytes.Buffer
+	for _, ev := range []*httpstream.StreamEvent{
+		{Type: httpstream.EventTypeReasoning, Delta: &provider.Delta{Reasoning: "let me think"}},
+		{Type: httpstream.EventTypeDelta, Delta: &provider.Delta{Content: "Calling"}},
+		{Type: httpstream.EventTypeToolCall, Delta: &provider.Delta{ToolCalls: []provider.ToolCall{
+			{ID: "call_1", Type: "function", Function: provider.ToolCallFunc{Name: "lookup", Arguments: `{"q":`}},
+		}}},
+		{Type: httpstream.EventTypeToolCall, Delta: &provider.Delta{ToolCalls: []provider.ToolCall{
+			{Function: provider.ToolCallFunc{Arguments: `"x"}`}},
+		}}},
+		{Type: httpstream.EventTypeUsage, Usage: &provider.Usage{PromptTokens: 4, CompletionTokens: 6, TotalTokens: 10}, FinishReason: "tool_calls"},
+	} {
+		if err := enc.EncodeEvent(&buf, ev); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}
+	if err := enc.End(&buf); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+	if strings.Contains(buf.String(), "[DONE]") {
+		t.Fatal("responses stream must not end with [DONE]")
+	}
+
+	events := parseResponsesEvents(t, buf.String())
+	var names []string
+	for i, ev := range events {
+		names = append(names, ev.name)
+		if seq, _ := ev.data["sequence_number"].(float64); int(seq) != i {
+			t.Fatalf("event %d (%s) sequence_number = %v", i, ev.name, ev.data["sequence_number"])
+		}
+		if ev.data["type"] != ev.name {
+			t.Fatalf("event %s has type %v", ev.name, ev.data["type"])
+		}
+	}
+	want := []string{
+		"response.created", "response.in_progress",
+		"response.output_item.added", "response.reasoning_summary_part.added", "response.reasoning_summary_text.delta",
+		"response.reasoning_summary_text.done", "response.reasoning_summary_part.done", "response.output_item.done",
+		"response.output_item.added", "response.content_part.added", "response.output_text.delta",
+		"response.output_text.done", "response.content_part.done", "response.output_item.done",
+		"response.output_item.added", "response.function_call_arguments.delta", "response.function_call_arguments.delta",
+		"response.function_call_arguments.done", "response.output_item.done",
+		"response.completed",
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("events:\n%v\nwant:\n%v", names, want)
+	}
+
+	final := events[len(events)-1].data["response"].(map[string]any)
+	output := final["output"].([]any)
+	if final["status"] != "completed" || len(output) != 3 {
+		t.Fatalf("final response = %v", final)
+	}
+	call := output[2].(map[string]any)
+	if call["call_id"] != "call_1" || call["arguments"] != `{"q":"x"}` || call["id"] != responses.ItemID("fc", "resp_abc", 0) {
+		t.Fatalf("function call item = %v", call)
+	}
+	if usage := final["usage"].(map[string]any); usage["total_tokens"] != float64(10) {
+		t.Fatalf("usage = %v", usage)
+	}
+}
+
+func TestSSEResponsesEncoder_ErrorFailsResponse(t *testing.T) {
+	t.Parallel()
+	enc := httpstream.NewSSEResponsesEncoder(&responses.Response{ID: "resp_abc", Object: "response"})
+
+	var buf bytes.Buffer
+	_ = enc.EncodeEvent(&buf, &httpstream.StreamEvent{Type: httpstream.EventTypeDelta, Delta: &provider.Delta{Content: "par"}})
+	_ = enc.EncodeError(&buf, &httpstream.WireError{Type: "upstream", Message: "boom"})
+	_ = enc.End(&buf)
+
+	events := parseResponsesEvents(t, buf.String())
+	last := events[len(events)-1]
+	if last.name != "response.failed" {
+		t.Fatalf("last event = %s", last.name)
+	}
+	resp := last.data["response"].(map[string]any)
+	if errObj := resp["error"].(map[string]any); errObj["code"] != "upstream" || errObj["message"] != "boom" {
+		t.Fatalf("error = %v", resp["error"])
+	}
+	if events[len(events)-2].name != "response.output_item.done" {
+		t.Fatalf("open message not closed before failure: %v", events)
+	}
+}
@@ -24,10 +24,14 @@ type Prefix string
 
 // Prefix constants for all Nexus entity types.
 const (
-	PrefixTenant  Prefix = "tenant"
-	PrefixKey     Prefix = "key"
-	PrefixUsage   Prefix = "usage"
-	PrefixRequest Prefix = "req"
+	PrefixTenant   Prefix = "tenant"
+	PrefixKey      Prefix = "key"
+	PrefixUsage    Prefix = "usage"
+	PrefixRequest  Prefix = "req"
+	PrefixDocument Prefix = "doc"
+	PrefixFile     Prefix = "file"
+	PrefixBatch    Prefix = "batch"
+	PrefixResponse Prefix = "resp"
 )
 
 // ID is the primary identifier type for all Nexus entities.
@@ -139,6 +143,18 @@ func NewUsageID() ID { return New(PrefixUsage) }
 // NewRequestID generates a new unique request ID.
 func NewRequestID() ID { return New(PrefixRequest) }
 
+// NewDocumentID generates a new unique RAG document ID.
+func NewDocumentID() ID { return New(PrefixDocument) }
+
+// NewFileID generates a new unique batch file ID.
+func NewFileID() ID { return New(PrefixFile) }
+
+// NewBatchID generates a new unique batch ID.
+func NewBatchID() ID { return New(PrefixBatch) }
+
+// NewResponseID generates a new unique background response ID.
+func NewResponseID() ID { return New(PrefixResponse) }
+
 // ──────────────────────────────────────────────────
 // Convenience parsers
 // ──────────────────────────────────────────────────
@@ -0,0 +1,341 @@
+package messages
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// ToCompletionRequest translates a Messages API request into a unified
+// completion request.
+func ToCompletionRequest(req *Request) (*provider.CompletionRequest, error) {
+	if req.Model == "" {
+		return nil, fmt.Errorf("model: field required")
+	}
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("messages: at least one message is required")
+	}
+
+	creq := &provider.CompletionRequest{
+		Model:       req.Model,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Stop:        req.StopSequences,
+		Stream:      req.Stream,
+	}
+
+	for i, b := range req.System {
+		if b.Type != BlockText {
+			return nil, fmt.Errorf("system.%d: unsupported block type %q", i, b.Type)
+		}
+	}
+	creq.System = joinText(req.System, "\n\n")
+	if n := len(req.System); n > 0 {
+		creq.SystemCacheControl = req.System[n-1].CacheControl
+	}
+
+	callNames := make(map[string]string)
+	for i, m := range req.Messages {
+		var (
+			msgs []provider.Message
+			err  error
+		)
+		switch m.Role {
+		case "user":
+			msgs, err = userMessages(m.Content, callNames)
+		case "assistant":
+			msgs, err = assistantMessages(m.Content, callNames)
+		default:
+			err = fmt.Errorf("unsupported role %q", m.Role)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("messages.%d: %w", i, err)
+		}
+		creq.Messages = append(creq.Messages, msgs...)
+	}
+
+	for i, t := range req.Tools {
+		if t.Type != "" && t.Type != "custom" {
+			return nil, fmt.Errorf("tools.%d: unsupported tool type %q: only custom tools are supported", i, t.Type)
+		}
+		creq.Tools = append(creq.Tools, provider.Tool{
+			Type: "function",
+			Function: provider.ToolFunction{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.InputSchema,
+			},
+			CacheControl: t.CacheControl,
+		})
+	}
+	if req.ToolChoice != nil {
+		choice, err := toolChoice(req.ToolChoice)
+		if err != nil {
+			return nil, err
+		}
+		creq.ToolChoice = choice
+	}
+
+	if req.Thinking != nil && req.Thinking.Type == "enabled" {
+		if req.Thinking.BudgetTokens <= 0 {
+			return nil, fmt.Errorf("thinking.budget_tokens: must be positive")
+		}
+		creq.Thinking = &provider.ThinkingConfig{
+			Enabled:         true,
+			BudgetTokens:    req.Thinking.BudgetTokens,
+			IncludeThinking: true,
+		}
+	}
+	if req.Metadata != nil && req.Metadata.UserID != "" {
+		creq.Metadata = map[string]string{"user_id": req.Metadata.UserID}
+	}
+	return creq, nil
+}
+
+// userMessages converts a user turn. Tool results become tool messages
+// named after the call they answer (Gemini needs the name); the remaining
+// blocks become one user message after them.
+func userMessages(content Content, callNames map[string]string) ([]provider.Message, error) {
+	var out []provider.Message
+	var parts []provider.ContentPart
+	for i, b := range content {
+		switch b.Type {
+		case BlockToolResult:
+			if b.ToolUseID == "" {
+				return nil, fmt.Errorf("content.%d: tool_use_id: field required", i)
+			}
+			text := joinText(b.Content, "\n")
+			if b.IsError && text == "" {
+				text = "error"
+			}
+			out = append(out, provider.Message{
+				Role:       "tool",
+				Content:    text,
+				Name:       callNames[b.ToolUseID],
+				ToolCallID: b.ToolUseID,
+			})
+		case BlockText:
+			parts = append(parts, provider.ContentPart{Type: "text", Text: b.Text, CacheControl: b.CacheControl})
+		case BlockImage:
+			part, err := imagePart(b)
+			if err != nil {
+				return nil, fmt.Errorf("content.%d: %w", i, err)
+			}
+			parts = append(parts, part)
+		default:
+			return nil, fmt.Errorf("content.%d: unsupported block type %q", i, b.Type)
+		}
+	}
+	if len(parts) > 0 {
+		out = append(out, contentMessage("user", parts))
+	}
+	return out, nil
+}
+
+// assistantMessages converts an assistant turn: text blocks become the
+// content, tool_use blocks its tool calls and thinking blocks its thinking,
+// which Anthropic verifies by signature when the turn is sent back.
+func assistantMessages(content Content, callNames map[string]string) ([]provider.Message, error) {
+	var text strings.Builder
+	var calls []provider.ToolCall
+	var thinking []provider.ThinkingBlock
+	for i, b := range content {
+		switch b.Type {
+		case BlockText:
+			text.WriteString(b.Text)
+		case BlockToolUse:
+			if b.ID == "" || b.Name == "" {
+				return nil, fmt.Errorf("content.%d: tool_use requires id and name", i)
+			}
+			args := string(b.Input)
+			if args == "" {
+				args = "{}"
+			}
+			callNames[b.ID] = b.Name
+			calls = append(calls, provider.ToolCall{
+				ID:       b.ID,
+				Type:     "function",
+				Function: provider.ToolCallFunc{Name: b.Name, Arguments: args},
+			})
+		case BlockThinking:
+			thinking = append(thinking, provider.ThinkingBlock{Thinking: b.Thinking, Signature: b.Signature})
+		case BlockRedactedThinking:
+			if b.Data == "" {
+				return nil, fmt.Errorf("content.%d: redacted_thinking requires data", i)
+			}
+			thinking = append(thinking, provider.ThinkingBlock{Redacted: b.Data})
+		default:
+			return nil, fmt.Errorf("content.%d: unsupported block type %q", i, b.Type)
+		}
+	}
+	return []provider.Message{{Role: "assistant", Content: text.String(), ToolCalls: calls, Thinking: thinking}}, nil
+}
+
+// contentMessage flattens text-only parts to a string, keeping a cache
+// marker on the last part as the message marker.
+func contentMessage(role string, parts []provider.ContentPart) provider.Message {
+	for _, p := range parts {
+		if p.Type != "text" {
+			return provider.Message{Role: role, Content: parts}
+		}
+	}
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(p.Text)
+	}
+	return provider.Message{Role: role, Content: b.String(), CacheControl: parts[len(parts)-1].CacheControl}
+}
+
+func imagePart(b Block) (provider.ContentPart, error) {
+	if b.Source == nil {
+		return provider.ContentPart{}, fmt.Errorf("image: source: field required")
+	}
+	switch b.Source.Type {
+	case "base64":
+		return provider.ContentPart{Type: "image_base64", Data: b.Source.Data, MimeType: b.Source.MediaType, CacheControl: b.CacheControl}, nil
+	case "url":
+		return provider.ContentPart{Type: "image_url", ImageURL: b.Source.URL, CacheControl: b.CacheControl}, nil
+	default:
+		return provider.ContentPart{}, fmt.Errorf("image: unsupported source type %q", b.Source.Type)
+	}
+}
+
+// toolChoice converts tool_choice to the chat form.
+func toolChoice(tc *ToolChoice) (any, error) {
+	switch tc.Type {
+	case "auto":
+		return "auto", nil
+	case "any":
+		return "required", nil
+	case "none":
+		return "none", nil
+	case "tool":
+		if tc.Name == "" {
+			return nil, fmt.Errorf("tool_choice.name: field required")
+		}
+		return map[string]any{"type": "function", "function": map[string]any{"name": tc.Name}}, nil
+	default:
+		return nil, fmt.Errorf("tool_choice: unsupported type %q", tc.Type)
+	}
+}
+
+// FromCompletion converts a completion into a message: the thinking blocks
+// (or one unsigned block when the provider returned only thinking text),
+// the text, then one tool_use block per call.
+func FromCompletion(resp *provider.CompletionResponse) *Response {
+	out := &Response{
+		ID:      MessageID(resp.ID),
+		Type:    "message",
+		Role:    "assistant",
+		Model:   resp.Model,
+		Content: []Block{},
+		Usage:   NewUsage(&resp.Usage),
+	}
+	var thinking []provider.ThinkingBlock
+	if len(resp.Choices) > 0 {
+		thinking = resp.Choices[0].Message.Thinking
+	}
+	for _, th := range thinking {
+		if th.Redacted != "" {
+			out.Content = append(out.Content, Block{Type: BlockRedactedThinking, Data: th.Redacted})
+			continue
+		}
+		out.Content = append(out.Content, Block{Type: BlockThinking, Thinking: th.Thinking, Signature: th.Signature})
+	}
+	if len(thinking) == 0 && resp.ThinkingContent != "" {
+		out.Content = append(out.Content, Block{Type: BlockThinking, Thinking: resp.ThinkingContent})
+	}
+	if len(resp.Choices) == 0 {
+		return out
+	}
+	choice := resp.Choices[0]
+	if text := messageText(choice.Message.Content); text != "" {
+		out.Content = append(out.Content, Block{Type: BlockText, Text: text})
+	}
+	for _, tc := range choice.Message.ToolCalls {
+		out.Content = append(out.Content, Block{Type: BlockToolUse, ID: tc.ID, Name: tc.Function.Name, Input: ToolInput(tc.Function.Arguments)})
+	}
+	if reason := StopReason(choice.FinishReason); reason != "" {
+		out.StopReason = &reason
+	}
+	return out
+}
+
+// MessageID returns id with the "msg_" prefix Anthropic clients expect.
+func MessageID(id string) string {
+	if strings.HasPrefix(id, "msg_") {
+		return id
+	}
+	return "msg_" + strings.TrimPrefix(id, "chatcmpl-")
+}
+
+// ToolInput returns tool-call arguments as a JSON object; arguments that
+// are empty or not valid JSON become {}.
+func ToolInput(arguments string) json.RawMessage {
+	if arguments == "" || !json.Valid([]byte(arguments)) {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(arguments)
+}
+
+// StopReason maps a unified finish reason to a Messages API stop reason.
+// Reasons already in Anthropic form pass through.
+func StopReason(finishReason string) string {
+	switch finishReason {
+	case "":
+		return ""
+	case "stop":
+		return StopEndTurn
+	case "length":
+		return StopMaxTokens
+	case "tool_calls", "function_call":
+		return StopToolUse
+	case "content_filter":
+		return StopRefusal
+	default:
+		return finishReason
+	}
+}
+
+// NewUsage converts unified usage to Messages API usage.
+func NewUsage(u *provider.Usage) Usage {
+	if u == nil {
+		return Usage{}
+	}
+	return Usage{
+		InputTokens:              u.PromptTokens,
+		OutputTokens:             u.CompletionTokens,
+		CacheCreationInputTokens: u.CacheWriteTokens,
+		CacheReadInputTokens:     u.CacheReadTokens,
+	}
+}
+
+// joinText concatenates the text of text blocks.
+func joinText(content Content, sep string) string {
+	texts := make([]string, 0, len(content))
+	for _, b := range content {
+		if b.Type == BlockText {
+			texts = append(texts, b.Text)
+		}
+	}
+	return strings.Join(texts, sep)
+}
+
+// messageText flattens message content to its text.
+func messageText(content any) string {
+	switch v := content.(type) {
+	case string:
+		return v
+	case []provider.ContentPart:
+		var b strings.Builder
+		for _, p := range v {
+			b.WriteString(p.Text)
+		}
+		return b.String()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,150 @@
+package messages_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xraph/nexus/messages"
+	"github.com/xraph/nexus/provider"
+)
+
+func decodeRequest(t *testing.T, body string) *messages.Request {
+	t.Helper()
+	var req messages.Request
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return &req
+}
+
+func TestToCompletionRequest(t *testing.T) {
+	t.Parallel()
+	req := decodeRequest(t, `{
+		"model": "claude-sonnet-4",
+		"max_tokens": 1024,
+		"system": [{"type": "text", "text": "You are terse."}, {"type": "text", "text": "Use tools.", "cache_control": {"type": "ephemeral"}}],
+		"messages": [
+			{"role": "user", "content": [
+				{"type": "text", "text": "What is in this image?"},
+				{"type": "image", "source": {"type": "base64", "media_type": "image/png", "data": "iVBOR"}}
+			]},
+			{"role": "assistant", "content": [
+				{"type": "thinking", "thinking": "look it up", "signature": "sig"},
+				{"type": "redacted_thinking", "data": "enc"},
+				{"type": "text", "text": "Checking."},
+				{"type": "tool_use", "id": "toolu_1", "name": "lookup", "input": {"q": "png"}}
+			]},
+			{"role": "user", "content": [
+				{"type": "tool_result", "tool_use_id": "toolu_1", "content": [{"type": "text", "text": "a cat"}]},
+				{"type": "text", "text": "Thanks"}
+			]}
+		],
+		"tools": [{"name": "lookup", "description": "Search", "input_schema": {"type": "object"}}],
+		"tool_choice": {"type": "any"},
+		"thinking": {"type": "enabled", "budget_tokens": 512},
+		"stop_sequences": ["END"],
+		"metadata": {"user_id": "u1"}
+	}`)
+
+	creq, err := messages.ToCompletionRequest(req)
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	if creq.System != "You are terse.\n\nUse tools." || creq.SystemCacheControl == nil {
+		t.Fatalf("system = %q cache=%v", creq.System, creq.SystemCacheControl)
+	}
+	if len(creq.Messages) != 4 {
+		t.Fatalf("messages = %+v", creq.Messages)
+	}
+	parts, ok := creq.Messages[0].Content.([]provider.ContentPart)
+	if !ok || len(parts) != 2 || parts[1].Type != "image_base64" || parts[1].MimeType != "image/png" {
+		t.Fatalf("image message = %#v", creq.Messages[0].Content)
+	}
+	if m := creq.Messages[1]; m.Role != "assistant" || m.Content != "Checking." || len(m.ToolCalls) != 1 || m.ToolCalls[0].Function.Arguments != `{"q": "png"}` {
+		t.Fatalf("assistant message = %+v", m)
+	}
+	if th := creq.Messages[1].Thinking; len(th) != 2 || th[0].Thinking != "look it up" || th[0].Signature != "sig" || th[1].Redacted != "enc" {
+		t.Fatalf("thinking blocks = %+v", th)
+	}
+	if m := creq.Messages[2]; m.Role != "tool" || m.ToolCallID != "toolu_1" || m.Name != "lookup" || m.Content != "a cat" {
+		t.Fatalf("tool result = %+v", m)
+	}
+	if m := creq.Messages[3]; m.Role != "user" || m.Content != "Thanks" {
+		t.Fatalf("trailing user text = %+v", m)
+	}
+	if creq.ToolChoice != "required" || len(creq.Tools) != 1 || creq.Tools[0].Function.Name != "lookup" {
+		t.Fatalf("tools = %+v choice=%v", creq.Tools, creq.ToolChoice)
+	}
+	if th := creq.Thinking; th == nil || !th.Enabled || th.BudgetTokens != 512 {
+		t.Fatalf("thinking = %+v", creq.Thinking)
+	}
+	if creq.MaxTokens != 1024 || creq.Stop[0] != "END" || creq.Metadata["user_id"] != "u1" {
+		t.Fatalf("request = %+v", creq)
+	}
+}
+
+func TestToCompletionRequest_Errors(t *testing.T) {
+	t.Parallel()
+	for name, body := range map[string]string{
+		"no messages": `{"model":"m","max_tokens":1}`,
+		"server tool": `{"model":"m","messages":[{"role":"user","content":"hi"}],"tools":[{"type":"web_search_20250305","name":"web_search"}]}`,
+		"bad role":    `{"model":"m","messages":[{"role":"system","content":"hi"}]}`,
+		"bad block":   `{"model":"m","messages":[{"role":"user","content":[{"type":"document"}]}]}`,
+		"bad choice":  `{"model":"m","messages":[{"role":"user","content":"hi"}],"tool_choice":{"type":"tool"}}`,
+		"no data":     `{"model":"m","messages":[{"role":"assistant","content":[{"type":"redacted_thinking"}]}]}`,
+	} {
+		if _, err := messages.ToCompletionRequest(decodeRequest(t, body)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestFromCompletion(t *testing.T) {
+	t.Parallel()
+	resp := messages.FromCompletion(&provider.CompletionResponse{
+		ID:              "chatcmpl-abc",
+		Model:           "gpt-4o",
+		ThinkingContent: "hmm",
+		Choices: []provider.Choice{{
+			Message: provider.Message{Role: "assistant", Content: "Let me check.", ToolCalls: []provider.ToolCall{
+				{ID: "call_1", Type: "function", Function: provider.ToolCallFunc{Name: "lookup", Arguments: `{"q":1}`}},
+			}},
+			FinishReason: "tool_calls",
+		}},
+		Usage: provider.Usage{PromptTokens: 10, CompletionTokens: 4, CacheReadTokens: 2},
+	})
+
+	raw, _ := json.Marshal(resp)
+	want := `{"id":"msg_abc","type":"message","role":"assistant","model":"gpt-4o","content":[` +
+		`{"type":"thinking","thinking":"hmm","signature":""},` +
+		`{"type":"text","text":"Let me check."},` +
+		`{"type":"tool_use","id":"call_1","name":"lookup","input":{"q":1}}],` +
+		`"stop_reason":"tool_use","stop_sequence":null,` +
+		`"usage":{"input_tokens":10,"output_tokens":4,"cache_creation_input_tokens":0,"cache_read_input_tokens":2}}`
+	if string(raw) != want {
+		t.Fatalf("message =\n%s\nwant\n%s", raw, want)
+	}
+}
+
+func TestFromCompletion_SignedThinking(t *testing.T) {
+	t.Parallel()
+	resp := messages.FromCompletion(&provider.CompletionResponse{
+		ID:              "msg_1",
+		ThinkingContent: "hmm",
+		Choices: []provider.Choice{{
+			Message: provider.Message{Role: "assistant", Content: "Done.", Thinking: []provider.ThinkingBlock{
+				{Thinking: "hmm", Signature: "sig"},
+				{Redacted: "enc"},
+			}},
+			FinishReason: "stop",
+		}},
+	})
+
+	raw, _ := json.Marshal(resp.Content)
+	want := `[{"type":"thinking","thinking":"hmm","signature":"sig"},` +
+		`{"type":"redacted_thinking","data":"enc"},` +
+		`{"type":"text","text":"Done."}]`
+	if string(raw) != want {
+		t.Fatalf("content =\n%s\nwant\n%s", raw, want)
+	}
+}
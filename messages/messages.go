@@ -0,0 +1,214 @@
+// Package messages translates the Anthropic Messages API (/v1/messages)
+// to and from the unified provider types, so clients built on the
+// Anthropic SDKs can reach any provider the gateway serves.
+//
+// Content blocks, tool_use and tool_result blocks, thinking and the
+// system prompt map onto a provider.CompletionRequest; a
+// provider.CompletionResponse maps back onto a message with thinking,
+// text and tool_use blocks.
+package messages
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// Block types.
+const (
+	BlockText             = "text"
+	BlockImage            = "image"
+	BlockToolUse          = "tool_use"
+	BlockToolResult       = "tool_result"
+	BlockThinking         = "thinking"
+	BlockRedactedThinking = "redacted_thinking"
+)
+
+// Stop reasons.
+const (
+	StopEndTurn      = "end_turn"
+	StopMaxTokens    = "max_tokens"
+	StopStopSequence = "stop_sequence"
+	StopToolUse      = "tool_use"
+	StopRefusal      = "refusal"
+)
+
+// Request is the body of POST /v1/messages and /v1/messages/count_tokens.
+type Request struct {
+	Model         string      `json:"model"`
+	Messages      []Message   `json:"messages"`
+	System        Content     `json:"system,omitempty"`
+	MaxTokens     int         `json:"max_tokens,omitempty"`
+	StopSequences []string    `json:"stop_sequences,omitempty"`
+	Stream        bool        `json:"stream,omitempty"`
+	Temperature   *float64    `json:"temperature,omitempty"`
+	TopP          *float64    `json:"top_p,omitempty"`
+	TopK          *int        `json:"top_k,omitempty"` // accepted, not forwarded
+	Tools         []Tool      `json:"tools,omitempty"`
+	ToolChoice    *ToolChoice `json:"tool_choice,omitempty"`
+	Thinking      *Thinking   `json:"thinking,omitempty"`
+	Metadata      *Metadata   `json:"metadata,omitempty"`
+}
+
+// Message is one conversation turn.
+type Message struct {
+	Role    string  `json:"role"` // user, assistant
+	Content Content `json:"content"`
+}
+
+// Content is a list of blocks; a plain string is one text block.
+type Content []Block
+
+// UnmarshalJSON accepts a string or an array of blocks.
+func (c *Content) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*c = Content{{Type: BlockText, Text: s}}
+		return nil
+	}
+	var blocks []Block
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return errors.New("content must be a string or an array of content blocks")
+	}
+	*c = blocks
+	return nil
+}
+
+// Block is one content block. Which fields apply depends on Type.
+type Block struct {
+	Type string `json:"type"`
+
+	// text
+	Text string `json:"text,omitempty"`
+
+	// image
+	Source *Source `json:"source,omitempty"`
+
+	// tool_use
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// tool_result
+	ToolUseID string  `json:"tool_use_id,omitempty"`
+	Content   Content `json:"content,omitempty"`
+	IsError   bool    `json:"is_error,omitempty"`
+
+	// thinking, redacted_thinking
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"`
+
+	CacheControl *provider.CacheControl `json:"cache_control,omitempty"`
+}
+
+// MarshalJSON writes the fields each block type requires, even when empty
+// (a thinking block always carries "signature", tool_use always "input").
+func (b Block) MarshalJSON() ([]byte, error) {
+	switch b.Type {
+	case BlockText:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}{b.Type, b.Text})
+	case BlockToolUse:
+		input := b.Input
+		if len(input) == 0 {
+			input = json.RawMessage("{}")
+		}
+		return json.Marshal(struct {
+			Type  string          `json:"type"`
+			ID    string          `json:"id"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		}{b.Type, b.ID, b.Name, input})
+	case BlockThinking:
+		return json.Marshal(struct {
+			Type      string `json:"type"`
+			Thinking  string `json:"thinking"`
+			Signature string `json:"signature"`
+		}{b.Type, b.Thinking, b.Signature})
+	default:
+		type plain Block
+		return json.Marshal(plain(b))
+	}
+}
+
+// Source is an image source: base64 data or a URL.
+type Source struct {
+	Type      string `json:"type"` // base64, url
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// Tool is a client tool. Anthropic server tools (web_search, bash, …)
+// carry a versioned type and are rejected.
+type Tool struct {
+	Type         string                 `json:"type,omitempty"` // empty or "custom"
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  any                    `json:"input_schema,omitempty"`
+	CacheControl *provider.CacheControl `json:"cache_control,omitempty"`
+}
+
+// ToolChoice selects how the model uses tools.
+type ToolChoice struct {
+	Type                   string `json:"type"` // auto, any, tool, none
+	Name                   string `json:"name,omitempty"`
+	DisableParallelToolUse bool   `json:"disable_parallel_tool_use,omitempty"`
+}
+
+// Thinking configures extended thinking.
+type Thinking struct {
+	Type         string `json:"type"` // enabled, disabled
+	BudgetTokens int    `json:"budget_tokens,omitempty"`
+}
+
+// Metadata describes the request's end user.
+type Metadata struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+// Response is the Messages API message object.
+type Response struct {
+	ID           string  `json:"id"`
+	Type         string  `json:"type"`
+	Role         string  `json:"role"`
+	Model        string  `json:"model"`
+	Content      []Block `json:"content"`
+	StopReason   *string `json:"stop_reason"`
+	StopSequence *string `json:"stop_sequence"`
+	Usage        Usage   `json:"usage"`
+}
+
+// Usage is the Messages API token usage.
+type Usage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// CountTokensResponse is the body returned by /v1/messages/count_tokens.
+type CountTokensResponse struct {
+	InputTokens int `json:"input_tokens"`
+}
+
+// ErrorResponse is the Messages API error envelope.
+type ErrorResponse struct {
+	Type  string    `json:"type"` // always "error"
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes an error.
+type ErrorBody struct {
+	Type    string `json:"type"` // invalid_request_error, authentication_error, not_found_error, api_error, …
+	Message string `json:"message"`
+}
@@ -1,6 +1,10 @@
 package model
 
-import "github.com/xraph/nexus/provider"
+import (
+	"context"
+
+	"github.com/xraph/nexus/provider"
+)
 
 // CostEstimate represents the estimated cost of a request.
 type CostEstimate struct {
@@ -8,12 +12,34 @@ type CostEstimate struct {
 	OutputCost float64 `json:"output_cost"` // USD
 	TotalCost  float64 `json:"total_cost"`  // USD
 	Currency   string  `json:"currency"`    // always "USD"
+
+	// CacheCost is the share of InputCost spent on prompt-cache reads and
+	// writes. Already included in InputCost.
+	CacheCost float64 `json:"cache_cost,omitempty"` // USD
 }
 
 // EstimateCost calculates the estimated cost for a request based on
-// token usage and model pricing.
+// token usage and model pricing. Cached prompt tokens are charged at the
+// pricing's cache read/write rates; the remaining prompt tokens at the
+// regular input rate.
 func EstimateCost(usage provider.Usage, pricing provider.Pricing) *CostEstimate {
-	inputCost := float64(usage.PromptTokens) / 1_000_000 * pricing.InputPerMillion
+	uncached := usage.PromptTokens - usage.CacheReadTokens - usage.CacheWriteTokens
+	if uncached < 0 {
+		uncached = 0
+	}
+
+	readRate := pricing.CacheReadPerMillion
+	if readRate == 0 {
+		readRate = pricing.InputPerMillion
+	}
+	writeRate := pricing.CacheWritePerMillion
+	if writeRate == 0 {
+		writeRate = pricing.InputPerMillion
+	}
+
+	cacheCost := float64(usage.CacheReadTokens)/1_000_000*readRate +
+		float64(usage.CacheWriteTokens)/1_000_000*writeRate
+	inputCost := float64(uncached)/1_000_000*pricing.InputPerMillion + cacheCost
 	outputCost := float64(usage.CompletionTokens) / 1_000_000 * pricing.OutputPerMillion
 
 	return &CostEstimate{
@@ -21,6 +47,7 @@ func EstimateCost(usage provider.Usage, pricing provider.Pricing) *CostEstimate
 		OutputCost: outputCost,
 		TotalCost:  inputCost + outputCost,
 		Currency:   "USD",
+		CacheCost:  cacheCost,
 	}
 }
 
@@ -36,3 +63,22 @@ func EstimateCostFromTokens(inputTokens, outputTokens int, pricing provider.Pric
 		Currency:   "USD",
 	}
 }
+
+// FindPricing looks modelID up in p's catalog. Dated model versions
+// returned by providers (gpt-4o-2024-08-06) match their catalog alias.
+func FindPricing(ctx context.Context, p provider.Provider, modelID string) (provider.Pricing, bool) {
+	models, err := p.Models(ctx)
+	if err != nil {
+		return provider.Pricing{}, false
+	}
+	var best provider.Model
+	for _, m := range models {
+		if m.ID == modelID {
+			return m.Pricing, true
+		}
+		if len(m.ID) > len(best.ID) && len(modelID) > len(m.ID) && modelID[:len(m.ID)] == m.ID && modelID[len(m.ID)] == '-' {
+			best = m
+		}
+	}
+	return best.Pricing, best.ID != ""
+}
@@ -0,0 +1,96 @@
+package model_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/xraph/nexus/model"
+	"github.com/xraph/nexus/provider"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestEstimateCost_CacheRates(t *testing.T) {
+	t.Parallel()
+	pricing := provider.Pricing{
+		InputPerMillion:      3,
+		OutputPerMillion:     15,
+		CacheReadPerMillion:  0.3,
+		CacheWritePerMillion: 3.75,
+	}
+
+	tests := []struct {
+		name                  string
+		usage                 provider.Usage
+		pricing               provider.Pricing
+		input, output, cached float64
+	}{
+		{
+			name:   "no cache",
+			usage:  provider.Usage{PromptTokens: 1_000_000, CompletionTokens: 100_000},
+			input:  3,
+			output: 1.5,
+		},
+		{
+			name:   "reads and writes",
+			usage:  provider.Usage{PromptTokens: 4_000_000, CompletionTokens: 0, CacheReadTokens: 2_000_000, CacheWriteTokens: 1_000_000},
+			input:  3 + 0.6 + 3.75,
+			cached: 0.6 + 3.75,
+		},
+		{
+			name:    "undiscounted cache falls back to the input rate",
+			usage:   provider.Usage{PromptTokens: 2_000_000, CacheReadTokens: 1_000_000, CacheWriteTokens: 1_000_000},
+			pricing: provider.Pricing{InputPerMillion: 3},
+			input:   6,
+			cached:  6,
+		},
+		{
+			name:   "cached tokens beyond the prompt count",
+			usage:  provider.Usage{PromptTokens: 500_000, CacheReadTokens: 1_000_000},
+			input:  0.3,
+			cached: 0.3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := pricing
+			if tt.pricing != (provider.Pricing{}) {
+				p = tt.pricing
+			}
+			got := model.EstimateCost(tt.usage, p)
+			if !near(got.InputCost, tt.input) || !near(got.OutputCost, tt.output) ||
+				!near(got.CacheCost, tt.cached) || !near(got.TotalCost, tt.input+tt.output) {
+				t.Fatalf("cost = %+v, want input %v output %v cache %v", got, tt.input, tt.output, tt.cached)
+			}
+		})
+	}
+}
+
+type catalogProvider struct {
+	provider.Provider
+	models []provider.Model
+}
+
+func (p catalogProvider) Models(context.Context) ([]provider.Model, error) { return p.models, nil }
+
+func TestFindPricing(t *testing.T) {
+	t.Parallel()
+	p := catalogProvider{models: []provider.Model{
+		{ID: "gpt-4o", Pricing: provider.Pricing{InputPerMillion: 2.5}},
+		{ID: "gpt-4o-mini", Pricing: provider.Pricing{InputPerMillion: 0.15}},
+	}}
+	for id, want := range map[string]float64{
+		"gpt-4o":                 2.5,
+		"gpt-4o-mini":            0.15,
+		"gpt-4o-2024-08-06":      2.5,
+		"gpt-4o-mini-2024-07-18": 0.15,
+	} {
+		if got, ok := model.FindPricing(context.Background(), p, id); !ok || got.InputPerMillion != want {
+			t.Errorf("FindPricing(%q) = %v, %v; want %v", id, got.InputPerMillion, ok, want)
+		}
+	}
+	if _, ok := model.FindPricing(context.Background(), p, "gpt-4"); ok {
+		t.Error("FindPricing matched a model outside the catalog")
+	}
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// imageTokens is the estimated prompt cost of one image part (a
+// high-detail 1024x1024 image on the OpenAI tile formula).
+const imageTokens = 765
+
+// defaultOutputReserve caps the output tokens reserved for requests that
+// do not set max_tokens.
+const defaultOutputReserve = 4096
+
+// catalogMissTTL is how long an unknown model is remembered as unknown, so
+// counting does not list every provider's models on each request.
+const catalogMissTTL = time.Minute
+
+// maxCatalogMisses bounds the unknown-model cache; model names come from
+// clients.
+const maxCatalogMisses = 10000
+
+// DefaultTokenCounter is the built-in RequestTokenCounter. Only heuristics
+// are built in: no rank files ship with Nexus, so OpenAI models are counted
+// with an approximation of their BPE and other providers with calibrated
+// per-family heuristics. Register the cl100k_base and o200k_base rank files
+// with WithEncoding for exact OpenAI counts. Context window and maximum
+// output come from the model catalog (provider.Model).
+type DefaultTokenCounter struct {
+	models    Service
+	strategy  OverflowStrategy
+	encodings map[string]Tokenizer
+
+	mu     sync.Mutex
+	misses map[string]time.Time
+}
+
+// CounterOption configures a DefaultTokenCounter.
+type CounterOption func(*DefaultTokenCounter)
+
+// WithOverflowStrategy sets the strategy reported on estimates. The empty
+// default lets overflowing requests through to the provider.
+func WithOverflowStrategy(s OverflowStrategy) CounterOption {
+	return func(c *DefaultTokenCounter) { c.strategy = s }
+}
+
+// WithEncoding registers an exact tokenizer for an encoding name
+// (EncodingCL100K, EncodingO200K), typically loaded with LoadBPEFile.
+func WithEncoding(name string, t Tokenizer) CounterOption {
+	return func(c *DefaultTokenCounter) { c.encodings[name] = t }
+}
+
+// NewTokenCounter creates the built-in token counter. models supplies
+// context window and output limits; with a nil service every model is
+// treated as having an unknown window and never overflows.
+func NewTokenCounter(models Service, opts ...CounterOption) *DefaultTokenCounter {
+	c := &DefaultTokenCounter{
+		models:    models,
+		encodings: make(map[string]Tokenizer),
+		misses:    make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Compile-time check.
+var _ RequestTokenCounter = (*DefaultTokenCounter)(nil)
+
+func (c *DefaultTokenCounter) tokenizer(fam tokenizerFamily) Tokenizer {
+	if t, ok := c.encodings[fam.encoding]; ok && fam.encoding != "" {
+		return t
+	}
+	return fam.fallback
+}
+
+// Count returns the token count of text for model.
+func (c *DefaultTokenCounter) Count(_ context.Context, text, model string) (int, error) {
+	return c.tokenizer(familyFor(model)).CountTokens(text), nil
+}
+
+// CountMessages returns the prompt tokens for messages, including the
+// per-message and per-request framing the provider adds.
+func (c *DefaultTokenCounter) CountMessages(_ context.Context, messages []Message, model string) (int, error) {
+	if len(messages) == 0 {
+		return 0, nil
+	}
+	fam := familyFor(model)
+	tok := c.tokenizer(fam)
+	n := fam.perRequest
+	for _, m := range messages {
+		n += fam.perMessage + tok.CountTokens(m.Content) + m.Images*imageTokens
+	}
+	return n, nil
+}
+
+// EstimateRequest estimates the request's input tokens and checks them,
+// plus the reserved output, against the model's context window.
+func (c *DefaultTokenCounter) EstimateRequest(ctx context.Context, messages []Message, model string, maxTokens int) (*TokenEstimate, error) {
+	input, err := c.CountMessages(ctx, messages, model)
+	if err != nil {
+		return nil, err
+	}
+	est := &TokenEstimate{
+		InputTokens:      input,
+		OutputMax:        maxTokens,
+		OverflowStrategy: c.strategy,
+	}
+	if m := c.lookup(ctx, model); m != nil {
+		est.ContextWindow = m.ContextWindow
+		if est.OutputMax == 0 {
+			est.OutputMax = m.MaxOutput
+			if est.OutputMax == 0 || est.OutputMax > defaultOutputReserve {
+				est.OutputMax = defaultOutputReserve
+			}
+		}
+	}
+	est.Total = est.InputTokens + est.OutputMax
+	est.Overflow = est.ContextWindow > 0 && est.Total > est.ContextWindow
+	return est, nil
+}
+
+// lookup returns the catalog entry for model, remembering misses briefly.
+func (c *DefaultTokenCounter) lookup(ctx context.Context, model string) *provider.Model {
+	if c.models == nil || model == "" {
+		return nil
+	}
+	c.mu.Lock()
+	if until, ok := c.misses[model]; ok && time.Now().Before(until) {
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+
+	m, err := c.models.Get(ctx, model)
+	if err != nil || m == nil {
+		c.mu.Lock()
+		if len(c.misses) >= maxCatalogMisses {
+			c.misses = make(map[string]time.Time)
+		}
+		c.misses[model] = time.Now().Add(catalogMissTTL)
+		c.mu.Unlock()
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,254 @@
+package model
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokenizer counts the tokens a model's tokenizer produces for a text.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// Encoding names for the OpenAI BPE vocabularies.
+const (
+	EncodingCL100K = "cl100k_base" // gpt-4, gpt-3.5-turbo, text-embedding-3-*
+	EncodingO200K  = "o200k_base"  // gpt-4o, gpt-4.1, gpt-5, o-series
+)
+
+// preTokenRe splits text into the pieces BPE merges within, following the
+// tiktoken cl100k/o200k split pattern. RE2 has no lookahead, so the
+// `\s+(?!\S)` alternative is emulated by splitPreTokens.
+var preTokenRe = regexp.MustCompile(`(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`)
+
+// splitPreTokens returns the pre-tokenized pieces of text. A run of spaces
+// followed by a word gives its last space to the word, as tiktoken does.
+func splitPreTokens(text string) []string {
+	var pieces []string
+	for pos := 0; pos < len(text); {
+		loc := preTokenRe.FindStringIndex(text[pos:])
+		if loc == nil {
+			pieces = append(pieces, text[pos:])
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		piece := text[start:end]
+		if end < len(text) && strings.TrimSpace(piece) == "" && !strings.ContainsAny(piece, "\r\n") {
+			if _, size := utf8.DecodeLastRuneInString(piece); size < len(piece) {
+				end -= size
+			}
+		}
+		pieces = append(pieces, text[start:end])
+		pos = end
+	}
+	return pieces
+}
+
+// BPETokenizer is a byte-level BPE tokenizer loaded from a tiktoken rank
+// file (one "<base64 token> <rank>" pair per line). Rank files are not
+// bundled; load them with LoadBPE or LoadBPEFile and register them on the
+// counter with WithEncoding.
+type BPETokenizer struct {
+	ranks map[string]int
+}
+
+// LoadBPE reads a tiktoken rank file.
+func LoadBPE(r io.Reader) (*BPETokenizer, error) {
+	t := &BPETokenizer{ranks: make(map[string]int)}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("model: bpe: line %d: expected token and rank", line)
+		}
+		tok, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("model: bpe: line %d: %w", line, err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("model: bpe: line %d: %w", line, err)
+		}
+		t.ranks[string(tok)] = rank
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("model: bpe: %w", err)
+	}
+	if len(t.ranks) == 0 {
+		return nil, fmt.Errorf("model: bpe: empty rank file")
+	}
+	return t, nil
+}
+
+// LoadBPEFile reads a tiktoken rank file from disk, e.g. cl100k_base.tiktoken.
+func LoadBPEFile(path string) (*BPETokenizer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("model: bpe: %w", err)
+	}
+	defer f.Close()
+	return LoadBPE(f)
+}
+
+// CountTokens returns the exact number of BPE tokens in text.
+func (t *BPETokenizer) CountTokens(text string) int {
+	n := 0
+	for _, piece := range splitPreTokens(text) {
+		n += t.countPiece(piece)
+	}
+	return n
+}
+
+// countPiece merges the byte pair with the lowest rank until no adjacent
+// pair is in the vocabulary and returns the number of parts left.
+func (t *BPETokenizer) countPiece(piece string) int {
+	if _, ok := t.ranks[piece]; ok {
+		return 1
+	}
+	// bounds[i] is the start offset of part i; the last entry is len(piece).
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+	for len(bounds) > 2 {
+		best, bestRank := -1, math.MaxInt
+		for i := 0; i+2 < len(bounds); i++ {
+			if r, ok := t.ranks[piece[bounds[i]:bounds[i+2]]]; ok && r < bestRank {
+				best, bestRank = i, r
+			}
+		}
+		if best < 0 {
+			break
+		}
+		bounds = append(bounds[:best+1], bounds[best+2:]...)
+	}
+	return len(bounds) - 1
+}
+
+// approxBPE estimates BPE token counts without a vocabulary: text is split
+// with the real pre-tokenizer and each piece is costed by its shape. Load
+// the real rank file with WithEncoding when exact counts matter.
+type approxBPE struct {
+	bytesPerWordToken float64
+}
+
+func (a approxBPE) CountTokens(text string) int {
+	n := 0
+	for _, piece := range splitPreTokens(text) {
+		n += a.countPiece(piece)
+	}
+	return n
+}
+
+func (a approxBPE) countPiece(piece string) int {
+	word := strings.TrimLeftFunc(piece, unicode.IsSpace)
+	switch {
+	case word == "":
+		return 1
+	case !isASCII(word):
+		// Non-Latin scripts average roughly one token per character.
+		return utf8.RuneCountInString(word)
+	case isLetters(word):
+		return int(math.Ceil(float64(len(word)) / a.bytesPerWordToken))
+	case isDigits(word):
+		return 1 // the pre-tokenizer already splits numbers into <=3 digits
+	default:
+		// Punctuation runs merge less readily than words.
+		return int(math.Ceil(float64(len(word)) / 2))
+	}
+}
+
+// heuristicTokenizer estimates tokens as characters divided by a calibrated
+// per-family ratio, counting non-ASCII runes individually.
+type heuristicTokenizer struct {
+	charsPerToken float64
+}
+
+func (h heuristicTokenizer) CountTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return int(math.Ceil(float64(ascii)/h.charsPerToken)) + other
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != '\'' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenizerFamily describes how a model family is counted.
+type tokenizerFamily struct {
+	encoding string // BPE encoding name; "" for heuristic families
+	fallback Tokenizer
+	// perMessage and perRequest are the framing overheads the provider adds
+	// around each message and the whole prompt.
+	perMessage int
+	perRequest int
+}
+
+// familyFor maps a model ID to its tokenizer family.
+func familyFor(modelID string) tokenizerFamily {
+	id := strings.ToLower(modelID)
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	switch {
+	case strings.HasPrefix(id, "gpt-4o"), strings.HasPrefix(id, "gpt-4.1"), strings.HasPrefix(id, "gpt-4.5"),
+		strings.HasPrefix(id, "gpt-5"), strings.HasPrefix(id, "chatgpt-4o"),
+		strings.HasPrefix(id, "o1"), strings.HasPrefix(id, "o3"), strings.HasPrefix(id, "o4"):
+		return tokenizerFamily{encoding: EncodingO200K, fallback: approxBPE{bytesPerWordToken: 6.5}, perMessage: 3, perRequest: 3}
+	case strings.HasPrefix(id, "gpt-4"), strings.HasPrefix(id, "gpt-3.5"), strings.HasPrefix(id, "text-embedding"):
+		return tokenizerFamily{encoding: EncodingCL100K, fallback: approxBPE{bytesPerWordToken: 6}, perMessage: 3, perRequest: 3}
+	case strings.Contains(id, "claude"):
+		return tokenizerFamily{fallback: heuristicTokenizer{charsPerToken: 3.5}, perMessage: 4, perRequest: 8}
+	case strings.Contains(id, "gemini"), strings.Contains(id, "gemma"):
+		return tokenizerFamily{fallback: heuristicTokenizer{charsPerToken: 4}, perMessage: 4, perRequest: 2}
+	case strings.Contains(id, "llama"), strings.Contains(id, "mistral"), strings.Contains(id, "mixtral"),
+		strings.Contains(id, "qwen"), strings.Contains(id, "deepseek"):
+		return tokenizerFamily{fallback: heuristicTokenizer{charsPerToken: 3.7}, perMessage: 5, perRequest: 2}
+	case strings.Contains(id, "command"), strings.Contains(id, "cohere"):
+		return tokenizerFamily{fallback: heuristicTokenizer{charsPerToken: 4.2}, perMessage: 4, perRequest: 2}
+	default:
+		return tokenizerFamily{fallback: heuristicTokenizer{charsPerToken: 4}, perMessage: 4, perRequest: 3}
+	}
+}
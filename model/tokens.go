@@ -25,7 +25,8 @@ type RequestTokenCounter interface {
 // It mirrors the essential fields from provider.Message.
 type Message struct {
 	Role    string
-	Content string
+	Content string // all text: content, content parts and tool-call arguments
+	Images  int    // number of image parts
 }
 
 // TokenEstimate is the result of a token estimation.
@@ -34,7 +35,7 @@ type TokenEstimate struct {
 	OutputMax        int              `json:"output_max"`
 	Total            int              `json:"total"`
 	ContextWindow    int              `json:"context_window"`
-	Overflow         bool             `json:"overflow"` // true if InputTokens > ContextWindow
+	Overflow         bool             `json:"overflow"` // true if Total > ContextWindow
 	OverflowStrategy OverflowStrategy `json:"overflow_strategy,omitempty"`
 }
 
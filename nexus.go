@@ -18,9 +18,12 @@ package nexus
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/xraph/nexus/auth"
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/batch"
 	"github.com/xraph/nexus/cache"
 	"github.com/xraph/nexus/guard"
 	"github.com/xraph/nexus/key"
@@ -29,7 +32,9 @@ import (
 	"github.com/xraph/nexus/pipeline"
 	"github.com/xraph/nexus/pipeline/middlewares"
 	"github.com/xraph/nexus/plugin"
+	"github.com/xraph/nexus/prompt"
 	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/rag"
 	"github.com/xraph/nexus/router"
 	"github.com/xraph/nexus/router/strategies"
 	"github.com/xraph/nexus/store"
@@ -72,13 +77,53 @@ type Gateway struct {
 	// Custom middleware to add to the pipeline
 	customMiddleware []pipeline.Middleware
 
+	// inputPipeline runs only the default pipeline's input phase; native
+	// batch items are prepared with it. Nil with a custom pipeline.
+	inputPipeline pipeline.Service
+
 	// Stream lifecycle config — tunes per-chunk hook fan-out for streaming.
 	streamLifecycleCfg middlewares.StreamLifecycleConfig
+	embeddingBatchCfg  middlewares.EmbeddingBatchConfig
 
 	// Stream cache (optional) for record-and-replay of streamed responses.
 	streamCache    cache.StreamCache
 	streamCacheCfg cache.StreamCacheOptions
 
+	// Request coalescing (optional) — shares one provider call between
+	// concurrent identical requests.
+	coalesce bool
+
+	// Streaming output guardrails — strategy and sliding-window size used
+	// when output guards are registered.
+	streamGuardStrategy guard.StreamStrategy
+	streamGuardWindow   int
+
+	// Named guardrail policies selected per tenant via
+	// tenant.Config.GuardrailPolicy, with each tenant's choice cached.
+	guardPolicies  *guard.PolicyRegistry
+	tenantPolicies tenantPolicyCache
+
+	// Context-window management — token counter (default: the built-in
+	// model.DefaultTokenCounter), its options, and an optional summarizer
+	// for turns dropped by truncation.
+	tokenCounter      model.RequestTokenCounter
+	tokenCounterOpts  []model.CounterOption
+	contextSummarizer middlewares.Summarizer
+
+	// Retrieval-augmented generation (optional).
+	rag *rag.Retriever
+
+	// Prompt template registry, backed by the store unless replaced.
+	prompts prompt.Service
+
+	// Batch jobs, run through the engine or native provider batch APIs.
+	batch     batch.Service
+	batchOpts []batch.Option
+
+	// Background responses, streamed on managed workers and persisted.
+	background     background.Service
+	backgroundOpts []background.Option
+
 	initialized bool
 }
 
@@ -113,12 +158,23 @@ func (gw *Gateway) Initialize(_ context.Context) error {
 	if gw.logger == nil {
 		gw.logger = NewNoopLogger()
 	}
+	if gw.tenant == nil {
+		gw.tenant = tenant.NewService(gw.store.Tenants())
+	}
+	if gw.prompts == nil {
+		gw.prompts = prompt.NewService(gw.store.Prompts())
+	}
 
 	// Initialize model service
 	if gw.model == nil {
 		gw.model = model.NewService(gw.aliasRegistry, gw.providers)
 	}
 
+	// Token counter for context-window management and count_tokens.
+	if gw.tokenCounter == nil {
+		gw.tokenCounter = model.NewTokenCounter(gw.model, gw.tokenCounterOpts...)
+	}
+
 	// Default router: priority strategy (registration order)
 	if gw.router == nil {
 		gw.router = router.NewService(strategies.NewPriority())
@@ -127,9 +183,34 @@ func (gw *Gateway) Initialize(_ context.Context) error {
 	// Initialize engine
 	gw.engine = newEngine(gw)
 
+	// Batch service: native provider batches where supported, otherwise
+	// a worker pool running items through the engine. Items are billed
+	// per line even when no usage service is configured.
+	if gw.batch == nil {
+		recorder := gw.usage
+		if recorder == nil {
+			recorder = usage.NewService(gw.store.Usage())
+		}
+		opts := append([]batch.Option{
+			batch.WithProviders(gw.providers),
+			batch.WithUsage(recorder),
+		}, gw.batchOpts...)
+		if gw.config.GlobalRateLimit > 0 {
+			opts = append([]batch.Option{batch.WithRateLimit(gw.config.GlobalRateLimit)}, opts...)
+		}
+		gw.batch = batch.NewService(gw.store.Batches(), batchExecutor{gw.engine}, opts...)
+	}
+
+	// Background service: long completions run detached from the request
+	// and are polled or replayed from their persisted frames.
+	if gw.background == nil {
+		gw.background = background.NewService(gw.store.Background(), backgroundExecutor{gw.engine}, gw.backgroundOpts...)
+	}
+
 	// Build default pipeline if not set
 	if gw.pipeline == nil {
 		gw.pipeline = gw.buildDefaultPipeline()
+		gw.inputPipeline = pipeline.NewBuilder().Use(gw.inputMiddleware()...).Use(preparedRequest{}).Build()
 	}
 
 	gw.initialized = true
@@ -157,20 +238,25 @@ func (gw *Gateway) buildDefaultPipeline() pipeline.Service {
 		b.Use(middlewares.NewTimeout(gw.config.DefaultTimeout))
 	}
 
-	// Priority 150: Input guardrails (if configured)
-	if gw.guard != nil {
-		b.Use(middlewares.NewGuardrail(gw.guard))
-	}
+	// Priorities 140-250: Prompt templates, input guardrails, transforms and
+	// alias resolution
+	b.Use(gw.inputMiddleware()...)
 
-	// Priority 200: Transforms (if configured)
-	if gw.transforms != nil {
-		b.Use(middlewares.NewTransform(gw.transforms))
+	// Priority 155: Streaming output guardrails (if output guards configured)
+	if len(guard.OutputStreamGuards(gw.guard)) > 0 || gw.guardPolicies != nil {
+		strategy := gw.streamGuardStrategy
+		if strategy == "" {
+			strategy = guard.StrategySlidingWindow
+		}
+		mw := middlewares.NewServiceStreamGuardrail(gw.guard, strategy).WithWindowSize(gw.streamGuardWindow)
+		if gw.guardPolicies != nil {
+			mw = mw.WithPolicies(gw.guardrailPolicies())
+		}
+		b.Use(mw)
 	}
 
-	// Priority 250: Alias resolution (if configured)
-	if gw.aliasRegistry != nil {
-		b.Use(middlewares.NewAlias(gw.aliasRegistry))
-	}
+	// Priority 260: Token counting and context-window management
+	b.Use(middlewares.NewTokenCounting(gw.tokenCounter).WithSummarizer(gw.contextSummarizer))
 
 	// Priority 280: Cache (if configured)
 	if gw.cache != nil || gw.streamCache != nil {
@@ -181,13 +267,18 @@ func (gw *Gateway) buildDefaultPipeline() pipeline.Service {
 		b.Use(mw)
 	}
 
+	// Priority 285: In-flight request coalescing (if enabled)
+	if gw.coalesce {
+		b.Use(middlewares.NewCoalesce())
+	}
+
 	// Priority 340: Retry (if resilience configured)
 	if gw.config.DefaultMaxRetries > 0 {
 		b.Use(middlewares.NewRetry(gw.config.DefaultMaxRetries, 500*time.Millisecond, 2.0))
 	}
 
 	// Priority 350: Core provider call (always present)
-	b.Use(middlewares.NewProviderCall(gw.router, gw.providers))
+	b.Use(middlewares.NewProviderCall(gw.router, gw.providers).WithEmbeddingBatching(gw.embeddingBatchCfg))
 
 	// Priority 500: Response headers
 	b.Use(middlewares.NewHeaders("nexus"))
@@ -212,6 +303,101 @@ func (gw *Gateway) buildDefaultPipeline() pipeline.Service {
 	return b.Build()
 }
 
+// inputMiddleware returns the middleware that shapes a request before it
+// is routed. It is shared by the default pipeline and the input pipeline
+// that prepares native batch items.
+func (gw *Gateway) inputMiddleware() []pipeline.Middleware {
+	// Priority 140: Prompt templates (prompt_id), rendered before the
+	// guardrails so client-supplied variables are guarded too
+	mws := []pipeline.Middleware{middlewares.NewPromptTemplate(gw.prompts)}
+
+	// Priority 150: Input guardrails (if configured)
+	if gw.guard != nil || gw.guardPolicies != nil {
+		mw := middlewares.NewGuardrail(gw.guard)
+		if gw.guardPolicies != nil {
+			mw = mw.WithPolicies(gw.guardrailPolicies())
+		}
+		mws = append(mws, mw)
+	}
+
+	// Priority 200: Transforms (if configured)
+	if gw.transforms != nil {
+		mws = append(mws, middlewares.NewTransform(gw.transforms))
+	}
+
+	// Priority 250: Alias resolution (if configured)
+	if gw.aliasRegistry != nil {
+		mws = append(mws, middlewares.NewAlias(gw.aliasRegistry))
+	}
+	return mws
+}
+
+// guardrailPolicies selects the guardrail policy named in the requesting
+// tenant's config. A failed tenant lookup fails the request rather than
+// serve it under the default policy, which may be weaker.
+func (gw *Gateway) guardrailPolicies() middlewares.GuardrailPolicies {
+	return middlewares.GuardrailPolicies{
+		Registry: gw.guardPolicies,
+		Resolve: func(ctx context.Context) (string, error) {
+			tenantID := pipeline.TenantID(ctx)
+			if tenantID == "" || gw.tenant == nil {
+				return "", nil
+			}
+			if policy, ok := gw.tenantPolicies.get(tenantID); ok {
+				return policy, nil
+			}
+			t, err := gw.tenant.Get(ctx, tenantID)
+			if err != nil {
+				gw.logger.Error("nexus guardrail policy lookup failed", "tenant_id", tenantID, "error", err)
+				return "", err
+			}
+			var policy string
+			if t != nil {
+				policy = t.Config.GuardrailPolicy
+			}
+			gw.tenantPolicies.put(tenantID, policy)
+			return policy, nil
+		},
+	}
+}
+
+// tenantPolicyTTL is how long a tenant's guardrail policy is reused before
+// its config is read again, so policy changes apply within this delay.
+const tenantPolicyTTL = 30 * time.Second
+
+// maxTenantPolicyEntries bounds the tenant policy cache.
+const maxTenantPolicyEntries = 10000
+
+// tenantPolicyCache caches the guardrail policy named by each tenant.
+type tenantPolicyCache struct {
+	mu      sync.Mutex
+	entries map[string]tenantPolicyEntry
+}
+
+type tenantPolicyEntry struct {
+	policy  string
+	expires time.Time
+}
+
+func (c *tenantPolicyCache) get(tenantID string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[tenantID]
+	if !ok || time.Now().After(e.expires) {
+		return "", false
+	}
+	return e.policy, true
+}
+
+func (c *tenantPolicyCache) put(tenantID, policy string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil || len(c.entries) >= maxTenantPolicyEntries {
+		c.entries = make(map[string]tenantPolicyEntry)
+	}
+	c.entries[tenantID] = tenantPolicyEntry{policy: policy, expires: time.Now().Add(tenantPolicyTTL)}
+}
+
 // Mount registers Nexus HTTP handlers on the given router.
 func (gw *Gateway) Mount(mux Router, basePath ...string) {
 	path := gw.config.BasePath
@@ -250,9 +436,24 @@ func (gw *Gateway) Tenants() tenant.Service { return gw.tenant }
 // Keys returns the API key service.
 func (gw *Gateway) Keys() key.Service { return gw.key }
 
+// Auth returns the authentication provider.
+func (gw *Gateway) Auth() auth.Provider { return gw.auth }
+
 // Usage returns the usage service.
 func (gw *Gateway) Usage() usage.Service { return gw.usage }
 
+// RAG returns the document retriever configured with WithRAG, or nil.
+func (gw *Gateway) RAG() *rag.Retriever { return gw.rag }
+
+// Prompts returns the prompt template registry.
+func (gw *Gateway) Prompts() prompt.Service { return gw.prompts }
+
+// Batches returns the batch service.
+func (gw *Gateway) Batches() batch.Service { return gw.batch }
+
+// Background returns the background response service.
+func (gw *Gateway) Background() background.Service { return gw.background }
+
 // Models returns the model service.
 func (gw *Gateway) Models() model.Service { return gw.model }
 
@@ -270,9 +471,20 @@ func (gw *Gateway) Health(_ context.Context) error {
 	return nil
 }
 
-// Shutdown gracefully stops all services.
-func (gw *Gateway) Shutdown(_ context.Context) error {
+// Shutdown gracefully stops all services. Running background responses
+// are given until ctx ends to finish; the rest are persisted as incomplete.
+func (gw *Gateway) Shutdown(ctx context.Context) error {
 	gw.logger.Info("nexus gateway shutting down")
+	if gw.background != nil {
+		if err := gw.background.Shutdown(ctx); err != nil {
+			gw.logger.Warn("nexus background service shutdown failed", "error", err)
+		}
+	}
+	if gw.batch != nil {
+		if err := gw.batch.Close(); err != nil {
+			gw.logger.Warn("nexus batch service shutdown failed", "error", err)
+		}
+	}
 	if gw.store != nil {
 		return gw.store.Close()
 	}
@@ -48,8 +48,19 @@ func (m *TracingMiddleware) Process(ctx context.Context, req *pipeline.Request,
 	}
 
 	model := ""
-	if req.Completion != nil {
+	switch {
+	case req.Completion != nil:
 		model = req.Completion.Model
+	case req.Embedding != nil:
+		model = req.Embedding.Model
+	case req.Image != nil:
+		model = req.Image.Model
+	case req.Transcription != nil:
+		model = req.Transcription.Model
+	case req.Speech != nil:
+		model = req.Speech.Model
+	case req.Rerank != nil:
+		model = req.Rerank.Model
 	}
 
 	ctx, span := m.tracer.StartSpan(ctx, "nexus.request",
@@ -66,6 +77,9 @@ func (m *TracingMiddleware) Process(ctx context.Context, req *pipeline.Request,
 	if providerName, ok := req.State["provider_name"].(string); ok {
 		span.SetAttribute("nexus.provider.name", providerName)
 	}
+	if providerType, ok := req.State["provider_type"].(string); ok {
+		span.SetAttribute("nexus.provider.type", providerType)
+	}
 	if resp != nil && resp.Completion != nil {
 		span.SetAttribute("nexus.tokens.input", resp.Completion.Usage.PromptTokens)
 		span.SetAttribute("nexus.tokens.output", resp.Completion.Usage.CompletionTokens)
@@ -1,17 +1,23 @@
 package nexus
 
 import (
+	"context"
 	"time"
 
 	"github.com/xraph/nexus/auth"
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/batch"
 	"github.com/xraph/nexus/cache"
 	"github.com/xraph/nexus/guard"
+	"github.com/xraph/nexus/guard/guards"
 	"github.com/xraph/nexus/model"
 	"github.com/xraph/nexus/observability"
 	"github.com/xraph/nexus/pipeline"
 	"github.com/xraph/nexus/pipeline/middlewares"
 	"github.com/xraph/nexus/plugin"
+	"github.com/xraph/nexus/prompt"
 	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/rag"
 	"github.com/xraph/nexus/router"
 	"github.com/xraph/nexus/store"
 	"github.com/xraph/nexus/transform"
@@ -35,7 +41,9 @@ func WithAuth(a auth.Provider) Option {
 	return func(gw *Gateway) { gw.auth = a }
 }
 
-// WithProvider registers an LLM provider.
+// WithProvider registers an LLM provider under its Name(). To register
+// several deployments of one provider type, give each a distinct name with
+// the provider's WithName option.
 func WithProvider(p provider.Provider) Option {
 	return func(gw *Gateway) { gw.providers.Register(p) }
 }
@@ -65,6 +73,57 @@ func WithGuard(g guard.Guard) Option {
 	}
 }
 
+// WithJudgeGuard adds an LLM-as-judge guard that classifies content with
+// model on the provider registered as providerName (e.g. Llama Guard on
+// Ollama). Judge calls go straight to the provider, bypassing the pipeline.
+// configure, if given, tunes the guard (phase, timeout, fail-open, ...).
+func WithJudgeGuard(providerName, model string, configure ...func(*guards.JudgeGuard)) Option {
+	return func(gw *Gateway) {
+		g := guards.NewJudge(gw.providers, providerName, model)
+		for _, fn := range configure {
+			fn(g)
+		}
+		WithGuard(g)(gw)
+	}
+}
+
+// WithGuardrailPolicy adds guards to a named guardrail policy. Tenants select
+// a policy through tenant.Config.GuardrailPolicy; tenants without one (or
+// naming an unknown policy) get the default policy, and failing that the
+// guards registered with WithGuard. Use guard.WithPhase to restrict a
+// guard to input or output within a policy.
+func WithGuardrailPolicy(name string, guards ...guard.Guard) Option {
+	return func(gw *Gateway) {
+		if gw.guardPolicies == nil {
+			gw.guardPolicies = guard.NewPolicyRegistry()
+		}
+		gw.guardPolicies.Register(name, guards...)
+	}
+}
+
+// WithDefaultGuardrailPolicy names the policy applied to tenants that have
+// no guardrail policy of their own.
+func WithDefaultGuardrailPolicy(name string) Option {
+	return func(gw *Gateway) {
+		if gw.guardPolicies == nil {
+			gw.guardPolicies = guard.NewPolicyRegistry()
+		}
+		gw.guardPolicies.SetDefault(name)
+	}
+}
+
+// WithStreamGuardStrategy sets how output guards are applied to streamed
+// responses. The default, guard.StrategySlidingWindow, holds back windowSize
+// bytes of text so patterns split across chunks are still caught; pass 0
+// for guard.DefaultWindowSize. Streaming guardrails are installed whenever
+// an output-phase guard is registered.
+func WithStreamGuardStrategy(strategy guard.StreamStrategy, windowSize int) Option {
+	return func(gw *Gateway) {
+		gw.streamGuardStrategy = strategy
+		gw.streamGuardWindow = windowSize
+	}
+}
+
 // WithMiddleware adds a custom middleware to the pipeline.
 func WithMiddleware(m pipeline.Middleware) Option {
 	return func(gw *Gateway) {
@@ -131,6 +190,118 @@ func WithTransforms(r *transform.Registry) Option {
 	return func(gw *Gateway) { gw.transforms = r }
 }
 
+// WithStructuredOutputValidation validates json_schema responses, repairing
+// malformed JSON and re-asking the model up to maxRetries times. Schema
+// instructions are added to the prompt for providers without native
+// json_schema support. Apply it after WithTransforms, which replaces the
+// transform registry.
+func WithStructuredOutputValidation(maxRetries int) Option {
+	return func(gw *Gateway) {
+		if gw.transforms == nil {
+			gw.transforms = transform.NewRegistry()
+		}
+		gw.transforms.Register(transform.NewStructuredOutput(gw.providers).WithMaxRetries(maxRetries))
+	}
+}
+
+// WithTokenCounter replaces the built-in token counter used for
+// context-window management.
+func WithTokenCounter(c model.RequestTokenCounter) Option {
+	return func(gw *Gateway) { gw.tokenCounter = c }
+}
+
+// WithContextOverflow sets how requests that do not fit their model's
+// context window are handled: model.OverflowError rejects them, and
+// model.OverflowTruncateOldest / model.OverflowTruncateMiddle drop whole
+// turns until they fit. By default they are passed to the provider.
+func WithContextOverflow(strategy model.OverflowStrategy) Option {
+	return func(gw *Gateway) {
+		gw.tokenCounterOpts = append(gw.tokenCounterOpts, model.WithOverflowStrategy(strategy))
+	}
+}
+
+// WithTokenizer registers an exact tokenizer for an OpenAI encoding
+// (model.EncodingCL100K, model.EncodingO200K), e.g. one loaded with
+// model.LoadBPEFile. Without it those models are counted approximately.
+func WithTokenizer(encoding string, t model.Tokenizer) Option {
+	return func(gw *Gateway) {
+		gw.tokenCounterOpts = append(gw.tokenCounterOpts, model.WithEncoding(encoding, t))
+	}
+}
+
+// WithContextSummarizer summarizes turns dropped by context truncation with
+// model on the provider registered as providerName, keeping the summary in
+// their place. Use a small, cheap model.
+func WithContextSummarizer(providerName, modelID string) Option {
+	return func(gw *Gateway) {
+		gw.contextSummarizer = middlewares.NewModelSummarizer(gw.providers, providerName, modelID)
+	}
+}
+
+// WithRAG enables retrieval-augmented generation backed by the gateway's own
+// embeddings path: documents ingested through Gateway.RAG (or the admin API)
+// are embedded with embeddingModel via Engine.Embed and stored in index
+// (rag.NewMemoryIndex when nil; use a store's VectorIndex for persistence).
+// Retrieval is scoped to the requesting tenant. configure customizes the
+// registered transform. Apply it after WithTransforms, which replaces the
+// transform registry.
+func WithRAG(embeddingModel string, index rag.Index, configure ...func(*transform.RAGTransform)) Option {
+	return func(gw *Gateway) {
+		if index == nil {
+			index = rag.NewMemoryIndex()
+		}
+		embedder := rag.EmbedderFunc(func(ctx context.Context, texts []string) ([][]float64, error) {
+			if gw.engine == nil {
+				return nil, ErrProviderNotFound
+			}
+			resp, err := gw.engine.Embed(ctx, &provider.EmbeddingRequest{Model: embeddingModel, Input: texts})
+			if err != nil {
+				return nil, err
+			}
+			return resp.Embeddings, nil
+		})
+		gw.rag = rag.NewRetriever(embedder, index)
+
+		t := transform.NewRAG(gw.rag)
+		for _, fn := range configure {
+			fn(t)
+		}
+		if gw.transforms == nil {
+			gw.transforms = transform.NewRegistry()
+		}
+		gw.transforms.Register(t)
+	}
+}
+
+// WithPrompts replaces the store-backed prompt template registry used to
+// render requests that reference a prompt_id.
+func WithPrompts(s prompt.Service) Option {
+	return func(gw *Gateway) { gw.prompts = s }
+}
+
+// WithBatches replaces the default batch service.
+func WithBatches(s batch.Service) Option {
+	return func(gw *Gateway) { gw.batch = s }
+}
+
+// WithBatchOptions configures the default batch service, e.g. its
+// worker-pool concurrency or rate limit. The pool inherits WithRateLimit
+// unless batch.WithRateLimit overrides it.
+func WithBatchOptions(opts ...batch.Option) Option {
+	return func(gw *Gateway) { gw.batchOpts = append(gw.batchOpts, opts...) }
+}
+
+// WithBackground replaces the default background response service.
+func WithBackground(s background.Service) Option {
+	return func(gw *Gateway) { gw.background = s }
+}
+
+// WithBackgroundOptions configures the default background service, e.g.
+// its concurrency or per-response timeout.
+func WithBackgroundOptions(opts ...background.Option) Option {
+	return func(gw *Gateway) { gw.backgroundOpts = append(gw.backgroundOpts, opts...) }
+}
+
 // WithHealthTracker sets the provider health tracker.
 func WithHealthTracker(h provider.HealthTracker) Option {
 	return func(gw *Gateway) { gw.healthTrack = h }
@@ -155,6 +326,13 @@ func WithStreamLifecycleConfig(cfg middlewares.StreamLifecycleConfig) Option {
 	return func(gw *Gateway) { gw.streamLifecycleCfg = cfg }
 }
 
+// WithEmbeddingBatchConfig tunes how embedding requests larger than a
+// provider's per-call limits are split: chunk concurrency and per-chunk
+// retries. The zero value uses the defaults (4 concurrent, 2 retries).
+func WithEmbeddingBatchConfig(cfg middlewares.EmbeddingBatchConfig) Option {
+	return func(gw *Gateway) { gw.embeddingBatchCfg = cfg }
+}
+
 // WithStreamCache enables record-and-replay caching for streaming responses
 // using the given backend and options. Streams that produce a cache hit are
 // served from the recorded frames; misses pass through and are recorded for
@@ -166,6 +344,14 @@ func WithStreamCache(sc cache.StreamCache, opts cache.StreamCacheOptions) Option
 	}
 }
 
+// WithRequestCoalescing deduplicates concurrent identical requests so they
+// share a single provider call. Non-streaming requests wait for the first
+// caller's response; streaming requests subscribe to the first caller's
+// upstream stream. Cache hits are served before coalescing is considered.
+func WithRequestCoalescing() Option {
+	return func(gw *Gateway) { gw.coalesce = true }
+}
+
 // WithTenantAlias registers a per-tenant model alias override.
 func WithTenantAlias(tenantID, name string, targets ...model.AliasTarget) Option {
 	return func(gw *Gateway) {
@@ -84,6 +84,62 @@ func (p *pipelineImpl) ExecuteEmbedding(ctx context.Context, req *provider.Embed
 	return resp.Embedding, nil
 }
 
+func (p *pipelineImpl) ExecuteImage(ctx context.Context, req *provider.ImageRequest) (*provider.ImageResponse, error) {
+	pReq := &Request{
+		Image: req,
+		Type:  RequestImage,
+		State: make(map[string]any),
+	}
+
+	resp, err := p.run(ctx, pReq, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Image, nil
+}
+
+func (p *pipelineImpl) ExecuteTranscription(ctx context.Context, req *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error) {
+	pReq := &Request{
+		Transcription: req,
+		Type:          RequestTranscription,
+		State:         make(map[string]any),
+	}
+
+	resp, err := p.run(ctx, pReq, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Transcription, nil
+}
+
+func (p *pipelineImpl) ExecuteSpeech(ctx context.Context, req *provider.SpeechRequest) (*provider.SpeechResponse, error) {
+	pReq := &Request{
+		Speech: req,
+		Type:   RequestSpeech,
+		State:  make(map[string]any),
+	}
+
+	resp, err := p.run(ctx, pReq, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Speech, nil
+}
+
+func (p *pipelineImpl) ExecuteRerank(ctx context.Context, req *provider.RerankRequest) (*provider.RerankResponse, error) {
+	pReq := &Request{
+		Rerank: req,
+		Type:   RequestRerank,
+		State:  make(map[string]any),
+	}
+
+	resp, err := p.run(ctx, pReq, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Rerank, nil
+}
+
 // run recursively calls each middleware in priority order.
 func (p *pipelineImpl) run(ctx context.Context, req *Request, idx int) (*Response, error) {
 	if idx >= len(p.middlewares) {
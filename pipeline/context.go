@@ -14,6 +14,7 @@ const (
 	ctxProvider  ctxKey = "nexus.provider"
 	ctxCacheHit  ctxKey = "nexus.cache_hit"
 	ctxStartTime ctxKey = "nexus.start_time"
+	ctxBatchID   ctxKey = "nexus.batch_id"
 )
 
 // TenantID returns the tenant ID from context.
@@ -99,3 +100,17 @@ func StartTime(ctx context.Context) time.Time {
 func WithStartTime(ctx context.Context, t time.Time) context.Context {
 	return context.WithValue(ctx, ctxStartTime, t)
 }
+
+// BatchID returns the batch job the request runs for, if any.
+func BatchID(ctx context.Context) string {
+	v, ok := ctx.Value(ctxBatchID).(string)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
+// WithBatchID marks the request as an item of a batch job.
+func WithBatchID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxBatchID, id)
+}
@@ -30,9 +30,13 @@ type NextFunc func(ctx context.Context) (*Response, error)
 
 // Request wraps the unified request with pipeline metadata.
 type Request struct {
-	Completion *provider.CompletionRequest
-	Embedding  *provider.EmbeddingRequest
-	Type       RequestType // "completion", "stream", "embedding"
+	Completion    *provider.CompletionRequest
+	Embedding     *provider.EmbeddingRequest
+	Image         *provider.ImageRequest
+	Transcription *provider.TranscriptionRequest
+	Speech        *provider.SpeechRequest
+	Rerank        *provider.RerankRequest
+	Type          RequestType // "completion", "stream", "embedding", "image", "transcription", "speech", "rerank"
 
 	// Mutable state middleware can read/write.
 	State map[string]any
@@ -42,14 +46,22 @@ type Request struct {
 type RequestType string
 
 const (
-	RequestCompletion RequestType = "completion"
-	RequestStream     RequestType = "stream"
-	RequestEmbedding  RequestType = "embedding"
+	RequestCompletion    RequestType = "completion"
+	RequestStream        RequestType = "stream"
+	RequestEmbedding     RequestType = "embedding"
+	RequestImage         RequestType = "image"
+	RequestTranscription RequestType = "transcription"
+	RequestSpeech        RequestType = "speech"
+	RequestRerank        RequestType = "rerank"
 )
 
 // Response wraps the unified response.
 type Response struct {
-	Completion *provider.CompletionResponse
-	Stream     provider.Stream
-	Embedding  *provider.EmbeddingResponse
+	Completion    *provider.CompletionResponse
+	Stream        provider.Stream
+	Embedding     *provider.EmbeddingResponse
+	Image         *provider.ImageResponse
+	Transcription *provider.TranscriptionResponse
+	Speech        *provider.SpeechResponse
+	Rerank        *provider.RerankResponse
 }
@@ -24,7 +24,13 @@ func (m *AliasMiddleware) Name() string  { return "alias" }
 func (m *AliasMiddleware) Priority() int { return 250 } // Before routing (350)
 
 func (m *AliasMiddleware) Process(ctx context.Context, req *pipeline.Request, next pipeline.NextFunc) (*pipeline.Response, error) {
-	if m.aliases == nil || req.Completion == nil {
+	if m.aliases == nil {
+		return next(ctx)
+	}
+	if req.Completion == nil {
+		if req.Embedding != nil {
+			m.resolveEmbedding(ctx, req)
+		}
 		return next(ctx)
 	}
 
@@ -52,6 +58,30 @@ func (m *AliasMiddleware) Process(ctx context.Context, req *pipeline.Request, ne
 	return next(ctx)
 }
 
+// resolveEmbedding rewrites an aliased embedding model. Unlike completions,
+// the target's provider is pinned: vectors from different providers are not
+// comparable, so an embedding alias must not drift between them.
+func (m *AliasMiddleware) resolveEmbedding(ctx context.Context, req *pipeline.Request) {
+	emb := req.Embedding
+	targets, err := m.aliases.Resolve(ctx, emb.Model, pipeline.TenantID(ctx))
+	if err != nil || len(targets) == 0 {
+		return
+	}
+	target := selectTarget(targets)
+	if target == nil {
+		return
+	}
+
+	req.State["original_model"] = emb.Model
+	req.State["alias_target_provider"] = target.Provider
+	req.State["alias_target_model"] = target.Model
+
+	emb.Model = target.Model
+	if emb.Provider == "" {
+		emb.Provider = target.Provider
+	}
+}
+
 // selectTarget picks a target based on weight. If no weights, uniform random.
 func selectTarget(targets []model.AliasTarget) *model.AliasTarget {
 	if len(targets) == 0 {
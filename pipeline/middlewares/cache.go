@@ -73,6 +73,8 @@ func (m *CacheMiddleware) Process(ctx context.Context, req *pipeline.Request, ne
 	cached, err := m.cache.Get(ctx, key)
 	if err == nil && cached != nil {
 		cached.Cached = true
+		// Served without a provider call.
+		cached.Cost = 0
 		return &pipeline.Response{Completion: cached}, nil
 	}
 
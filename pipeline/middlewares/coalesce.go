@@ -0,0 +1,439 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/xraph/nexus/cache"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/provider"
+)
+
+// StateKeyCoalesced is the pipeline.Request.State key set to true when a
+// request was served by another caller's in-flight provider call instead of
+// its own. UsageMiddleware records such requests as zero-cost shared hits.
+const StateKeyCoalesced = "coalesce.shared"
+
+// CoalesceMiddleware deduplicates identical in-flight requests so that
+// concurrent callers share a single provider call.
+//
+//   - Requests are keyed on coalesceKey: cache.Key plus everything else that
+//     shapes the answer (system prompt, response format, tool choice,
+//     thinking) and the tenant, so tenants never share a response.
+//   - For non-streaming requests the first caller (the
+//     leader) runs the rest of the pipeline; callers that arrive while it is
+//     in flight wait for its result and receive their own copy of the
+//     response. Only the leader is billed; waiters are marked with
+//     StateKeyCoalesced and recorded as zero-cost shared hits. The call
+//     runs detached from the leader's cancellation and is cancelled only
+//     once every caller has gone, so a leader that disconnects does not
+//     fail its waiters. If the call itself fails, every waiter receives
+//     the same error.
+//   - For streaming requests the leader's upstream
+//     provider.Stream is fanned out to every subscriber: frames are recorded
+//     as they arrive (the same StreamFrame buffer the stream cache uses), so
+//     late joiners replay what they missed and then follow the live stream.
+//     The upstream is dialed detached from the leader's cancellation and
+//     closed once the last subscriber closes.
+//
+// Position: priority 285 — after cache (280) so hits never coalesce, and
+// before retry (340) so one retried upstream call serves every waiter.
+type CoalesceMiddleware struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+	streams map[string]*sharedStream
+}
+
+// NewCoalesce creates a request coalescing middleware.
+func NewCoalesce() *CoalesceMiddleware {
+	return &CoalesceMiddleware{
+		flights: make(map[string]*flight),
+		streams: make(map[string]*sharedStream),
+	}
+}
+
+func (m *CoalesceMiddleware) Name() string  { return "coalesce" }
+func (m *CoalesceMiddleware) Priority() int { return 285 } // After cache, before retry
+
+// flight is a single in-progress non-streaming call.
+type flight struct {
+	done         chan struct{}
+	resp         *provider.CompletionResponse
+	providerName string
+	providerType string
+	err          error
+
+	refs   int // callers still waiting, the leader included; guarded by mu
+	cancel context.CancelFunc
+}
+
+func (m *CoalesceMiddleware) Process(ctx context.Context, req *pipeline.Request, next pipeline.NextFunc) (*pipeline.Response, error) {
+	if req.Completion == nil {
+		return next(ctx)
+	}
+	if req.Type == pipeline.RequestStream {
+		return m.processStream(ctx, req, next)
+	}
+
+	key := coalesceKey(ctx, req.Completion, false)
+	if key == "" {
+		return next(ctx)
+	}
+
+	m.mu.Lock()
+	if f, ok := m.flights[key]; ok {
+		f.refs++
+		m.mu.Unlock()
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			m.leave(key, f)
+			return nil, ctx.Err()
+		}
+		if f.err != nil {
+			return nil, f.err
+		}
+		req.State[StateKeyCoalesced] = true
+		if f.providerName != "" {
+			req.State["provider_name"] = f.providerName
+			req.State["provider_type"] = f.providerType
+		}
+		return &pipeline.Response{Completion: cloneCompletion(f.resp)}, nil
+	}
+	callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	f := &flight{done: make(chan struct{}), refs: 1, cancel: cancel}
+	m.flights[key] = f
+	m.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, func() { m.leave(key, f) })
+	resp, err := next(callCtx)
+	stop()
+
+	f.err = err
+	if err == nil && (resp == nil || resp.Completion == nil) {
+		// Nothing shareable — let waiters run their own call next time
+		// rather than handing them an empty response.
+		f.err = errors.New("nexus: coalesced request produced no response")
+	}
+	if f.err == nil {
+		f.resp = cloneCompletion(resp.Completion)
+		if providerName, ok := req.State["provider_name"].(string); ok {
+			f.providerName = providerName
+			f.providerType, _ = req.State["provider_type"].(string)
+		}
+	}
+
+	m.mu.Lock()
+	if m.flights[key] == f {
+		delete(m.flights, key)
+	}
+	m.mu.Unlock()
+	close(f.done)
+
+	return resp, err
+}
+
+// leave drops a caller that gave up on f. The call is cancelled once no
+// caller is left, and later callers start a new one.
+func (m *CoalesceMiddleware) leave(key string, f *flight) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f.refs--
+	if f.refs > 0 {
+		return
+	}
+	if m.flights[key] == f {
+		delete(m.flights, key)
+	}
+	f.cancel()
+}
+
+func (m *CoalesceMiddleware) processStream(ctx context.Context, req *pipeline.Request, next pipeline.NextFunc) (*pipeline.Response, error) {
+	key := coalesceKey(ctx, req.Completion, true)
+	if key == "" {
+		return next(ctx)
+	}
+
+	for {
+		m.mu.Lock()
+		ss, ok := m.streams[key]
+		if !ok {
+			break // leader; m.mu is still held
+		}
+		m.mu.Unlock()
+
+		// Wait for the leader to finish dialing upstream.
+		select {
+		case <-ss.ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if ss.dialErr != nil {
+			return nil, ss.dialErr
+		}
+		if sub := ss.subscribe(); sub != nil {
+			req.State[StateKeyCoalesced] = true
+			if ss.providerName != "" {
+				req.State["provider_name"] = ss.providerName
+				req.State["provider_type"] = ss.providerType
+			}
+			return &pipeline.Response{Stream: sub}, nil
+		}
+		// The shared stream already finished; drop it and start anew.
+		m.mu.Lock()
+		if m.streams[key] == ss {
+			delete(m.streams, key)
+		}
+		m.mu.Unlock()
+	}
+
+	// Register the stream before dialing, so callers that arrive while the
+	// leader connects wait for it instead of dialing themselves.
+	ss := &sharedStream{ready: make(chan struct{})}
+	m.streams[key] = ss
+	m.mu.Unlock()
+
+	unregister := func() {
+		m.mu.Lock()
+		if m.streams[key] == ss {
+			delete(m.streams, key)
+		}
+		m.mu.Unlock()
+	}
+
+	// Dial detached from the leader's cancellation: the stream is shared, and
+	// subscriber Close (the last one closes upstream) bounds its life.
+	resp, err := next(context.WithoutCancel(ctx))
+	if err != nil || resp == nil || resp.Stream == nil {
+		ss.dialErr = err
+		if ss.dialErr == nil {
+			ss.dialErr = errors.New("nexus: coalesced request produced no stream")
+		}
+		unregister()
+		close(ss.ready)
+		return resp, err
+	}
+
+	ss.upstream = resp.Stream
+	ss.startAt = time.Now()
+	if providerName, ok := req.State["provider_name"].(string); ok {
+		ss.providerName = providerName
+		ss.providerType, _ = req.State["provider_type"].(string)
+	}
+	ss.release = unregister
+	sub := ss.subscribe()
+	close(ss.ready)
+
+	resp.Stream = sub
+	return resp, nil
+}
+
+// coalesceKey identifies requests that may share one provider call. It
+// extends cache.Key (model, messages, sampling parameters, tools) with the
+// fields cache.Key leaves out and with the tenant, since a coalesced
+// response is handed to every caller unchanged. It returns "" when the
+// request cannot be keyed.
+func coalesceKey(ctx context.Context, req *provider.CompletionRequest, stream bool) string {
+	base := cache.Key(req)
+	if base == "" {
+		return ""
+	}
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "base:%s\nstream:%t\ntenant:%s\nprovider:%s\nsystem:%s\ncached_content:%s\n",
+		base, stream, pipeline.TenantID(ctx), req.Provider, req.System, req.CachedContent)
+	for _, field := range []struct {
+		name  string
+		value any
+	}{
+		{"tools", req.Tools},
+		{"tool_choice", req.ToolChoice},
+		{"response_format", req.ResponseFormat},
+		{"thinking", req.Thinking},
+	} {
+		data, err := json.Marshal(field.value)
+		if err != nil {
+			return ""
+		}
+		_, _ = fmt.Fprintf(h, "%s:%s\n", field.name, data)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// sharedStream fans a single upstream provider.Stream out to several
+// subscribers. Frames are appended to an ordered buffer as they are read
+// from upstream; each subscriber walks the buffer at its own pace and
+// whichever subscriber reaches the end first pulls the next frame.
+type sharedStream struct {
+	// ready is closed once the leader has dialed upstream; dialErr is its
+	// failure, shared with every caller that waited. The fields below are
+	// set before ready is closed.
+	ready   chan struct{}
+	dialErr error
+
+	upstream     provider.Stream
+	providerName string
+	providerType string
+	startAt      time.Time
+	release      func()
+
+	pullMu sync.Mutex // serialises upstream.Next
+
+	mu     sync.Mutex
+	frames []cache.StreamFrame
+	err    error // terminal upstream error (io.EOF on clean completion)
+	subs   int
+	closed bool
+}
+
+// subscribe registers a new subscriber. It returns nil once the upstream
+// has been closed, since there is nothing left to fan out.
+func (s *sharedStream) subscribe() *subscriberStream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.subs++
+	return &subscriberStream{shared: s}
+}
+
+// frame returns the frame at idx, pulling from upstream when idx is past
+// the end of the buffer.
+func (s *sharedStream) frame(ctx context.Context, idx int) (*provider.StreamChunk, error) {
+	if chunk, ok, err := s.buffered(idx); ok {
+		return chunk, err
+	}
+
+	s.pullMu.Lock()
+	defer s.pullMu.Unlock()
+
+	// Another subscriber may have pulled while we waited for pullMu.
+	if chunk, ok, err := s.buffered(idx); ok {
+		return chunk, err
+	}
+
+	chunk, err := s.upstream.Next(ctx)
+	if err != nil {
+		// A subscriber's own cancellation must not end the stream for the
+		// others; only upstream failures are terminal.
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+		if s.release != nil {
+			s.release()
+		}
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.frames = append(s.frames, cache.StreamFrame{
+		Chunk:    chunk,
+		OffsetMs: time.Since(s.startAt).Milliseconds(),
+	})
+	s.mu.Unlock()
+	return chunk, nil
+}
+
+// buffered reports the frame at idx (or the terminal error) when it is
+// already known without touching upstream.
+func (s *sharedStream) buffered(idx int) (chunk *provider.StreamChunk, ok bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if idx < len(s.frames) {
+		return s.frames[idx].Chunk, true, nil
+	}
+	if s.err != nil {
+		return nil, true, s.err
+	}
+	return nil, false, nil
+}
+
+// unsubscribe drops one subscriber and closes upstream after the last one.
+func (s *sharedStream) unsubscribe() error {
+	s.mu.Lock()
+	s.subs--
+	last := s.subs <= 0 && !s.closed
+	if last {
+		s.closed = true
+	}
+	s.mu.Unlock()
+
+	if !last {
+		return nil
+	}
+	if s.release != nil {
+		s.release()
+	}
+	return s.upstream.Close()
+}
+
+// subscriberStream is one caller's view of a sharedStream.
+type subscriberStream struct {
+	shared *sharedStream
+	idx    int
+	once   sync.Once
+}
+
+func (s *subscriberStream) Next(ctx context.Context) (*provider.StreamChunk, error) {
+	chunk, err := s.shared.frame(ctx, s.idx)
+	if err != nil {
+		return nil, err
+	}
+	s.idx++
+	return chunk, nil
+}
+
+func (s *subscriberStream) Close() error {
+	var err error
+	s.once.Do(func() { err = s.shared.unsubscribe() })
+	return err
+}
+
+// Usage returns the upstream usage. Every subscriber sees the full count;
+// UsageMiddleware bills only the leader.
+func (s *subscriberStream) Usage() *provider.Usage { return s.shared.upstream.Usage() }
+
+// cloneCompletion returns a copy of resp that callers can mutate without
+// affecting other coalesced callers: the slices middlewares rewrite in
+// place (choices, tool calls, content parts, citations) are copied.
+func cloneCompletion(resp *provider.CompletionResponse) *provider.CompletionResponse {
+	if resp == nil {
+		return nil
+	}
+	cp := *resp
+	if resp.Choices != nil {
+		cp.Choices = make([]provider.Choice, len(resp.Choices))
+		for i, c := range resp.Choices {
+			c.Message = cloneMessage(c.Message)
+			cp.Choices[i] = c
+		}
+	}
+	cp.Citations = append([]provider.Citation(nil), resp.Citations...)
+	if resp.State != nil {
+		cp.State = make(map[string]any, len(resp.State))
+		for k, v := range resp.State {
+			cp.State[k] = v
+		}
+	}
+	return &cp
+}
+
+func cloneMessage(m provider.Message) provider.Message {
+	m.ToolCalls = append([]provider.ToolCall(nil), m.ToolCalls...)
+	switch c := m.Content.(type) {
+	case []provider.ContentPart:
+		m.Content = append([]provider.ContentPart(nil), c...)
+	case []any:
+		m.Content = append([]any(nil), c...)
+	}
+	return m
+}
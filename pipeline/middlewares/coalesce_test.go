@@ -0,0 +1,401 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/testutil"
+)
+
+func coalesceRequest(stream bool) *pipeline.Request {
+	typ := pipeline.RequestCompletion
+	if stream {
+		typ = pipeline.RequestStream
+	}
+	return &pipeline.Request{
+		Completion: &provider.CompletionRequest{
+			Model:    "m",
+			Stream:   stream,
+			Messages: []provider.Message{{Role: "user", Content: "hi"}},
+		},
+		Type:  typ,
+		State: map[string]any{},
+	}
+}
+
+func TestCoalesceMiddleware_SharesInFlightCompletion(t *testing.T) {
+	t.Parallel()
+
+	mw := middlewares.NewCoalesce()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	next := func(_ context.Context) (*pipeline.Response, error) {
+		calls.Add(1)
+		<-release
+		return &pipeline.Response{Completion: &provider.CompletionResponse{
+			ID: "resp-1",
+			Choices: []provider.Choice{{Message: provider.Message{
+				Role:      "assistant",
+				Content:   []provider.ContentPart{{Type: "text", Text: "call 555-0100"}},
+				ToolCalls: []provider.ToolCall{{ID: "c1", Function: provider.ToolCallFunc{Name: "f", Arguments: `{"ssn":"123"}`}}},
+			}}},
+			Usage: provider.Usage{PromptTokens: 3, CompletionTokens: 4, TotalTokens: 7},
+		}}, nil
+	}
+
+	const callers = 5
+	reqs := make([]*pipeline.Request, callers)
+	resps := make([]*pipeline.Response, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		reqs[i] = coalesceRequest(false)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := mw.Process(context.Background(), reqs[i], next)
+			if err != nil {
+				t.Errorf("caller %d: %v", i, err)
+				return
+			}
+			resps[i] = resp
+		}(i)
+	}
+
+	// Give every caller time to join the flight before releasing it.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("upstream calls = %d, want 1", got)
+	}
+
+	shared := 0
+	for i, resp := range resps {
+		if resp == nil || resp.Completion == nil {
+			t.Fatalf("caller %d: nil response", i)
+		}
+		if resp.Completion.Usage.TotalTokens != 7 {
+			t.Errorf("caller %d: total tokens = %d, want 7", i, resp.Completion.Usage.TotalTokens)
+		}
+		if reqs[i].State[middlewares.StateKeyCoalesced] == true {
+			shared++
+		}
+	}
+	if shared != callers-1 {
+		t.Errorf("coalesced callers = %d, want %d", shared, callers-1)
+	}
+
+	// Copies must be independent, down to tool calls and content parts
+	// that redaction rewrites in place.
+	resps[0].Completion.ID = "mutated"
+	msg := &resps[0].Completion.Choices[0].Message
+	msg.ToolCalls[0].Function.Arguments = "[REDACTED]"
+	msg.Content.([]provider.ContentPart)[0].Text = "[REDACTED]"
+	for i := 1; i < callers; i++ {
+		other := resps[i].Completion
+		if other.ID != "resp-1" ||
+			other.Choices[0].Message.ToolCalls[0].Function.Arguments != `{"ssn":"123"}` ||
+			other.Choices[0].Message.Content.([]provider.ContentPart)[0].Text != "call 555-0100" {
+			t.Fatalf("caller %d saw mutation from caller 0", i)
+		}
+	}
+}
+
+func TestCoalesceMiddleware_KeepsTenantsAndPromptsApart(t *testing.T) {
+	t.Parallel()
+
+	mw := middlewares.NewCoalesce()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	next := func(_ context.Context) (*pipeline.Response, error) {
+		calls.Add(1)
+		<-release
+		return &pipeline.Response{Completion: &provider.CompletionResponse{ID: "r"}}, nil
+	}
+
+	acme := pipeline.WithTenantID(context.Background(), "acme")
+	globex := pipeline.WithTenantID(context.Background(), "globex")
+	withSystem := coalesceRequest(false)
+	withSystem.Completion.System = "answer in French"
+	withSchema := coalesceRequest(false)
+	withSchema.Completion.ResponseFormat = &provider.ResponseFormat{Type: "json_object"}
+
+	var wg sync.WaitGroup
+	for _, c := range []struct {
+		ctx context.Context
+		req *pipeline.Request
+	}{
+		{acme, coalesceRequest(false)},
+		{globex, coalesceRequest(false)},
+		{acme, withSystem},
+		{acme, withSchema},
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := mw.Process(c.ctx, c.req, next); err != nil {
+				t.Errorf("process: %v", err)
+			}
+			if c.req.State[middlewares.StateKeyCoalesced] == true {
+				t.Error("request was coalesced with a different tenant or prompt")
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 4 {
+		t.Fatalf("upstream calls = %d, want 4", got)
+	}
+}
+
+func TestCoalesceMiddleware_PropagatesLeaderError(t *testing.T) {
+	t.Parallel()
+
+	mw := middlewares.NewCoalesce()
+	boom := errors.New("upstream down")
+	release := make(chan struct{})
+	next := func(_ context.Context) (*pipeline.Response, error) {
+		<-release
+		return nil, boom
+	}
+
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := mw.Process(context.Background(), coalesceRequest(false), next)
+			errs <- err
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	for range 2 {
+		if err := <-errs; !errors.Is(err, boom) {
+			t.Fatalf("err = %v, want %v", err, boom)
+		}
+	}
+}
+
+func TestCoalesceMiddleware_FansOutStream(t *testing.T) {
+	t.Parallel()
+
+	chunks := []*provider.StreamChunk{
+		{Delta: provider.Delta{Content: "hello"}},
+		{Delta: provider.Delta{Content: " world"}, FinishReason: "stop"},
+	}
+	upstream := testutil.NewFakeStream(chunks, &provider.Usage{TotalTokens: 9})
+
+	mw := middlewares.NewCoalesce()
+	var calls atomic.Int32
+	next := func(_ context.Context) (*pipeline.Response, error) {
+		calls.Add(1)
+		return &pipeline.Response{Stream: upstream}, nil
+	}
+
+	first, err := mw.Process(context.Background(), coalesceRequest(true), next)
+	if err != nil {
+		t.Fatalf("first: %v", err)
+	}
+
+	// Leader reads one frame before the second subscriber joins.
+	c, err := first.Stream.Next(context.Background())
+	if err != nil || c.Delta.Content != "hello" {
+		t.Fatalf("first chunk = %+v, %v", c, err)
+	}
+
+	secondReq := coalesceRequest(true)
+	second, err := mw.Process(context.Background(), secondReq, next)
+	if err != nil {
+		t.Fatalf("second: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("upstream calls = %d, want 1", calls.Load())
+	}
+	if secondReq.State[middlewares.StateKeyCoalesced] != true {
+		t.Fatal("second request not marked as coalesced")
+	}
+
+	drain := func(s provider.Stream) string {
+		var out string
+		for {
+			c, err := s.Next(context.Background())
+			if errors.Is(err, io.EOF) {
+				return out
+			}
+			if err != nil {
+				t.Fatalf("drain: %v", err)
+			}
+			out += c.Delta.Content
+		}
+	}
+
+	if got := drain(second.Stream); got != "hello world" {
+		t.Errorf("late subscriber got %q, want %q", got, "hello world")
+	}
+	if got := drain(first.Stream); got != " world" {
+		t.Errorf("leader got %q, want %q", got, " world")
+	}
+	if u := second.Stream.Usage(); u == nil || u.TotalTokens != 9 {
+		t.Errorf("subscriber usage = %+v, want 9 total tokens", u)
+	}
+
+	_ = first.Stream.Close()
+	if upstream.Closed() {
+		t.Fatal("upstream closed while a subscriber is still open")
+	}
+	_ = second.Stream.Close()
+	if !upstream.Closed() {
+		t.Fatal("upstream not closed after last subscriber closed")
+	}
+}
+
+func TestCoalesceMiddleware_SharesStreamDial(t *testing.T) {
+	t.Parallel()
+
+	mw := middlewares.NewCoalesce()
+	upstream := testutil.NewFakeStream([]*provider.StreamChunk{{Delta: provider.Delta{Content: "hi"}}}, nil)
+	var calls atomic.Int32
+	dialed := make(chan struct{})
+	next := func(_ context.Context) (*pipeline.Response, error) {
+		calls.Add(1)
+		<-dialed // slow upstream connect
+		return &pipeline.Response{Stream: upstream}, nil
+	}
+
+	const callers = 4
+	streams := make([]provider.Stream, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := mw.Process(context.Background(), coalesceRequest(true), next)
+			if err != nil {
+				t.Errorf("caller %d: %v", i, err)
+				return
+			}
+			streams[i] = resp.Stream
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(dialed)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("upstream dials = %d, want 1", got)
+	}
+	for i, s := range streams {
+		if c, err := s.Next(context.Background()); err != nil || c.Delta.Content != "hi" {
+			t.Fatalf("caller %d chunk = %+v, %v", i, c, err)
+		}
+		_ = s.Close()
+	}
+	if !upstream.Closed() {
+		t.Fatal("upstream not closed after every subscriber closed")
+	}
+}
+
+func TestCoalesceMiddleware_BillsOnlyTheLeader(t *testing.T) {
+	t.Parallel()
+
+	rec := newRecordingUsage()
+	usageMW := middlewares.NewUsage(rec)
+	mw := middlewares.NewCoalesce()
+	release := make(chan struct{})
+	next := func(_ context.Context) (*pipeline.Response, error) {
+		<-release
+		return &pipeline.Response{Completion: &provider.CompletionResponse{
+			Usage: provider.Usage{PromptTokens: 3, CompletionTokens: 4, TotalTokens: 7},
+			Cost:  0.5,
+		}}, nil
+	}
+
+	const callers = 3
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := coalesceRequest(false)
+			_, err := usageMW.Process(context.Background(), req, func(ctx context.Context) (*pipeline.Response, error) {
+				return mw.Process(ctx, req, next)
+			})
+			if err != nil {
+				t.Errorf("process: %v", err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for range callers {
+		<-rec.done
+	}
+
+	var billed, shared int
+	for _, r := range rec.records {
+		switch {
+		case r.Cached && r.CostUSD == 0:
+			shared++
+		case !r.Cached && r.CostUSD == 0.5:
+			billed++
+		}
+	}
+	if billed != 1 || shared != callers-1 {
+		t.Fatalf("billed = %d, shared = %d, want 1 and %d", billed, shared, callers-1)
+	}
+}
+
+func TestCoalesceMiddleware_LeaderCancelDoesNotFailWaiters(t *testing.T) {
+	t.Parallel()
+
+	mw := middlewares.NewCoalesce()
+	release := make(chan struct{})
+	next := func(ctx context.Context) (*pipeline.Response, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return &pipeline.Response{Completion: &provider.CompletionResponse{ID: "r"}}, nil
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		_, _ = mw.Process(leaderCtx, coalesceRequest(false), next) //nolint:errcheck // leader outcome is irrelevant
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	waiter := make(chan error, 1)
+	go func() {
+		resp, err := mw.Process(context.Background(), coalesceRequest(false), next)
+		if err == nil && resp.Completion.ID != "r" {
+			err = errors.New("unexpected response")
+		}
+		waiter <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancelLeader()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if err := <-waiter; err != nil {
+		t.Fatalf("waiter err = %v, want the shared response", err)
+	}
+	<-leaderDone
+}
@@ -0,0 +1,207 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// EmbeddingBatchConfig tunes how embedding requests larger than a
+// provider's limits are split. Zero values use the defaults.
+type EmbeddingBatchConfig struct {
+	// Concurrency bounds the chunks of one request in flight at once.
+	// Default 4.
+	Concurrency int
+
+	// ChunkRetries is how many times a chunk that failed transiently (429,
+	// 5xx or a transport error) is retried on the same provider before the
+	// whole request fails. Default 2; negative disables retries.
+	ChunkRetries int
+
+	// RetryDelay is the pause before a chunk is retried, doubled on each
+	// attempt. Default 200ms.
+	RetryDelay time.Duration
+}
+
+func (c EmbeddingBatchConfig) withDefaults() EmbeddingBatchConfig {
+	if c.Concurrency <= 0 {
+		c.Concurrency = 4
+	}
+	if c.ChunkRetries < 0 {
+		c.ChunkRetries = 0
+	} else if c.ChunkRetries == 0 {
+		c.ChunkRetries = 2
+	}
+	if c.RetryDelay <= 0 {
+		c.RetryDelay = 200 * time.Millisecond
+	}
+	return c
+}
+
+// embed calls p.Embed, splitting req into chunks when the provider reports
+// limits the input exceeds. Chunks run concurrently and are retried
+// individually; the vectors are reassembled in input order and usage is
+// summed.
+func (m *ProviderCallMiddleware) embed(ctx context.Context, p provider.Provider, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	var limits provider.EmbeddingLimits
+	if l, ok := p.(provider.EmbeddingLimiter); ok {
+		limits = l.EmbeddingLimits(req.Model)
+	}
+	chunks := splitEmbeddingInput(req.Input, limits)
+	if len(chunks) <= 1 {
+		return p.Embed(ctx, req)
+	}
+
+	cfg := m.embedCfg.withDefaults()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, cfg.Concurrency)
+		parts    = make([]*provider.EmbeddingResponse, len(chunks))
+	)
+	for i, c := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, c inputRange) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			chunkReq := *req
+			chunkReq.Input = req.Input[c.start:c.end]
+			resp, err := embedChunk(ctx, p, &chunkReq, cfg)
+			if err == nil && len(resp.Embeddings) != c.end-c.start {
+				err = fmt.Errorf("got %d embeddings for %d inputs", len(resp.Embeddings), c.end-c.start)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+					cancel()
+				}
+				return
+			}
+			parts[i] = resp
+		}(i, c)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	out := &provider.EmbeddingResponse{
+		Provider:   parts[0].Provider,
+		Model:      parts[0].Model,
+		Embeddings: make([][]float64, 0, len(req.Input)),
+	}
+	for _, part := range parts {
+		out.Embeddings = append(out.Embeddings, part.Embeddings...)
+		out.Usage.PromptTokens += part.Usage.PromptTokens
+		out.Usage.TotalTokens += part.Usage.TotalTokens
+	}
+	return out, nil
+}
+
+// embedChunk calls p.Embed for one chunk, retrying transient failures
+// with backoff.
+func embedChunk(ctx context.Context, p provider.Provider, req *provider.EmbeddingRequest, cfg EmbeddingBatchConfig) (*provider.EmbeddingResponse, error) {
+	delay := cfg.RetryDelay
+	var lastErr error
+	for attempt := 0; attempt <= cfg.ChunkRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+				delay *= 2
+			}
+		}
+		resp, err := p.Embed(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+		if !retryableEmbedError(err) {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// upstreamStatusRe matches the status providers put in API errors:
+// "<name>: API error (status 429): ...".
+var upstreamStatusRe = regexp.MustCompile(`\(status (\d{3})\)`)
+
+// retryableEmbedError reports whether a failed chunk is worth retrying:
+// rate limits, upstream 5xx, and transport errors that carry no status.
+// Client errors such as 400, 401 or 404 fail the same way every time.
+func retryableEmbedError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, provider.ErrNotSupported) || errors.Is(err, provider.ErrMissingAPIKey) {
+		return false
+	}
+	m := upstreamStatusRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return true
+	}
+	status, _ := strconv.Atoi(m[1]) //nolint:errcheck // the pattern matches three digits
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// inputRange is a half-open slice of EmbeddingRequest.Input.
+type inputRange struct{ start, end int }
+
+// splitEmbeddingInput packs inputs greedily into ranges that respect the
+// limits. An input that alone exceeds MaxTokens gets a chunk of its own and
+// is left for the provider to truncate or reject.
+func splitEmbeddingInput(input []string, limits provider.EmbeddingLimits) []inputRange {
+	if len(input) == 0 {
+		return nil
+	}
+	var (
+		ranges []inputRange
+		start  int
+		tokens int
+	)
+	for i, text := range input {
+		n := estimateEmbeddingTokens(text)
+		full := limits.MaxInputs > 0 && i-start >= limits.MaxInputs
+		over := limits.MaxTokens > 0 && i > start && tokens+n > limits.MaxTokens
+		if full || over {
+			ranges = append(ranges, inputRange{start, i})
+			start, tokens = i, 0
+		}
+		tokens += n
+	}
+	return append(ranges, inputRange{start, len(input)})
+}
+
+// estimateEmbeddingTokens over-estimates the token count of text at three
+// bytes per token, so chunks stay under MaxTokens for typical tokenizers
+// without calling one.
+func estimateEmbeddingTokens(text string) int {
+	return len(text)/3 + 1
+}
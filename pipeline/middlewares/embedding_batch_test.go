@@ -0,0 +1,196 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/provider"
+)
+
+// limitedEmbedder caps inputs per call and encodes each input's position
+// in its vector so reassembly order can be checked.
+type limitedEmbedder struct {
+	maxInputs int
+	failOnce  string // an input whose first call fails
+
+	calls    atomic.Int32
+	inFlight atomic.Int32
+	peak     atomic.Int32
+	mu       sync.Mutex
+	failed   bool
+}
+
+func (e *limitedEmbedder) Name() string { return "limited" }
+func (e *limitedEmbedder) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Embeddings: true}
+}
+func (e *limitedEmbedder) Models(_ context.Context) ([]provider.Model, error) { return nil, nil }
+func (e *limitedEmbedder) Complete(_ context.Context, _ *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	return nil, provider.ErrNotSupported
+}
+func (e *limitedEmbedder) CompleteStream(_ context.Context, _ *provider.CompletionRequest) (provider.Stream, error) {
+	return nil, provider.ErrNotSupported
+}
+func (e *limitedEmbedder) Healthy(_ context.Context) bool { return true }
+
+func (e *limitedEmbedder) EmbeddingLimits(_ string) provider.EmbeddingLimits {
+	return provider.EmbeddingLimits{MaxInputs: e.maxInputs}
+}
+
+func (e *limitedEmbedder) Embed(_ context.Context, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	e.calls.Add(1)
+	n := e.inFlight.Add(1)
+	defer e.inFlight.Add(-1)
+	for {
+		peak := e.peak.Load()
+		if n <= peak || e.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if len(req.Input) > e.maxInputs {
+		return nil, errors.New("too many inputs")
+	}
+	e.mu.Lock()
+	for _, in := range req.Input {
+		if in == e.failOnce && !e.failed {
+			e.failed = true
+			e.mu.Unlock()
+			return nil, errors.New("transient")
+		}
+	}
+	e.mu.Unlock()
+
+	resp := &provider.EmbeddingResponse{Provider: "limited", Model: req.Model}
+	for _, in := range req.Input {
+		v, _ := strconv.Atoi(in) //nolint:errcheck // test inputs are numeric
+		resp.Embeddings = append(resp.Embeddings, []float64{float64(v)})
+	}
+	resp.Usage = provider.Usage{PromptTokens: len(req.Input), TotalTokens: len(req.Input)}
+	return resp, nil
+}
+
+func runEmbedding(t *testing.T, e *limitedEmbedder, cfg middlewares.EmbeddingBatchConfig, n int) (*pipeline.Response, error) {
+	t.Helper()
+	reg := provider.NewRegistry()
+	reg.Register(e)
+	mw := middlewares.NewProviderCall(nil, reg).WithEmbeddingBatching(cfg)
+
+	input := make([]string, n)
+	for i := range input {
+		input[i] = strconv.Itoa(i)
+	}
+	req := &pipeline.Request{
+		Type:      pipeline.RequestEmbedding,
+		Embedding: &provider.EmbeddingRequest{Model: "m", Input: input},
+		State:     map[string]any{},
+	}
+	return mw.Process(context.Background(), req, nil)
+}
+
+func TestEmbeddingBatchSplitting(t *testing.T) {
+	e := &limitedEmbedder{maxInputs: 96, failOnce: "500"}
+	resp, err := runEmbedding(t, e, middlewares.EmbeddingBatchConfig{Concurrency: 3, RetryDelay: time.Millisecond}, 1000)
+	if err != nil {
+		t.Fatalf("Process() error: %v", err)
+	}
+
+	emb := resp.Embedding
+	if len(emb.Embeddings) != 1000 {
+		t.Fatalf("embeddings = %d, want 1000", len(emb.Embeddings))
+	}
+	for i, v := range emb.Embeddings {
+		if v[0] != float64(i) {
+			t.Fatalf("embedding %d = %v, out of order", i, v)
+		}
+	}
+	if emb.Usage.PromptTokens != 1000 || emb.Usage.TotalTokens != 1000 {
+		t.Errorf("usage = %+v, want 1000 summed tokens", emb.Usage)
+	}
+	// 11 chunks plus one retry of the chunk that failed.
+	if got := e.calls.Load(); got != 12 {
+		t.Errorf("calls = %d, want 12", got)
+	}
+	if peak := e.peak.Load(); peak > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", peak)
+	}
+}
+
+func TestEmbeddingBatchChunkFailure(t *testing.T) {
+	e := &limitedEmbedder{maxInputs: 10, failOnce: "15"}
+	_, err := runEmbedding(t, e, middlewares.EmbeddingBatchConfig{ChunkRetries: -1}, 30)
+	if err == nil {
+		t.Fatal("expected an error when a chunk fails without retries")
+	}
+}
+
+func TestEmbeddingBatchSmallRequest(t *testing.T) {
+	e := &limitedEmbedder{maxInputs: 96}
+	if _, err := runEmbedding(t, e, middlewares.EmbeddingBatchConfig{}, 5); err != nil {
+		t.Fatal(err)
+	}
+	if got := e.calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+// failingEmbedder fails every call with err.
+type failingEmbedder struct {
+	limitedEmbedder
+	err error
+}
+
+func (e *failingEmbedder) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	e.calls.Add(1)
+	return nil, e.err
+}
+
+func TestEmbeddingBatchRetriesOnlyTransientErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls int32
+	}{
+		{name: "rate limited", err: errors.New("openai: API error (status 429): slow down"), wantCalls: 3},
+		{name: "server error", err: errors.New("cohere: embed API error (status 503): unavailable"), wantCalls: 3},
+		{name: "transport", err: errors.New("dial tcp: connection refused"), wantCalls: 3},
+		{name: "bad request", err: errors.New("openai: API error (status 400): input too long"), wantCalls: 1},
+		{name: "unauthorized", err: errors.New("openai: API error (status 401): invalid key"), wantCalls: 1},
+		{name: "not found", err: errors.New("voyageai: API error (status 404): no such model"), wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &failingEmbedder{limitedEmbedder: limitedEmbedder{maxInputs: 10}, err: tt.err}
+			reg := provider.NewRegistry()
+			reg.Register(e)
+			mw := middlewares.NewProviderCall(nil, reg).WithEmbeddingBatching(middlewares.EmbeddingBatchConfig{
+				Concurrency: 1, ChunkRetries: 2, RetryDelay: time.Millisecond,
+			})
+
+			input := make([]string, 15)
+			for i := range input {
+				input[i] = strconv.Itoa(i)
+			}
+			req := &pipeline.Request{
+				Type:      pipeline.RequestEmbedding,
+				Embedding: &provider.EmbeddingRequest{Model: "m", Input: input},
+				State:     map[string]any{},
+			}
+			if _, err := mw.Process(context.Background(), req, nil); err == nil {
+				t.Fatal("expected an error")
+			}
+			// The first chunk's failure cancels the second.
+			if got := e.calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -3,15 +3,55 @@ package middlewares
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/xraph/nexus/guard"
 	"github.com/xraph/nexus/pipeline"
 	"github.com/xraph/nexus/provider"
 )
 
+// StateKeyGuardrailPolicy is the pipeline.Request.State key holding the
+// name of the guardrail policy selected for the request.
+const StateKeyGuardrailPolicy = "guardrail.policy"
+
+// GuardrailPolicies selects a per-tenant guardrail policy.
+type GuardrailPolicies struct {
+	// Registry holds the named policies.
+	Registry *guard.PolicyRegistry
+
+	// Resolve returns the policy name for the request's tenant. An empty
+	// or unknown name selects the registry's default policy, and failing
+	// that the middleware's own guard service. An error fails the request
+	// rather than serving it under a possibly weaker policy.
+	Resolve func(ctx context.Context) (string, error)
+}
+
+// service returns the guard service for the request, recording the chosen
+// policy name in req.State so later middlewares reuse the decision.
+func (p *GuardrailPolicies) service(ctx context.Context, req *pipeline.Request, fallback guard.Service) (guard.Service, error) {
+	if p == nil || p.Registry == nil {
+		return fallback, nil
+	}
+	name, ok := req.State[StateKeyGuardrailPolicy].(string)
+	if !ok && p.Resolve != nil {
+		var err error
+		if name, err = p.Resolve(ctx); err != nil {
+			return nil, fmt.Errorf("nexus: resolve guardrail policy: %w", err)
+		}
+		if req.State != nil {
+			req.State[StateKeyGuardrailPolicy] = name
+		}
+	}
+	if svc := p.Registry.Resolve(name); svc != nil {
+		return svc, nil
+	}
+	return fallback, nil
+}
+
 // GuardrailMiddleware runs guardrails before and after the provider call.
 type GuardrailMiddleware struct {
-	guard guard.Service
+	guard    guard.Service
+	policies *GuardrailPolicies
 }
 
 // NewGuardrail creates a guardrail middleware.
@@ -19,21 +59,46 @@ func NewGuardrail(g guard.Service) *GuardrailMiddleware {
 	return &GuardrailMiddleware{guard: g}
 }
 
+// WithPolicies selects guards per tenant from named policies. The service
+// passed to NewGuardrail remains the fallback when no policy applies.
+func (m *GuardrailMiddleware) WithPolicies(p GuardrailPolicies) *GuardrailMiddleware {
+	m.policies = &p
+	return m
+}
+
 func (m *GuardrailMiddleware) Name() string  { return "guardrail" }
 func (m *GuardrailMiddleware) Priority() int { return 150 } // After auth, before transforms
 
 func (m *GuardrailMiddleware) Process(ctx context.Context, req *pipeline.Request, next pipeline.NextFunc) (*pipeline.Response, error) {
-	if m.guard == nil || req.Completion == nil {
+	switch {
+	case req.Image != nil:
+		return m.processText(ctx, req, &req.Image.Prompt, next)
+	case req.Speech != nil:
+		return m.processText(ctx, req, &req.Speech.Input, next)
+	case req.Rerank != nil:
+		return m.processText(ctx, req, &req.Rerank.Query, next)
+	case req.Transcription != nil:
+		return m.processTranscription(ctx, req, next)
+	}
+	if req.Completion == nil {
+		return next(ctx)
+	}
+	svc, err := m.policies.service(ctx, req, m.guard)
+	if err != nil {
+		return nil, err
+	}
+	if svc == nil {
 		return next(ctx)
 	}
 
 	// Input guardrails
 	input := &guard.CheckInput{
 		Messages: req.Completion.Messages,
+		System:   req.Completion.System,
 		TenantID: pipeline.TenantID(ctx),
 	}
 
-	result, err := m.guard.CheckPhase(ctx, guard.PhaseInput, input)
+	result, err := svc.CheckPhase(ctx, guard.PhaseInput, input)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +107,9 @@ func (m *GuardrailMiddleware) Process(ctx context.Context, req *pipeline.Request
 	}
 	if result.Modified {
 		req.Completion.Messages = result.Messages
+		if result.System != nil {
+			req.Completion.System = *result.System
+		}
 	}
 
 	// Continue pipeline (provider call)
@@ -50,7 +118,8 @@ func (m *GuardrailMiddleware) Process(ctx context.Context, req *pipeline.Request
 		return resp, err
 	}
 
-	// Output guardrails (non-streaming only)
+	// Output guardrails (non-streaming only; streams are guarded by
+	// StreamGuardrailMiddleware)
 	if resp != nil && resp.Completion != nil && len(resp.Completion.Choices) > 0 {
 		// Build output check input from response messages
 		outputMsgs := make([]provider.Message, len(resp.Completion.Choices))
@@ -63,7 +132,7 @@ func (m *GuardrailMiddleware) Process(ctx context.Context, req *pipeline.Request
 			TenantID: pipeline.TenantID(ctx),
 		}
 
-		outputResult, err := m.guard.CheckPhase(ctx, guard.PhaseOutput, outputInput)
+		outputResult, err := svc.CheckPhase(ctx, guard.PhaseOutput, outputInput)
 		if err != nil {
 			return nil, err
 		}
@@ -81,3 +150,67 @@ func (m *GuardrailMiddleware) Process(ctx context.Context, req *pipeline.Request
 
 	return resp, nil
 }
+
+// processText runs input guardrails on the text of a non-chat request,
+// such as an image prompt or speech input. The text is checked as a single
+// user message; a rewritten message replaces it.
+func (m *GuardrailMiddleware) processText(ctx context.Context, req *pipeline.Request, text *string, next pipeline.NextFunc) (*pipeline.Response, error) {
+	svc, err := m.policies.service(ctx, req, m.guard)
+	if err != nil {
+		return nil, err
+	}
+	if svc == nil {
+		return next(ctx)
+	}
+
+	input := &guard.CheckInput{
+		Messages: []provider.Message{{Role: "user", Content: *text}},
+		TenantID: pipeline.TenantID(ctx),
+	}
+	result, err := svc.CheckPhase(ctx, guard.PhaseInput, input)
+	if err != nil {
+		return nil, err
+	}
+	if result.Blocked {
+		return nil, errors.New("nexus: " + result.Reason)
+	}
+	if result.Modified && len(result.Messages) > 0 {
+		if rewritten, ok := result.Messages[0].Content.(string); ok {
+			*text = rewritten
+		}
+	}
+	return next(ctx)
+}
+
+// processTranscription runs output guardrails on a transcript. A rewritten
+// transcript drops the timed segments, which would otherwise still carry
+// the original text.
+func (m *GuardrailMiddleware) processTranscription(ctx context.Context, req *pipeline.Request, next pipeline.NextFunc) (*pipeline.Response, error) {
+	svc, err := m.policies.service(ctx, req, m.guard)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := next(ctx)
+	if err != nil || resp == nil || resp.Transcription == nil || svc == nil {
+		return resp, err
+	}
+
+	input := &guard.CheckInput{
+		Messages: []provider.Message{{Role: "assistant", Content: resp.Transcription.Text}},
+		TenantID: pipeline.TenantID(ctx),
+	}
+	result, err := svc.CheckPhase(ctx, guard.PhaseOutput, input)
+	if err != nil {
+		return nil, err
+	}
+	if result.Blocked {
+		return nil, errors.New("nexus: output blocked: " + result.Reason)
+	}
+	if result.Modified && len(result.Messages) > 0 {
+		if rewritten, ok := result.Messages[0].Content.(string); ok {
+			resp.Transcription.Text = rewritten
+			resp.Transcription.Segments = nil
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,39 @@
+package middlewares_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/guard"
+	"github.com/xraph/nexus/guard/guards"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/provider"
+)
+
+func TestGuardrailMiddleware_RedactsImagePrompt(t *testing.T) {
+	t.Parallel()
+
+	svc := guard.NewService()
+	svc.Register(guards.NewPII(guard.ActionRedact))
+	mw := middlewares.NewGuardrail(svc)
+
+	req := &pipeline.Request{
+		Image: &provider.ImageRequest{Model: "dall-e-3", Prompt: "A poster for jane@example.com"},
+		Type:  pipeline.RequestImage,
+		State: map[string]any{},
+	}
+
+	var sent string
+	_, err := mw.Process(context.Background(), req, func(_ context.Context) (*pipeline.Response, error) {
+		sent = req.Image.Prompt
+		return &pipeline.Response{Image: &provider.ImageResponse{}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent == "" || strings.Contains(sent, "@") {
+		t.Errorf("prompt sent to provider = %q, want redacted", sent)
+	}
+}
@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/prompt"
+	"github.com/xraph/nexus/transform"
+)
+
+// PromptTemplateMiddleware renders registry templates named by a
+// completion's PromptID into a leading system message. It runs ahead of
+// the input guardrails because PromptVariables come from the client: the
+// rendered text must pass the same guards and transforms as the messages.
+type PromptTemplateMiddleware struct {
+	render *transform.PromptTemplateTransform
+}
+
+// NewPromptTemplate creates a prompt template rendering middleware.
+func NewPromptTemplate(prompts prompt.Service) *PromptTemplateMiddleware {
+	return &PromptTemplateMiddleware{render: transform.NewPromptTemplate(prompts)}
+}
+
+func (m *PromptTemplateMiddleware) Name() string  { return "prompt_template" }
+func (m *PromptTemplateMiddleware) Priority() int { return 140 } // Before input guardrails (150)
+
+func (m *PromptTemplateMiddleware) Process(ctx context.Context, req *pipeline.Request, next pipeline.NextFunc) (*pipeline.Response, error) {
+	if req.Completion != nil {
+		if err := m.render.TransformInput(ctx, req.Completion); err != nil {
+			return nil, err
+		}
+	}
+	return next(ctx)
+}
@@ -7,16 +7,22 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/xraph/nexus/model"
 	"github.com/xraph/nexus/pipeline"
 	"github.com/xraph/nexus/provider"
 	"github.com/xraph/nexus/router"
 )
 
+// StateKeyProviderPricing holds the serving model's provider.Pricing for
+// streams, which the usage middleware prices once the stream closes.
+const StateKeyProviderPricing = "provider.pricing"
+
 // ProviderCallMiddleware is the core middleware that routes to a provider and
 // executes the request. It sits at priority 350 (middle of the routing range).
 type ProviderCallMiddleware struct {
 	router    router.Service
 	providers provider.Registry
+	embedCfg  EmbeddingBatchConfig
 }
 
 // NewProviderCall creates the core provider-calling middleware.
@@ -27,6 +33,13 @@ func NewProviderCall(r router.Service, providers provider.Registry) *ProviderCal
 	}
 }
 
+// WithEmbeddingBatching tunes how oversized embedding requests are split
+// into chunks.
+func (m *ProviderCallMiddleware) WithEmbeddingBatching(cfg EmbeddingBatchConfig) *ProviderCallMiddleware {
+	m.embedCfg = cfg
+	return m
+}
+
 func (m *ProviderCallMiddleware) Name() string  { return "provider_call" }
 func (m *ProviderCallMiddleware) Priority() int { return 350 }
 
@@ -38,6 +51,14 @@ func (m *ProviderCallMiddleware) Process(ctx context.Context, req *pipeline.Requ
 		return m.handleStream(ctx, req)
 	case pipeline.RequestEmbedding:
 		return m.handleEmbedding(ctx, req)
+	case pipeline.RequestImage:
+		return m.handleImage(ctx, req)
+	case pipeline.RequestTranscription:
+		return m.handleTranscription(ctx, req)
+	case pipeline.RequestSpeech:
+		return m.handleSpeech(ctx, req)
+	case pipeline.RequestRerank:
+		return m.handleRerank(ctx, req)
 	default:
 		return nil, fmt.Errorf("nexus: unknown request type: %s", req.Type)
 	}
@@ -56,10 +77,21 @@ func (m *ProviderCallMiddleware) handleCompletion(ctx context.Context, req *pipe
 	if err != nil {
 		return nil, fmt.Errorf("nexus: provider %s: %w", p.Name(), err)
 	}
+	// Report the instance that served the request, not its type.
+	resp.Provider = p.Name()
+	if resp.Cost == 0 {
+		modelID := resp.Model
+		if modelID == "" {
+			modelID = req.Completion.Model
+		}
+		if pricing, ok := model.FindPricing(ctx, p, modelID); ok {
+			resp.Cost = model.EstimateCost(resp.Usage, pricing).TotalCost
+		}
+	}
 
 	// Store timing
 	req.State["provider_latency"] = time.Since(start)
-	req.State["provider_name"] = p.Name()
+	setProviderState(req, p)
 
 	return &pipeline.Response{Completion: resp}, nil
 }
@@ -71,7 +103,11 @@ func (m *ProviderCallMiddleware) handleStream(ctx context.Context, req *pipeline
 	}
 
 	ctx = pipeline.WithProviderName(ctx, p.Name())
-	req.State["provider_name"] = p.Name()
+	setProviderState(req, p)
+	// Streams are priced when they close, once usage is known.
+	if pricing, ok := model.FindPricing(ctx, p, req.Completion.Model); ok {
+		req.State[StateKeyProviderPricing] = pricing
+	}
 
 	stream, err := p.CompleteStream(ctx, req.Completion)
 	if err != nil {
@@ -86,22 +122,281 @@ func (m *ProviderCallMiddleware) handleEmbedding(ctx context.Context, req *pipel
 		return nil, errors.New("nexus: embedding request is nil")
 	}
 
-	// For embeddings, pick the first provider that supports embeddings
-	allProviders := m.providers.WithCapability("embed")
-	if len(allProviders) == 0 {
+	candidates, err := m.embeddingCandidates(ctx, req.Embedding)
+	if err != nil {
+		return nil, err
+	}
+
+	// The router picks the primary; the remaining candidates are tried in
+	// order if it fails.
+	primary := candidates[0]
+	if m.router != nil && len(candidates) > 1 {
+		primary, err = m.router.Route(ctx, &provider.CompletionRequest{Model: req.Embedding.Model}, candidates)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: routing: %w", err)
+		}
+	}
+	order := make([]provider.Provider, 0, len(candidates))
+	order = append(order, primary)
+	for _, p := range candidates {
+		if p != primary {
+			order = append(order, p)
+		}
+	}
+
+	var lastErr error
+	for _, p := range order {
+		setProviderState(req, p)
+		start := time.Now()
+
+		resp, err := m.embed(pipeline.WithProviderName(ctx, p.Name()), p, req.Embedding)
+		if err == nil {
+			req.State["provider_latency"] = time.Since(start)
+			resp.Provider = p.Name()
+			return &pipeline.Response{Embedding: resp}, nil
+		}
+		lastErr = fmt.Errorf("nexus: provider %s embed: %w", p.Name(), err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// embeddingCandidates returns the embedding providers eligible for req.
+// Vectors from different provider types are not comparable, so a model no
+// provider lists only falls back to unlisting catalogs when the request
+// is pinned to a provider or a single provider type serves embeddings.
+func (m *ProviderCallMiddleware) embeddingCandidates(ctx context.Context, req *provider.EmbeddingRequest) ([]provider.Provider, error) {
+	all := m.providers.WithCapability("embeddings")
+	if len(all) == 0 {
 		return nil, errors.New("nexus: no providers support embeddings")
 	}
+	candidates := modelCandidates(ctx, all, req.Provider, req.Model)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("nexus: provider %q does not support embeddings", req.Provider)
+	}
+	// Candidates either all list the model or are every provider.
+	if req.Provider == "" && !singleType(candidates) && !providerListsModel(ctx, candidates[0], req.Model) {
+		return nil, fmt.Errorf("nexus: embedding model %q not found", req.Model)
+	}
+	return candidates, nil
+}
+
+// singleType reports whether all providers share one provider type.
+func singleType(providers []provider.Provider) bool {
+	for _, p := range providers[1:] {
+		if provider.TypeOf(p) != provider.TypeOf(providers[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// modelCandidates narrows providers for a request: to the forced provider
+// if one is named (an instance name, or a type selecting every instance of
+// it), then to those that list the model, falling back to all of them for
+// catalogs that don't enumerate their models. It returns nil when the
+// forced provider is not among providers.
+func modelCandidates(ctx context.Context, providers []provider.Provider, forced, model string) []provider.Provider {
+	if forced != "" {
+		var pinned []provider.Provider
+		for _, p := range providers {
+			if p.Name() == forced {
+				return []provider.Provider{p}
+			}
+			if provider.TypeOf(p) == forced {
+				pinned = append(pinned, p)
+			}
+		}
+		if len(pinned) == 0 {
+			return nil
+		}
+		providers = pinned
+	}
+
+	var matched []provider.Provider
+	for _, p := range providers {
+		if providerListsModel(ctx, p, model) {
+			matched = append(matched, p)
+		}
+	}
+	if len(matched) == 0 {
+		return providers
+	}
+	return matched
+}
+
+func providerListsModel(ctx context.Context, p provider.Provider, model string) bool {
+	models, err := p.Models(ctx)
+	if err != nil {
+		return false
+	}
+	for _, mdl := range models {
+		if mdl.ID == model {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ProviderCallMiddleware) handleImage(ctx context.Context, req *pipeline.Request) (*pipeline.Response, error) {
+	if req.Image == nil {
+		return nil, errors.New("nexus: image request is nil")
+	}
+
+	p := m.selectOptional(ctx, "images", req.Image.Provider, req.Image.Model, func(p provider.Provider) bool {
+		_, ok := p.(provider.ImageProvider)
+		return ok
+	})
+	ip, ok := p.(provider.ImageProvider)
+	if !ok {
+		return nil, errors.New("nexus: no providers support image generation")
+	}
 
-	p := allProviders[0]
 	ctx = pipeline.WithProviderName(ctx, p.Name())
-	req.State["provider_name"] = p.Name()
+	setProviderState(req, p)
+	start := time.Now()
 
-	resp, err := p.Embed(ctx, req.Embedding)
+	resp, err := ip.GenerateImage(ctx, req.Image)
 	if err != nil {
-		return nil, fmt.Errorf("nexus: provider %s embed: %w", p.Name(), err)
+		return nil, fmt.Errorf("nexus: provider %s image: %w", p.Name(), err)
 	}
+	req.State["provider_latency"] = time.Since(start)
+	resp.Provider = p.Name()
+
+	return &pipeline.Response{Image: resp}, nil
+}
+
+func (m *ProviderCallMiddleware) handleTranscription(ctx context.Context, req *pipeline.Request) (*pipeline.Response, error) {
+	if req.Transcription == nil {
+		return nil, errors.New("nexus: transcription request is nil")
+	}
+
+	p := m.selectOptional(ctx, "audio", req.Transcription.Provider, req.Transcription.Model, func(p provider.Provider) bool {
+		_, ok := p.(provider.TranscriptionProvider)
+		return ok
+	})
+	tp, ok := p.(provider.TranscriptionProvider)
+	if !ok {
+		return nil, errors.New("nexus: no providers support audio transcription")
+	}
+
+	ctx = pipeline.WithProviderName(ctx, p.Name())
+	setProviderState(req, p)
+	start := time.Now()
+
+	resp, err := tp.Transcribe(ctx, req.Transcription)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: provider %s transcription: %w", p.Name(), err)
+	}
+	req.State["provider_latency"] = time.Since(start)
+	resp.Provider = p.Name()
 
-	return &pipeline.Response{Embedding: resp}, nil
+	return &pipeline.Response{Transcription: resp}, nil
+}
+
+func (m *ProviderCallMiddleware) handleSpeech(ctx context.Context, req *pipeline.Request) (*pipeline.Response, error) {
+	if req.Speech == nil {
+		return nil, errors.New("nexus: speech request is nil")
+	}
+
+	p := m.selectOptional(ctx, "audio", req.Speech.Provider, req.Speech.Model, func(p provider.Provider) bool {
+		_, ok := p.(provider.SpeechProvider)
+		return ok
+	})
+	sp, ok := p.(provider.SpeechProvider)
+	if !ok {
+		return nil, errors.New("nexus: no providers support speech synthesis")
+	}
+
+	ctx = pipeline.WithProviderName(ctx, p.Name())
+	setProviderState(req, p)
+	start := time.Now()
+
+	resp, err := sp.Speech(ctx, req.Speech)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: provider %s speech: %w", p.Name(), err)
+	}
+	// Time to first byte; the audio body is still streaming.
+	req.State["provider_latency"] = time.Since(start)
+	resp.Provider = p.Name()
+
+	return &pipeline.Response{Speech: resp}, nil
+}
+
+func (m *ProviderCallMiddleware) handleRerank(ctx context.Context, req *pipeline.Request) (*pipeline.Response, error) {
+	if req.Rerank == nil {
+		return nil, errors.New("nexus: rerank request is nil")
+	}
+
+	p := m.selectOptional(ctx, "rerank", req.Rerank.Provider, req.Rerank.Model, func(p provider.Provider) bool {
+		_, ok := p.(provider.RerankProvider)
+		return ok
+	})
+	rp, ok := p.(provider.RerankProvider)
+	if !ok {
+		return nil, errors.New("nexus: no providers support reranking")
+	}
+
+	ctx = pipeline.WithProviderName(ctx, p.Name())
+	setProviderState(req, p)
+	start := time.Now()
+
+	resp, err := rp.Rerank(ctx, req.Rerank)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: provider %s rerank: %w", p.Name(), err)
+	}
+	req.State["provider_latency"] = time.Since(start)
+	resp.Provider = p.Name()
+
+	// Not every reranker echoes documents back; fill them in from the
+	// request so return_documents behaves the same everywhere.
+	if req.Rerank.ReturnDocuments {
+		for i, r := range resp.Results {
+			if r.Document == nil && r.Index >= 0 && r.Index < len(req.Rerank.Documents) {
+				resp.Results[i].Document = &provider.RerankDocument{Text: req.Rerank.Documents[r.Index]}
+			}
+		}
+	}
+
+	return &pipeline.Response{Rerank: resp}, nil
+}
+
+// setProviderState records the serving instance and its type for the
+// usage, header and tracing middleware.
+func setProviderState(req *pipeline.Request, p provider.Provider) {
+	req.State["provider_name"] = p.Name()
+	req.State["provider_type"] = provider.TypeOf(p)
+}
+
+// selectOptional picks a provider for an endpoint served through an
+// optional interface: the forced instance if one is named (or, for a forced
+// type, the first of its instances that lists the model), otherwise the
+// first qualifying provider that lists the model, otherwise the first
+// qualifying provider. It returns nil when none qualifies.
+func (m *ProviderCallMiddleware) selectOptional(ctx context.Context, capability, forced, model string, implements func(provider.Provider) bool) provider.Provider {
+	var first provider.Provider
+	for _, p := range m.providers.WithCapability(capability) {
+		if !implements(p) {
+			continue
+		}
+		if forced != "" {
+			if p.Name() == forced {
+				return p
+			}
+			if provider.TypeOf(p) != forced {
+				continue
+			}
+		}
+		if first == nil {
+			first = p
+		}
+		if providerListsModel(ctx, p, model) {
+			return p
+		}
+	}
+	return first
 }
 
 func (m *ProviderCallMiddleware) selectProvider(ctx context.Context, req *pipeline.Request) (provider.Provider, error) {
@@ -110,8 +405,13 @@ func (m *ProviderCallMiddleware) selectProvider(ctx context.Context, req *pipeli
 		return nil, errors.New("nexus: no providers registered")
 	}
 
+	candidates := modelCandidates(ctx, allProviders, req.Completion.Provider, req.Completion.Model)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("nexus: provider %q is not registered", req.Completion.Provider)
+	}
+
 	if m.router != nil {
-		p, err := m.router.Route(ctx, req.Completion, allProviders)
+		p, err := m.router.Route(ctx, req.Completion, candidates)
 		if err != nil {
 			return nil, fmt.Errorf("nexus: routing: %w", err)
 		}
@@ -119,5 +419,5 @@ func (m *ProviderCallMiddleware) selectProvider(ctx context.Context, req *pipeli
 	}
 
 	// No router configured — use first available provider
-	return allProviders[0], nil
+	return candidates[0], nil
 }
@@ -0,0 +1,239 @@
+package middlewares_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/provider"
+)
+
+// instanceProvider is one named deployment of a provider type.
+type instanceProvider struct {
+	name, typ string
+	models    []string
+}
+
+func (p *instanceProvider) Name() string { return p.name }
+func (p *instanceProvider) Type() string { return p.typ }
+func (p *instanceProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Chat: true}
+}
+func (p *instanceProvider) Models(_ context.Context) ([]provider.Model, error) {
+	out := make([]provider.Model, len(p.models))
+	for i, id := range p.models {
+		out[i] = provider.Model{ID: id, Provider: p.name}
+	}
+	return out, nil
+}
+func (p *instanceProvider) Complete(_ context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	// Report the type, as provider clients do; the middleware stamps the
+	// instance.
+	return &provider.CompletionResponse{Provider: p.typ, Model: req.Model}, nil
+}
+func (p *instanceProvider) CompleteStream(_ context.Context, _ *provider.CompletionRequest) (provider.Stream, error) {
+	return nil, provider.ErrNotSupported
+}
+func (p *instanceProvider) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	return nil, provider.ErrNotSupported
+}
+func (p *instanceProvider) Healthy(_ context.Context) bool { return true }
+
+func TestProviderCall_RoutesAcrossInstances(t *testing.T) {
+	reg := provider.NewRegistry()
+	reg.Register(&instanceProvider{name: "openai-us", typ: "openai", models: []string{"gpt-4o"}})
+	reg.Register(&instanceProvider{name: "openai-eu", typ: "openai", models: []string{"gpt-4o-mini"}})
+	reg.Register(&instanceProvider{name: "ollama-gpu", typ: "ollama", models: []string{"llama3"}})
+	mw := middlewares.NewProviderCall(nil, reg)
+
+	tests := []struct {
+		name, model, forced, want string
+		wantErr                   bool
+	}{
+		{name: "model picks instance", model: "gpt-4o-mini", want: "openai-eu"},
+		{name: "other instance", model: "llama3", want: "ollama-gpu"},
+		{name: "forced instance", model: "gpt-4o-mini", forced: "openai-us", want: "openai-us"},
+		{name: "forced type narrows by model", model: "gpt-4o-mini", forced: "openai", want: "openai-eu"},
+		{name: "forced type unknown model", model: "custom", forced: "ollama", want: "ollama-gpu"},
+		{name: "unknown forced", model: "gpt-4o", forced: "azure-west", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &pipeline.Request{
+				Type:       pipeline.RequestCompletion,
+				Completion: &provider.CompletionRequest{Model: tt.model, Provider: tt.forced},
+				State:      map[string]any{},
+			}
+			resp, err := mw.Process(context.Background(), req, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Completion.Provider != tt.want {
+				t.Errorf("provider = %q, want %q", resp.Completion.Provider, tt.want)
+			}
+			if req.State["provider_name"] != tt.want {
+				t.Errorf("state provider_name = %v, want %q", req.State["provider_name"], tt.want)
+			}
+			wantType := "openai"
+			if tt.want == "ollama-gpu" {
+				wantType = "ollama"
+			}
+			if req.State["provider_type"] != wantType {
+				t.Errorf("state provider_type = %v, want %q", req.State["provider_type"], wantType)
+			}
+		})
+	}
+}
+
+// pricedProvider serves a dated version of a catalog model with cached
+// prompt tokens and no cost of its own.
+type pricedProvider struct{ instanceProvider }
+
+func (p *pricedProvider) Models(_ context.Context) ([]provider.Model, error) {
+	return []provider.Model{{ID: "claude-sonnet", Pricing: provider.Pricing{
+		InputPerMillion: 3, OutputPerMillion: 15, CacheReadPerMillion: 0.3,
+	}}}, nil
+}
+func (p *pricedProvider) Complete(_ context.Context, _ *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	return &provider.CompletionResponse{Model: "claude-sonnet-20250514", Usage: provider.Usage{
+		PromptTokens: 2_000_000, CompletionTokens: 100_000, CacheReadTokens: 1_000_000,
+	}}, nil
+}
+
+func TestProviderCall_PricesCompletions(t *testing.T) {
+	reg := provider.NewRegistry()
+	reg.Register(&pricedProvider{instanceProvider{name: "anthropic", typ: "anthropic"}})
+	mw := middlewares.NewProviderCall(nil, reg)
+
+	req := &pipeline.Request{
+		Type:       pipeline.RequestCompletion,
+		Completion: &provider.CompletionRequest{Model: "claude-sonnet"},
+		State:      map[string]any{},
+	}
+	resp, err := mw.Process(context.Background(), req, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1M uncached at $3, 1M cache reads at $0.30, 100k output at $15.
+	if got, want := resp.Completion.Cost, 3+0.3+1.5; got < want-1e-9 || got > want+1e-9 {
+		t.Fatalf("cost = %v, want %v", got, want)
+	}
+}
+
+// endpointProvider serves every optional endpoint and, like provider
+// clients, reports its type rather than its instance name.
+type endpointProvider struct{ instanceProvider }
+
+func (p *endpointProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Embeddings: true, Images: true, Audio: true, Rerank: true}
+}
+func (p *endpointProvider) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	return &provider.EmbeddingResponse{Provider: p.typ}, nil
+}
+func (p *endpointProvider) GenerateImage(_ context.Context, _ *provider.ImageRequest) (*provider.ImageResponse, error) {
+	return &provider.ImageResponse{Provider: p.typ}, nil
+}
+func (p *endpointProvider) Transcribe(_ context.Context, _ *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error) {
+	return &provider.TranscriptionResponse{Provider: p.typ}, nil
+}
+func (p *endpointProvider) Speech(_ context.Context, _ *provider.SpeechRequest) (*provider.SpeechResponse, error) {
+	return &provider.SpeechResponse{Provider: p.typ}, nil
+}
+func (p *endpointProvider) Rerank(_ context.Context, _ *provider.RerankRequest) (*provider.RerankResponse, error) {
+	return &provider.RerankResponse{Provider: p.typ}, nil
+}
+
+func TestProviderCall_StampsInstanceOnEveryResponse(t *testing.T) {
+	reg := provider.NewRegistry()
+	reg.Register(&endpointProvider{instanceProvider{name: "bedrock-eu", typ: "bedrock", models: []string{"m"}}})
+	mw := middlewares.NewProviderCall(nil, reg)
+
+	tests := []struct {
+		name string
+		req  *pipeline.Request
+		got  func(*pipeline.Response) string
+	}{
+		{
+			name: "embedding",
+			req:  &pipeline.Request{Type: pipeline.RequestEmbedding, Embedding: &provider.EmbeddingRequest{Model: "m", Input: []string{"a"}}},
+			got:  func(r *pipeline.Response) string { return r.Embedding.Provider },
+		},
+		{
+			name: "image",
+			req:  &pipeline.Request{Type: pipeline.RequestImage, Image: &provider.ImageRequest{Model: "m"}},
+			got:  func(r *pipeline.Response) string { return r.Image.Provider },
+		},
+		{
+			name: "transcription",
+			req:  &pipeline.Request{Type: pipeline.RequestTranscription, Transcription: &provider.TranscriptionRequest{Model: "m"}},
+			got:  func(r *pipeline.Response) string { return r.Transcription.Provider },
+		},
+		{
+			name: "speech",
+			req:  &pipeline.Request{Type: pipeline.RequestSpeech, Speech: &provider.SpeechRequest{Model: "m"}},
+			got:  func(r *pipeline.Response) string { return r.Speech.Provider },
+		},
+		{
+			name: "rerank",
+			req:  &pipeline.Request{Type: pipeline.RequestRerank, Rerank: &provider.RerankRequest{Model: "m"}},
+			got:  func(r *pipeline.Response) string { return r.Rerank.Provider },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.State = map[string]any{}
+			resp, err := mw.Process(context.Background(), tt.req, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.got(resp); got != "bedrock-eu" {
+				t.Errorf("provider = %q, want %q", got, "bedrock-eu")
+			}
+		})
+	}
+}
+
+func TestProviderCall_EmbeddingModelNotListed(t *testing.T) {
+	reg := provider.NewRegistry()
+	reg.Register(&endpointProvider{instanceProvider{name: "openai", typ: "openai", models: []string{"text-embedding-3-small"}}})
+	reg.Register(&endpointProvider{instanceProvider{name: "cohere", typ: "cohere", models: []string{"embed-english-v3.0"}}})
+	mw := middlewares.NewProviderCall(nil, reg)
+
+	tests := []struct {
+		name, model, forced, want string
+		wantErr                   bool
+	}{
+		{name: "listed model", model: "embed-english-v3.0", want: "cohere"},
+		{name: "unlisted model", model: "nomic-embed-text", wantErr: true},
+		{name: "unlisted model pinned", model: "text-embedding-ada-002", forced: "openai", want: "openai"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &pipeline.Request{
+				Type:      pipeline.RequestEmbedding,
+				Embedding: &provider.EmbeddingRequest{Model: tt.model, Provider: tt.forced, Input: []string{"a"}},
+				State:     map[string]any{},
+			}
+			resp, err := mw.Process(context.Background(), req, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("provider = %q, want a model not found error", resp.Embedding.Provider)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Embedding.Provider != tt.want {
+				t.Errorf("provider = %q, want %q", resp.Embedding.Provider, tt.want)
+			}
+		})
+	}
+}
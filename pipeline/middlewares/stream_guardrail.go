@@ -10,7 +10,10 @@ import (
 // StreamGuardrailMiddleware applies guardrails to streaming responses.
 type StreamGuardrailMiddleware struct {
 	guards   []guard.StreamGuard
+	service  guard.Service
+	policies *GuardrailPolicies
 	strategy guard.StreamStrategy
+	window   int
 }
 
 // NewStreamGuardrail creates middleware that guards streaming responses.
@@ -21,6 +24,31 @@ func NewStreamGuardrail(guards []guard.StreamGuard, strategy guard.StreamStrateg
 	}
 }
 
+// NewServiceStreamGuardrail creates middleware that guards streaming
+// responses with the output-phase guards registered on svc, looked up per
+// request. The gateway only installs it when output guards or policies
+// exist at Initialize, so an output guard registered afterwards does not
+// apply to streams unless the middleware is already in the pipeline.
+func NewServiceStreamGuardrail(svc guard.Service, strategy guard.StreamStrategy) *StreamGuardrailMiddleware {
+	return &StreamGuardrailMiddleware{
+		service:  svc,
+		strategy: strategy,
+	}
+}
+
+// WithPolicies selects output guards per tenant from named policies, as
+// GuardrailMiddleware.WithPolicies does for input and non-streaming output.
+func (m *StreamGuardrailMiddleware) WithPolicies(p GuardrailPolicies) *StreamGuardrailMiddleware {
+	m.policies = &p
+	return m
+}
+
+// WithWindowSize sets the hold-back used by guard.StrategySlidingWindow.
+func (m *StreamGuardrailMiddleware) WithWindowSize(n int) *StreamGuardrailMiddleware {
+	m.window = n
+	return m
+}
+
 func (m *StreamGuardrailMiddleware) Name() string  { return "stream_guardrail" }
 func (m *StreamGuardrailMiddleware) Priority() int { return 155 } // just after input guardrail
 
@@ -30,6 +58,15 @@ func (m *StreamGuardrailMiddleware) Process(ctx context.Context, req *pipeline.R
 		return next(ctx)
 	}
 
+	guards := m.guards
+	svc, err := m.policies.service(ctx, req, m.service)
+	if err != nil {
+		return nil, err
+	}
+	if svc != nil {
+		guards = append(guards[:len(guards):len(guards)], guard.OutputStreamGuards(svc)...)
+	}
+
 	// Let the request proceed and wrap the stream in the response.
 	resp, err := next(ctx)
 	if err != nil {
@@ -37,8 +74,8 @@ func (m *StreamGuardrailMiddleware) Process(ctx context.Context, req *pipeline.R
 	}
 
 	// If there's a stream, wrap it with guardrails.
-	if resp.Stream != nil && len(m.guards) > 0 {
-		resp.Stream = guard.NewGuardedStream(resp.Stream, m.guards, m.strategy)
+	if resp != nil && resp.Stream != nil && len(guards) > 0 {
+		resp.Stream = guard.NewGuardedStream(resp.Stream, guards, m.strategy).WithWindowSize(m.window)
 	}
 
 	return resp, nil
@@ -0,0 +1,103 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/guard"
+	"github.com/xraph/nexus/guard/guards"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/testutil"
+)
+
+func runGuardedStream(t *testing.T, svc guard.Service, strategy guard.StreamStrategy, deltas ...string) (string, error) {
+	t.Helper()
+
+	chunks := make([]*provider.StreamChunk, len(deltas))
+	for i, d := range deltas {
+		chunks[i] = &provider.StreamChunk{Delta: provider.Delta{Content: d}}
+	}
+	chunks[len(chunks)-1].FinishReason = "stop"
+	upstream := testutil.NewFakeStream(chunks, &provider.Usage{TotalTokens: 5})
+
+	mw := middlewares.NewServiceStreamGuardrail(svc, strategy).WithWindowSize(32)
+	req := &pipeline.Request{
+		Completion: &provider.CompletionRequest{Model: "m", Stream: true},
+		Type:       pipeline.RequestStream,
+		State:      map[string]any{},
+	}
+	resp, err := mw.Process(context.Background(), req, func(_ context.Context) (*pipeline.Response, error) {
+		return &pipeline.Response{Stream: upstream}, nil
+	})
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	defer func() { _ = resp.Stream.Close() }()
+
+	var out strings.Builder
+	for {
+		c, err := resp.Stream.Next(context.Background())
+		if errors.Is(err, io.EOF) {
+			return out.String(), nil
+		}
+		if err != nil {
+			return out.String(), err
+		}
+		out.WriteString(c.Delta.Content)
+	}
+}
+
+func TestStreamGuardrail_SlidingWindowRedactsSplitPattern(t *testing.T) {
+	t.Parallel()
+
+	svc := guard.NewService()
+	svc.Register(guards.NewPII(guard.ActionRedact))
+
+	got, err := runGuardedStream(t, svc, guard.StrategySlidingWindow,
+		"Contact me at jane.do", "e@example.com for ", "details.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "@") || strings.Contains(got, "jane") {
+		t.Fatalf("email leaked: %q", got)
+	}
+	if want := "Contact me at [EMAIL_REDACTED] for details."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStreamGuardrail_ChunkwiseMissesSplitPattern(t *testing.T) {
+	t.Parallel()
+
+	svc := guard.NewService()
+	svc.Register(guards.NewPII(guard.ActionRedact))
+
+	// Chunkwise only sees one delta at a time — the reason the sliding
+	// window is the default.
+	got, err := runGuardedStream(t, svc, guard.StrategyChunkwise,
+		"Contact me at jane.do", "e@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "jane.do") {
+		t.Errorf("expected the split prefix to pass chunkwise, got %q", got)
+	}
+}
+
+func TestStreamGuardrail_SlidingWindowBlocks(t *testing.T) {
+	t.Parallel()
+
+	svc := guard.NewService()
+	svc.Register(guards.NewContentFilter(guard.ActionBlock, "forbidden"))
+
+	_, err := runGuardedStream(t, svc, guard.StrategySlidingWindow, "this is forb", "idden text")
+	var blocked *guard.BlockedError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("err = %v, want BlockedError", err)
+	}
+}
@@ -2,15 +2,39 @@ package middlewares
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/xraph/nexus/model"
 	"github.com/xraph/nexus/pipeline"
 	"github.com/xraph/nexus/provider"
 )
 
+// State keys written by TokenCountingMiddleware.
+const (
+	StateKeyTokenEstimateInput   = "token_estimate_input"
+	StateKeyContextWindow        = "token_estimate_context_window"
+	StateKeyTruncatedMessages    = "context_truncated_messages"
+	StateKeyContextSummaryTokens = "context_summary_tokens"
+)
+
+// Summarizer condenses conversation turns dropped to fit the context
+// window into a short text that is kept in their place.
+type Summarizer func(ctx context.Context, dropped []provider.Message) (string, error)
+
 // TokenCountingMiddleware estimates token usage and enforces context window limits.
+//
+// With a truncating overflow strategy, whole turns are dropped until the
+// request fits its model's context window less the reserved output: leading
+// system messages and the final turn are always kept, and an assistant
+// message with tool calls is dropped together with its tool results. With a
+// Summarizer, the dropped turns are replaced by a system message holding
+// their summary.
 type TokenCountingMiddleware struct {
-	counter model.RequestTokenCounter
+	counter    model.RequestTokenCounter
+	summarizer Summarizer
 }
 
 // NewTokenCounting creates a token counting middleware.
@@ -18,35 +42,42 @@ func NewTokenCounting(counter model.RequestTokenCounter) *TokenCountingMiddlewar
 	return &TokenCountingMiddleware{counter: counter}
 }
 
+// WithSummarizer summarizes turns dropped by truncation.
+func (m *TokenCountingMiddleware) WithSummarizer(s Summarizer) *TokenCountingMiddleware {
+	m.summarizer = s
+	return m
+}
+
 func (m *TokenCountingMiddleware) Name() string  { return "token_counting" }
-func (m *TokenCountingMiddleware) Priority() int { return 220 } // After alias, before routing
+func (m *TokenCountingMiddleware) Priority() int { return 260 } // After alias (250), before cache and routing
 
 func (m *TokenCountingMiddleware) Process(ctx context.Context, req *pipeline.Request, next pipeline.NextFunc) (*pipeline.Response, error) {
 	if m.counter == nil || req.Completion == nil {
 		return next(ctx)
 	}
+	comp := req.Completion
 
 	// Convert provider messages to model messages for estimation
-	msgs := toModelMessages(req.Completion.Messages)
+	msgs := requestModelMessages(comp)
 
 	// Estimate tokens
-	estimate, err := m.counter.EstimateRequest(ctx, msgs, req.Completion.Model, req.Completion.MaxTokens)
+	estimate, err := m.counter.EstimateRequest(ctx, msgs, comp.Model, comp.MaxTokens)
 	if err != nil {
 		// Token estimation is non-fatal — continue
 		return next(ctx)
 	}
 
 	// Store estimate in state for usage tracking
-	req.State["token_estimate_input"] = estimate.InputTokens
-	req.State["token_estimate_context_window"] = estimate.ContextWindow
+	req.State[StateKeyTokenEstimateInput] = estimate.InputTokens
+	req.State[StateKeyContextWindow] = estimate.ContextWindow
 
 	// Check overflow
 	if estimate.Overflow {
 		switch estimate.OverflowStrategy {
-		case model.OverflowTruncateOldest:
-			req.Completion.Messages = truncateOldest(req.Completion.Messages)
-		case model.OverflowTruncateMiddle:
-			req.Completion.Messages = truncateMiddle(req.Completion.Messages)
+		case model.OverflowTruncateOldest, model.OverflowTruncateMiddle:
+			if err := m.truncate(ctx, req, estimate); err != nil {
+				return nil, err
+			}
 		case model.OverflowError:
 			return nil, newContextOverflowError(estimate)
 		default:
@@ -57,50 +88,251 @@ func (m *TokenCountingMiddleware) Process(ctx context.Context, req *pipeline.Req
 	return next(ctx)
 }
 
-func toModelMessages(msgs []provider.Message) []model.Message {
-	result := make([]model.Message, len(msgs))
-	for i, m := range msgs {
-		content, _ := m.Content.(string) //nolint:errcheck // non-string content defaults to ""
-		result[i] = model.Message{
-			Role:    m.Role,
-			Content: content,
+// truncate drops turns until the request fits, optionally replacing them
+// with a summary.
+func (m *TokenCountingMiddleware) truncate(ctx context.Context, req *pipeline.Request, est *model.TokenEstimate) error {
+	comp := req.Completion
+	budget := est.ContextWindow - est.OutputMax
+
+	turns := splitTurns(comp.Messages)
+	lead := leadingSystem(comp.Messages)
+	first := 0
+	for first < len(turns) && turns[first].start < lead {
+		first++
+	}
+	if est.OverflowStrategy == model.OverflowTruncateMiddle && first < len(turns)-1 {
+		first++ // keep the opening turn, drop from the middle
+	}
+	droppable := turns[first:max(first, len(turns)-1)]
+
+	// A turn's cost is what it adds next to a placeholder message, which
+	// cancels the counter's per-request overhead.
+	placeholder := []model.Message{{Role: "user"}}
+	base, err := m.counter.CountMessages(ctx, placeholder, comp.Model)
+	if err != nil {
+		return newContextOverflowError(est)
+	}
+	total := est.InputTokens
+	costs := make([]int, len(droppable))
+	for i, t := range droppable {
+		n, err := m.counter.CountMessages(ctx, append(toModelMessages(comp.Messages[t.start:t.end]), placeholder...), comp.Model)
+		if err != nil {
+			return newContextOverflowError(est)
 		}
+		costs[i] = n - base
 	}
-	return result
+
+	drop := 0
+	// When only system messages are kept before the dropped turns, the
+	// conversation must resume on a user turn: providers reject one that
+	// opens with an assistant message or a tool result.
+	opening := len(droppable) == 0 || droppable[0].start == lead
+	toUserTurn := func() {
+		for opening && drop < len(droppable) && comp.Messages[droppable[drop].start].Role != "user" {
+			total -= costs[drop]
+			drop++
+		}
+	}
+	for drop < len(droppable) && total > budget {
+		total -= costs[drop]
+		drop++
+	}
+	if drop > 0 {
+		toUserTurn()
+	}
+	if drop == 0 {
+		return newContextOverflowError(est)
+	}
+
+	from, to := droppable[0].start, droppable[drop-1].end
+	dropped := comp.Messages[from:to]
+
+	var summary []provider.Message
+	if m.summarizer != nil {
+		if text, err := m.summarizer(ctx, dropped); err == nil && strings.TrimSpace(text) != "" {
+			msg := provider.Message{Role: "system", Content: "Summary of earlier conversation:\n" + text}
+			n, _ := m.counter.CountMessages(ctx, toModelMessages([]provider.Message{msg}), comp.Model) //nolint:errcheck // estimate only
+			// Make room for the summary; the extra turns are covered by it
+			// only approximately, so they count as dropped too.
+			keptDrop, keptTotal := drop, total
+			for drop < len(droppable) && total+n > budget {
+				total -= costs[drop]
+				drop++
+			}
+			toUserTurn()
+			if total+n <= budget {
+				summary = []provider.Message{msg}
+				total += n
+				to = droppable[drop-1].end
+				req.State[StateKeyContextSummaryTokens] = n
+			} else {
+				drop, total = keptDrop, keptTotal
+			}
+		}
+	}
+	if total > budget {
+		return newContextOverflowError(est)
+	}
+
+	kept := make([]provider.Message, 0, len(comp.Messages)-(to-from)+len(summary))
+	kept = append(kept, comp.Messages[:from]...)
+	kept = append(kept, summary...)
+	kept = append(kept, comp.Messages[to:]...)
+	comp.Messages = kept
+
+	req.State[StateKeyTruncatedMessages] = to - from
+	req.State[StateKeyTokenEstimateInput] = total
+	return nil
 }
 
-// truncateOldest keeps the first (system) message and the last N messages.
-func truncateOldest(msgs []provider.Message) []provider.Message {
-	if len(msgs) <= 2 {
-		return msgs
+// turn is a span of messages that must be kept or dropped together.
+type turn struct{ start, end int }
+
+// splitTurns groups messages into turns: an assistant message with tool
+// calls forms one turn with the tool results that follow it.
+func splitTurns(msgs []provider.Message) []turn {
+	var turns []turn
+	for i := 0; i < len(msgs); {
+		end := i + 1
+		if msgs[i].Role == "assistant" && len(msgs[i].ToolCalls) > 0 {
+			for end < len(msgs) && msgs[end].Role == "tool" {
+				end++
+			}
+		}
+		turns = append(turns, turn{start: i, end: end})
+		i = end
 	}
+	return turns
+}
 
-	// Keep first system message + last 2 messages
-	result := make([]provider.Message, 0, 3)
-	result = append(result, msgs[0])
-	result = append(result, msgs[len(msgs)-2:]...)
-	return result
+// leadingSystem returns the number of system messages at the start.
+func leadingSystem(msgs []provider.Message) int {
+	n := 0
+	for n < len(msgs) && msgs[n].Role == "system" {
+		n++
+	}
+	return n
+}
+
+// CountRequestTokens returns the prompt tokens of req as the token-counting
+// middleware estimates them: system field, tool definitions and messages.
+func CountRequestTokens(ctx context.Context, counter model.TokenCounter, req *provider.CompletionRequest) (int, error) {
+	return counter.CountMessages(ctx, requestModelMessages(req), req.Model)
 }
 
-// truncateMiddle keeps the first 2 and last 2 messages, removing the middle.
-func truncateMiddle(msgs []provider.Message) []provider.Message {
-	if len(msgs) <= 4 {
-		return msgs
+// requestModelMessages converts the whole prompt — system field, tool
+// definitions and messages — for estimation.
+func requestModelMessages(req *provider.CompletionRequest) []model.Message {
+	var msgs []model.Message
+	if req.System != "" {
+		msgs = append(msgs, model.Message{Role: "system", Content: req.System})
+	}
+	if len(req.Tools) > 0 {
+		if b, err := json.Marshal(req.Tools); err == nil {
+			msgs = append(msgs, model.Message{Role: "system", Content: string(b)})
+		}
 	}
+	return append(msgs, toModelMessages(req.Messages)...)
+}
 
-	result := make([]provider.Message, 0, 4)
-	result = append(result, msgs[:2]...)
-	result = append(result, msgs[len(msgs)-2:]...)
+// toModelMessages flattens messages to their text — string content, text
+// parts and tool-call names and arguments — and counts image parts.
+func toModelMessages(msgs []provider.Message) []model.Message {
+	result := make([]model.Message, len(msgs))
+	for i, m := range msgs {
+		var b strings.Builder
+		images := 0
+		switch c := m.Content.(type) {
+		case string:
+			b.WriteString(c)
+		case []provider.ContentPart:
+			for _, p := range c {
+				if p.Text != "" {
+					b.WriteString(p.Text)
+					b.WriteByte('\n')
+				} else if p.ImageURL != "" || p.Data != "" {
+					images++
+				}
+			}
+		case []any:
+			for _, p := range c {
+				pm, ok := p.(map[string]any)
+				if !ok {
+					continue
+				}
+				if text, ok := pm["text"].(string); ok {
+					b.WriteString(text)
+					b.WriteByte('\n')
+				} else if typ, _ := pm["type"].(string); strings.HasPrefix(typ, "image") {
+					images++
+				}
+			}
+		}
+		for _, tc := range m.ToolCalls {
+			b.WriteString(tc.Function.Name)
+			b.WriteByte('\n')
+			b.WriteString(tc.Function.Arguments)
+			b.WriteByte('\n')
+		}
+		result[i] = model.Message{
+			Role:    m.Role,
+			Content: b.String(),
+			Images:  images,
+		}
+	}
 	return result
 }
 
+// NewModelSummarizer returns a Summarizer that asks model on the provider
+// registered as providerName. The call goes directly to the provider, not
+// through the pipeline.
+func NewModelSummarizer(providers provider.Registry, providerName, modelID string) Summarizer {
+	return func(ctx context.Context, dropped []provider.Message) (string, error) {
+		p, ok := providers.Get(providerName)
+		if !ok {
+			return "", fmt.Errorf("summarizer: provider %q not registered", providerName)
+		}
+		var transcript strings.Builder
+		for _, m := range toModelMessages(dropped) {
+			if m.Content == "" {
+				continue
+			}
+			transcript.WriteString(m.Role)
+			transcript.WriteString(": ")
+			transcript.WriteString(m.Content)
+			transcript.WriteString("\n")
+		}
+		temp := 0.0
+		resp, err := p.Complete(ctx, &provider.CompletionRequest{
+			Model: modelID,
+			Messages: []provider.Message{
+				{Role: "system", Content: "Summarize the conversation below in a few sentences. Keep names, numbers, decisions and open questions. Reply with the summary only."},
+				{Role: "user", Content: transcript.String()},
+			},
+			MaxTokens:   512,
+			Temperature: &temp,
+		})
+		if err != nil {
+			return "", fmt.Errorf("summarizer: %w", err)
+		}
+		if len(resp.Choices) == 0 {
+			return "", errors.New("summarizer: empty response")
+		}
+		text, ok := resp.Choices[0].Message.Content.(string)
+		if !ok {
+			return "", errors.New("summarizer: non-text response")
+		}
+		return text, nil
+	}
+}
+
 type contextOverflowError struct {
 	inputTokens   int
 	contextWindow int
 }
 
 func (e *contextOverflowError) Error() string {
-	return "nexus: request exceeds context window"
+	return fmt.Sprintf("nexus: request exceeds context window (%d input tokens, %d window)", e.inputTokens, e.contextWindow)
 }
 
 func newContextOverflowError(est *model.TokenEstimate) error {
@@ -0,0 +1,186 @@
+package middlewares_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/model"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/provider"
+)
+
+// catalog is a model.Service with fixed model limits.
+type catalog map[string]provider.Model
+
+func (c catalog) ListModels(context.Context) ([]provider.Model, error) { return nil, nil }
+func (c catalog) ResolveAlias(_ context.Context, alias, _ string) (string, string, error) {
+	return alias, "", nil
+}
+func (c catalog) Get(_ context.Context, id string) (*provider.Model, error) {
+	if m, ok := c[id]; ok {
+		return &m, nil
+	}
+	return nil, nil
+}
+
+func longConversation() []provider.Message {
+	filler := strings.Repeat("lorem ipsum dolor sit amet ", 40)
+	msgs := []provider.Message{{Role: "system", Content: "You are helpful."}}
+	for i := 0; i < 6; i++ {
+		msgs = append(msgs,
+			provider.Message{Role: "user", Content: filler},
+			provider.Message{Role: "assistant", Content: filler},
+		)
+	}
+	msgs = append(msgs,
+		provider.Message{Role: "user", Content: "check the weather"},
+		provider.Message{Role: "assistant", ToolCalls: []provider.ToolCall{{
+			ID: "call_1", Type: "function",
+			Function: provider.ToolCallFunc{Name: "weather", Arguments: `{"city":"` + filler + `"}`},
+		}}},
+		provider.Message{Role: "tool", ToolCallID: "call_1", Content: filler},
+		provider.Message{Role: "user", Content: "and tomorrow?"},
+	)
+	return msgs
+}
+
+func runTokenCounting(t *testing.T, mw *middlewares.TokenCountingMiddleware, msgs []provider.Message) (*pipeline.Request, error) {
+	t.Helper()
+	req := &pipeline.Request{
+		Completion: &provider.CompletionRequest{Model: "claude-test", Messages: msgs, MaxTokens: 100},
+		Type:       pipeline.RequestCompletion,
+		State:      map[string]any{},
+	}
+	_, err := mw.Process(context.Background(), req, func(context.Context) (*pipeline.Response, error) {
+		return &pipeline.Response{}, nil
+	})
+	return req, err
+}
+
+func TestTokenCounting_TruncatesWholeTurnsToBudget(t *testing.T) {
+	t.Parallel()
+
+	models := catalog{"claude-test": {ID: "claude-test", ContextWindow: 1500}}
+	counter := model.NewTokenCounter(models, model.WithOverflowStrategy(model.OverflowTruncateOldest))
+	mw := middlewares.NewTokenCounting(counter)
+
+	req, err := runTokenCounting(t, mw, longConversation())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	msgs := req.Completion.Messages
+
+	if msgs[0].Role != "system" {
+		t.Errorf("system message dropped: first role %q", msgs[0].Role)
+	}
+	if last := msgs[len(msgs)-1]; last.Content != "and tomorrow?" {
+		t.Errorf("final turn dropped: %v", last.Content)
+	}
+	for i, m := range msgs {
+		if m.Role == "tool" && (i == 0 || len(msgs[i-1].ToolCalls) == 0 && msgs[i-1].Role != "tool") {
+			t.Errorf("tool result at %d kept without its tool call", i)
+		}
+		if len(m.ToolCalls) > 0 && (i+1 >= len(msgs) || msgs[i+1].Role != "tool") {
+			t.Errorf("tool call at %d kept without its result", i)
+		}
+	}
+	if n, _ := req.State[middlewares.StateKeyTruncatedMessages].(int); n == 0 {
+		t.Error("no messages truncated")
+	}
+	input, _ := req.State[middlewares.StateKeyTokenEstimateInput].(int)
+	if input+100 > 1500 {
+		t.Errorf("estimate after truncation = %d, exceeds budget", input)
+	}
+	got, err := counter.CountMessages(context.Background(), modelMessages(msgs), "claude-test")
+	if err != nil || got+100 > 1500 {
+		t.Errorf("recounted %d tokens (err %v), exceeds budget", got, err)
+	}
+}
+
+func TestTokenCounting_SummarizesDroppedTurns(t *testing.T) {
+	t.Parallel()
+
+	models := catalog{"claude-test": {ID: "claude-test", ContextWindow: 1500}}
+	counter := model.NewTokenCounter(models, model.WithOverflowStrategy(model.OverflowTruncateOldest))
+	var summarized int
+	mw := middlewares.NewTokenCounting(counter).WithSummarizer(func(_ context.Context, dropped []provider.Message) (string, error) {
+		summarized = len(dropped)
+		return "The user asked about lorem ipsum.", nil
+	})
+
+	req, err := runTokenCounting(t, mw, longConversation())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if summarized == 0 {
+		t.Fatal("summarizer not called")
+	}
+	msgs := req.Completion.Messages
+	if len(msgs) < 2 || msgs[1].Role != "system" || !strings.Contains(msgs[1].Content.(string), "lorem ipsum.") {
+		t.Errorf("summary not inserted after system prompt: %+v", msgs[:2])
+	}
+}
+
+func TestTokenCounting_ErrorStrategyRejects(t *testing.T) {
+	t.Parallel()
+
+	models := catalog{"claude-test": {ID: "claude-test", ContextWindow: 1500}}
+	mw := middlewares.NewTokenCounting(model.NewTokenCounter(models, model.WithOverflowStrategy(model.OverflowError)))
+
+	if _, err := runTokenCounting(t, mw, longConversation()); err == nil {
+		t.Fatal("expected context overflow error")
+	}
+
+	// Unknown models have no window and pass through.
+	req, err := runTokenCounting(t, mw, []provider.Message{{Role: "user", Content: []provider.ContentPart{
+		{Type: "text", Text: "describe this"},
+		{Type: "image_url", ImageURL: "https://example.com/a.png"},
+	}}})
+	if err != nil {
+		t.Fatalf("short request rejected: %v", err)
+	}
+	if n, _ := req.State[middlewares.StateKeyTokenEstimateInput].(int); n < 765 {
+		t.Errorf("content parts not counted: estimate %d", n)
+	}
+}
+
+// modelMessages flattens string contents for recounting.
+func modelMessages(msgs []provider.Message) []model.Message {
+	out := make([]model.Message, len(msgs))
+	for i, m := range msgs {
+		text, _ := m.Content.(string)
+		for _, tc := range m.ToolCalls {
+			text += tc.Function.Name + "\n" + tc.Function.Arguments + "\n"
+		}
+		out[i] = model.Message{Role: m.Role, Content: text}
+	}
+	return out
+}
+
+func TestTokenCounting_TruncationResumesOnUserTurn(t *testing.T) {
+	t.Parallel()
+
+	models := catalog{"claude-test": {ID: "claude-test", ContextWindow: 400}}
+	counter := model.NewTokenCounter(models, model.WithOverflowStrategy(model.OverflowTruncateOldest))
+	mw := middlewares.NewTokenCounting(counter)
+
+	// Dropping the long opening question alone would fit, leaving the
+	// assistant's reply to start the conversation.
+	req, err := runTokenCounting(t, mw, []provider.Message{
+		{Role: "system", Content: "You are helpful."},
+		{Role: "user", Content: strings.Repeat("lorem ipsum dolor sit amet ", 40)},
+		{Role: "assistant", Content: "ok"},
+		{Role: "user", Content: "thanks"},
+		{Role: "assistant", Content: "welcome"},
+		{Role: "user", Content: "and tomorrow?"},
+	})
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	msgs := req.Completion.Messages
+	if len(msgs) != 4 || msgs[1].Role != "user" || msgs[1].Content != "thanks" {
+		t.Fatalf("messages after truncation = %+v, want the conversation to resume at the next user turn", msgs)
+	}
+}
@@ -82,13 +82,31 @@ type streamingTransformer struct {
 	acc        *provider.Accumulator
 
 	once sync.Once
+
+	// Chunks released by transform.StreamFlusher implementations once the
+	// upstream hit EOF.
+	flushed bool
+	pending []*provider.StreamChunk
 }
 
 func (s *streamingTransformer) Next(ctx context.Context) (*provider.StreamChunk, error) {
 	for {
+		if s.flushed {
+			if len(s.pending) == 0 {
+				return nil, io.EOF
+			}
+			chunk := s.pending[0]
+			s.pending = s.pending[1:]
+			s.acc.Add(chunk)
+			return chunk, nil
+		}
+
 		chunk, err := s.inner.Next(ctx)
 		if errors.Is(err, io.EOF) {
-			return nil, err
+			if err := s.flush(); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		if err != nil {
 			return nil, err
@@ -97,20 +115,11 @@ func (s *streamingTransformer) Next(ctx context.Context) (*provider.StreamChunk,
 			continue
 		}
 
-		current := chunk
-		dropped := false
-		for _, t := range s.transforms {
-			next, err := t.TransformChunk(s.ctx, s.req, current)
-			if err != nil {
-				return nil, err
-			}
-			if next == nil {
-				dropped = true
-				break
-			}
-			current = next
+		current, err := s.apply(s.transforms, chunk)
+		if err != nil {
+			return nil, err
 		}
-		if dropped {
+		if current == nil {
 			continue
 		}
 		s.acc.Add(current)
@@ -118,6 +127,48 @@ func (s *streamingTransformer) Next(ctx context.Context) (*provider.StreamChunk,
 	}
 }
 
+// apply runs chunk through transforms, returning nil if one drops it.
+func (s *streamingTransformer) apply(transforms []transform.StreamingOutputTransform, chunk *provider.StreamChunk) (*provider.StreamChunk, error) {
+	current := chunk
+	for _, t := range transforms {
+		next, err := t.TransformChunk(s.ctx, s.req, current)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			return nil, nil
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// flush collects held-back chunks from every transform.StreamFlusher.
+func (s *streamingTransformer) flush() error {
+	s.flushed = true
+	for i, t := range s.transforms {
+		f, ok := t.(transform.StreamFlusher)
+		if !ok {
+			continue
+		}
+		chunk, err := f.FlushStream(s.ctx, s.req)
+		if err != nil {
+			return err
+		}
+		if chunk == nil {
+			continue
+		}
+		chunk, err = s.apply(s.transforms[i+1:], chunk)
+		if err != nil {
+			return err
+		}
+		if chunk != nil {
+			s.pending = append(s.pending, chunk)
+		}
+	}
+	return nil
+}
+
 func (s *streamingTransformer) Close() error {
 	s.once.Do(func() {
 		final := s.acc.Finalize(s.inner.Usage)
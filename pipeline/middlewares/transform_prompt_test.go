@@ -0,0 +1,109 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/prompt"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/store"
+	"github.com/xraph/nexus/transform"
+)
+
+func TestPromptTemplateTransform_RendersLabelledVersion(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	prompts := prompt.NewService(store.NewMemory().Prompts())
+	for _, in := range []*prompt.CreateInput{
+		{Name: "support", Content: "You help {{.customer}} customers.", Labels: []string{prompt.LabelProduction}},
+		{Name: "support", Content: "You are {{.customer}}'s support agent. Be brief."},
+		{Name: "support", TenantID: "acme", Content: "Acme support for {{.customer}}."},
+	} {
+		if _, err := prompts.Create(ctx, in); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	reg := transform.NewRegistry()
+	reg.Register(transform.NewPromptTemplate(prompts))
+	rec := newRecordingUsage()
+	chain := func(tenantID, version string) (*pipeline.Request, error) {
+		req := &pipeline.Request{
+			Completion: &provider.CompletionRequest{
+				Model:           "m",
+				Messages:        []provider.Message{{Role: "user", Content: "hi"}},
+				PromptID:        "support",
+				PromptVersion:   provider.PromptRef(version),
+				PromptVariables: map[string]any{"customer": "Globex"},
+			},
+			Type:  pipeline.RequestCompletion,
+			State: map[string]any{},
+		}
+		_, err := middlewares.NewUsage(rec).Process(pipeline.WithTenantID(ctx, tenantID), req, func(ctx context.Context) (*pipeline.Response, error) {
+			return middlewares.NewTransform(reg).Process(ctx, req, func(context.Context) (*pipeline.Response, error) {
+				return &pipeline.Response{Completion: &provider.CompletionResponse{}}, nil
+			})
+		})
+		return req, err
+	}
+
+	tests := []struct {
+		tenant, version string
+		want            string
+		wantVersion     int
+	}{
+		{"", "", "You help Globex customers.", 1}, // production label, not latest
+		{"", "2", "You are Globex's support agent. Be brief.", 2},
+		{"acme", "", "Acme support for Globex.", 1}, // tenant template shadows shared
+	}
+	for _, tt := range tests {
+		req, err := chain(tt.tenant, tt.version)
+		if err != nil {
+			t.Fatalf("tenant %q version %q: %v", tt.tenant, tt.version, err)
+		}
+		msgs := req.Completion.Messages
+		if len(msgs) != 2 || msgs[0].Role != "system" || msgs[0].Content != tt.want {
+			t.Errorf("tenant %q version %q: messages = %+v", tt.tenant, tt.version, msgs)
+		}
+
+		select {
+		case <-rec.done:
+		case <-time.After(time.Second):
+			t.Fatal("usage not recorded")
+		}
+		rec.mu.Lock()
+		got := rec.records[len(rec.records)-1]
+		rec.mu.Unlock()
+		if got.PromptID != "support" || got.PromptVersion != tt.wantVersion {
+			t.Errorf("usage prompt = %s@%d, want support@%d", got.PromptID, got.PromptVersion, tt.wantVersion)
+		}
+	}
+}
+
+func TestPromptTemplateTransform_Errors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	prompts := prompt.NewService(store.NewMemory().Prompts())
+	if _, err := prompts.Create(ctx, &prompt.CreateInput{Name: "p", Content: "{{.missing"}); err == nil {
+		t.Error("invalid template syntax was accepted")
+	}
+	if _, err := prompts.Create(ctx, &prompt.CreateInput{Name: "p", Content: "Hello {{.name}}"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tr := transform.NewPromptTemplate(prompts)
+	req := &provider.CompletionRequest{PromptID: "p"}
+	if err := tr.TransformInput(ctx, req); err == nil {
+		t.Error("missing variable rendered without error")
+	}
+	req = &provider.CompletionRequest{PromptID: "p", PromptVersion: "canary"}
+	if err := tr.TransformInput(ctx, req); !errors.Is(err, prompt.ErrNotFound) {
+		t.Errorf("unknown label: err = %v, want ErrNotFound", err)
+	}
+}
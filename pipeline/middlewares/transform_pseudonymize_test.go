@@ -0,0 +1,112 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/testutil"
+	"github.com/xraph/nexus/transform"
+)
+
+func pseudonymizeRequest(stream bool) *pipeline.Request {
+	typ := pipeline.RequestCompletion
+	if stream {
+		typ = pipeline.RequestStream
+	}
+	return &pipeline.Request{
+		Completion: &provider.CompletionRequest{
+			Model:  "m",
+			Stream: stream,
+			Messages: []provider.Message{{
+				Role:    "user",
+				Content: "Email jane@example.com and cc jane@example.com, then bob@example.org.",
+			}},
+		},
+		Type:  typ,
+		State: map[string]any{},
+	}
+}
+
+func pseudonymizeMiddleware() *middlewares.TransformMiddleware {
+	reg := transform.NewRegistry()
+	reg.Register(transform.NewPseudonymizer())
+	return middlewares.NewTransform(reg)
+}
+
+func TestTransformMiddleware_PseudonymizesAndRestores(t *testing.T) {
+	t.Parallel()
+
+	req := pseudonymizeRequest(false)
+	var sent string
+	next := func(_ context.Context) (*pipeline.Response, error) {
+		sent, _ = req.Completion.Messages[0].Content.(string) //nolint:errcheck // checked below
+		return &pipeline.Response{Completion: &provider.CompletionResponse{
+			Choices: []provider.Choice{{Message: provider.Message{
+				Role:    "assistant",
+				Content: "I will write to EMAIL_1 and EMAIL_2.",
+				ToolCalls: []provider.ToolCall{{
+					ID:       "call_1",
+					Function: provider.ToolCallFunc{Name: "send", Arguments: `{"to":"EMAIL_2"}`},
+				}},
+			}}},
+		}}, nil
+	}
+
+	resp, err := pseudonymizeMiddleware().Process(context.Background(), req, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "Email EMAIL_1 and cc EMAIL_1, then EMAIL_2."; sent != want {
+		t.Errorf("provider saw %q, want %q", sent, want)
+	}
+	msg := resp.Completion.Choices[0].Message
+	if got, want := msg.Content, "I will write to jane@example.com and bob@example.org."; got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+	if got, want := msg.ToolCalls[0].Function.Arguments, `{"to":"bob@example.org"}`; got != want {
+		t.Errorf("arguments = %q, want %q", got, want)
+	}
+}
+
+func TestTransformMiddleware_PseudonymizerRestoresSplitStreamTokens(t *testing.T) {
+	t.Parallel()
+
+	chunks := []*provider.StreamChunk{
+		{Delta: provider.Delta{Content: "Contact EMA"}},
+		{Delta: provider.Delta{Content: "IL_1 or EMAIL"}},
+		{Delta: provider.Delta{Content: "_2"}}, // stream ends without a finish chunk
+	}
+	upstream := testutil.NewFakeStream(chunks, nil)
+
+	req := pseudonymizeRequest(true)
+	resp, err := pseudonymizeMiddleware().Process(context.Background(), req, func(_ context.Context) (*pipeline.Response, error) {
+		return &pipeline.Response{Stream: upstream}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	for {
+		c, err := resp.Stream.Next(context.Background())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.WriteString(c.Delta.Content)
+	}
+	_ = resp.Stream.Close()
+
+	if got, want := out.String(), "Contact jane@example.com or bob@example.org"; got != want {
+		t.Errorf("streamed %q, want %q", got, want)
+	}
+}
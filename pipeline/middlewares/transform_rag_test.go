@@ -0,0 +1,127 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/rag"
+	"github.com/xraph/nexus/transform"
+)
+
+// bagOfWords embeds text as hashed word counts, enough for similarity.
+var bagOfWords = rag.EmbedderFunc(func(_ context.Context, texts []string) ([][]float64, error) {
+	out := make([][]float64, len(texts))
+	for i, text := range texts {
+		v := make([]float64, 64)
+		for _, w := range strings.Fields(strings.ToLower(text)) {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(strings.Trim(w, ".,?!")))
+			v[h.Sum32()%64]++
+		}
+		out[i] = v
+	}
+	return out, nil
+})
+
+func TestRAGTransform_TenantScopedRetrievalWithCitations(t *testing.T) {
+	t.Parallel()
+
+	retriever := rag.NewRetriever(bagOfWords, rag.NewMemoryIndex())
+	_, n, err := retriever.Ingest(context.Background(),
+		rag.Document{TenantID: "acme", Content: "Acme refunds are processed within 14 days.", Source: "https://acme.example/refunds", Title: "Refund policy"},
+		rag.Document{TenantID: "globex", Content: "Globex refunds are processed within 30 days.", Source: "https://globex.example/refunds"},
+		rag.Document{Content: "Support hours are 9am to 5pm.", Source: "https://example.com/support"},
+	)
+	if err != nil || n != 3 {
+		t.Fatalf("Ingest: %d chunks, %v", n, err)
+	}
+
+	reg := transform.NewRegistry()
+	reg.Register(transform.NewRAG(retriever).WithMaxResults(2))
+	mw := middlewares.NewTransform(reg)
+
+	req := &pipeline.Request{
+		Completion: &provider.CompletionRequest{
+			Model:    "m",
+			Messages: []provider.Message{{Role: "user", Content: "How long do refunds take?"}},
+		},
+		Type:  pipeline.RequestCompletion,
+		State: map[string]any{},
+	}
+	var injected string
+	next := func(_ context.Context) (*pipeline.Response, error) {
+		injected, _ = req.Completion.Messages[0].Content.(string)
+		return &pipeline.Response{Completion: &provider.CompletionResponse{
+			Choices: []provider.Choice{{Message: provider.Message{Role: "assistant", Content: "Within 14 days [1]."}}},
+		}}, nil
+	}
+
+	resp, err := mw.Process(pipeline.WithTenantID(context.Background(), "acme"), req, next)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if !strings.Contains(injected, "14 days") || strings.Contains(injected, "30 days") {
+		t.Errorf("context not scoped to tenant:\n%s", injected)
+	}
+	if !strings.Contains(injected, "Refund policy, https://acme.example/refunds") {
+		t.Errorf("context not attributed:\n%s", injected)
+	}
+
+	cits := resp.Completion.Citations
+	if len(cits) != 1 || cits[0].URL != "https://acme.example/refunds" || cits[0].Title != "Refund policy" {
+		t.Errorf("citations = %+v, want only the cited acme source", cits)
+	}
+}
+
+type failingRAG struct{}
+
+func (failingRAG) Retrieve(context.Context, string, int) ([]transform.RAGChunk, error) {
+	return nil, errors.New("index offline")
+}
+
+func TestRAGTransform_RetrievalErrors(t *testing.T) {
+	t.Parallel()
+
+	req := func() *provider.CompletionRequest {
+		return &provider.CompletionRequest{Messages: []provider.Message{{Role: "user", Content: "hi"}}}
+	}
+
+	optional := req()
+	if err := transform.NewRAG(failingRAG{}).TransformInput(context.Background(), optional); err != nil {
+		t.Fatalf("optional retrieval failed the request: %v", err)
+	}
+	if got := optional.State[transform.StateKeyRAGError]; got != "index offline" {
+		t.Errorf("recorded error = %v", got)
+	}
+
+	if err := transform.NewRAG(failingRAG{}).WithRequired(true).TransformInput(context.Background(), req()); err == nil {
+		t.Error("required retrieval error was swallowed")
+	}
+}
+
+func TestRAGTransform_TokenBudget(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("word ", 400) // ~500 estimated tokens
+	chunks := staticRAG{{Content: long, Source: "a"}, {Content: long, Source: "b"}, {Content: "short", Source: "c"}}
+	r := &provider.CompletionRequest{Messages: []provider.Message{{Role: "user", Content: "q"}}}
+	if err := transform.NewRAG(chunks).WithTokenBudget(700).TransformInput(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := r.State[transform.StateKeyRAGChunks].([]transform.RAGChunk)
+	if len(got) != 1 || got[0].Source != "a" {
+		t.Errorf("kept %d chunks, want only the first within budget", len(got))
+	}
+}
+
+type staticRAG []transform.RAGChunk
+
+func (s staticRAG) Retrieve(context.Context, string, int) ([]transform.RAGChunk, error) {
+	return s, nil
+}
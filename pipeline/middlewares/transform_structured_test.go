@@ -0,0 +1,198 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/xraph/nexus/model"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/transform"
+)
+
+// reaskProvider replies to re-asks with a fixed answer.
+type reaskProvider struct {
+	name    string
+	reply   string
+	cost    float64
+	native  bool
+	models  []string
+	calls   int
+	lastReq *provider.CompletionRequest
+}
+
+func (p *reaskProvider) Name() string { return p.name }
+func (p *reaskProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Chat: true, JSONSchema: p.native}
+}
+func (p *reaskProvider) Models(context.Context) ([]provider.Model, error) {
+	out := make([]provider.Model, len(p.models))
+	for i, id := range p.models {
+		out[i] = provider.Model{ID: id}
+	}
+	return out, nil
+}
+func (p *reaskProvider) Complete(_ context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	p.calls++
+	p.lastReq = req
+	return &provider.CompletionResponse{
+		Choices: []provider.Choice{{Message: provider.Message{Role: "assistant", Content: p.reply}}},
+		Usage:   provider.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		Cost:    p.cost,
+	}, nil
+}
+func (p *reaskProvider) CompleteStream(context.Context, *provider.CompletionRequest) (provider.Stream, error) {
+	return nil, errors.New("not supported")
+}
+func (p *reaskProvider) Embed(context.Context, *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	return nil, errors.New("not supported")
+}
+func (p *reaskProvider) Healthy(context.Context) bool { return true }
+
+var personSchema = map[string]any{
+	"type":                 "object",
+	"required":             []any{"name", "age"},
+	"additionalProperties": false,
+	"properties": map[string]any{
+		"name": map[string]any{"type": "string"},
+		"age":  map[string]any{"type": "integer", "minimum": 0.0},
+	},
+}
+
+func structuredRequest(strict bool) *pipeline.Request {
+	return &pipeline.Request{
+		Completion: &provider.CompletionRequest{
+			Model:    "llama3",
+			Provider: "ollama",
+			Messages: []provider.Message{{Role: "user", Content: "Who?"}},
+			ResponseFormat: &provider.ResponseFormat{
+				Type:       "json_schema",
+				JSONSchema: &provider.JSONSchemaDef{Name: "person", Schema: personSchema, Strict: strict},
+			},
+		},
+		Type:  pipeline.RequestCompletion,
+		State: map[string]any{},
+	}
+}
+
+func structuredResponse(content string) pipeline.NextFunc {
+	return func(_ context.Context) (*pipeline.Response, error) {
+		return &pipeline.Response{Completion: &provider.CompletionResponse{
+			Provider: "ollama",
+			Choices:  []provider.Choice{{Message: provider.Message{Role: "assistant", Content: content}}},
+			Usage:    provider.Usage{TotalTokens: 20},
+		}}, nil
+	}
+}
+
+func TestStructuredOutput_RepairsAndInjectsSchema(t *testing.T) {
+	t.Parallel()
+
+	providers := provider.NewRegistry()
+	providers.Register(&reaskProvider{name: "ollama"})
+	reg := transform.NewRegistry()
+	reg.Register(transform.NewStructuredOutput(providers))
+
+	req := structuredRequest(true)
+	resp, err := middlewares.NewTransform(reg).Process(context.Background(), req,
+		structuredResponse("Sure!\n```json\n{\"name\": \"Ada\", \"age\": 36,}\n```"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Completion.Choices[0].Message.Content; got != `{"name": "Ada", "age": 36}` {
+		t.Errorf("content = %q", got)
+	}
+	if req.Completion.Messages[0].Role != "system" {
+		t.Error("schema instructions not injected for a provider without native json_schema")
+	}
+}
+
+func TestStructuredOutput_ReasksWithValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	rp := &reaskProvider{name: "ollama", reply: `{"name": "Ada", "age": 36}`, cost: 0.25}
+	providers := provider.NewRegistry()
+	providers.Register(rp)
+	reg := transform.NewRegistry()
+	reg.Register(transform.NewStructuredOutput(providers).WithMaxRetries(1))
+
+	resp, err := middlewares.NewTransform(reg).Process(context.Background(), structuredRequest(true),
+		structuredResponse(`{"name": "Ada", "age": "thirty-six"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rp.calls != 1 {
+		t.Fatalf("re-asks = %d, want 1", rp.calls)
+	}
+	last := rp.lastReq.Messages[len(rp.lastReq.Messages)-1]
+	if s, ok := last.Content.(string); !ok || s == "" {
+		t.Error("re-ask did not include validation errors")
+	}
+	if got := resp.Completion.Choices[0].Message.Content; got != `{"name": "Ada", "age": 36}` {
+		t.Errorf("content = %q", got)
+	}
+	if resp.Completion.Usage.TotalTokens != 35 {
+		t.Errorf("usage = %d, want re-ask tokens included", resp.Completion.Usage.TotalTokens)
+	}
+	if resp.Completion.Cost != 0.25 {
+		t.Errorf("cost = %v, want re-ask cost included", resp.Completion.Cost)
+	}
+}
+
+func TestStructuredOutput_ResolvesAliasesForNativeSupport(t *testing.T) {
+	t.Parallel()
+
+	providers := provider.NewRegistry()
+	providers.Register(&reaskProvider{name: "openai", native: true, models: []string{"gpt-4o"}})
+	providers.Register(&reaskProvider{name: "ollama", models: []string{"llama3"}})
+	aliases := model.NewAliasRegistry()
+	_ = aliases.Register(&model.Alias{Name: "smart", Targets: []model.AliasTarget{{Provider: "openai", Model: "gpt-4o"}}})
+	_ = aliases.Register(&model.Alias{Name: "local", Targets: []model.AliasTarget{{Provider: "ollama", Model: "llama3"}}})
+	reg := transform.NewRegistry()
+	reg.Register(transform.NewStructuredOutput(providers).WithAliases(aliases))
+
+	for alias, inject := range map[string]bool{"smart": false, "local": true} {
+		req := structuredRequest(false)
+		req.Completion.Provider = ""
+		req.Completion.Model = alias
+		// Spare capacity the injection must not write into.
+		msgs := make([]provider.Message, 1, 4)
+		msgs[0] = req.Completion.Messages[0]
+		req.Completion.Messages = msgs
+
+		if _, err := middlewares.NewTransform(reg).Process(context.Background(), req,
+			structuredResponse(`{"name": "Ada", "age": 36}`)); err != nil {
+			t.Fatal(err)
+		}
+		if got := req.Completion.Messages[0].Role == "system"; got != inject {
+			t.Errorf("%s: schema injected = %v, want %v", alias, got, inject)
+		}
+		if msgs[0].Role != "user" || msgs[:2][1].Role != "" {
+			t.Errorf("%s: injection wrote into the caller's messages: %+v", alias, msgs[:2])
+		}
+	}
+}
+
+func TestStructuredOutput_StrictFailureReturnsError(t *testing.T) {
+	t.Parallel()
+
+	reg := transform.NewRegistry()
+	reg.Register(transform.NewStructuredOutput(nil).WithMaxRetries(0))
+
+	_, err := middlewares.NewTransform(reg).Process(context.Background(), structuredRequest(true),
+		structuredResponse(`{"name": "Ada"}`))
+	if !errors.Is(err, transform.ErrStructuredOutput) {
+		t.Fatalf("err = %v, want ErrStructuredOutput", err)
+	}
+
+	resp, err := middlewares.NewTransform(reg).Process(context.Background(), structuredRequest(false),
+		structuredResponse(`{"name": "Ada"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := resp.Completion.State[transform.StateKeyStructuredOutputErrors]; !ok {
+		t.Error("non-strict failure not recorded in response state")
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/xraph/nexus/id"
+	"github.com/xraph/nexus/model"
 	"github.com/xraph/nexus/pipeline"
 	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/transform"
 	"github.com/xraph/nexus/usage"
 )
 
@@ -18,6 +20,12 @@ import (
 // at handler-return time, it wraps resp.Stream so usage records are emitted
 // when the consumer drains and closes the stream — without this, streamed
 // traffic silently bypasses billing.
+//
+// Records carry the tenant and key from the context. Requests served by
+// another caller's in-flight call (StateKeyCoalesced) are recorded as
+// cached with zero cost, so the shared call is billed once, to the leader.
+// Batch items (pipeline.BatchID) are not recorded here: the batch service
+// records each once, with its job ID and any batch discount.
 type UsageMiddleware struct {
 	usage usage.Service
 }
@@ -31,7 +39,7 @@ func (m *UsageMiddleware) Name() string  { return "usage" }
 func (m *UsageMiddleware) Priority() int { return 550 } // After everything else
 
 func (m *UsageMiddleware) Process(ctx context.Context, req *pipeline.Request, next pipeline.NextFunc) (*pipeline.Response, error) {
-	if m.usage == nil {
+	if m.usage == nil || pipeline.BatchID(ctx) != "" {
 		return next(ctx)
 	}
 
@@ -45,14 +53,35 @@ func (m *UsageMiddleware) Process(ctx context.Context, req *pipeline.Request, ne
 		Latency:   elapsed,
 		CreatedAt: time.Now(),
 	}
+	rec.TenantID, _ = id.ParseTenantID(pipeline.TenantID(ctx)) //nolint:errcheck // non-ID tenants are recorded as Nil
+	rec.KeyID, _ = id.ParseKeyID(pipeline.KeyID(ctx))          //nolint:errcheck // non-ID keys are recorded as Nil
+	shared, _ := req.State[StateKeyCoalesced].(bool)
 
 	if req.Completion != nil {
 		rec.Model = req.Completion.Model
+		// Recorded by the prompt template transform when the request
+		// referenced a registry template.
+		rec.PromptID, _ = req.Completion.State[transform.StateKeyPromptID].(string)
+		rec.PromptVersion, _ = req.Completion.State[transform.StateKeyPromptVersion].(int)
+	}
+
+	switch {
+	case req.Image != nil:
+		rec.Model = req.Image.Model
+	case req.Transcription != nil:
+		rec.Model = req.Transcription.Model
+	case req.Speech != nil:
+		rec.Model = req.Speech.Model
+	case req.Rerank != nil:
+		rec.Model = req.Rerank.Model
 	}
 
 	if providerName, ok := req.State["provider_name"].(string); ok {
 		rec.Provider = providerName
 	}
+	if providerType, ok := req.State["provider_type"].(string); ok {
+		rec.ProviderType = providerType
+	}
 
 	switch {
 	case err != nil:
@@ -63,11 +92,13 @@ func (m *UsageMiddleware) Process(ctx context.Context, req *pipeline.Request, ne
 		// totals are captured from Stream.Usage() once the upstream finishes
 		// (and from any final response synthesised by StreamLifecycle).
 		rec.StatusCode = 200
+		rec.Cached = shared
 		resp.Stream = &usageRecordingStream{
 			inner:   resp.Stream,
 			rec:     rec,
 			req:     req,
 			start:   start,
+			shared:  shared,
 			recordF: m.recordAsync,
 		}
 	case resp != nil && resp.Completion != nil:
@@ -75,8 +106,38 @@ func (m *UsageMiddleware) Process(ctx context.Context, req *pipeline.Request, ne
 		rec.PromptTokens = resp.Completion.Usage.PromptTokens
 		rec.CompletionTokens = resp.Completion.Usage.CompletionTokens
 		rec.TotalTokens = resp.Completion.Usage.TotalTokens
-		rec.Cached = resp.Completion.Cached
-		rec.CostUSD = resp.Completion.Cost
+		rec.Cached = resp.Completion.Cached || shared
+		if !shared {
+			rec.CostUSD = resp.Completion.Cost
+		}
+		m.recordAsync(rec)
+	case resp != nil && resp.Image != nil:
+		rec.StatusCode = 200
+		rec.PromptTokens = resp.Image.Usage.PromptTokens
+		rec.CompletionTokens = resp.Image.Usage.CompletionTokens
+		rec.TotalTokens = resp.Image.Usage.TotalTokens
+		rec.CostUSD = resp.Image.Cost
+		m.recordAsync(rec)
+	case resp != nil && resp.Transcription != nil:
+		rec.StatusCode = 200
+		rec.PromptTokens = resp.Transcription.Usage.PromptTokens
+		rec.CompletionTokens = resp.Transcription.Usage.CompletionTokens
+		rec.TotalTokens = resp.Transcription.Usage.TotalTokens
+		rec.AudioSeconds = resp.Transcription.Duration
+		rec.CostUSD = resp.Transcription.Cost
+		m.recordAsync(rec)
+	case resp != nil && resp.Speech != nil:
+		// Speech is billed on input characters, known before the audio
+		// finishes streaming.
+		rec.StatusCode = 200
+		rec.Characters = resp.Speech.Characters
+		rec.CostUSD = resp.Speech.Cost
+		m.recordAsync(rec)
+	case resp != nil && resp.Rerank != nil:
+		rec.StatusCode = 200
+		rec.PromptTokens = resp.Rerank.Usage.PromptTokens
+		rec.TotalTokens = resp.Rerank.Usage.TotalTokens
+		rec.CostUSD = resp.Rerank.Cost
 		m.recordAsync(rec)
 	default:
 		rec.StatusCode = 200
@@ -102,6 +163,9 @@ type usageRecordingStream struct {
 	req   *pipeline.Request
 	start time.Time
 
+	// shared marks a coalesced subscriber, recorded at zero cost.
+	shared bool
+
 	recordF  func(*usage.Record)
 	recorded bool
 	mu       sync.Mutex
@@ -154,14 +218,15 @@ func (s *usageRecordingStream) Close() error {
 				if s.rec.Provider == "" {
 					s.rec.Provider = final.Provider
 				}
-				if s.rec.CostUSD == 0 {
+				if s.rec.CostUSD == 0 && !s.shared {
 					s.rec.CostUSD = final.Cost
 				}
 			}
 		}
 	}
 
-	if u := s.inner.Usage(); u != nil {
+	u := s.inner.Usage()
+	if u != nil {
 		if s.rec.PromptTokens == 0 {
 			s.rec.PromptTokens = u.PromptTokens
 		}
@@ -173,6 +238,19 @@ func (s *usageRecordingStream) Close() error {
 		}
 	}
 
+	// Price the stream at the serving model's rates when neither the
+	// provider nor the lifecycle middleware reported a cost.
+	if s.req != nil && s.rec.CostUSD == 0 && !s.shared {
+		if pricing, ok := s.req.State[StateKeyProviderPricing].(provider.Pricing); ok {
+			priced := provider.Usage{PromptTokens: s.rec.PromptTokens, CompletionTokens: s.rec.CompletionTokens}
+			if u != nil {
+				priced.CacheReadTokens = u.CacheReadTokens
+				priced.CacheWriteTokens = u.CacheWriteTokens
+			}
+			s.rec.CostUSD = model.EstimateCost(priced, pricing).TotalCost
+		}
+	}
+
 	if s.rec.TotalTokens == 0 {
 		s.rec.TotalTokens = s.rec.PromptTokens + s.rec.CompletionTokens
 	}
@@ -102,3 +102,48 @@ func TestUsageMiddleware_RecordsForStreams(t *testing.T) {
 		t.Fatalf("status: %d", r.StatusCode)
 	}
 }
+
+func TestUsageMiddleware_PricesStreams(t *testing.T) {
+	t.Parallel()
+
+	chunks := []*provider.StreamChunk{
+		{Delta: provider.Delta{Content: "hi"}, FinishReason: "stop"},
+	}
+	stream := testutil.NewFakeStream(chunks, &provider.Usage{
+		PromptTokens: 2_000_000, CompletionTokens: 100_000, TotalTokens: 2_100_000, CacheReadTokens: 1_000_000,
+	})
+
+	rec := newRecordingUsage()
+	mw := middlewares.NewUsage(rec)
+
+	req := &pipeline.Request{
+		Completion: &provider.CompletionRequest{Model: "claude-sonnet"},
+		Type:       pipeline.RequestStream,
+		State: map[string]any{middlewares.StateKeyProviderPricing: provider.Pricing{
+			InputPerMillion: 3, OutputPerMillion: 15, CacheReadPerMillion: 0.3,
+		}},
+	}
+	resp, err := mw.Process(context.Background(), req, func(_ context.Context) (*pipeline.Response, error) {
+		return &pipeline.Response{Stream: stream}, nil
+	})
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	for {
+		if _, e := resp.Stream.Next(context.Background()); e != nil {
+			break
+		}
+	}
+	_ = resp.Stream.Close()
+
+	select {
+	case <-rec.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("usage record never written")
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if got, want := rec.records[0].CostUSD, 3+0.3+1.5; got < want-1e-9 || got > want+1e-9 {
+		t.Fatalf("cost = %v, want %v", got, want)
+	}
+}
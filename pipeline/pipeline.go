@@ -18,4 +18,16 @@ type Service interface {
 
 	// ExecuteEmbedding processes an embedding request.
 	ExecuteEmbedding(ctx context.Context, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error)
+
+	// ExecuteImage processes an image generation request.
+	ExecuteImage(ctx context.Context, req *provider.ImageRequest) (*provider.ImageResponse, error)
+
+	// ExecuteTranscription processes a transcription or translation request.
+	ExecuteTranscription(ctx context.Context, req *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error)
+
+	// ExecuteSpeech processes a text-to-speech request.
+	ExecuteSpeech(ctx context.Context, req *provider.SpeechRequest) (*provider.SpeechResponse, error)
+
+	// ExecuteRerank processes a document rerank request.
+	ExecuteRerank(ctx context.Context, req *provider.RerankRequest) (*provider.RerankResponse, error)
 }
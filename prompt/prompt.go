@@ -0,0 +1,97 @@
+// Package prompt defines the prompt template registry: named, versioned
+// templates rendered with Go text/template variables.
+package prompt
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// LabelProduction is the label resolved when a request names a template
+// without a version.
+const LabelProduction = "production"
+
+// ErrNotFound is returned when a template, version or label does not exist.
+var ErrNotFound = errors.New("nexus: prompt not found")
+
+// Template is one immutable version of a named prompt template.
+type Template struct {
+	Name        string            `json:"name"`
+	Version     int               `json:"version"`
+	TenantID    string            `json:"tenant_id,omitempty"` // empty = shared by all tenants
+	Content     string            `json:"content"`             // Go text/template source
+	Description string            `json:"description,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// Label points a name such as "production" at a version of a template.
+type Label struct {
+	Name      string    `json:"name"` // template name
+	TenantID  string    `json:"tenant_id,omitempty"`
+	Label     string    `json:"label"`
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateInput is the input for creating a template version.
+type CreateInput struct {
+	Name        string            `json:"name"`
+	TenantID    string            `json:"tenant_id,omitempty"`
+	Content     string            `json:"content"`
+	Description string            `json:"description,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+
+	// Labels are pointed at the new version once it is stored.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// Service manages prompt templates.
+type Service interface {
+	// Create stores content as the next version of the named template.
+	// Versions are never modified after creation.
+	Create(ctx context.Context, input *CreateInput) (*Template, error)
+
+	// Get resolves ref to a template version. An empty ref resolves the
+	// "production" label if set, otherwise the latest version; a number
+	// selects that version; anything else is treated as a label. Tenant
+	// templates shadow shared templates of the same name.
+	Get(ctx context.Context, tenantID, name, ref string) (*Template, error)
+
+	// Render resolves ref like Get and executes the template with vars.
+	// Referencing a variable that was not supplied is an error.
+	Render(ctx context.Context, tenantID, name, ref string, vars map[string]any) (string, *Template, error)
+
+	// SetLabel points label at an existing version.
+	SetLabel(ctx context.Context, tenantID, name, label string, version int) (*Label, error)
+
+	// Versions lists every version of a template, newest first.
+	Versions(ctx context.Context, tenantID, name string) ([]*Template, error)
+
+	// Labels lists the labels of a template.
+	Labels(ctx context.Context, tenantID, name string) ([]*Label, error)
+
+	// List returns the latest version of each of the tenant's templates.
+	List(ctx context.Context, tenantID string) ([]*Template, error)
+}
+
+// Store is the persistence interface for prompt templates.
+type Store interface {
+	// InsertVersion stores a new version; it fails if the version exists.
+	InsertVersion(ctx context.Context, t *Template) error
+
+	// FindVersion returns a version, or the latest when version is 0.
+	// It returns nil, nil when nothing matches.
+	FindVersion(ctx context.Context, tenantID, name string, version int) (*Template, error)
+
+	ListVersions(ctx context.Context, tenantID, name string) ([]*Template, error)
+	ListLatest(ctx context.Context, tenantID string) ([]*Template, error)
+
+	// SetLabel creates or moves a label.
+	SetLabel(ctx context.Context, l *Label) error
+
+	// FindLabel returns nil, nil when the label does not exist.
+	FindLabel(ctx context.Context, tenantID, name, label string) (*Label, error)
+	ListLabels(ctx context.Context, tenantID, name string) ([]*Label, error)
+}
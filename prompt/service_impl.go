@@ -0,0 +1,221 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// createAttempts bounds how often Create retries when another gateway
+// instance takes the version it computed.
+const createAttempts = 3
+
+type service struct {
+	store Store
+
+	// mu serializes Create, which computes the next version from the
+	// latest stored one.
+	mu sync.Mutex
+
+	// parsed caches compiled templates; versions are immutable so entries
+	// never go stale.
+	parsed sync.Map // "tenant/name/version" -> *template.Template
+}
+
+// NewService creates a new prompt template service.
+func NewService(store Store) Service {
+	return &service{store: store}
+}
+
+func (s *service) Create(ctx context.Context, input *CreateInput) (*Template, error) {
+	if input.Name == "" {
+		return nil, errors.New("nexus: prompt name is required")
+	}
+	if strings.TrimSpace(input.Content) == "" {
+		return nil, errors.New("nexus: prompt content is required")
+	}
+	if _, err := parse(input.Name, input.Content); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var t *Template
+	for attempt := 1; ; attempt++ {
+		latest, err := s.store.FindVersion(ctx, input.TenantID, input.Name, 0)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: find latest prompt version: %w", err)
+		}
+		version := 1
+		if latest != nil {
+			version = latest.Version + 1
+		}
+
+		t = &Template{
+			Name:        input.Name,
+			Version:     version,
+			TenantID:    input.TenantID,
+			Content:     input.Content,
+			Description: input.Description,
+			Metadata:    input.Metadata,
+			CreatedAt:   time.Now(),
+		}
+		err = s.store.InsertVersion(ctx, t)
+		if err == nil {
+			break
+		}
+		// The version exists: another instance created it first.
+		if taken, ferr := s.store.FindVersion(ctx, t.TenantID, t.Name, version); ferr == nil && taken != nil && attempt < createAttempts {
+			continue
+		}
+		return nil, fmt.Errorf("nexus: insert prompt version: %w", err)
+	}
+
+	for _, label := range input.Labels {
+		if _, err := s.SetLabel(ctx, t.TenantID, t.Name, label, t.Version); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (s *service) Get(ctx context.Context, tenantID, name, ref string) (*Template, error) {
+	scopes := []string{""}
+	if tenantID != "" {
+		scopes = []string{tenantID, ""}
+	}
+	for _, scope := range scopes {
+		latest, err := s.store.FindVersion(ctx, scope, name, 0)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: find prompt: %w", err)
+		}
+		if latest == nil {
+			continue
+		}
+		return s.resolve(ctx, latest, ref)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+// resolve applies ref within the scope latest belongs to.
+func (s *service) resolve(ctx context.Context, latest *Template, ref string) (*Template, error) {
+	label := ref
+	if ref == "" {
+		label = LabelProduction
+	} else if n, err := strconv.Atoi(ref); err == nil {
+		return s.findVersion(ctx, latest.TenantID, latest.Name, n)
+	}
+
+	l, err := s.store.FindLabel(ctx, latest.TenantID, latest.Name, label)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: find prompt label: %w", err)
+	}
+	switch {
+	case l != nil:
+		return s.findVersion(ctx, latest.TenantID, latest.Name, l.Version)
+	case ref == "":
+		return latest, nil
+	default:
+		return nil, fmt.Errorf("%w: %s@%s", ErrNotFound, latest.Name, ref)
+	}
+}
+
+func (s *service) findVersion(ctx context.Context, tenantID, name string, version int) (*Template, error) {
+	if version <= 0 {
+		return nil, fmt.Errorf("%w: %s@%d", ErrNotFound, name, version)
+	}
+	t, err := s.store.FindVersion(ctx, tenantID, name, version)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: find prompt version: %w", err)
+	}
+	if t == nil {
+		return nil, fmt.Errorf("%w: %s@%d", ErrNotFound, name, version)
+	}
+	return t, nil
+}
+
+func (s *service) Render(ctx context.Context, tenantID, name, ref string, vars map[string]any) (string, *Template, error) {
+	t, err := s.Get(ctx, tenantID, name, ref)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmpl, err := s.compiled(t)
+	if err != nil {
+		return "", nil, err
+	}
+	if vars == nil {
+		vars = map[string]any{}
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", nil, fmt.Errorf("nexus: render prompt %s@%d: %w", t.Name, t.Version, err)
+	}
+	return b.String(), t, nil
+}
+
+func (s *service) SetLabel(ctx context.Context, tenantID, name, label string, version int) (*Label, error) {
+	if label == "" {
+		return nil, errors.New("nexus: prompt label is required")
+	}
+	if _, err := strconv.Atoi(label); err == nil {
+		return nil, fmt.Errorf("nexus: prompt label %q must not be numeric", label)
+	}
+	if _, err := s.findVersion(ctx, tenantID, name, version); err != nil {
+		return nil, err
+	}
+
+	l := &Label{
+		Name:      name,
+		TenantID:  tenantID,
+		Label:     label,
+		Version:   version,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.store.SetLabel(ctx, l); err != nil {
+		return nil, fmt.Errorf("nexus: set prompt label: %w", err)
+	}
+	return l, nil
+}
+
+func (s *service) Versions(ctx context.Context, tenantID, name string) ([]*Template, error) {
+	return s.store.ListVersions(ctx, tenantID, name)
+}
+
+func (s *service) Labels(ctx context.Context, tenantID, name string) ([]*Label, error) {
+	return s.store.ListLabels(ctx, tenantID, name)
+}
+
+func (s *service) List(ctx context.Context, tenantID string) ([]*Template, error) {
+	return s.store.ListLatest(ctx, tenantID)
+}
+
+// compiled returns the parsed template for a version, parsing it once.
+func (s *service) compiled(t *Template) (*template.Template, error) {
+	cacheKey := fmt.Sprintf("%s/%s/%d", t.TenantID, t.Name, t.Version)
+	if v, ok := s.parsed.Load(cacheKey); ok {
+		if tmpl, ok := v.(*template.Template); ok {
+			return tmpl, nil
+		}
+	}
+	tmpl, err := parse(t.Name, t.Content)
+	if err != nil {
+		return nil, err
+	}
+	s.parsed.Store(cacheKey, tmpl)
+	return tmpl, nil
+}
+
+func parse(name, content string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("nexus: parse prompt %s: %w", name, err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,138 @@
+package prompt_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xraph/nexus/prompt"
+	"github.com/xraph/nexus/store"
+)
+
+func newService(t *testing.T, inputs ...*prompt.CreateInput) prompt.Service {
+	t.Helper()
+	svc := prompt.NewService(store.NewMemory().Prompts())
+	for _, in := range inputs {
+		if _, err := svc.Create(context.Background(), in); err != nil {
+			t.Fatalf("Create %s: %v", in.Name, err)
+		}
+	}
+	return svc
+}
+
+func TestService_ResolvesLabels(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	svc := newService(t,
+		&prompt.CreateInput{Name: "greet", Content: "v1", Labels: []string{prompt.LabelProduction}},
+		&prompt.CreateInput{Name: "greet", Content: "v2", Labels: []string{"canary"}},
+		&prompt.CreateInput{Name: "greet", Content: "v3"},
+		&prompt.CreateInput{Name: "plain", Content: "p1"},
+		&prompt.CreateInput{Name: "plain", Content: "p2"},
+	)
+
+	tests := []struct {
+		name, ref string
+		want      int
+	}{
+		{"greet", "", 1}, // production label, not the latest
+		{"greet", "canary", 2},
+		{"greet", "3", 3},
+		{"plain", "", 2}, // no production label: latest
+	}
+	for _, tt := range tests {
+		got, err := svc.Get(ctx, "", tt.name, tt.ref)
+		if err != nil || got.Version != tt.want {
+			t.Errorf("Get(%s@%q) = %+v, %v; want version %d", tt.name, tt.ref, got, err, tt.want)
+		}
+	}
+
+	for _, ref := range []string{"missing", "9"} {
+		if _, err := svc.Get(ctx, "", "greet", ref); !errors.Is(err, prompt.ErrNotFound) {
+			t.Errorf("Get(greet@%s) err = %v, want ErrNotFound", ref, err)
+		}
+	}
+	if _, err := svc.SetLabel(ctx, "", "greet", "7", 1); err == nil {
+		t.Error("numeric label accepted")
+	}
+
+	// Moving a label changes what it resolves to.
+	if _, err := svc.SetLabel(ctx, "", "greet", prompt.LabelProduction, 3); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := svc.Get(ctx, "", "greet", ""); err != nil || got.Version != 3 {
+		t.Errorf("after relabel = %+v, %v; want version 3", got, err)
+	}
+}
+
+func TestService_TenantShadowsShared(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	svc := newService(t,
+		&prompt.CreateInput{Name: "support", Content: "shared v1"},
+		&prompt.CreateInput{Name: "support", Content: "shared v2", Labels: []string{"canary"}},
+		&prompt.CreateInput{Name: "support", TenantID: "acme", Content: "acme v1"},
+	)
+
+	got, err := svc.Get(ctx, "acme", "support", "")
+	if err != nil || got.TenantID != "acme" || got.Content != "acme v1" {
+		t.Fatalf("acme = %+v, %v; want its own template", got, err)
+	}
+	// Refs resolve within the shadowing template only.
+	if _, err := svc.Get(ctx, "acme", "support", "canary"); !errors.Is(err, prompt.ErrNotFound) {
+		t.Errorf("acme canary err = %v, want ErrNotFound", err)
+	}
+	if got, err := svc.Get(ctx, "globex", "support", ""); err != nil || got.Content != "shared v2" {
+		t.Errorf("globex = %+v, %v; want the shared latest", got, err)
+	}
+	text, _, err := svc.Render(ctx, "acme", "support", "1", nil)
+	if err != nil || text != "acme v1" {
+		t.Errorf("render = %q, %v", text, err)
+	}
+}
+
+// slowStore widens the window between reading the latest version and
+// inserting the next one.
+type slowStore struct{ prompt.Store }
+
+func (s slowStore) FindVersion(ctx context.Context, tenantID, name string, version int) (*prompt.Template, error) {
+	t, err := s.Store.FindVersion(ctx, tenantID, name, version)
+	time.Sleep(time.Millisecond)
+	return t, err
+}
+
+func TestService_ConcurrentCreatesGetDistinctVersions(t *testing.T) {
+	t.Parallel()
+	svc := prompt.NewService(slowStore{store.NewMemory().Prompts()})
+
+	const n = 20
+	versions := make(chan int, n)
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tmpl, err := svc.Create(context.Background(), &prompt.CreateInput{Name: "p", Content: "x"})
+			if err != nil {
+				t.Errorf("Create: %v", err)
+				return
+			}
+			versions <- tmpl.Version
+		}()
+	}
+	wg.Wait()
+	close(versions)
+
+	seen := make(map[int]bool)
+	for v := range versions {
+		if seen[v] {
+			t.Errorf("version %d created twice", v)
+		}
+		seen[v] = true
+	}
+	if len(seen) != n {
+		t.Errorf("created %d versions, want %d", len(seen), n)
+	}
+}
@@ -13,7 +13,8 @@ import (
 //
 // Merge rules:
 //   - Content: concatenated into Choices[0].Message.Content.
-//   - Reasoning: concatenated into ThinkingContent.
+//   - Reasoning: concatenated into ThinkingContent; each signed span also
+//     becomes a thinking block on Choices[0].Message.
 //   - ToolCalls: merged by ID first, falling back to slot index. function.name
 //     keeps the first non-empty value; function.arguments are concatenated.
 //   - Citations: appended.
@@ -101,6 +102,10 @@ type streamAccumulator struct {
 	reasoningSB strings.Builder
 	transcript  string
 
+	// Reasoning since the last signature, and the signed blocks so far.
+	blockSB  strings.Builder
+	thinking []ThinkingBlock
+
 	role         string
 	finishReason string
 
@@ -161,6 +166,11 @@ func (a *streamAccumulator) add(c *StreamChunk) {
 	}
 	if d.Reasoning != "" {
 		a.reasoningSB.WriteString(d.Reasoning)
+		a.blockSB.WriteString(d.Reasoning)
+	}
+	if d.ReasoningSignature != "" {
+		a.thinking = append(a.thinking, ThinkingBlock{Thinking: a.blockSB.String(), Signature: d.ReasoningSignature})
+		a.blockSB.Reset()
 	}
 	if d.Transcript != "" {
 		a.transcript = d.Transcript
@@ -265,6 +275,7 @@ func (a *streamAccumulator) finalize(s Stream) *CompletionResponse {
 					Role:      role,
 					Content:   a.contentSB.String(),
 					ToolCalls: tools,
+					Thinking:  a.thinking,
 				},
 				FinishReason: a.finishReason,
 			},
@@ -283,6 +294,8 @@ func (a *streamAccumulator) finalize(s Stream) *CompletionResponse {
 		resp.ThinkingTokens = resp.Usage.ThinkingTokens
 	}
 
+	resp.Citations = a.citations
+
 	if len(a.citations) > 0 || len(a.audioBuf) > 0 || a.finalImg != nil || a.transcript != "" {
 		resp.State = make(map[string]any, 4)
 		if len(a.citations) > 0 {
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"unicode/utf8"
+)
+
+// TranscriptionProvider is implemented by providers that convert speech to
+// text. It is optional: the gateway routes transcription and translation
+// requests only to providers that implement it and advertise
+// Capabilities.Audio.
+type TranscriptionProvider interface {
+	// Transcribe converts an audio file to text. When req.Translate is set
+	// the text is translated to English.
+	Transcribe(ctx context.Context, req *TranscriptionRequest) (*TranscriptionResponse, error)
+}
+
+// SpeechProvider is implemented by providers that synthesize speech. Like
+// TranscriptionProvider it is optional.
+type SpeechProvider interface {
+	// Speech synthesizes req.Input. The caller must close the returned
+	// response's Audio.
+	Speech(ctx context.Context, req *SpeechRequest) (*SpeechResponse, error)
+}
+
+// TranscriptionRequest is the unified speech-to-text request, modelled on
+// OpenAI's /v1/audio/transcriptions and /v1/audio/translations.
+type TranscriptionRequest struct {
+	Model    string `json:"model"`
+	Provider string `json:"provider,omitempty"` // force specific provider
+
+	File     []byte `json:"-"`        // raw audio
+	Filename string `json:"filename"` // original name; providers infer the format from it
+
+	Language    string   `json:"language,omitempty"` // ISO-639-1 hint (transcription only)
+	Prompt      string   `json:"prompt,omitempty"`   // vocabulary or style hint
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// Translate selects /translations: the output is English regardless
+	// of the spoken language.
+	Translate bool `json:"translate,omitempty"`
+
+	// Nexus metadata (not sent to provider)
+	TenantID string `json:"-"`
+	KeyID    string `json:"-"`
+}
+
+// TranscriptionResponse is the unified speech-to-text response. Providers
+// request verbose output so Duration is known for per-second billing.
+type TranscriptionResponse struct {
+	Provider string                 `json:"provider"`
+	Model    string                 `json:"model"`
+	Text     string                 `json:"text"`
+	Language string                 `json:"language,omitempty"`
+	Duration float64                `json:"duration,omitempty"` // seconds of audio
+	Segments []TranscriptionSegment `json:"segments,omitempty"`
+	Usage    Usage                  `json:"usage,omitempty"` // token-billed models
+	Cost     float64                `json:"cost,omitempty"`  // USD
+}
+
+// TranscriptionSegment is a timed span of a transcript.
+type TranscriptionSegment struct {
+	ID    int     `json:"id"`
+	Start float64 `json:"start"` // seconds
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// SpeechRequest is the unified text-to-speech request, modelled on OpenAI's
+// /v1/audio/speech.
+type SpeechRequest struct {
+	Model    string `json:"model"`
+	Provider string `json:"provider,omitempty"` // force specific provider
+
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	Instructions   string  `json:"instructions,omitempty"`    // tone and style (gpt-4o-mini-tts)
+	ResponseFormat string  `json:"response_format,omitempty"` // "mp3" (default), "opus", "aac", "flac", "wav", "pcm"
+	Speed          float64 `json:"speed,omitempty"`
+
+	// StreamFormat "sse" asks for audio delivered as stream events instead
+	// of a raw audio body. It is handled by the gateway, not providers.
+	StreamFormat string `json:"stream_format,omitempty"`
+
+	// Nexus metadata (not sent to provider)
+	TenantID string `json:"-"`
+	KeyID    string `json:"-"`
+}
+
+// Characters returns the billable length of the input.
+func (r *SpeechRequest) Characters() int {
+	return utf8.RuneCountInString(r.Input)
+}
+
+// Format returns the requested audio format, defaulting to mp3.
+func (r *SpeechRequest) Format() string {
+	if r.ResponseFormat == "" {
+		return "mp3"
+	}
+	return r.ResponseFormat
+}
+
+// SpeechResponse carries synthesized audio as it arrives from the
+// provider.
+type SpeechResponse struct {
+	Provider    string        `json:"provider"`
+	Model       string        `json:"model"`
+	ContentType string        `json:"content_type"`
+	Format      string        `json:"format"`
+	Audio       io.ReadCloser `json:"-"`
+	Characters  int           `json:"characters"`
+	Cost        float64       `json:"cost,omitempty"` // USD
+}
+
+// AudioContentType returns the MIME type for an audio format name.
+func AudioContentType(format string) string {
+	switch format {
+	case "opus":
+		return "audio/ogg"
+	case "aac":
+		return "audio/aac"
+	case "flac":
+		return "audio/flac"
+	case "wav":
+		return "audio/wav"
+	case "pcm":
+		return "audio/pcm"
+	default:
+		return "audio/mpeg"
+	}
+}
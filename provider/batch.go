@@ -0,0 +1,63 @@
+package provider
+
+import "context"
+
+// BatchProvider is implemented by providers with an asynchronous batch API
+// (OpenAI Batch, Anthropic Message Batches). It is optional: batches for
+// models no native provider serves run on the gateway's worker pool.
+type BatchProvider interface {
+	// SubmitBatch uploads the requests and starts a provider batch,
+	// returning the provider's batch ID.
+	SubmitBatch(ctx context.Context, reqs []BatchRequest) (string, error)
+
+	// BatchStatus reports the progress of a provider batch.
+	BatchStatus(ctx context.Context, batchID string) (*BatchStatus, error)
+
+	// BatchResults downloads the results of an ended batch.
+	BatchResults(ctx context.Context, batchID string) ([]BatchResult, error)
+
+	// CancelBatch asks the provider to stop processing a batch.
+	CancelBatch(ctx context.Context, batchID string) error
+
+	// BatchDiscount is the fraction taken off the standard price for
+	// batched requests, e.g. 0.5 for half price.
+	BatchDiscount() float64
+}
+
+// Provider batch states reported by BatchStatus.
+const (
+	BatchStateInProgress = "in_progress"
+	BatchStateEnded      = "ended"     // results are ready
+	BatchStateFailed     = "failed"    // the batch as a whole was rejected
+	BatchStateCancelled  = "cancelled" // partial results may be ready
+	BatchStateExpired    = "expired"   // partial results may be ready
+)
+
+// BatchRequest is one line of a batch.
+type BatchRequest struct {
+	CustomID string             `json:"custom_id"`
+	Request  *CompletionRequest `json:"request"`
+}
+
+// BatchStatus is the progress of a provider batch.
+type BatchStatus struct {
+	State     string `json:"state"`
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+	Failed    int    `json:"failed"`
+}
+
+// Ended reports whether the batch has stopped processing and its results
+// can be downloaded.
+func (s *BatchStatus) Ended() bool {
+	return s.State != BatchStateInProgress
+}
+
+// BatchResult is the outcome of one line of a batch: either Response or
+// Error is set.
+type BatchResult struct {
+	CustomID   string              `json:"custom_id"`
+	StatusCode int                 `json:"status_code"`
+	Response   *CompletionResponse `json:"response,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
@@ -2,16 +2,18 @@ package provider
 
 // Capabilities describes what a provider can do.
 type Capabilities struct {
-	Chat       bool `json:"chat"`       // Chat completions
-	Streaming  bool `json:"streaming"`  // SSE streaming
-	Embeddings bool `json:"embeddings"` // Text embeddings
-	Images     bool `json:"images"`     // Image generation
-	Vision     bool `json:"vision"`     // Image input in messages
-	Tools      bool `json:"tools"`      // Function/tool calling
-	JSON       bool `json:"json"`       // Structured JSON output
-	Audio      bool `json:"audio"`      // Audio input/output
-	Thinking   bool `json:"thinking"`   // Extended thinking / reasoning
-	Batch      bool `json:"batch"`      // Batch API support
+	Chat       bool `json:"chat"`                  // Chat completions
+	Streaming  bool `json:"streaming"`             // SSE streaming
+	Embeddings bool `json:"embeddings"`            // Text embeddings
+	Images     bool `json:"images"`                // Image generation
+	Vision     bool `json:"vision"`                // Image input in messages
+	Tools      bool `json:"tools"`                 // Function/tool calling
+	JSON       bool `json:"json"`                  // Structured JSON output
+	JSONSchema bool `json:"json_schema,omitempty"` // Enforces response_format json_schema natively
+	Audio      bool `json:"audio"`                 // Audio input/output
+	Thinking   bool `json:"thinking"`              // Extended thinking / reasoning
+	Batch      bool `json:"batch"`                 // Batch API support
+	Rerank     bool `json:"rerank,omitempty"`      // Document reranking
 
 	// Streaming-specific capabilities — describe what the provider can
 	// surface incrementally during a stream. Use these for feature
@@ -33,7 +35,7 @@ func (c Capabilities) Supports(capability string) bool {
 		return c.Chat
 	case "streaming":
 		return c.Streaming
-	case "embeddings":
+	case "embeddings", "embed":
 		return c.Embeddings
 	case "images":
 		return c.Images
@@ -43,12 +45,16 @@ func (c Capabilities) Supports(capability string) bool {
 		return c.Tools
 	case "json":
 		return c.JSON
+	case "json_schema":
+		return c.JSONSchema
 	case "audio":
 		return c.Audio
 	case "thinking":
 		return c.Thinking
 	case "batch":
 		return c.Batch
+	case "rerank":
+		return c.Rerank
 	case "streaming_reasoning":
 		return c.StreamingReasoning
 	case "streaming_tools":
@@ -3,8 +3,13 @@ package provider
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 )
 
 // CredentialProvider retrieves API keys or tokens for providers at runtime.
@@ -15,6 +20,14 @@ type CredentialProvider interface {
 	GetCredential(ctx context.Context, providerName string) (string, error)
 }
 
+// CredentialFunc adapts a function to CredentialProvider.
+type CredentialFunc func(ctx context.Context, providerName string) (string, error)
+
+// GetCredential calls f.
+func (f CredentialFunc) GetCredential(ctx context.Context, providerName string) (string, error) {
+	return f(ctx, providerName)
+}
+
 // StaticCredential always returns the same key. Useful for testing.
 type StaticCredential struct {
 	credentials map[string]string
@@ -41,15 +54,317 @@ type EnvCredential struct {
 }
 
 // NewEnvCredential creates a credential provider that reads from env vars.
-// The environment variable name is formed as: {prefix}{PROVIDER_NAME}_API_KEY.
+// The environment variable name is formed as: {prefix}{PROVIDER_NAME}_API_KEY,
+// with hyphens in the provider name replaced by underscores.
 func NewEnvCredential(prefix string) *EnvCredential {
 	return &EnvCredential{envPrefix: prefix}
 }
 
 func (ec *EnvCredential) GetCredential(_ context.Context, providerName string) (string, error) {
-	key := os.Getenv(ec.envPrefix + strings.ToUpper(providerName) + "_API_KEY")
+	name := strings.ReplaceAll(strings.ToUpper(providerName), "-", "_")
+	key := os.Getenv(ec.envPrefix + name + "_API_KEY")
 	if key == "" {
 		return "", errCredentialNotFound
 	}
 	return key, nil
 }
+
+// CredentialFeedback is implemented by credential providers that track key
+// health. CredentialTransport reports keys the upstream rejected so the
+// source can rotate away from them.
+type CredentialFeedback interface {
+	// ReportFailure records that key was rejected with an HTTP status:
+	// 401 or 403 (revoked or invalid) or 429 (rate limited).
+	ReportFailure(providerName, key string, status int)
+}
+
+// CachedCredential caches another provider's credentials for a short TTL,
+// so slow sources (secret managers) are not consulted on every request.
+// Failure reports are forwarded and evict the cached entry.
+type CachedCredential struct {
+	src CredentialProvider
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedKey
+}
+
+type cachedKey struct {
+	key     string
+	fetched time.Time
+}
+
+// NewCachedCredential wraps src with a cache of the given TTL.
+func NewCachedCredential(src CredentialProvider, ttl time.Duration) *CachedCredential {
+	return &CachedCredential{src: src, ttl: ttl, entries: map[string]cachedKey{}}
+}
+
+func (cc *CachedCredential) GetCredential(ctx context.Context, providerName string) (string, error) {
+	cc.mu.Lock()
+	e, ok := cc.entries[providerName]
+	cc.mu.Unlock()
+	if ok && time.Since(e.fetched) < cc.ttl {
+		return e.key, nil
+	}
+
+	key, err := cc.src.GetCredential(ctx, providerName)
+	if err != nil {
+		return "", err
+	}
+	cc.mu.Lock()
+	cc.entries[providerName] = cachedKey{key: key, fetched: time.Now()}
+	cc.mu.Unlock()
+	return key, nil
+}
+
+// ReportFailure implements CredentialFeedback.
+func (cc *CachedCredential) ReportFailure(providerName, key string, status int) {
+	cc.mu.Lock()
+	delete(cc.entries, providerName)
+	cc.mu.Unlock()
+	if fb, ok := cc.src.(CredentialFeedback); ok {
+		fb.ReportFailure(providerName, key, status)
+	}
+}
+
+// FileCredential reads each provider's credential from a file named after
+// the provider in a directory — the layout of a Kubernetes secret mounted
+// as a volume. Files are re-read when their modification time changes, so
+// rotating the secret takes effect without a restart. A file may hold
+// several keys, one per line, for use with KeyPool.
+type FileCredential struct {
+	dir string
+
+	// PollInterval is how often a provider's file is stat'ed for changes.
+	// Default 1s.
+	PollInterval time.Duration
+
+	mu    sync.Mutex
+	files map[string]*watchedFile
+}
+
+type watchedFile struct {
+	value   string
+	modTime time.Time
+	checked time.Time
+}
+
+// NewFileCredential creates a credential provider reading from dir.
+func NewFileCredential(dir string) *FileCredential {
+	return &FileCredential{dir: dir, PollInterval: time.Second, files: map[string]*watchedFile{}}
+}
+
+func (fc *FileCredential) GetCredential(_ context.Context, providerName string) (string, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	f := fc.files[providerName]
+	if f != nil && time.Since(f.checked) < fc.PollInterval {
+		return f.value, nil
+	}
+
+	path := filepath.Join(fc.dir, providerName)
+	info, err := os.Stat(path)
+	if err != nil {
+		if f != nil {
+			// Keep serving the last key while the secret is being swapped.
+			return f.value, nil
+		}
+		return "", errCredentialNotFound
+	}
+	if f == nil || !info.ModTime().Equal(f.modTime) {
+		data, readErr := os.ReadFile(path) //nolint:gosec // G304 -- path is inside the configured secret directory
+		if readErr != nil {
+			return "", readErr
+		}
+		value := strings.TrimSpace(string(data))
+		if value == "" {
+			return "", errCredentialNotFound
+		}
+		f = &watchedFile{value: value, modTime: info.ModTime()}
+		fc.files[providerName] = f
+	}
+	f.checked = time.Now()
+	return f.value, nil
+}
+
+// KeyPool spreads requests across several keys per provider. The source
+// returns the keys as one value separated by newlines or commas (an env
+// var, a mounted secret file). Keys are used round-robin; a key rejected
+// with 429 is rested for RateLimitCooldown and one rejected with 401/403
+// for InvalidCooldown. New keys from a rotated source are picked up on the
+// next request.
+type KeyPool struct {
+	src CredentialProvider
+
+	// RateLimitCooldown is how long a 429'd key is skipped. Default 30s.
+	RateLimitCooldown time.Duration
+	// InvalidCooldown is how long a 401/403'd key is skipped. Default 10m.
+	InvalidCooldown time.Duration
+
+	mu       sync.Mutex
+	next     map[string]int
+	cooldown map[string]time.Time // provider + "\x00" + key → usable again at
+}
+
+// NewKeyPool creates a key pool over src.
+func NewKeyPool(src CredentialProvider) *KeyPool {
+	return &KeyPool{
+		src:               src,
+		RateLimitCooldown: 30 * time.Second,
+		InvalidCooldown:   10 * time.Minute,
+		next:              map[string]int{},
+		cooldown:          map[string]time.Time{},
+	}
+}
+
+func (kp *KeyPool) GetCredential(ctx context.Context, providerName string) (string, error) {
+	raw, err := kp.src.GetCredential(ctx, providerName)
+	if err != nil {
+		return "", err
+	}
+	keys := splitKeys(raw)
+	if len(keys) == 0 {
+		return "", errCredentialNotFound
+	}
+
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	now := time.Now()
+	start := kp.next[providerName]
+	best, bestUntil := "", time.Time{}
+	for i := range keys {
+		key := keys[(start+i)%len(keys)]
+		until := kp.cooldown[providerName+"\x00"+key]
+		if !until.After(now) {
+			kp.next[providerName] = (start + i + 1) % len(keys)
+			return key, nil
+		}
+		if best == "" || until.Before(bestUntil) {
+			best, bestUntil = key, until
+		}
+	}
+	// Every key is resting; use the one that recovers first.
+	return best, nil
+}
+
+// ReportFailure implements CredentialFeedback.
+func (kp *KeyPool) ReportFailure(providerName, key string, status int) {
+	d := kp.InvalidCooldown
+	if status == http.StatusTooManyRequests {
+		d = kp.RateLimitCooldown
+	}
+	kp.mu.Lock()
+	kp.cooldown[providerName+"\x00"+key] = time.Now().Add(d)
+	kp.mu.Unlock()
+	if fb, ok := kp.src.(CredentialFeedback); ok {
+		fb.ReportFailure(providerName, key, status)
+	}
+}
+
+func splitKeys(raw string) []string {
+	fields := strings.FieldsFunc(raw, func(r rune) bool { return r == '\n' || r == ',' || r == '\r' })
+	keys := fields[:0]
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			keys = append(keys, f)
+		}
+	}
+	return keys
+}
+
+// CredentialApplier sets a credential on an outgoing request, as a header
+// or query parameter.
+type CredentialApplier func(req *http.Request, credential string) error
+
+// BearerAuth sets "Authorization: Bearer <credential>".
+func BearerAuth(req *http.Request, credential string) error {
+	req.Header.Set("Authorization", "Bearer "+credential)
+	return nil
+}
+
+// HeaderAuth sets the named header to the credential.
+func HeaderAuth(name string) CredentialApplier {
+	return func(req *http.Request, credential string) error {
+		req.Header.Set(name, credential)
+		return nil
+	}
+}
+
+// maxCredentialAttempts bounds how many keys one request tries.
+const maxCredentialAttempts = 3
+
+// CredentialTransport is an http.RoundTripper that authenticates every
+// request with the provider's current credential, resolved per request so
+// rotated or pooled keys take effect immediately. When the upstream
+// answers 401, 403 or 429 and the source implements CredentialFeedback, the
+// key is reported and the request is retried with the next key, up to
+// three keys in all. Request bodies must be replayable (GetBody set, as
+// http.NewRequest does for in-memory bodies) for a retry to happen.
+type CredentialTransport struct {
+	base     http.RoundTripper
+	creds    CredentialProvider
+	provider string
+	apply    CredentialApplier
+}
+
+// NewCredentialTransport wraps base (http.DefaultTransport if nil) so that
+// requests carry providerName's credential from creds, set by apply.
+func NewCredentialTransport(base http.RoundTripper, providerName string, creds CredentialProvider, apply CredentialApplier) *CredentialTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &CredentialTransport{base: base, creds: creds, provider: providerName, apply: apply}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CredentialTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	feedback, _ := t.creds.(CredentialFeedback)
+	tried := map[string]bool{}
+
+	var resp *http.Response
+	for attempt := 0; attempt < maxCredentialAttempts; attempt++ {
+		key, err := t.creds.GetCredential(req.Context(), t.provider)
+		if err != nil {
+			return nil, fmt.Errorf("nexus: credential for %s: %w", t.provider, err)
+		}
+		if tried[key] {
+			break // no other key to fail over to
+		}
+		tried[key] = true
+
+		out := req.Clone(req.Context())
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				break
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				break
+			}
+			out.Body = body
+		}
+		if err := t.apply(out, key); err != nil {
+			return nil, fmt.Errorf("nexus: apply credential for %s: %w", t.provider, err)
+		}
+
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		resp, err = t.base.RoundTrip(out)
+		if err != nil {
+			return nil, err
+		}
+		switch resp.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+			if feedback == nil {
+				return resp, nil
+			}
+			feedback.ReportFailure(t.provider, key, resp.StatusCode)
+			continue
+		}
+		return resp, nil
+	}
+	return resp, nil
+}
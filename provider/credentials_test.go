@@ -0,0 +1,187 @@
+package provider_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+func TestEnvCredential_HyphenatedName(t *testing.T) {
+	t.Setenv("NEXUS_GEMINI_LIVE_API_KEY", "k")
+	got, err := provider.NewEnvCredential("NEXUS_").GetCredential(context.Background(), "gemini-live")
+	if err != nil || got != "k" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+}
+
+func TestCachedCredential(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	src := provider.CredentialFunc(func(context.Context, string) (string, error) {
+		calls++
+		return "key", nil
+	})
+	cc := provider.NewCachedCredential(src, time.Minute)
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := cc.GetCredential(ctx, "openai"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("source calls = %d, want 1", calls)
+	}
+
+	cc.ReportFailure("openai", "key", http.StatusUnauthorized)
+	if _, err := cc.GetCredential(ctx, "openai"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("source calls after failure = %d, want 2", calls)
+	}
+}
+
+func TestFileCredential_Rotation(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "openai")
+	if err := os.WriteFile(path, []byte("sk-old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fc := provider.NewFileCredential(dir)
+	fc.PollInterval = 0
+	ctx := context.Background()
+
+	got, err := fc.GetCredential(ctx, "openai")
+	if err != nil || got != "sk-old" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("sk-new\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = fc.GetCredential(ctx, "openai"); got != "sk-new" {
+		t.Fatalf("after rotation got %q, want sk-new", got)
+	}
+
+	if _, err := fc.GetCredential(ctx, "anthropic"); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
+func TestKeyPool_RoundRobinAndCooldown(t *testing.T) {
+	t.Parallel()
+	kp := provider.NewKeyPool(provider.NewStaticCredential(map[string]string{"openai": "a, b\nc"}))
+	ctx := context.Background()
+
+	var got []string
+	for range 4 {
+		k, err := kp.GetCredential(ctx, "openai")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, k)
+	}
+	if strings.Join(got, "") != "abca" {
+		t.Fatalf("rotation = %v, want a b c a", got)
+	}
+
+	kp.ReportFailure("openai", "b", http.StatusTooManyRequests)
+	kp.ReportFailure("openai", "c", http.StatusUnauthorized)
+	for range 3 {
+		if k, _ := kp.GetCredential(ctx, "openai"); k != "a" {
+			t.Fatalf("got %q, want only a while b and c rest", k)
+		}
+	}
+
+	// With every key resting, the one recovering first is used.
+	kp.ReportFailure("openai", "a", http.StatusUnauthorized)
+	if k, _ := kp.GetCredential(ctx, "openai"); k != "b" {
+		t.Fatalf("got %q, want b (shortest cooldown)", k)
+	}
+}
+
+func TestCredentialTransport_Failover(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		seen = append(seen, r.Header.Get("Authorization")+"|"+string(body))
+		mu.Unlock()
+		switch r.Header.Get("Authorization") {
+		case "Bearer revoked":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "Bearer limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = io.WriteString(w, "ok")
+		}
+	}))
+	defer srv.Close()
+
+	kp := provider.NewKeyPool(provider.NewStaticCredential(map[string]string{"openai": "revoked\nlimited\ngood"}))
+	client := &http.Client{Transport: provider.NewCredentialTransport(nil, "openai", kp, provider.BearerAuth)}
+
+	resp, err := client.Post(srv.URL, "application/json", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	want := []string{"Bearer revoked|payload", "Bearer limited|payload", "Bearer good|payload"}
+	if strings.Join(seen, ",") != strings.Join(want, ",") {
+		t.Fatalf("attempts = %v, want %v", seen, want)
+	}
+
+	// Failed keys rest, so the next request goes straight to the good key.
+	seen = nil
+	resp2, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp2.Body.Close()
+	if len(seen) != 1 || !strings.HasPrefix(seen[0], "Bearer good") {
+		t.Fatalf("second request attempts = %v", seen)
+	}
+}
+
+func TestCredentialTransport_NoFeedbackPassesThrough(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	cp := provider.NewStaticCredential(map[string]string{"cohere": "k"})
+	client := &http.Client{Transport: provider.NewCredentialTransport(nil, "cohere", cp, provider.HeaderAuth("x-api-key"))}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || calls != 1 {
+		t.Fatalf("status = %d calls = %d, want 401 after one call", resp.StatusCode, calls)
+	}
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// ImageProvider is implemented by providers that generate images. It is
+// optional: the gateway routes image requests only to providers that
+// implement it and advertise Capabilities.Images.
+type ImageProvider interface {
+	// GenerateImage creates images from a text prompt.
+	GenerateImage(ctx context.Context, req *ImageRequest) (*ImageResponse, error)
+}
+
+// ImageRequest is the unified image generation request, modelled on
+// OpenAI's /v1/images/generations.
+type ImageRequest struct {
+	Model    string `json:"model"`
+	Provider string `json:"provider,omitempty"` // force specific provider
+
+	Prompt         string `json:"prompt"`
+	NegativePrompt string `json:"negative_prompt,omitempty"` // Imagen, Titan, SDXL
+
+	N              int    `json:"n,omitempty"`               // number of images (default 1)
+	Size           string `json:"size,omitempty"`            // "WIDTHxHEIGHT", e.g. "1024x1024"
+	Quality        string `json:"quality,omitempty"`         // "standard", "hd", "low", "medium", "high"
+	Style          string `json:"style,omitempty"`           // "vivid", "natural" (DALL·E 3)
+	ResponseFormat string `json:"response_format,omitempty"` // "url" or "b64_json"
+	Seed           *int64 `json:"seed,omitempty"`
+	User           string `json:"user,omitempty"`
+
+	// Nexus metadata (not sent to provider)
+	TenantID string `json:"-"`
+	KeyID    string `json:"-"`
+}
+
+// Count returns the number of images requested, at least 1.
+func (r *ImageRequest) Count() int {
+	if r.N < 1 {
+		return 1
+	}
+	return r.N
+}
+
+// Dimensions parses Size into width and height, defaulting to 1024x1024
+// when it is empty or malformed.
+func (r *ImageRequest) Dimensions() (width, height int) {
+	ws, hs, ok := strings.Cut(r.Size, "x")
+	if ok {
+		w, errW := strconv.Atoi(ws)
+		h, errH := strconv.Atoi(hs)
+		if errW == nil && errH == nil && w > 0 && h > 0 {
+			return w, h
+		}
+	}
+	return 1024, 1024
+}
+
+// ImageResponse is the unified image generation response. It marshals to
+// the OpenAI images response shape plus Nexus fields.
+type ImageResponse struct {
+	Created  int64   `json:"created"`
+	Provider string  `json:"provider"`
+	Model    string  `json:"model"`
+	Data     []Image `json:"data"`
+	Usage    Usage   `json:"usage,omitempty"` // token-billed models (gpt-image-1)
+	Cost     float64 `json:"cost,omitempty"`  // USD
+}
+
+// Image is one generated image, returned either by URL or inline.
+type Image struct {
+	URL           string `json:"url,omitempty"`
+	B64JSON       string `json:"b64_json,omitempty"`
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+}
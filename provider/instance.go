@@ -0,0 +1,75 @@
+package provider
+
+// Instance identifies one deployment of a provider type. Providers embed it
+// so several deployments of the same type — two OpenAI organizations,
+// regional Azure OpenAI resources, several Ollama hosts — can be registered
+// side by side under distinct names, each with its own model list.
+//
+// The registry, router, health tracker, circuit breakers and usage records
+// all key on Name(), so each instance is tracked separately. TypeOf reports
+// the shared provider type.
+type Instance struct {
+	// Name overrides the provider's type name. Empty keeps the type name.
+	Name string
+
+	// Models limits the instance to these model IDs, in this order. IDs
+	// missing from the provider's catalog (custom deployments, local
+	// models) are listed with an empty price. Empty keeps the full catalog.
+	Models []string
+}
+
+// NameOr returns the instance name, or typ when none is set.
+func (i Instance) NameOr(typ string) string {
+	if i.Name != "" {
+		return i.Name
+	}
+	return typ
+}
+
+// FilterModels applies the instance's model list to catalog and stamps
+// each model with the instance name.
+func (i Instance) FilterModels(name string, catalog []Model) []Model {
+	if i.Name == "" && len(i.Models) == 0 {
+		return catalog
+	}
+
+	if len(i.Models) == 0 {
+		out := make([]Model, len(catalog))
+		for k, m := range catalog {
+			m.Provider = name
+			out[k] = m
+		}
+		return out
+	}
+
+	byID := make(map[string]Model, len(catalog))
+	for _, m := range catalog {
+		byID[m.ID] = m
+	}
+	out := make([]Model, 0, len(i.Models))
+	for _, id := range i.Models {
+		m, ok := byID[id]
+		if !ok {
+			m = Model{ID: id, Name: id}
+		}
+		m.Provider = name
+		out = append(out, m)
+	}
+	return out
+}
+
+// Typed is implemented by providers that can be registered under an
+// instance name, to report the provider type ("openai", "azureopenai")
+// independently of Name().
+type Typed interface {
+	Type() string
+}
+
+// TypeOf returns p's provider type: Type() when p implements Typed,
+// otherwise Name().
+func TypeOf(p Provider) string {
+	if t, ok := p.(Typed); ok {
+		return t.Type()
+	}
+	return p.Name()
+}
@@ -0,0 +1,44 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/xraph/nexus/provider"
+)
+
+func TestInstance_FilterModels(t *testing.T) {
+	catalog := []provider.Model{
+		{ID: "gpt-4o", Provider: "openai", Pricing: provider.Pricing{InputPerMillion: 2.5}},
+		{ID: "gpt-4o-mini", Provider: "openai"},
+	}
+
+	if got := (provider.Instance{}).FilterModels("openai", catalog); len(got) != 2 || got[0].Provider != "openai" {
+		t.Fatalf("unnamed instance changed catalog: %+v", got)
+	}
+
+	named := provider.Instance{Name: "openai-eu"}.FilterModels("openai-eu", catalog)
+	if len(named) != 2 || named[0].Provider != "openai-eu" {
+		t.Fatalf("named = %+v", named)
+	}
+	if catalog[0].Provider != "openai" {
+		t.Fatal("catalog was mutated")
+	}
+
+	limited := provider.Instance{Name: "az", Models: []string{"my-deployment", "gpt-4o"}}.FilterModels("az", catalog)
+	if len(limited) != 2 {
+		t.Fatalf("limited = %+v", limited)
+	}
+	if limited[0].ID != "my-deployment" || limited[0].Provider != "az" {
+		t.Errorf("custom model = %+v", limited[0])
+	}
+	if limited[1].ID != "gpt-4o" || limited[1].Pricing.InputPerMillion != 2.5 {
+		t.Errorf("catalog model lost its pricing: %+v", limited[1])
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	p := newMock("plain", provider.Capabilities{}, true)
+	if got := provider.TypeOf(p); got != "plain" {
+		t.Errorf("TypeOf(untyped) = %q, want its name", got)
+	}
+}
@@ -16,4 +16,33 @@ type Pricing struct {
 	InputPerMillion     float64 `json:"input_per_million"`
 	OutputPerMillion    float64 `json:"output_per_million"`
 	EmbeddingPerMillion float64 `json:"embedding_per_million,omitempty"`
+
+	// PerImage is the price of one generated image at the model's default
+	// size and quality.
+	PerImage float64 `json:"per_image,omitempty"`
+
+	// Audio rates: transcription per second of input audio, speech per
+	// million input characters.
+	AudioPerSecond       float64 `json:"audio_per_second,omitempty"`
+	CharactersPerMillion float64 `json:"characters_per_million,omitempty"`
+
+	// PerSearch is the price of one rerank search unit, for rerankers
+	// billed per search rather than per token.
+	PerSearch float64 `json:"per_search,omitempty"`
+
+	// Prompt-cache rates. Zero means the provider does not discount cached
+	// tokens, so they are charged at InputPerMillion.
+	CacheReadPerMillion  float64 `json:"cache_read_per_million,omitempty"`
+	CacheWritePerMillion float64 `json:"cache_write_per_million,omitempty"`
+}
+
+// RerankCost prices a rerank call: search units at PerSearch plus tokens
+// at InputPerMillion.
+func (p Pricing) RerankCost(searchUnits, tokens int) float64 {
+	return float64(searchUnits)*p.PerSearch + float64(tokens)/1_000_000*p.InputPerMillion
+}
+
+// AudioCost prices transcribed seconds and synthesized characters.
+func (p Pricing) AudioCost(seconds float64, characters int) float64 {
+	return seconds*p.AudioPerSecond + float64(characters)/1_000_000*p.CharactersPerMillion
 }
@@ -0,0 +1,116 @@
+package provider
+
+// CacheControl marks the end of a cacheable prompt prefix. Everything in
+// the request up to and including the marked element (tools, then system,
+// then messages) may be served from the provider's prompt cache on later
+// requests that share the same prefix.
+//
+// Providers translate the marker to their native mechanism: Anthropic
+// cache_control blocks, Gemini cachedContents resources. Providers with
+// automatic prefix caching (OpenAI) ignore it and report cache hits in
+// Usage.CacheReadTokens.
+type CacheControl struct {
+	Type string `json:"type"`          // "ephemeral"
+	TTL  string `json:"ttl,omitempty"` // e.g. "5m", "1h"; provider default when empty
+}
+
+// CacheControlEphemeral is the only cache type providers currently accept.
+const CacheControlEphemeral = "ephemeral"
+
+// NewCacheControl returns an ephemeral cache marker with the given TTL.
+// Pass an empty ttl for the provider default.
+func NewCacheControl(ttl string) *CacheControl {
+	return &CacheControl{Type: CacheControlEphemeral, TTL: ttl}
+}
+
+// HasCacheControl reports whether any element of the request carries a
+// prompt-cache marker.
+func (r *CompletionRequest) HasCacheControl() bool {
+	if r.SystemCacheControl != nil {
+		return true
+	}
+	for i := range r.Tools {
+		if r.Tools[i].CacheControl != nil {
+			return true
+		}
+	}
+	for i := range r.Messages {
+		if messageHasCacheControl(&r.Messages[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// StripCacheControl returns req with every prompt-cache marker removed,
+// for providers whose wire format rejects unknown fields. The original
+// request is left untouched; req itself is returned when it has no
+// markers.
+func StripCacheControl(req *CompletionRequest) *CompletionRequest {
+	if !req.HasCacheControl() {
+		return req
+	}
+	out := *req
+	out.SystemCacheControl = nil
+
+	if len(req.Tools) > 0 {
+		out.Tools = make([]Tool, len(req.Tools))
+		for i, t := range req.Tools {
+			t.CacheControl = nil
+			out.Tools[i] = t
+		}
+	}
+
+	out.Messages = make([]Message, len(req.Messages))
+	for i, m := range req.Messages {
+		m.CacheControl = nil
+		switch parts := m.Content.(type) {
+		case []ContentPart:
+			stripped := make([]ContentPart, len(parts))
+			for j, p := range parts {
+				p.CacheControl = nil
+				stripped[j] = p
+			}
+			m.Content = stripped
+		case []any:
+			// Decoded JSON content parts arrive as generic maps.
+			stripped := make([]any, len(parts))
+			for j, p := range parts {
+				if pm, ok := p.(map[string]any); ok && pm["cache_control"] != nil {
+					cp := make(map[string]any, len(pm))
+					for k, v := range pm {
+						if k != "cache_control" {
+							cp[k] = v
+						}
+					}
+					p = cp
+				}
+				stripped[j] = p
+			}
+			m.Content = stripped
+		}
+		out.Messages[i] = m
+	}
+	return &out
+}
+
+func messageHasCacheControl(m *Message) bool {
+	if m.CacheControl != nil {
+		return true
+	}
+	switch parts := m.Content.(type) {
+	case []ContentPart:
+		for i := range parts {
+			if parts[i].CacheControl != nil {
+				return true
+			}
+		}
+	case []any:
+		for _, p := range parts {
+			if pm, ok := p.(map[string]any); ok && pm["cache_control"] != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,5 +1,10 @@
 package provider
 
+import (
+	"encoding/json"
+	"errors"
+)
+
 // CompletionRequest is the unified request type across all providers.
 type CompletionRequest struct {
 	// Routing
@@ -10,6 +15,12 @@ type CompletionRequest struct {
 	Messages []Message `json:"messages"`
 	System   string    `json:"system,omitempty"` // system prompt (Anthropic-style)
 
+	// Prompt caching. SystemCacheControl marks the System prompt as a
+	// cacheable prefix; CachedContent names an existing provider-side cache
+	// resource (Gemini cachedContents) to serve the prefix from.
+	SystemCacheControl *CacheControl `json:"system_cache_control,omitempty"`
+	CachedContent      string        `json:"cached_content,omitempty"`
+
 	// Parameters
 	MaxTokens   int      `json:"max_tokens,omitempty"`
 	Temperature *float64 `json:"temperature,omitempty"`
@@ -27,10 +38,41 @@ type CompletionRequest struct {
 	// Extended thinking / reasoning
 	Thinking *ThinkingConfig `json:"thinking,omitempty"`
 
+	// Prompt registry. PromptID names a registered template that is
+	// rendered with PromptVariables and prepended as a system message;
+	// PromptVersion selects a version number or label (default: the
+	// "production" label, else the latest version). Not sent to providers.
+	PromptID        string         `json:"prompt_id,omitempty"`
+	PromptVersion   PromptRef      `json:"prompt_version,omitempty"`
+	PromptVariables map[string]any `json:"prompt_variables,omitempty"`
+
 	// Nexus metadata (not sent to provider)
 	TenantID string            `json:"-"`
 	KeyID    string            `json:"-"`
 	Metadata map[string]string `json:"-"` // user-defined labels
+
+	// State carries per-request data from input transforms to the matching
+	// output transforms (e.g. pseudonym mappings). Not sent to providers.
+	State map[string]any `json:"-"`
+}
+
+// PromptRef is a prompt version number or label. It decodes from a JSON
+// number as well as a string, so "prompt_version": 3 and "3" are the same.
+type PromptRef string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *PromptRef) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*r = PromptRef(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return errors.New("prompt_version must be a version number or a label")
+	}
+	*r = PromptRef(n.String())
+	return nil
 }
 
 // ThinkingConfig controls extended thinking / reasoning behavior.
@@ -38,6 +80,10 @@ type ThinkingConfig struct {
 	Enabled         bool `json:"enabled,omitempty"`
 	BudgetTokens    int  `json:"budget_tokens,omitempty"`
 	IncludeThinking bool `json:"include_thinking,omitempty"`
+
+	// Effort is the reasoning level for models that take one instead of a
+	// token budget (OpenAI o-series): minimal, low, medium or high.
+	Effort string `json:"effort,omitempty"`
 }
 
 // Message represents a conversation message.
@@ -47,6 +93,22 @@ type Message struct {
 	Name       string     `json:"name,omitempty"`
 	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
 	ToolCallID string     `json:"tool_call_id,omitempty"`
+
+	// Thinking holds an assistant turn's extended-thinking blocks, sent
+	// back to providers that verify them (Anthropic) and ignored by others.
+	Thinking []ThinkingBlock `json:"thinking_blocks,omitempty"`
+
+	// CacheControl marks this message as the end of a cacheable prefix.
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
+}
+
+// ThinkingBlock is one extended-thinking block. Signature, or Redacted for
+// a block the provider returned encrypted, lets the provider that produced
+// it verify the block when the turn is sent back.
+type ThinkingBlock struct {
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Redacted  string `json:"redacted,omitempty"`
 }
 
 // ContentPart for multimodal messages.
@@ -56,12 +118,19 @@ type ContentPart struct {
 	ImageURL string `json:"image_url,omitempty"`
 	Data     string `json:"data,omitempty"` // base64
 	MimeType string `json:"mime_type,omitempty"`
+
+	// CacheControl marks this part as the end of a cacheable prefix.
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 // Tool definition.
 type Tool struct {
 	Type     string       `json:"type"` // function
 	Function ToolFunction `json:"function"`
+
+	// CacheControl marks the tool list up to and including this tool as a
+	// cacheable prefix.
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 // ToolFunction describes a function tool.
@@ -114,6 +183,44 @@ type JSONSchemaDef struct {
 // EmbeddingRequest for text embeddings.
 type EmbeddingRequest struct {
 	Model    string   `json:"model"`
+	Provider string   `json:"provider,omitempty"` // force specific provider
 	Input    []string `json:"input"`
-	TenantID string   `json:"-"`
+
+	// Dimensions truncates the output vectors for models that support it
+	// (OpenAI text-embedding-3, Cohere v4, Voyage, Jina, Gemini).
+	Dimensions int `json:"dimensions,omitempty"`
+	// EncodingFormat is "float" (default) or "base64". Providers always
+	// return floats; the proxy does the encoding.
+	EncodingFormat string `json:"encoding_format,omitempty"`
+	// InputType tells retrieval models what the text is for: one of the
+	// EmbeddingInput* constants. Providers without the notion ignore it.
+	InputType string `json:"input_type,omitempty"`
+	// Truncate controls what happens to inputs longer than the context
+	// window: "none" (error), "start" or "end". Empty uses the provider
+	// default.
+	Truncate string `json:"truncate,omitempty"`
+
+	TenantID string `json:"-"`
 }
+
+// EmbeddingLimits caps a single upstream embedding call. Zero means no
+// limit.
+type EmbeddingLimits struct {
+	MaxInputs int `json:"max_inputs,omitempty"` // texts per call
+	MaxTokens int `json:"max_tokens,omitempty"` // total input tokens per call
+}
+
+// EmbeddingLimiter is implemented by providers that cap embedding calls.
+// The gateway splits larger requests into chunks that fit and reassembles
+// the results.
+type EmbeddingLimiter interface {
+	EmbeddingLimits(model string) EmbeddingLimits
+}
+
+// Embedding input types.
+const (
+	EmbeddingInputQuery          = "query"
+	EmbeddingInputDocument       = "document"
+	EmbeddingInputClassification = "classification"
+	EmbeddingInputClustering     = "clustering"
+)
@@ -0,0 +1,33 @@
+package provider_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xraph/nexus/provider"
+)
+
+func TestPromptRef_DecodesNumbersAndLabels(t *testing.T) {
+	t.Parallel()
+	for body, want := range map[string]provider.PromptRef{
+		`{"prompt_version":3}`:          "3",
+		`{"prompt_version":"3"}`:        "3",
+		`{"prompt_version":"canary"}`:   "canary",
+		`{"prompt_version":null}`:       "",
+		`{"model":"m","prompt_id":"p"}`: "",
+	} {
+		var req provider.CompletionRequest
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Errorf("%s: %v", body, err)
+			continue
+		}
+		if req.PromptVersion != want {
+			t.Errorf("%s: prompt version = %q, want %q", body, req.PromptVersion, want)
+		}
+	}
+
+	var req provider.CompletionRequest
+	if err := json.Unmarshal([]byte(`{"prompt_version":true}`), &req); err == nil {
+		t.Error("boolean prompt version: expected an error")
+	}
+}
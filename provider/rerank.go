@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// RerankProvider is implemented by providers that score documents by
+// relevance to a query. It is optional: the gateway routes rerank requests
+// only to providers that implement it and advertise Capabilities.Rerank.
+type RerankProvider interface {
+	// Rerank orders req.Documents by relevance to req.Query.
+	Rerank(ctx context.Context, req *RerankRequest) (*RerankResponse, error)
+}
+
+// RerankRequest is the unified rerank request, modelled on Cohere's
+// /v2/rerank.
+type RerankRequest struct {
+	Model    string `json:"model"`
+	Provider string `json:"provider,omitempty"` // force specific provider
+
+	Query     string          `json:"query"`
+	Documents RerankDocuments `json:"documents"`
+
+	TopN            int  `json:"top_n,omitempty"` // return only the best N (default all)
+	ReturnDocuments bool `json:"return_documents,omitempty"`
+	MaxTokensPerDoc int  `json:"max_tokens_per_doc,omitempty"` // truncate long documents
+
+	// Nexus metadata (not sent to provider)
+	TenantID string `json:"-"`
+	KeyID    string `json:"-"`
+}
+
+// RerankDocuments is the list of texts to rank. It decodes from plain
+// strings or Cohere v1 objects of the form {"text": "..."}.
+type RerankDocuments []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *RerankDocuments) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	docs := make(RerankDocuments, len(raw))
+	for i, r := range raw {
+		if err := json.Unmarshal(r, &docs[i]); err == nil {
+			continue
+		}
+		var obj struct {
+			Text *string `json:"text"`
+		}
+		if err := json.Unmarshal(r, &obj); err != nil || obj.Text == nil {
+			return errors.New("documents must be strings or objects with a text field")
+		}
+		docs[i] = *obj.Text
+	}
+	*d = docs
+	return nil
+}
+
+// RerankResponse is the unified rerank response. Results are ordered by
+// descending relevance.
+type RerankResponse struct {
+	ID       string         `json:"id,omitempty"`
+	Provider string         `json:"provider"`
+	Model    string         `json:"model"`
+	Results  []RerankResult `json:"results"`
+	Usage    Usage          `json:"usage,omitempty"` // token-billed rerankers (Jina, Voyage)
+
+	// SearchUnits is set by rerankers billed per search (Cohere).
+	SearchUnits int     `json:"search_units,omitempty"`
+	Cost        float64 `json:"cost,omitempty"` // USD
+}
+
+// RerankResult is one scored document.
+type RerankResult struct {
+	Index          int             `json:"index"` // position in RerankRequest.Documents
+	RelevanceScore float64         `json:"relevance_score"`
+	Document       *RerankDocument `json:"document,omitempty"` // set when ReturnDocuments
+}
+
+// RerankDocument is a ranked document's text.
+type RerankDocument struct {
+	Text string `json:"text"`
+}
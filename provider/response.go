@@ -24,6 +24,10 @@ type CompletionResponse struct {
 	ThinkingContent string `json:"thinking_content,omitempty"`
 	ThinkingTokens  int    `json:"thinking_tokens,omitempty"`
 
+	// Citations are source references for the response: provider grounding
+	// or retrieved context (RAG).
+	Citations []Citation `json:"citations,omitempty"`
+
 	// State is used to pass metadata between middleware layers.
 	State map[string]any `json:"-"`
 }
@@ -36,6 +40,11 @@ type Choice struct {
 }
 
 // Usage tracks token consumption.
+//
+// PromptTokens counts every input token, including those read from or
+// written to the provider's prompt cache; CacheReadTokens and
+// CacheWriteTokens break out the cached share so cost can be computed at
+// the discounted rates.
 type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
@@ -102,6 +102,10 @@ type Delta struct {
 	// DeepSeek R1 reasoning_content). Emitted alongside (or before) Content.
 	Reasoning string `json:"reasoning,omitempty"`
 
+	// ReasoningSignature closes the thinking block streamed so far with the
+	// provider's signature (Claude signature_delta).
+	ReasoningSignature string `json:"reasoning_signature,omitempty"`
+
 	// Refusal is an OpenAI-style structured refusal string.
 	Refusal string `json:"refusal,omitempty"`
 
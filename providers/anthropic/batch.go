@@ -0,0 +1,214 @@
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// batchDiscount is the price reduction Anthropic applies to Message
+// Batches requests.
+const batchDiscount = 0.5
+
+type anthropicBatchRequest struct {
+	CustomID string            `json:"custom_id"`
+	Params   *anthropicRequest `json:"params"`
+}
+
+type anthropicBatch struct {
+	ID               string `json:"id"`
+	ProcessingStatus string `json:"processing_status"` // in_progress, canceling, ended
+	ResultsURL       string `json:"results_url"`
+	RequestCounts    struct {
+		Processing int `json:"processing"`
+		Succeeded  int `json:"succeeded"`
+		Errored    int `json:"errored"`
+		Canceled   int `json:"canceled"`
+		Expired    int `json:"expired"`
+	} `json:"request_counts"`
+}
+
+type anthropicBatchResult struct {
+	CustomID string `json:"custom_id"`
+	Result   struct {
+		Type    string             `json:"type"` // succeeded, errored, canceled, expired
+		Message *anthropicResponse `json:"message"`
+		Error   *struct {
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"error"`
+	} `json:"result"`
+}
+
+// SubmitBatch creates a Message Batch. Anthropic requires custom IDs of
+// 1-64 letters, digits, hyphens and underscores and rejects the batch
+// otherwise.
+func (p *Provider) SubmitBatch(ctx context.Context, reqs []provider.BatchRequest) (string, error) {
+	if err := p.client.requireAPIKey(); err != nil {
+		return "", err
+	}
+	batchReqs := make([]anthropicBatchRequest, len(reqs))
+	for i, r := range reqs {
+		params := p.client.toAnthropicRequest(r.Request)
+		params.Stream = false
+		batchReqs[i] = anthropicBatchRequest{CustomID: r.CustomID, Params: params}
+	}
+
+	body, err := p.client.doJSON(ctx, "POST", p.client.baseURL+"/v1/messages/batches", map[string]any{"requests": batchReqs})
+	if err != nil {
+		return "", err
+	}
+	var b anthropicBatch
+	if err := json.Unmarshal(body, &b); err != nil {
+		return "", fmt.Errorf("anthropic: decode batch: %w", err)
+	}
+	return b.ID, nil
+}
+
+// BatchStatus reports the progress of a Message Batch.
+func (p *Provider) BatchStatus(ctx context.Context, batchID string) (*provider.BatchStatus, error) {
+	b, err := p.client.getBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	counts := b.RequestCounts
+	status := &provider.BatchStatus{
+		State:     provider.BatchStateInProgress,
+		Total:     counts.Processing + counts.Succeeded + counts.Errored + counts.Canceled + counts.Expired,
+		Completed: counts.Succeeded,
+		Failed:    counts.Errored + counts.Canceled + counts.Expired,
+	}
+	if b.ProcessingStatus == "ended" {
+		switch {
+		case counts.Canceled > 0:
+			status.State = provider.BatchStateCancelled
+		case counts.Expired > 0:
+			status.State = provider.BatchStateExpired
+		default:
+			status.State = provider.BatchStateEnded
+		}
+	}
+	return status, nil
+}
+
+// BatchResults downloads the results of an ended Message Batch.
+func (p *Provider) BatchResults(ctx context.Context, batchID string) ([]provider.BatchResult, error) {
+	b, err := p.client.getBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	url := b.ResultsURL
+	if url == "" {
+		url = p.client.baseURL + "/v1/messages/batches/" + batchID + "/results"
+	}
+	content, err := p.client.doJSON(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []provider.BatchResult
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var line anthropicBatchResult
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("anthropic: decode batch result: %w", err)
+		}
+		results = append(results, p.client.fromBatchResult(&line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("anthropic: read batch results: %w", err)
+	}
+	return results, nil
+}
+
+// CancelBatch cancels a Message Batch. Requests already processed keep
+// their results.
+func (p *Provider) CancelBatch(ctx context.Context, batchID string) error {
+	_, err := p.client.doJSON(ctx, "POST", p.client.baseURL+"/v1/messages/batches/"+batchID+"/cancel", nil)
+	return err
+}
+
+// BatchDiscount returns the Message Batches discount.
+func (p *Provider) BatchDiscount() float64 { return batchDiscount }
+
+func (c *client) getBatch(ctx context.Context, batchID string) (*anthropicBatch, error) {
+	body, err := c.doJSON(ctx, "GET", c.baseURL+"/v1/messages/batches/"+batchID, nil)
+	if err != nil {
+		return nil, err
+	}
+	var b anthropicBatch
+	if err := json.Unmarshal(body, &b); err != nil {
+		return nil, fmt.Errorf("anthropic: decode batch: %w", err)
+	}
+	return &b, nil
+}
+
+func (c *client) fromBatchResult(line *anthropicBatchResult) provider.BatchResult {
+	r := provider.BatchResult{CustomID: line.CustomID}
+	switch line.Result.Type {
+	case "succeeded":
+		if line.Result.Message == nil {
+			r.Error = "missing message"
+			break
+		}
+		r.StatusCode = http.StatusOK
+		r.Response = c.fromAnthropicResponse(line.Result.Message, 0)
+	case "errored":
+		r.StatusCode = http.StatusBadRequest
+		r.Error = "request errored"
+		if line.Result.Error != nil {
+			r.Error = line.Result.Error.Error.Type + ": " + line.Result.Error.Error.Message
+		}
+	default: // canceled, expired
+		r.Error = "request " + line.Result.Type
+	}
+	return r
+}
+
+// doJSON sends a JSON request (payload may be nil) to url and returns the
+// response body.
+func (c *client) doJSON(ctx context.Context, method, url string, payload any) ([]byte, error) {
+	var body io.Reader = http.NoBody
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("anthropic: marshal request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("anthropic: API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
+
+// Compile-time check.
+var _ provider.BatchProvider = (*Provider)(nil)
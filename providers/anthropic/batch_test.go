@@ -0,0 +1,80 @@
+package anthropic_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/providers/anthropic"
+)
+
+func TestBatch(t *testing.T) {
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/messages/batches", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []struct {
+				CustomID string         `json:"custom_id"`
+				Params   map[string]any `json:"params"`
+			} `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if len(body.Requests) != 2 || body.Requests[0].CustomID != "a" || body.Requests[0].Params["model"] != "claude-3-5-haiku-latest" {
+			t.Errorf("batch body = %+v", body)
+		}
+		if r.Header.Get("x-api-key") != "test-key" {
+			t.Errorf("x-api-key = %q", r.Header.Get("x-api-key"))
+		}
+		_, _ = io.WriteString(w, `{"id":"msgbatch_1","processing_status":"in_progress"}`)
+	})
+	mux.HandleFunc("GET /v1/messages/batches/msgbatch_1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"id":"msgbatch_1","processing_status":"ended","results_url":"`+server.URL+`/results/msgbatch_1",
+			"request_counts":{"processing":0,"succeeded":1,"errored":1,"canceled":0,"expired":0}}`)
+	})
+	mux.HandleFunc("GET /results/msgbatch_1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"custom_id":"a","result":{"type":"succeeded","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-3-5-haiku-latest","content":[{"type":"text","text":"hi"}],"stop_reason":"end_turn","usage":{"input_tokens":4,"output_tokens":1}}}}
+{"custom_id":"b","result":{"type":"errored","error":{"type":"error","error":{"type":"invalid_request_error","message":"bad"}}}}
+`)
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	p := anthropic.New("test-key", anthropic.WithBaseURL(server.URL))
+	ctx := context.Background()
+	req := &provider.CompletionRequest{Model: "claude-3-5-haiku-latest", Messages: []provider.Message{{Role: "user", Content: "hello"}}}
+	batchID, err := p.SubmitBatch(ctx, []provider.BatchRequest{{CustomID: "a", Request: req}, {CustomID: "b", Request: req}})
+	if err != nil {
+		t.Fatalf("SubmitBatch() error: %v", err)
+	}
+	if batchID != "msgbatch_1" {
+		t.Fatalf("batch ID = %q", batchID)
+	}
+
+	status, err := p.BatchStatus(ctx, batchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.State != provider.BatchStateEnded || status.Total != 2 || status.Completed != 1 || status.Failed != 1 {
+		t.Errorf("status = %+v", status)
+	}
+
+	results, err := p.BatchResults(ctx, batchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %d, want 2", len(results))
+	}
+	if r := results[0]; r.Response == nil || r.Response.Usage.PromptTokens != 4 || r.Response.Choices[0].Message.Content != "hi" {
+		t.Errorf("result a = %+v", r)
+	}
+	if r := results[1]; r.Response != nil || r.Error != "invalid_request_error: bad" {
+		t.Errorf("result b = %+v", r)
+	}
+}
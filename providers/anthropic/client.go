@@ -19,6 +19,9 @@ type client struct {
 	apiKey  string
 	baseURL string
 	http    *http.Client
+
+	// creds, when set, supplies the key per request via the transport.
+	creds provider.CredentialProvider
 }
 
 func newClient(apiKey, baseURL string) *client {
@@ -35,7 +38,7 @@ func newClient(apiKey, baseURL string) *client {
 type anthropicRequest struct {
 	Model         string             `json:"model"`
 	Messages      []anthropicMessage `json:"messages"`
-	System        string             `json:"system,omitempty"`
+	System        any                `json:"system,omitempty"` // string, or []anthropicRequestBlock when cache-marked
 	MaxTokens     int                `json:"max_tokens"`
 	Stream        bool               `json:"stream,omitempty"`
 	Temperature   *float64           `json:"temperature,omitempty"`
@@ -54,11 +57,16 @@ type anthropicMessage struct {
 // has no "tool" role: a tool call is a tool_use block on an assistant message,
 // and a tool result is a tool_result block on a user message.
 type anthropicRequestBlock struct {
-	Type string `json:"type"` // text, tool_use, tool_result
+	Type string `json:"type"` // text, tool_use, tool_result, thinking, redacted_thinking
 
 	// text
 	Text string `json:"text,omitempty"`
 
+	// thinking, redacted_thinking
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"`
+
 	// tool_use
 	ID    string `json:"id,omitempty"`
 	Name  string `json:"name,omitempty"`
@@ -67,12 +75,25 @@ type anthropicRequestBlock struct {
 	// tool_result
 	ToolUseID string `json:"tool_use_id,omitempty"`
 	Content   any    `json:"content,omitempty"`
+
+	// image
+	Source *anthropicImageSource `json:"source,omitempty"`
+
+	CacheControl *provider.CacheControl `json:"cache_control,omitempty"`
+}
+
+type anthropicImageSource struct {
+	Type      string `json:"type"` // base64, url
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
 }
 
 type anthropicTool struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	InputSchema any    `json:"input_schema,omitempty"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  any                    `json:"input_schema,omitempty"`
+	CacheControl *provider.CacheControl `json:"cache_control,omitempty"`
 }
 
 type anthropicThinking struct {
@@ -87,19 +108,40 @@ type anthropicResponse struct {
 	Model      string                  `json:"model"`
 	Content    []anthropicContentBlock `json:"content"`
 	StopReason string                  `json:"stop_reason"`
-	Usage      struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
+	Usage      anthropicUsage          `json:"usage"`
+}
+
+// anthropicUsage reports token counts. input_tokens excludes tokens read
+// from or written to the prompt cache, which are reported separately.
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+}
+
+// toUsage normalises Anthropic usage so PromptTokens includes cached
+// tokens, matching the provider.Usage contract.
+func (u anthropicUsage) toUsage() provider.Usage {
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return provider.Usage{
+		PromptTokens:     prompt,
+		CompletionTokens: u.OutputTokens,
+		CacheReadTokens:  u.CacheReadInputTokens,
+		CacheWriteTokens: u.CacheCreationInputTokens,
+		TotalTokens:      prompt + u.OutputTokens,
+	}
 }
 
 type anthropicContentBlock struct {
-	Type     string `json:"type"` // text, tool_use, thinking
-	Text     string `json:"text,omitempty"`
-	ID       string `json:"id,omitempty"`
-	Name     string `json:"name,omitempty"`
-	Input    any    `json:"input,omitempty"`
-	Thinking string `json:"thinking,omitempty"`
+	Type      string `json:"type"` // text, tool_use, thinking, redacted_thinking
+	Text      string `json:"text,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Input     any    `json:"input,omitempty"`
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"`
 }
 
 func (c *client) complete(ctx context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error) {
@@ -207,7 +249,7 @@ func (c *client) ping(ctx context.Context) error {
 // upstream 401 ("x-api-key header is required"), so we surface a clear local
 // error before any network round-trip instead.
 func (c *client) requireAPIKey() error {
-	if c.apiKey == "" {
+	if c.apiKey == "" && c.creds == nil {
 		return fmt.Errorf("anthropic: %w", provider.ErrMissingAPIKey)
 	}
 	return nil
@@ -226,16 +268,15 @@ func (c *client) toAnthropicRequest(req *provider.CompletionRequest) *anthropicR
 	// Anthropic shape. Anthropic only accepts "user" and "assistant": a
 	// "tool" result message becomes a user message with a tool_result block,
 	// and an assistant tool call becomes a tool_use block.
-	system := req.System
+	var system []anthropicRequestBlock
+	if req.System != "" {
+		system = append(system, anthropicRequestBlock{Type: "text", Text: req.System, CacheControl: req.SystemCacheControl})
+	}
 	for _, m := range req.Messages {
 		switch m.Role {
 		case "system":
-			if s, ok := m.Content.(string); ok {
-				if system == "" {
-					system = s
-				} else {
-					system += "\n\n" + s
-				}
+			if text := messageText(m.Content); text != "" {
+				system = append(system, anthropicRequestBlock{Type: "text", Text: text, CacheControl: lastCacheControl(m)})
 			}
 
 		case "tool":
@@ -245,21 +286,30 @@ func (c *client) toAnthropicRequest(req *provider.CompletionRequest) *anthropicR
 			messages = append(messages, anthropicMessage{
 				Role: "user",
 				Content: []anthropicRequestBlock{{
-					Type:      "tool_result",
-					ToolUseID: m.ToolCallID,
-					Content:   messageText(m.Content),
+					Type:         "tool_result",
+					ToolUseID:    m.ToolCallID,
+					Content:      messageText(m.Content),
+					CacheControl: m.CacheControl,
 				}},
 			})
 
 		case "assistant":
-			if len(m.ToolCalls) == 0 {
-				messages = append(messages, anthropicMessage{Role: "assistant", Content: m.Content})
+			if len(m.ToolCalls) == 0 && len(m.Thinking) == 0 {
+				messages = append(messages, anthropicMessage{Role: "assistant", Content: messageContent(m)})
 				break
 			}
 			// An assistant turn that calls tools must serialize each call as a
 			// tool_use block (with the arguments as a JSON object, not a
-			// string), preceded by any accompanying text.
-			blocks := make([]anthropicRequestBlock, 0, len(m.ToolCalls)+1)
+			// string), preceded by its thinking blocks, which Anthropic
+			// verifies by signature, and any accompanying text.
+			blocks := make([]anthropicRequestBlock, 0, len(m.Thinking)+len(m.ToolCalls)+1)
+			for _, th := range m.Thinking {
+				if th.Redacted != "" {
+					blocks = append(blocks, anthropicRequestBlock{Type: "redacted_thinking", Data: th.Redacted})
+					continue
+				}
+				blocks = append(blocks, anthropicRequestBlock{Type: "thinking", Thinking: th.Thinking, Signature: th.Signature})
+			}
 			if text := messageText(m.Content); text != "" {
 				blocks = append(blocks, anthropicRequestBlock{Type: "text", Text: text})
 			}
@@ -271,10 +321,14 @@ func (c *client) toAnthropicRequest(req *provider.CompletionRequest) *anthropicR
 					Input: toolInput(tc.Function.Arguments),
 				})
 			}
+			// Thinking blocks cannot carry a cache marker.
+			if last := &blocks[len(blocks)-1]; last.Type != "thinking" && last.Type != "redacted_thinking" {
+				last.CacheControl = m.CacheControl
+			}
 			messages = append(messages, anthropicMessage{Role: "assistant", Content: blocks})
 
 		default: // "user" and anything else
-			messages = append(messages, anthropicMessage{Role: m.Role, Content: m.Content})
+			messages = append(messages, anthropicMessage{Role: m.Role, Content: messageContent(m)})
 		}
 	}
 
@@ -286,19 +340,25 @@ func (c *client) toAnthropicRequest(req *provider.CompletionRequest) *anthropicR
 	antReq := &anthropicRequest{
 		Model:         req.Model,
 		Messages:      messages,
-		System:        system,
 		MaxTokens:     maxTokens,
 		Temperature:   req.Temperature,
 		TopP:          req.TopP,
 		StopSequences: req.Stop,
 	}
 
+	// A plain string keeps the request identical to what Anthropic has
+	// always received; blocks are only needed to carry cache_control.
+	if len(system) > 0 {
+		antReq.System = joinSystem(system)
+	}
+
 	// Convert tools
 	for _, t := range req.Tools {
 		antReq.Tools = append(antReq.Tools, anthropicTool{
-			Name:        t.Function.Name,
-			Description: t.Function.Description,
-			InputSchema: t.Function.Parameters,
+			Name:         t.Function.Name,
+			Description:  t.Function.Description,
+			InputSchema:  t.Function.Parameters,
+			CacheControl: t.CacheControl,
 		})
 	}
 
@@ -313,6 +373,101 @@ func (c *client) toAnthropicRequest(req *provider.CompletionRequest) *anthropicR
 	return antReq
 }
 
+// joinSystem returns the system prompt as a single string when no block is
+// cache-marked, or as text blocks otherwise.
+func joinSystem(blocks []anthropicRequestBlock) any {
+	for _, b := range blocks {
+		if b.CacheControl != nil {
+			return blocks
+		}
+	}
+	texts := make([]string, len(blocks))
+	for i, b := range blocks {
+		texts[i] = b.Text
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// messageContent converts a user or assistant message's Content to the
+// Anthropic shape. Strings pass through unless the message is cache-marked;
+// multimodal parts become text/image blocks carrying their cache markers.
+func messageContent(m provider.Message) any {
+	switch v := m.Content.(type) {
+	case string:
+		if m.CacheControl == nil {
+			return v
+		}
+		return []anthropicRequestBlock{{Type: "text", Text: v, CacheControl: m.CacheControl}}
+	case []provider.ContentPart:
+		blocks := make([]anthropicRequestBlock, 0, len(v))
+		for _, p := range v {
+			blocks = append(blocks, partBlock(p))
+		}
+		if m.CacheControl != nil && len(blocks) > 0 {
+			blocks[len(blocks)-1].CacheControl = m.CacheControl
+		}
+		return blocks
+	case []any:
+		// Decoded JSON parts already use Anthropic's {type,text,cache_control}
+		// shape for text; only a message-level marker needs applying.
+		if m.CacheControl == nil || len(v) == 0 {
+			return v
+		}
+		out := append([]any(nil), v...)
+		if last, ok := out[len(out)-1].(map[string]any); ok {
+			cp := make(map[string]any, len(last)+1)
+			for k, val := range last {
+				cp[k] = val
+			}
+			cp["cache_control"] = m.CacheControl
+			out[len(out)-1] = cp
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// partBlock converts a unified content part to an Anthropic content block.
+func partBlock(p provider.ContentPart) anthropicRequestBlock {
+	switch p.Type {
+	case "image_url":
+		src := &anthropicImageSource{Type: "url", URL: p.ImageURL}
+		// Anthropic's url source only fetches http(s); inline data URLs
+		// must be sent as base64.
+		if rest, ok := strings.CutPrefix(p.ImageURL, "data:"); ok {
+			if meta, data, found := strings.Cut(rest, ","); found {
+				src = &anthropicImageSource{Type: "base64", MediaType: strings.TrimSuffix(meta, ";base64"), Data: data}
+			}
+		}
+		return anthropicRequestBlock{Type: "image", Source: src, CacheControl: p.CacheControl}
+	case "image_base64":
+		return anthropicRequestBlock{
+			Type:         "image",
+			Source:       &anthropicImageSource{Type: "base64", MediaType: p.MimeType, Data: p.Data},
+			CacheControl: p.CacheControl,
+		}
+	default:
+		return anthropicRequestBlock{Type: "text", Text: p.Text, CacheControl: p.CacheControl}
+	}
+}
+
+// lastCacheControl returns the marker that applies to the end of m: the
+// message-level marker, or else the marker on its last content part.
+func lastCacheControl(m provider.Message) *provider.CacheControl {
+	if m.CacheControl != nil {
+		return m.CacheControl
+	}
+	if parts, ok := m.Content.([]provider.ContentPart); ok {
+		for i := len(parts) - 1; i >= 0; i-- {
+			if parts[i].CacheControl != nil {
+				return parts[i].CacheControl
+			}
+		}
+	}
+	return nil
+}
+
 // messageText flattens a message's Content to a plain string for use inside a
 // tool_result block or as text content. Strings pass through; multimodal parts
 // contribute their text; anything else falls back to its JSON encoding.
@@ -357,6 +512,7 @@ func (c *client) fromAnthropicResponse(resp *anthropicResponse, elapsed time.Dur
 	var content string
 	var thinkingContent string
 	var toolCalls []provider.ToolCall
+	var thinking []provider.ThinkingBlock
 
 	for _, block := range resp.Content {
 		switch block.Type {
@@ -364,6 +520,9 @@ func (c *client) fromAnthropicResponse(resp *anthropicResponse, elapsed time.Dur
 			content += block.Text
 		case "thinking":
 			thinkingContent += block.Thinking
+			thinking = append(thinking, provider.ThinkingBlock{Thinking: block.Thinking, Signature: block.Signature})
+		case "redacted_thinking":
+			thinking = append(thinking, provider.ThinkingBlock{Redacted: block.Data})
 		case "tool_use":
 			inputJSON, _ := json.Marshal(block.Input) //nolint:errcheck // known-good struct
 			toolCalls = append(toolCalls, provider.ToolCall{
@@ -388,14 +547,11 @@ func (c *client) fromAnthropicResponse(resp *anthropicResponse, elapsed time.Dur
 				Role:      "assistant",
 				Content:   content,
 				ToolCalls: toolCalls,
+				Thinking:  thinking,
 			},
 			FinishReason: mapStopReason(resp.StopReason),
 		}},
-		Usage: provider.Usage{
-			PromptTokens:     resp.Usage.InputTokens,
-			CompletionTokens: resp.Usage.OutputTokens,
-			TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
-		},
+		Usage:           resp.Usage.toUsage(),
 		Latency:         elapsed,
 		ThinkingContent: thinkingContent,
 	}
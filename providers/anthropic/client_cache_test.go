@@ -0,0 +1,57 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// Cache markers on the unified request must reach Anthropic as
+// cache_control on the matching system, tool and content blocks.
+func TestToAnthropicRequest_cacheControlTranslated(t *testing.T) {
+	c := newClient("k", "https://example.test")
+	req := &provider.CompletionRequest{
+		Model:              "claude-sonnet-4-5-20250514",
+		System:             "long stable instructions",
+		SystemCacheControl: provider.NewCacheControl(""),
+		Tools: []provider.Tool{{
+			Type:         "function",
+			Function:     provider.ToolFunction{Name: "search"},
+			CacheControl: provider.NewCacheControl("1h"),
+		}},
+		Messages: []provider.Message{
+			{Role: "user", Content: []provider.ContentPart{
+				{Type: "text", Text: "a long document", CacheControl: provider.NewCacheControl("")},
+				{Type: "text", Text: "question"},
+			}},
+		},
+	}
+
+	got := c.toAnthropicRequest(req)
+
+	blocks, ok := got.System.([]anthropicRequestBlock)
+	if !ok || len(blocks) != 1 || blocks[0].CacheControl == nil {
+		t.Fatalf("system = %#v, want one cache-marked block", got.System)
+	}
+
+	raw, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	wire := string(raw)
+	if n := strings.Count(wire, `"cache_control":{"type":"ephemeral"`); n != 3 {
+		t.Fatalf("cache_control count = %d, want 3 in: %s", n, wire)
+	}
+	if !strings.Contains(wire, `"ttl":"1h"`) {
+		t.Fatalf("tool ttl missing in: %s", wire)
+	}
+}
+
+func TestAnthropicUsage_includesCachedTokens(t *testing.T) {
+	u := anthropicUsage{InputTokens: 10, OutputTokens: 5, CacheReadInputTokens: 100, CacheCreationInputTokens: 20}.toUsage()
+	if u.PromptTokens != 130 || u.CacheReadTokens != 100 || u.CacheWriteTokens != 20 || u.TotalTokens != 135 {
+		t.Fatalf("usage = %+v", u)
+	}
+}
@@ -0,0 +1,62 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// With extended thinking and tools, Anthropic requires the assistant turn's
+// signed thinking blocks back, ahead of its tool_use blocks.
+func TestToAnthropicRequest_sendsThinkingBlocksBack(t *testing.T) {
+	c := newClient("k", "https://example.test")
+	req := &provider.CompletionRequest{
+		Model: "claude-sonnet-4",
+		Messages: []provider.Message{
+			{Role: "user", Content: "weather in Paris?"},
+			{Role: "assistant", Thinking: []provider.ThinkingBlock{
+				{Thinking: "look it up", Signature: "sig"},
+				{Redacted: "enc"},
+			}, ToolCalls: []provider.ToolCall{{
+				ID: "toolu_1", Type: "function",
+				Function: provider.ToolCallFunc{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+			}}},
+			{Role: "tool", ToolCallID: "toolu_1", Content: "sunny"},
+		},
+	}
+
+	raw, err := json.Marshal(c.toAnthropicRequest(req).Messages[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"role":"assistant","content":[` +
+		`{"type":"thinking","thinking":"look it up","signature":"sig"},` +
+		`{"type":"redacted_thinking","data":"enc"},` +
+		`{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{"city":"Paris"}}]}`
+	if string(raw) != want {
+		t.Fatalf("assistant turn =\n%s\nwant\n%s", raw, want)
+	}
+}
+
+func TestFromAnthropicResponse_keepsThinkingSignatures(t *testing.T) {
+	c := newClient("k", "https://example.test")
+	var resp anthropicResponse
+	if err := json.NewDecoder(strings.NewReader(`{"id":"msg_1","model":"claude-sonnet-4","stop_reason":"end_turn","content":[
+		{"type":"thinking","thinking":"hmm","signature":"sig"},
+		{"type":"redacted_thinking","data":"enc"},
+		{"type":"text","text":"Done."}]}`)).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+
+	out := c.fromAnthropicResponse(&resp, time.Millisecond)
+	th := out.Choices[0].Message.Thinking
+	if len(th) != 2 || th[0].Thinking != "hmm" || th[0].Signature != "sig" || th[1].Redacted != "enc" {
+		t.Fatalf("thinking blocks = %+v", th)
+	}
+	if out.ThinkingContent != "hmm" {
+		t.Fatalf("thinking content = %q", out.ThinkingContent)
+	}
+}
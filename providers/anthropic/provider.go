@@ -12,6 +12,9 @@ type Provider struct {
 	apiKey  string
 	baseURL string
 	client  *client
+
+	creds provider.CredentialProvider
+	inst  provider.Instance
 }
 
 // New creates a new Anthropic provider.
@@ -24,11 +27,18 @@ func New(apiKey string, opts ...Option) *Provider {
 		opt(p)
 	}
 	p.client = newClient(p.apiKey, p.baseURL)
+	if p.creds != nil {
+		p.client.http.Transport = provider.NewCredentialTransport(nil, p.Name(), p.creds, provider.HeaderAuth("x-api-key"))
+		p.client.creds = p.creds
+	}
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "anthropic" }
+func (p *Provider) Name() string { return p.inst.NameOr("anthropic") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "anthropic" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
@@ -48,7 +58,7 @@ func (p *Provider) Capabilities() provider.Capabilities {
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return anthropicModels(), nil
+	return p.inst.FilterModels(p.Name(), anthropicModels()), nil
 }
 
 // Complete sends a chat completion request.
@@ -79,5 +89,21 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
 // Compile-time check.
 var _ provider.Provider = (*Provider)(nil)
@@ -594,3 +594,39 @@ func TestConformance_RejectsMissingAPIKey(t *testing.T) {
 // --------------------------------------------------------------------
 
 var _ provider.Provider = (*anthropic.Provider)(nil)
+
+func TestCompleteStream_ThinkingSignature(t *testing.T) {
+	p, mock := newMockProvider(t)
+
+	delta := func(d map[string]any) string {
+		return testutil.AnthropicEventJSON("content_block_delta", map[string]any{"type": "content_block_delta", "index": 0, "delta": d})
+	}
+	mock.Ctrl.SetStreamHandler(testutil.AnthropicStreamHandler([]string{
+		testutil.AnthropicEventJSON("message_start", map[string]any{
+			"type":    "message_start",
+			"message": map[string]any{"id": "msg_think", "model": "claude-sonnet-4"},
+		}), "",
+		delta(map[string]any{"type": "thinking_delta", "thinking": "let me "}), "",
+		delta(map[string]any{"type": "thinking_delta", "thinking": "think"}), "",
+		delta(map[string]any{"type": "signature_delta", "signature": "sig"}), "",
+		delta(map[string]any{"type": "text_delta", "text": "Done."}), "",
+		testutil.AnthropicEventJSON("message_stop", map[string]any{"type": "message_stop"}), "",
+	}))
+
+	stream, err := p.CompleteStream(context.Background(), &provider.CompletionRequest{
+		Model:    "claude-sonnet-4",
+		Messages: []provider.Message{{Role: "user", Content: "Hello"}},
+		Stream:   true,
+	})
+	if err != nil {
+		t.Fatalf("CompleteStream() error: %v", err)
+	}
+	resp, err := provider.Accumulate(context.Background(), stream)
+	if err != nil {
+		t.Fatalf("Accumulate() error: %v", err)
+	}
+	th := resp.Choices[0].Message.Thinking
+	if len(th) != 1 || th[0].Thinking != "let me think" || th[0].Signature != "sig" {
+		t.Fatalf("thinking blocks = %+v", th)
+	}
+}
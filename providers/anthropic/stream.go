@@ -18,8 +18,10 @@ type anthropicStream struct {
 	usage   *provider.Usage
 	done    bool
 	msgID   string
-	// inputTokens captured from message_start.
+	// inputTokens captured from message_start, including cached tokens.
 	inputTokens int
+	cacheRead   int
+	cacheWrite  int
 
 	// toolBlocks maps Anthropic content-block indexes to in-flight tool
 	// metadata (id + name). content_block_start fires first, then a stream
@@ -98,8 +100,18 @@ func (s *anthropicStream) Next(ctx context.Context) (*provider.StreamChunk, erro
 				s.msgID, _ = msg["id"].(string) //nolint:errcheck // zero value is fine
 				if u, ok := msg["usage"].(map[string]any); ok {
 					if f, ok := u["input_tokens"].(float64); ok {
-						s.inputTokens = int(f)
-						s.usage = &provider.Usage{PromptTokens: s.inputTokens}
+						if r, ok := u["cache_read_input_tokens"].(float64); ok {
+							s.cacheRead = int(r)
+						}
+						if w, ok := u["cache_creation_input_tokens"].(float64); ok {
+							s.cacheWrite = int(w)
+						}
+						s.inputTokens = int(f) + s.cacheRead + s.cacheWrite
+						s.usage = &provider.Usage{
+							PromptTokens:     s.inputTokens,
+							CacheReadTokens:  s.cacheRead,
+							CacheWriteTokens: s.cacheWrite,
+						}
 					}
 				}
 			}
@@ -157,6 +169,16 @@ func (s *anthropicStream) Next(ctx context.Context) (*provider.StreamChunk, erro
 					Delta:    provider.Delta{Reasoning: txt},
 				}, nil
 
+			case "signature_delta":
+				sig, _ := delta["signature"].(string) //nolint:errcheck // zero value is fine
+				return &provider.StreamChunk{
+					ID:       s.msgID,
+					Provider: "anthropic",
+					Model:    s.model,
+					Kind:     provider.EventReasoning,
+					Delta:    provider.Delta{ReasoningSignature: sig},
+				}, nil
+
 			case "input_json_delta":
 				partial, _ := delta["partial_json"].(string) //nolint:errcheck // zero value is fine
 				idx := indexOf(raw)
@@ -212,14 +234,22 @@ func (s *anthropicStream) Next(ctx context.Context) (*provider.StreamChunk, erro
 			if u, ok := raw["usage"].(map[string]any); ok {
 				out, _ := u["output_tokens"].(float64) //nolint:errcheck // zero value is fine
 				outputTokens := int(out)
+				if r, ok := u["cache_read_input_tokens"].(float64); ok {
+					s.cacheRead = int(r)
+				}
+				if w, ok := u["cache_creation_input_tokens"].(float64); ok {
+					s.cacheWrite = int(w)
+				}
 				promptTokens := s.inputTokens
 				if in, ok := u["input_tokens"].(float64); ok {
-					promptTokens = int(in)
+					promptTokens = int(in) + s.cacheRead + s.cacheWrite
 				}
 				total := promptTokens + outputTokens
 				s.usage = &provider.Usage{
 					PromptTokens:     promptTokens,
 					CompletionTokens: outputTokens,
+					CacheReadTokens:  s.cacheRead,
+					CacheWriteTokens: s.cacheWrite,
 					TotalTokens:      total,
 				}
 			}
@@ -0,0 +1,174 @@
+package azureopenai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// Transcribe converts speech to text with a Whisper deployment. Like
+// images, audio models are served from their own deployments.
+func (p *Provider) Transcribe(ctx context.Context, req *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error) {
+	return p.client.transcribe(ctx, req)
+}
+
+// Speech synthesizes speech with a tts deployment.
+func (p *Provider) Speech(ctx context.Context, req *provider.SpeechRequest) (*provider.SpeechResponse, error) {
+	return p.client.speech(ctx, req)
+}
+
+func (c *client) audioURL(operation string) string {
+	return fmt.Sprintf("%s/openai/deployments/%s/audio/%s?api-version=%s",
+		c.baseURL, c.deploymentID, operation, c.apiVersion)
+}
+
+func (c *client) transcribe(ctx context.Context, req *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	filename := req.Filename
+	if filename == "" {
+		filename = "audio.mp3"
+	}
+	fw, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("azureopenai: create form file: %w", err)
+	}
+	if _, err := fw.Write(req.File); err != nil {
+		return nil, fmt.Errorf("azureopenai: write form file: %w", err)
+	}
+	fields := map[string]string{
+		"response_format": "verbose_json",
+		"prompt":          req.Prompt,
+	}
+	if !req.Translate {
+		fields["language"] = req.Language
+	}
+	if req.Temperature != nil {
+		fields["temperature"] = strconv.FormatFloat(*req.Temperature, 'f', -1, 64)
+	}
+	for k, v := range fields {
+		if v == "" {
+			continue
+		}
+		if err := mw.WriteField(k, v); err != nil {
+			return nil, fmt.Errorf("azureopenai: write form field: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("azureopenai: close form: %w", err)
+	}
+
+	operation := "transcriptions"
+	if req.Translate {
+		operation = "translations"
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.audioURL(operation), &body)
+	if err != nil {
+		return nil, fmt.Errorf("azureopenai: create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", mw.FormDataContentType())
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("azureopenai: request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		return nil, fmt.Errorf("azureopenai: API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var resp struct {
+		Text     string                          `json:"text"`
+		Language string                          `json:"language"`
+		Duration float64                         `json:"duration"`
+		Segments []provider.TranscriptionSegment `json:"segments"`
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("azureopenai: decode response: %w", err)
+	}
+
+	return &provider.TranscriptionResponse{
+		Provider: "azureopenai",
+		Model:    req.Model,
+		Text:     resp.Text,
+		Language: resp.Language,
+		Duration: resp.Duration,
+		Segments: resp.Segments,
+		Cost:     modelPricing(req.Model).AudioCost(resp.Duration, 0),
+	}, nil
+}
+
+func (c *client) speech(ctx context.Context, req *provider.SpeechRequest) (*provider.SpeechResponse, error) {
+	payload := map[string]any{
+		"model":           req.Model,
+		"input":           req.Input,
+		"voice":           req.Voice,
+		"response_format": req.Format(),
+	}
+	if req.Speed != 0 {
+		payload["speed"] = req.Speed
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("azureopenai: marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.audioURL("speech"), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("azureopenai: create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("azureopenai: request failed: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		_ = httpResp.Body.Close()
+		return nil, fmt.Errorf("azureopenai: API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	contentType := httpResp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = provider.AudioContentType(req.Format())
+	}
+	return &provider.SpeechResponse{
+		Provider:    "azureopenai",
+		Model:       req.Model,
+		ContentType: contentType,
+		Format:      req.Format(),
+		Audio:       httpResp.Body,
+		Characters:  req.Characters(),
+		Cost:        modelPricing(req.Model).AudioCost(0, req.Characters()),
+	}, nil
+}
+
+// modelPricing returns the catalog pricing for a model, zero if unknown.
+func modelPricing(model string) provider.Pricing {
+	for _, m := range azureOpenAIModels() {
+		if m.ID == model {
+			return m.Pricing
+		}
+	}
+	return provider.Pricing{}
+}
+
+// Compile-time checks.
+var (
+	_ provider.TranscriptionProvider = (*Provider)(nil)
+	_ provider.SpeechProvider        = (*Provider)(nil)
+)
@@ -159,6 +159,9 @@ func (c *client) embed(ctx context.Context, req *provider.EmbeddingRequest) (*pr
 		"model": req.Model,
 		"input": req.Input,
 	}
+	if req.Dimensions > 0 {
+		payload["dimensions"] = req.Dimensions
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -238,6 +241,9 @@ func (c *client) setHeaders(req *http.Request) {
 }
 
 func (c *client) toOAIRequest(req *provider.CompletionRequest) *oaiRequest {
+	// Azure OpenAI rejects unknown fields; prefix caching is automatic.
+	req = provider.StripCacheControl(req)
+
 	messages := make([]oaiMessage, len(req.Messages))
 	for i, m := range req.Messages {
 		messages[i] = oaiMessage{
@@ -0,0 +1,105 @@
+package azureopenai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// GenerateImage creates images with a DALL·E or gpt-image deployment.
+// Azure serves image models from their own deployments, so the provider
+// should be configured with WithDeploymentID pointing at one.
+func (p *Provider) GenerateImage(ctx context.Context, req *provider.ImageRequest) (*provider.ImageResponse, error) {
+	return p.client.generateImage(ctx, req)
+}
+
+func (c *client) imagesURL() string {
+	return fmt.Sprintf("%s/openai/deployments/%s/images/generations?api-version=%s",
+		c.baseURL, c.deploymentID, c.apiVersion)
+}
+
+func (c *client) generateImage(ctx context.Context, req *provider.ImageRequest) (*provider.ImageResponse, error) {
+	payload := map[string]any{
+		"prompt": req.Prompt,
+	}
+	if req.N > 0 {
+		payload["n"] = req.N
+	}
+	if req.Size != "" {
+		payload["size"] = req.Size
+	}
+	if req.Quality != "" {
+		payload["quality"] = req.Quality
+	}
+	if req.Style != "" {
+		payload["style"] = req.Style
+	}
+	if req.ResponseFormat != "" {
+		payload["response_format"] = req.ResponseFormat
+	}
+	if req.User != "" {
+		payload["user"] = req.User
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("azureopenai: marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.imagesURL(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("azureopenai: create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("azureopenai: request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		return nil, fmt.Errorf("azureopenai: API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var resp struct {
+		Created int64            `json:"created"`
+		Data    []provider.Image `json:"data"`
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("azureopenai: decode response: %w", err)
+	}
+
+	return &provider.ImageResponse{
+		Created:  resp.Created,
+		Provider: "azureopenai",
+		Model:    req.Model,
+		Data:     resp.Data,
+		Cost:     imageCost(req, len(resp.Data)),
+	}, nil
+}
+
+// imageCost prices images from the model catalog, doubling the DALL·E 3
+// price for HD quality.
+func imageCost(req *provider.ImageRequest, n int) float64 {
+	for _, m := range azureOpenAIModels() {
+		if m.ID != req.Model {
+			continue
+		}
+		price := m.Pricing.PerImage
+		if req.Quality == "hd" {
+			price *= 2
+		}
+		return price * float64(n)
+	}
+	return 0
+}
+
+// Compile-time check.
+var _ provider.ImageProvider = (*Provider)(nil)
@@ -24,5 +24,29 @@ func azureOpenAIModels() []provider.Model {
 			ContextWindow: 8191,
 			Pricing:       provider.Pricing{EmbeddingPerMillion: 0.02},
 		},
+		{
+			ID: "dall-e-3", Provider: "azureopenai", Name: "DALL·E 3",
+			Capabilities:  provider.Capabilities{Images: true},
+			ContextWindow: 4000, // maximum prompt length
+			Pricing:       provider.Pricing{PerImage: 0.04},
+		},
+		{
+			ID: "whisper", Provider: "azureopenai", Name: "Whisper",
+			Capabilities:  provider.Capabilities{Audio: true},
+			ContextWindow: 448,
+			Pricing:       provider.Pricing{AudioPerSecond: 0.0001},
+		},
+		{
+			ID: "tts", Provider: "azureopenai", Name: "TTS",
+			Capabilities:  provider.Capabilities{Audio: true},
+			ContextWindow: 4096, // maximum input characters
+			Pricing:       provider.Pricing{CharactersPerMillion: 15.00},
+		},
+		{
+			ID: "tts-hd", Provider: "azureopenai", Name: "TTS HD",
+			Capabilities:  provider.Capabilities{Audio: true},
+			ContextWindow: 4096, // maximum input characters
+			Pricing:       provider.Pricing{CharactersPerMillion: 30.00},
+		},
 	}
 }
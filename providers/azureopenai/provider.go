@@ -17,6 +17,9 @@ type Provider struct {
 	apiVersion   string
 	baseURL      string
 	client       *client
+
+	creds provider.CredentialProvider
+	inst  provider.Instance
 }
 
 // New creates a new Azure OpenAI provider.
@@ -29,11 +32,17 @@ func New(apiKey string, opts ...Option) *Provider {
 		opt(p)
 	}
 	p.client = newClient(p.apiKey, p.resourceName, p.deploymentID, p.apiVersion, p.baseURL)
+	if p.creds != nil {
+		p.client.http.Transport = provider.NewCredentialTransport(nil, p.Name(), p.creds, provider.HeaderAuth("api-key"))
+	}
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "azureopenai" }
+func (p *Provider) Name() string { return p.inst.NameOr("azureopenai") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "azureopenai" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
@@ -41,15 +50,18 @@ func (p *Provider) Capabilities() provider.Capabilities {
 		Chat:       true,
 		Streaming:  true,
 		Embeddings: true,
+		Images:     true,
+		Audio:      true,
 		Vision:     true,
 		Tools:      true,
 		JSON:       true,
+		JSONSchema: true,
 	}
 }
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return azureOpenAIModels(), nil
+	return p.inst.FilterModels(p.Name(), azureOpenAIModels()), nil
 }
 
 // Complete sends a chat completion request.
@@ -67,6 +79,11 @@ func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*
 	return p.client.embed(ctx, req)
 }
 
+// EmbeddingLimits reports Azure OpenAI's per-call cap of 2048 inputs.
+func (p *Provider) EmbeddingLimits(_ string) provider.EmbeddingLimits {
+	return provider.EmbeddingLimits{MaxInputs: 2048}
+}
+
 // Healthy returns true if the provider is reachable.
 func (p *Provider) Healthy(ctx context.Context) bool {
 	return p.client.ping(ctx) == nil
@@ -95,5 +112,24 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
-// Compile-time check.
-var _ provider.Provider = (*Provider)(nil)
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
+// Compile-time checks.
+var (
+	_ provider.Provider         = (*Provider)(nil)
+	_ provider.EmbeddingLimiter = (*Provider)(nil)
+)
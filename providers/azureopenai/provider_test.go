@@ -188,6 +188,62 @@ func TestEmbed(t *testing.T) {
 	}
 }
 
+func TestGenerateImage(t *testing.T) {
+	mock := testutil.NewMockServer(t)
+	mock.Ctrl.SetCompletion(map[string]any{
+		"created": 1700000000,
+		"data":    []map[string]any{{"url": "https://example.com/a.png"}},
+	})
+	p := New("test-key",
+		WithBaseURL(mock.Server.URL),
+		WithDeploymentID("dall-e-3"),
+	)
+
+	resp, err := p.GenerateImage(context.Background(), &provider.ImageRequest{
+		Model:  "dall-e-3",
+		Prompt: "a fox",
+	})
+	if err != nil {
+		t.Fatalf("GenerateImage() error: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].URL != "https://example.com/a.png" {
+		t.Errorf("Data=%+v", resp.Data)
+	}
+	if resp.Cost != 0.04 {
+		t.Errorf("Cost=%v, want 0.04", resp.Cost)
+	}
+	if path := mock.Ctrl.GetLastPath(); path != "/openai/deployments/dall-e-3/images/generations" {
+		t.Errorf("path=%q", path)
+	}
+}
+
+func TestTranscribe(t *testing.T) {
+	mock := testutil.NewMockServer(t)
+	mock.Ctrl.SetCompletion(map[string]any{"text": "hello", "duration": 60.0})
+	p := New("test-key",
+		WithBaseURL(mock.Server.URL),
+		WithDeploymentID("whisper"),
+	)
+
+	resp, err := p.Transcribe(context.Background(), &provider.TranscriptionRequest{
+		Model:    "whisper",
+		File:     []byte("audio"),
+		Filename: "a.mp3",
+	})
+	if err != nil {
+		t.Fatalf("Transcribe() error: %v", err)
+	}
+	if resp.Text != "hello" {
+		t.Errorf("Text=%q, want %q", resp.Text, "hello")
+	}
+	if resp.Cost < 0.00599 || resp.Cost > 0.00601 {
+		t.Errorf("Cost=%v, want 0.006", resp.Cost)
+	}
+	if path := mock.Ctrl.GetLastPath(); path != "/openai/deployments/whisper/audio/transcriptions" {
+		t.Errorf("path=%q", path)
+	}
+}
+
 func TestHealthy(t *testing.T) {
 	mock := testutil.NewMockServer(t)
 	p := New("test-key",
@@ -224,3 +280,41 @@ func TestOptions(t *testing.T) {
 		t.Errorf("baseURL=%q, want %q", p.baseURL, "https://custom.endpoint.com")
 	}
 }
+
+func TestNamedInstances(t *testing.T) {
+	mock := testutil.NewMockServer(t)
+	creds := provider.NewStaticCredential(map[string]string{
+		"azure-east": "east-key",
+		"azure-west": "west-key",
+	})
+	east := New("", WithName("azure-east"), WithBaseURL(mock.Server.URL), WithCredentials(creds),
+		WithModelIDs("gpt-4o", "my-finetune"))
+	west := New("", WithName("azure-west"), WithBaseURL(mock.Server.URL), WithCredentials(creds))
+
+	if east.Name() != "azure-east" || east.Type() != "azureopenai" {
+		t.Fatalf("east = %q/%q", east.Name(), east.Type())
+	}
+
+	reg := provider.NewRegistry()
+	reg.Register(east)
+	reg.Register(west)
+	if reg.Count() != 2 {
+		t.Fatalf("registry count = %d, want 2 instances", reg.Count())
+	}
+
+	models, err := east.Models(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models) != 2 || models[1].ID != "my-finetune" || models[0].Provider != "azure-east" {
+		t.Errorf("east models = %+v", models)
+	}
+
+	_, _ = west.Complete(context.Background(), &provider.CompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []provider.Message{{Role: "user", Content: "Hello"}},
+	})
+	if got := mock.Ctrl.GetLastHeader().Get("api-key"); got != "west-key" {
+		t.Errorf("api-key = %q, want the west instance's key", got)
+	}
+}
@@ -123,7 +123,7 @@ func (c *client) complete(ctx context.Context, req *provider.CompletionRequest)
 		return nil, fmt.Errorf("bedrock: marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/model/%s/converse", c.baseURL, req.Model)
+	url := fmt.Sprintf("%s/model/%s/converse", c.baseURL, escapeModelID(req.Model))
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("bedrock: create request: %w", err)
@@ -164,7 +164,7 @@ func (c *client) completeStream(ctx context.Context, req *provider.CompletionReq
 		return nil, fmt.Errorf("bedrock: marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/model/%s/converse-with-response-stream", c.baseURL, req.Model)
+	url := fmt.Sprintf("%s/model/%s/converse-with-response-stream", c.baseURL, escapeModelID(req.Model))
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("bedrock: create request: %w", err)
@@ -0,0 +1,161 @@
+package bedrock
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+const defaultRegion = "us-east-1"
+
+// awsCredentials is a static AWS credential set and region.
+type awsCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	region          string
+}
+
+// resolveCredentials fills what the caller left empty from the standard
+// AWS chain: the AWS_* environment variables, then the shared credentials
+// and config files for the profile (AWS_PROFILE, or "default"). Keys are
+// taken as a set, never mixed across sources. Only static keys are
+// supported; SSO, assume-role and instance metadata are not.
+func resolveCredentials(c awsCredentials, profile string) awsCredentials {
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	var creds, conf map[string]string
+	loadShared := func() {
+		if creds == nil {
+			creds = readINISection(sharedFile("AWS_SHARED_CREDENTIALS_FILE", "credentials"), profile)
+			section := "profile " + profile
+			if profile == "default" {
+				section = "default"
+			}
+			conf = readINISection(sharedFile("AWS_CONFIG_FILE", "config"), section)
+		}
+	}
+
+	if c.accessKeyID == "" {
+		if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+			c.accessKeyID, c.secretAccessKey = id, secret
+			if c.sessionToken == "" {
+				c.sessionToken = os.Getenv("AWS_SESSION_TOKEN")
+			}
+		} else {
+			loadShared()
+			for _, src := range []map[string]string{creds, conf} {
+				if src["aws_access_key_id"] != "" {
+					c.accessKeyID = src["aws_access_key_id"]
+					c.secretAccessKey = src["aws_secret_access_key"]
+					if c.sessionToken == "" {
+						c.sessionToken = src["aws_session_token"]
+					}
+					break
+				}
+			}
+		}
+	}
+
+	if c.region == "" {
+		c.region = os.Getenv("AWS_REGION")
+	}
+	if c.region == "" {
+		c.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if c.region == "" {
+		loadShared()
+		c.region = conf["region"]
+	}
+	if c.region == "" {
+		c.region = defaultRegion
+	}
+	return c
+}
+
+// sharedFile returns the path of a shared AWS file, honouring its
+// environment override.
+func sharedFile(envVar, name string) string {
+	if path := os.Getenv(envVar); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".aws", name)
+}
+
+// readINISection returns the keys of one section of an INI file, or an
+// empty map if the file or section is missing.
+func readINISection(path, section string) map[string]string {
+	out := map[string]string{}
+	if path == "" {
+		return out
+	}
+	f, err := os.Open(path) //nolint:gosec // G304 -- path is the user's own AWS config
+	if err != nil {
+		return out
+	}
+	defer func() { _ = f.Close() }()
+
+	in := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case line[0] == '[' && line[len(line)-1] == ']':
+			in = strings.TrimSpace(line[1:len(line)-1]) == section
+		case in:
+			if key, value, ok := strings.Cut(line, "="); ok {
+				out[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return out
+}
+
+// signWithCredential returns a CredentialApplier that signs requests with a
+// credential of the form "ACCESS_KEY_ID:SECRET_ACCESS_KEY[:SESSION_TOKEN]",
+// replacing the signature from the static keys.
+func signWithCredential(region string) provider.CredentialApplier {
+	return func(req *http.Request, credential string) error {
+		parts := strings.SplitN(credential, ":", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return errors.New("bedrock: credential must be ACCESS_KEY_ID:SECRET_ACCESS_KEY[:SESSION_TOKEN]")
+		}
+		signer := newSigV4Signer(parts[0], parts[1], region)
+		if len(parts) == 3 {
+			signer.sessionToken = parts[2]
+		}
+
+		var body []byte
+		if req.GetBody != nil {
+			rc, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			body, err = io.ReadAll(rc)
+			_ = rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		req.Header.Del("x-amz-security-token")
+		return signer.Sign(req, body, time.Now())
+	}
+}
@@ -0,0 +1,132 @@
+package bedrock
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// Titan Text Embeddings types.
+
+type titanEmbedRequest struct {
+	InputText  string `json:"inputText"`
+	Dimensions int    `json:"dimensions,omitempty"` // v2 only: 256, 512 or 1024
+	Normalize  *bool  `json:"normalize,omitempty"`  // v2 only
+}
+
+type titanEmbedResponse struct {
+	Embedding           []float64 `json:"embedding"`
+	InputTextTokenCount int       `json:"inputTextTokenCount"`
+}
+
+// Cohere Embed on Bedrock types.
+
+type cohereEmbedRequest struct {
+	Texts     []string `json:"texts"`
+	InputType string   `json:"input_type"`
+	Truncate  string   `json:"truncate,omitempty"`
+}
+
+type cohereEmbedResponse struct {
+	ID         string      `json:"id"`
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+// Embed creates embeddings with Titan Text Embeddings or Cohere Embed
+// through InvokeModel.
+func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	return p.client.embed(ctx, req)
+}
+
+// EmbeddingLimits reports per-call caps: Titan embeds one text per call,
+// Cohere up to 96.
+func (p *Provider) EmbeddingLimits(model string) provider.EmbeddingLimits {
+	switch base := baseModelID(model); {
+	case strings.HasPrefix(base, "amazon.titan-embed"):
+		return provider.EmbeddingLimits{MaxInputs: 1}
+	case strings.HasPrefix(base, "cohere.embed"):
+		return provider.EmbeddingLimits{MaxInputs: 96}
+	default:
+		return provider.EmbeddingLimits{}
+	}
+}
+
+func (c *client) embed(ctx context.Context, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	switch base := baseModelID(req.Model); {
+	case strings.HasPrefix(base, "amazon.titan-embed"):
+		return c.embedTitan(ctx, req, strings.HasPrefix(base, "amazon.titan-embed-text-v2"))
+	case strings.HasPrefix(base, "cohere.embed"):
+		return c.embedCohere(ctx, req)
+	default:
+		return nil, fmt.Errorf("bedrock: model %q does not support embeddings: %w", req.Model, provider.ErrNotSupported)
+	}
+}
+
+// embedTitan invokes the model once per input, since Titan embeds a single
+// text per call.
+func (c *client) embedTitan(ctx context.Context, req *provider.EmbeddingRequest, v2 bool) (*provider.EmbeddingResponse, error) {
+	out := &provider.EmbeddingResponse{
+		Provider:   "bedrock",
+		Model:      req.Model,
+		Embeddings: make([][]float64, 0, len(req.Input)),
+	}
+	for _, text := range req.Input {
+		payload := titanEmbedRequest{InputText: text}
+		if v2 {
+			normalize := true
+			payload.Normalize = &normalize
+			payload.Dimensions = req.Dimensions
+		}
+
+		var resp titanEmbedResponse
+		if _, err := c.invokeModel(ctx, req.Model, payload, &resp); err != nil {
+			return nil, err
+		}
+		out.Embeddings = append(out.Embeddings, resp.Embedding)
+		out.Usage.PromptTokens += resp.InputTextTokenCount
+		out.Usage.TotalTokens += resp.InputTextTokenCount
+	}
+	return out, nil
+}
+
+func (c *client) embedCohere(ctx context.Context, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	payload := cohereEmbedRequest{
+		Texts:     req.Input,
+		InputType: cohereInputType(req.InputType),
+		Truncate:  strings.ToUpper(req.Truncate),
+	}
+
+	var resp cohereEmbedResponse
+	header, err := c.invokeModel(ctx, req.Model, payload, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cohere's body carries no usage; Bedrock reports it in a header.
+	tokens, _ := strconv.Atoi(header.Get("X-Amzn-Bedrock-Input-Token-Count")) //nolint:errcheck // absent header means unknown usage
+	return &provider.EmbeddingResponse{
+		Provider:   "bedrock",
+		Model:      req.Model,
+		Embeddings: resp.Embeddings,
+		Usage:      provider.Usage{PromptTokens: tokens, TotalTokens: tokens},
+	}, nil
+}
+
+// cohereInputType maps a unified input type to Cohere's, defaulting to
+// documents as the API requires one.
+func cohereInputType(t string) string {
+	switch t {
+	case provider.EmbeddingInputQuery:
+		return "search_query"
+	case provider.EmbeddingInputClassification, provider.EmbeddingInputClustering:
+		return t
+	default:
+		return "search_document"
+	}
+}
+
+// Compile-time check.
+var _ provider.EmbeddingLimiter = (*Provider)(nil)
@@ -0,0 +1,210 @@
+package bedrock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// Titan Image Generator types.
+
+type titanImageRequest struct {
+	TaskType              string                `json:"taskType"`
+	TextToImageParams     titanTextToImage      `json:"textToImageParams"`
+	ImageGenerationConfig titanImageGenerateCfg `json:"imageGenerationConfig"`
+}
+
+type titanTextToImage struct {
+	Text         string `json:"text"`
+	NegativeText string `json:"negativeText,omitempty"`
+}
+
+type titanImageGenerateCfg struct {
+	NumberOfImages int    `json:"numberOfImages"`
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	Quality        string `json:"quality,omitempty"` // "standard" or "premium"
+	Seed           *int64 `json:"seed,omitempty"`
+}
+
+type titanImageResponse struct {
+	Images []string `json:"images"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Stable Diffusion XL types.
+
+type sdxlRequest struct {
+	TextPrompts []sdxlPrompt `json:"text_prompts"`
+	Width       int          `json:"width"`
+	Height      int          `json:"height"`
+	Samples     int          `json:"samples"`
+	Seed        *int64       `json:"seed,omitempty"`
+	StylePreset string       `json:"style_preset,omitempty"`
+}
+
+type sdxlPrompt struct {
+	Text   string  `json:"text"`
+	Weight float64 `json:"weight"`
+}
+
+type sdxlResponse struct {
+	Artifacts []struct {
+		Base64       string `json:"base64"`
+		FinishReason string `json:"finishReason"`
+	} `json:"artifacts"`
+}
+
+// GenerateImage creates images with Titan Image Generator or Stable
+// Diffusion XL through InvokeModel.
+func (p *Provider) GenerateImage(ctx context.Context, req *provider.ImageRequest) (*provider.ImageResponse, error) {
+	return p.client.generateImage(ctx, req)
+}
+
+func (c *client) generateImage(ctx context.Context, req *provider.ImageRequest) (*provider.ImageResponse, error) {
+	var (
+		images []string
+		err    error
+	)
+	switch base := baseModelID(req.Model); {
+	case strings.HasPrefix(base, "amazon.titan-image"):
+		images, err = c.generateTitanImage(ctx, req)
+	case strings.HasPrefix(base, "stability."):
+		images, err = c.generateSDXLImage(ctx, req)
+	default:
+		return nil, fmt.Errorf("bedrock: model %q does not support image generation: %w", req.Model, provider.ErrNotSupported)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]provider.Image, len(images))
+	for i, b64 := range images {
+		data[i] = provider.Image{B64JSON: b64}
+	}
+	return &provider.ImageResponse{
+		Created:  time.Now().Unix(),
+		Provider: "bedrock",
+		Model:    req.Model,
+		Data:     data,
+		Cost:     imageCost(req.Model, len(data)),
+	}, nil
+}
+
+func (c *client) generateTitanImage(ctx context.Context, req *provider.ImageRequest) ([]string, error) {
+	w, h := req.Dimensions()
+	quality := "standard"
+	if req.Quality == "hd" || req.Quality == "high" || req.Quality == "premium" {
+		quality = "premium"
+	}
+	payload := titanImageRequest{
+		TaskType: "TEXT_IMAGE",
+		TextToImageParams: titanTextToImage{
+			Text:         req.Prompt,
+			NegativeText: req.NegativePrompt,
+		},
+		ImageGenerationConfig: titanImageGenerateCfg{
+			NumberOfImages: req.Count(),
+			Width:          w,
+			Height:         h,
+			Quality:        quality,
+			Seed:           req.Seed,
+		},
+	}
+
+	var resp titanImageResponse
+	if _, err := c.invokeModel(ctx, req.Model, payload, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("bedrock: image generation failed: %s", resp.Error)
+	}
+	return resp.Images, nil
+}
+
+// generateSDXLImage invokes the model once per image, since Bedrock's
+// SDXL only returns a single sample per call.
+func (c *client) generateSDXLImage(ctx context.Context, req *provider.ImageRequest) ([]string, error) {
+	w, h := req.Dimensions()
+	payload := sdxlRequest{
+		TextPrompts: []sdxlPrompt{{Text: req.Prompt, Weight: 1}},
+		Width:       w,
+		Height:      h,
+		Samples:     1,
+		Seed:        req.Seed,
+		StylePreset: req.Style,
+	}
+	if req.NegativePrompt != "" {
+		payload.TextPrompts = append(payload.TextPrompts, sdxlPrompt{Text: req.NegativePrompt, Weight: -1})
+	}
+
+	images := make([]string, 0, req.Count())
+	for range req.Count() {
+		var resp sdxlResponse
+		if _, err := c.invokeModel(ctx, req.Model, payload, &resp); err != nil {
+			return nil, err
+		}
+		for _, a := range resp.Artifacts {
+			if a.FinishReason != "" && a.FinishReason != "SUCCESS" {
+				return nil, fmt.Errorf("bedrock: image generation failed: %s", a.FinishReason)
+			}
+			images = append(images, a.Base64)
+		}
+	}
+	return images, nil
+}
+
+// invokeModel calls the InvokeModel API with a model-native JSON body. It
+// returns the response headers, which carry token counts for models whose
+// body does not.
+func (c *client) invokeModel(ctx context.Context, model string, payload, out any) (http.Header, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("bedrock: marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/model/%s/invoke", c.baseURL, escapeModelID(model))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("bedrock: create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	if signErr := c.signer.Sign(httpReq, body, time.Now()); signErr != nil {
+		return nil, fmt.Errorf("bedrock: sign request: %w", signErr)
+	}
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("bedrock: request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		return nil, fmt.Errorf("bedrock: API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	if err := json.NewDecoder(httpResp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("bedrock: decode response: %w", err)
+	}
+	return httpResp.Header, nil
+}
+
+func imageCost(model string, n int) float64 {
+	if m, ok := lookupModel(model); ok {
+		return m.Pricing.PerImage * float64(n)
+	}
+	return 0
+}
+
+// Compile-time check.
+var _ provider.ImageProvider = (*Provider)(nil)
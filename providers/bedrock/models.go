@@ -1,6 +1,10 @@
 package bedrock
 
-import "github.com/xraph/nexus/provider"
+import (
+	"strings"
+
+	"github.com/xraph/nexus/provider"
+)
 
 // bedrockModels returns the known Bedrock model catalog.
 func bedrockModels() []provider.Model {
@@ -23,5 +27,122 @@ func bedrockModels() []provider.Model {
 			ContextWindow: 8192, MaxOutput: 4096,
 			Pricing: provider.Pricing{InputPerMillion: 0.20, OutputPerMillion: 0.60},
 		},
+		{
+			ID: "amazon.titan-embed-text-v2:0", Provider: "bedrock", Name: "Titan Text Embeddings v2",
+			Capabilities:  provider.Capabilities{Embeddings: true},
+			ContextWindow: 8192,
+			Pricing:       provider.Pricing{EmbeddingPerMillion: 0.02},
+		},
+		{
+			ID: "amazon.titan-embed-text-v1", Provider: "bedrock", Name: "Titan Embeddings G1 - Text",
+			Capabilities:  provider.Capabilities{Embeddings: true},
+			ContextWindow: 8192,
+			Pricing:       provider.Pricing{EmbeddingPerMillion: 0.10},
+		},
+		{
+			ID: "cohere.embed-english-v3", Provider: "bedrock", Name: "Cohere Embed English (Bedrock)",
+			Capabilities:  provider.Capabilities{Embeddings: true},
+			ContextWindow: 512,
+			Pricing:       provider.Pricing{EmbeddingPerMillion: 0.10},
+		},
+		{
+			ID: "cohere.embed-multilingual-v3", Provider: "bedrock", Name: "Cohere Embed Multilingual (Bedrock)",
+			Capabilities:  provider.Capabilities{Embeddings: true},
+			ContextWindow: 512,
+			Pricing:       provider.Pricing{EmbeddingPerMillion: 0.10},
+		},
+		{
+			ID: "amazon.titan-image-generator-v2:0", Provider: "bedrock", Name: "Titan Image Generator v2",
+			Capabilities:  provider.Capabilities{Images: true},
+			ContextWindow: 512, // maximum prompt length
+			Pricing:       provider.Pricing{PerImage: 0.01},
+		},
+		{
+			ID: "amazon.titan-image-generator-v1", Provider: "bedrock", Name: "Titan Image Generator",
+			Capabilities:  provider.Capabilities{Images: true},
+			ContextWindow: 512, // maximum prompt length
+			Pricing:       provider.Pricing{PerImage: 0.01},
+		},
+		{
+			ID: "stability.stable-diffusion-xl-v1", Provider: "bedrock", Name: "Stable Diffusion XL",
+			Capabilities:  provider.Capabilities{Images: true},
+			ContextWindow: 2000, // maximum prompt length
+			Pricing:       provider.Pricing{PerImage: 0.04},
+		},
+	}
+}
+
+// crossRegionModels are the catalog models Bedrock also serves through
+// cross-region inference profiles.
+var crossRegionModels = map[string]bool{
+	"anthropic.claude-3-5-sonnet-20241022-v2:0": true,
+	"meta.llama3-1-70b-instruct-v1:0":           true,
+}
+
+// profileGeographies are the inference-profile ID prefixes, e.g. the "us"
+// in "us.anthropic.claude-3-5-sonnet-20241022-v2:0".
+var profileGeographies = map[string]bool{
+	"us": true, "us-gov": true, "eu": true, "apac": true, "global": true,
+}
+
+// profileGeography returns the inference-profile prefix for models called
+// from region, or "" if the region has none.
+func profileGeography(region string) string {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return "us-gov"
+	case strings.HasPrefix(region, "us-"):
+		return "us"
+	case strings.HasPrefix(region, "eu-"):
+		return "eu"
+	case strings.HasPrefix(region, "ap-"):
+		return "apac"
+	default:
+		return ""
+	}
+}
+
+// baseModelID resolves an inference profile, given as an ID
+// ("us.anthropic...") or an ARN (".../inference-profile/us.anthropic..."),
+// to the foundation model ID. Other IDs are returned unchanged.
+func baseModelID(id string) string {
+	if strings.HasPrefix(id, "arn:") {
+		if i := strings.LastIndexByte(id, '/'); i >= 0 {
+			id = id[i+1:]
+		}
+	}
+	if geo, rest, ok := strings.Cut(id, "."); ok && profileGeographies[geo] {
+		return rest
+	}
+	return id
+}
+
+// lookupModel returns the catalog entry for id, resolving inference
+// profiles to their foundation model.
+func lookupModel(id string) (provider.Model, bool) {
+	base := baseModelID(id)
+	for _, m := range bedrockModels() {
+		if m.ID == base {
+			return m, true
+		}
+	}
+	return provider.Model{}, false
+}
+
+// regionModels returns the catalog plus the cross-region inference
+// profiles callable from region, priced as their foundation models.
+func regionModels(region string) []provider.Model {
+	models := bedrockModels()
+	geo := profileGeography(region)
+	if geo == "" {
+		return models
+	}
+	for _, m := range bedrockModels() {
+		if crossRegionModels[m.ID] {
+			m.ID = geo + "." + m.ID
+			m.Name += " (cross-region)"
+			models = append(models, m)
+		}
 	}
+	return models
 }
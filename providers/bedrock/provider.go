@@ -1,4 +1,7 @@
 // Package bedrock provides an Amazon Bedrock provider implementation for Nexus.
+// Chat uses the Converse API; embeddings and images use InvokeModel.
+// Cross-region inference profile IDs and ARNs are accepted wherever a model
+// ID is.
 package bedrock
 
 import (
@@ -13,11 +16,17 @@ type Provider struct {
 	secretAccessKey string
 	sessionToken    string
 	region          string
+	profile         string
 	baseURL         string
 	client          *client
+
+	creds provider.CredentialProvider
+	inst  provider.Instance
 }
 
-// New creates a new Bedrock provider.
+// New creates a new Bedrock provider. Empty credentials or region are
+// resolved from the standard AWS environment variables and shared
+// config files; see NewFromEnvironment.
 func New(accessKeyID, secretAccessKey, region string, opts ...Option) *Provider {
 	p := &Provider{
 		accessKeyID:     accessKeyID,
@@ -27,26 +36,53 @@ func New(accessKeyID, secretAccessKey, region string, opts ...Option) *Provider
 	for _, opt := range opts {
 		opt(p)
 	}
+	resolved := resolveCredentials(awsCredentials{
+		accessKeyID:     p.accessKeyID,
+		secretAccessKey: p.secretAccessKey,
+		sessionToken:    p.sessionToken,
+		region:          p.region,
+	}, p.profile)
+	p.accessKeyID = resolved.accessKeyID
+	p.secretAccessKey = resolved.secretAccessKey
+	p.sessionToken = resolved.sessionToken
+	p.region = resolved.region
 	p.client = newClient(p.accessKeyID, p.secretAccessKey, p.sessionToken, p.region, p.baseURL)
+	if p.creds != nil {
+		p.client.http.Transport = provider.NewCredentialTransport(nil, p.Name(), p.creds, signWithCredential(p.region))
+	}
 	return p
 }
 
+// NewFromEnvironment creates a Bedrock provider whose credentials and
+// region come entirely from the AWS environment variables (AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN, AWS_REGION) or, failing those,
+// the shared credentials and config files.
+func NewFromEnvironment(opts ...Option) *Provider {
+	return New("", "", "", opts...)
+}
+
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "bedrock" }
+func (p *Provider) Name() string { return p.inst.NameOr("bedrock") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "bedrock" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
 	return provider.Capabilities{
-		Chat:      true,
-		Streaming: true,
-		Tools:     true,
-		JSON:      true,
+		Chat:       true,
+		Streaming:  true,
+		Embeddings: true,
+		Images:     true,
+		Tools:      true,
+		JSON:       true,
 	}
 }
 
-// Models returns the list of available models.
+// Models returns the list of available models, including the cross-region
+// inference profiles callable from the configured region.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return bedrockModels(), nil
+	return p.inst.FilterModels(p.Name(), regionModels(p.region)), nil
 }
 
 // Complete sends a chat completion request.
@@ -59,11 +95,6 @@ func (p *Provider) CompleteStream(ctx context.Context, req *provider.CompletionR
 	return p.client.completeStream(ctx, req)
 }
 
-// Embed sends an embedding request -- Bedrock via Converse API does not support embeddings.
-func (p *Provider) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
-	return nil, provider.ErrNotSupported
-}
-
 // Healthy returns true if the provider is reachable.
 func (p *Provider) Healthy(ctx context.Context) bool {
 	return p.client.ping(ctx) == nil
@@ -77,10 +108,35 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
+// WithProfile selects the shared config profile used to resolve missing
+// credentials, overriding AWS_PROFILE.
+func WithProfile(name string) Option {
+	return func(p *Provider) { p.profile = name }
+}
+
 // WithSessionToken sets an AWS session token for temporary credentials.
 func WithSessionToken(token string) Option {
 	return func(p *Provider) { p.sessionToken = token }
 }
 
+// WithCredentials resolves AWS keys from cp on every request, formatted as
+// "ACCESS_KEY_ID:SECRET_ACCESS_KEY[:SESSION_TOKEN]". Each request is
+// re-signed with the current keys, so rotated STS credentials are used as
+// soon as the source returns them.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
 // Compile-time check.
 var _ provider.Provider = (*Provider)(nil)
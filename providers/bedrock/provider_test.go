@@ -3,10 +3,14 @@ package bedrock
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/xraph/nexus/provider"
@@ -88,8 +92,8 @@ func TestCapabilities(t *testing.T) {
 	if !caps.JSON {
 		t.Error("expected JSON capability")
 	}
-	if caps.Embeddings {
-		t.Error("expected Embeddings to be false")
+	if !caps.Embeddings {
+		t.Error("expected Embeddings capability")
 	}
 }
 
@@ -193,9 +197,254 @@ func TestHealthy(t *testing.T) {
 	}
 }
 
-func TestEmbedNotSupported(t *testing.T) {
+func TestGenerateImage_Titan(t *testing.T) {
+	var got titanImageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/model/amazon.titan-image-generator-v1/invoke" {
+			t.Errorf("path=%q", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") == "" {
+			t.Error("expected signed request")
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"images": []string{"aW1n", "aW1nMg=="}})
+	}))
+	t.Cleanup(server.Close)
+
+	p := New("key", "secret", "us-east-1", WithBaseURL(server.URL))
+	resp, err := p.GenerateImage(context.Background(), &provider.ImageRequest{
+		Model:          "amazon.titan-image-generator-v1",
+		Prompt:         "a fox",
+		NegativePrompt: "blurry",
+		N:              2,
+		Size:           "512x512",
+		Quality:        "hd",
+	})
+	if err != nil {
+		t.Fatalf("GenerateImage() error: %v", err)
+	}
+	if got.TaskType != "TEXT_IMAGE" || got.TextToImageParams.NegativeText != "blurry" {
+		t.Errorf("request=%+v", got)
+	}
+	cfg := got.ImageGenerationConfig
+	if cfg.NumberOfImages != 2 || cfg.Width != 512 || cfg.Height != 512 || cfg.Quality != "premium" {
+		t.Errorf("imageGenerationConfig=%+v", cfg)
+	}
+	if len(resp.Data) != 2 || resp.Data[1].B64JSON != "aW1nMg==" {
+		t.Errorf("Data=%+v", resp.Data)
+	}
+	if resp.Cost != 0.02 {
+		t.Errorf("Cost=%v, want 0.02", resp.Cost)
+	}
+}
+
+func TestGenerateImage_SDXL(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"artifacts": []map[string]any{{"base64": fmt.Sprintf("img%d", calls), "finishReason": "SUCCESS"}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	p := New("key", "secret", "us-east-1", WithBaseURL(server.URL))
+	resp, err := p.GenerateImage(context.Background(), &provider.ImageRequest{
+		Model:  "stability.stable-diffusion-xl-v1",
+		Prompt: "a fox",
+		N:      2,
+	})
+	if err != nil {
+		t.Fatalf("GenerateImage() error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls=%d, want 2", calls)
+	}
+	if len(resp.Data) != 2 || resp.Data[1].B64JSON != "img2" {
+		t.Errorf("Data=%+v", resp.Data)
+	}
+}
+
+func TestGenerateImage_UnsupportedModel(t *testing.T) {
 	p := New("key", "secret", "us-east-1")
-	providertest.TestProviderEmbedNotSupported(t, p)
+	_, err := p.GenerateImage(context.Background(), &provider.ImageRequest{
+		Model:  "anthropic.claude-3-5-sonnet-20241022-v2:0",
+		Prompt: "a fox",
+	})
+	if !errors.Is(err, provider.ErrNotSupported) {
+		t.Errorf("err=%v, want ErrNotSupported", err)
+	}
+}
+
+func TestEmbed_Titan(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		var got titanEmbedRequest
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if got.Dimensions != 256 || got.Normalize == nil || !*got.Normalize {
+			t.Errorf("request=%+v", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(titanEmbedResponse{Embedding: []float64{float64(len(got.InputText))}, InputTextTokenCount: 2})
+	}))
+	t.Cleanup(server.Close)
+
+	p := New("key", "secret", "us-east-1", WithBaseURL(server.URL))
+	resp, err := p.Embed(context.Background(), &provider.EmbeddingRequest{
+		Model:      "amazon.titan-embed-text-v2:0",
+		Input:      []string{"a", "bbb"},
+		Dimensions: 256,
+	})
+	if err != nil {
+		t.Fatalf("Embed() error: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/model/amazon.titan-embed-text-v2%3A0/invoke" {
+		t.Errorf("paths=%v", paths)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[1][0] != 3 {
+		t.Errorf("Embeddings=%v", resp.Embeddings)
+	}
+	if resp.Usage.PromptTokens != 4 {
+		t.Errorf("PromptTokens=%d, want 4", resp.Usage.PromptTokens)
+	}
+	if got := p.EmbeddingLimits("amazon.titan-embed-text-v2:0").MaxInputs; got != 1 {
+		t.Errorf("MaxInputs=%d, want 1", got)
+	}
+}
+
+func TestEmbed_Cohere(t *testing.T) {
+	var got cohereEmbedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/model/cohere.embed-english-v3/invoke" {
+			t.Errorf("path=%q", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Amzn-Bedrock-Input-Token-Count", "7")
+		_ = json.NewEncoder(w).Encode(cohereEmbedResponse{ID: "e1", Embeddings: [][]float64{{0.1}, {0.2}}})
+	}))
+	t.Cleanup(server.Close)
+
+	p := New("key", "secret", "us-east-1", WithBaseURL(server.URL))
+	resp, err := p.Embed(context.Background(), &provider.EmbeddingRequest{
+		Model:     "cohere.embed-english-v3",
+		Input:     []string{"q1", "q2"},
+		InputType: provider.EmbeddingInputQuery,
+		Truncate:  "end",
+	})
+	if err != nil {
+		t.Fatalf("Embed() error: %v", err)
+	}
+	if len(got.Texts) != 2 || got.InputType != "search_query" || got.Truncate != "END" {
+		t.Errorf("request=%+v", got)
+	}
+	if len(resp.Embeddings) != 2 || resp.Usage.PromptTokens != 7 {
+		t.Errorf("response=%+v", resp)
+	}
+}
+
+func TestEmbed_UnsupportedModel(t *testing.T) {
+	p := New("key", "secret", "us-east-1")
+	_, err := p.Embed(context.Background(), &provider.EmbeddingRequest{
+		Model: "anthropic.claude-3-5-sonnet-20241022-v2:0",
+		Input: []string{"hi"},
+	})
+	if !errors.Is(err, provider.ErrNotSupported) {
+		t.Errorf("err=%v, want ErrNotSupported", err)
+	}
+}
+
+func TestInferenceProfiles(t *testing.T) {
+	tests := map[string]string{
+		"us.anthropic.claude-3-5-sonnet-20241022-v2:0":                                                "anthropic.claude-3-5-sonnet-20241022-v2:0",
+		"apac.amazon.titan-embed-text-v2:0":                                                           "amazon.titan-embed-text-v2:0",
+		"arn:aws:bedrock:eu-west-1:123456789012:inference-profile/eu.meta.llama3-1-70b-instruct-v1:0": "meta.llama3-1-70b-instruct-v1:0",
+		"amazon.titan-embed-text-v1":                                                                  "amazon.titan-embed-text-v1",
+	}
+	for in, want := range tests {
+		if got := baseModelID(in); got != want {
+			t.Errorf("baseModelID(%q)=%q, want %q", in, got, want)
+		}
+	}
+
+	p := New("key", "secret", "eu-central-1")
+	models, err := p.Models(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, m := range models {
+		if m.ID == "eu.anthropic.claude-3-5-sonnet-20241022-v2:0" {
+			found = m.Pricing.InputPerMillion == 3.00 && m.ContextWindow == 200000
+		}
+	}
+	if !found {
+		t.Error("expected eu cross-region profile priced as the foundation model")
+	}
+
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		_ = json.NewEncoder(w).Encode(map[string]any{"images": []string{"aW1n"}})
+	}))
+	t.Cleanup(server.Close)
+	p = New("key", "secret", "us-east-1", WithBaseURL(server.URL))
+	arn := "arn:aws:bedrock:us-east-1:123456789012:inference-profile/us.amazon.titan-image-generator-v1"
+	resp, err := p.GenerateImage(context.Background(), &provider.ImageRequest{Model: arn, Prompt: "a fox"})
+	if err != nil {
+		t.Fatalf("GenerateImage() error: %v", err)
+	}
+	if want := "/model/" + escapeModelID(arn) + "/invoke"; path != want {
+		t.Errorf("path=%q, want %q", path, want)
+	}
+	if resp.Cost != 0.01 {
+		t.Errorf("Cost=%v, want 0.01", resp.Cost)
+	}
+}
+
+func TestCanonicalURIDoubleEncodes(t *testing.T) {
+	req, err := http.NewRequest("POST", "https://bedrock-runtime.us-east-1.amazonaws.com/model/"+escapeModelID("a.b-v2:0")+"/invoke", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := canonicalURI(req.URL), "/model/a.b-v2%253A0/invoke"; got != want {
+		t.Errorf("canonicalURI=%q, want %q", got, want)
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	dir := t.TempDir()
+	credsFile := filepath.Join(dir, "credentials")
+	configFile := filepath.Join(dir, "config")
+	_ = os.WriteFile(credsFile, []byte("[default]\naws_access_key_id = DEFAULTKEY\naws_secret_access_key = defaultsecret\n\n[work]\naws_access_key_id=WORKKEY\naws_secret_access_key=worksecret\naws_session_token=worktoken\n"), 0o600)
+	_ = os.WriteFile(configFile, []byte("[profile work]\nregion = eu-west-1\n"), 0o600)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credsFile)
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	for _, k := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_REGION", "AWS_DEFAULT_REGION", "AWS_PROFILE"} {
+		t.Setenv(k, "")
+	}
+
+	p := NewFromEnvironment(WithProfile("work"))
+	if p.accessKeyID != "WORKKEY" || p.secretAccessKey != "worksecret" || p.sessionToken != "worktoken" || p.region != "eu-west-1" {
+		t.Errorf("profile work: %q %q %q %q", p.accessKeyID, p.secretAccessKey, p.sessionToken, p.region)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "ENVKEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecret")
+	t.Setenv("AWS_REGION", "ap-south-1")
+	p = NewFromEnvironment()
+	if p.accessKeyID != "ENVKEY" || p.secretAccessKey != "envsecret" || p.region != "ap-south-1" {
+		t.Errorf("env: %q %q %q", p.accessKeyID, p.secretAccessKey, p.region)
+	}
+
+	// Explicit keys are never mixed with resolved ones.
+	p = New("key", "secret", "us-west-2")
+	if p.accessKeyID != "key" || p.sessionToken != "" || p.region != "us-west-2" {
+		t.Errorf("explicit: %q %q %q", p.accessKeyID, p.sessionToken, p.region)
+	}
 }
 
 func TestConformance(t *testing.T) {
@@ -228,3 +477,34 @@ func TestWithSessionToken(t *testing.T) {
 		t.Errorf("sessionToken=%q, want %q", p.sessionToken, "session-tok")
 	}
 }
+
+func TestWithCredentials_ResignsRequest(t *testing.T) {
+	var auth, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		token = r.Header.Get("x-amz-security-token")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(converseResponse{
+			Output:     converseOutput{Message: &converseMessage{Role: "assistant", Content: []contentBlock{{Text: "ok"}}}},
+			StopReason: "end_turn",
+		})
+	}))
+	defer server.Close()
+
+	creds := provider.NewStaticCredential(map[string]string{"bedrock": "ASIAROTATED:rotated-secret:rotated-token"})
+	p := New("AKIASTATIC", "static-secret", "us-east-1", WithSessionToken("static-token"),
+		WithBaseURL(server.URL), WithCredentials(creds))
+
+	if _, err := p.Complete(context.Background(), &provider.CompletionRequest{
+		Model:    "anthropic.claude-3-5-sonnet-20241022-v2:0",
+		Messages: []provider.Message{{Role: "user", Content: "Hello"}},
+	}); err != nil {
+		t.Fatalf("Complete() error: %v", err)
+	}
+	if !strings.Contains(auth, "Credential=ASIAROTATED/") {
+		t.Errorf("Authorization = %q, want rotated access key", auth)
+	}
+	if token != "rotated-token" {
+		t.Errorf("x-amz-security-token = %q, want rotated-token", token)
+	}
+}
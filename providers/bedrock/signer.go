@@ -143,14 +143,42 @@ func buildCanonicalHeaders(req *http.Request) (canonicalHeaders, signedHeaders s
 	return strings.Join(canonicalParts, ""), strings.Join(signedParts, ";")
 }
 
-// canonicalURI returns the URI-encoded path component. If the path is empty,
-// "/" is used.
+// canonicalURI returns the request path with each segment URI-encoded
+// again, as SigV4 requires for every service but S3. An empty path is "/".
 func canonicalURI(u *url.URL) string {
 	path := u.EscapedPath()
 	if path == "" {
 		return "/"
 	}
-	return path
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		segments[i] = uriEncode(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// escapeModelID encodes a model ID, inference-profile ID or ARN for use as
+// a path segment, the way the AWS SDKs do.
+func escapeModelID(id string) string {
+	return uriEncode(id)
+}
+
+// uriEncode percent-encodes every byte of s outside the RFC 3986
+// unreserved set, per the SigV4 rules.
+func uriEncode(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&0xF])
+	}
+	return b.String()
 }
 
 // canonicalQueryString returns the sorted, URI-encoded query string.
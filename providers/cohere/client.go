@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/xraph/nexus/provider"
@@ -103,9 +104,11 @@ type cohereUsage struct {
 
 // Embed types.
 type cohereEmbedRequest struct {
-	Model     string   `json:"model"`
-	Texts     []string `json:"texts"`
-	InputType string   `json:"input_type"`
+	Model           string   `json:"model"`
+	Texts           []string `json:"texts"`
+	InputType       string   `json:"input_type"`
+	OutputDimension int      `json:"output_dimension,omitempty"`
+	Truncate        string   `json:"truncate,omitempty"`
 }
 
 type cohereEmbedResponse struct {
@@ -185,9 +188,11 @@ func (c *client) completeStream(ctx context.Context, req *provider.CompletionReq
 
 func (c *client) embed(ctx context.Context, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
 	embedReq := cohereEmbedRequest{
-		Model:     req.Model,
-		Texts:     req.Input,
-		InputType: "search_document",
+		Model:           req.Model,
+		Texts:           req.Input,
+		InputType:       cohereInputType(req.InputType),
+		OutputDimension: req.Dimensions,
+		Truncate:        strings.ToUpper(req.Truncate),
 	}
 
 	body, err := json.Marshal(embedReq)
@@ -364,3 +369,16 @@ func mapCohereFinishReason(reason string) string {
 		return reason
 	}
 }
+
+// cohereInputType maps a unified input type to Cohere's, defaulting to
+// documents as the API requires one.
+func cohereInputType(t string) string {
+	switch t {
+	case provider.EmbeddingInputQuery:
+		return "search_query"
+	case provider.EmbeddingInputClassification, provider.EmbeddingInputClustering:
+		return t
+	default:
+		return "search_document"
+	}
+}
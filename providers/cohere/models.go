@@ -35,5 +35,23 @@ func cohereModels() []provider.Model {
 			ContextWindow: 512,
 			Pricing:       provider.Pricing{EmbeddingPerMillion: 0.10},
 		},
+		{
+			ID: "rerank-v3.5", Provider: "cohere", Name: "Rerank v3.5",
+			Capabilities:  provider.Capabilities{Rerank: true},
+			ContextWindow: 4096,
+			Pricing:       provider.Pricing{PerSearch: 0.002},
+		},
+		{
+			ID: "rerank-english-v3.0", Provider: "cohere", Name: "Rerank English v3.0",
+			Capabilities:  provider.Capabilities{Rerank: true},
+			ContextWindow: 4096,
+			Pricing:       provider.Pricing{PerSearch: 0.002},
+		},
+		{
+			ID: "rerank-multilingual-v3.0", Provider: "cohere", Name: "Rerank Multilingual v3.0",
+			Capabilities:  provider.Capabilities{Rerank: true},
+			ContextWindow: 4096,
+			Pricing:       provider.Pricing{PerSearch: 0.002},
+		},
 	}
 }
@@ -1,5 +1,6 @@
 // Package cohere provides a Cohere provider implementation for Nexus.
-// Cohere uses its own v2 chat API format with native embedding support.
+// Cohere uses its own v2 chat API format with native embedding and rerank
+// support.
 package cohere
 
 import (
@@ -15,6 +16,9 @@ type Provider struct {
 	apiKey  string
 	baseURL string
 	client  *client
+
+	creds provider.CredentialProvider
+	inst  provider.Instance
 }
 
 // New creates a new Cohere provider.
@@ -27,11 +31,17 @@ func New(apiKey string, opts ...Option) *Provider {
 		opt(p)
 	}
 	p.client = newClient(p.apiKey, p.baseURL)
+	if p.creds != nil {
+		p.client.http.Transport = provider.NewCredentialTransport(nil, p.Name(), p.creds, provider.BearerAuth)
+	}
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "cohere" }
+func (p *Provider) Name() string { return p.inst.NameOr("cohere") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "cohere" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
@@ -40,13 +50,14 @@ func (p *Provider) Capabilities() provider.Capabilities {
 		Streaming:  true,
 		Embeddings: true,
 		Tools:      true,
+		Rerank:     true,
 		JSON:       true,
 	}
 }
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return cohereModels(), nil
+	return p.inst.FilterModels(p.Name(), cohereModels()), nil
 }
 
 // Complete sends a chat completion request.
@@ -64,6 +75,11 @@ func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*
 	return p.client.embed(ctx, req)
 }
 
+// EmbeddingLimits reports Cohere's per-call cap of 96 texts.
+func (p *Provider) EmbeddingLimits(_ string) provider.EmbeddingLimits {
+	return provider.EmbeddingLimits{MaxInputs: 96}
+}
+
 // Healthy returns true if the provider is reachable.
 func (p *Provider) Healthy(ctx context.Context) bool {
 	return p.client.ping(ctx) == nil
@@ -77,5 +93,24 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
-// Compile-time check.
-var _ provider.Provider = (*Provider)(nil)
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
+// Compile-time checks.
+var (
+	_ provider.Provider         = (*Provider)(nil)
+	_ provider.EmbeddingLimiter = (*Provider)(nil)
+)
@@ -2,6 +2,10 @@ package cohere
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/xraph/nexus/provider"
@@ -34,6 +38,9 @@ func TestCapabilities(t *testing.T) {
 	if !caps.JSON {
 		t.Error("expected JSON capability")
 	}
+	if !caps.Rerank {
+		t.Error("expected Rerank capability")
+	}
 }
 
 func TestModels(t *testing.T) {
@@ -108,6 +115,38 @@ func TestEmbed(t *testing.T) {
 	}
 }
 
+func TestRerank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/rerank" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["query"] != "capital of France" || body["top_n"] != float64(1) {
+			t.Errorf("body = %v", body)
+		}
+		_, _ = io.WriteString(w, `{"id":"rr-1","results":[{"index":1,"relevance_score":0.98}],"meta":{"billed_units":{"search_units":1}}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	p := New("test-key", WithBaseURL(server.URL))
+	resp, err := p.Rerank(context.Background(), &provider.RerankRequest{
+		Model:     "rerank-v3.5",
+		Query:     "capital of France",
+		Documents: []string{"Berlin is in Germany", "Paris is the capital of France"},
+		TopN:      1,
+	})
+	if err != nil {
+		t.Fatalf("Rerank() error: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Index != 1 || resp.Results[0].RelevanceScore != 0.98 {
+		t.Errorf("results = %+v", resp.Results)
+	}
+	if resp.SearchUnits != 1 || resp.Cost != 0.002 {
+		t.Errorf("search units = %d, cost = %v, want 1 and 0.002", resp.SearchUnits, resp.Cost)
+	}
+}
+
 func TestHealthy(t *testing.T) {
 	mock := testutil.NewMockServer(t)
 	p := New("test-key", WithBaseURL(mock.Server.URL))
@@ -128,3 +167,29 @@ func TestConformance(t *testing.T) {
 	p := New("test-key", WithBaseURL(mock.Server.URL))
 	providertest.TestProviderContract(t, p)
 }
+
+func TestEmbedOptions(t *testing.T) {
+	var got cohereEmbedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(`{"id":"emb-1","embeddings":[[0.1]]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	p := New("test-key", WithBaseURL(server.URL))
+	_, err := p.Embed(context.Background(), &provider.EmbeddingRequest{
+		Model:      "embed-v4.0",
+		Input:      []string{"what is nexus?"},
+		InputType:  provider.EmbeddingInputQuery,
+		Dimensions: 512,
+		Truncate:   "end",
+	})
+	if err != nil {
+		t.Fatalf("Embed() error: %v", err)
+	}
+	if got.InputType != "search_query" || got.OutputDimension != 512 || got.Truncate != "END" {
+		t.Errorf("request = %+v", got)
+	}
+}
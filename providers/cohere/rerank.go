@@ -0,0 +1,97 @@
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+)
+
+type cohereRerankRequest struct {
+	Model           string   `json:"model"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	TopN            int      `json:"top_n,omitempty"`
+	MaxTokensPerDoc int      `json:"max_tokens_per_doc,omitempty"`
+}
+
+type cohereRerankResponse struct {
+	ID      string `json:"id"`
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+	Meta struct {
+		BilledUnits struct {
+			SearchUnits int `json:"search_units"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
+
+// Rerank scores documents with the v2 rerank API. Cohere bills per search
+// unit: one query over up to 100 documents.
+func (p *Provider) Rerank(ctx context.Context, req *provider.RerankRequest) (*provider.RerankResponse, error) {
+	return p.client.rerank(ctx, req)
+}
+
+func (c *client) rerank(ctx context.Context, req *provider.RerankRequest) (*provider.RerankResponse, error) {
+	body, err := json.Marshal(cohereRerankRequest{
+		Model:           req.Model,
+		Query:           req.Query,
+		Documents:       req.Documents,
+		TopN:            req.TopN,
+		MaxTokensPerDoc: req.MaxTokensPerDoc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cohere: marshal rerank request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v2/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("cohere: create rerank request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("cohere: rerank request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		return nil, fmt.Errorf("cohere: rerank API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var rerankResp cohereRerankResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&rerankResp); err != nil {
+		return nil, fmt.Errorf("cohere: decode rerank response: %w", err)
+	}
+
+	results := make([]provider.RerankResult, len(rerankResp.Results))
+	for i, r := range rerankResp.Results {
+		results[i] = provider.RerankResult{Index: r.Index, RelevanceScore: r.RelevanceScore}
+	}
+	units := rerankResp.Meta.BilledUnits.SearchUnits
+	resp := &provider.RerankResponse{
+		ID:          rerankResp.ID,
+		Provider:    "cohere",
+		Model:       req.Model,
+		Results:     results,
+		SearchUnits: units,
+	}
+	for _, m := range cohereModels() {
+		if m.ID == req.Model {
+			resp.Cost = m.Pricing.RerankCost(units, 0)
+			break
+		}
+	}
+	return resp, nil
+}
+
+// Compile-time check.
+var _ provider.RerankProvider = (*Provider)(nil)
@@ -16,6 +16,8 @@ type Provider struct {
 	inner   *openai.Provider
 	baseURL string
 	models  []provider.Model
+	creds   provider.CredentialProvider
+	inst    provider.Instance
 }
 
 // New creates a new Deepinfra provider.
@@ -28,13 +30,20 @@ func New(apiKey string, opts ...Option) *Provider {
 	if p.baseURL != "" {
 		baseURL = p.baseURL
 	}
-	p.inner = openai.New(apiKey, openai.WithBaseURL(baseURL))
+	innerOpts := []openai.Option{openai.WithBaseURL(baseURL)}
+	if p.creds != nil {
+		innerOpts = append(innerOpts, openai.WithCredentials(p.creds), openai.WithCredentialName(p.Name()))
+	}
+	p.inner = openai.New(apiKey, innerOpts...)
 	p.models = deepinfraModels()
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "deepinfra" }
+func (p *Provider) Name() string { return p.inst.NameOr("deepinfra") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "deepinfra" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
@@ -49,7 +58,7 @@ func (p *Provider) Capabilities() provider.Capabilities {
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return p.models, nil
+	return p.inst.FilterModels(p.Name(), p.models), nil
 }
 
 // Complete sends a chat completion request.
@@ -58,7 +67,7 @@ func (p *Provider) Complete(ctx context.Context, req *provider.CompletionRequest
 	if err != nil {
 		return nil, err
 	}
-	resp.Provider = "deepinfra"
+	resp.Provider = p.Name()
 	return resp, nil
 }
 
@@ -73,7 +82,7 @@ func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	resp.Provider = "deepinfra"
+	resp.Provider = p.Name()
 	return resp, nil
 }
 
@@ -90,5 +99,21 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
 // Compile-time check.
 var _ provider.Provider = (*Provider)(nil)
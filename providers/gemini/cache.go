@@ -0,0 +1,271 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// cacheFailureBackoff is how long a prefix that Gemini refused to cache
+// (usually because it is below the model's minimum cacheable size) is sent
+// uncached before creation is attempted again.
+const cacheFailureBackoff = 10 * time.Minute
+
+// defaultCacheTTL is used when the cache marker carries no TTL.
+const defaultCacheTTL = 5 * time.Minute
+
+// cachedContentRequest is the body of POST /v1beta/cachedContents.
+type cachedContentRequest struct {
+	Model             string             `json:"model"`
+	Contents          []geminiContent    `json:"contents,omitempty"`
+	SystemInstruction *geminiContent     `json:"systemInstruction,omitempty"`
+	Tools             []geminiToolConfig `json:"tools,omitempty"`
+	TTL               string             `json:"ttl,omitempty"`
+}
+
+type cachedContentResponse struct {
+	Name       string    `json:"name"`
+	ExpireTime time.Time `json:"expireTime"`
+}
+
+// cacheSweepInterval is how often store drops expired entries, so
+// prefixes that are never looked up again do not accumulate.
+const cacheSweepInterval = time.Minute
+
+// contextCache remembers the cachedContents resources created for request
+// prefixes so identical prefixes reuse the same resource until it expires.
+type contextCache struct {
+	mu        sync.Mutex
+	entries   map[string]cacheEntry
+	lastSweep time.Time
+}
+
+type cacheEntry struct {
+	name    string // empty for a negative entry
+	expires time.Time
+}
+
+func newContextCache() *contextCache {
+	return &contextCache{entries: make(map[string]cacheEntry)}
+}
+
+// lookup returns the cached resource name for key. found is true for both
+// live resources and recent failures; name is empty for the latter.
+func (cc *contextCache) lookup(key string) (name string, found bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	e, ok := cc.entries[key]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(e.expires) {
+		delete(cc.entries, key)
+		return "", false
+	}
+	return e.name, true
+}
+
+func (cc *contextCache) store(key, name string, expires time.Time) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if now := time.Now(); now.Sub(cc.lastSweep) >= cacheSweepInterval {
+		for k, e := range cc.entries {
+			if now.After(e.expires) {
+				delete(cc.entries, k)
+			}
+		}
+		cc.lastSweep = now
+	}
+	cc.entries[key] = cacheEntry{name: name, expires: expires}
+}
+
+// applyPromptCache rewrites gemReq to use a cachedContents resource.
+//
+// An explicit req.CachedContent is passed through as-is. Otherwise, when
+// the request carries cache markers, the prefix up to the last marked
+// message (plus system instruction and tools) is stored as a
+// cachedContents resource and the request is trimmed to the remainder.
+// Any failure falls back to sending the full request uncached.
+func (c *client) applyPromptCache(ctx context.Context, req *provider.CompletionRequest, gemReq *geminiRequest) {
+	if req.CachedContent != "" {
+		// The cached resource already holds the system instruction and
+		// tools; Gemini rejects requests that repeat them.
+		gemReq.CachedContent = req.CachedContent
+		gemReq.SystemInstruction = nil
+		gemReq.Tools = nil
+		return
+	}
+	if !req.HasCacheControl() {
+		return
+	}
+
+	prefix, ttl := cachePrefix(req)
+	// The final user turn always goes in the request: Gemini rejects a
+	// generateContent call with nothing after the cached contents.
+	if last := lastUserContent(gemReq.Contents); prefix > last {
+		prefix = max(last, 0)
+	}
+	body := &cachedContentRequest{
+		Model:             "models/" + req.Model,
+		Contents:          gemReq.Contents[:prefix],
+		SystemInstruction: gemReq.SystemInstruction,
+		Tools:             gemReq.Tools,
+		TTL:               fmt.Sprintf("%ds", int(ttl.Seconds())),
+	}
+	if len(body.Contents) == 0 && body.SystemInstruction == nil && len(body.Tools) == 0 {
+		return
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return
+	}
+	sum := sha256.Sum256(raw)
+	key := hex.EncodeToString(sum[:])
+
+	name, found := c.caches.lookup(key)
+	if !found {
+		resp, err := c.createCachedContent(ctx, raw)
+		if err != nil {
+			c.caches.store(key, "", time.Now().Add(cacheFailureBackoff))
+			return
+		}
+		name = resp.Name
+		expires := resp.ExpireTime
+		if expires.IsZero() {
+			expires = time.Now().Add(ttl)
+		}
+		// Stop reusing the resource slightly before Gemini deletes it.
+		c.caches.store(key, name, expires.Add(-10*time.Second))
+	}
+	if name == "" {
+		return
+	}
+
+	gemReq.CachedContent = name
+	gemReq.SystemInstruction = nil
+	gemReq.Tools = nil
+	gemReq.Contents = gemReq.Contents[prefix:]
+}
+
+func (c *client) createCachedContent(ctx context.Context, body []byte) (*cachedContentResponse, error) {
+	url := fmt.Sprintf("%s/v1beta/cachedContents?key=%s", c.baseURL, c.apiKey)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("gemini: create cache request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("gemini: cache request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		return nil, fmt.Errorf("gemini: cache API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var resp cachedContentResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("gemini: decode cache response: %w", err)
+	}
+	if resp.Name == "" {
+		return nil, fmt.Errorf("gemini: cache response has no name")
+	}
+	return &resp, nil
+}
+
+// cachePrefix returns how many Gemini contents precede and include the last
+// cache-marked message, and the longest TTL requested by any marker.
+func cachePrefix(req *provider.CompletionRequest) (int, time.Duration) {
+	ttl := markerTTL(req.SystemCacheControl, 0)
+	for i := range req.Tools {
+		ttl = markerTTL(req.Tools[i].CacheControl, ttl)
+	}
+
+	prefix, contents := 0, 0
+	for i := range req.Messages {
+		m := &req.Messages[i]
+		switch m.Role {
+		case "user", "assistant", "tool":
+			contents++
+		default:
+			// System messages fold into the system instruction, which is
+			// always part of the cached prefix.
+			continue
+		}
+		if cc := lastMarker(m); cc != nil {
+			prefix = contents
+			ttl = markerTTL(cc, ttl)
+		}
+	}
+	if ttl == 0 {
+		ttl = defaultCacheTTL
+	}
+	return prefix, ttl
+}
+
+// lastUserContent returns the index of the last user content (a user turn
+// or tool result), or of the last content when there is none.
+func lastUserContent(contents []geminiContent) int {
+	for i := len(contents) - 1; i >= 0; i-- {
+		if contents[i].Role == "user" {
+			return i
+		}
+	}
+	return len(contents) - 1
+}
+
+// lastMarker returns the cache marker on m or on its last marked part.
+func lastMarker(m *provider.Message) *provider.CacheControl {
+	var found *provider.CacheControl
+	switch parts := m.Content.(type) {
+	case []provider.ContentPart:
+		for i := range parts {
+			if parts[i].CacheControl != nil {
+				found = parts[i].CacheControl
+			}
+		}
+	case []any:
+		for _, p := range parts {
+			pm, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			if cm, ok := pm["cache_control"].(map[string]any); ok {
+				cc := &provider.CacheControl{Type: provider.CacheControlEphemeral}
+				if ttl, ok := cm["ttl"].(string); ok {
+					cc.TTL = ttl
+				}
+				found = cc
+			}
+		}
+	}
+	if m.CacheControl != nil {
+		found = m.CacheControl
+	}
+	return found
+}
+
+// markerTTL returns the larger of cur and the TTL on cc.
+func markerTTL(cc *provider.CacheControl, cur time.Duration) time.Duration {
+	if cc == nil || cc.TTL == "" {
+		return cur
+	}
+	d, err := time.ParseDuration(cc.TTL)
+	if err != nil || d <= cur {
+		return cur
+	}
+	return d
+}
@@ -0,0 +1,136 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xraph/nexus/provider"
+)
+
+func TestApplyPromptCache_createsAndReusesCachedContent(t *testing.T) {
+	var creates atomic.Int32
+	var lastGenerate map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body) //nolint:errcheck // test server
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v1beta/cachedContents"):
+			creates.Add(1)
+			_, _ = w.Write([]byte(`{"name":"cachedContents/abc","expireTime":"2099-01-01T00:00:00Z"}`)) //nolint:errcheck // test server
+		default:
+			_ = json.Unmarshal(body, &lastGenerate)                                                                //nolint:errcheck // test server
+			_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"ok"}]},"finishReason":"STOP"}],` + //nolint:errcheck // test server
+				`"usageMetadata":{"promptTokenCount":1200,"candidatesTokenCount":2,"totalTokenCount":1202,"cachedContentTokenCount":1100}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := newClient("k", srv.URL)
+	req := &provider.CompletionRequest{
+		Model:  "gemini-1.5-flash",
+		System: "long instructions",
+		Messages: []provider.Message{
+			{Role: "user", Content: "big document", CacheControl: provider.NewCacheControl("1h")},
+			{Role: "user", Content: "question"},
+		},
+	}
+
+	for range 2 {
+		resp, err := c.complete(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Usage.CacheReadTokens != 1100 {
+			t.Errorf("CacheReadTokens = %d, want 1100", resp.Usage.CacheReadTokens)
+		}
+	}
+
+	if got := creates.Load(); got != 1 {
+		t.Errorf("cachedContents created %d times, want 1", got)
+	}
+	if lastGenerate["cachedContent"] != "cachedContents/abc" {
+		t.Errorf("cachedContent = %v", lastGenerate["cachedContent"])
+	}
+	if _, ok := lastGenerate["systemInstruction"]; ok {
+		t.Error("systemInstruction sent alongside cachedContent")
+	}
+	if contents, _ := lastGenerate["contents"].([]any); len(contents) != 1 { //nolint:errcheck // length check covers failure
+		t.Errorf("contents = %d, want only the uncached turn", len(contents))
+	}
+}
+
+func TestApplyPromptCache_fallsBackWhenCreationFails(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "too small", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := newClient("k", srv.URL)
+	req := &provider.CompletionRequest{
+		Model:    "gemini-1.5-flash",
+		System:   "short",
+		Messages: []provider.Message{{Role: "user", Content: "hi", CacheControl: provider.NewCacheControl("")}},
+	}
+	gemReq := c.toGeminiRequest(req)
+	c.applyPromptCache(context.Background(), req, gemReq)
+
+	if gemReq.CachedContent != "" || gemReq.SystemInstruction == nil || len(gemReq.Contents) != 1 {
+		t.Fatalf("request modified despite cache failure: %+v", gemReq)
+	}
+
+	// The failure is remembered, so the next request skips creation.
+	c.applyPromptCache(context.Background(), req, c.toGeminiRequest(req))
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("creation attempts = %d, want 1", got)
+	}
+}
+
+func TestApplyPromptCache_keepsFinalUserTurn(t *testing.T) {
+	var created cachedContentRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&created)                                                //nolint:errcheck // test server
+		_, _ = w.Write([]byte(`{"name":"cachedContents/abc","expireTime":"2099-01-01T00:00:00Z"}`)) //nolint:errcheck // test server
+	}))
+	defer srv.Close()
+
+	c := newClient("k", srv.URL)
+	req := &provider.CompletionRequest{
+		Model:  "gemini-1.5-flash",
+		System: "long instructions",
+		Messages: []provider.Message{
+			{Role: "user", Content: "big document"},
+			{Role: "assistant", Content: "noted"},
+			{Role: "user", Content: "question", CacheControl: provider.NewCacheControl("")},
+		},
+	}
+	gemReq := c.toGeminiRequest(req)
+	c.applyPromptCache(context.Background(), req, gemReq)
+
+	if gemReq.CachedContent != "cachedContents/abc" || len(created.Contents) != 2 {
+		t.Fatalf("cached %d contents as %q, want the 2 before the final turn", len(created.Contents), gemReq.CachedContent)
+	}
+	if len(gemReq.Contents) != 1 || gemReq.Contents[0].Parts[0].Text != "question" {
+		t.Fatalf("contents = %+v, want only the final user turn", gemReq.Contents)
+	}
+}
+
+func TestContextCache_sweepsExpiredEntries(t *testing.T) {
+	cc := newContextCache()
+	cc.store("old", "cachedContents/old", time.Now().Add(-time.Minute))
+	cc.lastSweep = time.Now().Add(-2 * cacheSweepInterval)
+	cc.store("new", "cachedContents/new", time.Now().Add(time.Hour))
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if _, ok := cc.entries["old"]; ok || len(cc.entries) != 1 {
+		t.Fatalf("entries = %v, want only the live one", cc.entries)
+	}
+}
@@ -16,6 +16,7 @@ type client struct {
 	apiKey  string
 	baseURL string
 	http    *http.Client
+	caches  *contextCache
 }
 
 func newClient(apiKey, baseURL string) *client {
@@ -25,6 +26,7 @@ func newClient(apiKey, baseURL string) *client {
 		http: &http.Client{
 			Timeout: 120 * time.Second,
 		},
+		caches: newContextCache(),
 	}
 }
 
@@ -34,6 +36,7 @@ type geminiRequest struct {
 	SystemInstruction *geminiContent     `json:"systemInstruction,omitempty"`
 	GenerationConfig  *generationConfig  `json:"generationConfig,omitempty"`
 	Tools             []geminiToolConfig `json:"tools,omitempty"`
+	CachedContent     string             `json:"cachedContent,omitempty"`
 }
 
 type geminiContent struct {
@@ -90,12 +93,26 @@ type geminiUsage struct {
 	PromptTokenCount     int `json:"promptTokenCount"`
 	CandidatesTokenCount int `json:"candidatesTokenCount"`
 	TotalTokenCount      int `json:"totalTokenCount"`
+	// CachedContentTokenCount is the share of PromptTokenCount served
+	// from a cachedContents resource.
+	CachedContentTokenCount int `json:"cachedContentTokenCount,omitempty"`
+}
+
+func (u *geminiUsage) toUsage() provider.Usage {
+	return provider.Usage{
+		PromptTokens:     u.PromptTokenCount,
+		CompletionTokens: u.CandidatesTokenCount,
+		TotalTokens:      u.TotalTokenCount,
+		CacheReadTokens:  u.CachedContentTokenCount,
+	}
 }
 
 // Embedding types.
 type geminiEmbedRequest struct {
-	Model   string             `json:"model"`
-	Content geminiEmbedContent `json:"content"`
+	Model                string             `json:"model"`
+	Content              geminiEmbedContent `json:"content"`
+	TaskType             string             `json:"taskType,omitempty"`
+	OutputDimensionality int                `json:"outputDimensionality,omitempty"`
 }
 
 type geminiEmbedContent struct {
@@ -114,6 +131,7 @@ type geminiBatchEmbedResponse struct {
 
 func (c *client) complete(ctx context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error) {
 	gemReq := c.toGeminiRequest(req)
+	c.applyPromptCache(ctx, req, gemReq)
 
 	body, err := json.Marshal(gemReq)
 	if err != nil {
@@ -150,6 +168,7 @@ func (c *client) complete(ctx context.Context, req *provider.CompletionRequest)
 
 func (c *client) completeStream(ctx context.Context, req *provider.CompletionRequest) (provider.Stream, error) {
 	gemReq := c.toGeminiRequest(req)
+	c.applyPromptCache(ctx, req, gemReq)
 
 	body, err := json.Marshal(gemReq)
 	if err != nil {
@@ -186,6 +205,8 @@ func (c *client) embed(ctx context.Context, req *provider.EmbeddingRequest) (*pr
 			Content: geminiEmbedContent{
 				Parts: []geminiPart{{Text: text}},
 			},
+			TaskType:             geminiTaskType(req.InputType),
+			OutputDimensionality: req.Dimensions,
 		}
 	}
 
@@ -412,11 +433,7 @@ func (c *client) fromGeminiResponse(resp *geminiResponse, model string, elapsed
 	}
 
 	if resp.UsageMetadata != nil {
-		result.Usage = provider.Usage{
-			PromptTokens:     resp.UsageMetadata.PromptTokenCount,
-			CompletionTokens: resp.UsageMetadata.CandidatesTokenCount,
-			TotalTokens:      resp.UsageMetadata.TotalTokenCount,
-		}
+		result.Usage = resp.UsageMetadata.toUsage()
 	}
 
 	return result
@@ -436,3 +453,19 @@ func mapFinishReason(reason string) string {
 		return reason
 	}
 }
+
+// geminiTaskType maps a unified embedding input type to a Gemini task type.
+func geminiTaskType(t string) string {
+	switch t {
+	case provider.EmbeddingInputQuery:
+		return "RETRIEVAL_QUERY"
+	case provider.EmbeddingInputDocument:
+		return "RETRIEVAL_DOCUMENT"
+	case provider.EmbeddingInputClassification:
+		return "CLASSIFICATION"
+	case provider.EmbeddingInputClustering:
+		return "CLUSTERING"
+	default:
+		return ""
+	}
+}
@@ -9,7 +9,7 @@ func geminiModels() []provider.Model {
 			ID: "gemini-2.0-flash", Provider: "gemini", Name: "Gemini 2.0 Flash",
 			Capabilities:  provider.Capabilities{Chat: true, Streaming: true, Vision: true, Tools: true, JSON: true},
 			ContextWindow: 1048576, MaxOutput: 8192,
-			Pricing: provider.Pricing{InputPerMillion: 0.10, OutputPerMillion: 0.40},
+			Pricing: provider.Pricing{InputPerMillion: 0.10, OutputPerMillion: 0.40, CacheReadPerMillion: 0.025},
 		},
 		{
 			ID: "gemini-2.0-flash-lite", Provider: "gemini", Name: "Gemini 2.0 Flash Lite",
@@ -21,13 +21,13 @@ func geminiModels() []provider.Model {
 			ID: "gemini-1.5-pro", Provider: "gemini", Name: "Gemini 1.5 Pro",
 			Capabilities:  provider.Capabilities{Chat: true, Streaming: true, Vision: true, Tools: true, JSON: true},
 			ContextWindow: 2097152, MaxOutput: 8192,
-			Pricing: provider.Pricing{InputPerMillion: 1.25, OutputPerMillion: 5.00},
+			Pricing: provider.Pricing{InputPerMillion: 1.25, OutputPerMillion: 5.00, CacheReadPerMillion: 0.3125},
 		},
 		{
 			ID: "gemini-1.5-flash", Provider: "gemini", Name: "Gemini 1.5 Flash",
 			Capabilities:  provider.Capabilities{Chat: true, Streaming: true, Vision: true, Tools: true, JSON: true},
 			ContextWindow: 1048576, MaxOutput: 8192,
-			Pricing: provider.Pricing{InputPerMillion: 0.075, OutputPerMillion: 0.30},
+			Pricing: provider.Pricing{InputPerMillion: 0.075, OutputPerMillion: 0.30, CacheReadPerMillion: 0.01875},
 		},
 		{
 			ID: "text-embedding-004", Provider: "gemini", Name: "Text Embedding 004",
@@ -5,6 +5,7 @@ package gemini
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/xraph/nexus/provider"
 )
@@ -16,6 +17,9 @@ type Provider struct {
 	apiKey  string
 	baseURL string
 	client  *client
+
+	creds provider.CredentialProvider
+	inst  provider.Instance
 }
 
 // New creates a new Gemini provider.
@@ -28,11 +32,17 @@ func New(apiKey string, opts ...Option) *Provider {
 		opt(p)
 	}
 	p.client = newClient(p.apiKey, p.baseURL)
+	if p.creds != nil {
+		p.client.http.Transport = provider.NewCredentialTransport(nil, p.Name(), p.creds, applyAPIKey)
+	}
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "gemini" }
+func (p *Provider) Name() string { return p.inst.NameOr("gemini") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "gemini" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
@@ -48,7 +58,7 @@ func (p *Provider) Capabilities() provider.Capabilities {
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return geminiModels(), nil
+	return p.inst.FilterModels(p.Name(), geminiModels()), nil
 }
 
 // Complete sends a chat completion request.
@@ -66,6 +76,11 @@ func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*
 	return p.client.embed(ctx, req)
 }
 
+// EmbeddingLimits reports the batchEmbedContents cap of 100 requests.
+func (p *Provider) EmbeddingLimits(_ string) provider.EmbeddingLimits {
+	return provider.EmbeddingLimits{MaxInputs: 100}
+}
+
 // Healthy returns true if the provider is reachable.
 func (p *Provider) Healthy(ctx context.Context) bool {
 	return p.client.ping(ctx) == nil
@@ -79,5 +94,34 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
-// Compile-time check.
-var _ provider.Provider = (*Provider)(nil)
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// applyAPIKey moves authentication to the x-goog-api-key header, dropping
+// the static key the client put in the query string.
+func applyAPIKey(req *http.Request, key string) error {
+	q := req.URL.Query()
+	q.Del("key")
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("x-goog-api-key", key)
+	return nil
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
+// Compile-time checks.
+var (
+	_ provider.Provider         = (*Provider)(nil)
+	_ provider.EmbeddingLimiter = (*Provider)(nil)
+)
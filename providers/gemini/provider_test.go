@@ -2,6 +2,9 @@ package gemini
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/xraph/nexus/provider"
@@ -130,3 +133,29 @@ func TestConformance(t *testing.T) {
 	p := New("test-key", WithBaseURL(mock.Server.URL))
 	providertest.TestProviderContract(t, p)
 }
+
+func TestWithCredentials_UsesHeader(t *testing.T) {
+	var gotKey, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("x-goog-api-key")
+		gotQuery = r.URL.Query().Get("key")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"candidates":[{"content":{"parts":[{"text":"ok"}],"role":"model"},"finishReason":"STOP"}]}`)
+	}))
+	defer srv.Close()
+
+	creds := provider.NewStaticCredential(map[string]string{"gemini": "rotated-key"})
+	p := New("static-key", WithBaseURL(srv.URL), WithCredentials(creds))
+	if _, err := p.Complete(context.Background(), &provider.CompletionRequest{
+		Model:    "gemini-2.0-flash",
+		Messages: []provider.Message{{Role: "user", Content: "Hello"}},
+	}); err != nil {
+		t.Fatalf("Complete() error: %v", err)
+	}
+	if gotKey != "rotated-key" {
+		t.Errorf("x-goog-api-key = %q, want rotated-key", gotKey)
+	}
+	if gotQuery != "" {
+		t.Errorf("static key leaked into query: %q", gotQuery)
+	}
+}
@@ -55,11 +55,8 @@ func (s *geminiStream) Next(_ context.Context) (*provider.StreamChunk, error) {
 
 		// Capture usage if present.
 		if resp.UsageMetadata != nil {
-			s.usage = &provider.Usage{
-				PromptTokens:     resp.UsageMetadata.PromptTokenCount,
-				CompletionTokens: resp.UsageMetadata.CandidatesTokenCount,
-				TotalTokens:      resp.UsageMetadata.TotalTokenCount,
-			}
+			usage := resp.UsageMetadata.toUsage()
+			s.usage = &usage
 		}
 
 		if len(resp.Candidates) == 0 {
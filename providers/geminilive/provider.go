@@ -31,6 +31,8 @@ type Provider struct {
 	model   string
 	setup   SetupConfig
 	dial    dialFunc
+	creds   provider.CredentialProvider
+	inst    provider.Instance
 }
 
 // Option configures the Live provider.
@@ -47,6 +49,14 @@ func WithModel(m string) Option { return func(p *Provider) { p.model = m } }
 // to the Live API on session start.
 func WithSetup(cfg SetupConfig) Option { return func(p *Provider) { p.setup = cfg } }
 
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option { return func(p *Provider) { p.inst.Name = name } }
+
+// WithCredentials resolves the API key from cp each time a session opens.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
 func withDialer(d dialFunc) Option { return func(p *Provider) { p.dial = d } }
 
 // New returns a Live provider.
@@ -63,7 +73,10 @@ func New(apiKey string, opts ...Option) *Provider {
 	return p
 }
 
-func (p *Provider) Name() string { return "gemini-live" }
+func (p *Provider) Name() string { return p.inst.NameOr("gemini-live") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "gemini-live" }
 
 func (p *Provider) Capabilities() provider.Capabilities {
 	return provider.Capabilities{
@@ -95,7 +108,11 @@ func (p *Provider) CompleteStream(ctx context.Context, req *provider.CompletionR
 	if model == "" {
 		model = p.model
 	}
-	conn, err := p.dial(ctx, p.baseURL, p.apiKey)
+	apiKey, err := p.credential(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := p.dial(ctx, p.baseURL, apiKey)
 	if err != nil {
 		return nil, err
 	}
@@ -134,5 +151,18 @@ func dialDefault(ctx context.Context, baseURL, apiKey string) (wsConn, error) {
 	return conn, nil
 }
 
+// credential returns the key for a new session: the credential provider's
+// current key when one is configured, otherwise the static key.
+func (p *Provider) credential(ctx context.Context) (string, error) {
+	if p.creds == nil {
+		return p.apiKey, nil
+	}
+	key, err := p.creds.GetCredential(ctx, p.Name())
+	if err != nil {
+		return "", fmt.Errorf("geminilive: credential: %w", err)
+	}
+	return key, nil
+}
+
 // Compile-time check.
 var _ provider.Provider = (*Provider)(nil)
@@ -0,0 +1,22 @@
+package groq
+
+import (
+	"context"
+
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/providers/openai"
+)
+
+// Transcribe converts speech to text with Groq-hosted Whisper models.
+func (p *Provider) Transcribe(ctx context.Context, req *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error) {
+	resp, err := p.inner.Transcribe(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Provider = "groq"
+	resp.Cost = openai.TranscriptionCost(p.models, resp)
+	return resp, nil
+}
+
+// Compile-time check.
+var _ provider.TranscriptionProvider = (*Provider)(nil)
@@ -29,5 +29,17 @@ func groqModels() []provider.Model {
 			ContextWindow: 8192, MaxOutput: 8192,
 			Pricing: provider.Pricing{InputPerMillion: 0.20, OutputPerMillion: 0.20},
 		},
+		{
+			ID: "whisper-large-v3", Provider: "groq", Name: "Whisper Large v3",
+			Capabilities:  provider.Capabilities{Audio: true},
+			ContextWindow: 448,
+			Pricing:       provider.Pricing{AudioPerSecond: 0.111 / 3600},
+		},
+		{
+			ID: "whisper-large-v3-turbo", Provider: "groq", Name: "Whisper Large v3 Turbo",
+			Capabilities:  provider.Capabilities{Audio: true},
+			ContextWindow: 448,
+			Pricing:       provider.Pricing{AudioPerSecond: 0.04 / 3600},
+		},
 	}
 }
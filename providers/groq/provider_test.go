@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/xraph/nexus/provider"
 	"github.com/xraph/nexus/providertest"
 	"github.com/xraph/nexus/testutil"
 )
@@ -33,6 +34,9 @@ func TestCapabilities(t *testing.T) {
 	if !caps.JSON {
 		t.Error("expected JSON capability")
 	}
+	if !caps.Audio {
+		t.Error("expected Audio capability")
+	}
 	if caps.Embeddings {
 		t.Error("expected Embeddings to be false")
 	}
@@ -60,6 +64,29 @@ func TestComplete(t *testing.T) {
 	providertest.TestProviderComplete(t, p)
 }
 
+func TestTranscribe(t *testing.T) {
+	mock := testutil.NewMockServer(t)
+	mock.Ctrl.SetCompletion(map[string]any{"text": "hola", "duration": 3600.0})
+	p := New("test-key", WithBaseURL(mock.Server.URL))
+
+	resp, err := p.Transcribe(context.Background(), &provider.TranscriptionRequest{
+		Model: "whisper-large-v3-turbo",
+		File:  []byte("audio"),
+	})
+	if err != nil {
+		t.Fatalf("Transcribe() error: %v", err)
+	}
+	if resp.Provider != "groq" || resp.Text != "hola" {
+		t.Errorf("resp = %+v", resp)
+	}
+	if resp.Cost < 0.0399 || resp.Cost > 0.0401 {
+		t.Errorf("Cost=%v, want 0.04", resp.Cost)
+	}
+	if got := mock.Ctrl.GetLastPath(); got != "/audio/transcriptions" {
+		t.Errorf("path=%q", got)
+	}
+}
+
 func TestHealthy(t *testing.T) {
 	mock := testutil.NewMockServer(t)
 	p := New("test-key", WithBaseURL(mock.Server.URL))
@@ -0,0 +1,110 @@
+package jinaai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+)
+
+type jinaEmbedRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Task       string   `json:"task,omitempty"`
+	Dimensions int      `json:"dimensions,omitempty"`
+	Truncate   *bool    `json:"truncate,omitempty"`
+}
+
+type jinaEmbedResponse struct {
+	Model string `json:"model"`
+	Data  []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// embed calls Jina's embeddings API directly rather than through the inner
+// OpenAI client, which cannot send task or truncate.
+func (p *Provider) embed(ctx context.Context, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	embedReq := jinaEmbedRequest{
+		Model:      req.Model,
+		Input:      req.Input,
+		Task:       jinaTask(req.InputType),
+		Dimensions: req.Dimensions,
+	}
+	if req.Truncate != "" {
+		truncate := req.Truncate != "none"
+		embedReq.Truncate = &truncate
+	}
+
+	body, err := json.Marshal(embedReq)
+	if err != nil {
+		return nil, fmt.Errorf("jinaai: marshal embed request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("jinaai: create embed request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	httpResp, err := p.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("jinaai: embed request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		return nil, fmt.Errorf("jinaai: embed API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var resp jinaEmbedResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("jinaai: decode embed response: %w", err)
+	}
+
+	embeddings := make([][]float64, len(resp.Data))
+	for i, d := range resp.Data {
+		if d.Index >= 0 && d.Index < len(embeddings) {
+			embeddings[d.Index] = d.Embedding
+		} else {
+			embeddings[i] = d.Embedding
+		}
+	}
+
+	return &provider.EmbeddingResponse{
+		Provider:   "jinaai",
+		Model:      req.Model,
+		Embeddings: embeddings,
+		Usage: provider.Usage{
+			PromptTokens: resp.Usage.PromptTokens,
+			TotalTokens:  resp.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+// jinaTask maps a unified embedding input type to a Jina task.
+func jinaTask(t string) string {
+	switch t {
+	case provider.EmbeddingInputQuery:
+		return "retrieval.query"
+	case provider.EmbeddingInputDocument:
+		return "retrieval.passage"
+	case provider.EmbeddingInputClassification:
+		return "classification"
+	case provider.EmbeddingInputClustering:
+		return "separation"
+	default:
+		return ""
+	}
+}
@@ -17,5 +17,17 @@ func jinaAIModels() []provider.Model {
 			ContextWindow: 8192,
 			Pricing:       provider.Pricing{EmbeddingPerMillion: 0.02},
 		},
+		{
+			ID: "jina-reranker-v2-base-multilingual", Provider: "jinaai", Name: "Jina Reranker v2 Multilingual",
+			Capabilities:  provider.Capabilities{Rerank: true},
+			ContextWindow: 1024,
+			Pricing:       provider.Pricing{InputPerMillion: 0.02},
+		},
+		{
+			ID: "jina-reranker-m0", Provider: "jinaai", Name: "Jina Reranker m0",
+			Capabilities:  provider.Capabilities{Rerank: true},
+			ContextWindow: 10240,
+			Pricing:       provider.Pricing{InputPerMillion: 0.02},
+		},
 	}
 }
@@ -1,11 +1,13 @@
 // Package jinaai provides a Jina AI provider implementation for Nexus.
-// Jina AI uses an OpenAI-compatible API for embeddings and does not support
-// chat completions. This provider wraps the OpenAI provider with the Jina AI
-// base URL.
+// Jina AI serves embeddings and reranking and does not support chat
+// completions. Embeddings and rerank call Jina's API directly; the wrapped
+// OpenAI provider is used for health checks.
 package jinaai
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"github.com/xraph/nexus/provider"
 	"github.com/xraph/nexus/providers/openai"
@@ -16,36 +18,50 @@ const defaultBaseURL = "https://api.jina.ai/v1"
 // Provider implements the Nexus provider interface for Jina AI.
 type Provider struct {
 	inner   *openai.Provider
+	apiKey  string
 	baseURL string
+	http    *http.Client // embed and rerank requests
+
+	creds provider.CredentialProvider
+	inst  provider.Instance
 }
 
 // New creates a new Jina AI provider.
 func New(apiKey string, opts ...Option) *Provider {
-	p := &Provider{}
+	p := &Provider{apiKey: apiKey}
 	for _, opt := range opts {
 		opt(p)
 	}
-	baseURL := defaultBaseURL
-	if p.baseURL != "" {
-		baseURL = p.baseURL
+	if p.baseURL == "" {
+		p.baseURL = defaultBaseURL
+	}
+	innerOpts := []openai.Option{openai.WithBaseURL(p.baseURL)}
+	p.http = &http.Client{Timeout: 120 * time.Second}
+	if p.creds != nil {
+		innerOpts = append(innerOpts, openai.WithCredentials(p.creds), openai.WithCredentialName(p.Name()))
+		p.http.Transport = provider.NewCredentialTransport(nil, p.Name(), p.creds, provider.BearerAuth)
 	}
-	p.inner = openai.New(apiKey, openai.WithBaseURL(baseURL))
+	p.inner = openai.New(apiKey, innerOpts...)
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "jinaai" }
+func (p *Provider) Name() string { return p.inst.NameOr("jinaai") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "jinaai" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
 	return provider.Capabilities{
 		Embeddings: true,
+		Rerank:     true,
 	}
 }
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return jinaAIModels(), nil
+	return p.inst.FilterModels(p.Name(), jinaAIModels()), nil
 }
 
 // Complete is not supported by Jina AI.
@@ -58,14 +74,14 @@ func (p *Provider) CompleteStream(_ context.Context, _ *provider.CompletionReque
 	return nil, provider.ErrNotSupported
 }
 
-// Embed sends an embedding request via the inner OpenAI-compatible client.
+// Embed sends an embedding request, mapping InputType to a Jina task.
 func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
-	resp, err := p.inner.Embed(ctx, req)
-	if err != nil {
-		return nil, err
-	}
-	resp.Provider = "jinaai"
-	return resp, nil
+	return p.embed(ctx, req)
+}
+
+// EmbeddingLimits reports Jina's per-call cap of 2048 inputs.
+func (p *Provider) EmbeddingLimits(_ string) provider.EmbeddingLimits {
+	return provider.EmbeddingLimits{MaxInputs: 2048}
 }
 
 // Healthy returns true if the provider is reachable.
@@ -81,5 +97,24 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
-// Compile-time check.
-var _ provider.Provider = (*Provider)(nil)
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
+// Compile-time checks.
+var (
+	_ provider.Provider         = (*Provider)(nil)
+	_ provider.EmbeddingLimiter = (*Provider)(nil)
+)
@@ -2,7 +2,10 @@ package jinaai
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/xraph/nexus/provider"
@@ -23,6 +26,9 @@ func TestCapabilities(t *testing.T) {
 	if !caps.Embeddings {
 		t.Error("expected Embeddings capability")
 	}
+	if !caps.Rerank {
+		t.Error("expected Rerank capability")
+	}
 	if caps.Chat {
 		t.Error("expected Chat to be false")
 	}
@@ -44,8 +50,8 @@ func TestModels(t *testing.T) {
 		if m.Provider != "jinaai" {
 			t.Errorf("model %q provider=%q, want %q", m.ID, m.Provider, "jinaai")
 		}
-		if !m.Capabilities.Embeddings {
-			t.Errorf("model %q should have Embeddings capability", m.ID)
+		if !m.Capabilities.Embeddings && !m.Capabilities.Rerank {
+			t.Errorf("model %q should have Embeddings or Rerank capability", m.ID)
 		}
 	}
 }
@@ -79,6 +85,44 @@ func TestEmbed(t *testing.T) {
 	}
 }
 
+func TestRerank(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if r.URL.Path != "/rerank" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"model": "jina-reranker-v2-base-multilingual",
+			"results": []map[string]any{
+				{"index": 1, "relevance_score": 0.8, "document": map[string]any{"text": "Paris is the capital of France"}},
+				{"index": 0, "relevance_score": 0.1, "document": map[string]any{"text": "Berlin is in Germany"}},
+			},
+			"usage": map[string]any{"total_tokens": 12},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	p := New("jina-key", WithBaseURL(server.URL))
+	resp, err := p.Rerank(context.Background(), &provider.RerankRequest{
+		Model:     "jina-reranker-v2-base-multilingual",
+		Query:     "capital of France",
+		Documents: []string{"Berlin is in Germany", "Paris is the capital of France"},
+	})
+	if err != nil {
+		t.Fatalf("Rerank() error: %v", err)
+	}
+	if auth != "Bearer jina-key" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Index != 1 || resp.Results[0].Document != nil {
+		t.Errorf("results = %+v", resp.Results)
+	}
+	if resp.Provider != "jinaai" || resp.Usage.TotalTokens != 12 || resp.Cost <= 0 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
 func TestCompleteNotSupported(t *testing.T) {
 	p := New("test-key")
 	_, err := p.Complete(context.Background(), &provider.CompletionRequest{
@@ -0,0 +1,97 @@
+package jinaai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+)
+
+type jinaRerankRequest struct {
+	Model           string   `json:"model"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	TopN            int      `json:"top_n,omitempty"`
+	ReturnDocuments bool     `json:"return_documents"`
+}
+
+type jinaRerankResponse struct {
+	Model   string `json:"model"`
+	Results []struct {
+		Index          int                      `json:"index"`
+		RelevanceScore float64                  `json:"relevance_score"`
+		Document       *provider.RerankDocument `json:"document"`
+	} `json:"results"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// Rerank scores documents against a query. Jina bills rerank calls per
+// token.
+func (p *Provider) Rerank(ctx context.Context, req *provider.RerankRequest) (*provider.RerankResponse, error) {
+	body, err := json.Marshal(jinaRerankRequest{
+		Model:           req.Model,
+		Query:           req.Query,
+		Documents:       req.Documents,
+		TopN:            req.TopN,
+		ReturnDocuments: req.ReturnDocuments,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jinaai: marshal rerank request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("jinaai: create rerank request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	httpResp, err := p.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("jinaai: rerank request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		return nil, fmt.Errorf("jinaai: rerank API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var resp jinaRerankResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("jinaai: decode rerank response: %w", err)
+	}
+
+	results := make([]provider.RerankResult, len(resp.Results))
+	for i, r := range resp.Results {
+		results[i] = provider.RerankResult{Index: r.Index, RelevanceScore: r.RelevanceScore}
+		if req.ReturnDocuments {
+			results[i].Document = r.Document
+		}
+	}
+	out := &provider.RerankResponse{
+		Provider: "jinaai",
+		Model:    req.Model,
+		Results:  results,
+		Usage: provider.Usage{
+			PromptTokens: resp.Usage.TotalTokens,
+			TotalTokens:  resp.Usage.TotalTokens,
+		},
+	}
+	for _, m := range jinaAIModels() {
+		if m.ID == req.Model {
+			out.Cost = m.Pricing.RerankCost(0, resp.Usage.TotalTokens)
+			break
+		}
+	}
+	return out, nil
+}
+
+// Compile-time check.
+var _ provider.RerankProvider = (*Provider)(nil)
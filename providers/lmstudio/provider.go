@@ -18,6 +18,8 @@ type Provider struct {
 	baseURL          string
 	models           []provider.Model
 	extractToolCalls bool
+	creds            provider.CredentialProvider
+	inst             provider.Instance
 }
 
 // New creates a new LM Studio provider.
@@ -31,14 +33,22 @@ func New(opts ...Option) *Provider {
 	if p.baseURL != "" {
 		baseURL = p.baseURL
 	}
-	// LM Studio doesn't require an API key; pass empty string.
-	p.inner = openai.New("", openai.WithBaseURL(baseURL))
+	// LM Studio doesn't require an API key; pass empty string unless the
+	// server sits behind an authenticating proxy.
+	innerOpts := []openai.Option{openai.WithBaseURL(baseURL)}
+	if p.creds != nil {
+		innerOpts = append(innerOpts, openai.WithCredentials(p.creds), openai.WithCredentialName(p.Name()))
+	}
+	p.inner = openai.New("", innerOpts...)
 	p.models = lmStudioModels()
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "lmstudio" }
+func (p *Provider) Name() string { return p.inst.NameOr("lmstudio") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "lmstudio" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
@@ -54,7 +64,7 @@ func (p *Provider) Capabilities() provider.Capabilities {
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return p.models, nil
+	return p.inst.FilterModels(p.Name(), p.models), nil
 }
 
 // Complete sends a chat completion request.
@@ -63,7 +73,7 @@ func (p *Provider) Complete(ctx context.Context, req *provider.CompletionRequest
 	if err != nil {
 		return nil, err
 	}
-	resp.Provider = "lmstudio"
+	resp.Provider = p.Name()
 	return resp, nil
 }
 
@@ -92,7 +102,7 @@ func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	resp.Provider = "lmstudio"
+	resp.Provider = p.Name()
 	return resp, nil
 }
 
@@ -109,6 +119,22 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
 // Compile-time check.
 var _ provider.Provider = (*Provider)(nil)
 
@@ -0,0 +1,229 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// openAITranscriptionResponse covers the json and verbose_json formats.
+type openAITranscriptionResponse struct {
+	Text     string                          `json:"text"`
+	Language string                          `json:"language,omitempty"`
+	Duration float64                         `json:"duration,omitempty"`
+	Segments []provider.TranscriptionSegment `json:"segments,omitempty"`
+	Usage    *struct {
+		Type         string  `json:"type"` // "tokens" or "duration"
+		InputTokens  int     `json:"input_tokens"`
+		OutputTokens int     `json:"output_tokens"`
+		TotalTokens  int     `json:"total_tokens"`
+		Seconds      float64 `json:"seconds"`
+	} `json:"usage,omitempty"`
+}
+
+// Transcribe converts speech to text with Whisper or gpt-4o transcription
+// models.
+func (p *Provider) Transcribe(ctx context.Context, req *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error) {
+	resp, err := p.client.transcribe(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Cost = TranscriptionCost(openAIModels(), resp)
+	return resp, nil
+}
+
+// Speech synthesizes speech with the tts models. The audio body streams
+// from the upstream response.
+func (p *Provider) Speech(ctx context.Context, req *provider.SpeechRequest) (*provider.SpeechResponse, error) {
+	resp, err := p.client.speech(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Cost = SpeechCost(openAIModels(), resp)
+	return resp, nil
+}
+
+func (c *client) transcribe(ctx context.Context, req *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	filename := req.Filename
+	if filename == "" {
+		filename = "audio.mp3"
+	}
+	fw, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("openai: create form file: %w", err)
+	}
+	if _, err := fw.Write(req.File); err != nil {
+		return nil, fmt.Errorf("openai: write form file: %w", err)
+	}
+
+	// verbose_json carries the duration used for billing; gpt-4o
+	// transcription models reject it and report token usage instead.
+	format := "verbose_json"
+	if strings.HasPrefix(req.Model, "gpt-4o") {
+		format = "json"
+	}
+	fields := map[string]string{
+		"model":           req.Model,
+		"response_format": format,
+		"prompt":          req.Prompt,
+	}
+	if !req.Translate {
+		fields["language"] = req.Language
+	}
+	if req.Temperature != nil {
+		fields["temperature"] = strconv.FormatFloat(*req.Temperature, 'f', -1, 64)
+	}
+	for k, v := range fields {
+		if v == "" {
+			continue
+		}
+		if err := mw.WriteField(k, v); err != nil {
+			return nil, fmt.Errorf("openai: write form field: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("openai: close form: %w", err)
+	}
+
+	path := "/audio/transcriptions"
+	if req.Translate {
+		path = "/audio/translations"
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, &body)
+	if err != nil {
+		return nil, fmt.Errorf("openai: create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", mw.FormDataContentType())
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai: request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		return nil, fmt.Errorf("openai: API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var oaiResp openAITranscriptionResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&oaiResp); err != nil {
+		return nil, fmt.Errorf("openai: decode response: %w", err)
+	}
+
+	resp := &provider.TranscriptionResponse{
+		Provider: "openai",
+		Model:    req.Model,
+		Text:     oaiResp.Text,
+		Language: oaiResp.Language,
+		Duration: oaiResp.Duration,
+		Segments: oaiResp.Segments,
+	}
+	if u := oaiResp.Usage; u != nil {
+		if u.Type == "duration" && resp.Duration == 0 {
+			resp.Duration = u.Seconds
+		}
+		resp.Usage = provider.Usage{
+			PromptTokens:     u.InputTokens,
+			CompletionTokens: u.OutputTokens,
+			TotalTokens:      u.TotalTokens,
+		}
+	}
+	return resp, nil
+}
+
+func (c *client) speech(ctx context.Context, req *provider.SpeechRequest) (*provider.SpeechResponse, error) {
+	payload := map[string]any{
+		"model":           req.Model,
+		"input":           req.Input,
+		"voice":           req.Voice,
+		"response_format": req.Format(),
+	}
+	if req.Instructions != "" {
+		payload["instructions"] = req.Instructions
+	}
+	if req.Speed != 0 {
+		payload["speed"] = req.Speed
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("openai: marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("openai: create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai: request failed: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		_ = httpResp.Body.Close()
+		return nil, fmt.Errorf("openai: API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	contentType := httpResp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = provider.AudioContentType(req.Format())
+	}
+	return &provider.SpeechResponse{
+		Provider:    "openai",
+		Model:       req.Model,
+		ContentType: contentType,
+		Format:      req.Format(),
+		Audio:       httpResp.Body,
+		Characters:  req.Characters(),
+	}, nil
+}
+
+// TranscriptionCost prices a transcript from a model catalog: per second
+// of audio when the model has an audio rate, otherwise by token usage.
+// OpenAI-compatible providers reuse it with their own catalogs.
+func TranscriptionCost(models []provider.Model, resp *provider.TranscriptionResponse) float64 {
+	for _, m := range models {
+		if m.ID != resp.Model {
+			continue
+		}
+		if m.Pricing.AudioPerSecond > 0 {
+			return m.Pricing.AudioCost(resp.Duration, 0)
+		}
+		return float64(resp.Usage.PromptTokens)/1_000_000*m.Pricing.InputPerMillion +
+			float64(resp.Usage.CompletionTokens)/1_000_000*m.Pricing.OutputPerMillion
+	}
+	return 0
+}
+
+// SpeechCost prices synthesized speech per input character from a model
+// catalog.
+func SpeechCost(models []provider.Model, resp *provider.SpeechResponse) float64 {
+	for _, m := range models {
+		if m.ID == resp.Model {
+			return m.Pricing.AudioCost(0, resp.Characters)
+		}
+	}
+	return 0
+}
+
+// Compile-time checks.
+var (
+	_ provider.TranscriptionProvider = (*Provider)(nil)
+	_ provider.SpeechProvider        = (*Provider)(nil)
+)
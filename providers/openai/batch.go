@@ -0,0 +1,281 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// batchDiscount is the price reduction OpenAI applies to Batch API requests.
+const batchDiscount = 0.5
+
+type openAIBatchLine struct {
+	CustomID string         `json:"custom_id"`
+	Method   string         `json:"method"`
+	URL      string         `json:"url"`
+	Body     *openAIRequest `json:"body"`
+}
+
+type openAIBatch struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	OutputFileID  string `json:"output_file_id"`
+	ErrorFileID   string `json:"error_file_id"`
+	RequestCounts struct {
+		Total     int `json:"total"`
+		Completed int `json:"completed"`
+		Failed    int `json:"failed"`
+	} `json:"request_counts"`
+}
+
+type openAIBatchResult struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// SubmitBatch uploads the requests as a JSONL file and starts a batch
+// against /v1/chat/completions.
+func (p *Provider) SubmitBatch(ctx context.Context, reqs []provider.BatchRequest) (string, error) {
+	return p.client.submitBatch(ctx, reqs)
+}
+
+// BatchStatus reports the progress of a batch.
+func (p *Provider) BatchStatus(ctx context.Context, batchID string) (*provider.BatchStatus, error) {
+	b, err := p.client.getBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return &provider.BatchStatus{
+		State:     batchState(b.Status),
+		Total:     b.RequestCounts.Total,
+		Completed: b.RequestCounts.Completed,
+		Failed:    b.RequestCounts.Failed,
+	}, nil
+}
+
+// BatchResults downloads a batch's output and error files.
+func (p *Provider) BatchResults(ctx context.Context, batchID string) ([]provider.BatchResult, error) {
+	b, err := p.client.getBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	var results []provider.BatchResult
+	for _, fileID := range []string{b.OutputFileID, b.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		rs, err := p.client.batchResults(ctx, fileID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, rs...)
+	}
+	return results, nil
+}
+
+// CancelBatch cancels a batch. OpenAI keeps finished results.
+func (p *Provider) CancelBatch(ctx context.Context, batchID string) error {
+	_, err := p.client.doJSON(ctx, "POST", "/batches/"+batchID+"/cancel", nil)
+	return err
+}
+
+// BatchDiscount returns the Batch API discount.
+func (p *Provider) BatchDiscount() float64 { return batchDiscount }
+
+func batchState(status string) string {
+	switch status {
+	case "completed":
+		return provider.BatchStateEnded
+	case "failed":
+		return provider.BatchStateFailed
+	case "expired":
+		return provider.BatchStateExpired
+	case "cancelled":
+		return provider.BatchStateCancelled
+	default: // validating, in_progress, finalizing, cancelling
+		return provider.BatchStateInProgress
+	}
+}
+
+func (c *client) submitBatch(ctx context.Context, reqs []provider.BatchRequest) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range reqs {
+		if err := enc.Encode(openAIBatchLine{
+			CustomID: r.CustomID,
+			Method:   "POST",
+			URL:      "/v1/chat/completions",
+			Body:     c.toOpenAIRequest(r.Request),
+		}); err != nil {
+			return "", fmt.Errorf("openai: encode batch line: %w", err)
+		}
+	}
+
+	fileID, err := c.uploadBatchFile(ctx, buf.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	body, err := c.doJSON(ctx, "POST", "/batches", map[string]string{
+		"input_file_id":     fileID,
+		"endpoint":          "/v1/chat/completions",
+		"completion_window": "24h",
+	})
+	if err != nil {
+		return "", err
+	}
+	var b openAIBatch
+	if err := json.Unmarshal(body, &b); err != nil {
+		return "", fmt.Errorf("openai: decode batch: %w", err)
+	}
+	return b.ID, nil
+}
+
+func (c *client) uploadBatchFile(ctx context.Context, content []byte) (string, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("purpose", "batch"); err != nil {
+		return "", fmt.Errorf("openai: write multipart: %w", err)
+	}
+	part, err := w.CreateFormFile("file", "batch.jsonl")
+	if err != nil {
+		return "", fmt.Errorf("openai: write multipart: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", fmt.Errorf("openai: write multipart: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("openai: write multipart: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/files", &body)
+	if err != nil {
+		return "", fmt.Errorf("openai: create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", w.FormDataContentType())
+
+	respBody, err := c.send(httpReq)
+	if err != nil {
+		return "", err
+	}
+	var f struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &f); err != nil {
+		return "", fmt.Errorf("openai: decode file: %w", err)
+	}
+	return f.ID, nil
+}
+
+func (c *client) getBatch(ctx context.Context, batchID string) (*openAIBatch, error) {
+	body, err := c.doJSON(ctx, "GET", "/batches/"+batchID, nil)
+	if err != nil {
+		return nil, err
+	}
+	var b openAIBatch
+	if err := json.Unmarshal(body, &b); err != nil {
+		return nil, fmt.Errorf("openai: decode batch: %w", err)
+	}
+	return &b, nil
+}
+
+func (c *client) batchResults(ctx context.Context, fileID string) ([]provider.BatchResult, error) {
+	content, err := c.doJSON(ctx, "GET", "/files/"+fileID+"/content", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []provider.BatchResult
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var line openAIBatchResult
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("openai: decode batch result: %w", err)
+		}
+		results = append(results, c.fromBatchResult(&line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("openai: read batch results: %w", err)
+	}
+	return results, nil
+}
+
+func (c *client) fromBatchResult(line *openAIBatchResult) provider.BatchResult {
+	r := provider.BatchResult{CustomID: line.CustomID}
+	switch {
+	case line.Error != nil:
+		r.Error = line.Error.Message
+	case line.Response == nil:
+		r.Error = "missing response"
+	case line.Response.StatusCode != http.StatusOK:
+		r.StatusCode = line.Response.StatusCode
+		r.Error = string(line.Response.Body)
+	default:
+		r.StatusCode = http.StatusOK
+		var oaiResp openAIResponse
+		if err := json.Unmarshal(line.Response.Body, &oaiResp); err != nil {
+			r.Error = "decode response: " + err.Error()
+			break
+		}
+		r.Response = c.fromOpenAIResponse(&oaiResp, 0)
+	}
+	return r
+}
+
+// doJSON sends a JSON request (payload may be nil) and returns the
+// response body.
+func (c *client) doJSON(ctx context.Context, method, path string, payload any) ([]byte, error) {
+	var body io.Reader = http.NoBody
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("openai: marshal request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("openai: create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	return c.send(httpReq)
+}
+
+func (c *client) send(httpReq *http.Request) ([]byte, error) {
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai: request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("openai: read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai: API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
+
+// Compile-time check.
+var _ provider.BatchProvider = (*Provider)(nil)
@@ -43,6 +43,8 @@ type openAIRequest struct {
 	Tools          []provider.Tool          `json:"tools,omitempty"`
 	ToolChoice     any                      `json:"tool_choice,omitempty"`
 	ResponseFormat *provider.ResponseFormat `json:"response_format,omitempty"`
+
+	ReasoningEffort string `json:"reasoning_effort,omitempty"`
 }
 
 type openAIStreamOptions struct {
@@ -70,11 +72,32 @@ type openAIResponse struct {
 		Message      openAIMessage `json:"message"`
 		FinishReason string        `json:"finish_reason"`
 	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
+	Usage openAIUsage `json:"usage"`
+}
+
+// openAIUsage is the usage block shared by completions and the final
+// stream chunk. OpenAI caches long prompt prefixes automatically and
+// reports the hit in prompt_tokens_details.cached_tokens (already counted
+// in prompt_tokens).
+type openAIUsage struct {
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	TotalTokens         int `json:"total_tokens"`
+	PromptTokensDetails *struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details,omitempty"`
+}
+
+func (u *openAIUsage) toUsage() provider.Usage {
+	out := provider.Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if u.PromptTokensDetails != nil {
+		out.CacheReadTokens = u.PromptTokensDetails.CachedTokens
+	}
+	return out
 }
 
 func (c *client) complete(ctx context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error) {
@@ -150,6 +173,9 @@ func (c *client) embed(ctx context.Context, req *provider.EmbeddingRequest) (*pr
 		"model": req.Model,
 		"input": req.Input,
 	}
+	if req.Dimensions > 0 {
+		payload["dimensions"] = req.Dimensions
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -231,6 +257,10 @@ func (c *client) setHeaders(req *http.Request) {
 }
 
 func (c *client) toOpenAIRequest(req *provider.CompletionRequest) *openAIRequest {
+	// OpenAI caches prefixes on its own and rejects unknown fields, so
+	// explicit cache markers are dropped.
+	req = provider.StripCacheControl(req)
+
 	messages := make([]openAIMessage, len(req.Messages))
 	for i, m := range req.Messages {
 		messages[i] = openAIMessage{
@@ -250,7 +280,7 @@ func (c *client) toOpenAIRequest(req *provider.CompletionRequest) *openAIRequest
 		}}, messages...)
 	}
 
-	return &openAIRequest{
+	oaiReq := &openAIRequest{
 		Model:          req.Model,
 		Messages:       messages,
 		MaxTokens:      req.MaxTokens,
@@ -261,6 +291,10 @@ func (c *client) toOpenAIRequest(req *provider.CompletionRequest) *openAIRequest
 		ToolChoice:     req.ToolChoice,
 		ResponseFormat: req.ResponseFormat,
 	}
+	if req.Thinking != nil && req.Thinking.Effort != "" {
+		oaiReq.ReasoningEffort = req.Thinking.Effort
+	}
+	return oaiReq
 }
 
 func (c *client) fromOpenAIResponse(resp *openAIResponse, elapsed time.Duration) *provider.CompletionResponse {
@@ -302,12 +336,8 @@ func (c *client) fromOpenAIResponse(resp *openAIResponse, elapsed time.Duration)
 		Model:    resp.Model,
 		Created:  time.Unix(resp.Created, 0),
 		Choices:  choices,
-		Usage: provider.Usage{
-			PromptTokens:     resp.Usage.PromptTokens,
-			CompletionTokens: resp.Usage.CompletionTokens,
-			TotalTokens:      resp.Usage.TotalTokens,
-		},
-		Latency: elapsed,
+		Usage:    resp.Usage.toUsage(),
+		Latency:  elapsed,
 	}
 }
 
@@ -0,0 +1,130 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// openAIImageRequest is the /images/generations request format.
+type openAIImageRequest struct {
+	Model          string `json:"model,omitempty"`
+	Prompt         string `json:"prompt"`
+	N              int    `json:"n,omitempty"`
+	Size           string `json:"size,omitempty"`
+	Quality        string `json:"quality,omitempty"`
+	Style          string `json:"style,omitempty"`
+	ResponseFormat string `json:"response_format,omitempty"`
+	User           string `json:"user,omitempty"`
+}
+
+type openAIImageResponse struct {
+	Created int64            `json:"created"`
+	Data    []provider.Image `json:"data"`
+	Usage   *struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage,omitempty"`
+}
+
+// GenerateImage creates images with DALL·E or gpt-image models.
+func (p *Provider) GenerateImage(ctx context.Context, req *provider.ImageRequest) (*provider.ImageResponse, error) {
+	return p.client.generateImage(ctx, req)
+}
+
+func (c *client) generateImage(ctx context.Context, req *provider.ImageRequest) (*provider.ImageResponse, error) {
+	payload := toOpenAIImageRequest(req)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("openai: marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/images/generations", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("openai: create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai: request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		return nil, fmt.Errorf("openai: API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var oaiResp openAIImageResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&oaiResp); err != nil {
+		return nil, fmt.Errorf("openai: decode response: %w", err)
+	}
+
+	resp := &provider.ImageResponse{
+		Created:  oaiResp.Created,
+		Provider: "openai",
+		Model:    req.Model,
+		Data:     oaiResp.Data,
+	}
+	if oaiResp.Usage != nil {
+		resp.Usage = provider.Usage{
+			PromptTokens:     oaiResp.Usage.InputTokens,
+			CompletionTokens: oaiResp.Usage.OutputTokens,
+			TotalTokens:      oaiResp.Usage.TotalTokens,
+		}
+	}
+	resp.Cost = imageCost(req, resp)
+	return resp, nil
+}
+
+// toOpenAIImageRequest maps the unified request. gpt-image models always
+// return base64 and reject response_format and style.
+func toOpenAIImageRequest(req *provider.ImageRequest) *openAIImageRequest {
+	out := &openAIImageRequest{
+		Model:          req.Model,
+		Prompt:         req.Prompt,
+		N:              req.N,
+		Size:           req.Size,
+		Quality:        req.Quality,
+		Style:          req.Style,
+		ResponseFormat: req.ResponseFormat,
+		User:           req.User,
+	}
+	if strings.HasPrefix(req.Model, "gpt-image") {
+		out.ResponseFormat = ""
+		out.Style = ""
+	}
+	return out
+}
+
+// imageCost prices a response from the model catalog: token-billed models
+// by usage, the rest per image (doubled for DALL·E 3 HD).
+func imageCost(req *provider.ImageRequest, resp *provider.ImageResponse) float64 {
+	for _, m := range openAIModels() {
+		if m.ID != req.Model {
+			continue
+		}
+		if m.Pricing.PerImage == 0 {
+			return float64(resp.Usage.PromptTokens)/1_000_000*m.Pricing.InputPerMillion +
+				float64(resp.Usage.CompletionTokens)/1_000_000*m.Pricing.OutputPerMillion
+		}
+		price := m.Pricing.PerImage
+		if req.Model == "dall-e-3" && req.Quality == "hd" {
+			price *= 2
+		}
+		return price * float64(len(resp.Data))
+	}
+	return 0
+}
+
+// Compile-time check.
+var _ provider.ImageProvider = (*Provider)(nil)
@@ -9,13 +9,13 @@ func openAIModels() []provider.Model {
 			ID: "gpt-4o", Provider: "openai", Name: "GPT-4o",
 			Capabilities:  provider.Capabilities{Chat: true, Streaming: true, Embeddings: false, Vision: true, Tools: true, JSON: true},
 			ContextWindow: 128000, MaxOutput: 16384,
-			Pricing: provider.Pricing{InputPerMillion: 2.50, OutputPerMillion: 10.00},
+			Pricing: provider.Pricing{InputPerMillion: 2.50, OutputPerMillion: 10.00, CacheReadPerMillion: 1.25},
 		},
 		{
 			ID: "gpt-4o-mini", Provider: "openai", Name: "GPT-4o Mini",
 			Capabilities:  provider.Capabilities{Chat: true, Streaming: true, Embeddings: false, Vision: true, Tools: true, JSON: true},
 			ContextWindow: 128000, MaxOutput: 16384,
-			Pricing: provider.Pricing{InputPerMillion: 0.15, OutputPerMillion: 0.60},
+			Pricing: provider.Pricing{InputPerMillion: 0.15, OutputPerMillion: 0.60, CacheReadPerMillion: 0.075},
 		},
 		{
 			ID: "gpt-4-turbo", Provider: "openai", Name: "GPT-4 Turbo",
@@ -27,13 +27,13 @@ func openAIModels() []provider.Model {
 			ID: "o1", Provider: "openai", Name: "o1",
 			Capabilities:  provider.Capabilities{Chat: true, Streaming: true, Thinking: true, Tools: true, JSON: true},
 			ContextWindow: 200000, MaxOutput: 100000,
-			Pricing: provider.Pricing{InputPerMillion: 15.00, OutputPerMillion: 60.00},
+			Pricing: provider.Pricing{InputPerMillion: 15.00, OutputPerMillion: 60.00, CacheReadPerMillion: 7.50},
 		},
 		{
 			ID: "o1-mini", Provider: "openai", Name: "o1-mini",
 			Capabilities:  provider.Capabilities{Chat: true, Streaming: true, Thinking: true},
 			ContextWindow: 128000, MaxOutput: 65536,
-			Pricing: provider.Pricing{InputPerMillion: 3.00, OutputPerMillion: 12.00},
+			Pricing: provider.Pricing{InputPerMillion: 3.00, OutputPerMillion: 12.00, CacheReadPerMillion: 1.50},
 		},
 		{
 			ID: "text-embedding-3-small", Provider: "openai", Name: "Embedding 3 Small",
@@ -47,5 +47,53 @@ func openAIModels() []provider.Model {
 			ContextWindow: 8191,
 			Pricing:       provider.Pricing{EmbeddingPerMillion: 0.13},
 		},
+		{
+			ID: "gpt-image-1", Provider: "openai", Name: "GPT Image 1",
+			Capabilities:  provider.Capabilities{Images: true},
+			ContextWindow: 32000, // maximum prompt length
+			Pricing:       provider.Pricing{InputPerMillion: 5.00, OutputPerMillion: 40.00},
+		},
+		{
+			ID: "dall-e-3", Provider: "openai", Name: "DALL·E 3",
+			Capabilities:  provider.Capabilities{Images: true},
+			ContextWindow: 4000, // maximum prompt length
+			Pricing:       provider.Pricing{PerImage: 0.04},
+		},
+		{
+			ID: "dall-e-2", Provider: "openai", Name: "DALL·E 2",
+			Capabilities:  provider.Capabilities{Images: true},
+			ContextWindow: 1000, // maximum prompt length
+			Pricing:       provider.Pricing{PerImage: 0.02},
+		},
+		{
+			ID: "whisper-1", Provider: "openai", Name: "Whisper",
+			Capabilities:  provider.Capabilities{Audio: true},
+			ContextWindow: 448,
+			Pricing:       provider.Pricing{AudioPerSecond: 0.0001},
+		},
+		{
+			ID: "gpt-4o-transcribe", Provider: "openai", Name: "GPT-4o Transcribe",
+			Capabilities:  provider.Capabilities{Audio: true},
+			ContextWindow: 16000,
+			Pricing:       provider.Pricing{InputPerMillion: 6.00, OutputPerMillion: 10.00},
+		},
+		{
+			ID: "gpt-4o-mini-transcribe", Provider: "openai", Name: "GPT-4o Mini Transcribe",
+			Capabilities:  provider.Capabilities{Audio: true},
+			ContextWindow: 16000,
+			Pricing:       provider.Pricing{InputPerMillion: 3.00, OutputPerMillion: 5.00},
+		},
+		{
+			ID: "tts-1", Provider: "openai", Name: "TTS 1",
+			Capabilities:  provider.Capabilities{Audio: true},
+			ContextWindow: 4096, // maximum input characters
+			Pricing:       provider.Pricing{CharactersPerMillion: 15.00},
+		},
+		{
+			ID: "tts-1-hd", Provider: "openai", Name: "TTS 1 HD",
+			Capabilities:  provider.Capabilities{Audio: true},
+			ContextWindow: 4096, // maximum input characters
+			Pricing:       provider.Pricing{CharactersPerMillion: 30.00},
+		},
 	}
 }
@@ -49,9 +49,11 @@ func TestOpenAIModels_PositivePricing(t *testing.T) {
 		t.Run(m.ID, func(t *testing.T) {
 			hasChatPricing := m.Pricing.InputPerMillion > 0 || m.Pricing.OutputPerMillion > 0
 			hasEmbedPricing := m.Pricing.EmbeddingPerMillion > 0
+			hasImagePricing := m.Pricing.PerImage > 0
+			hasAudioPricing := m.Pricing.AudioPerSecond > 0 || m.Pricing.CharactersPerMillion > 0
 
-			if !hasChatPricing && !hasEmbedPricing {
-				t.Errorf("model %q must have pricing set (chat or embedding)", m.ID)
+			if !hasChatPricing && !hasEmbedPricing && !hasImagePricing && !hasAudioPricing {
+				t.Errorf("model %q must have pricing set (chat, embedding, image or audio)", m.ID)
 			}
 
 			// Ensure no negative pricing values.
@@ -82,8 +84,8 @@ func TestOpenAIModels_UniqueIDs(t *testing.T) {
 func TestOpenAIModels_ChatModelsHaveChatCapability(t *testing.T) {
 	models := openAIModels()
 	for _, m := range models {
-		// Skip embedding-only models.
-		if m.Capabilities.Embeddings && !m.Capabilities.Chat {
+		// Skip embedding, image and audio models.
+		if (m.Capabilities.Embeddings || m.Capabilities.Images || m.Capabilities.Audio) && !m.Capabilities.Chat {
 			continue
 		}
 		t.Run(m.ID, func(t *testing.T) {
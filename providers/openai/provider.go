@@ -13,6 +13,10 @@ type Provider struct {
 	baseURL string
 	orgID   string
 	client  *client
+
+	creds    provider.CredentialProvider
+	credName string
+	inst     provider.Instance
 }
 
 // New creates a new OpenAI provider.
@@ -25,11 +29,21 @@ func New(apiKey string, opts ...Option) *Provider {
 		opt(p)
 	}
 	p.client = newClient(p.apiKey, p.baseURL, p.orgID)
+	if p.creds != nil {
+		name := p.credName
+		if name == "" {
+			name = p.Name()
+		}
+		p.client.http.Transport = provider.NewCredentialTransport(nil, name, p.creds, provider.BearerAuth)
+	}
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "openai" }
+func (p *Provider) Name() string { return p.inst.NameOr("openai") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "openai" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
@@ -40,7 +54,9 @@ func (p *Provider) Capabilities() provider.Capabilities {
 		Vision:             true,
 		Tools:              true,
 		JSON:               true,
+		JSONSchema:         true,
 		Images:             true,
+		Audio:              true, // transcription + speech
 		Thinking:           true, // o-series
 		Batch:              true,
 		StreamingReasoning: true, // o-series + Responses API
@@ -51,7 +67,7 @@ func (p *Provider) Capabilities() provider.Capabilities {
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return openAIModels(), nil
+	return p.inst.FilterModels(p.Name(), openAIModels()), nil
 }
 
 // Complete sends a chat completion request.
@@ -69,6 +85,12 @@ func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*
 	return p.client.embed(ctx, req)
 }
 
+// EmbeddingLimits reports OpenAI's per-call caps: 2048 inputs and 300K
+// tokens.
+func (p *Provider) EmbeddingLimits(_ string) provider.EmbeddingLimits {
+	return provider.EmbeddingLimits{MaxInputs: 2048, MaxTokens: 300_000}
+}
+
 // Healthy returns true if the provider is reachable.
 func (p *Provider) Healthy(ctx context.Context) bool {
 	return p.client.ping(ctx) == nil
@@ -87,5 +109,31 @@ func WithOrgID(orgID string) Option {
 	return func(p *Provider) { p.orgID = orgID }
 }
 
-// Compile-time check.
-var _ provider.Provider = (*Provider)(nil)
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// WithCredentialName sets the name the key is looked up under in the
+// credential provider. Defaults to "openai"; OpenAI-compatible wrappers set
+// their own name.
+func WithCredentialName(name string) Option {
+	return func(p *Provider) { p.credName = name }
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
+// Compile-time checks.
+var (
+	_ provider.Provider         = (*Provider)(nil)
+	_ provider.EmbeddingLimiter = (*Provider)(nil)
+)
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/xraph/nexus/provider"
@@ -67,6 +69,7 @@ func TestCapabilities(t *testing.T) {
 		{"Tools", caps.Tools},
 		{"JSON", caps.JSON},
 		{"Images", caps.Images},
+		{"Audio", caps.Audio},
 		{"Thinking", caps.Thinking},
 		{"Batch", caps.Batch},
 	}
@@ -75,11 +78,6 @@ func TestCapabilities(t *testing.T) {
 			t.Errorf("capability %s should be true", tc.name)
 		}
 	}
-
-	// Audio is the one capability OpenAI provider does NOT declare.
-	if caps.Audio {
-		t.Error("Audio capability should be false for openai provider")
-	}
 }
 
 // ---------------------------------------------------------------------------
@@ -382,6 +380,239 @@ func TestEmbed_APIError(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// GenerateImage (mock server)
+// ---------------------------------------------------------------------------
+
+func TestGenerateImage(t *testing.T) {
+	mock := testutil.NewMockServer(t)
+	mock.Ctrl.SetCompletion(map[string]any{
+		"created": 1700000000,
+		"data": []map[string]any{
+			{"url": "https://example.com/a.png", "revised_prompt": "a red fox"},
+			{"url": "https://example.com/b.png"},
+		},
+	})
+
+	p := New("test-key", WithBaseURL(mock.Server.URL))
+	resp, err := p.GenerateImage(context.Background(), &provider.ImageRequest{
+		Model:   "dall-e-3",
+		Prompt:  "a fox",
+		N:       2,
+		Quality: "hd",
+	})
+	if err != nil {
+		t.Fatalf("GenerateImage() error: %v", err)
+	}
+	if len(resp.Data) != 2 {
+		t.Fatalf("len(Data) = %d, want 2", len(resp.Data))
+	}
+	if resp.Data[0].RevisedPrompt != "a red fox" {
+		t.Errorf("RevisedPrompt = %q, want %q", resp.Data[0].RevisedPrompt, "a red fox")
+	}
+	// Two HD images at twice the standard $0.04.
+	if resp.Cost < 0.159 || resp.Cost > 0.161 {
+		t.Errorf("Cost = %v, want 0.16", resp.Cost)
+	}
+	if got := mock.Ctrl.GetLastPath(); got != "/images/generations" {
+		t.Errorf("request path = %q, want %q", got, "/images/generations")
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(mock.Ctrl.GetLastBody(), &body); err != nil {
+		t.Fatalf("unmarshal request body: %v", err)
+	}
+	if body["prompt"] != "a fox" || body["quality"] != "hd" {
+		t.Errorf("unexpected request body: %v", body)
+	}
+}
+
+func TestGenerateImage_APIError(t *testing.T) {
+	mock := testutil.NewMockServer(t)
+	mock.Ctrl.SetStatusCode(http.StatusBadRequest)
+	p := New("test-key", WithBaseURL(mock.Server.URL))
+
+	_, err := p.GenerateImage(context.Background(), &provider.ImageRequest{
+		Model:  "dall-e-3",
+		Prompt: "a fox",
+	})
+	if err == nil {
+		t.Fatal("expected error for 400 status")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Audio (mock server)
+// ---------------------------------------------------------------------------
+
+func TestTranscribe(t *testing.T) {
+	mock := testutil.NewMockServer(t)
+	mock.Ctrl.SetCompletion(map[string]any{
+		"text":     "hello there",
+		"language": "english",
+		"duration": 30.0,
+		"segments": []map[string]any{{"id": 0, "start": 0.0, "end": 1.5, "text": "hello there"}},
+	})
+
+	p := New("test-key", WithBaseURL(mock.Server.URL))
+	resp, err := p.Transcribe(context.Background(), &provider.TranscriptionRequest{
+		Model:    "whisper-1",
+		File:     []byte("RIFF...."),
+		Filename: "clip.wav",
+		Language: "en",
+	})
+	if err != nil {
+		t.Fatalf("Transcribe() error: %v", err)
+	}
+	if resp.Text != "hello there" || len(resp.Segments) != 1 {
+		t.Errorf("resp = %+v", resp)
+	}
+	// 30 seconds at $0.006/minute.
+	if resp.Cost < 0.00299 || resp.Cost > 0.00301 {
+		t.Errorf("Cost = %v, want 0.003", resp.Cost)
+	}
+	if got := mock.Ctrl.GetLastPath(); got != "/audio/transcriptions" {
+		t.Errorf("request path = %q, want %q", got, "/audio/transcriptions")
+	}
+	ct := mock.Ctrl.GetLastHeader().Get("Content-Type")
+	if !strings.HasPrefix(ct, "multipart/form-data") {
+		t.Fatalf("Content-Type = %q, want multipart/form-data", ct)
+	}
+	body := string(mock.Ctrl.GetLastBody())
+	for _, want := range []string{`filename="clip.wav"`, "verbose_json", "RIFF...."} {
+		if !strings.Contains(body, want) {
+			t.Errorf("multipart body missing %q", want)
+		}
+	}
+}
+
+func TestTranscribe_Translate(t *testing.T) {
+	mock := testutil.NewMockServer(t)
+	mock.Ctrl.SetCompletion(map[string]any{"text": "hello"})
+
+	p := New("test-key", WithBaseURL(mock.Server.URL))
+	if _, err := p.Transcribe(context.Background(), &provider.TranscriptionRequest{
+		Model:     "whisper-1",
+		File:      []byte("audio"),
+		Translate: true,
+	}); err != nil {
+		t.Fatalf("Transcribe() error: %v", err)
+	}
+	if got := mock.Ctrl.GetLastPath(); got != "/audio/translations" {
+		t.Errorf("request path = %q, want %q", got, "/audio/translations")
+	}
+}
+
+func TestBatch(t *testing.T) {
+	var submitted string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /files", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("purpose") != "batch" {
+			t.Errorf("purpose = %q", r.FormValue("purpose"))
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(f)
+		submitted = string(content)
+		_, _ = io.WriteString(w, `{"id":"file-in"}`)
+	})
+	mux.HandleFunc("POST /batches", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["input_file_id"] != "file-in" || body["endpoint"] != "/v1/chat/completions" {
+			t.Errorf("create batch body = %v", body)
+		}
+		_, _ = io.WriteString(w, `{"id":"batch_1","status":"validating"}`)
+	})
+	mux.HandleFunc("GET /batches/batch_1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"id":"batch_1","status":"completed","output_file_id":"file-out","error_file_id":"file-err",
+			"request_counts":{"total":2,"completed":1,"failed":1}}`)
+	})
+	mux.HandleFunc("GET /files/file-out/content", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"id":"l1","custom_id":"a","response":{"status_code":200,"body":{"id":"chatcmpl-1","model":"gpt-4o-mini","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":4,"completion_tokens":1,"total_tokens":5}}},"error":null}`+"\n")
+	})
+	mux.HandleFunc("GET /files/file-err/content", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"id":"l2","custom_id":"b","response":{"status_code":400,"body":{"error":{"message":"bad"}}},"error":null}`+"\n")
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	p := New("test-key", WithBaseURL(server.URL))
+	ctx := context.Background()
+	req := &provider.CompletionRequest{Model: "gpt-4o-mini", Messages: []provider.Message{{Role: "user", Content: "hello"}}}
+	batchID, err := p.SubmitBatch(ctx, []provider.BatchRequest{{CustomID: "a", Request: req}, {CustomID: "b", Request: req}})
+	if err != nil {
+		t.Fatalf("SubmitBatch() error: %v", err)
+	}
+	if batchID != "batch_1" || strings.Count(submitted, "\n") != 2 || !strings.Contains(submitted, `"custom_id":"a"`) {
+		t.Fatalf("batch = %q, submitted = %q", batchID, submitted)
+	}
+
+	status, err := p.BatchStatus(ctx, batchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.State != provider.BatchStateEnded || status.Completed != 1 || status.Failed != 1 {
+		t.Errorf("status = %+v", status)
+	}
+
+	results, err := p.BatchResults(ctx, batchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %d, want 2", len(results))
+	}
+	if r := results[0]; r.CustomID != "a" || r.Response == nil || r.Response.Usage.TotalTokens != 5 {
+		t.Errorf("result a = %+v", r)
+	}
+	if r := results[1]; r.CustomID != "b" || r.StatusCode != 400 || r.Response != nil {
+		t.Errorf("result b = %+v", r)
+	}
+	if p.BatchDiscount() != 0.5 {
+		t.Errorf("BatchDiscount() = %v", p.BatchDiscount())
+	}
+}
+
+func TestSpeech(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/speech" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		_, _ = w.Write([]byte("ID3-audio-bytes"))
+	}))
+	t.Cleanup(server.Close)
+
+	p := New("test-key", WithBaseURL(server.URL))
+	resp, err := p.Speech(context.Background(), &provider.SpeechRequest{
+		Model: "tts-1",
+		Input: "Hello, world!",
+		Voice: "alloy",
+	})
+	if err != nil {
+		t.Fatalf("Speech() error: %v", err)
+	}
+	defer func() { _ = resp.Audio.Close() }()
+
+	audio, err := io.ReadAll(resp.Audio)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(audio) != "ID3-audio-bytes" {
+		t.Errorf("audio = %q", audio)
+	}
+	if resp.Characters != 13 || resp.ContentType != "audio/mpeg" {
+		t.Errorf("resp = %+v", resp)
+	}
+	// 13 characters at $15 per million.
+	if want := 13 * 15.0 / 1_000_000; resp.Cost != want {
+		t.Errorf("Cost = %v, want %v", resp.Cost, want)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Healthy (mock server)
 // ---------------------------------------------------------------------------
@@ -483,3 +714,35 @@ func joinStrings(ss []string) string {
 	}
 	return out
 }
+
+func TestWithCredentials_FailsOverToNextKey(t *testing.T) {
+	var auths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		auths = append(auths, auth)
+		if auth != "Bearer sk-good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":"c1","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`)
+	}))
+	defer srv.Close()
+
+	pool := provider.NewKeyPool(provider.NewStaticCredential(map[string]string{"groq": "sk-revoked\nsk-good"}))
+	p := New("sk-static", WithBaseURL(srv.URL), WithCredentials(pool), WithCredentialName("groq"))
+
+	resp, err := p.Complete(context.Background(), &provider.CompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []provider.Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error: %v", err)
+	}
+	if resp.Choices[0].Message.Content != "hi" {
+		t.Errorf("content = %q", resp.Choices[0].Message.Content)
+	}
+	if strings.Join(auths, ",") != "Bearer sk-revoked,Bearer sk-good" {
+		t.Errorf("auth attempts = %v", auths)
+	}
+}
@@ -9,6 +9,7 @@ package openairealtime
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/xraph/nexus/provider"
 )
@@ -22,6 +23,9 @@ type Provider struct {
 	// dial lets tests inject a fake WebSocket dialer that doesn't hit the
 	// network. Production code calls dialDefault.
 	dial dialFunc
+
+	creds provider.CredentialProvider
+	inst  provider.Instance
 }
 
 // Option configures the Realtime provider.
@@ -33,6 +37,14 @@ func WithBaseURL(u string) Option { return func(p *Provider) { p.baseURL = u } }
 // WithModel sets the Realtime session's default model.
 func WithModel(m string) Option { return func(p *Provider) { p.model = m } }
 
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option { return func(p *Provider) { p.inst.Name = name } }
+
+// WithCredentials resolves the API key from cp each time a session opens.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
 // withDialer is internal — used by tests to inject a fake transport.
 func withDialer(d dialFunc) Option { return func(p *Provider) { p.dial = d } }
 
@@ -51,7 +63,10 @@ func New(apiKey string, opts ...Option) *Provider {
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "openai-realtime" }
+func (p *Provider) Name() string { return p.inst.NameOr("openai-realtime") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "openai-realtime" }
 
 // Capabilities reports the streaming feature matrix.
 func (p *Provider) Capabilities() provider.Capabilities {
@@ -89,7 +104,11 @@ func (p *Provider) CompleteStream(ctx context.Context, req *provider.CompletionR
 	if model == "" {
 		model = p.model
 	}
-	conn, err := p.dial(ctx, p.baseURL, p.apiKey, model)
+	apiKey, err := p.credential(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := p.dial(ctx, p.baseURL, apiKey, model)
 	if err != nil {
 		return nil, err
 	}
@@ -106,5 +125,18 @@ func (p *Provider) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*prov
 // rely on CompleteStream to surface connection errors.
 func (p *Provider) Healthy(_ context.Context) bool { return true }
 
+// credential returns the key for a new session: the credential provider's
+// current key when one is configured, otherwise the static key.
+func (p *Provider) credential(ctx context.Context) (string, error) {
+	if p.creds == nil {
+		return p.apiKey, nil
+	}
+	key, err := p.creds.GetCredential(ctx, p.Name())
+	if err != nil {
+		return "", fmt.Errorf("openairealtime: credential: %w", err)
+	}
+	return key, nil
+}
+
 // Compile-time check.
 var _ provider.Provider = (*Provider)(nil)
@@ -0,0 +1,38 @@
+package opencompat
+
+import (
+	"context"
+
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/providers/openai"
+)
+
+// Transcribe sends a transcription or translation request to the
+// backend's /audio endpoints. Enable Capabilities.Audio with
+// WithCapabilities for the gateway to route audio requests here.
+func (p *Provider) Transcribe(ctx context.Context, req *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error) {
+	resp, err := p.inner.Transcribe(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Provider = p.name
+	resp.Cost = openai.TranscriptionCost(p.models, resp)
+	return resp, nil
+}
+
+// Speech sends a text-to-speech request to the backend's /audio/speech.
+func (p *Provider) Speech(ctx context.Context, req *provider.SpeechRequest) (*provider.SpeechResponse, error) {
+	resp, err := p.inner.Speech(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Provider = p.name
+	resp.Cost = openai.SpeechCost(p.models, resp)
+	return resp, nil
+}
+
+// Compile-time checks.
+var (
+	_ provider.TranscriptionProvider = (*Provider)(nil)
+	_ provider.SpeechProvider        = (*Provider)(nil)
+)
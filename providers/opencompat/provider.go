@@ -16,13 +16,13 @@ type Provider struct {
 	inner  *openai.Provider
 	caps   provider.Capabilities
 	models []provider.Model
+	creds  provider.CredentialProvider
 }
 
 // New creates a generic OpenAI-compatible provider.
 func New(name, baseURL, apiKey string, opts ...Option) *Provider {
 	p := &Provider{
-		name:  name,
-		inner: openai.New(apiKey, openai.WithBaseURL(baseURL)),
+		name: name,
 		caps: provider.Capabilities{
 			Chat:      true,
 			Streaming: true,
@@ -31,12 +31,20 @@ func New(name, baseURL, apiKey string, opts ...Option) *Provider {
 	for _, opt := range opts {
 		opt(p)
 	}
+	innerOpts := []openai.Option{openai.WithBaseURL(baseURL)}
+	if p.creds != nil {
+		innerOpts = append(innerOpts, openai.WithCredentials(p.creds), openai.WithCredentialName(name))
+	}
+	p.inner = openai.New(apiKey, innerOpts...)
 	return p
 }
 
 // Name returns the provider identifier.
 func (p *Provider) Name() string { return p.name }
 
+// Type returns "opencompat"; the name given to New is the instance name.
+func (p *Provider) Type() string { return "opencompat" }
+
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities { return p.caps }
 
@@ -88,5 +96,10 @@ func WithModels(models []provider.Model) Option {
 	return func(p *Provider) { p.models = models }
 }
 
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
 // Compile-time check.
 var _ provider.Provider = (*Provider)(nil)
@@ -417,6 +417,34 @@ func TestEmbed_ErrorFromServer(t *testing.T) {
 	}
 }
 
+func TestTranscribe_DelegatesToInnerProvider(t *testing.T) {
+	mock := testutil.NewMockServer(t)
+	mock.Ctrl.SetCompletion(map[string]any{"text": "hello", "duration": 10.0})
+
+	p := opencompat.New("my-stt", mock.Server.URL, "test-key",
+		opencompat.WithModels([]provider.Model{{
+			ID: "stt-1", Provider: "my-stt",
+			Pricing: provider.Pricing{AudioPerSecond: 0.001},
+		}}),
+	)
+	resp, err := p.Transcribe(context.Background(), &provider.TranscriptionRequest{
+		Model: "stt-1",
+		File:  []byte("audio"),
+	})
+	if err != nil {
+		t.Fatalf("Transcribe() returned error: %v", err)
+	}
+	if resp.Provider != "my-stt" {
+		t.Errorf("Provider = %q, want %q", resp.Provider, "my-stt")
+	}
+	if resp.Cost < 0.00999 || resp.Cost > 0.01001 {
+		t.Errorf("Cost = %v, want 0.01", resp.Cost)
+	}
+	if got := mock.Ctrl.GetLastPath(); got != "/audio/transcriptions" {
+		t.Errorf("request path = %q, want %q", got, "/audio/transcriptions")
+	}
+}
+
 func TestHealthy_DelegatesToInnerProvider(t *testing.T) {
 	mock := testutil.NewMockServer(t)
 	p := opencompat.New("test", mock.Server.URL, "key")
@@ -19,6 +19,8 @@ type Provider struct {
 	siteURL  string
 	siteName string
 	models   []provider.Model
+	creds    provider.CredentialProvider
+	inst     provider.Instance
 }
 
 // New creates a new OpenRouter provider.
@@ -34,13 +36,19 @@ func New(apiKey string, opts ...Option) *Provider {
 
 	// OpenRouter requires extra headers, so we use a custom HTTP transport.
 	openaiOpts := []openai.Option{openai.WithBaseURL(baseURL)}
+	if p.creds != nil {
+		openaiOpts = append(openaiOpts, openai.WithCredentials(p.creds), openai.WithCredentialName(p.Name()))
+	}
 	p.inner = openai.New(apiKey, openaiOpts...)
 	p.models = openRouterModels()
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "openrouter" }
+func (p *Provider) Name() string { return p.inst.NameOr("openrouter") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "openrouter" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
@@ -56,7 +64,7 @@ func (p *Provider) Capabilities() provider.Capabilities {
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return p.models, nil
+	return p.inst.FilterModels(p.Name(), p.models), nil
 }
 
 // Complete sends a chat completion request.
@@ -65,7 +73,7 @@ func (p *Provider) Complete(ctx context.Context, req *provider.CompletionRequest
 	if err != nil {
 		return nil, err
 	}
-	resp.Provider = "openrouter"
+	resp.Provider = p.Name()
 	return resp, nil
 }
 
@@ -80,7 +88,7 @@ func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	resp.Provider = "openrouter"
+	resp.Provider = p.Name()
 	return resp, nil
 }
 
@@ -97,6 +105,11 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
 // WithSiteURL sets the HTTP-Referer header for OpenRouter rankings.
 func WithSiteURL(url string) Option {
 	return func(p *Provider) { p.siteURL = url }
@@ -107,5 +120,16 @@ func WithSiteName(name string) Option {
 	return func(p *Provider) { p.siteName = name }
 }
 
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
 // Compile-time check.
 var _ provider.Provider = (*Provider)(nil)
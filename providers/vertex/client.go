@@ -109,11 +109,18 @@ type geminiUsage struct {
 // Embedding types.
 
 type vertexEmbedRequest struct {
-	Instances []vertexEmbedInstance `json:"instances"`
+	Instances  []vertexEmbedInstance `json:"instances"`
+	Parameters *vertexEmbedParams    `json:"parameters,omitempty"`
 }
 
 type vertexEmbedInstance struct {
-	Content string `json:"content"`
+	Content  string `json:"content"`
+	TaskType string `json:"task_type,omitempty"`
+}
+
+type vertexEmbedParams struct {
+	OutputDimensionality int   `json:"outputDimensionality,omitempty"`
+	AutoTruncate         *bool `json:"autoTruncate,omitempty"`
 }
 
 type vertexEmbedResponse struct {
@@ -209,10 +216,17 @@ func (c *client) completeStream(ctx context.Context, req *provider.CompletionReq
 func (c *client) embed(ctx context.Context, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
 	instances := make([]vertexEmbedInstance, len(req.Input))
 	for i, text := range req.Input {
-		instances[i] = vertexEmbedInstance{Content: text}
+		instances[i] = vertexEmbedInstance{Content: text, TaskType: vertexTaskType(req.InputType)}
 	}
 
 	embedReq := vertexEmbedRequest{Instances: instances}
+	if req.Dimensions > 0 || req.Truncate == "none" {
+		embedReq.Parameters = &vertexEmbedParams{OutputDimensionality: req.Dimensions}
+		if req.Truncate == "none" {
+			autoTruncate := false
+			embedReq.Parameters.AutoTruncate = &autoTruncate
+		}
+	}
 	body, err := json.Marshal(embedReq)
 	if err != nil {
 		return nil, fmt.Errorf("vertex: marshal embed request: %w", err)
@@ -588,3 +602,19 @@ func (s *vertexStream) Usage() *provider.Usage {
 
 // Compile-time check.
 var _ provider.Stream = (*vertexStream)(nil)
+
+// vertexTaskType maps a unified embedding input type to a Vertex task type.
+func vertexTaskType(t string) string {
+	switch t {
+	case provider.EmbeddingInputQuery:
+		return "RETRIEVAL_QUERY"
+	case provider.EmbeddingInputDocument:
+		return "RETRIEVAL_DOCUMENT"
+	case provider.EmbeddingInputClassification:
+		return "CLASSIFICATION"
+	case provider.EmbeddingInputClustering:
+		return "CLUSTERING"
+	default:
+		return ""
+	}
+}
@@ -23,5 +23,17 @@ func vertexModels() []provider.Model {
 			ContextWindow: 2048,
 			Pricing:       provider.Pricing{EmbeddingPerMillion: 0.025},
 		},
+		{
+			ID: "imagen-3.0-generate-002", Provider: "vertex", Name: "Imagen 3",
+			Capabilities:  provider.Capabilities{Images: true},
+			ContextWindow: 480, // maximum prompt tokens
+			Pricing:       provider.Pricing{PerImage: 0.04},
+		},
+		{
+			ID: "imagen-3.0-fast-generate-001", Provider: "vertex", Name: "Imagen 3 Fast",
+			Capabilities:  provider.Capabilities{Images: true},
+			ContextWindow: 480, // maximum prompt tokens
+			Pricing:       provider.Pricing{PerImage: 0.02},
+		},
 	}
 }
@@ -17,6 +17,9 @@ type Provider struct {
 	credentialsJSON []byte
 	baseURL         string
 	client          *client
+
+	creds provider.CredentialProvider
+	inst  provider.Instance
 }
 
 // New creates a new Vertex AI provider.
@@ -28,11 +31,17 @@ func New(opts ...Option) *Provider {
 		opt(p)
 	}
 	p.client = newClient(p.projectID, p.location, p.accessToken, p.credentialsJSON, p.baseURL)
+	if p.creds != nil {
+		p.client.http.Transport = provider.NewCredentialTransport(nil, p.Name(), p.creds, provider.BearerAuth)
+	}
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "vertex" }
+func (p *Provider) Name() string { return p.inst.NameOr("vertex") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "vertex" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
@@ -40,6 +49,7 @@ func (p *Provider) Capabilities() provider.Capabilities {
 		Chat:       true,
 		Streaming:  true,
 		Embeddings: true,
+		Images:     true,
 		Vision:     true,
 		Tools:      true,
 		JSON:       true,
@@ -48,7 +58,7 @@ func (p *Provider) Capabilities() provider.Capabilities {
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return vertexModels(), nil
+	return p.inst.FilterModels(p.Name(), vertexModels()), nil
 }
 
 // Complete sends a chat completion request.
@@ -66,6 +76,12 @@ func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*
 	return p.client.embed(ctx, req)
 }
 
+// EmbeddingLimits reports Vertex's per-call caps: 250 instances and 20K
+// tokens.
+func (p *Provider) EmbeddingLimits(_ string) provider.EmbeddingLimits {
+	return provider.EmbeddingLimits{MaxInputs: 250, MaxTokens: 20_000}
+}
+
 // Healthy returns true if the provider is reachable.
 func (p *Provider) Healthy(ctx context.Context) bool {
 	return p.client.ping(ctx) == nil
@@ -100,5 +116,26 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
-// Compile-time check.
-var _ provider.Provider = (*Provider)(nil)
+// WithCredentials resolves an OAuth access token from cp on every request,
+// taking precedence over WithAccessToken and WithCredentialsJSON. Use it
+// with a source kept fresh by an external token refresher.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
+// Compile-time checks.
+var (
+	_ provider.Provider         = (*Provider)(nil)
+	_ provider.EmbeddingLimiter = (*Provider)(nil)
+)
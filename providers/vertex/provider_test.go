@@ -232,6 +232,49 @@ func TestEmbed(t *testing.T) {
 	}
 }
 
+func TestGenerateImage(t *testing.T) {
+	var got imagenRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/test-project/locations/us-central1/publishers/google/models/imagen-3.0-generate-002:predict" {
+			t.Errorf("path=%q", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"predictions": []map[string]any{
+				{"bytesBase64Encoded": "aW1n", "mimeType": "image/png"},
+				{"bytesBase64Encoded": "aW1nMg==", "mimeType": "image/png"},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	p := New(
+		WithAccessToken("test-token"),
+		WithProjectID("test-project"),
+		WithBaseURL(server.URL),
+	)
+	resp, err := p.GenerateImage(context.Background(), &provider.ImageRequest{
+		Model:          "imagen-3.0-generate-002",
+		Prompt:         "a fox",
+		NegativePrompt: "blurry",
+		N:              2,
+		Size:           "1792x1008",
+	})
+	if err != nil {
+		t.Fatalf("GenerateImage() error: %v", err)
+	}
+	if got.Parameters.SampleCount != 2 || got.Parameters.AspectRatio != "16:9" || got.Parameters.NegativePrompt != "blurry" {
+		t.Errorf("parameters=%+v", got.Parameters)
+	}
+	if len(resp.Data) != 2 || resp.Data[0].B64JSON != "aW1n" {
+		t.Errorf("Data=%+v", resp.Data)
+	}
+	if resp.Cost != 0.08 {
+		t.Errorf("Cost=%v, want 0.08", resp.Cost)
+	}
+}
+
 func TestHealthy(t *testing.T) {
 	server := vertexMockServer(t)
 	p := New(
@@ -33,6 +33,15 @@ func (c *client) embed(ctx context.Context, req *provider.EmbeddingRequest) (*pr
 		"model": req.Model,
 		"input": req.Input,
 	}
+	if req.InputType == provider.EmbeddingInputQuery || req.InputType == provider.EmbeddingInputDocument {
+		payload["input_type"] = req.InputType
+	}
+	if req.Dimensions > 0 {
+		payload["output_dimension"] = req.Dimensions
+	}
+	if req.Truncate == "none" {
+		payload["truncation"] = false
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
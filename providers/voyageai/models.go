@@ -29,5 +29,17 @@ func voyageAIModels() []provider.Model {
 			ContextWindow: 32000,
 			Pricing:       provider.Pricing{EmbeddingPerMillion: 0.06},
 		},
+		{
+			ID: "rerank-2", Provider: "voyageai", Name: "Rerank 2",
+			Capabilities:  provider.Capabilities{Rerank: true},
+			ContextWindow: 16000,
+			Pricing:       provider.Pricing{InputPerMillion: 0.05},
+		},
+		{
+			ID: "rerank-2-lite", Provider: "voyageai", Name: "Rerank 2 Lite",
+			Capabilities:  provider.Capabilities{Rerank: true},
+			ContextWindow: 8000,
+			Pricing:       provider.Pricing{InputPerMillion: 0.02},
+		},
 	}
 }
@@ -1,10 +1,11 @@
 // Package voyageai provides a Voyage AI provider implementation for Nexus.
-// Voyage AI specializes in high-quality text embeddings and does not support
-// chat completions.
+// Voyage AI specializes in high-quality text embeddings and reranking and
+// does not support chat completions.
 package voyageai
 
 import (
 	"context"
+	"strings"
 
 	"github.com/xraph/nexus/provider"
 )
@@ -16,6 +17,9 @@ type Provider struct {
 	apiKey  string
 	baseURL string
 	client  *client
+
+	creds provider.CredentialProvider
+	inst  provider.Instance
 }
 
 // New creates a new Voyage AI provider.
@@ -28,22 +32,29 @@ func New(apiKey string, opts ...Option) *Provider {
 		opt(p)
 	}
 	p.client = newClient(p.apiKey, p.baseURL)
+	if p.creds != nil {
+		p.client.http.Transport = provider.NewCredentialTransport(nil, p.Name(), p.creds, provider.BearerAuth)
+	}
 	return p
 }
 
 // Name returns the provider identifier.
-func (p *Provider) Name() string { return "voyageai" }
+func (p *Provider) Name() string { return p.inst.NameOr("voyageai") }
+
+// Type returns the provider type, shared by every named instance.
+func (p *Provider) Type() string { return "voyageai" }
 
 // Capabilities returns what this provider supports.
 func (p *Provider) Capabilities() provider.Capabilities {
 	return provider.Capabilities{
 		Embeddings: true,
+		Rerank:     true,
 	}
 }
 
 // Models returns the list of available models.
 func (p *Provider) Models(_ context.Context) ([]provider.Model, error) {
-	return voyageAIModels(), nil
+	return p.inst.FilterModels(p.Name(), voyageAIModels()), nil
 }
 
 // Complete is not supported by Voyage AI.
@@ -61,6 +72,19 @@ func (p *Provider) Embed(ctx context.Context, req *provider.EmbeddingRequest) (*
 	return p.client.embed(ctx, req)
 }
 
+// EmbeddingLimits reports Voyage's per-call caps: 128 inputs, and a token
+// budget that depends on the model size.
+func (p *Provider) EmbeddingLimits(model string) provider.EmbeddingLimits {
+	limits := provider.EmbeddingLimits{MaxInputs: 128, MaxTokens: 120_000}
+	switch {
+	case strings.HasSuffix(model, "-lite"):
+		limits.MaxTokens = 1_000_000
+	case model == "voyage-3" || model == "voyage-3.5":
+		limits.MaxTokens = 320_000
+	}
+	return limits
+}
+
 // Healthy returns true if the provider is reachable.
 func (p *Provider) Healthy(ctx context.Context) bool {
 	return p.client.ping(ctx) == nil
@@ -74,5 +98,24 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
-// Compile-time check.
-var _ provider.Provider = (*Provider)(nil)
+// WithCredentials resolves the API key from cp on every request.
+func WithCredentials(cp provider.CredentialProvider) Option {
+	return func(p *Provider) { p.creds = cp }
+}
+
+// WithName registers the provider under an instance name; see provider.Instance.
+func WithName(name string) Option {
+	return func(p *Provider) { p.inst.Name = name }
+}
+
+// WithModelIDs limits this instance to the given models. IDs outside the
+// built-in catalog are accepted, for custom deployments.
+func WithModelIDs(ids ...string) Option {
+	return func(p *Provider) { p.inst.Models = ids }
+}
+
+// Compile-time checks.
+var (
+	_ provider.Provider         = (*Provider)(nil)
+	_ provider.EmbeddingLimiter = (*Provider)(nil)
+)
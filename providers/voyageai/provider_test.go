@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -41,6 +42,9 @@ func TestCapabilities(t *testing.T) {
 	if !caps.Embeddings {
 		t.Error("expected Embeddings capability")
 	}
+	if !caps.Rerank {
+		t.Error("expected Rerank capability")
+	}
 	if caps.Chat {
 		t.Error("expected Chat to be false")
 	}
@@ -62,8 +66,8 @@ func TestModels(t *testing.T) {
 		if m.Provider != "voyageai" {
 			t.Errorf("model %q provider=%q, want %q", m.ID, m.Provider, "voyageai")
 		}
-		if !m.Capabilities.Embeddings {
-			t.Errorf("model %q should have Embeddings capability", m.ID)
+		if !m.Capabilities.Embeddings && !m.Capabilities.Rerank {
+			t.Errorf("model %q should have Embeddings or Rerank capability", m.ID)
 		}
 	}
 }
@@ -93,6 +97,43 @@ func TestEmbed(t *testing.T) {
 	}
 }
 
+func TestRerank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/rerank" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["top_k"] != float64(1) || body["return_documents"] != true {
+			t.Errorf("body = %v", body)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data":  []map[string]any{{"index": 1, "relevance_score": 0.9, "document": "Paris is the capital of France"}},
+			"model": "rerank-2",
+			"usage": map[string]any{"total_tokens": 20},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	p := New("test-key", WithBaseURL(server.URL))
+	resp, err := p.Rerank(context.Background(), &provider.RerankRequest{
+		Model:           "rerank-2",
+		Query:           "capital of France",
+		Documents:       []string{"Berlin is in Germany", "Paris is the capital of France"},
+		TopN:            1,
+		ReturnDocuments: true,
+	})
+	if err != nil {
+		t.Fatalf("Rerank() error: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Index != 1 || resp.Results[0].Document.Text != "Paris is the capital of France" {
+		t.Errorf("results = %+v", resp.Results)
+	}
+	if resp.Usage.TotalTokens != 20 || math.Abs(resp.Cost-1e-6) > 1e-12 {
+		t.Errorf("usage = %+v, cost = %v", resp.Usage, resp.Cost)
+	}
+}
+
 func TestCompleteNotSupported(t *testing.T) {
 	p := New("test-key")
 	_, err := p.Complete(context.Background(), &provider.CompletionRequest{
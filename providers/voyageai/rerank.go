@@ -0,0 +1,98 @@
+package voyageai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/nexus/provider"
+)
+
+type voyageRerankResponse struct {
+	Data []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+		Document       string  `json:"document"`
+	} `json:"data"`
+	Model string `json:"model"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// Rerank scores documents against a query. Voyage bills rerank calls per
+// token.
+func (p *Provider) Rerank(ctx context.Context, req *provider.RerankRequest) (*provider.RerankResponse, error) {
+	return p.client.rerank(ctx, req)
+}
+
+func (c *client) rerank(ctx context.Context, req *provider.RerankRequest) (*provider.RerankResponse, error) {
+	payload := map[string]any{
+		"model":     req.Model,
+		"query":     req.Query,
+		"documents": req.Documents,
+	}
+	if req.TopN > 0 {
+		payload["top_k"] = req.TopN
+	}
+	if req.ReturnDocuments {
+		payload["return_documents"] = true
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("voyageai: marshal rerank request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v1/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("voyageai: create rerank request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("voyageai: rerank request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body) //nolint:errcheck // best-effort read for error message
+		return nil, fmt.Errorf("voyageai: rerank API error (status %d): %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var resp voyageRerankResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("voyageai: decode rerank response: %w", err)
+	}
+
+	results := make([]provider.RerankResult, len(resp.Data))
+	for i, d := range resp.Data {
+		results[i] = provider.RerankResult{Index: d.Index, RelevanceScore: d.RelevanceScore}
+		if req.ReturnDocuments {
+			results[i].Document = &provider.RerankDocument{Text: d.Document}
+		}
+	}
+	out := &provider.RerankResponse{
+		Provider: "voyageai",
+		Model:    req.Model,
+		Results:  results,
+		Usage: provider.Usage{
+			PromptTokens: resp.Usage.TotalTokens,
+			TotalTokens:  resp.Usage.TotalTokens,
+		},
+	}
+	for _, m := range voyageAIModels() {
+		if m.ID == req.Model {
+			out.Cost = m.Pricing.RerankCost(0, resp.Usage.TotalTokens)
+			break
+		}
+	}
+	return out, nil
+}
+
+// Compile-time check.
+var _ provider.RerankProvider = (*Provider)(nil)
@@ -54,10 +54,13 @@ func TestProviderContract(t *testing.T, p provider.Provider) {
 				t.Errorf("model %q ContextWindow must be positive, got %d", m.ID, m.ContextWindow)
 			}
 
-			// Models should have pricing set (except embeddings-only which use EmbeddingPerMillion).
+			// Models should have pricing set (embeddings-only models use
+			// EmbeddingPerMillion, image, audio and rerank models may use
+			// their per-unit rates).
 			hasChatPricing := m.Pricing.InputPerMillion > 0 || m.Pricing.OutputPerMillion > 0
 			hasEmbedPricing := m.Pricing.EmbeddingPerMillion > 0
-			if !hasChatPricing && !hasEmbedPricing {
+			hasUnitPricing := m.Pricing.PerImage > 0 || m.Pricing.AudioPerSecond > 0 || m.Pricing.CharactersPerMillion > 0 || m.Pricing.PerSearch > 0
+			if !hasChatPricing && !hasEmbedPricing && !hasUnitPricing {
 				t.Errorf("model %q must have pricing set", m.ID)
 			}
 		}
@@ -0,0 +1,213 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/provider"
+)
+
+// maxAudioUpload matches OpenAI's 25 MB limit for audio files.
+const maxAudioUpload = 25 << 20
+
+// handleAudioTranscriptions handles POST /v1/audio/transcriptions
+func (p *Proxy) handleAudioTranscriptions(w http.ResponseWriter, r *http.Request) {
+	p.handleTranscription(w, r, false)
+}
+
+// handleAudioTranslations handles POST /v1/audio/translations
+func (p *Proxy) handleAudioTranslations(w http.ResponseWriter, r *http.Request) {
+	p.handleTranscription(w, r, true)
+}
+
+func (p *Proxy) handleTranscription(w http.ResponseWriter, r *http.Request, translate bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxAudioUpload+1<<20)
+	if err := r.ParseMultipartForm(maxAudioUpload); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "invalid_request_error", "audio file exceeds 25 MB")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid multipart form: "+err.Error())
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "file is required")
+		return
+	}
+	defer func() { _ = file.Close() }()
+	audio, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "failed to read audio file")
+		return
+	}
+
+	req := provider.TranscriptionRequest{
+		Model:     r.FormValue("model"),
+		Provider:  r.FormValue("provider"),
+		File:      audio,
+		Filename:  header.Filename,
+		Language:  r.FormValue("language"),
+		Prompt:    r.FormValue("prompt"),
+		Translate: translate,
+	}
+	if req.Model == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "model is required")
+		return
+	}
+	if t := r.FormValue("temperature"); t != "" {
+		temp, parseErr := strconv.ParseFloat(t, 64)
+		if parseErr != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "temperature must be a number")
+			return
+		}
+		req.Temperature = &temp
+	}
+
+	format := r.FormValue("response_format")
+	switch format {
+	case "", "json", "text", "verbose_json", "srt", "vtt":
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "unsupported response_format: "+format)
+		return
+	}
+
+	resp, err := p.engine.Transcribe(r.Context(), &req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+	writeTranscription(w, resp, format, translate)
+}
+
+// writeTranscription renders a transcript in the requested OpenAI
+// response_format. Subtitle formats are built from the timed segments.
+func writeTranscription(w http.ResponseWriter, resp *provider.TranscriptionResponse, format string, translate bool) {
+	switch format {
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, resp.Text+"\n")
+	case "srt":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, subtitles(resp, false))
+	case "vtt":
+		w.Header().Set("Content-Type", "text/vtt; charset=utf-8")
+		_, _ = io.WriteString(w, subtitles(resp, true))
+	case "verbose_json":
+		task := "transcribe"
+		if translate {
+			task = "translate"
+		}
+		writeJSON(w, http.StatusOK, openAIVerboseTranscription{
+			Task:     task,
+			Language: resp.Language,
+			Duration: resp.Duration,
+			Text:     resp.Text,
+			Segments: resp.Segments,
+		})
+	default:
+		writeJSON(w, http.StatusOK, openAITranscription{Text: resp.Text})
+	}
+}
+
+// subtitles renders SRT or WebVTT cues. A transcript without segments
+// becomes a single cue spanning the whole duration.
+func subtitles(resp *provider.TranscriptionResponse, vtt bool) string {
+	segments := resp.Segments
+	if len(segments) == 0 {
+		segments = []provider.TranscriptionSegment{{End: resp.Duration, Text: resp.Text}}
+	}
+
+	var b strings.Builder
+	if vtt {
+		b.WriteString("WEBVTT\n\n")
+	}
+	for i, s := range segments {
+		if !vtt {
+			fmt.Fprintf(&b, "%d\n", i+1)
+		}
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n", timestamp(s.Start, vtt), timestamp(s.End, vtt), strings.TrimSpace(s.Text))
+	}
+	return b.String()
+}
+
+// timestamp formats seconds as HH:MM:SS,mmm (SRT) or HH:MM:SS.mmm (VTT).
+func timestamp(seconds float64, vtt bool) string {
+	ms := int64(seconds*1000 + 0.5)
+	sep := ","
+	if vtt {
+		sep = "."
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3_600_000, ms/60_000%60, ms/1000%60, sep, ms%1000)
+}
+
+// handleAudioSpeech handles POST /v1/audio/speech. Audio is relayed as it
+// arrives, either as the raw body or, with stream_format "sse", as
+// speech.audio.delta events.
+func (p *Proxy) handleAudioSpeech(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "failed to read request body")
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	var req provider.SpeechRequest
+	if unmarshalErr := json.Unmarshal(body, &req); unmarshalErr != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON")
+		return
+	}
+	if req.Model == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "model is required")
+		return
+	}
+	if req.Input == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "input is required")
+		return
+	}
+	if req.StreamFormat != "" && req.StreamFormat != "audio" && req.StreamFormat != "sse" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "stream_format must be \"audio\" or \"sse\"")
+		return
+	}
+
+	ctx, cancel := p.streamContext(r.Context())
+	defer cancel()
+	resp, err := p.engine.Speech(ctx, &req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+
+	if req.StreamFormat == "sse" {
+		stream := httpstream.NewAudioStream(resp.Audio, resp.Model, resp.Format)
+		httpstream.Run(ctx, w, stream, httpstream.NewSSESpeechEncoder(), httpstream.RunOptions{
+			RequestID: pipeline.RequestID(ctx),
+		})
+		return
+	}
+
+	defer func() { _ = resp.Audio.Close() }()
+	_ = httpstream.WriteAudio(w, resp.Audio, resp.ContentType) //nolint:errcheck // client disconnects end the copy; nothing left to report
+}
+
+type openAITranscription struct {
+	Text string `json:"text"`
+}
+
+type openAIVerboseTranscription struct {
+	Task     string                          `json:"task"`
+	Language string                          `json:"language,omitempty"`
+	Duration float64                         `json:"duration"`
+	Text     string                          `json:"text"`
+	Segments []provider.TranscriptionSegment `json:"segments,omitempty"`
+}
@@ -0,0 +1,197 @@
+package proxy_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	nexus "github.com/xraph/nexus"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/proxy"
+)
+
+// audioProvider serves canned transcripts and speech.
+type audioProvider struct {
+	gotTranscription *provider.TranscriptionRequest
+}
+
+func (p *audioProvider) Name() string { return "audio" }
+func (p *audioProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Audio: true}
+}
+func (p *audioProvider) Models(_ context.Context) ([]provider.Model, error) { return nil, nil }
+func (p *audioProvider) Complete(_ context.Context, _ *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *audioProvider) CompleteStream(_ context.Context, _ *provider.CompletionRequest) (provider.Stream, error) {
+	return nil, errors.New("not used")
+}
+func (p *audioProvider) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *audioProvider) Healthy(_ context.Context) bool { return true }
+
+func (p *audioProvider) Transcribe(_ context.Context, req *provider.TranscriptionRequest) (*provider.TranscriptionResponse, error) {
+	p.gotTranscription = req
+	return &provider.TranscriptionResponse{
+		Model:    req.Model,
+		Text:     "Hello there. General Kenobi.",
+		Duration: 3.5,
+		Segments: []provider.TranscriptionSegment{
+			{ID: 0, Start: 0, End: 1.25, Text: " Hello there."},
+			{ID: 1, Start: 1.25, End: 3.5, Text: " General Kenobi."},
+		},
+	}, nil
+}
+
+func (p *audioProvider) Speech(_ context.Context, req *provider.SpeechRequest) (*provider.SpeechResponse, error) {
+	return &provider.SpeechResponse{
+		Model:       req.Model,
+		ContentType: "audio/mpeg",
+		Format:      req.Format(),
+		Audio:       io.NopCloser(strings.NewReader("mp3-bytes")),
+		Characters:  req.Characters(),
+	}, nil
+}
+
+func newAudioServer(t *testing.T) (*audioProvider, *httptest.Server) {
+	t.Helper()
+	ap := &audioProvider{}
+	engine := nexus.NewEngine(nexus.WithProvider(ap))
+	srv := httptest.NewServer(proxy.New(engine, proxy.WithoutWebSocket()))
+	t.Cleanup(srv.Close)
+	return ap, srv
+}
+
+func postTranscription(t *testing.T, url string, fields map[string]string) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "clip.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write([]byte("RIFF-audio"))
+	for k, v := range fields {
+		_ = mw.WriteField(k, v)
+	}
+	_ = mw.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, url, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestProxy_AudioTranscriptionSRT(t *testing.T) {
+	t.Parallel()
+	ap, srv := newAudioServer(t)
+
+	resp := postTranscription(t, srv.URL+"/v1/audio/transcriptions", map[string]string{
+		"model":           "whisper-1",
+		"language":        "en",
+		"response_format": "srt",
+	})
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		t.Fatalf("status = %d: %s", resp.StatusCode, b)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	want := "1\n00:00:00,000 --> 00:00:01,250\nHello there.\n\n2\n00:00:01,250 --> 00:00:03,500\nGeneral Kenobi.\n\n"
+	if string(got) != want {
+		t.Errorf("srt =\n%s\nwant\n%s", got, want)
+	}
+
+	r := ap.gotTranscription
+	if r == nil || string(r.File) != "RIFF-audio" || r.Filename != "clip.wav" || r.Language != "en" || r.Translate {
+		t.Errorf("provider request = %+v", r)
+	}
+}
+
+func TestProxy_AudioTranslationJSON(t *testing.T) {
+	t.Parallel()
+	ap, srv := newAudioServer(t)
+
+	resp := postTranscription(t, srv.URL+"/v1/audio/translations", map[string]string{"model": "whisper-1"})
+	got, _ := io.ReadAll(resp.Body)
+	if string(got) != `{"text":"Hello there. General Kenobi."}`+"\n" {
+		t.Errorf("body = %s", got)
+	}
+	if ap.gotTranscription == nil || !ap.gotTranscription.Translate {
+		t.Error("translation endpoint did not set Translate")
+	}
+}
+
+func TestProxy_AudioTranscriptionRequiresFile(t *testing.T) {
+	t.Parallel()
+	_, srv := newAudioServer(t)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("model", "whisper-1")
+	_ = mw.Close()
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/v1/audio/transcriptions", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestProxy_AudioSpeech(t *testing.T) {
+	t.Parallel()
+	_, srv := newAudioServer(t)
+
+	body := strings.NewReader(`{"model":"tts-1","input":"Hello","voice":"alloy"}`)
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/v1/audio/speech", body)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	got, _ := io.ReadAll(resp.Body)
+	if string(got) != "mp3-bytes" {
+		t.Errorf("audio = %q", got)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "audio/mpeg" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestProxy_AudioSpeechSSE(t *testing.T) {
+	t.Parallel()
+	_, srv := newAudioServer(t)
+
+	body := strings.NewReader(`{"model":"tts-1","input":"Hello","voice":"alloy","stream_format":"sse"}`)
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/v1/audio/speech", body)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	got, _ := io.ReadAll(resp.Body)
+	delta := `data: {"type":"speech.audio.delta","audio":"` + base64.StdEncoding.EncodeToString([]byte("mp3-bytes")) + `"}`
+	if !strings.Contains(string(got), delta) {
+		t.Errorf("missing audio delta in:\n%s", got)
+	}
+	if !strings.HasSuffix(string(got), `data: {"type":"speech.audio.done"}`+"\n\n") {
+		t.Errorf("missing done event in:\n%s", got)
+	}
+}
@@ -0,0 +1,164 @@
+package proxy
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/provider"
+)
+
+// backgroundMode holds the request fields that select background mode.
+// They are not part of provider.CompletionRequest.
+type backgroundMode struct {
+	Background bool              `json:"background"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// handleBackgroundCompletion starts a chat completion on a background
+// worker and answers immediately with the queued response.
+func (p *Proxy) handleBackgroundCompletion(w http.ResponseWriter, r *http.Request, req *provider.CompletionRequest, metadata map[string]string) {
+	ctx := r.Context()
+	req.TenantID = pipeline.TenantID(ctx)
+	req.KeyID = pipeline.KeyID(ctx)
+
+	resp, err := p.engine.CreateBackground(ctx, &background.CreateInput{
+		Request:  req,
+		Endpoint: background.EndpointChatCompletions,
+		Metadata: metadata,
+	})
+	if errors.Is(err, background.ErrClosed) {
+		writeError(w, http.StatusServiceUnavailable, "internal_error", err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toOpenAIBackgroundResponse(resp))
+}
+
+// handleGetResponse handles GET /v1/responses/{id}. With ?stream=true the
+// response's stream is replayed from the frame after starting_after (or
+// Last-Event-ID) and followed until it finishes.
+func (p *Proxy) handleGetResponse(w http.ResponseWriter, r *http.Request) {
+	resp, ok := p.findResponse(w, r)
+	if !ok {
+		return
+	}
+	if r.URL.Query().Get("stream") != "true" {
+		writeJSON(w, http.StatusOK, renderResponse(resp))
+		return
+	}
+
+	after := 0
+	if q := r.URL.Query().Get("starting_after"); q != "" {
+		n, err := strconv.Atoi(q)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "starting_after must be a non-negative integer")
+			return
+		}
+		after = n
+	} else if rid, seq, ok := httpstream.ResumePoint(r); ok && (rid == "" || rid == resp.ID) {
+		after = int(seq) //nolint:gosec // sequence numbers are frame counts
+	}
+
+	ctx, cancel := p.streamContext(r.Context())
+	defer cancel()
+
+	var encoder httpstream.StreamEncoder
+	if resp.Endpoint == background.EndpointResponses {
+		encoder = responsesEncoder(resp)
+	} else if encoder = httpstream.Negotiate(r, p.encoders); encoder == nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", "no stream encoder available")
+		return
+	}
+
+	httpstream.Run(ctx, w, background.Replay(p.engine.Gateway().Background(), resp.ID, after, 0), encoder, httpstream.RunOptions{
+		RequestID:   resp.ID,
+		LastEventID: uint64(after), //nolint:gosec // non-negative, checked above
+	})
+}
+
+// handleCancelResponse handles POST /v1/responses/{id}/cancel
+func (p *Proxy) handleCancelResponse(w http.ResponseWriter, r *http.Request) {
+	resp, ok := p.findResponse(w, r)
+	if !ok {
+		return
+	}
+	resp, err := p.engine.CancelBackground(r.Context(), resp.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, renderResponse(resp))
+}
+
+// renderResponse renders a response in the shape of the API that created
+// it.
+func renderResponse(resp *background.Response) any {
+	if resp.Endpoint == background.EndpointResponses {
+		return toResponsesObject(resp)
+	}
+	return toOpenAIBackgroundResponse(resp)
+}
+
+// findResponse loads the background response named in the path, hiding
+// other tenants' responses.
+func (p *Proxy) findResponse(w http.ResponseWriter, r *http.Request) (*background.Response, bool) {
+	resp, err := p.engine.GetBackground(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return nil, false
+	}
+	if resp == nil || resp.TenantID != pipeline.TenantID(r.Context()) {
+		writeError(w, http.StatusNotFound, "invalid_request_error", "no such response: "+r.PathValue("id"))
+		return nil, false
+	}
+	return resp, true
+}
+
+// ─────────────────────────────────────────────────────────────
+// OpenAI wire types
+// ─────────────────────────────────────────────────────────────
+
+// openAIBackgroundResponse mirrors the Responses API envelope. Responses
+// created through /v1/chat/completions carry their answer as a chat
+// completion.
+type openAIBackgroundResponse struct {
+	ID             string              `json:"id"`
+	Object         string              `json:"object"`
+	CreatedAt      int64               `json:"created_at"`
+	Status         string              `json:"status"`
+	Background     bool                `json:"background"`
+	Model          string              `json:"model"`
+	Endpoint       string              `json:"endpoint"`
+	Error          *background.Error   `json:"error"`
+	CompletedAt    *int64              `json:"completed_at"`
+	SequenceNumber int                 `json:"sequence_number"`
+	Metadata       map[string]string   `json:"metadata"`
+	ChatCompletion *openAIChatResponse `json:"chat_completion,omitempty"`
+}
+
+func toOpenAIBackgroundResponse(resp *background.Response) openAIBackgroundResponse {
+	out := openAIBackgroundResponse{
+		ID:             resp.ID,
+		Object:         "response",
+		CreatedAt:      resp.CreatedAt.Unix(),
+		Status:         string(resp.Status),
+		Background:     true,
+		Model:          resp.Model,
+		Endpoint:       resp.Endpoint,
+		Error:          resp.Error,
+		CompletedAt:    unixTime(resp.CompletedAt),
+		SequenceNumber: resp.Frames,
+		Metadata:       resp.Metadata,
+	}
+	if resp.Result != nil {
+		out.ChatCompletion = toOpenAIChatResponse(resp.Result)
+	}
+	return out
+}
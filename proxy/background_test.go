@@ -0,0 +1,178 @@
+package proxy_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	nexus "github.com/xraph/nexus"
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/proxy"
+)
+
+type backgroundResponse struct {
+	ID             string `json:"id"`
+	Object         string `json:"object"`
+	Status         string `json:"status"`
+	SequenceNumber int    `json:"sequence_number"`
+	Error          *struct {
+		Code string `json:"code"`
+	} `json:"error"`
+	ChatCompletion *struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	} `json:"chat_completion"`
+}
+
+func newBackgroundServer(t *testing.T, gp *gatedProvider) (*proxy.Proxy, *httptest.Server) {
+	t.Helper()
+	engine := nexus.NewEngine(nexus.WithProvider(gp))
+	p := proxy.New(engine, proxy.WithoutWebSocket())
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { _ = engine.Gateway().Shutdown(context.Background()) })
+	return p, srv
+}
+
+func doBackground(t *testing.T, method, url, body string) (int, backgroundResponse) {
+	t.Helper()
+	var rdr io.Reader = http.NoBody
+	if body != "" {
+		rdr = strings.NewReader(body)
+	}
+	req, _ := http.NewRequestWithContext(context.Background(), method, url, rdr)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var out backgroundResponse
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func pollBackground(t *testing.T, url string, done func(backgroundResponse) bool) backgroundResponse {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_, out := doBackground(t, http.MethodGet, url, "")
+		if done(out) {
+			return out
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("response never reached the expected state: %+v", out)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+const backgroundBody = `{"model":"x","messages":[{"role":"user","content":"hi"}],"background":true}`
+
+func TestProxy_BackgroundCompletion(t *testing.T) {
+	t.Parallel()
+	gp := &gatedProvider{gate: make(chan struct{})}
+	_, srv := newBackgroundServer(t, gp)
+
+	status, created := doBackground(t, http.MethodPost, srv.URL+"/v1/chat/completions", backgroundBody)
+	if status != http.StatusOK || !strings.HasPrefix(created.ID, "resp_") || created.Object != "response" {
+		t.Fatalf("create = %d %+v", status, created)
+	}
+
+	close(gp.gate)
+	url := srv.URL + "/v1/responses/" + created.ID
+	done := pollBackground(t, url, func(r backgroundResponse) bool { return r.Status == "completed" })
+	if done.ChatCompletion == nil || done.ChatCompletion.Choices[0].Message.Content != "onetwothree" || done.SequenceNumber != 3 {
+		t.Fatalf("completed response = %+v", done)
+	}
+
+	resp, err := http.Get(url + "?stream=true&starting_after=1") //nolint:noctx // test
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	raw, _ := io.ReadAll(resp.Body)
+	replay := string(raw)
+	if strings.Contains(replay, `"one"`) {
+		t.Fatalf("replay included a frame before starting_after: %s", replay)
+	}
+	for _, want := range []string{"id: " + created.ID + ":2", `"two"`, `"three"`, "data: [DONE]"} {
+		if !strings.Contains(replay, want) {
+			t.Fatalf("replay missing %q:\n%s", want, replay)
+		}
+	}
+}
+
+func TestBackgroundRunsAsTheRequestTenant(t *testing.T) {
+	t.Parallel()
+	rp := &replyProvider{}
+	engine := nexus.NewEngine(nexus.WithProvider(rp))
+	t.Cleanup(func() { _ = engine.Gateway().Shutdown(context.Background()) })
+
+	resp, err := engine.CreateBackground(context.Background(), &background.CreateInput{Request: &provider.CompletionRequest{
+		Model: "x", TenantID: "acme", Messages: []provider.Message{{Role: "user", Content: "hi"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !resp.Status.Done() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		resp, _ = engine.GetBackground(context.Background(), resp.ID)
+	}
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if len(rp.tenants) != 1 || rp.tenants[0] != "acme" {
+		t.Fatalf("stream tenants = %q, want [acme]", rp.tenants)
+	}
+}
+
+func TestProxy_BackgroundCancel(t *testing.T) {
+	t.Parallel()
+	_, srv := newBackgroundServer(t, &gatedProvider{gate: make(chan struct{})})
+
+	_, created := doBackground(t, http.MethodPost, srv.URL+"/v1/chat/completions", backgroundBody)
+	url := srv.URL + "/v1/responses/" + created.ID
+	pollBackground(t, url, func(r backgroundResponse) bool { return r.Status == "in_progress" })
+
+	if status, _ := doBackground(t, http.MethodPost, url+"/cancel", ""); status != http.StatusOK {
+		t.Fatalf("cancel status = %d", status)
+	}
+	pollBackground(t, url, func(r backgroundResponse) bool { return r.Status == "cancelled" })
+
+	if status, _ := doBackground(t, http.MethodGet, srv.URL+"/v1/responses/resp_missing", ""); status != http.StatusNotFound {
+		t.Fatalf("unknown response status = %d, want 404", status)
+	}
+}
+
+func TestProxy_ShutdownPersistsBackgroundAsIncomplete(t *testing.T) {
+	t.Parallel()
+	p, srv := newBackgroundServer(t, &gatedProvider{gate: make(chan struct{})})
+
+	_, created := doBackground(t, http.MethodPost, srv.URL+"/v1/chat/completions", backgroundBody)
+	url := srv.URL + "/v1/responses/" + created.ID
+	pollBackground(t, url, func(r backgroundResponse) bool { return r.SequenceNumber == 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	_, out := doBackground(t, http.MethodGet, url, "")
+	if out.Status != "incomplete" || out.Error == nil || out.Error.Code != "shutdown" {
+		t.Fatalf("response after shutdown = %+v", out)
+	}
+	if status, _ := doBackground(t, http.MethodPost, srv.URL+"/v1/chat/completions", backgroundBody); status != http.StatusServiceUnavailable {
+		t.Fatalf("create after shutdown status = %d, want 503", status)
+	}
+}
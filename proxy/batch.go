@@ -0,0 +1,373 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/xraph/nexus/batch"
+	"github.com/xraph/nexus/pipeline"
+)
+
+// maxBatchUpload matches OpenAI's 200 MB limit for batch input files.
+const maxBatchUpload = 200 << 20
+
+// handleUploadFile handles POST /v1/files
+func (p *Proxy) handleUploadFile(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchUpload+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "invalid_request_error", "file exceeds 200 MB")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid multipart form: "+err.Error())
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	purpose := r.FormValue("purpose")
+	if purpose != batch.PurposeBatch {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", `purpose must be "batch"`)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "file is required")
+		return
+	}
+	defer func() { _ = file.Close() }()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "failed to read file")
+		return
+	}
+
+	f, err := p.engine.Gateway().Batches().UploadFile(r.Context(), &batch.File{
+		TenantID: pipeline.TenantID(r.Context()),
+		Filename: header.Filename,
+		Purpose:  purpose,
+		Content:  content,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toOpenAIFile(f))
+}
+
+// handleListFiles handles GET /v1/files
+func (p *Proxy) handleListFiles(w http.ResponseWriter, r *http.Request) {
+	files, err := p.engine.Gateway().Batches().ListFiles(r.Context(), pipeline.TenantID(r.Context()), r.URL.Query().Get("purpose"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+	data := make([]openAIFile, len(files))
+	for i, f := range files {
+		data[i] = toOpenAIFile(f)
+	}
+	writeJSON(w, http.StatusOK, openAIList[openAIFile]{Object: "list", Data: data})
+}
+
+// handleGetFile handles GET /v1/files/{id}
+func (p *Proxy) handleGetFile(w http.ResponseWriter, r *http.Request) {
+	f, ok := p.findFile(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, toOpenAIFile(f))
+}
+
+// handleFileContent handles GET /v1/files/{id}/content
+func (p *Proxy) handleFileContent(w http.ResponseWriter, r *http.Request) {
+	f, ok := p.findFile(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/jsonl")
+	w.Header().Set("Content-Length", strconv.Itoa(len(f.Content)))
+	_, _ = w.Write(f.Content)
+}
+
+// handleDeleteFile handles DELETE /v1/files/{id}
+func (p *Proxy) handleDeleteFile(w http.ResponseWriter, r *http.Request) {
+	f, ok := p.findFile(w, r)
+	if !ok {
+		return
+	}
+	if err := p.engine.Gateway().Batches().DeleteFile(r.Context(), f.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, openAIDeleted{ID: f.ID, Object: "file", Deleted: true})
+}
+
+// findFile loads the file named in the path, hiding other tenants' files.
+func (p *Proxy) findFile(w http.ResponseWriter, r *http.Request) (*batch.File, bool) {
+	f, err := p.engine.Gateway().Batches().GetFile(r.Context(), r.PathValue("id"))
+	switch {
+	case errors.Is(err, batch.ErrFileNotFound):
+		f = nil
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return nil, false
+	}
+	if f == nil || f.TenantID != pipeline.TenantID(r.Context()) {
+		writeError(w, http.StatusNotFound, "invalid_request_error", "no such file: "+r.PathValue("id"))
+		return nil, false
+	}
+	return f, true
+}
+
+// handleCreateBatch handles POST /v1/batches
+func (p *Proxy) handleCreateBatch(w http.ResponseWriter, r *http.Request) {
+	var req openAIBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid request body: "+err.Error())
+		return
+	}
+	if req.InputFileID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "input_file_id is required")
+		return
+	}
+
+	ctx := r.Context()
+	job, err := p.engine.CreateBatch(ctx, &batch.CreateInput{
+		TenantID:         pipeline.TenantID(ctx),
+		KeyID:            pipeline.KeyID(ctx),
+		InputFileID:      req.InputFileID,
+		Endpoint:         req.Endpoint,
+		CompletionWindow: req.CompletionWindow,
+		Metadata:         req.Metadata,
+	})
+	if errors.Is(err, batch.ErrFileNotFound) {
+		writeError(w, http.StatusNotFound, "invalid_request_error", "no such file: "+req.InputFileID)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toOpenAIBatch(job))
+}
+
+// handleListBatches handles GET /v1/batches
+func (p *Proxy) handleListBatches(w http.ResponseWriter, r *http.Request) {
+	jobs, err := p.engine.Gateway().Batches().List(r.Context(), pipeline.TenantID(r.Context()))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+
+	// Cursor pagination: jobs are newest first, "after" names the last
+	// job of the previous page.
+	if after := r.URL.Query().Get("after"); after != "" {
+		for i, job := range jobs {
+			if job.ID == after {
+				jobs = jobs[i+1:]
+				break
+			}
+		}
+	}
+	limit := 20
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	hasMore := len(jobs) > limit
+	if hasMore {
+		jobs = jobs[:limit]
+	}
+
+	data := make([]openAIBatch, len(jobs))
+	for i, job := range jobs {
+		data[i] = toOpenAIBatch(job)
+	}
+	list := openAIList[openAIBatch]{Object: "list", Data: data, HasMore: hasMore}
+	if len(data) > 0 {
+		list.FirstID = data[0].ID
+		list.LastID = data[len(data)-1].ID
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// handleGetBatch handles GET /v1/batches/{id}
+func (p *Proxy) handleGetBatch(w http.ResponseWriter, r *http.Request) {
+	job, ok := p.findBatch(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, toOpenAIBatch(job))
+}
+
+// handleCancelBatch handles POST /v1/batches/{id}/cancel
+func (p *Proxy) handleCancelBatch(w http.ResponseWriter, r *http.Request) {
+	job, ok := p.findBatch(w, r)
+	if !ok {
+		return
+	}
+	if err := p.engine.CancelBatch(r.Context(), job.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+	if job, ok = p.findBatch(w, r); ok {
+		writeJSON(w, http.StatusOK, toOpenAIBatch(job))
+	}
+}
+
+// findBatch loads the batch named in the path, hiding other tenants' jobs.
+func (p *Proxy) findBatch(w http.ResponseWriter, r *http.Request) (*batch.Job, bool) {
+	job, err := p.engine.GetBatch(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return nil, false
+	}
+	if job == nil || job.TenantID != pipeline.TenantID(r.Context()) {
+		writeError(w, http.StatusNotFound, "invalid_request_error", "no such batch: "+r.PathValue("id"))
+		return nil, false
+	}
+	return job, true
+}
+
+// ─────────────────────────────────────────────────────────────
+// OpenAI wire types
+// ─────────────────────────────────────────────────────────────
+
+type openAIBatchRequest struct {
+	InputFileID      string         `json:"input_file_id"`
+	Endpoint         string         `json:"endpoint"`
+	CompletionWindow string         `json:"completion_window"`
+	Metadata         map[string]any `json:"metadata,omitempty"`
+}
+
+type openAIList[T any] struct {
+	Object  string `json:"object"`
+	Data    []T    `json:"data"`
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+	HasMore bool   `json:"has_more"`
+}
+
+type openAIFile struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int    `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+	Status    string `json:"status"`
+}
+
+type openAIDeleted struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+type openAIBatch struct {
+	ID               string                   `json:"id"`
+	Object           string                   `json:"object"`
+	Endpoint         string                   `json:"endpoint"`
+	Errors           *openAIList[batch.Error] `json:"errors"`
+	InputFileID      string                   `json:"input_file_id"`
+	CompletionWindow string                   `json:"completion_window"`
+	Status           string                   `json:"status"`
+	OutputFileID     *string                  `json:"output_file_id"`
+	ErrorFileID      *string                  `json:"error_file_id"`
+	CreatedAt        int64                    `json:"created_at"`
+	InProgressAt     *int64                   `json:"in_progress_at"`
+	ExpiresAt        *int64                   `json:"expires_at"`
+	CompletedAt      *int64                   `json:"completed_at"`
+	FailedAt         *int64                   `json:"failed_at"`
+	ExpiredAt        *int64                   `json:"expired_at"`
+	CancelledAt      *int64                   `json:"cancelled_at"`
+	RequestCounts    openAIRequestCounts      `json:"request_counts"`
+	Metadata         map[string]any           `json:"metadata"`
+}
+
+type openAIRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+func toOpenAIFile(f *batch.File) openAIFile {
+	return openAIFile{
+		ID:        f.ID,
+		Object:    "file",
+		Bytes:     f.Bytes,
+		CreatedAt: f.CreatedAt.Unix(),
+		Filename:  f.Filename,
+		Purpose:   f.Purpose,
+		Status:    "processed",
+	}
+}
+
+func toOpenAIBatch(job *batch.Job) openAIBatch {
+	out := openAIBatch{
+		ID:               job.ID,
+		Object:           "batch",
+		Endpoint:         job.Endpoint,
+		InputFileID:      job.InputFileID,
+		CompletionWindow: job.CompletionWindow,
+		Status:           openAIBatchStatus(job.Status),
+		OutputFileID:     optionalString(job.OutputFileID),
+		ErrorFileID:      optionalString(job.ErrorFileID),
+		CreatedAt:        job.CreatedAt.Unix(),
+		InProgressAt:     unixTime(job.StartedAt),
+		RequestCounts: openAIRequestCounts{
+			Total:     job.TotalItems,
+			Completed: job.Completed,
+			Failed:    job.Failed,
+		},
+		Metadata: job.Metadata,
+	}
+	if window, err := time.ParseDuration(job.CompletionWindow); err == nil {
+		expires := job.CreatedAt.Add(window).Unix()
+		out.ExpiresAt = &expires
+	}
+	if len(job.Errors) > 0 {
+		out.Errors = &openAIList[batch.Error]{Object: "list", Data: job.Errors}
+	}
+	switch job.Status {
+	case batch.JobCompleted:
+		out.CompletedAt = unixTime(job.CompletedAt)
+	case batch.JobFailed:
+		out.FailedAt = unixTime(job.CompletedAt)
+	case batch.JobExpired:
+		out.ExpiredAt = unixTime(job.CompletedAt)
+	case batch.JobCancelled:
+		out.CancelledAt = unixTime(job.CompletedAt)
+	}
+	return out
+}
+
+// openAIBatchStatus maps job statuses to OpenAI's batch lifecycle names.
+func openAIBatchStatus(s batch.JobStatus) string {
+	switch s {
+	case batch.JobPending:
+		return "validating"
+	case batch.JobRunning:
+		return "in_progress"
+	default:
+		return string(s)
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func unixTime(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	u := t.Unix()
+	return &u
+}
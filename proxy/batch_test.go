@@ -0,0 +1,307 @@
+package proxy_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	nexus "github.com/xraph/nexus"
+	"github.com/xraph/nexus/batch"
+	"github.com/xraph/nexus/pipeline/middlewares"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/proxy"
+	"github.com/xraph/nexus/store"
+	"github.com/xraph/nexus/usage"
+)
+
+// echoProvider answers chat completions with the last user message.
+type echoProvider struct{}
+
+func (p *echoProvider) Name() string { return "echo" }
+func (p *echoProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Chat: true}
+}
+func (p *echoProvider) Models(_ context.Context) ([]provider.Model, error) { return nil, nil }
+func (p *echoProvider) Complete(_ context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	content := req.Messages[len(req.Messages)-1].Content
+	return &provider.CompletionResponse{
+		ID:       "chatcmpl-echo",
+		Provider: "echo",
+		Model:    req.Model,
+		Choices:  []provider.Choice{{Message: provider.Message{Role: "assistant", Content: content}, FinishReason: "stop"}},
+		Usage:    provider.Usage{PromptTokens: 3, CompletionTokens: 3, TotalTokens: 6},
+	}, nil
+}
+func (p *echoProvider) CompleteStream(_ context.Context, _ *provider.CompletionRequest) (provider.Stream, error) {
+	return nil, errors.New("not used")
+}
+func (p *echoProvider) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *echoProvider) Healthy(_ context.Context) bool { return true }
+
+func newBatchServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	engine := nexus.NewEngine(nexus.WithProvider(&echoProvider{}))
+	t.Cleanup(func() { _ = engine.Gateway().Shutdown(context.Background()) })
+	srv := httptest.NewServer(proxy.New(engine, proxy.WithoutWebSocket()))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func uploadBatchFile(t *testing.T, url, purpose, content string) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := mw.WriteField("purpose", purpose); err != nil {
+		t.Fatal(err)
+	}
+	fw, err := mw.CreateFormFile("file", "requests.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.WriteString(fw, content)
+	_ = mw.Close()
+
+	resp, err := http.Post(url+"/v1/files", mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func decodeBody(t *testing.T, resp *http.Response, v any) {
+	t.Helper()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBatchLifecycle(t *testing.T) {
+	srv := newBatchServer(t)
+
+	input := `{"custom_id":"req-1","method":"POST","url":"/v1/chat/completions","body":{"model":"m","messages":[{"role":"user","content":"ping"}]}}
+{"custom_id":"req-2","method":"POST","url":"/v1/chat/completions","body":{"model":"m","messages":[{"role":"user","content":"pong"}]}}
+`
+	resp := uploadBatchFile(t, srv.URL, "batch", input)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("upload status = %d", resp.StatusCode)
+	}
+	var file struct {
+		ID      string `json:"id"`
+		Object  string `json:"object"`
+		Bytes   int    `json:"bytes"`
+		Purpose string `json:"purpose"`
+	}
+	decodeBody(t, resp, &file)
+	if file.Object != "file" || file.Bytes != len(input) || file.Purpose != "batch" {
+		t.Fatalf("file = %+v", file)
+	}
+
+	resp, err := http.Post(srv.URL+"/v1/batches", "application/json", strings.NewReader(
+		`{"input_file_id":"`+file.ID+`","endpoint":"/v1/chat/completions","completion_window":"24h","metadata":{"job":"nightly"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("create status = %d: %s", resp.StatusCode, body)
+	}
+	type batchObject struct {
+		ID            string         `json:"id"`
+		Object        string         `json:"object"`
+		Status        string         `json:"status"`
+		OutputFileID  *string        `json:"output_file_id"`
+		ExpiresAt     *int64         `json:"expires_at"`
+		Metadata      map[string]any `json:"metadata"`
+		RequestCounts struct {
+			Total     int `json:"total"`
+			Completed int `json:"completed"`
+			Failed    int `json:"failed"`
+		} `json:"request_counts"`
+	}
+	var b batchObject
+	decodeBody(t, resp, &b)
+	if b.Object != "batch" || b.RequestCounts.Total != 2 || b.ExpiresAt == nil || b.Metadata["job"] != "nightly" {
+		t.Fatalf("batch = %+v", b)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for b.Status != "completed" {
+		if time.Now().After(deadline) {
+			t.Fatalf("batch status = %q, want completed", b.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+		getResp, err := http.Get(srv.URL + "/v1/batches/" + b.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decodeBody(t, getResp, &b)
+		_ = getResp.Body.Close()
+	}
+	if b.RequestCounts.Completed != 2 || b.OutputFileID == nil {
+		t.Fatalf("finished batch = %+v", b)
+	}
+
+	contentResp, err := http.Get(srv.URL + "/v1/files/" + *b.OutputFileID + "/content")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = contentResp.Body.Close() }()
+	type outputLine struct {
+		CustomID string `json:"custom_id"`
+		Response struct {
+			StatusCode int `json:"status_code"`
+			Body       struct {
+				Object  string `json:"object"`
+				Choices []struct {
+					Message struct {
+						Content string `json:"content"`
+					} `json:"message"`
+				} `json:"choices"`
+			} `json:"body"`
+		} `json:"response"`
+	}
+	var lines []outputLine
+	dec := json.NewDecoder(contentResp.Body)
+	for dec.More() {
+		var line outputLine
+		if err := dec.Decode(&line); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("output lines = %d, want 2", len(lines))
+	}
+	for _, line := range lines {
+		want := map[string]string{"req-1": "ping", "req-2": "pong"}[line.CustomID]
+		if line.Response.StatusCode != 200 || line.Response.Body.Object != "chat.completion" || line.Response.Body.Choices[0].Message.Content != want {
+			t.Errorf("output line = %+v", line)
+		}
+	}
+
+	listResp, err := http.Get(srv.URL + "/v1/batches?limit=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listResp.Body.Close() }()
+	var list struct {
+		Object string        `json:"object"`
+		Data   []batchObject `json:"data"`
+	}
+	decodeBody(t, listResp, &list)
+	if list.Object != "list" || len(list.Data) != 1 || list.Data[0].ID != b.ID {
+		t.Fatalf("list = %+v", list)
+	}
+}
+
+func TestBatchRejectsInvalidInput(t *testing.T) {
+	srv := newBatchServer(t)
+
+	if resp := uploadBatchFile(t, srv.URL, "fine-tune", "{}\n"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("upload with purpose fine-tune: status = %d, want 400", resp.StatusCode)
+	}
+
+	resp, err := http.Post(srv.URL+"/v1/batches", "application/json", strings.NewReader(`{"input_file_id":"file_missing"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("batch with unknown file: status = %d, want 404", resp.StatusCode)
+	}
+
+	getResp, err := http.Get(srv.URL + "/v1/batches/batch_missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = getResp.Body.Close() }()
+	if getResp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown batch: status = %d, want 404", getResp.StatusCode)
+	}
+}
+
+// wrappingUsage runs the usage middleware around the provider call, the
+// way deployments that record every call install it.
+type wrappingUsage struct{ *middlewares.UsageMiddleware }
+
+func (wrappingUsage) Priority() int { return 300 }
+
+func TestBatchRecordsUsageOncePerItem(t *testing.T) {
+	st := store.NewMemory()
+	engine := nexus.NewEngine(
+		nexus.WithProvider(&echoProvider{}),
+		nexus.WithDatabase(st),
+		nexus.WithMiddleware(wrappingUsage{middlewares.NewUsage(usage.NewService(st.Usage()))}),
+	)
+	t.Cleanup(func() { _ = engine.Gateway().Shutdown(context.Background()) })
+
+	job, err := engine.CreateBatch(context.Background(), &batch.CreateInput{Inputs: []batch.Input{
+		{CustomID: "a", Request: &provider.CompletionRequest{Model: "m", Messages: []provider.Message{{Role: "user", Content: "x"}}}},
+		{CustomID: "b", Request: &provider.CompletionRequest{Model: "m", Messages: []provider.Message{{Role: "user", Content: "y"}}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !job.Status.Done() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		job, _ = engine.GetBatch(context.Background(), job.ID)
+	}
+	if job.Status != batch.JobCompleted {
+		t.Fatalf("job status = %s", job.Status)
+	}
+
+	// Usage is recorded asynchronously by the pipeline; give a duplicate
+	// time to land before counting.
+	time.Sleep(50 * time.Millisecond)
+	records, _, err := st.Usage().Query(context.Background(), &usage.QueryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("usage records = %d, want one per item", len(records))
+	}
+	for _, rec := range records {
+		if rec.BatchID != job.ID {
+			t.Errorf("usage record batch = %q, want %q", rec.BatchID, job.ID)
+		}
+	}
+}
+
+func TestBatchItemsRunAsTheJobTenant(t *testing.T) {
+	rp := &replyProvider{}
+	engine := nexus.NewEngine(nexus.WithProvider(rp))
+	t.Cleanup(func() { _ = engine.Gateway().Shutdown(context.Background()) })
+
+	job, err := engine.CreateBatch(context.Background(), &batch.CreateInput{
+		TenantID: "acme",
+		Inputs: []batch.Input{
+			{CustomID: "a", Request: &provider.CompletionRequest{Model: "m", Messages: []provider.Message{{Role: "user", Content: "x"}}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !job.Status.Done() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		job, _ = engine.GetBatch(context.Background(), job.ID)
+	}
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if len(rp.tenants) != 1 || rp.tenants[0] != "acme" {
+		t.Fatalf("item tenants = %q, want [acme]", rp.tenants)
+	}
+}
@@ -0,0 +1,133 @@
+package proxy_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	nexus "github.com/xraph/nexus"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/proxy"
+)
+
+// embedProvider serves a fixed vector for the models it lists, or fails.
+type embedProvider struct {
+	name   string
+	models []string
+	fail   bool
+	got    *provider.EmbeddingRequest
+}
+
+func (p *embedProvider) Name() string { return p.name }
+func (p *embedProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Embeddings: true}
+}
+func (p *embedProvider) Models(_ context.Context) ([]provider.Model, error) {
+	models := make([]provider.Model, len(p.models))
+	for i, id := range p.models {
+		models[i] = provider.Model{ID: id, Provider: p.name, Capabilities: provider.Capabilities{Embeddings: true}}
+	}
+	return models, nil
+}
+func (p *embedProvider) Complete(_ context.Context, _ *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *embedProvider) CompleteStream(_ context.Context, _ *provider.CompletionRequest) (provider.Stream, error) {
+	return nil, errors.New("not used")
+}
+func (p *embedProvider) Embed(_ context.Context, req *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	p.got = req
+	if p.fail {
+		return nil, errors.New("upstream unavailable")
+	}
+	return &provider.EmbeddingResponse{Provider: p.name, Model: req.Model, Embeddings: [][]float64{{1, 0.5, -2}}}, nil
+}
+func (p *embedProvider) Healthy(_ context.Context) bool { return true }
+
+func postEmbeddings(t *testing.T, url, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(url+"/v1/embeddings", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestEmbeddingsRouting(t *testing.T) {
+	a := &embedProvider{name: "a", models: []string{"shared"}, fail: true}
+	b := &embedProvider{name: "b", models: []string{"shared", "only-b"}}
+	engine := nexus.NewEngine(nexus.WithProvider(a), nexus.WithProvider(b))
+	srv := httptest.NewServer(proxy.New(engine, proxy.WithoutWebSocket()))
+	t.Cleanup(srv.Close)
+
+	// Model matching: only b lists the model.
+	resp := postEmbeddings(t, srv.URL, `{"model":"only-b","input":"hi","dimensions":256,"input_type":"query","truncate":"end"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if a.got != nil {
+		t.Error("provider a was called for a model it does not list")
+	}
+	if got := b.got; got == nil || got.Dimensions != 256 || got.InputType != "query" || got.Truncate != "end" || got.Input[0] != "hi" {
+		t.Errorf("provider b got %+v", got)
+	}
+
+	// Fallback: a fails, b serves.
+	b.got = nil
+	resp = postEmbeddings(t, srv.URL, `{"model":"shared","input":["x"]}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("fallback status = %d", resp.StatusCode)
+	}
+	if b.got == nil {
+		t.Error("expected fallback to provider b")
+	}
+
+	// Forced provider that cannot serve fails instead of falling back.
+	resp = postEmbeddings(t, srv.URL, `{"model":"shared","input":["x"],"provider":"a"}`)
+	if resp.StatusCode == http.StatusOK {
+		t.Error("forced provider a should not fall back")
+	}
+}
+
+func TestEmbeddingsBase64(t *testing.T) {
+	engine := nexus.NewEngine(nexus.WithProvider(&embedProvider{name: "b", models: []string{"m"}}))
+	srv := httptest.NewServer(proxy.New(engine, proxy.WithoutWebSocket()))
+	t.Cleanup(srv.Close)
+
+	resp := postEmbeddings(t, srv.URL, `{"model":"m","input":"hi","encoding_format":"base64"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	var out struct {
+		Data []struct {
+			Embedding string `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(out.Data[0].Embedding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(raw) != 12 {
+		t.Fatalf("decoded %d bytes, want 12", len(raw))
+	}
+	for i, want := range []float32{1, 0.5, -2} {
+		if got := math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:])); got != want {
+			t.Errorf("vector[%d] = %v, want %v", i, got, want)
+		}
+	}
+
+	if resp := postEmbeddings(t, srv.URL, `{"model":"m","input":"hi","encoding_format":"int8"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad encoding_format status = %d, want 400", resp.StatusCode)
+	}
+}
@@ -0,0 +1,182 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/xraph/nexus/genai"
+	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/pipeline"
+)
+
+// handleGenAI handles POST /v1beta/models/{model}:{method}, the Gemini
+// API. generateContent and streamGenerateContent are translated to chat
+// completions and embedContent and batchEmbedContents to embeddings, so
+// Google GenAI SDK clients can reach every provider.
+func (p *Proxy) handleGenAI(w http.ResponseWriter, r *http.Request) {
+	r, ok := p.genaiAuth(w, r)
+	if !ok {
+		return
+	}
+	model, method, _ := strings.Cut(r.PathValue("call"), ":")
+	switch method {
+	case "generateContent":
+		p.handleGenerateContent(w, r, model, false)
+	case "streamGenerateContent":
+		if r.URL.Query().Get("alt") != "sse" {
+			writeGenAIError(w, http.StatusBadRequest, "streamGenerateContent is only served as SSE: add alt=sse")
+			return
+		}
+		p.handleGenerateContent(w, r, model, true)
+	case "countTokens":
+		p.handleGenAICountTokens(w, r, model)
+	case "embedContent":
+		var req genai.EmbedContentRequest
+		if !decodeGenAIRequest(w, r, &req) {
+			return
+		}
+		p.handleEmbedContent(w, r, model, []genai.EmbedContentRequest{req}, false)
+	case "batchEmbedContents":
+		var req genai.BatchEmbedContentsRequest
+		if !decodeGenAIRequest(w, r, &req) {
+			return
+		}
+		p.handleEmbedContent(w, r, model, req.Requests, true)
+	default:
+		writeGenAIError(w, http.StatusNotFound, "unknown method "+method+" on models/"+model)
+	}
+}
+
+func (p *Proxy) handleGenerateContent(w http.ResponseWriter, r *http.Request, model string, stream bool) {
+	var req genai.Request
+	if !decodeGenAIRequest(w, r, &req) {
+		return
+	}
+	creq, err := genai.ToCompletionRequest(model, &req)
+	if err != nil {
+		writeGenAIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	if !stream {
+		resp, err := p.engine.Complete(ctx, creq)
+		if err != nil {
+			writeGenAIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, genai.FromCompletion(resp))
+		return
+	}
+
+	creq.Stream = true
+	ctx, cancel := p.streamContext(ctx)
+	defer cancel()
+
+	encoder := httpstream.ForStream(p.encoders.Lookup("gemini"))
+	if encoder == nil {
+		encoder = httpstream.NewSSEGeminiEncoder()
+	}
+	s, err := p.engine.CompleteStream(ctx, creq)
+	if err != nil {
+		writeGenAIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	httpstream.Run(ctx, w, s, encoder, httpstream.RunOptions{
+		RequestID: pipeline.RequestID(ctx),
+	})
+}
+
+// handleGenAICountTokens answers :countTokens with the gateway's estimate;
+// no provider is called.
+func (p *Proxy) handleGenAICountTokens(w http.ResponseWriter, r *http.Request, model string) {
+	var req struct {
+		genai.Request
+		GenerateContentRequest *genai.Request `json:"generateContentRequest,omitempty"`
+	}
+	if !decodeGenAIRequest(w, r, &req) {
+		return
+	}
+	body := &req.Request
+	if req.GenerateContentRequest != nil {
+		body = req.GenerateContentRequest
+	}
+	creq, err := genai.ToCompletionRequest(model, body)
+	if err != nil {
+		writeGenAIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	n, err := p.engine.CountTokens(r.Context(), creq)
+	if err != nil {
+		writeGenAIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"totalTokens": n})
+}
+
+func (p *Proxy) handleEmbedContent(w http.ResponseWriter, r *http.Request, model string, reqs []genai.EmbedContentRequest, batch bool) {
+	ereq, err := genai.ToEmbeddingRequest(model, reqs)
+	if err != nil {
+		writeGenAIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := p.engine.Embed(r.Context(), ereq)
+	if err != nil {
+		writeGenAIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	embeddings := make([]genai.ContentEmbedding, len(resp.Embeddings))
+	for i, values := range resp.Embeddings {
+		embeddings[i] = genai.ContentEmbedding{Values: values}
+	}
+	if !batch {
+		if len(embeddings) == 0 {
+			writeGenAIError(w, http.StatusInternalServerError, "provider returned no embedding")
+			return
+		}
+		writeJSON(w, http.StatusOK, genai.EmbedContentResponse{Embedding: embeddings[0]})
+		return
+	}
+	writeJSON(w, http.StatusOK, genai.BatchEmbedContentsResponse{Embeddings: embeddings})
+}
+
+// genaiAuth authenticates the key the Google GenAI SDKs send, in the
+// x-goog-api-key header or the key query parameter. See apiKeyAuth.
+func (p *Proxy) genaiAuth(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	apiKey := r.Header.Get("X-Goog-Api-Key")
+	if apiKey == "" {
+		apiKey = r.URL.Query().Get("key")
+	}
+	r, ok := p.apiKeyAuth(r, apiKey)
+	if !ok {
+		writeGenAIError(w, http.StatusUnauthorized, "API key not valid. Please pass a valid API key.")
+	}
+	return r, ok
+}
+
+func decodeGenAIRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	defer func() { _ = r.Body.Close() }()
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeGenAIError(w, http.StatusBadRequest, "invalid JSON payload: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeGenAIError writes a Gemini API error; the status name is derived
+// from the HTTP status the way Google APIs pair them.
+func writeGenAIError(w http.ResponseWriter, status int, message string) {
+	name := "INTERNAL"
+	switch status {
+	case http.StatusBadRequest:
+		name = "INVALID_ARGUMENT"
+	case http.StatusUnauthorized:
+		name = "UNAUTHENTICATED"
+	case http.StatusNotFound:
+		name = "NOT_FOUND"
+	case http.StatusTooManyRequests:
+		name = "RESOURCE_EXHAUSTED"
+	}
+	writeJSON(w, status, genai.ErrorResponse{Error: genai.ErrorBody{Code: status, Message: message, Status: name}})
+}
@@ -0,0 +1,124 @@
+package proxy_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	nexus "github.com/xraph/nexus"
+)
+
+func postGenAI(t *testing.T, url, apiKey, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("X-Goog-Api-Key", apiKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	raw, _ := io.ReadAll(resp.Body)
+	return resp, raw
+}
+
+type genaiResponse struct {
+	Candidates []struct {
+		Content struct {
+			Role  string `json:"role"`
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+		FinishReason string `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		TotalTokenCount int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+	Error *struct {
+		Code   int    `json:"code"`
+		Status string `json:"status"`
+	} `json:"error"`
+}
+
+func TestProxy_GenerateContent(t *testing.T) {
+	t.Parallel()
+	rp, srv := newResponsesServer(t, nexus.WithAuth(keyAuth{}))
+	url := srv.URL + "/v1beta/models/gemini-2.5-flash:generateContent"
+
+	resp, raw := postGenAI(t, url, "nxs_good",
+		`{"systemInstruction":{"parts":[{"text":"be brief"}]},"contents":[{"role":"user","parts":[{"text":"hi"}]}],"generationConfig":{"maxOutputTokens":64}}`)
+	var out genaiResponse
+	_ = json.Unmarshal(raw, &out)
+	if resp.StatusCode != http.StatusOK || len(out.Candidates) != 1 {
+		t.Fatalf("generateContent = %d %s", resp.StatusCode, raw)
+	}
+	c := out.Candidates[0]
+	if c.Content.Role != "model" || c.Content.Parts[0].Text != "reply 1" || c.FinishReason != "STOP" || out.UsageMetadata.TotalTokenCount != 5 {
+		t.Fatalf("response = %s", raw)
+	}
+	if got := rp.last(); got.Model != "gemini-2.5-flash" || got.System != "be brief" || got.MaxTokens != 64 {
+		t.Fatalf("upstream request = %+v", got)
+	}
+	rp.mu.Lock()
+	tenant := rp.tenants[0]
+	rp.mu.Unlock()
+	if tenant != "acme" {
+		t.Fatalf("request tenant = %q, want acme", tenant)
+	}
+
+	resp, raw = postGenAI(t, url+"?key=nxs_bad", "", `{"contents":[{"parts":[{"text":"hi"}]}]}`)
+	_ = json.Unmarshal(raw, &out)
+	if resp.StatusCode != http.StatusUnauthorized || out.Error == nil || out.Error.Status != "UNAUTHENTICATED" {
+		t.Fatalf("bad key = %d %s", resp.StatusCode, raw)
+	}
+
+	resp, raw = postGenAI(t, url, "", `{"contents":[]}`)
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(raw), `"INVALID_ARGUMENT"`) {
+		t.Fatalf("empty contents = %d %s", resp.StatusCode, raw)
+	}
+
+	resp, _ = postGenAI(t, srv.URL+"/v1beta/models/gemini-2.5-flash:predict", "", `{}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown method status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestProxy_StreamGenerateContent(t *testing.T) {
+	t.Parallel()
+	_, srv := newResponsesServer(t)
+	url := srv.URL + "/v1beta/models/gemini-2.5-flash:streamGenerateContent"
+	body := `{"contents":[{"role":"user","parts":[{"text":"hi"}]}]}`
+
+	if resp, _ := postGenAI(t, url, "", body); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("without alt=sse status = %d, want 400", resp.StatusCode)
+	}
+
+	resp, raw := postGenAI(t, url+"?alt=sse", "", body)
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("content type = %q", resp.Header.Get("Content-Type"))
+	}
+	var text strings.Builder
+	var last genaiResponse
+	for _, line := range strings.Split(string(raw), "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		last = genaiResponse{}
+		if err := json.Unmarshal([]byte(data), &last); err != nil {
+			t.Fatalf("bad chunk %q: %v", data, err)
+		}
+		for _, p := range last.Candidates[0].Content.Parts {
+			text.WriteString(p.Text)
+		}
+	}
+	if text.String() != "hello" || last.Candidates[0].FinishReason != "STOP" || last.UsageMetadata.TotalTokenCount != 5 {
+		t.Fatalf("stream = %s", raw)
+	}
+}
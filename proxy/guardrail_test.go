@@ -0,0 +1,180 @@
+package proxy_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	nexus "github.com/xraph/nexus"
+	"github.com/xraph/nexus/guard"
+	"github.com/xraph/nexus/guard/guards"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/prompt"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/store"
+	"github.com/xraph/nexus/tenant"
+)
+
+// drainEngineStream streams a request through the engine's default
+// pipeline and returns the text that reached the client.
+func drainEngineStream(t *testing.T, opts ...nexus.Option) (string, error) {
+	t.Helper()
+	engine := nexus.NewEngine(append([]nexus.Option{nexus.WithProvider(&replyProvider{})}, opts...)...)
+	t.Cleanup(func() { _ = engine.Gateway().Shutdown(context.Background()) })
+
+	ctx := context.Background()
+	s, err := engine.CompleteStream(ctx, &provider.CompletionRequest{
+		Model:    "m",
+		Messages: []provider.Message{{Role: "user", Content: "hi"}},
+		Stream:   true,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = s.Close() }()
+
+	var text strings.Builder
+	for {
+		chunk, err := s.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return text.String(), nil
+		}
+		if err != nil {
+			return text.String(), err
+		}
+		text.WriteString(chunk.Delta.Content)
+	}
+}
+
+func TestEngine_StreamGuardrailBlocksInDefaultPipeline(t *testing.T) {
+	t.Parallel()
+	blocklist := nexus.WithGuard(guards.NewContentFilter(guard.ActionBlock, "hello"))
+
+	// The upstream streams "he" then "llo": the default sliding window
+	// sees the word across the chunk boundary.
+	text, err := drainEngineStream(t, blocklist)
+	var blocked *guard.BlockedError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("default strategy: err = %v, text %q; want BlockedError", err, text)
+	}
+	if strings.Contains(text, "hello") {
+		t.Fatalf("blocked text reached the client: %q", text)
+	}
+
+	// The configured strategy is the one installed.
+	text, err = drainEngineStream(t, blocklist, nexus.WithStreamGuardStrategy(guard.StrategyChunkwise, 0))
+	if err != nil || text != "hello" {
+		t.Fatalf("chunkwise: text %q, err %v; want the split word to pass", text, err)
+	}
+}
+
+func TestEngine_InputGuardrailsSeeRenderedPromptTemplate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	engine := nexus.NewEngine(
+		nexus.WithProvider(&replyProvider{}),
+		nexus.WithGuard(guards.NewContentFilter(guard.ActionBlock, "forbidden")),
+	)
+	t.Cleanup(func() { _ = engine.Gateway().Shutdown(ctx) })
+	if _, err := engine.Gateway().Prompts().Create(ctx, &prompt.CreateInput{Name: "support", Content: "You help {{.customer}}."}); err != nil {
+		t.Fatal(err)
+	}
+
+	complete := func(customer string) error {
+		_, err := engine.Complete(ctx, &provider.CompletionRequest{
+			Model:           "m",
+			Messages:        []provider.Message{{Role: "user", Content: "hi"}},
+			PromptID:        "support",
+			PromptVariables: map[string]any{"customer": customer},
+		})
+		return err
+	}
+	if err := complete("Globex"); err != nil {
+		t.Fatalf("clean variables: %v", err)
+	}
+	// The variable only appears in the rendered system message.
+	if err := complete("forbidden"); err == nil || !strings.Contains(err.Error(), "blocked") {
+		t.Fatalf("err = %v, want a block for a guarded word in a prompt variable", err)
+	}
+}
+
+// flakyTenants counts tenant lookups and fails them while down is set.
+type flakyTenants struct {
+	tenant.Store
+	lookups atomic.Int32
+	down    atomic.Bool
+}
+
+func (s *flakyTenants) FindByID(ctx context.Context, tenantID string) (*tenant.Tenant, error) {
+	s.lookups.Add(1)
+	if s.down.Load() {
+		return nil, errors.New("tenant store unavailable")
+	}
+	return s.Store.FindByID(ctx, tenantID)
+}
+
+type flakyStore struct {
+	store.Store
+	tenants *flakyTenants
+}
+
+func (s flakyStore) Tenants() tenant.Store { return s.tenants }
+
+func TestEngine_TenantGuardrailPolicyIsCachedAndFailsClosed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	mem := store.NewMemory()
+	tenants := &flakyTenants{Store: mem.Tenants()}
+	engine := nexus.NewEngine(
+		nexus.WithProvider(&replyProvider{}),
+		nexus.WithDatabase(flakyStore{Store: mem, tenants: tenants}),
+		nexus.WithGuardrailPolicy("strict", guards.NewContentFilter(guard.ActionBlock, "forbidden")),
+		nexus.WithGuardrailPolicy("lenient"),
+		nexus.WithDefaultGuardrailPolicy("lenient"),
+	)
+	t.Cleanup(func() { _ = engine.Gateway().Shutdown(ctx) })
+	tn, err := engine.Gateway().Tenants().Create(ctx, &tenant.CreateInput{
+		Name: "Clinic", Slug: "clinic", Config: &tenant.Config{GuardrailPolicy: "strict"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tenantCtx := pipeline.WithTenantID(ctx, tn.ID.String())
+	complete := func(content string) error {
+		_, err := engine.Complete(tenantCtx, &provider.CompletionRequest{
+			Model:    "m",
+			Messages: []provider.Message{{Role: "user", Content: content}},
+		})
+		return err
+	}
+	for range 3 {
+		if err := complete("forbidden"); err == nil {
+			t.Fatal("strict policy allowed blocked content")
+		}
+	}
+	if n := tenants.lookups.Load(); n != 1 {
+		t.Fatalf("tenant looked up %d times, want once", n)
+	}
+
+	// A tenant whose policy cannot be read is refused, not served under the
+	// lenient default.
+	down := nexus.NewEngine(
+		nexus.WithProvider(&replyProvider{}),
+		nexus.WithDatabase(flakyStore{Store: mem, tenants: tenants}),
+		nexus.WithGuardrailPolicy("strict", guards.NewContentFilter(guard.ActionBlock, "forbidden")),
+		nexus.WithGuardrailPolicy("lenient"),
+		nexus.WithDefaultGuardrailPolicy("lenient"),
+	)
+	t.Cleanup(func() { _ = down.Gateway().Shutdown(ctx) })
+	tenants.down.Store(true)
+	if _, err := down.Complete(tenantCtx, &provider.CompletionRequest{
+		Model:    "m",
+		Messages: []provider.Message{{Role: "user", Content: "hello"}},
+	}); err == nil {
+		t.Fatal("request served while the tenant's policy could not be read")
+	}
+}
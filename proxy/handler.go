@@ -1,19 +1,31 @@
 package proxy
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"time"
 
 	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/id"
 	"github.com/xraph/nexus/pipeline"
 	"github.com/xraph/nexus/provider"
 )
 
 // handleChatCompletions handles POST /v1/chat/completions
 func (p *Proxy) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if p.resume != nil {
+		if _, _, ok := httpstream.ResumePoint(r); ok {
+			p.handleResumeCompletion(w, r)
+			return
+		}
+	}
+
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid_request_error", "failed to read request body")
@@ -39,6 +51,14 @@ func (p *Proxy) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
+	// Background mode: run detached and answer with the response ID.
+	var mode backgroundMode
+	_ = json.Unmarshal(body, &mode) //nolint:errcheck // body already decoded above
+	if mode.Background {
+		p.handleBackgroundCompletion(w, r, &req, mode.Metadata)
+		return
+	}
+
 	// Streaming response
 	if req.Stream {
 		p.handleStreamingCompletion(w, r, &req)
@@ -61,21 +81,79 @@ func (p *Proxy) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
 func (p *Proxy) handleStreamingCompletion(w http.ResponseWriter, r *http.Request, req *provider.CompletionRequest) {
 	ctx, cancel := p.streamContext(r.Context())
 	defer cancel()
-	stream, err := p.engine.CompleteStream(ctx, req)
+
+	encoder := httpstream.Negotiate(r, p.encoders)
+	if encoder == nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", "no stream encoder available")
+		return
+	}
+
+	requestID := pipeline.RequestID(ctx)
+	var stream provider.Stream
+	var err error
+	if p.resume != nil {
+		stream, requestID, err = p.openResumable(r.Context(), req)
+	} else {
+		stream, err = p.engine.CompleteStream(ctx, req)
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
 		return
 	}
 
+	httpstream.Run(ctx, w, stream, encoder, httpstream.RunOptions{
+		RequestID: requestID,
+	})
+}
+
+// openResumable starts a completion stream that outlives the client
+// connection: the upstream drains into the resume store, still bounded by
+// Shutdown, and the returned stream reads it back from the first frame.
+// A request ID is minted when the caller did not provide one, since it is
+// the handle a reconnecting client resumes by.
+func (p *Proxy) openResumable(reqCtx context.Context, req *provider.CompletionRequest) (provider.Stream, string, error) {
+	requestID := pipeline.RequestID(reqCtx)
+	if requestID == "" {
+		requestID = id.NewRequestID().String()
+		reqCtx = pipeline.WithRequestID(reqCtx, requestID)
+	}
+
+	upCtx, upCancel := p.streamContext(context.WithoutCancel(reqCtx))
+	stream, err := p.engine.CompleteStream(upCtx, req)
+	if err != nil {
+		upCancel()
+		return nil, "", err
+	}
+	return p.resume.Track(upCtx, requestID, pipeline.TenantID(reqCtx), stream, upCancel), requestID, nil
+}
+
+// handleResumeCompletion reattaches a reconnecting client to a buffered
+// stream, replaying the events after its Last-Event-ID.
+func (p *Proxy) handleResumeCompletion(w http.ResponseWriter, r *http.Request) {
+	requestID, after, ok := httpstream.ResumePoint(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "Last-Event-ID or resume is required")
+		return
+	}
+
+	ctx, cancel := p.streamContext(r.Context())
+	defer cancel()
+
 	encoder := httpstream.Negotiate(r, p.encoders)
 	if encoder == nil {
-		_ = stream.Close()
 		writeError(w, http.StatusInternalServerError, "internal_error", "no stream encoder available")
 		return
 	}
 
+	stream, ok := p.resume.Resume(ctx, requestID, pipeline.TenantID(ctx), after)
+	if !ok {
+		writeError(w, http.StatusNotFound, "invalid_request_error", "no such stream: "+requestID)
+		return
+	}
+
 	httpstream.Run(ctx, w, stream, encoder, httpstream.RunOptions{
-		RequestID: pipeline.RequestID(ctx),
+		RequestID:   requestID,
+		LastEventID: after,
 	})
 }
 
@@ -89,25 +167,24 @@ func (p *Proxy) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
 	defer func() { _ = r.Body.Close() }()
 
 	// Parse request — accept string or array of strings for "input"
-	var raw map[string]json.RawMessage
+	var raw struct {
+		provider.EmbeddingRequest
+		Input json.RawMessage `json:"input"`
+	}
 	if unmarshalErr := json.Unmarshal(body, &raw); unmarshalErr != nil {
 		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON")
 		return
 	}
 
-	var req provider.EmbeddingRequest
-	if modelRaw, ok := raw["model"]; ok {
-		_ = json.Unmarshal(modelRaw, &req.Model) //nolint:errcheck // best-effort; validated below
-	}
-
-	if inputRaw, ok := raw["input"]; ok {
+	req := raw.EmbeddingRequest
+	if len(raw.Input) > 0 {
 		// Try as string first
 		var single string
-		if unmarshalErr := json.Unmarshal(inputRaw, &single); unmarshalErr == nil {
+		if unmarshalErr := json.Unmarshal(raw.Input, &single); unmarshalErr == nil {
 			req.Input = []string{single}
 		} else {
 			// Try as array of strings
-			_ = json.Unmarshal(inputRaw, &req.Input) //nolint:errcheck // best-effort; validated below
+			_ = json.Unmarshal(raw.Input, &req.Input) //nolint:errcheck // best-effort; validated below
 		}
 	}
 
@@ -119,6 +196,10 @@ func (p *Proxy) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_request_error", "input is required")
 		return
 	}
+	if req.EncodingFormat != "" && req.EncodingFormat != "float" && req.EncodingFormat != "base64" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "encoding_format must be float or base64")
+		return
+	}
 
 	resp, err := p.engine.Embed(r.Context(), &req)
 	if err != nil {
@@ -127,10 +208,84 @@ func (p *Proxy) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Convert to OpenAI embeddings response format
-	openAIResp := toOpenAIEmbeddingResponse(resp)
+	openAIResp := toOpenAIEmbeddingResponse(resp, req.EncodingFormat == "base64")
 	writeJSON(w, http.StatusOK, openAIResp)
 }
 
+// handleImageGenerations handles POST /v1/images/generations
+func (p *Proxy) handleImageGenerations(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "failed to read request body")
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	var req provider.ImageRequest
+	if unmarshalErr := json.Unmarshal(body, &req); unmarshalErr != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON")
+		return
+	}
+	if req.Prompt == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "prompt is required")
+		return
+	}
+
+	resp, err := p.engine.GenerateImage(r.Context(), &req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, openAIImageResponse{
+		Created: resp.Created,
+		Data:    resp.Data,
+	})
+}
+
+// handleRerank handles POST /v1/rerank with Cohere's request and response
+// schema.
+func (p *Proxy) handleRerank(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "failed to read request body")
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	var req provider.RerankRequest
+	if unmarshalErr := json.Unmarshal(body, &req); unmarshalErr != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON: "+unmarshalErr.Error())
+		return
+	}
+	switch {
+	case req.Model == "":
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "model is required")
+		return
+	case req.Query == "":
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "query is required")
+		return
+	case len(req.Documents) == 0:
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "documents is required")
+		return
+	}
+
+	resp, err := p.engine.Rerank(r.Context(), &req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cohereRerankResponse{
+		ID:      resp.ID,
+		Results: resp.Results,
+		Meta: cohereRerankMeta{BilledUnits: cohereBilledUnits{
+			SearchUnits: resp.SearchUnits,
+			InputTokens: resp.Usage.TotalTokens,
+		}},
+	})
+}
+
 // handleListModels handles GET /v1/models
 func (p *Proxy) handleListModels(w http.ResponseWriter, r *http.Request) {
 	models, err := p.engine.ListModels(r.Context())
@@ -235,6 +390,26 @@ type openAIModel struct {
 	OwnedBy string `json:"owned_by"`
 }
 
+type openAIImageResponse struct {
+	Created int64            `json:"created"`
+	Data    []provider.Image `json:"data"`
+}
+
+type cohereRerankResponse struct {
+	ID      string                  `json:"id,omitempty"`
+	Results []provider.RerankResult `json:"results"`
+	Meta    cohereRerankMeta        `json:"meta"`
+}
+
+type cohereRerankMeta struct {
+	BilledUnits cohereBilledUnits `json:"billed_units"`
+}
+
+type cohereBilledUnits struct {
+	SearchUnits int `json:"search_units,omitempty"`
+	InputTokens int `json:"input_tokens,omitempty"`
+}
+
 type openAIEmbeddingResponse struct {
 	Object string            `json:"object"`
 	Data   []openAIEmbedding `json:"data"`
@@ -243,9 +418,9 @@ type openAIEmbeddingResponse struct {
 }
 
 type openAIEmbedding struct {
-	Object    string    `json:"object"`
-	Embedding []float64 `json:"embedding"`
-	Index     int       `json:"index"`
+	Object    string `json:"object"`
+	Embedding any    `json:"embedding"` // []float64, or a base64 string
+	Index     int    `json:"index"`
 }
 
 type openAIError struct {
@@ -290,7 +465,7 @@ func toOpenAIChatResponse(resp *provider.CompletionResponse) *openAIChatResponse
 	}
 }
 
-func toOpenAIEmbeddingResponse(resp *provider.EmbeddingResponse) *openAIEmbeddingResponse {
+func toOpenAIEmbeddingResponse(resp *provider.EmbeddingResponse, base64Encode bool) *openAIEmbeddingResponse {
 	data := make([]openAIEmbedding, len(resp.Embeddings))
 	for i, emb := range resp.Embeddings {
 		data[i] = openAIEmbedding{
@@ -298,6 +473,9 @@ func toOpenAIEmbeddingResponse(resp *provider.EmbeddingResponse) *openAIEmbeddin
 			Embedding: emb,
 			Index:     i,
 		}
+		if base64Encode {
+			data[i].Embedding = encodeEmbedding(emb)
+		}
 	}
 
 	return &openAIEmbeddingResponse{
@@ -329,3 +507,13 @@ func writeError(w http.ResponseWriter, status int, errType, message string) {
 		},
 	})
 }
+
+// encodeEmbedding packs a vector as little-endian float32s in base64, the
+// format the OpenAI SDKs request by default.
+func encodeEmbedding(emb []float64) string {
+	buf := make([]byte, 4*len(emb))
+	for i, v := range emb {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(float32(v)))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,138 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/messages"
+	"github.com/xraph/nexus/pipeline"
+)
+
+// handleMessages handles POST /v1/messages, the Anthropic Messages API.
+// The request is translated to a chat completion, so Anthropic SDK
+// clients can reach every provider.
+func (p *Proxy) handleMessages(w http.ResponseWriter, r *http.Request) {
+	r, ok := p.anthropicAuth(w, r)
+	if !ok {
+		return
+	}
+	req, ok := decodeMessagesRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.MaxTokens <= 0 {
+		writeAnthropicError(w, http.StatusBadRequest, "invalid_request_error", "max_tokens: field required")
+		return
+	}
+	creq, err := messages.ToCompletionRequest(req)
+	if err != nil {
+		writeAnthropicError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	if !req.Stream {
+		resp, err := p.engine.Complete(ctx, creq)
+		if err != nil {
+			writeAnthropicError(w, http.StatusInternalServerError, "api_error", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, messages.FromCompletion(resp))
+		return
+	}
+
+	ctx, cancel := p.streamContext(ctx)
+	defer cancel()
+
+	encoder := httpstream.ForStream(p.encoders.Lookup("anthropic"))
+	if encoder == nil {
+		encoder = httpstream.NewSSEAnthropicEncoder()
+	}
+	stream, err := p.engine.CompleteStream(ctx, creq)
+	if err != nil {
+		writeAnthropicError(w, http.StatusInternalServerError, "api_error", err.Error())
+		return
+	}
+	httpstream.Run(ctx, w, stream, encoder, httpstream.RunOptions{
+		RequestID: pipeline.RequestID(ctx),
+	})
+}
+
+// handleCountTokens handles POST /v1/messages/count_tokens. The count is
+// the gateway's estimate; no provider is called.
+func (p *Proxy) handleCountTokens(w http.ResponseWriter, r *http.Request) {
+	r, ok := p.anthropicAuth(w, r)
+	if !ok {
+		return
+	}
+	req, ok := decodeMessagesRequest(w, r)
+	if !ok {
+		return
+	}
+	creq, err := messages.ToCompletionRequest(req)
+	if err != nil {
+		writeAnthropicError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	n, err := p.engine.CountTokens(r.Context(), creq)
+	if err != nil {
+		writeAnthropicError(w, http.StatusInternalServerError, "api_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, messages.CountTokensResponse{InputTokens: n})
+}
+
+// anthropicAuth authenticates the x-api-key header the Anthropic SDKs
+// send. See apiKeyAuth.
+func (p *Proxy) anthropicAuth(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	r, ok := p.apiKeyAuth(r, r.Header.Get("X-Api-Key"))
+	if !ok {
+		writeAnthropicError(w, http.StatusUnauthorized, "authentication_error", "invalid x-api-key")
+	}
+	return r, ok
+}
+
+// apiKeyAuth authenticates an API key sent the way a vendor SDK sends it
+// with the gateway's auth provider and scopes the request to the key's
+// tenant. Requests already scoped upstream, or sent without a key, pass
+// through unchanged. It reports false when the key is rejected; the
+// caller writes the error in its API's shape.
+func (p *Proxy) apiKeyAuth(r *http.Request, apiKey string) (*http.Request, bool) {
+	if apiKey == "" || pipeline.TenantID(r.Context()) != "" {
+		return r, true
+	}
+	authn := p.engine.Gateway().Auth()
+	if authn == nil {
+		return r, true
+	}
+	claims, err := authn.AuthenticateAPIKey(r.Context(), apiKey)
+	if err != nil || claims == nil {
+		return r, false
+	}
+	ctx := r.Context()
+	if claims.TenantID != "" {
+		ctx = pipeline.WithTenantID(ctx, claims.TenantID)
+	}
+	if claims.KeyID != "" {
+		ctx = pipeline.WithKeyID(ctx, claims.KeyID)
+	}
+	return r.WithContext(ctx), true
+}
+
+func decodeMessagesRequest(w http.ResponseWriter, r *http.Request) (*messages.Request, bool) {
+	defer func() { _ = r.Body.Close() }()
+	var req messages.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAnthropicError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON: "+err.Error())
+		return nil, false
+	}
+	return &req, true
+}
+
+func writeAnthropicError(w http.ResponseWriter, status int, errType, message string) {
+	writeJSON(w, status, messages.ErrorResponse{
+		Type:  "error",
+		Error: messages.ErrorBody{Type: errType, Message: message},
+	})
+}
@@ -0,0 +1,133 @@
+package proxy_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	nexus "github.com/xraph/nexus"
+	"github.com/xraph/nexus/auth"
+)
+
+// keyAuth accepts one API key, scoped to tenant "acme".
+type keyAuth struct{}
+
+func (keyAuth) Authenticate(_ context.Context) (*auth.Claims, error) {
+	return nil, errors.New("not used")
+}
+
+func (keyAuth) AuthenticateAPIKey(_ context.Context, apiKey string) (*auth.Claims, error) {
+	if apiKey != "nxs_good" {
+		return nil, errors.New("unknown key")
+	}
+	return &auth.Claims{TenantID: "acme", KeyID: "key_1"}, nil
+}
+
+func postMessages(t *testing.T, url, apiKey, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Anthropic-Version", "2023-06-01")
+	if apiKey != "" {
+		req.Header.Set("X-Api-Key", apiKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	raw, _ := io.ReadAll(resp.Body)
+	return resp, raw
+}
+
+func TestProxy_Messages(t *testing.T) {
+	t.Parallel()
+	rp, srv := newResponsesServer(t, nexus.WithAuth(keyAuth{}))
+
+	resp, raw := postMessages(t, srv.URL+"/v1/messages", "nxs_good",
+		`{"model":"x","max_tokens":64,"system":"be brief","messages":[{"role":"user","content":"hi"}]}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d: %s", resp.StatusCode, raw)
+	}
+	var msg struct {
+		ID         string `json:"id"`
+		Type       string `json:"type"`
+		StopReason string `json:"stop_reason"`
+		Content    []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	}
+	_ = json.Unmarshal(raw, &msg)
+	if msg.Type != "message" || !strings.HasPrefix(msg.ID, "msg_") || msg.StopReason != "end_turn" ||
+		len(msg.Content) != 1 || msg.Content[0].Text != "reply 1" || msg.Usage.InputTokens != 3 {
+		t.Fatalf("message = %s", raw)
+	}
+	if got := rp.last(); got.System != "be brief" || got.MaxTokens != 64 {
+		t.Fatalf("upstream request = %+v", got)
+	}
+	rp.mu.Lock()
+	tenant := rp.tenants[0]
+	rp.mu.Unlock()
+	if tenant != "acme" {
+		t.Fatalf("request tenant = %q, want acme", tenant)
+	}
+
+	resp, raw = postMessages(t, srv.URL+"/v1/messages", "nxs_bad",
+		`{"model":"x","max_tokens":64,"messages":[{"role":"user","content":"hi"}]}`)
+	if resp.StatusCode != http.StatusUnauthorized || !strings.Contains(string(raw), `"authentication_error"`) {
+		t.Fatalf("bad key = %d %s", resp.StatusCode, raw)
+	}
+
+	resp, raw = postMessages(t, srv.URL+"/v1/messages", "",
+		`{"model":"x","messages":[{"role":"user","content":"hi"}]}`)
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(raw), `"type":"error"`) {
+		t.Fatalf("missing max_tokens = %d %s", resp.StatusCode, raw)
+	}
+}
+
+func TestProxy_MessagesStream(t *testing.T) {
+	t.Parallel()
+	_, srv := newResponsesServer(t)
+
+	resp, raw := postMessages(t, srv.URL+"/v1/messages", "",
+		`{"model":"x","max_tokens":64,"stream":true,"messages":[{"role":"user","content":"hi"}]}`)
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("content type = %q", resp.Header.Get("Content-Type"))
+	}
+	var events []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		if ev, ok := strings.CutPrefix(line, "event: "); ok {
+			events = append(events, ev)
+		}
+	}
+	want := "message_start,content_block_start,content_block_delta,content_block_delta,content_block_stop,message_delta,message_stop"
+	if strings.Join(events, ",") != want {
+		t.Fatalf("events = %v\n%s", events, raw)
+	}
+	if !strings.Contains(string(raw), `"stop_reason":"end_turn"`) {
+		t.Fatalf("missing stop reason:\n%s", raw)
+	}
+}
+
+func TestProxy_CountTokens(t *testing.T) {
+	t.Parallel()
+	_, srv := newResponsesServer(t)
+
+	resp, raw := postMessages(t, srv.URL+"/v1/messages/count_tokens", "",
+		`{"model":"claude-sonnet-4","system":"You are a helpful assistant.","messages":[{"role":"user","content":"Hello, how are you today?"}]}`)
+	var out struct {
+		InputTokens int `json:"input_tokens"`
+	}
+	_ = json.Unmarshal(raw, &out)
+	if resp.StatusCode != http.StatusOK || out.InputTokens <= 0 {
+		t.Fatalf("count_tokens = %d %s", resp.StatusCode, raw)
+	}
+}
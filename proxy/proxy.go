@@ -14,6 +14,15 @@
 // Then from any OpenAI SDK:
 //
 //	client = OpenAI(base_url="http://localhost:8080/v1", api_key="nxs_...")
+//
+// The proxy also serves the Anthropic Messages API at /v1/messages, so
+// Anthropic SDK clients can reach any provider too:
+//
+//	client = Anthropic(base_url="http://localhost:8080", api_key="nxs_...")
+//
+// and the Gemini API under /v1beta/models, for Google GenAI SDK clients:
+//
+//	client = genai.Client(api_key="nxs_...", http_options={"base_url": "http://localhost:8080"})
 package proxy
 
 import (
@@ -33,6 +42,7 @@ type Proxy struct {
 	encoders   *httpstream.Registry
 	wsOptions  httpstream.WSOptions
 	wsDisabled bool
+	resume     *httpstream.ResumeStore
 
 	// shutdown wires every streaming request's context to a single
 	// gateway-scoped cancel signal, so Shutdown can tear active SSE/WS
@@ -79,6 +89,14 @@ func WithoutWebSocket() Option {
 	return func(p *Proxy) { p.wsDisabled = true }
 }
 
+// WithResume makes streaming chat completions resumable: events carry
+// sequence IDs, the upstream keeps draining into store after the client
+// disconnects, and a reconnect with Last-Event-ID (or `?resume=<request_id>`)
+// replays the missed frames before continuing live.
+func WithResume(store *httpstream.ResumeStore) Option {
+	return func(p *Proxy) { p.resume = store }
+}
+
 // New creates a new OpenAI-compatible proxy.
 func New(engine *nexus.Engine, opts ...Option) *Proxy {
 	baseCtx, baseCancel := context.WithCancel(context.Background())
@@ -103,12 +121,19 @@ func New(engine *nexus.Engine, opts ...Option) *Proxy {
 // After Shutdown is called, any new requests still receive normal handling
 // because http.ServeMux remains live, but their contexts are pre-canceled,
 // which the streaming runner detects and tears down promptly.
-func (p *Proxy) Shutdown(_ context.Context) error {
+//
+// Background responses are not tied to a connection: Shutdown waits for
+// them to finish until ctx ends, then stops the rest, which are persisted
+// as incomplete with the frames produced so far.
+func (p *Proxy) Shutdown(ctx context.Context) error {
 	p.shutdownOnce.Do(func() {
 		if p.baseCancel != nil {
 			p.baseCancel()
 		}
 	})
+	if bg := p.engine.Gateway().Background(); bg != nil {
+		return bg.Shutdown(ctx)
+	}
 	return nil
 }
 
@@ -131,8 +156,8 @@ func (p *Proxy) streamContext(reqCtx context.Context) (context.Context, context.
 func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Add CORS headers for browser-based clients
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Api-Key, Anthropic-Version, Anthropic-Beta, X-Goog-Api-Key, X-Goog-Api-Client")
 
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusOK)
@@ -144,7 +169,30 @@ func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func (p *Proxy) registerRoutes() {
 	p.mux.HandleFunc("POST /v1/chat/completions", p.handleChatCompletions)
+	if p.resume != nil {
+		p.mux.HandleFunc("GET /v1/chat/completions", p.handleResumeCompletion)
+	}
 	p.mux.HandleFunc("POST /v1/embeddings", p.handleEmbeddings)
+	p.mux.HandleFunc("POST /v1/images/generations", p.handleImageGenerations)
+	p.mux.HandleFunc("POST /v1/rerank", p.handleRerank)
+	p.mux.HandleFunc("POST /v1/audio/transcriptions", p.handleAudioTranscriptions)
+	p.mux.HandleFunc("POST /v1/audio/translations", p.handleAudioTranslations)
+	p.mux.HandleFunc("POST /v1/audio/speech", p.handleAudioSpeech)
+	p.mux.HandleFunc("POST /v1/files", p.handleUploadFile)
+	p.mux.HandleFunc("GET /v1/files", p.handleListFiles)
+	p.mux.HandleFunc("GET /v1/files/{id}", p.handleGetFile)
+	p.mux.HandleFunc("GET /v1/files/{id}/content", p.handleFileContent)
+	p.mux.HandleFunc("DELETE /v1/files/{id}", p.handleDeleteFile)
+	p.mux.HandleFunc("POST /v1/batches", p.handleCreateBatch)
+	p.mux.HandleFunc("GET /v1/batches", p.handleListBatches)
+	p.mux.HandleFunc("GET /v1/batches/{id}", p.handleGetBatch)
+	p.mux.HandleFunc("POST /v1/batches/{id}/cancel", p.handleCancelBatch)
+	p.mux.HandleFunc("POST /v1/messages", p.handleMessages)
+	p.mux.HandleFunc("POST /v1/messages/count_tokens", p.handleCountTokens)
+	p.mux.HandleFunc("POST /v1beta/models/{call}", p.handleGenAI)
+	p.mux.HandleFunc("POST /v1/responses", p.handleResponses)
+	p.mux.HandleFunc("GET /v1/responses/{id}", p.handleGetResponse)
+	p.mux.HandleFunc("POST /v1/responses/{id}/cancel", p.handleCancelResponse)
 	p.mux.HandleFunc("GET /v1/models", p.handleListModels)
 	p.mux.HandleFunc("GET /v1/models/{model}", p.handleGetModel)
 	p.mux.HandleFunc("GET /health", p.handleHealth)
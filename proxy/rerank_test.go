@@ -0,0 +1,117 @@
+package proxy_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	nexus "github.com/xraph/nexus"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/proxy"
+)
+
+// rerankProvider ranks documents by reverse position and bills per search.
+type rerankProvider struct {
+	got *provider.RerankRequest
+}
+
+func (p *rerankProvider) Name() string { return "reranker" }
+func (p *rerankProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Embeddings: true, Rerank: true}
+}
+func (p *rerankProvider) Models(_ context.Context) ([]provider.Model, error) { return nil, nil }
+func (p *rerankProvider) Complete(_ context.Context, _ *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *rerankProvider) CompleteStream(_ context.Context, _ *provider.CompletionRequest) (provider.Stream, error) {
+	return nil, errors.New("not used")
+}
+func (p *rerankProvider) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *rerankProvider) Healthy(_ context.Context) bool { return true }
+
+func (p *rerankProvider) Rerank(_ context.Context, req *provider.RerankRequest) (*provider.RerankResponse, error) {
+	p.got = req
+	results := make([]provider.RerankResult, 0, len(req.Documents))
+	for i := len(req.Documents) - 1; i >= 0; i-- {
+		results = append(results, provider.RerankResult{Index: i, RelevanceScore: float64(i+1) / 10})
+	}
+	if req.TopN > 0 && req.TopN < len(results) {
+		results = results[:req.TopN]
+	}
+	return &provider.RerankResponse{ID: "rr-1", Provider: "reranker", Model: req.Model, Results: results, SearchUnits: 1}, nil
+}
+
+func TestRerank(t *testing.T) {
+	rp := &rerankProvider{}
+	engine := nexus.NewEngine(nexus.WithProvider(rp))
+	srv := httptest.NewServer(proxy.New(engine, proxy.WithoutWebSocket()))
+	t.Cleanup(srv.Close)
+
+	body := `{"model":"rerank-v3.5","query":"capital of France","top_n":2,"return_documents":true,
+		"documents":["Berlin",{"text":"Madrid"},"Paris"]}`
+	resp, err := http.Post(srv.URL+"/v1/rerank", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+
+	if rp.got == nil || len(rp.got.Documents) != 3 || rp.got.Documents[1] != "Madrid" {
+		t.Fatalf("provider got %+v", rp.got)
+	}
+
+	var out struct {
+		ID      string `json:"id"`
+		Results []struct {
+			Index          int     `json:"index"`
+			RelevanceScore float64 `json:"relevance_score"`
+			Document       *struct {
+				Text string `json:"text"`
+			} `json:"document"`
+		} `json:"results"`
+		Meta struct {
+			BilledUnits struct {
+				SearchUnits int `json:"search_units"`
+			} `json:"billed_units"`
+		} `json:"meta"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != "rr-1" || len(out.Results) != 2 || out.Meta.BilledUnits.SearchUnits != 1 {
+		t.Fatalf("response = %+v", out)
+	}
+	if r := out.Results[0]; r.Index != 2 || r.Document == nil || r.Document.Text != "Paris" {
+		t.Errorf("top result = %+v, want Paris with its document filled in", r)
+	}
+}
+
+func TestRerankValidation(t *testing.T) {
+	engine := nexus.NewEngine(nexus.WithProvider(&rerankProvider{}))
+	srv := httptest.NewServer(proxy.New(engine, proxy.WithoutWebSocket()))
+	t.Cleanup(srv.Close)
+
+	for _, body := range []string{
+		`{"query":"q","documents":["a"]}`,
+		`{"model":"m","documents":["a"]}`,
+		`{"model":"m","query":"q","documents":[]}`,
+		`{"model":"m","query":"q","documents":[{"title":"no text"}]}`,
+	} {
+		resp, err := http.Post(srv.URL+"/v1/rerank", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/id"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/responses"
+)
+
+// handleResponses handles POST /v1/responses, the OpenAI Responses API.
+// The request is translated to a chat completion, so every provider can
+// serve it; stored responses can be fetched and continued with
+// previous_response_id.
+func (p *Proxy) handleResponses(w http.ResponseWriter, r *http.Request) {
+	var req responses.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON: "+err.Error())
+		return
+	}
+	defer func() { _ = r.Body.Close() }()
+
+	ctx := r.Context()
+
+	var history []provider.Message
+	if req.PreviousResponseID != "" {
+		prev, err := p.engine.GetBackground(ctx, req.PreviousResponseID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+			return
+		}
+		if prev == nil || prev.TenantID != pipeline.TenantID(ctx) {
+			writeError(w, http.StatusNotFound, "invalid_request_error", "no such response: "+req.PreviousResponseID)
+			return
+		}
+		if prev.Result == nil || prev.Request == nil {
+			writeError(w, http.StatusBadRequest, "invalid_request_error", "previous response "+prev.ID+" has no output to continue from")
+			return
+		}
+		history = responses.Conversation(prev.Request, prev.Result)
+	}
+
+	creq, err := responses.ToCompletionRequest(&req, history)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	creq.TenantID = pipeline.TenantID(ctx)
+	creq.KeyID = pipeline.KeyID(ctx)
+	// The pipeline rewrites the request it runs (alias, RAG, guardrail
+	// redaction); the stored copy is what the client sent, so a
+	// continuation replays the conversation rather than the injections.
+	stored := *creq
+	stored.Messages = slices.Clone(creq.Messages)
+
+	if req.Background {
+		p.handleBackgroundResponse(w, r, &req, creq)
+		return
+	}
+
+	base := responses.NewResponse(id.NewResponseID().String(), time.Now().Unix(), &req)
+	if req.Stream {
+		p.handleStreamingResponse(w, r, &req, creq, &stored, base)
+		return
+	}
+
+	result, err := p.engine.Complete(ctx, creq)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+	base.Finish(result)
+	if req.Stored() {
+		if err := p.recordResponse(ctx, base, &stored, result); err != nil {
+			writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, base)
+}
+
+// handleStreamingResponse streams a response as Responses API events and
+// stores the merged result once the stream completes.
+func (p *Proxy) handleStreamingResponse(w http.ResponseWriter, r *http.Request, req *responses.Request, creq, stored *provider.CompletionRequest, base *responses.Response) {
+	ctx, cancel := p.streamContext(r.Context())
+	defer cancel()
+
+	stream, err := p.engine.CompleteStream(ctx, creq)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+	if req.Stored() {
+		stream = &recordingStream{
+			inner: stream,
+			acc:   provider.NewAccumulator(),
+			onDone: func(result *provider.CompletionResponse) {
+				done := *base
+				done.Finish(result)
+				_ = p.recordResponse(context.WithoutCancel(ctx), &done, stored, result) //nolint:errcheck // the client already has the stream
+			},
+		}
+	}
+
+	httpstream.Run(ctx, w, stream, httpstream.NewSSEResponsesEncoder(base), httpstream.RunOptions{
+		RequestID: base.ID,
+	})
+}
+
+// handleBackgroundResponse runs a response on a background worker. With
+// stream=true the client follows it as it runs; otherwise it gets the
+// queued response and polls.
+func (p *Proxy) handleBackgroundResponse(w http.ResponseWriter, r *http.Request, req *responses.Request, creq *provider.CompletionRequest) {
+	if !req.Stored() {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "background responses must be stored")
+		return
+	}
+	resp, err := p.engine.CreateBackground(r.Context(), &background.CreateInput{
+		Request:  creq,
+		Endpoint: background.EndpointResponses,
+		Metadata: req.Metadata,
+	})
+	if errors.Is(err, background.ErrClosed) {
+		writeError(w, http.StatusServiceUnavailable, "internal_error", err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	if !req.Stream {
+		writeJSON(w, http.StatusOK, toResponsesObject(resp))
+		return
+	}
+
+	ctx, cancel := p.streamContext(r.Context())
+	defer cancel()
+	httpstream.Run(ctx, w, background.Replay(p.engine.Gateway().Background(), resp.ID, 0, 0), responsesEncoder(resp), httpstream.RunOptions{
+		RequestID: resp.ID,
+	})
+}
+
+// recordResponse stores a finished foreground response.
+func (p *Proxy) recordResponse(ctx context.Context, resp *responses.Response, creq *provider.CompletionRequest, result *provider.CompletionResponse) error {
+	now := time.Now()
+	return p.engine.RecordResponse(ctx, &background.Response{
+		ID:          resp.ID,
+		TenantID:    creq.TenantID,
+		KeyID:       creq.KeyID,
+		Status:      background.Status(resp.Status),
+		Model:       resp.Model,
+		Endpoint:    background.EndpointResponses,
+		Request:     creq,
+		Result:      result,
+		Metadata:    creq.Metadata,
+		CreatedAt:   time.Unix(resp.CreatedAt, 0),
+		CompletedAt: &now,
+	})
+}
+
+// responsesEncoder returns a Responses event encoder for replaying a
+// stored response.
+func responsesEncoder(resp *background.Response) *httpstream.SSEResponsesEncoder {
+	base := toResponsesObject(resp)
+	base.Status = responses.StatusInProgress
+	base.Error = nil
+	base.IncompleteDetails = nil
+	base.Usage = nil
+	return httpstream.NewSSEResponsesEncoder(base)
+}
+
+// toResponsesObject renders a stored response as a Responses API object.
+// Request parameters are recovered from the translated chat request.
+func toResponsesObject(resp *background.Response) *responses.Response {
+	req := responses.Request{
+		Model: resp.Model,
+		// Foreground responses are recorded already finished and never
+		// run on a worker, so they have no start time.
+		Background: resp.StartedAt != nil || resp.Status == background.StatusQueued,
+		Metadata:   resp.Metadata,
+	}
+	if creq := resp.Request; creq != nil {
+		req.Instructions = creq.System
+		req.Temperature = creq.Temperature
+		req.TopP = creq.TopP
+		req.MaxOutputTokens = creq.MaxTokens
+		req.ToolChoice = creq.ToolChoice
+		for _, t := range creq.Tools {
+			req.Tools = append(req.Tools, responses.Tool{
+				Type:        "function",
+				Name:        t.Function.Name,
+				Description: t.Function.Description,
+				Parameters:  t.Function.Parameters,
+			})
+		}
+	}
+
+	out := responses.NewResponse(resp.ID, resp.CreatedAt.Unix(), &req)
+	out.Status = string(resp.Status)
+	if resp.Result != nil {
+		out.Output = responses.OutputItems(resp.ID, resp.Result)
+		out.Usage = responses.NewUsage(&resp.Result.Usage)
+		if resp.Status == background.StatusIncomplete && len(resp.Result.Choices) > 0 {
+			_, out.IncompleteDetails = responses.FinishStatus(resp.Result.Choices[0].FinishReason)
+		}
+	}
+	if resp.Error != nil {
+		out.Error = &responses.Error{Code: resp.Error.Code, Message: resp.Error.Message}
+	}
+	return out
+}
+
+// recordingStream merges the chunks it passes through and hands the
+// merged response to onDone when the stream completes.
+type recordingStream struct {
+	inner  provider.Stream
+	acc    *provider.Accumulator
+	onDone func(*provider.CompletionResponse)
+}
+
+func (s *recordingStream) Next(ctx context.Context) (*provider.StreamChunk, error) {
+	chunk, err := s.inner.Next(ctx)
+	if errors.Is(err, io.EOF) && s.onDone != nil {
+		s.onDone(s.acc.Finalize(s.inner.Usage))
+		s.onDone = nil
+	}
+	if err == nil {
+		s.acc.Add(chunk)
+	}
+	return chunk, err
+}
+
+func (s *recordingStream) Close() error           { return s.inner.Close() }
+func (s *recordingStream) Usage() *provider.Usage { return s.inner.Usage() }
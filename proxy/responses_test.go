@@ -0,0 +1,258 @@
+package proxy_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	nexus "github.com/xraph/nexus"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/proxy"
+)
+
+// replyProvider answers every request with a numbered reply and keeps the
+// requests it saw, and the tenant each was made for.
+type replyProvider struct {
+	mu      sync.Mutex
+	reqs    []*provider.CompletionRequest
+	tenants []string
+}
+
+func (p *replyProvider) Name() string { return "reply" }
+func (p *replyProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Chat: true, Streaming: true}
+}
+func (p *replyProvider) Models(_ context.Context) ([]provider.Model, error) { return nil, nil }
+func (p *replyProvider) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *replyProvider) Healthy(_ context.Context) bool { return true }
+
+func (p *replyProvider) Complete(ctx context.Context, req *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	p.mu.Lock()
+	p.reqs = append(p.reqs, req)
+	p.tenants = append(p.tenants, pipeline.TenantID(ctx))
+	n := len(p.reqs)
+	p.mu.Unlock()
+	return &provider.CompletionResponse{
+		ID:      "c",
+		Model:   "x",
+		Created: time.Now(),
+		Choices: []provider.Choice{{
+			Message:      provider.Message{Role: "assistant", Content: "reply " + string(rune('0'+n))},
+			FinishReason: "stop",
+		}},
+		Usage: provider.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+	}, nil
+}
+
+func (p *replyProvider) CompleteStream(ctx context.Context, req *provider.CompletionRequest) (provider.Stream, error) {
+	p.mu.Lock()
+	p.reqs = append(p.reqs, req)
+	p.tenants = append(p.tenants, pipeline.TenantID(ctx))
+	p.mu.Unlock()
+	return &chunkStream{chunks: []*provider.StreamChunk{
+		{ID: "c", Model: "x", Delta: provider.Delta{Content: "he"}},
+		{ID: "c", Model: "x", Delta: provider.Delta{Content: "llo"}},
+		{ID: "c", Model: "x", FinishReason: "stop", Usage: &provider.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}},
+	}}, nil
+}
+
+func (p *replyProvider) last() *provider.CompletionRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reqs[len(p.reqs)-1]
+}
+
+type chunkStream struct{ chunks []*provider.StreamChunk }
+
+func (s *chunkStream) Next(_ context.Context) (*provider.StreamChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	c := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return c, nil
+}
+
+func (s *chunkStream) Close() error           { return nil }
+func (s *chunkStream) Usage() *provider.Usage { return nil }
+
+type responsesObject struct {
+	ID                 string  `json:"id"`
+	Object             string  `json:"object"`
+	Status             string  `json:"status"`
+	PreviousResponseID *string `json:"previous_response_id"`
+	Output             []struct {
+		Type    string `json:"type"`
+		Role    string `json:"role"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"output"`
+	Usage *struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+func (o responsesObject) text() string {
+	for _, it := range o.Output {
+		if it.Type == "message" && len(it.Content) > 0 {
+			return it.Content[0].Text
+		}
+	}
+	return ""
+}
+
+func newResponsesServer(t *testing.T, opts ...nexus.Option) (*replyProvider, *httptest.Server) {
+	t.Helper()
+	rp := &replyProvider{}
+	engine := nexus.NewEngine(append([]nexus.Option{nexus.WithProvider(rp)}, opts...)...)
+	srv := httptest.NewServer(proxy.New(engine, proxy.WithoutWebSocket()))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { _ = engine.Gateway().Shutdown(context.Background()) })
+	return rp, srv
+}
+
+func doResponses(t *testing.T, method, url, body string) (int, responsesObject) {
+	t.Helper()
+	var rdr io.Reader = http.NoBody
+	if body != "" {
+		rdr = strings.NewReader(body)
+	}
+	req, _ := http.NewRequestWithContext(context.Background(), method, url, rdr)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var out responsesObject
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func TestProxy_ResponsesChainsPreviousResponse(t *testing.T) {
+	t.Parallel()
+	rp, srv := newResponsesServer(t)
+
+	status, first := doResponses(t, http.MethodPost, srv.URL+"/v1/responses",
+		`{"model":"x","instructions":"be brief","input":"hi"}`)
+	if status != http.StatusOK || first.Object != "response" || first.Status != "completed" || first.text() != "reply 1" {
+		t.Fatalf("first = %d %+v", status, first)
+	}
+	if first.Usage == nil || first.Usage.TotalTokens != 5 {
+		t.Fatalf("usage = %+v", first.Usage)
+	}
+	if got := rp.last(); got.System != "be brief" || len(got.Messages) != 1 || got.Messages[0].Content != "hi" {
+		t.Fatalf("first upstream request = %+v", got)
+	}
+
+	status, second := doResponses(t, http.MethodPost, srv.URL+"/v1/responses",
+		`{"model":"x","previous_response_id":"`+first.ID+`","input":[{"role":"user","content":[{"type":"input_text","text":"more"}]}]}`)
+	if status != http.StatusOK || second.text() != "reply 2" || second.PreviousResponseID == nil || *second.PreviousResponseID != first.ID {
+		t.Fatalf("second = %d %+v", status, second)
+	}
+	msgs := rp.last().Messages
+	if len(msgs) != 3 || msgs[1].Role != "assistant" || msgs[1].Content != "reply 1" || msgs[2].Content != "more" {
+		t.Fatalf("second upstream messages = %+v", msgs)
+	}
+	if rp.last().System != "" {
+		t.Fatalf("instructions carried over: %q", rp.last().System)
+	}
+
+	_, fetched := doResponses(t, http.MethodGet, srv.URL+"/v1/responses/"+second.ID, "")
+	if fetched.Object != "response" || fetched.Status != "completed" || fetched.text() != "reply 2" {
+		t.Fatalf("fetched = %+v", fetched)
+	}
+
+	if status, _ := doResponses(t, http.MethodPost, srv.URL+"/v1/responses",
+		`{"model":"x","previous_response_id":"resp_missing","input":"hi"}`); status != http.StatusNotFound {
+		t.Fatalf("unknown previous_response_id status = %d, want 404", status)
+	}
+	if status, _ := doResponses(t, http.MethodPost, srv.URL+"/v1/responses",
+		`{"model":"x","input":"hi","tools":[{"type":"web_search"}]}`); status != http.StatusBadRequest {
+		t.Fatalf("built-in tool status = %d, want 400", status)
+	}
+}
+
+// contextInjector stands in for RAG: it prepends a system message and
+// redacts the user's text in place.
+type contextInjector struct{}
+
+func (contextInjector) Name() string  { return "context_injector" }
+func (contextInjector) Priority() int { return 240 }
+func (contextInjector) Process(ctx context.Context, req *pipeline.Request, next pipeline.NextFunc) (*pipeline.Response, error) {
+	msgs := req.Completion.Messages
+	msgs[len(msgs)-1].Content = "[redacted]"
+	req.Completion.Messages = append([]provider.Message{{Role: "system", Content: "retrieved"}}, msgs...)
+	return next(ctx)
+}
+
+func TestProxy_ResponsesStoresTheClientRequest(t *testing.T) {
+	t.Parallel()
+	rp, srv := newResponsesServer(t, nexus.WithMiddleware(contextInjector{}))
+
+	_, first := doResponses(t, http.MethodPost, srv.URL+"/v1/responses", `{"model":"x","input":"hi"}`)
+	_, _ = doResponses(t, http.MethodPost, srv.URL+"/v1/responses",
+		`{"model":"x","previous_response_id":"`+first.ID+`","input":"more"}`)
+
+	msgs := rp.last().Messages
+	if len(msgs) != 4 || msgs[0].Content != "retrieved" || msgs[1].Content != "hi" || msgs[2].Content != "reply 1" {
+		t.Fatalf("continued upstream messages = %+v, want the stored conversation plus one injection", msgs)
+	}
+}
+
+func TestProxy_ResponsesStream(t *testing.T) {
+	t.Parallel()
+	_, srv := newResponsesServer(t)
+
+	resp, err := http.Post(srv.URL+"/v1/responses", "application/json", //nolint:noctx // test
+		strings.NewReader(`{"model":"x","input":"hi","stream":true}`))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	var events []string
+	responseID := ""
+	for _, line := range strings.Split(string(raw), "\n") {
+		if ev, ok := strings.CutPrefix(line, "event: "); ok {
+			events = append(events, ev)
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok && responseID == "" {
+			var created struct {
+				Response struct {
+					ID string `json:"id"`
+				} `json:"response"`
+			}
+			_ = json.Unmarshal([]byte(data), &created)
+			responseID = created.Response.ID
+		}
+	}
+	want := []string{
+		"response.created", "response.in_progress",
+		"response.output_item.added", "response.content_part.added",
+		"response.output_text.delta", "response.output_text.delta",
+		"response.output_text.done", "response.content_part.done", "response.output_item.done",
+		"response.completed",
+	}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Fatalf("events = %v\nwant %v", events, want)
+	}
+
+	_, fetched := doResponses(t, http.MethodGet, srv.URL+"/v1/responses/"+responseID, "")
+	if fetched.Status != "completed" || fetched.text() != "hello" {
+		t.Fatalf("stored streamed response = %+v", fetched)
+	}
+}
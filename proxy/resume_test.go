@@ -0,0 +1,143 @@
+package proxy_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	nexus "github.com/xraph/nexus"
+	"github.com/xraph/nexus/httpstream"
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/proxy"
+)
+
+// gatedProvider streams "one", then holds the rest of the answer until
+// gate closes — long enough for the test client to drop the connection.
+type gatedProvider struct {
+	gate chan struct{}
+}
+
+func (p *gatedProvider) Name() string { return "gated" }
+func (p *gatedProvider) Capabilities() provider.Capabilities {
+	return provider.Capabilities{Streaming: true}
+}
+func (p *gatedProvider) Models(_ context.Context) ([]provider.Model, error) { return nil, nil }
+func (p *gatedProvider) Complete(_ context.Context, _ *provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *gatedProvider) Embed(_ context.Context, _ *provider.EmbeddingRequest) (*provider.EmbeddingResponse, error) {
+	return nil, errors.New("not used")
+}
+func (p *gatedProvider) Healthy(_ context.Context) bool { return true }
+
+func (p *gatedProvider) CompleteStream(_ context.Context, _ *provider.CompletionRequest) (provider.Stream, error) {
+	return &gatedStream{gate: p.gate, parts: []string{"one", "two", "three"}}, nil
+}
+
+type gatedStream struct {
+	gate  chan struct{}
+	parts []string
+	sent  int
+}
+
+func (s *gatedStream) Next(ctx context.Context) (*provider.StreamChunk, error) {
+	if s.sent == len(s.parts) {
+		return nil, io.EOF
+	}
+	if s.sent == 1 {
+		select {
+		case <-s.gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	s.sent++
+	return &provider.StreamChunk{ID: "c", Model: "x", Delta: provider.Delta{Content: s.parts[s.sent-1]}}, nil
+}
+
+func (s *gatedStream) Close() error           { return nil }
+func (s *gatedStream) Usage() *provider.Usage { return nil }
+
+func TestProxy_ResumeAfterDisconnect(t *testing.T) {
+	t.Parallel()
+
+	gp := &gatedProvider{gate: make(chan struct{})}
+	engine := nexus.NewEngine(nexus.WithProvider(gp))
+	p := proxy.New(engine, proxy.WithoutWebSocket(), proxy.WithResume(httpstream.NewResumeStore()))
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+
+	// First connection: read the first event, then drop.
+	ctx, cancel := context.WithCancel(context.Background())
+	body := strings.NewReader(`{"model":"x","messages":[{"role":"user","content":"hi"}],"stream":true}`)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/v1/chat/completions", body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	lastID := ""
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		if id, ok := strings.CutPrefix(sc.Text(), "id: "); ok {
+			lastID = id
+			break
+		}
+	}
+	cancel()
+	_ = resp.Body.Close()
+	if !strings.HasSuffix(lastID, ":1") {
+		t.Fatalf("first event id = %q, want <request_id>:1", lastID)
+	}
+
+	// The upstream keeps producing while nobody is connected.
+	close(gp.gate)
+	time.Sleep(50 * time.Millisecond)
+
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/v1/chat/completions", http.NoBody)
+	req.Header.Set("Last-Event-ID", lastID)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("resume status = %d", resp.StatusCode)
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	replay := string(raw)
+
+	requestID := strings.TrimSuffix(lastID, ":1")
+	if strings.Contains(replay, `"one"`) {
+		t.Fatalf("replay repeated an event the client already had: %s", replay)
+	}
+	for _, want := range []string{"id: " + requestID + ":2", `"two"`, "id: " + requestID + ":3", `"three"`, "data: [DONE]"} {
+		if !strings.Contains(replay, want) {
+			t.Fatalf("replay missing %q:\n%s", want, replay)
+		}
+	}
+}
+
+func TestProxy_ResumeUnknownStream(t *testing.T) {
+	t.Parallel()
+
+	engine := nexus.NewEngine(nexus.WithProvider(&gatedProvider{gate: make(chan struct{})}))
+	p := proxy.New(engine, proxy.WithoutWebSocket(), proxy.WithResume(httpstream.NewResumeStore()))
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/v1/chat/completions?resume=req_missing") //nolint:noctx // test
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", resp.StatusCode)
+	}
+}
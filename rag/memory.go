@@ -0,0 +1,70 @@
+package rag
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryIndex is an in-process Index that searches by brute-force cosine
+// similarity. It suits development and corpora up to tens of thousands of
+// chunks; use a store-backed index for persistence.
+type MemoryIndex struct {
+	mu     sync.RWMutex
+	chunks map[chunkKey]Chunk
+}
+
+// chunkKey identifies a chunk; chunk IDs are only unique within a tenant.
+type chunkKey struct{ tenantID, id string }
+
+// Compile-time check.
+var _ Index = (*MemoryIndex)(nil)
+
+// NewMemoryIndex creates an empty in-memory index.
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{chunks: make(map[chunkKey]Chunk)}
+}
+
+func (m *MemoryIndex) Upsert(_ context.Context, chunks []Chunk) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range chunks {
+		m.chunks[chunkKey{c.TenantID, c.ID}] = c
+	}
+	return nil
+}
+
+func (m *MemoryIndex) Search(_ context.Context, tenantID string, embedding []float64, k int) ([]Match, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	matches := make([]Match, 0, k)
+	for _, c := range m.chunks {
+		if c.TenantID != "" && c.TenantID != tenantID {
+			continue
+		}
+		matches = append(matches, Match{Chunk: c, Score: Cosine(embedding, c.Embedding)})
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+func (m *MemoryIndex) DeleteDocument(_ context.Context, tenantID, documentID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, c := range m.chunks {
+		if c.DocumentID == documentID && c.TenantID == tenantID {
+			delete(m.chunks, k)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of stored chunks.
+func (m *MemoryIndex) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.chunks)
+}
@@ -0,0 +1,84 @@
+// Package rag provides batteries-included retrieval for
+// transform.RAGTransform: documents are split into chunks, embedded through
+// an Embedder (typically the gateway's own embeddings path) and stored in a
+// vector Index that is searched per tenant at request time.
+package rag
+
+import (
+	"context"
+	"math"
+)
+
+// Document is a unit of ingested content.
+type Document struct {
+	ID       string            `json:"id,omitempty"` // generated when empty
+	TenantID string            `json:"tenant_id,omitempty"`
+	Content  string            `json:"content"`
+	Source   string            `json:"source,omitempty"` // URL or path; surfaced as a citation
+	Title    string            `json:"title,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Chunk is an embedded piece of a document as stored in an Index.
+// Chunks with an empty TenantID are shared by all tenants.
+type Chunk struct {
+	ID         string            `json:"id"`
+	DocumentID string            `json:"document_id"`
+	TenantID   string            `json:"tenant_id,omitempty"`
+	Content    string            `json:"content"`
+	Source     string            `json:"source,omitempty"`
+	Title      string            `json:"title,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Embedding  []float64         `json:"-"`
+}
+
+// Match is a search hit with its cosine similarity to the query.
+type Match struct {
+	Chunk Chunk
+	Score float64
+}
+
+// Index stores chunk embeddings and searches them by similarity.
+type Index interface {
+	// Upsert stores chunks, replacing any with the same tenant and ID.
+	// Chunk IDs need only be unique within a tenant.
+	Upsert(ctx context.Context, chunks []Chunk) error
+
+	// Search returns up to k chunks most similar to embedding, restricted
+	// to tenantID's chunks and shared (empty-tenant) chunks, best first.
+	Search(ctx context.Context, tenantID string, embedding []float64, k int) ([]Match, error)
+
+	// DeleteDocument removes every chunk of a document.
+	DeleteDocument(ctx context.Context, tenantID, documentID string) error
+}
+
+// Embedder turns texts into embedding vectors, one per text, in order.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// EmbedderFunc adapts a function to Embedder.
+type EmbedderFunc func(ctx context.Context, texts []string) ([][]float64, error)
+
+// Embed calls f.
+func (f EmbedderFunc) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	return f(ctx, texts)
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 when their lengths
+// differ or either is zero.
+func Cosine(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
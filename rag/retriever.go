@@ -0,0 +1,208 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/xraph/nexus/id"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/transform"
+)
+
+// embedBatchSize bounds the number of texts sent per embedding call.
+const embedBatchSize = 64
+
+// Retriever ingests documents into an Index and retrieves context for
+// queries. It implements transform.RAGProvider; the tenant is taken from
+// the request context (pipeline.TenantID), so each tenant sees only its own
+// documents plus shared ones.
+type Retriever struct {
+	embedder     Embedder
+	index        Index
+	chunkSize    int // runes
+	chunkOverlap int // runes
+	minScore     float64
+}
+
+// Compile-time check.
+var _ transform.RAGProvider = (*Retriever)(nil)
+
+// NewRetriever creates a retriever. Defaults: 1000-rune chunks with a
+// 200-rune overlap, no minimum score.
+func NewRetriever(embedder Embedder, index Index) *Retriever {
+	return &Retriever{
+		embedder:     embedder,
+		index:        index,
+		chunkSize:    1000,
+		chunkOverlap: 200,
+	}
+}
+
+// WithChunking sets the chunk size and overlap in runes.
+func (r *Retriever) WithChunking(size, overlap int) *Retriever {
+	r.chunkSize = size
+	r.chunkOverlap = overlap
+	return r
+}
+
+// WithMinScore drops matches whose similarity is below score.
+func (r *Retriever) WithMinScore(score float64) *Retriever {
+	r.minScore = score
+	return r
+}
+
+// Ingest chunks, embeds and indexes documents, returning them with IDs
+// assigned and the number of chunks stored. Re-ingesting a document ID
+// replaces its previous chunks once the new ones are embedded, so a failed
+// re-ingest leaves the indexed copy in place.
+func (r *Retriever) Ingest(ctx context.Context, docs ...Document) ([]Document, int, error) {
+	var chunks []Chunk
+	var replaced []Document
+	out := make([]Document, len(docs))
+	for i, doc := range docs {
+		if strings.TrimSpace(doc.Content) == "" {
+			return nil, 0, fmt.Errorf("rag: document %d has no content", i)
+		}
+		if doc.ID == "" {
+			doc.ID = id.NewDocumentID().String()
+		} else {
+			replaced = append(replaced, doc)
+		}
+		out[i] = doc
+		for j, text := range splitText(doc.Content, r.chunkSize, r.chunkOverlap) {
+			chunks = append(chunks, Chunk{
+				ID:         fmt.Sprintf("%s#%d", doc.ID, j),
+				DocumentID: doc.ID,
+				TenantID:   doc.TenantID,
+				Content:    text,
+				Source:     doc.Source,
+				Title:      doc.Title,
+				Metadata:   doc.Metadata,
+			})
+		}
+	}
+
+	for start := 0; start < len(chunks); start += embedBatchSize {
+		batch := chunks[start:min(start+embedBatchSize, len(chunks))]
+		texts := make([]string, len(batch))
+		for i, c := range batch {
+			texts[i] = c.Content
+		}
+		vectors, err := r.embedder.Embed(ctx, texts)
+		if err != nil {
+			return nil, 0, fmt.Errorf("rag: embed: %w", err)
+		}
+		if len(vectors) != len(batch) {
+			return nil, 0, fmt.Errorf("rag: embed: got %d vectors for %d texts", len(vectors), len(batch))
+		}
+		for i := range batch {
+			batch[i].Embedding = vectors[i]
+		}
+	}
+
+	for _, doc := range replaced {
+		if err := r.index.DeleteDocument(ctx, doc.TenantID, doc.ID); err != nil {
+			return nil, 0, fmt.Errorf("rag: replace document %s: %w", doc.ID, err)
+		}
+	}
+	if err := r.index.Upsert(ctx, chunks); err != nil {
+		return nil, 0, fmt.Errorf("rag: index: %w", err)
+	}
+	return out, len(chunks), nil
+}
+
+// Delete removes a document from the index.
+func (r *Retriever) Delete(ctx context.Context, tenantID, documentID string) error {
+	if err := r.index.DeleteDocument(ctx, tenantID, documentID); err != nil {
+		return fmt.Errorf("rag: delete: %w", err)
+	}
+	return nil
+}
+
+// Retrieve returns the chunks most relevant to query for the requesting
+// tenant.
+func (r *Retriever) Retrieve(ctx context.Context, query string, maxResults int) ([]transform.RAGChunk, error) {
+	vectors, err := r.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("rag: embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, errors.New("rag: embed query: no vector returned")
+	}
+
+	matches, err := r.index.Search(ctx, pipeline.TenantID(ctx), vectors[0], maxResults)
+	if err != nil {
+		return nil, fmt.Errorf("rag: search: %w", err)
+	}
+
+	chunks := make([]transform.RAGChunk, 0, len(matches))
+	for _, m := range matches {
+		if m.Score < r.minScore {
+			continue
+		}
+		chunks = append(chunks, transform.RAGChunk{
+			Content:  m.Chunk.Content,
+			Source:   m.Chunk.Source,
+			Title:    m.Chunk.Title,
+			Score:    m.Score,
+			Metadata: m.Chunk.Metadata,
+		})
+	}
+	return chunks, nil
+}
+
+// splitText splits text into chunks of at most size runes, preferring
+// paragraph, then sentence, then word boundaries. Consecutive chunks share
+// up to overlap runes.
+func splitText(text string, size, overlap int) []string {
+	runes := []rune(strings.TrimSpace(text))
+	if size <= 0 || len(runes) <= size {
+		return []string{string(runes)}
+	}
+	if overlap >= size {
+		overlap = size / 4
+	}
+
+	var chunks []string
+	for start := 0; start < len(runes); {
+		end := start + size
+		if end >= len(runes) {
+			chunks = append(chunks, strings.TrimSpace(string(runes[start:])))
+			break
+		}
+		end = breakPoint(runes, start, end)
+		chunks = append(chunks, strings.TrimSpace(string(runes[start:end])))
+
+		next := end - overlap
+		if next <= start {
+			next = end
+		}
+		// Start the overlap on a word boundary.
+		for next < end && !unicode.IsSpace(runes[next-1]) {
+			next++
+		}
+		start = next
+	}
+	return chunks
+}
+
+// breakPoint returns the best place at or before end to cut, searching the
+// second half of the window for a paragraph break, sentence end or space.
+func breakPoint(runes []rune, start, end int) int {
+	floor := start + (end-start)/2
+	for _, isBreak := range []func(i int) bool{
+		func(i int) bool { return runes[i] == '\n' && runes[i-1] == '\n' },
+		func(i int) bool { return unicode.IsSpace(runes[i]) && strings.ContainsRune(".!?", runes[i-1]) },
+		func(i int) bool { return unicode.IsSpace(runes[i]) },
+	} {
+		for i := end; i > floor; i-- {
+			if isBreak(i) {
+				return i
+			}
+		}
+	}
+	return end
+}
@@ -0,0 +1,151 @@
+package rag_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/rag"
+)
+
+// wordEmbedder embeds a text as the count of each vocabulary word in it.
+var wordEmbedder = rag.EmbedderFunc(func(_ context.Context, texts []string) ([][]float64, error) {
+	vocab := []string{"alpha", "bravo", "charlie"}
+	out := make([][]float64, len(texts))
+	for i, text := range texts {
+		out[i] = make([]float64, len(vocab))
+		for j, w := range vocab {
+			out[i][j] = float64(strings.Count(text, w))
+		}
+	}
+	return out, nil
+})
+
+func retrieve(t *testing.T, r *rag.Retriever, tenantID, query string) []string {
+	t.Helper()
+	chunks, err := r.Retrieve(pipeline.WithTenantID(context.Background(), tenantID), query, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make([]string, len(chunks))
+	for i, c := range chunks {
+		contents[i] = c.Content
+	}
+	return contents
+}
+
+func TestRetriever_TenantsSharingDocumentIDStaySeparate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	index := rag.NewMemoryIndex()
+	r := rag.NewRetriever(wordEmbedder, index).WithMinScore(0.5)
+
+	if _, _, err := r.Ingest(ctx, rag.Document{ID: "handbook", TenantID: "a", Content: "alpha"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.Ingest(ctx, rag.Document{ID: "handbook", TenantID: "b", Content: "bravo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := retrieve(t, r, "a", "alpha"); len(got) != 1 || got[0] != "alpha" {
+		t.Fatalf("tenant a retrieved %q, want its own chunk", got)
+	}
+	if got := retrieve(t, r, "a", "bravo"); len(got) != 0 {
+		t.Fatalf("tenant a retrieved %q from tenant b", got)
+	}
+	if got := retrieve(t, r, "b", "bravo"); len(got) != 1 || got[0] != "bravo" {
+		t.Fatalf("tenant b retrieved %q, want its own chunk", got)
+	}
+	if index.Len() != 2 {
+		t.Fatalf("index holds %d chunks, want 2", index.Len())
+	}
+}
+
+func TestRetriever_IngestAndReingest(t *testing.T) {
+	t.Parallel()
+	ctx := pipeline.WithTenantID(context.Background(), "a")
+	index := rag.NewMemoryIndex()
+	r := rag.NewRetriever(wordEmbedder, index).WithChunking(12, 0).WithMinScore(0.5)
+
+	docs, n, err := r.Ingest(ctx,
+		rag.Document{TenantID: "a", Content: "alpha alpha\n\nbravo bravo"},
+		rag.Document{ID: "notes", TenantID: "a", Content: "charlie"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if docs[0].ID == "" || docs[1].ID != "notes" {
+		t.Fatalf("ids = %q, %q; want one generated and one kept", docs[0].ID, docs[1].ID)
+	}
+	if n != 3 || index.Len() != 3 {
+		t.Fatalf("stored %d chunks (index %d), want 3", n, index.Len())
+	}
+
+	// Re-ingesting replaces the document's chunks.
+	if _, _, err := r.Ingest(ctx, rag.Document{ID: "notes", TenantID: "a", Content: "bravo"}); err != nil {
+		t.Fatal(err)
+	}
+	if index.Len() != 3 {
+		t.Fatalf("index holds %d chunks after re-ingest, want 3", index.Len())
+	}
+	if got := retrieve(t, r, "a", "charlie"); len(got) != 0 {
+		t.Fatalf("retrieved replaced chunk %q", got)
+	}
+	if got := retrieve(t, r, "a", "bravo"); len(got) != 2 {
+		t.Fatalf("retrieved %q, want both bravo chunks", got)
+	}
+}
+
+func TestRetriever_FailedReingestKeepsDocument(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	index := rag.NewMemoryIndex()
+	if _, _, err := rag.NewRetriever(wordEmbedder, index).Ingest(ctx, rag.Document{ID: "notes", Content: "alpha"}); err != nil {
+		t.Fatal(err)
+	}
+
+	failing := rag.EmbedderFunc(func(context.Context, []string) ([][]float64, error) {
+		return nil, errors.New("rate limited")
+	})
+	if _, _, err := rag.NewRetriever(failing, index).Ingest(ctx, rag.Document{ID: "notes", Content: "bravo"}); err == nil {
+		t.Fatal("expected the embedding error")
+	}
+	if index.Len() != 1 {
+		t.Fatalf("index holds %d chunks, want the original document kept", index.Len())
+	}
+}
+
+func TestRetriever_DeleteAndSharedDocuments(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := rag.NewRetriever(wordEmbedder, rag.NewMemoryIndex()).WithMinScore(0.5)
+	if _, _, err := r.Ingest(ctx,
+		rag.Document{ID: "shared", Content: "alpha"},
+		rag.Document{ID: "private", TenantID: "a", Content: "alpha alpha"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := retrieve(t, r, "a", "alpha"); len(got) != 2 {
+		t.Fatalf("tenant a retrieved %q, want its own and the shared chunk", got)
+	}
+	if got := retrieve(t, r, "b", "alpha"); len(got) != 1 || got[0] != "alpha" {
+		t.Fatalf("tenant b retrieved %q, want only the shared chunk", got)
+	}
+
+	// Deleting is scoped to the tenant.
+	if err := r.Delete(ctx, "b", "private"); err != nil {
+		t.Fatal(err)
+	}
+	if got := retrieve(t, r, "a", "alpha"); len(got) != 2 {
+		t.Fatalf("tenant b deleted tenant a's document: %q", got)
+	}
+	if err := r.Delete(ctx, "a", "private"); err != nil {
+		t.Fatal(err)
+	}
+	if got := retrieve(t, r, "a", "alpha"); len(got) != 1 || got[0] != "alpha" {
+		t.Fatalf("tenant a retrieved %q after delete, want only the shared chunk", got)
+	}
+}
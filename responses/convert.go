@@ -0,0 +1,388 @@
+package responses
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// reasoningBudgets maps a reasoning effort to a thinking budget for
+// providers that take tokens rather than an effort level.
+var reasoningBudgets = map[string]int{
+	"minimal": 1024,
+	"low":     2048,
+	"medium":  8192,
+	"high":    24576,
+}
+
+// ToCompletionRequest translates a Responses API request into a unified
+// completion request. history holds the messages of the conversation named
+// by previous_response_id (see Conversation) and is prepended to the input.
+func ToCompletionRequest(req *Request, history []provider.Message) (*provider.CompletionRequest, error) {
+	if req.Model == "" {
+		return nil, fmt.Errorf("model is required")
+	}
+	if len(req.Input) == 0 {
+		return nil, fmt.Errorf("input is required")
+	}
+
+	input, err := toMessages(req.Input, history)
+	if err != nil {
+		return nil, err
+	}
+
+	creq := &provider.CompletionRequest{
+		Model:       req.Model,
+		Messages:    append(append([]provider.Message(nil), history...), input...),
+		System:      req.Instructions,
+		MaxTokens:   req.MaxOutputTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Stream:      req.Stream,
+		Metadata:    req.Metadata,
+	}
+
+	for _, t := range req.Tools {
+		if t.Type != "function" {
+			return nil, fmt.Errorf("unsupported tool type %q: only function tools are supported", t.Type)
+		}
+		if t.Name == "" {
+			return nil, fmt.Errorf("function tools require a name")
+		}
+		creq.Tools = append(creq.Tools, provider.Tool{
+			Type: "function",
+			Function: provider.ToolFunction{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		})
+	}
+	creq.ToolChoice = toolChoice(req.ToolChoice)
+
+	if req.Text != nil && req.Text.Format != nil {
+		creq.ResponseFormat = responseFormat(req.Text.Format)
+	}
+
+	if req.Reasoning != nil && req.Reasoning.Effort != "" {
+		budget, ok := reasoningBudgets[req.Reasoning.Effort]
+		if !ok {
+			return nil, fmt.Errorf("unsupported reasoning effort %q", req.Reasoning.Effort)
+		}
+		if creq.MaxTokens > 0 && budget >= creq.MaxTokens {
+			budget = creq.MaxTokens - 1
+		}
+		creq.Thinking = &provider.ThinkingConfig{
+			Enabled:         true,
+			BudgetTokens:    budget,
+			IncludeThinking: req.Reasoning.Summary != "",
+			Effort:          req.Reasoning.Effort,
+		}
+	}
+
+	return creq, nil
+}
+
+// toMessages converts input items to messages. Consecutive function_call
+// items become the tool calls of one assistant message; function outputs
+// become tool messages named after the call they answer, which Gemini
+// needs. Reasoning items are dropped: no provider accepts them back.
+func toMessages(items []Item, history []provider.Message) ([]provider.Message, error) {
+	callNames := make(map[string]string)
+	for _, m := range history {
+		for _, tc := range m.ToolCalls {
+			callNames[tc.ID] = tc.Function.Name
+		}
+	}
+
+	var out []provider.Message
+	for i, it := range items {
+		switch it.Type {
+		case "", ItemMessage:
+			role := it.Role
+			switch role {
+			case "user", "assistant", "system":
+			case "developer":
+				role = "system"
+			default:
+				return nil, fmt.Errorf("input[%d]: unsupported role %q", i, it.Role)
+			}
+			content, err := messageContent(it.Content)
+			if err != nil {
+				return nil, fmt.Errorf("input[%d]: %w", i, err)
+			}
+			out = append(out, provider.Message{Role: role, Content: content})
+		case ItemFunctionCall:
+			if it.CallID == "" || it.Name == "" {
+				return nil, fmt.Errorf("input[%d]: function_call requires call_id and name", i)
+			}
+			callNames[it.CallID] = it.Name
+			tc := provider.ToolCall{
+				ID:       it.CallID,
+				Type:     "function",
+				Function: provider.ToolCallFunc{Name: it.Name, Arguments: it.Arguments},
+			}
+			if n := len(out); n > 0 && out[n-1].Role == "assistant" && len(out[n-1].ToolCalls) > 0 {
+				out[n-1].ToolCalls = append(out[n-1].ToolCalls, tc)
+				continue
+			}
+			out = append(out, provider.Message{Role: "assistant", Content: "", ToolCalls: []provider.ToolCall{tc}})
+		case ItemFunctionCallOutput:
+			if it.CallID == "" {
+				return nil, fmt.Errorf("input[%d]: function_call_output requires call_id", i)
+			}
+			out = append(out, provider.Message{
+				Role:       "tool",
+				Content:    it.Output,
+				Name:       callNames[it.CallID],
+				ToolCallID: it.CallID,
+			})
+		case ItemReasoning:
+		default:
+			return nil, fmt.Errorf("input[%d]: unsupported item type %q", i, it.Type)
+		}
+	}
+	return out, nil
+}
+
+// messageContent flattens text-only content to a string; anything else
+// becomes OpenAI chat content parts, the shape /v1/chat/completions
+// passes to providers.
+func messageContent(content Content) (any, error) {
+	textOnly := true
+	for _, p := range content {
+		if p.Type != PartInputText && p.Type != PartOutputText {
+			textOnly = false
+		}
+	}
+	if textOnly {
+		var b strings.Builder
+		for _, p := range content {
+			b.WriteString(p.Text)
+		}
+		return b.String(), nil
+	}
+
+	parts := make([]any, 0, len(content))
+	for _, p := range content {
+		switch p.Type {
+		case PartInputText, PartOutputText:
+			parts = append(parts, map[string]any{"type": "text", "text": p.Text})
+		case PartRefusal:
+			parts = append(parts, map[string]any{"type": "text", "text": p.Refusal})
+		case PartInputImage:
+			if p.ImageURL == "" {
+				return nil, fmt.Errorf("input_image requires image_url")
+			}
+			img := map[string]any{"url": p.ImageURL}
+			if p.Detail != "" {
+				img["detail"] = p.Detail
+			}
+			parts = append(parts, map[string]any{"type": "image_url", "image_url": img})
+		default:
+			return nil, fmt.Errorf("unsupported content type %q", p.Type)
+		}
+	}
+	return parts, nil
+}
+
+// toolChoice converts the Responses tool_choice to the chat form: strings
+// pass through, {"type":"function","name":…} gains a function wrapper.
+func toolChoice(choice any) any {
+	m, ok := choice.(map[string]any)
+	if !ok || m["type"] != "function" {
+		return choice
+	}
+	name, _ := m["name"].(string)
+	return map[string]any{"type": "function", "function": map[string]any{"name": name}}
+}
+
+// responseFormat converts text.format to a chat response format.
+func responseFormat(f *TextFormat) *provider.ResponseFormat {
+	rf := &provider.ResponseFormat{Type: f.Type}
+	if f.Type == "json_schema" {
+		rf.JSONSchema = &provider.JSONSchemaDef{
+			Name:        f.Name,
+			Description: f.Description,
+			Schema:      f.Schema,
+			Strict:      f.Strict != nil && *f.Strict,
+		}
+	}
+	return rf
+}
+
+// Conversation returns the message history a follow-up request continues
+// from: the stored request's messages followed by the assistant's answer.
+// The system prompt is not carried over; instructions apply per request.
+func Conversation(req *provider.CompletionRequest, result *provider.CompletionResponse) []provider.Message {
+	msgs := append([]provider.Message(nil), req.Messages...)
+	if result == nil || len(result.Choices) == 0 {
+		return msgs
+	}
+	msg := result.Choices[0].Message
+	msg.Role = "assistant"
+	if msg.Content == nil {
+		msg.Content = ""
+	}
+	return append(msgs, msg)
+}
+
+// NewResponse returns an in-progress response echoing the parameters of
+// the translated request.
+func NewResponse(id string, createdAt int64, req *Request) *Response {
+	resp := &Response{
+		ID:                id,
+		Object:            "response",
+		CreatedAt:         createdAt,
+		Status:            StatusInProgress,
+		Background:        req.Background,
+		Model:             req.Model,
+		Output:            []Item{},
+		Reasoning:         req.Reasoning,
+		Store:             req.Stored(),
+		Temperature:       req.Temperature,
+		TopP:              req.TopP,
+		Text:              req.Text,
+		ToolChoice:        req.ToolChoice,
+		Tools:             req.Tools,
+		ParallelToolCalls: req.ParallelToolCalls == nil || *req.ParallelToolCalls,
+		Metadata:          req.Metadata,
+	}
+	if resp.ToolChoice == nil {
+		resp.ToolChoice = "auto"
+	}
+	if resp.Tools == nil {
+		resp.Tools = []Tool{}
+	}
+	if resp.Metadata == nil {
+		resp.Metadata = map[string]string{}
+	}
+	if req.Instructions != "" {
+		resp.Instructions = &req.Instructions
+	}
+	if req.MaxOutputTokens > 0 {
+		resp.MaxOutputTokens = &req.MaxOutputTokens
+	}
+	if req.PreviousResponseID != "" {
+		resp.PreviousResponseID = &req.PreviousResponseID
+	}
+	if resp.Text == nil {
+		resp.Text = &TextConfig{Format: &TextFormat{Type: "text"}}
+	}
+	return resp
+}
+
+// Finish fills in the output, usage and final status from a completion.
+func (r *Response) Finish(result *provider.CompletionResponse) {
+	if result.Model != "" {
+		r.Model = result.Model
+	}
+	r.Output = OutputItems(r.ID, result)
+	r.Usage = NewUsage(&result.Usage)
+
+	finish := ""
+	if len(result.Choices) > 0 {
+		finish = result.Choices[0].FinishReason
+	}
+	r.Status, r.IncompleteDetails = FinishStatus(finish)
+}
+
+// Fail marks the response failed.
+func (r *Response) Fail(code, message string) {
+	r.Status = StatusFailed
+	r.Error = &Error{Code: code, Message: message}
+}
+
+// OutputItems converts a completion into output items: a reasoning item
+// when thinking was returned, the message, then one item per tool call.
+func OutputItems(responseID string, result *provider.CompletionResponse) []Item {
+	out := []Item{}
+	if result.ThinkingContent != "" {
+		out = append(out, Item{
+			Type:    ItemReasoning,
+			ID:      ItemID("rs", responseID, 0),
+			Summary: []ContentPart{{Type: PartSummaryText, Text: result.ThinkingContent}},
+		})
+	}
+	if len(result.Choices) == 0 {
+		return out
+	}
+	msg := result.Choices[0].Message
+	if text := contentText(msg.Content); text != "" {
+		out = append(out, Item{
+			Type:    ItemMessage,
+			ID:      ItemID("msg", responseID, 0),
+			Status:  StatusCompleted,
+			Role:    "assistant",
+			Content: Content{{Type: PartOutputText, Text: text}},
+		})
+	}
+	for i, tc := range msg.ToolCalls {
+		out = append(out, Item{
+			Type:      ItemFunctionCall,
+			ID:        ItemID("fc", responseID, i),
+			Status:    StatusCompleted,
+			CallID:    tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: tc.Function.Arguments,
+		})
+	}
+	return out
+}
+
+// ItemID derives a stable output item ID from the response ID, so the
+// streamed and stored forms of a response agree.
+func ItemID(prefix, responseID string, n int) string {
+	suffix := responseID
+	if _, rest, ok := strings.Cut(responseID, "_"); ok {
+		suffix = rest
+	}
+	return fmt.Sprintf("%s_%s_%d", prefix, suffix, n)
+}
+
+// NewUsage converts unified usage to Responses usage.
+func NewUsage(u *provider.Usage) *Usage {
+	if u == nil {
+		return nil
+	}
+	total := u.TotalTokens
+	if total == 0 {
+		total = u.PromptTokens + u.CompletionTokens
+	}
+	return &Usage{
+		InputTokens:         u.PromptTokens,
+		InputTokensDetails:  InputTokensDetails{CachedTokens: u.CacheReadTokens},
+		OutputTokens:        u.CompletionTokens,
+		OutputTokensDetails: OutputTokensDetails{ReasoningTokens: u.ThinkingTokens},
+		TotalTokens:         total,
+	}
+}
+
+// FinishStatus maps a finish reason to a response status.
+func FinishStatus(finishReason string) (string, *IncompleteDetails) {
+	switch finishReason {
+	case "length", "max_tokens":
+		return StatusIncomplete, &IncompleteDetails{Reason: "max_output_tokens"}
+	case "content_filter":
+		return StatusIncomplete, &IncompleteDetails{Reason: "content_filter"}
+	default:
+		return StatusCompleted, nil
+	}
+}
+
+// contentText flattens message content to its text.
+func contentText(content any) string {
+	switch v := content.(type) {
+	case string:
+		return v
+	case []provider.ContentPart:
+		var b strings.Builder
+		for _, p := range v {
+			b.WriteString(p.Text)
+		}
+		return b.String()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,146 @@
+package responses_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/responses"
+)
+
+func decodeRequest(t *testing.T, body string) *responses.Request {
+	t.Helper()
+	var req responses.Request
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return &req
+}
+
+func TestToCompletionRequest_Items(t *testing.T) {
+	t.Parallel()
+	req := decodeRequest(t, `{
+		"model": "m",
+		"instructions": "be brief",
+		"max_output_tokens": 2000,
+		"input": [
+			{"role": "developer", "content": "use metric units"},
+			{"role": "user", "content": [
+				{"type": "input_text", "text": "what is this?"},
+				{"type": "input_image", "image_url": "https://example.com/a.png", "detail": "low"}
+			]},
+			{"type": "reasoning", "id": "rs_1", "summary": []},
+			{"type": "function_call", "call_id": "call_1", "name": "lookup", "arguments": "{}"},
+			{"type": "function_call", "call_id": "call_2", "name": "measure", "arguments": "{}"},
+			{"type": "function_call_output", "call_id": "call_2", "output": "3 m"}
+		],
+		"tools": [{"type": "function", "name": "lookup", "parameters": {"type": "object"}}],
+		"tool_choice": {"type": "function", "name": "lookup"},
+		"reasoning": {"effort": "high", "summary": "auto"},
+		"text": {"format": {"type": "json_schema", "name": "answer", "schema": {"type": "object"}, "strict": true}}
+	}`)
+
+	creq, err := responses.ToCompletionRequest(req, nil)
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	if creq.System != "be brief" || creq.MaxTokens != 2000 {
+		t.Fatalf("system/max tokens = %q/%d", creq.System, creq.MaxTokens)
+	}
+	if len(creq.Messages) != 4 {
+		t.Fatalf("messages = %+v", creq.Messages)
+	}
+	if m := creq.Messages[0]; m.Role != "system" || m.Content != "use metric units" {
+		t.Fatalf("developer message = %+v", m)
+	}
+	parts, ok := creq.Messages[1].Content.([]any)
+	if !ok || len(parts) != 2 {
+		t.Fatalf("multimodal content = %#v", creq.Messages[1].Content)
+	}
+	if img := parts[1].(map[string]any); img["type"] != "image_url" || img["image_url"].(map[string]any)["detail"] != "low" {
+		t.Fatalf("image part = %v", img)
+	}
+	if m := creq.Messages[2]; m.Role != "assistant" || len(m.ToolCalls) != 2 || m.ToolCalls[1].Function.Name != "measure" {
+		t.Fatalf("tool call message = %+v", m)
+	}
+	if m := creq.Messages[3]; m.Role != "tool" || m.ToolCallID != "call_2" || m.Name != "measure" || m.Content != "3 m" {
+		t.Fatalf("tool output message = %+v", m)
+	}
+
+	if len(creq.Tools) != 1 || creq.Tools[0].Function.Name != "lookup" {
+		t.Fatalf("tools = %+v", creq.Tools)
+	}
+	choice, _ := json.Marshal(creq.ToolChoice)
+	if string(choice) != `{"function":{"name":"lookup"},"type":"function"}` {
+		t.Fatalf("tool_choice = %s", choice)
+	}
+	if th := creq.Thinking; th == nil || th.BudgetTokens != 1999 || th.Effort != "high" || !th.IncludeThinking {
+		t.Fatalf("thinking = %+v", creq.Thinking)
+	}
+	if rf := creq.ResponseFormat; rf == nil || rf.JSONSchema == nil || rf.JSONSchema.Name != "answer" || !rf.JSONSchema.Strict {
+		t.Fatalf("response format = %+v", creq.ResponseFormat)
+	}
+}
+
+func TestToCompletionRequest_Errors(t *testing.T) {
+	t.Parallel()
+	for name, body := range map[string]string{
+		"no input":        `{"model":"m"}`,
+		"built-in tool":   `{"model":"m","input":"hi","tools":[{"type":"web_search"}]}`,
+		"bad role":        `{"model":"m","input":[{"role":"robot","content":"hi"}]}`,
+		"bad effort":      `{"model":"m","input":"hi","reasoning":{"effort":"extreme"}}`,
+		"orphan function": `{"model":"m","input":[{"type":"function_call","name":"f"}]}`,
+	} {
+		if _, err := responses.ToCompletionRequest(decodeRequest(t, body), nil); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestConversationAndOutput(t *testing.T) {
+	t.Parallel()
+	prev := &provider.CompletionRequest{
+		Model:    "m",
+		System:   "old instructions",
+		Messages: []provider.Message{{Role: "user", Content: "weather?"}},
+	}
+	result := &provider.CompletionResponse{
+		Model:           "m",
+		ThinkingContent: "need a tool",
+		Choices: []provider.Choice{{
+			Message: provider.Message{Role: "assistant", Content: "", ToolCalls: []provider.ToolCall{
+				{ID: "call_1", Type: "function", Function: provider.ToolCallFunc{Name: "weather", Arguments: `{"city":"Oslo"}`}},
+			}},
+			FinishReason: "tool_calls",
+		}},
+		Usage: provider.Usage{PromptTokens: 5, CompletionTokens: 7, ThinkingTokens: 3},
+	}
+
+	history := responses.Conversation(prev, result)
+	req := decodeRequest(t, `{"model":"m","input":[{"type":"function_call_output","call_id":"call_1","output":"sunny"}]}`)
+	creq, err := responses.ToCompletionRequest(req, history)
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	if creq.System != "" || len(creq.Messages) != 3 || creq.Messages[2].Name != "weather" {
+		t.Fatalf("continued request = %+v", creq)
+	}
+
+	resp := responses.NewResponse("resp_1", 100, req)
+	resp.Finish(result)
+	if resp.Status != responses.StatusCompleted || len(resp.Output) != 2 {
+		t.Fatalf("response = %+v", resp)
+	}
+	if resp.Output[0].Type != responses.ItemReasoning || resp.Output[1].Type != responses.ItemFunctionCall || resp.Output[1].CallID != "call_1" {
+		t.Fatalf("output = %+v", resp.Output)
+	}
+	if resp.Usage.TotalTokens != 12 || resp.Usage.OutputTokensDetails.ReasoningTokens != 3 {
+		t.Fatalf("usage = %+v", resp.Usage)
+	}
+
+	result.Choices[0].FinishReason = "length"
+	resp.Finish(result)
+	if resp.Status != responses.StatusIncomplete || resp.IncompleteDetails.Reason != "max_output_tokens" {
+		t.Fatalf("truncated response = %s %+v", resp.Status, resp.IncompleteDetails)
+	}
+}
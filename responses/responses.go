@@ -0,0 +1,317 @@
+// Package responses translates the OpenAI Responses API (/v1/responses)
+// to and from the unified provider types, so any provider — OpenAI,
+// Anthropic, Gemini or an OpenAI-compatible server — can serve it.
+//
+// Input items, instructions, function tools, reasoning settings and text
+// formats map onto a provider.CompletionRequest; a provider.CompletionResponse
+// maps back onto the Response envelope with reasoning, message and
+// function_call output items. Conversation state for previous_response_id
+// is kept by the caller: Conversation rebuilds the message history of a
+// stored response.
+package responses
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// Item types.
+const (
+	ItemMessage            = "message"
+	ItemFunctionCall       = "function_call"
+	ItemFunctionCallOutput = "function_call_output"
+	ItemReasoning          = "reasoning"
+)
+
+// Content part types.
+const (
+	PartInputText   = "input_text"
+	PartInputImage  = "input_image"
+	PartInputFile   = "input_file"
+	PartOutputText  = "output_text"
+	PartRefusal     = "refusal"
+	PartSummaryText = "summary_text"
+)
+
+// Response statuses.
+const (
+	StatusQueued     = "queued"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusIncomplete = "incomplete"
+	StatusFailed     = "failed"
+	StatusCancelled  = "cancelled"
+)
+
+// Request is the body of POST /v1/responses.
+type Request struct {
+	Model              string            `json:"model"`
+	Input              Input             `json:"input"`
+	Instructions       string            `json:"instructions,omitempty"`
+	Tools              []Tool            `json:"tools,omitempty"`
+	ToolChoice         any               `json:"tool_choice,omitempty"`
+	Temperature        *float64          `json:"temperature,omitempty"`
+	TopP               *float64          `json:"top_p,omitempty"`
+	MaxOutputTokens    int               `json:"max_output_tokens,omitempty"`
+	Stream             bool              `json:"stream,omitempty"`
+	Store              *bool             `json:"store,omitempty"` // default true
+	Background         bool              `json:"background,omitempty"`
+	PreviousResponseID string            `json:"previous_response_id,omitempty"`
+	Reasoning          *Reasoning        `json:"reasoning,omitempty"`
+	Text               *TextConfig       `json:"text,omitempty"`
+	ParallelToolCalls  *bool             `json:"parallel_tool_calls,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+	User               string            `json:"user,omitempty"`
+}
+
+// Stored reports whether the response should be persisted for retrieval
+// and previous_response_id (the default).
+func (r *Request) Stored() bool { return r.Store == nil || *r.Store }
+
+// Input is the request input: a plain string (one user message) or a
+// list of items.
+type Input []Item
+
+// UnmarshalJSON accepts a string or an array of items.
+func (in *Input) UnmarshalJSON(data []byte) error {
+	if s, ok := jsonString(data); ok {
+		*in = Input{{Type: ItemMessage, Role: "user", Content: Content{{Type: PartInputText, Text: s}}}}
+		return nil
+	}
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return errors.New("input must be a string or an array of items")
+	}
+	*in = items
+	return nil
+}
+
+// Item is one input or output item. Which fields apply depends on Type;
+// an item without a type is a message.
+type Item struct {
+	Type   string `json:"type,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status,omitempty"`
+
+	// message
+	Role    string  `json:"role,omitempty"`
+	Content Content `json:"content,omitempty"`
+
+	// function_call, function_call_output
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	Output    string `json:"output,omitempty"`
+
+	// reasoning
+	Summary []ContentPart `json:"summary,omitempty"`
+}
+
+// MarshalJSON writes the fields each item type requires, even when empty
+// (an in-progress function call has "arguments": "").
+func (it Item) MarshalJSON() ([]byte, error) {
+	switch it.Type {
+	case ItemFunctionCall:
+		return json.Marshal(struct {
+			Type      string `json:"type"`
+			ID        string `json:"id,omitempty"`
+			Status    string `json:"status,omitempty"`
+			CallID    string `json:"call_id"`
+			Name      string `json:"name"`
+			Arguments string `json:"arguments"`
+		}{it.Type, it.ID, it.Status, it.CallID, it.Name, it.Arguments})
+	case ItemFunctionCallOutput:
+		return json.Marshal(struct {
+			Type   string `json:"type"`
+			ID     string `json:"id,omitempty"`
+			Status string `json:"status,omitempty"`
+			CallID string `json:"call_id"`
+			Output string `json:"output"`
+		}{it.Type, it.ID, it.Status, it.CallID, it.Output})
+	case ItemReasoning:
+		summary := it.Summary
+		if summary == nil {
+			summary = []ContentPart{}
+		}
+		return json.Marshal(struct {
+			Type    string        `json:"type"`
+			ID      string        `json:"id,omitempty"`
+			Status  string        `json:"status,omitempty"`
+			Summary []ContentPart `json:"summary"`
+		}{it.Type, it.ID, it.Status, summary})
+	default:
+		content := it.Content
+		if content == nil {
+			content = Content{}
+		}
+		typ := it.Type
+		if typ == "" {
+			typ = ItemMessage
+		}
+		return json.Marshal(struct {
+			Type    string  `json:"type"`
+			ID      string  `json:"id,omitempty"`
+			Status  string  `json:"status,omitempty"`
+			Role    string  `json:"role"`
+			Content Content `json:"content"`
+		}{typ, it.ID, it.Status, it.Role, content})
+	}
+}
+
+// Content is a message's content: a plain string or a list of parts.
+type Content []ContentPart
+
+// UnmarshalJSON accepts a string or an array of parts.
+func (c *Content) UnmarshalJSON(data []byte) error {
+	if s, ok := jsonString(data); ok {
+		*c = Content{{Type: PartInputText, Text: s}}
+		return nil
+	}
+	var parts []ContentPart
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return errors.New("content must be a string or an array of parts")
+	}
+	*c = parts
+	return nil
+}
+
+// ContentPart is one part of a message, or of a reasoning summary.
+type ContentPart struct {
+	Type        string `json:"type"`
+	Text        string `json:"text,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	FileID      string `json:"file_id,omitempty"`
+	Detail      string `json:"detail,omitempty"`
+	Refusal     string `json:"refusal,omitempty"`
+	Annotations []any  `json:"annotations,omitempty"`
+}
+
+// MarshalJSON writes the fields each part type requires: text parts
+// always carry "text", and output text always carries "annotations".
+func (p ContentPart) MarshalJSON() ([]byte, error) {
+	switch p.Type {
+	case PartOutputText:
+		annotations := p.Annotations
+		if annotations == nil {
+			annotations = []any{}
+		}
+		return json.Marshal(struct {
+			Type        string `json:"type"`
+			Text        string `json:"text"`
+			Annotations []any  `json:"annotations"`
+		}{p.Type, p.Text, annotations})
+	case PartInputText, PartSummaryText:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}{p.Type, p.Text})
+	case PartRefusal:
+		return json.Marshal(struct {
+			Type    string `json:"type"`
+			Refusal string `json:"refusal"`
+		}{p.Type, p.Refusal})
+	default:
+		type plain ContentPart
+		return json.Marshal(plain(p))
+	}
+}
+
+// Tool is a Responses API tool. Only function tools can be served by
+// every provider; built-in tools (web_search, file_search, …) are rejected.
+type Tool struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+	Strict      *bool  `json:"strict,omitempty"`
+}
+
+// Reasoning configures reasoning models.
+type Reasoning struct {
+	Effort  string `json:"effort,omitempty"`  // minimal, low, medium, high
+	Summary string `json:"summary,omitempty"` // auto, concise, detailed
+}
+
+// TextConfig configures the text output format.
+type TextConfig struct {
+	Format *TextFormat `json:"format,omitempty"`
+}
+
+// TextFormat is the output format: text, json_object or json_schema.
+type TextFormat struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Schema      any    `json:"schema,omitempty"`
+	Strict      *bool  `json:"strict,omitempty"`
+}
+
+// Response is the Responses API response object.
+type Response struct {
+	ID                 string             `json:"id"`
+	Object             string             `json:"object"`
+	CreatedAt          int64              `json:"created_at"`
+	Status             string             `json:"status"`
+	Background         bool               `json:"background"`
+	Model              string             `json:"model"`
+	Output             []Item             `json:"output"`
+	Error              *Error             `json:"error"`
+	IncompleteDetails  *IncompleteDetails `json:"incomplete_details"`
+	Instructions       *string            `json:"instructions"`
+	MaxOutputTokens    *int               `json:"max_output_tokens"`
+	PreviousResponseID *string            `json:"previous_response_id"`
+	Reasoning          *Reasoning         `json:"reasoning,omitempty"`
+	Store              bool               `json:"store"`
+	Temperature        *float64           `json:"temperature"`
+	TopP               *float64           `json:"top_p"`
+	Text               *TextConfig        `json:"text,omitempty"`
+	ToolChoice         any                `json:"tool_choice"`
+	Tools              []Tool             `json:"tools"`
+	ParallelToolCalls  bool               `json:"parallel_tool_calls"`
+	Usage              *Usage             `json:"usage"`
+	Metadata           map[string]string  `json:"metadata"`
+}
+
+// Error describes why a response failed.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// IncompleteDetails explains why a response is incomplete.
+type IncompleteDetails struct {
+	Reason string `json:"reason"` // max_output_tokens, content_filter, …
+}
+
+// Usage is the Responses API token usage.
+type Usage struct {
+	InputTokens         int                 `json:"input_tokens"`
+	InputTokensDetails  InputTokensDetails  `json:"input_tokens_details"`
+	OutputTokens        int                 `json:"output_tokens"`
+	OutputTokensDetails OutputTokensDetails `json:"output_tokens_details"`
+	TotalTokens         int                 `json:"total_tokens"`
+}
+
+// InputTokensDetails breaks down input tokens.
+type InputTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// OutputTokensDetails breaks down output tokens.
+type OutputTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+// jsonString decodes data if it is a JSON string.
+func jsonString(data []byte) (string, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '"' {
+		return "", false
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", false
+	}
+	return s, true
+}
@@ -2,9 +2,15 @@ package store
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/batch"
 	"github.com/xraph/nexus/key"
+	"github.com/xraph/nexus/prompt"
 	"github.com/xraph/nexus/tenant"
 	"github.com/xraph/nexus/usage"
 )
@@ -14,6 +20,9 @@ type memoryStore struct {
 	tenants *memoryTenantStore
 	keys    *memoryKeyStore
 	usage   *memoryUsageStore
+	prompts *memoryPromptStore
+	batches *memoryBatchStore
+	bg      *memoryBackgroundStore
 }
 
 // NewMemory creates an in-memory store.
@@ -22,15 +31,28 @@ func NewMemory() Store {
 		tenants: &memoryTenantStore{data: make(map[string]*tenant.Tenant)},
 		keys:    &memoryKeyStore{data: make(map[string]*key.APIKey)},
 		usage:   &memoryUsageStore{},
+		prompts: &memoryPromptStore{labels: make(map[string]*prompt.Label)},
+		batches: &memoryBatchStore{
+			jobs:  make(map[string]batch.Job),
+			files: make(map[string]batch.File),
+		},
+		bg: &memoryBackgroundStore{
+			responses: make(map[string]background.Response),
+			frames:    make(map[string][]background.Frame),
+		},
 	}
 }
 
 func (s *memoryStore) Tenants() tenant.Store { return s.tenants }
 func (s *memoryStore) Keys() key.Store       { return s.keys }
 func (s *memoryStore) Usage() usage.Store    { return s.usage }
+func (s *memoryStore) Prompts() prompt.Store { return s.prompts }
+func (s *memoryStore) Batches() batch.Store  { return s.batches }
 func (s *memoryStore) Migrate() error        { return nil }
 func (s *memoryStore) Close() error          { return nil }
 
+func (s *memoryStore) Background() background.Store { return s.bg }
+
 // memoryTenantStore is an in-memory tenant store.
 type memoryTenantStore struct {
 	mu   sync.RWMutex
@@ -198,3 +220,288 @@ func (s *memoryUsageStore) Query(_ context.Context, _ *usage.QueryOptions) ([]*u
 	defer s.mu.RUnlock()
 	return s.records, len(s.records), nil
 }
+
+// memoryPromptStore is an in-memory prompt template store.
+type memoryPromptStore struct {
+	mu        sync.RWMutex
+	templates []*prompt.Template
+	labels    map[string]*prompt.Label // tenant/name/label
+}
+
+func (s *memoryPromptStore) InsertVersion(_ context.Context, t *prompt.Template) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.templates {
+		if existing.TenantID == t.TenantID && existing.Name == t.Name && existing.Version == t.Version {
+			return fmt.Errorf("prompt %s version %d already exists", t.Name, t.Version)
+		}
+	}
+	s.templates = append(s.templates, t)
+	return nil
+}
+
+func (s *memoryPromptStore) FindVersion(_ context.Context, tenantID, name string, version int) (*prompt.Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var found *prompt.Template
+	for _, t := range s.templates {
+		if t.TenantID != tenantID || t.Name != name {
+			continue
+		}
+		if (version == 0 && (found == nil || t.Version > found.Version)) || t.Version == version {
+			found = t
+		}
+	}
+	return found, nil
+}
+
+func (s *memoryPromptStore) ListVersions(_ context.Context, tenantID, name string) ([]*prompt.Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []*prompt.Template
+	for _, t := range s.templates {
+		if t.TenantID == tenantID && t.Name == name {
+			result = append(result, t)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version > result[j].Version })
+	return result, nil
+}
+
+func (s *memoryPromptStore) ListLatest(_ context.Context, tenantID string) ([]*prompt.Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	latest := make(map[string]*prompt.Template)
+	for _, t := range s.templates {
+		if t.TenantID != tenantID {
+			continue
+		}
+		if cur, ok := latest[t.Name]; !ok || t.Version > cur.Version {
+			latest[t.Name] = t
+		}
+	}
+	result := make([]*prompt.Template, 0, len(latest))
+	for _, t := range latest {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func (s *memoryPromptStore) SetLabel(_ context.Context, l *prompt.Label) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.labels[l.TenantID+"/"+l.Name+"/"+l.Label] = l
+	return nil
+}
+
+func (s *memoryPromptStore) FindLabel(_ context.Context, tenantID, name, label string) (*prompt.Label, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.labels[tenantID+"/"+name+"/"+label], nil
+}
+
+func (s *memoryPromptStore) ListLabels(_ context.Context, tenantID, name string) ([]*prompt.Label, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []*prompt.Label
+	for _, l := range s.labels {
+		if l.TenantID == tenantID && l.Name == name {
+			result = append(result, l)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Label < result[j].Label })
+	return result, nil
+}
+
+// memoryBatchStore is an in-memory batch job and file store. It keeps
+// copies so callers can keep mutating the jobs they pass in.
+type memoryBatchStore struct {
+	mu    sync.RWMutex
+	jobs  map[string]batch.Job
+	files map[string]batch.File
+}
+
+func (s *memoryBatchStore) InsertJob(_ context.Context, job *batch.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.ID]; ok {
+		return fmt.Errorf("batch %s already exists", job.ID)
+	}
+	s.jobs[job.ID] = *job
+	return nil
+}
+
+func (s *memoryBatchStore) UpdateJob(_ context.Context, job *batch.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.ID]; !ok {
+		return fmt.Errorf("batch %s not found", job.ID)
+	}
+	s.jobs[job.ID] = *job
+	return nil
+}
+
+func (s *memoryBatchStore) FindJob(_ context.Context, jobID string) (*batch.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[jobID]
+	if !ok {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+func (s *memoryBatchStore) ListJobs(_ context.Context, tenantID string) ([]*batch.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []*batch.Job
+	for _, job := range s.jobs {
+		if tenantID == "" || job.TenantID == tenantID {
+			result = append(result, &job)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result, nil
+}
+
+func (s *memoryBatchStore) ListJobsByStatus(_ context.Context, statuses ...batch.JobStatus) ([]*batch.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []*batch.Job
+	for _, job := range s.jobs {
+		for _, status := range statuses {
+			if job.Status == status {
+				result = append(result, &job)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryBatchStore) InsertFile(_ context.Context, f *batch.File) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[f.ID] = *f
+	return nil
+}
+
+func (s *memoryBatchStore) FindFile(_ context.Context, fileID string) (*batch.File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f, ok := s.files[fileID]
+	if !ok {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+func (s *memoryBatchStore) ListFiles(_ context.Context, tenantID, purpose string) ([]*batch.File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []*batch.File
+	for _, f := range s.files {
+		if (tenantID != "" && f.TenantID != tenantID) || (purpose != "" && f.Purpose != purpose) {
+			continue
+		}
+		f.Content = nil
+		result = append(result, &f)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result, nil
+}
+
+func (s *memoryBatchStore) DeleteFile(_ context.Context, fileID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, fileID)
+	return nil
+}
+
+// memoryBackgroundStore is an in-memory background response and frame
+// store. Like memoryBatchStore it keeps copies of the responses.
+type memoryBackgroundStore struct {
+	mu        sync.RWMutex
+	responses map[string]background.Response
+	frames    map[string][]background.Frame
+}
+
+func (s *memoryBackgroundStore) InsertResponse(_ context.Context, r *background.Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.responses[r.ID]; ok {
+		return fmt.Errorf("background response %s already exists", r.ID)
+	}
+	s.responses[r.ID] = *r
+	return nil
+}
+
+func (s *memoryBackgroundStore) UpdateResponse(_ context.Context, r *background.Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.responses[r.ID]; !ok {
+		return fmt.Errorf("background response %s not found", r.ID)
+	}
+	s.responses[r.ID] = *r
+	return nil
+}
+
+func (s *memoryBackgroundStore) FindResponse(_ context.Context, responseID string) (*background.Response, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.responses[responseID]
+	if !ok {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+func (s *memoryBackgroundStore) ListResponsesByStatus(_ context.Context, statuses ...background.Status) ([]*background.Response, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []*background.Response
+	for _, r := range s.responses {
+		for _, status := range statuses {
+			if r.Status == status {
+				result = append(result, &r)
+				break
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+func (s *memoryBackgroundStore) AppendFrames(_ context.Context, frames []background.Frame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range frames {
+		s.frames[f.ResponseID] = append(s.frames[f.ResponseID], f)
+	}
+	return nil
+}
+
+func (s *memoryBackgroundStore) ListFrames(_ context.Context, responseID string, after int) ([]background.Frame, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []background.Frame
+	for _, f := range s.frames[responseID] {
+		if f.Seq > after {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryBackgroundStore) DeleteResponsesBefore(_ context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, r := range s.responses {
+		if r.CompletedAt != nil && r.CompletedAt.Before(before) {
+			delete(s.responses, id)
+			delete(s.frames, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/xraph/grove/drivers/mongodriver"
+
+	"github.com/xraph/nexus/background"
+)
+
+type backgroundStore struct {
+	mdb *mongodriver.MongoDB
+}
+
+func (s *backgroundStore) InsertResponse(ctx context.Context, r *background.Response) error {
+	_, err := s.mdb.NewInsert(backgroundResponseToModel(r)).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/mongo: insert background response: %w", err)
+	}
+	return nil
+}
+
+func (s *backgroundStore) UpdateResponse(ctx context.Context, r *background.Response) error {
+	m := backgroundResponseToModel(r)
+	res, err := s.mdb.NewUpdate(m).Filter(bson.M{"_id": m.ID}).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/mongo: update background response: %w", err)
+	}
+	if res.MatchedCount() == 0 {
+		return fmt.Errorf("nexus/mongo: background response not found")
+	}
+	return nil
+}
+
+func (s *backgroundStore) FindResponse(ctx context.Context, responseID string) (*background.Response, error) {
+	var m backgroundResponseModel
+	if err := s.mdb.NewFind(&m).Filter(bson.M{"_id": responseID}).Scan(ctx); err != nil {
+		if isNoDocuments(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/mongo: find background response: %w", err)
+	}
+	return backgroundResponseFromModel(&m)
+}
+
+func (s *backgroundStore) ListResponsesByStatus(ctx context.Context, statuses ...background.Status) ([]*background.Response, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	var models []backgroundResponseModel
+	err := s.mdb.NewFind(&models).
+		Filter(bson.M{"status": bson.M{"$in": values}}).
+		Sort(bson.D{{Key: "created_at", Value: 1}}).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/mongo: list background responses by status: %w", err)
+	}
+	resps := make([]*background.Response, 0, len(models))
+	for i := range models {
+		r, err := backgroundResponseFromModel(&models[i])
+		if err != nil {
+			return nil, fmt.Errorf("nexus/mongo: convert background response model: %w", err)
+		}
+		resps = append(resps, r)
+	}
+	return resps, nil
+}
+
+func (s *backgroundStore) AppendFrames(ctx context.Context, frames []background.Frame) error {
+	if len(frames) == 0 {
+		return nil
+	}
+	models := make([]backgroundFrameModel, len(frames))
+	for i := range frames {
+		models[i] = *backgroundFrameToModel(&frames[i])
+	}
+	if _, err := s.mdb.NewInsert(&models).Exec(ctx); err != nil {
+		return fmt.Errorf("nexus/mongo: append background frames: %w", err)
+	}
+	return nil
+}
+
+func (s *backgroundStore) ListFrames(ctx context.Context, responseID string, after int) ([]background.Frame, error) {
+	var models []backgroundFrameModel
+	err := s.mdb.NewFind(&models).
+		Filter(bson.M{"response_id": responseID, "seq": bson.M{"$gt": after}}).
+		Sort(bson.D{{Key: "seq", Value: 1}}).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/mongo: list background frames: %w", err)
+	}
+	frames := make([]background.Frame, 0, len(models))
+	for i := range models {
+		f, err := backgroundFrameFromModel(&models[i])
+		if err != nil {
+			return nil, fmt.Errorf("nexus/mongo: convert background frame model: %w", err)
+		}
+		frames = append(frames, f)
+	}
+	return frames, nil
+}
+
+func (s *backgroundStore) DeleteResponsesBefore(ctx context.Context, before time.Time) error {
+	var models []backgroundResponseModel
+	expired := bson.M{"completed_at": bson.M{"$lt": before}}
+	if err := s.mdb.NewFind(&models).Filter(expired).Project(bson.M{"_id": 1}).Scan(ctx); err != nil {
+		return fmt.Errorf("nexus/mongo: find expired background responses: %w", err)
+	}
+	if len(models) == 0 {
+		return nil
+	}
+	ids := make([]string, len(models))
+	for i := range models {
+		ids[i] = models[i].ID
+	}
+	_, err := s.mdb.NewDelete((*backgroundFrameModel)(nil)).Filter(bson.M{"response_id": bson.M{"$in": ids}}).Many().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/mongo: delete expired background frames: %w", err)
+	}
+	_, err = s.mdb.NewDelete((*backgroundResponseModel)(nil)).Filter(bson.M{"_id": bson.M{"$in": ids}}).Many().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/mongo: delete expired background responses: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/xraph/grove/drivers/mongodriver"
+
+	"github.com/xraph/nexus/batch"
+)
+
+type batchStore struct {
+	mdb *mongodriver.MongoDB
+}
+
+func (s *batchStore) InsertJob(ctx context.Context, j *batch.Job) error {
+	_, err := s.mdb.NewInsert(batchJobToModel(j)).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/mongo: insert batch: %w", err)
+	}
+	return nil
+}
+
+func (s *batchStore) UpdateJob(ctx context.Context, j *batch.Job) error {
+	m := batchJobToModel(j)
+	res, err := s.mdb.NewUpdate(m).Filter(bson.M{"_id": m.ID}).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/mongo: update batch: %w", err)
+	}
+	if res.MatchedCount() == 0 {
+		return fmt.Errorf("nexus/mongo: batch not found")
+	}
+	return nil
+}
+
+func (s *batchStore) FindJob(ctx context.Context, jobID string) (*batch.Job, error) {
+	var m batchJobModel
+	if err := s.mdb.NewFind(&m).Filter(bson.M{"_id": jobID}).Scan(ctx); err != nil {
+		if isNoDocuments(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/mongo: find batch: %w", err)
+	}
+	return batchJobFromModel(&m), nil
+}
+
+func (s *batchStore) ListJobs(ctx context.Context, tenantID string) ([]*batch.Job, error) {
+	filter := bson.M{}
+	if tenantID != "" {
+		filter["tenant_id"] = tenantID
+	}
+	return s.findJobs(ctx, filter, -1)
+}
+
+func (s *batchStore) ListJobsByStatus(ctx context.Context, statuses ...batch.JobStatus) ([]*batch.Job, error) {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	return s.findJobs(ctx, bson.M{"status": bson.M{"$in": values}}, 1)
+}
+
+func (s *batchStore) findJobs(ctx context.Context, filter bson.M, order int) ([]*batch.Job, error) {
+	var models []batchJobModel
+	err := s.mdb.NewFind(&models).
+		Filter(filter).
+		Sort(bson.D{{Key: "created_at", Value: order}}).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/mongo: list batches: %w", err)
+	}
+	jobs := make([]*batch.Job, 0, len(models))
+	for i := range models {
+		jobs = append(jobs, batchJobFromModel(&models[i]))
+	}
+	return jobs, nil
+}
+
+func (s *batchStore) InsertFile(ctx context.Context, f *batch.File) error {
+	_, err := s.mdb.NewInsert(batchFileToModel(f)).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/mongo: insert file: %w", err)
+	}
+	return nil
+}
+
+func (s *batchStore) FindFile(ctx context.Context, fileID string) (*batch.File, error) {
+	var m batchFileModel
+	if err := s.mdb.NewFind(&m).Filter(bson.M{"_id": fileID}).Scan(ctx); err != nil {
+		if isNoDocuments(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/mongo: find file: %w", err)
+	}
+	return batchFileFromModel(&m), nil
+}
+
+func (s *batchStore) ListFiles(ctx context.Context, tenantID, purpose string) ([]*batch.File, error) {
+	filter := bson.M{}
+	if tenantID != "" {
+		filter["tenant_id"] = tenantID
+	}
+	if purpose != "" {
+		filter["purpose"] = purpose
+	}
+	var models []batchFileModel
+	err := s.mdb.NewFind(&models).
+		Filter(filter).
+		Project(bson.M{"content": 0}).
+		Sort(bson.D{{Key: "created_at", Value: -1}}).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/mongo: list files: %w", err)
+	}
+	files := make([]*batch.File, 0, len(models))
+	for i := range models {
+		files = append(files, batchFileFromModel(&models[i]))
+	}
+	return files, nil
+}
+
+func (s *batchStore) DeleteFile(ctx context.Context, fileID string) error {
+	_, err := s.mdb.NewDelete((*batchFileModel)(nil)).Filter(bson.M{"_id": fileID}).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/mongo: delete file: %w", err)
+	}
+	return nil
+}
@@ -100,6 +100,108 @@ func init() {
 				return mexec.DropCollection(ctx, (*usageModel)(nil))
 			},
 		},
+		&migrate.Migration{
+			Name:    "create_nexus_prompts",
+			Version: "20240101000004",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+
+				if err := mexec.CreateCollection(ctx, (*promptTemplateModel)(nil)); err != nil {
+					return err
+				}
+				if err := mexec.CreateCollection(ctx, (*promptLabelModel)(nil)); err != nil {
+					return err
+				}
+
+				if err := mexec.CreateIndexes(ctx, colPromptTemplates, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}, {Key: "version", Value: -1}}},
+				}); err != nil {
+					return err
+				}
+				return mexec.CreateIndexes(ctx, colPromptLabels, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}}},
+				})
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+				if err := mexec.DropCollection(ctx, (*promptLabelModel)(nil)); err != nil {
+					return err
+				}
+				return mexec.DropCollection(ctx, (*promptTemplateModel)(nil))
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_nexus_batches",
+			Version: "20240101000005",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+
+				if err := mexec.CreateCollection(ctx, (*batchJobModel)(nil)); err != nil {
+					return err
+				}
+				if err := mexec.CreateCollection(ctx, (*batchFileModel)(nil)); err != nil {
+					return err
+				}
+
+				indexes := migrationIndexes()
+				if err := mexec.CreateIndexes(ctx, colBatchJobs, indexes[colBatchJobs]); err != nil {
+					return err
+				}
+				return mexec.CreateIndexes(ctx, colBatchFiles, indexes[colBatchFiles])
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+				if err := mexec.DropCollection(ctx, (*batchFileModel)(nil)); err != nil {
+					return err
+				}
+				return mexec.DropCollection(ctx, (*batchJobModel)(nil))
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_nexus_background_responses",
+			Version: "20240101000006",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+
+				if err := mexec.CreateCollection(ctx, (*backgroundResponseModel)(nil)); err != nil {
+					return err
+				}
+				if err := mexec.CreateCollection(ctx, (*backgroundFrameModel)(nil)); err != nil {
+					return err
+				}
+
+				indexes := migrationIndexes()
+				if err := mexec.CreateIndexes(ctx, colBackgroundResponses, indexes[colBackgroundResponses]); err != nil {
+					return err
+				}
+				return mexec.CreateIndexes(ctx, colBackgroundFrames, indexes[colBackgroundFrames])
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+				if err := mexec.DropCollection(ctx, (*backgroundFrameModel)(nil)); err != nil {
+					return err
+				}
+				return mexec.DropCollection(ctx, (*backgroundResponseModel)(nil))
+			},
+		},
 	)
 }
 
@@ -123,5 +225,28 @@ func migrationIndexes() map[string][]mongo.IndexModel {
 			{Keys: bson.D{{Key: "provider", Value: 1}}},
 			{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "provider", Value: 1}, {Key: "model", Value: 1}}},
 		},
+		colPromptTemplates: {
+			{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}, {Key: "version", Value: -1}}},
+		},
+		colPromptLabels: {
+			{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}}},
+		},
+		colBatchJobs: {
+			{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "status", Value: 1}}},
+		},
+		colBatchFiles: {
+			{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "purpose", Value: 1}, {Key: "created_at", Value: -1}}},
+		},
+		colBackgroundResponses: {
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+			{Keys: bson.D{{Key: "completed_at", Value: 1}}},
+		},
+		colBackgroundFrames: {
+			{
+				Keys:    bson.D{{Key: "response_id", Value: 1}, {Key: "seq", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
 	}
 }
@@ -1,12 +1,18 @@
 package mongo
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/xraph/grove"
 
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/batch"
 	"github.com/xraph/nexus/id"
 	"github.com/xraph/nexus/key"
+	"github.com/xraph/nexus/prompt"
 	"github.com/xraph/nexus/tenant"
 	"github.com/xraph/nexus/usage"
 )
@@ -130,6 +136,7 @@ type usageModel struct {
 	KeyID            string    `grove:"key_id"            bson:"key_id"`
 	RequestID        string    `grove:"request_id"        bson:"request_id"`
 	Provider         string    `grove:"provider"          bson:"provider"`
+	ProviderType     string    `grove:"provider_type"     bson:"provider_type,omitempty"`
 	Model            string    `grove:"model"             bson:"model"`
 	PromptTokens     int       `grove:"prompt_tokens"     bson:"prompt_tokens"`
 	CompletionTokens int       `grove:"completion_tokens" bson:"completion_tokens"`
@@ -138,6 +145,11 @@ type usageModel struct {
 	LatencyNs        int64     `grove:"latency_ns"        bson:"latency_ns"`
 	Cached           bool      `grove:"cached"            bson:"cached"`
 	StatusCode       int       `grove:"status_code"       bson:"status_code"`
+	PromptID         string    `grove:"prompt_id"         bson:"prompt_id,omitempty"`
+	PromptVersion    int       `grove:"prompt_version"    bson:"prompt_version,omitempty"`
+	AudioSeconds     float64   `grove:"audio_seconds"     bson:"audio_seconds,omitempty"`
+	Characters       int       `grove:"characters"        bson:"characters,omitempty"`
+	BatchID          string    `grove:"batch_id"          bson:"batch_id,omitempty"`
 	CreatedAt        time.Time `grove:"created_at"        bson:"created_at"`
 }
 
@@ -148,6 +160,7 @@ func usageToModel(rec *usage.Record) *usageModel {
 		KeyID:            rec.KeyID.String(),
 		RequestID:        rec.RequestID.String(),
 		Provider:         rec.Provider,
+		ProviderType:     rec.ProviderType,
 		Model:            rec.Model,
 		PromptTokens:     rec.PromptTokens,
 		CompletionTokens: rec.CompletionTokens,
@@ -156,6 +169,11 @@ func usageToModel(rec *usage.Record) *usageModel {
 		LatencyNs:        rec.Latency.Nanoseconds(),
 		Cached:           rec.Cached,
 		StatusCode:       rec.StatusCode,
+		PromptID:         rec.PromptID,
+		PromptVersion:    rec.PromptVersion,
+		AudioSeconds:     rec.AudioSeconds,
+		Characters:       rec.Characters,
+		BatchID:          rec.BatchID,
 		CreatedAt:        rec.CreatedAt,
 	}
 }
@@ -183,6 +201,7 @@ func usageFromModel(m *usageModel) (*usage.Record, error) {
 		KeyID:            kid,
 		RequestID:        rid,
 		Provider:         m.Provider,
+		ProviderType:     m.ProviderType,
 		Model:            m.Model,
 		PromptTokens:     m.PromptTokens,
 		CompletionTokens: m.CompletionTokens,
@@ -191,6 +210,301 @@ func usageFromModel(m *usageModel) (*usage.Record, error) {
 		Latency:          time.Duration(m.LatencyNs),
 		Cached:           m.Cached,
 		StatusCode:       m.StatusCode,
+		PromptID:         m.PromptID,
+		PromptVersion:    m.PromptVersion,
+		AudioSeconds:     m.AudioSeconds,
+		Characters:       m.Characters,
+		BatchID:          m.BatchID,
 		CreatedAt:        m.CreatedAt,
 	}, nil
 }
+
+// ──────────────────────────────────────────────────
+// Prompt models
+// ──────────────────────────────────────────────────
+
+type promptTemplateModel struct {
+	grove.BaseModel `grove:"table:nexus_prompt_templates"`
+	ID              string            `grove:"id,pk"       bson:"_id"` // tenant/name/version
+	TenantID        string            `grove:"tenant_id"   bson:"tenant_id"`
+	Name            string            `grove:"name"        bson:"name"`
+	Version         int               `grove:"version"     bson:"version"`
+	Content         string            `grove:"content"     bson:"content"`
+	Description     string            `grove:"description" bson:"description,omitempty"`
+	Metadata        map[string]string `grove:"metadata"    bson:"metadata,omitempty"`
+	CreatedAt       time.Time         `grove:"created_at"  bson:"created_at"`
+}
+
+func promptTemplateToModel(t *prompt.Template) *promptTemplateModel {
+	return &promptTemplateModel{
+		ID:          t.TenantID + "/" + t.Name + "/" + strconv.Itoa(t.Version),
+		TenantID:    t.TenantID,
+		Name:        t.Name,
+		Version:     t.Version,
+		Content:     t.Content,
+		Description: t.Description,
+		Metadata:    t.Metadata,
+		CreatedAt:   t.CreatedAt,
+	}
+}
+
+func promptTemplateFromModel(m *promptTemplateModel) *prompt.Template {
+	return &prompt.Template{
+		Name:        m.Name,
+		Version:     m.Version,
+		TenantID:    m.TenantID,
+		Content:     m.Content,
+		Description: m.Description,
+		Metadata:    m.Metadata,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+type promptLabelModel struct {
+	grove.BaseModel `grove:"table:nexus_prompt_labels"`
+	ID              string    `grove:"id,pk"      bson:"_id"` // tenant/name/label
+	TenantID        string    `grove:"tenant_id"  bson:"tenant_id"`
+	Name            string    `grove:"name"       bson:"name"`
+	Label           string    `grove:"label"      bson:"label"`
+	Version         int       `grove:"version"    bson:"version"`
+	UpdatedAt       time.Time `grove:"updated_at" bson:"updated_at"`
+}
+
+func promptLabelToModel(l *prompt.Label) *promptLabelModel {
+	return &promptLabelModel{
+		ID:        l.TenantID + "/" + l.Name + "/" + l.Label,
+		TenantID:  l.TenantID,
+		Name:      l.Name,
+		Label:     l.Label,
+		Version:   l.Version,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
+
+func promptLabelFromModel(m *promptLabelModel) *prompt.Label {
+	return &prompt.Label{
+		Name:      m.Name,
+		TenantID:  m.TenantID,
+		Label:     m.Label,
+		Version:   m.Version,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+// ──────────────────────────────────────────────────
+// Batch models
+// ──────────────────────────────────────────────────
+
+type batchJobModel struct {
+	grove.BaseModel  `grove:"table:nexus_batch_jobs"`
+	ID               string         `grove:"id,pk"             bson:"_id"`
+	TenantID         string         `grove:"tenant_id"         bson:"tenant_id"`
+	KeyID            string         `grove:"key_id"            bson:"key_id,omitempty"`
+	Status           string         `grove:"status"            bson:"status"`
+	TotalItems       int            `grove:"total_items"       bson:"total_items"`
+	Completed        int            `grove:"completed"         bson:"completed"`
+	Failed           int            `grove:"failed"            bson:"failed"`
+	Endpoint         string         `grove:"endpoint"          bson:"endpoint"`
+	InputFileID      string         `grove:"input_file_id"     bson:"input_file_id"`
+	OutputFileID     string         `grove:"output_file_id"    bson:"output_file_id,omitempty"`
+	ErrorFileID      string         `grove:"error_file_id"     bson:"error_file_id,omitempty"`
+	CompletionWindow string         `grove:"completion_window" bson:"completion_window"`
+	Provider         string         `grove:"provider"          bson:"provider,omitempty"`
+	ProviderBatchID  string         `grove:"provider_batch_id" bson:"provider_batch_id,omitempty"`
+	Errors           []batch.Error  `grove:"errors"            bson:"errors,omitempty"`
+	Metadata         map[string]any `grove:"metadata"          bson:"metadata,omitempty"`
+	CreatedAt        time.Time      `grove:"created_at"        bson:"created_at"`
+	StartedAt        *time.Time     `grove:"started_at"        bson:"started_at,omitempty"`
+	CompletedAt      *time.Time     `grove:"completed_at"      bson:"completed_at,omitempty"`
+}
+
+func batchJobToModel(j *batch.Job) *batchJobModel {
+	return &batchJobModel{
+		ID:               j.ID,
+		TenantID:         j.TenantID,
+		KeyID:            j.KeyID,
+		Status:           string(j.Status),
+		TotalItems:       j.TotalItems,
+		Completed:        j.Completed,
+		Failed:           j.Failed,
+		Endpoint:         j.Endpoint,
+		InputFileID:      j.InputFileID,
+		OutputFileID:     j.OutputFileID,
+		ErrorFileID:      j.ErrorFileID,
+		CompletionWindow: j.CompletionWindow,
+		Provider:         j.Provider,
+		ProviderBatchID:  j.ProviderBatchID,
+		Errors:           j.Errors,
+		Metadata:         j.Metadata,
+		CreatedAt:        j.CreatedAt,
+		StartedAt:        j.StartedAt,
+		CompletedAt:      j.CompletedAt,
+	}
+}
+
+func batchJobFromModel(m *batchJobModel) *batch.Job {
+	return &batch.Job{
+		ID:               m.ID,
+		TenantID:         m.TenantID,
+		KeyID:            m.KeyID,
+		Status:           batch.JobStatus(m.Status),
+		TotalItems:       m.TotalItems,
+		Completed:        m.Completed,
+		Failed:           m.Failed,
+		Endpoint:         m.Endpoint,
+		InputFileID:      m.InputFileID,
+		OutputFileID:     m.OutputFileID,
+		ErrorFileID:      m.ErrorFileID,
+		CompletionWindow: m.CompletionWindow,
+		Provider:         m.Provider,
+		ProviderBatchID:  m.ProviderBatchID,
+		Errors:           m.Errors,
+		Metadata:         m.Metadata,
+		CreatedAt:        m.CreatedAt,
+		StartedAt:        m.StartedAt,
+		CompletedAt:      m.CompletedAt,
+	}
+}
+
+type batchFileModel struct {
+	grove.BaseModel `grove:"table:nexus_batch_files"`
+	ID              string    `grove:"id,pk"      bson:"_id"`
+	TenantID        string    `grove:"tenant_id"  bson:"tenant_id"`
+	Filename        string    `grove:"filename"   bson:"filename"`
+	Purpose         string    `grove:"purpose"    bson:"purpose"`
+	Bytes           int       `grove:"bytes"      bson:"bytes"`
+	Content         []byte    `grove:"content"    bson:"content,omitempty"`
+	CreatedAt       time.Time `grove:"created_at" bson:"created_at"`
+}
+
+func batchFileToModel(f *batch.File) *batchFileModel {
+	return &batchFileModel{
+		ID:        f.ID,
+		TenantID:  f.TenantID,
+		Filename:  f.Filename,
+		Purpose:   f.Purpose,
+		Bytes:     f.Bytes,
+		Content:   f.Content,
+		CreatedAt: f.CreatedAt,
+	}
+}
+
+func batchFileFromModel(m *batchFileModel) *batch.File {
+	return &batch.File{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		Filename:  m.Filename,
+		Purpose:   m.Purpose,
+		Bytes:     m.Bytes,
+		Content:   m.Content,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// ──────────────────────────────────────────────────
+// Background response models
+// ──────────────────────────────────────────────────
+
+// Requests, results and chunks are stored as JSON rather than mapped to
+// BSON: they carry free-form fields (tool schemas, provider extras) that
+// would not round-trip through BSON decoding.
+type backgroundResponseModel struct {
+	grove.BaseModel `grove:"table:nexus_background_responses"`
+	ID              string            `grove:"id,pk"        bson:"_id"`
+	TenantID        string            `grove:"tenant_id"    bson:"tenant_id"`
+	KeyID           string            `grove:"key_id"       bson:"key_id,omitempty"`
+	Status          string            `grove:"status"       bson:"status"`
+	Model           string            `grove:"model"        bson:"model"`
+	Endpoint        string            `grove:"endpoint"     bson:"endpoint"`
+	Request         string            `grove:"request"      bson:"request"`
+	Result          string            `grove:"result"       bson:"result,omitempty"`
+	Error           *background.Error `grove:"error"        bson:"error,omitempty"`
+	Frames          int               `grove:"frames"       bson:"frames"`
+	Metadata        map[string]string `grove:"metadata"     bson:"metadata,omitempty"`
+	CreatedAt       time.Time         `grove:"created_at"   bson:"created_at"`
+	StartedAt       *time.Time        `grove:"started_at"   bson:"started_at,omitempty"`
+	CompletedAt     *time.Time        `grove:"completed_at" bson:"completed_at,omitempty"`
+}
+
+func backgroundResponseToModel(r *background.Response) *backgroundResponseModel {
+	return &backgroundResponseModel{
+		ID:          r.ID,
+		TenantID:    r.TenantID,
+		KeyID:       r.KeyID,
+		Status:      string(r.Status),
+		Model:       r.Model,
+		Endpoint:    r.Endpoint,
+		Request:     jsonString(r.Request),
+		Result:      jsonString(r.Result),
+		Error:       r.Error,
+		Frames:      r.Frames,
+		Metadata:    r.Metadata,
+		CreatedAt:   r.CreatedAt,
+		StartedAt:   r.StartedAt,
+		CompletedAt: r.CompletedAt,
+	}
+}
+
+func backgroundResponseFromModel(m *backgroundResponseModel) (*background.Response, error) {
+	r := &background.Response{
+		ID:          m.ID,
+		TenantID:    m.TenantID,
+		KeyID:       m.KeyID,
+		Status:      background.Status(m.Status),
+		Model:       m.Model,
+		Endpoint:    m.Endpoint,
+		Error:       m.Error,
+		Frames:      m.Frames,
+		Metadata:    m.Metadata,
+		CreatedAt:   m.CreatedAt,
+		StartedAt:   m.StartedAt,
+		CompletedAt: m.CompletedAt,
+	}
+	if m.Request != "" {
+		if err := json.Unmarshal([]byte(m.Request), &r.Request); err != nil {
+			return nil, fmt.Errorf("nexus: unmarshal background request: %w", err)
+		}
+	}
+	if m.Result != "" {
+		if err := json.Unmarshal([]byte(m.Result), &r.Result); err != nil {
+			return nil, fmt.Errorf("nexus: unmarshal background result: %w", err)
+		}
+	}
+	return r, nil
+}
+
+type backgroundFrameModel struct {
+	grove.BaseModel `grove:"table:nexus_background_frames"`
+	ID              string `grove:"id,pk"       bson:"_id"`
+	ResponseID      string `grove:"response_id" bson:"response_id"`
+	Seq             int    `grove:"seq"         bson:"seq"`
+	Chunk           string `grove:"chunk"       bson:"chunk"`
+}
+
+func backgroundFrameToModel(f *background.Frame) *backgroundFrameModel {
+	return &backgroundFrameModel{
+		ID:         f.ResponseID + ":" + strconv.Itoa(f.Seq),
+		ResponseID: f.ResponseID,
+		Seq:        f.Seq,
+		Chunk:      jsonString(f.Chunk),
+	}
+}
+
+func backgroundFrameFromModel(m *backgroundFrameModel) (background.Frame, error) {
+	f := background.Frame{ResponseID: m.ResponseID, Seq: m.Seq}
+	if m.Chunk != "" {
+		if err := json.Unmarshal([]byte(m.Chunk), &f.Chunk); err != nil {
+			return f, fmt.Errorf("nexus: unmarshal background frame: %w", err)
+		}
+	}
+	return f, nil
+}
+
+// jsonString encodes v as JSON, or "" when v is nil.
+func jsonString(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,119 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/xraph/grove/drivers/mongodriver"
+
+	"github.com/xraph/nexus/prompt"
+)
+
+type promptStore struct {
+	mdb *mongodriver.MongoDB
+}
+
+func (s *promptStore) InsertVersion(ctx context.Context, t *prompt.Template) error {
+	m := promptTemplateToModel(t)
+	_, err := s.mdb.NewInsert(m).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/mongo: insert prompt version: %w", err)
+	}
+	return nil
+}
+
+func (s *promptStore) FindVersion(ctx context.Context, tenantID, name string, version int) (*prompt.Template, error) {
+	var m promptTemplateModel
+	filter := bson.M{"tenant_id": tenantID, "name": name}
+	if version > 0 {
+		filter["version"] = version
+	}
+	err := s.mdb.NewFind(&m).
+		Filter(filter).
+		Sort(bson.D{{Key: "version", Value: -1}}).
+		Scan(ctx)
+	if err != nil {
+		if isNoDocuments(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/mongo: find prompt version: %w", err)
+	}
+	return promptTemplateFromModel(&m), nil
+}
+
+func (s *promptStore) ListVersions(ctx context.Context, tenantID, name string) ([]*prompt.Template, error) {
+	var models []promptTemplateModel
+	err := s.mdb.NewFind(&models).
+		Filter(bson.M{"tenant_id": tenantID, "name": name}).
+		Sort(bson.D{{Key: "version", Value: -1}}).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/mongo: list prompt versions: %w", err)
+	}
+	templates := make([]*prompt.Template, 0, len(models))
+	for i := range models {
+		templates = append(templates, promptTemplateFromModel(&models[i]))
+	}
+	return templates, nil
+}
+
+func (s *promptStore) ListLatest(ctx context.Context, tenantID string) ([]*prompt.Template, error) {
+	var models []promptTemplateModel
+	err := s.mdb.NewFind(&models).
+		Filter(bson.M{"tenant_id": tenantID}).
+		Sort(bson.D{{Key: "name", Value: 1}, {Key: "version", Value: -1}}).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/mongo: list prompts: %w", err)
+	}
+	var templates []*prompt.Template
+	for i := range models {
+		// Sorted newest first within each name; keep the first of each.
+		if i > 0 && models[i].Name == models[i-1].Name {
+			continue
+		}
+		templates = append(templates, promptTemplateFromModel(&models[i]))
+	}
+	return templates, nil
+}
+
+func (s *promptStore) SetLabel(ctx context.Context, l *prompt.Label) error {
+	m := promptLabelToModel(l)
+	_, err := s.mdb.NewUpdate(m).Filter(bson.M{"_id": m.ID}).Upsert().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/mongo: set prompt label: %w", err)
+	}
+	return nil
+}
+
+func (s *promptStore) FindLabel(ctx context.Context, tenantID, name, label string) (*prompt.Label, error) {
+	var m promptLabelModel
+	err := s.mdb.NewFind(&m).
+		Filter(bson.M{"tenant_id": tenantID, "name": name, "label": label}).
+		Scan(ctx)
+	if err != nil {
+		if isNoDocuments(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/mongo: find prompt label: %w", err)
+	}
+	return promptLabelFromModel(&m), nil
+}
+
+func (s *promptStore) ListLabels(ctx context.Context, tenantID, name string) ([]*prompt.Label, error) {
+	var models []promptLabelModel
+	err := s.mdb.NewFind(&models).
+		Filter(bson.M{"tenant_id": tenantID, "name": name}).
+		Sort(bson.D{{Key: "label", Value: 1}}).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/mongo: list prompt labels: %w", err)
+	}
+	labels := make([]*prompt.Label, 0, len(models))
+	for i := range models {
+		labels = append(labels, promptLabelFromModel(&models[i]))
+	}
+	return labels, nil
+}
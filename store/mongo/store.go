@@ -14,7 +14,10 @@ import (
 	"github.com/xraph/grove"
 	"github.com/xraph/grove/drivers/mongodriver"
 
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/batch"
 	"github.com/xraph/nexus/key"
+	"github.com/xraph/nexus/prompt"
 	"github.com/xraph/nexus/store"
 	"github.com/xraph/nexus/tenant"
 	"github.com/xraph/nexus/usage"
@@ -25,6 +28,15 @@ const (
 	colTenants = "nexus_tenants"
 	colKeys    = "nexus_api_keys"
 	colUsage   = "nexus_usage_records"
+
+	colPromptTemplates = "nexus_prompt_templates"
+	colPromptLabels    = "nexus_prompt_labels"
+
+	colBatchJobs  = "nexus_batch_jobs"
+	colBatchFiles = "nexus_batch_files"
+
+	colBackgroundResponses = "nexus_background_responses"
+	colBackgroundFrames    = "nexus_background_frames"
 )
 
 // Compile-time interface check.
@@ -44,9 +56,12 @@ func New(db *grove.DB) *Store {
 	}
 }
 
-func (s *Store) Tenants() tenant.Store { return &tenantStore{mdb: s.mdb} }
-func (s *Store) Keys() key.Store       { return &keyStore{mdb: s.mdb} }
-func (s *Store) Usage() usage.Store    { return &usageStore{mdb: s.mdb} }
+func (s *Store) Tenants() tenant.Store        { return &tenantStore{mdb: s.mdb} }
+func (s *Store) Keys() key.Store              { return &keyStore{mdb: s.mdb} }
+func (s *Store) Usage() usage.Store           { return &usageStore{mdb: s.mdb} }
+func (s *Store) Prompts() prompt.Store        { return &promptStore{mdb: s.mdb} }
+func (s *Store) Batches() batch.Store         { return &batchStore{mdb: s.mdb} }
+func (s *Store) Background() background.Store { return &backgroundStore{mdb: s.mdb} }
 
 // Migrate creates indexes for all nexus collections.
 func (s *Store) Migrate() error {
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xraph/grove/drivers/pgdriver"
+
+	"github.com/xraph/nexus/background"
+)
+
+type backgroundStore struct {
+	pgdb *pgdriver.PgDB
+}
+
+func (s *backgroundStore) InsertResponse(ctx context.Context, r *background.Response) error {
+	_, err := s.pgdb.NewInsert(backgroundResponseToModel(r)).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/postgres: insert background response: %w", err)
+	}
+	return nil
+}
+
+func (s *backgroundStore) UpdateResponse(ctx context.Context, r *background.Response) error {
+	_, err := s.pgdb.NewUpdate(backgroundResponseToModel(r)).WherePK().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/postgres: update background response: %w", err)
+	}
+	return nil
+}
+
+func (s *backgroundStore) FindResponse(ctx context.Context, responseID string) (*background.Response, error) {
+	m := new(backgroundResponseModel)
+	if err := s.pgdb.NewSelect(m).Where("id = ?", responseID).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/postgres: find background response: %w", err)
+	}
+	return backgroundResponseFromModel(m)
+}
+
+func (s *backgroundStore) ListResponsesByStatus(ctx context.Context, statuses ...background.Status) ([]*background.Response, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	args := make([]any, len(statuses))
+	for i, status := range statuses {
+		args[i] = string(status)
+	}
+	var models []backgroundResponseModel
+	err := s.pgdb.NewSelect(&models).
+		Where("status IN ("+strings.Repeat("?, ", len(args)-1)+"?)", args...).
+		OrderExpr("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/postgres: list background responses by status: %w", err)
+	}
+	resps := make([]*background.Response, 0, len(models))
+	for i := range models {
+		r, err := backgroundResponseFromModel(&models[i])
+		if err != nil {
+			return nil, fmt.Errorf("nexus/postgres: convert background response model: %w", err)
+		}
+		resps = append(resps, r)
+	}
+	return resps, nil
+}
+
+func (s *backgroundStore) AppendFrames(ctx context.Context, frames []background.Frame) error {
+	if len(frames) == 0 {
+		return nil
+	}
+	models := make([]backgroundFrameModel, len(frames))
+	for i := range frames {
+		models[i] = *backgroundFrameToModel(&frames[i])
+	}
+	if _, err := s.pgdb.NewInsert(&models).Exec(ctx); err != nil {
+		return fmt.Errorf("nexus/postgres: append background frames: %w", err)
+	}
+	return nil
+}
+
+func (s *backgroundStore) ListFrames(ctx context.Context, responseID string, after int) ([]background.Frame, error) {
+	var models []backgroundFrameModel
+	err := s.pgdb.NewSelect(&models).
+		Where("response_id = ?", responseID).
+		Where("seq > ?", after).
+		OrderExpr("seq ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/postgres: list background frames: %w", err)
+	}
+	frames := make([]background.Frame, 0, len(models))
+	for i := range models {
+		f, err := backgroundFrameFromModel(&models[i])
+		if err != nil {
+			return nil, fmt.Errorf("nexus/postgres: convert background frame model: %w", err)
+		}
+		frames = append(frames, f)
+	}
+	return frames, nil
+}
+
+func (s *backgroundStore) DeleteResponsesBefore(ctx context.Context, before time.Time) error {
+	_, err := s.pgdb.NewDelete((*backgroundFrameModel)(nil)).
+		Where("response_id IN (SELECT id FROM nexus_background_responses WHERE completed_at < ?)", before).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/postgres: delete expired background frames: %w", err)
+	}
+	if _, err := s.pgdb.NewDelete((*backgroundResponseModel)(nil)).Where("completed_at < ?", before).Exec(ctx); err != nil {
+		return fmt.Errorf("nexus/postgres: delete expired background responses: %w", err)
+	}
+	return nil
+}
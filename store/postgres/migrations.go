@@ -101,6 +101,248 @@ CREATE INDEX IF NOT EXISTS idx_nexus_usage_created ON nexus_usage_records(create
 				return err
 			},
 		},
+		&migrate.Migration{
+			Name:    "create_prompts",
+			Version: "20240101000004",
+			Comment: "Create prompt template tables and record prompt versions on usage",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS nexus_prompt_templates (
+    tenant_id   TEXT NOT NULL DEFAULT '',
+    name        TEXT NOT NULL,
+    version     INTEGER NOT NULL,
+    content     TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    metadata    JSONB NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, name, version)
+);
+
+CREATE TABLE IF NOT EXISTS nexus_prompt_labels (
+    tenant_id  TEXT NOT NULL DEFAULT '',
+    name       TEXT NOT NULL,
+    label      TEXT NOT NULL,
+    version    INTEGER NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, name, label)
+);
+
+ALTER TABLE nexus_usage_records ADD COLUMN IF NOT EXISTS prompt_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE nexus_usage_records ADD COLUMN IF NOT EXISTS prompt_version INTEGER NOT NULL DEFAULT 0;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP TABLE IF EXISTS nexus_prompt_labels;
+DROP TABLE IF EXISTS nexus_prompt_templates;
+ALTER TABLE nexus_usage_records DROP COLUMN IF EXISTS prompt_version;
+ALTER TABLE nexus_usage_records DROP COLUMN IF EXISTS prompt_id;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_audio_usage",
+			Version: "20240101000005",
+			Comment: "Record audio seconds and synthesized characters on usage",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE nexus_usage_records ADD COLUMN IF NOT EXISTS audio_seconds DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE nexus_usage_records ADD COLUMN IF NOT EXISTS characters INTEGER NOT NULL DEFAULT 0;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE nexus_usage_records DROP COLUMN IF EXISTS characters;
+ALTER TABLE nexus_usage_records DROP COLUMN IF EXISTS audio_seconds;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_batches",
+			Version: "20240101000006",
+			Comment: "Create batch job and file tables and record batch IDs on usage",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS nexus_batch_files (
+    id         TEXT PRIMARY KEY,
+    tenant_id  TEXT NOT NULL DEFAULT '',
+    filename   TEXT NOT NULL DEFAULT '',
+    purpose    TEXT NOT NULL,
+    bytes      INTEGER NOT NULL DEFAULT 0,
+    content    BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_nexus_batch_files_tenant ON nexus_batch_files(tenant_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS nexus_batch_jobs (
+    id                TEXT PRIMARY KEY,
+    tenant_id         TEXT NOT NULL DEFAULT '',
+    key_id            TEXT NOT NULL DEFAULT '',
+    status            TEXT NOT NULL,
+    total_items       INTEGER NOT NULL DEFAULT 0,
+    completed         INTEGER NOT NULL DEFAULT 0,
+    failed            INTEGER NOT NULL DEFAULT 0,
+    endpoint          TEXT NOT NULL,
+    input_file_id     TEXT NOT NULL DEFAULT '',
+    output_file_id    TEXT NOT NULL DEFAULT '',
+    error_file_id     TEXT NOT NULL DEFAULT '',
+    completion_window TEXT NOT NULL DEFAULT '',
+    provider          TEXT NOT NULL DEFAULT '',
+    provider_batch_id TEXT NOT NULL DEFAULT '',
+    errors            JSONB NOT NULL DEFAULT '[]',
+    metadata          JSONB NOT NULL DEFAULT '{}',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at        TIMESTAMPTZ,
+    completed_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_nexus_batch_jobs_tenant ON nexus_batch_jobs(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_nexus_batch_jobs_status ON nexus_batch_jobs(status);
+
+ALTER TABLE nexus_usage_records ADD COLUMN IF NOT EXISTS batch_id TEXT NOT NULL DEFAULT '';
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP TABLE IF EXISTS nexus_batch_jobs;
+DROP TABLE IF EXISTS nexus_batch_files;
+ALTER TABLE nexus_usage_records DROP COLUMN IF EXISTS batch_id;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_usage_provider_type",
+			Version: "20240101000007",
+			Comment: "Record the provider type alongside the instance name on usage",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE nexus_usage_records ADD COLUMN IF NOT EXISTS provider_type TEXT NOT NULL DEFAULT '';
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE nexus_usage_records DROP COLUMN IF EXISTS provider_type;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_background_responses",
+			Version: "20240101000008",
+			Comment: "Create nexus_background_responses and nexus_background_frames tables",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS nexus_background_responses (
+    id           TEXT PRIMARY KEY,
+    tenant_id    TEXT NOT NULL DEFAULT '',
+    key_id       TEXT NOT NULL DEFAULT '',
+    status       TEXT NOT NULL,
+    model        TEXT NOT NULL DEFAULT '',
+    endpoint     TEXT NOT NULL DEFAULT '',
+    request      JSONB NOT NULL DEFAULT 'null',
+    result       JSONB NOT NULL DEFAULT 'null',
+    error        JSONB NOT NULL DEFAULT 'null',
+    frames       INTEGER NOT NULL DEFAULT 0,
+    metadata     JSONB NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at   TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_nexus_background_responses_status ON nexus_background_responses(status);
+CREATE INDEX IF NOT EXISTS idx_nexus_background_responses_completed_at ON nexus_background_responses(completed_at);
+
+CREATE TABLE IF NOT EXISTS nexus_background_frames (
+    response_id TEXT NOT NULL,
+    seq         INTEGER NOT NULL,
+    chunk       JSONB NOT NULL,
+    PRIMARY KEY (response_id, seq)
+);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP TABLE IF EXISTS nexus_background_frames;
+DROP TABLE IF EXISTS nexus_background_responses;
+`)
+				return err
+			},
+		},
+	)
+	return g
+}()
+
+// VectorMigrations is the grove migration group for the pgvector-backed RAG
+// index returned by Store.VectorIndex. It is separate from Migrations
+// because it requires the pgvector extension; run it with
+// Store.MigrateVectorIndex.
+var VectorMigrations = func() *migrate.Group {
+	g := migrate.NewGroup("nexus_rag")
+	g.MustRegister(
+		&migrate.Migration{
+			Name:    "create_rag_chunks",
+			Version: "20240101000001",
+			Comment: "Create nexus_rag_chunks table with a pgvector column",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE EXTENSION IF NOT EXISTS vector;
+
+CREATE TABLE IF NOT EXISTS nexus_rag_chunks (
+    id          TEXT PRIMARY KEY,
+    document_id TEXT NOT NULL,
+    tenant_id   TEXT NOT NULL DEFAULT '',
+    content     TEXT NOT NULL,
+    source      TEXT NOT NULL DEFAULT '',
+    title       TEXT NOT NULL DEFAULT '',
+    metadata    JSONB NOT NULL DEFAULT '{}',
+    embedding   vector NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_nexus_rag_chunks_tenant ON nexus_rag_chunks(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_nexus_rag_chunks_document ON nexus_rag_chunks(document_id);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `DROP TABLE IF EXISTS nexus_rag_chunks`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "key_rag_chunks_by_tenant",
+			Version: "20240101000002",
+			Comment: "Key nexus_rag_chunks by tenant and chunk ID so tenants sharing a document ID stay separate",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE nexus_rag_chunks DROP CONSTRAINT IF EXISTS nexus_rag_chunks_pkey;
+ALTER TABLE nexus_rag_chunks ADD PRIMARY KEY (tenant_id, id);
+
+DROP INDEX IF EXISTS idx_nexus_rag_chunks_document;
+CREATE INDEX IF NOT EXISTS idx_nexus_rag_chunks_document ON nexus_rag_chunks(tenant_id, document_id);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP INDEX IF EXISTS idx_nexus_rag_chunks_document;
+CREATE INDEX IF NOT EXISTS idx_nexus_rag_chunks_document ON nexus_rag_chunks(document_id);
+
+ALTER TABLE nexus_rag_chunks DROP CONSTRAINT IF EXISTS nexus_rag_chunks_pkey;
+ALTER TABLE nexus_rag_chunks ADD PRIMARY KEY (id);
+`)
+				return err
+			},
+		},
 	)
 	return g
 }()
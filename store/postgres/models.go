@@ -7,8 +7,11 @@ import (
 
 	"github.com/xraph/grove"
 
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/batch"
 	"github.com/xraph/nexus/id"
 	"github.com/xraph/nexus/key"
+	"github.com/xraph/nexus/prompt"
 	"github.com/xraph/nexus/tenant"
 	"github.com/xraph/nexus/usage"
 )
@@ -145,6 +148,7 @@ type usageModel struct {
 	KeyID            string    `grove:"key_id,notnull"`
 	RequestID        string    `grove:"request_id,notnull"`
 	Provider         string    `grove:"provider,notnull"`
+	ProviderType     string    `grove:"provider_type"`
 	Model            string    `grove:"model,notnull"`
 	PromptTokens     int       `grove:"prompt_tokens"`
 	CompletionTokens int       `grove:"completion_tokens"`
@@ -153,6 +157,11 @@ type usageModel struct {
 	LatencyNs        int64     `grove:"latency_ns"`
 	Cached           bool      `grove:"cached"`
 	StatusCode       int       `grove:"status_code"`
+	PromptID         string    `grove:"prompt_id"`
+	PromptVersion    int       `grove:"prompt_version"`
+	AudioSeconds     float64   `grove:"audio_seconds"`
+	Characters       int       `grove:"characters"`
+	BatchID          string    `grove:"batch_id"`
 	CreatedAt        time.Time `grove:"created_at,notnull,default:current_timestamp"`
 }
 
@@ -163,6 +172,7 @@ func usageToModel(rec *usage.Record) *usageModel {
 		KeyID:            rec.KeyID.String(),
 		RequestID:        rec.RequestID.String(),
 		Provider:         rec.Provider,
+		ProviderType:     rec.ProviderType,
 		Model:            rec.Model,
 		PromptTokens:     rec.PromptTokens,
 		CompletionTokens: rec.CompletionTokens,
@@ -171,6 +181,11 @@ func usageToModel(rec *usage.Record) *usageModel {
 		LatencyNs:        rec.Latency.Nanoseconds(),
 		Cached:           rec.Cached,
 		StatusCode:       rec.StatusCode,
+		PromptID:         rec.PromptID,
+		PromptVersion:    rec.PromptVersion,
+		AudioSeconds:     rec.AudioSeconds,
+		Characters:       rec.Characters,
+		BatchID:          rec.BatchID,
 		CreatedAt:        rec.CreatedAt,
 	}
 }
@@ -198,6 +213,7 @@ func usageFromModel(m *usageModel) (*usage.Record, error) {
 		KeyID:            kid,
 		RequestID:        rid,
 		Provider:         m.Provider,
+		ProviderType:     m.ProviderType,
 		Model:            m.Model,
 		PromptTokens:     m.PromptTokens,
 		CompletionTokens: m.CompletionTokens,
@@ -206,10 +222,299 @@ func usageFromModel(m *usageModel) (*usage.Record, error) {
 		Latency:          time.Duration(m.LatencyNs),
 		Cached:           m.Cached,
 		StatusCode:       m.StatusCode,
+		PromptID:         m.PromptID,
+		PromptVersion:    m.PromptVersion,
+		AudioSeconds:     m.AudioSeconds,
+		Characters:       m.Characters,
+		BatchID:          m.BatchID,
 		CreatedAt:        m.CreatedAt,
 	}, nil
 }
 
+// ──────────────────────────────────────────────────
+// Prompt models
+// ──────────────────────────────────────────────────
+
+type promptTemplateModel struct {
+	grove.BaseModel `grove:"table:nexus_prompt_templates"`
+	TenantID        string    `grove:"tenant_id,pk"`
+	Name            string    `grove:"name,pk"`
+	Version         int       `grove:"version,pk"`
+	Content         string    `grove:"content,notnull"`
+	Description     string    `grove:"description"`
+	Metadata        string    `grove:"metadata,type:jsonb"`
+	CreatedAt       time.Time `grove:"created_at,notnull,default:current_timestamp"`
+}
+
+func promptTemplateToModel(t *prompt.Template) *promptTemplateModel {
+	return &promptTemplateModel{
+		TenantID:    t.TenantID,
+		Name:        t.Name,
+		Version:     t.Version,
+		Content:     t.Content,
+		Description: t.Description,
+		Metadata:    mustJSON(t.Metadata),
+		CreatedAt:   t.CreatedAt,
+	}
+}
+
+func promptTemplateFromModel(m *promptTemplateModel) (*prompt.Template, error) {
+	t := &prompt.Template{
+		Name:        m.Name,
+		Version:     m.Version,
+		TenantID:    m.TenantID,
+		Content:     m.Content,
+		Description: m.Description,
+		CreatedAt:   m.CreatedAt,
+	}
+	if m.Metadata != "" && m.Metadata != "null" {
+		if err := json.Unmarshal([]byte(m.Metadata), &t.Metadata); err != nil {
+			return nil, fmt.Errorf("nexus: unmarshal prompt metadata: %w", err)
+		}
+	}
+	return t, nil
+}
+
+type promptLabelModel struct {
+	grove.BaseModel `grove:"table:nexus_prompt_labels"`
+	TenantID        string    `grove:"tenant_id,pk"`
+	Name            string    `grove:"name,pk"`
+	Label           string    `grove:"label,pk"`
+	Version         int       `grove:"version,notnull"`
+	UpdatedAt       time.Time `grove:"updated_at,notnull,default:current_timestamp"`
+}
+
+func promptLabelFromModel(m *promptLabelModel) *prompt.Label {
+	return &prompt.Label{
+		Name:      m.Name,
+		TenantID:  m.TenantID,
+		Label:     m.Label,
+		Version:   m.Version,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+// ──────────────────────────────────────────────────
+// Batch models
+// ──────────────────────────────────────────────────
+
+type batchJobModel struct {
+	grove.BaseModel  `grove:"table:nexus_batch_jobs"`
+	ID               string     `grove:"id,pk"`
+	TenantID         string     `grove:"tenant_id"`
+	KeyID            string     `grove:"key_id"`
+	Status           string     `grove:"status,notnull"`
+	TotalItems       int        `grove:"total_items"`
+	Completed        int        `grove:"completed"`
+	Failed           int        `grove:"failed"`
+	Endpoint         string     `grove:"endpoint,notnull"`
+	InputFileID      string     `grove:"input_file_id"`
+	OutputFileID     string     `grove:"output_file_id"`
+	ErrorFileID      string     `grove:"error_file_id"`
+	CompletionWindow string     `grove:"completion_window"`
+	Provider         string     `grove:"provider"`
+	ProviderBatchID  string     `grove:"provider_batch_id"`
+	Errors           string     `grove:"errors,type:jsonb"`
+	Metadata         string     `grove:"metadata,type:jsonb"`
+	CreatedAt        time.Time  `grove:"created_at,notnull,default:current_timestamp"`
+	StartedAt        *time.Time `grove:"started_at"`
+	CompletedAt      *time.Time `grove:"completed_at"`
+}
+
+func batchJobToModel(j *batch.Job) *batchJobModel {
+	return &batchJobModel{
+		ID:               j.ID,
+		TenantID:         j.TenantID,
+		KeyID:            j.KeyID,
+		Status:           string(j.Status),
+		TotalItems:       j.TotalItems,
+		Completed:        j.Completed,
+		Failed:           j.Failed,
+		Endpoint:         j.Endpoint,
+		InputFileID:      j.InputFileID,
+		OutputFileID:     j.OutputFileID,
+		ErrorFileID:      j.ErrorFileID,
+		CompletionWindow: j.CompletionWindow,
+		Provider:         j.Provider,
+		ProviderBatchID:  j.ProviderBatchID,
+		Errors:           mustJSON(j.Errors),
+		Metadata:         mustJSON(j.Metadata),
+		CreatedAt:        j.CreatedAt,
+		StartedAt:        j.StartedAt,
+		CompletedAt:      j.CompletedAt,
+	}
+}
+
+func batchJobFromModel(m *batchJobModel) (*batch.Job, error) {
+	j := &batch.Job{
+		ID:               m.ID,
+		TenantID:         m.TenantID,
+		KeyID:            m.KeyID,
+		Status:           batch.JobStatus(m.Status),
+		TotalItems:       m.TotalItems,
+		Completed:        m.Completed,
+		Failed:           m.Failed,
+		Endpoint:         m.Endpoint,
+		InputFileID:      m.InputFileID,
+		OutputFileID:     m.OutputFileID,
+		ErrorFileID:      m.ErrorFileID,
+		CompletionWindow: m.CompletionWindow,
+		Provider:         m.Provider,
+		ProviderBatchID:  m.ProviderBatchID,
+		CreatedAt:        m.CreatedAt,
+		StartedAt:        m.StartedAt,
+		CompletedAt:      m.CompletedAt,
+	}
+	if m.Errors != "" && m.Errors != "null" {
+		if err := json.Unmarshal([]byte(m.Errors), &j.Errors); err != nil {
+			return nil, fmt.Errorf("nexus: unmarshal batch errors: %w", err)
+		}
+	}
+	if m.Metadata != "" && m.Metadata != "null" {
+		if err := json.Unmarshal([]byte(m.Metadata), &j.Metadata); err != nil {
+			return nil, fmt.Errorf("nexus: unmarshal batch metadata: %w", err)
+		}
+	}
+	return j, nil
+}
+
+type batchFileModel struct {
+	grove.BaseModel `grove:"table:nexus_batch_files"`
+	ID              string    `grove:"id,pk"`
+	TenantID        string    `grove:"tenant_id"`
+	Filename        string    `grove:"filename"`
+	Purpose         string    `grove:"purpose,notnull"`
+	Bytes           int       `grove:"bytes"`
+	Content         []byte    `grove:"content"`
+	CreatedAt       time.Time `grove:"created_at,notnull,default:current_timestamp"`
+}
+
+func batchFileToModel(f *batch.File) *batchFileModel {
+	return &batchFileModel{
+		ID:        f.ID,
+		TenantID:  f.TenantID,
+		Filename:  f.Filename,
+		Purpose:   f.Purpose,
+		Bytes:     f.Bytes,
+		Content:   f.Content,
+		CreatedAt: f.CreatedAt,
+	}
+}
+
+func batchFileFromModel(m *batchFileModel) *batch.File {
+	return &batch.File{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		Filename:  m.Filename,
+		Purpose:   m.Purpose,
+		Bytes:     m.Bytes,
+		Content:   m.Content,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// ──────────────────────────────────────────────────
+// Background response models
+// ──────────────────────────────────────────────────
+
+type backgroundResponseModel struct {
+	grove.BaseModel `grove:"table:nexus_background_responses"`
+	ID              string     `grove:"id,pk"`
+	TenantID        string     `grove:"tenant_id"`
+	KeyID           string     `grove:"key_id"`
+	Status          string     `grove:"status,notnull"`
+	Model           string     `grove:"model"`
+	Endpoint        string     `grove:"endpoint"`
+	Request         string     `grove:"request,type:jsonb"`
+	Result          string     `grove:"result,type:jsonb"`
+	Error           string     `grove:"error,type:jsonb"`
+	Frames          int        `grove:"frames"`
+	Metadata        string     `grove:"metadata,type:jsonb"`
+	CreatedAt       time.Time  `grove:"created_at,notnull,default:current_timestamp"`
+	StartedAt       *time.Time `grove:"started_at"`
+	CompletedAt     *time.Time `grove:"completed_at"`
+}
+
+func backgroundResponseToModel(r *background.Response) *backgroundResponseModel {
+	return &backgroundResponseModel{
+		ID:          r.ID,
+		TenantID:    r.TenantID,
+		KeyID:       r.KeyID,
+		Status:      string(r.Status),
+		Model:       r.Model,
+		Endpoint:    r.Endpoint,
+		Request:     mustJSON(r.Request),
+		Result:      mustJSON(r.Result),
+		Error:       mustJSON(r.Error),
+		Frames:      r.Frames,
+		Metadata:    mustJSON(r.Metadata),
+		CreatedAt:   r.CreatedAt,
+		StartedAt:   r.StartedAt,
+		CompletedAt: r.CompletedAt,
+	}
+}
+
+func backgroundResponseFromModel(m *backgroundResponseModel) (*background.Response, error) {
+	r := &background.Response{
+		ID:          m.ID,
+		TenantID:    m.TenantID,
+		KeyID:       m.KeyID,
+		Status:      background.Status(m.Status),
+		Model:       m.Model,
+		Endpoint:    m.Endpoint,
+		Frames:      m.Frames,
+		CreatedAt:   m.CreatedAt,
+		StartedAt:   m.StartedAt,
+		CompletedAt: m.CompletedAt,
+	}
+	if m.Request != "" && m.Request != "null" {
+		if err := json.Unmarshal([]byte(m.Request), &r.Request); err != nil {
+			return nil, fmt.Errorf("nexus: unmarshal background request: %w", err)
+		}
+	}
+	if m.Result != "" && m.Result != "null" {
+		if err := json.Unmarshal([]byte(m.Result), &r.Result); err != nil {
+			return nil, fmt.Errorf("nexus: unmarshal background result: %w", err)
+		}
+	}
+	if m.Error != "" && m.Error != "null" {
+		if err := json.Unmarshal([]byte(m.Error), &r.Error); err != nil {
+			return nil, fmt.Errorf("nexus: unmarshal background error: %w", err)
+		}
+	}
+	if m.Metadata != "" && m.Metadata != "null" {
+		if err := json.Unmarshal([]byte(m.Metadata), &r.Metadata); err != nil {
+			return nil, fmt.Errorf("nexus: unmarshal background metadata: %w", err)
+		}
+	}
+	return r, nil
+}
+
+type backgroundFrameModel struct {
+	grove.BaseModel `grove:"table:nexus_background_frames"`
+	ResponseID      string `grove:"response_id,pk"`
+	Seq             int    `grove:"seq,pk"`
+	Chunk           string `grove:"chunk,type:jsonb"`
+}
+
+func backgroundFrameToModel(f *background.Frame) *backgroundFrameModel {
+	return &backgroundFrameModel{
+		ResponseID: f.ResponseID,
+		Seq:        f.Seq,
+		Chunk:      mustJSON(f.Chunk),
+	}
+}
+
+func backgroundFrameFromModel(m *backgroundFrameModel) (background.Frame, error) {
+	f := background.Frame{ResponseID: m.ResponseID, Seq: m.Seq}
+	if m.Chunk != "" && m.Chunk != "null" {
+		if err := json.Unmarshal([]byte(m.Chunk), &f.Chunk); err != nil {
+			return f, fmt.Errorf("nexus: unmarshal background frame: %w", err)
+		}
+	}
+	return f, nil
+}
+
 // ──────────────────────────────────────────────────
 // JSON helper
 // ──────────────────────────────────────────────────
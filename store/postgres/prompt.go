@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/xraph/grove/drivers/pgdriver"
+
+	"github.com/xraph/nexus/prompt"
+)
+
+type promptStore struct {
+	pgdb *pgdriver.PgDB
+}
+
+func (s *promptStore) InsertVersion(ctx context.Context, t *prompt.Template) error {
+	m := promptTemplateToModel(t)
+	_, err := s.pgdb.NewInsert(m).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/postgres: insert prompt version: %w", err)
+	}
+	return nil
+}
+
+func (s *promptStore) FindVersion(ctx context.Context, tenantID, name string, version int) (*prompt.Template, error) {
+	m := new(promptTemplateModel)
+	q := s.pgdb.NewSelect(m).
+		Where("tenant_id = ?", tenantID).
+		Where("name = ?", name)
+	if version > 0 {
+		q = q.Where("version = ?", version)
+	} else {
+		q = q.OrderExpr("version DESC").Limit(1)
+	}
+	if err := q.Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/postgres: find prompt version: %w", err)
+	}
+	return promptTemplateFromModel(m)
+}
+
+func (s *promptStore) ListVersions(ctx context.Context, tenantID, name string) ([]*prompt.Template, error) {
+	var models []promptTemplateModel
+	err := s.pgdb.NewSelect(&models).
+		Where("tenant_id = ?", tenantID).
+		Where("name = ?", name).
+		OrderExpr("version DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/postgres: list prompt versions: %w", err)
+	}
+	return promptTemplatesFromModels(models)
+}
+
+func (s *promptStore) ListLatest(ctx context.Context, tenantID string) ([]*prompt.Template, error) {
+	var models []promptTemplateModel
+	err := s.pgdb.NewSelect(&models).
+		Where("tenant_id = ?", tenantID).
+		Where("version = (SELECT MAX(p.version) FROM nexus_prompt_templates p WHERE p.tenant_id = nexus_prompt_templates.tenant_id AND p.name = nexus_prompt_templates.name)").
+		OrderExpr("name ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/postgres: list prompts: %w", err)
+	}
+	return promptTemplatesFromModels(models)
+}
+
+func (s *promptStore) SetLabel(ctx context.Context, l *prompt.Label) error {
+	_, err := s.pgdb.Exec(ctx, `INSERT INTO nexus_prompt_labels (tenant_id, name, label, version, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (tenant_id, name, label) DO UPDATE SET version = EXCLUDED.version, updated_at = EXCLUDED.updated_at`,
+		l.TenantID, l.Name, l.Label, l.Version, l.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("nexus/postgres: set prompt label: %w", err)
+	}
+	return nil
+}
+
+func (s *promptStore) FindLabel(ctx context.Context, tenantID, name, label string) (*prompt.Label, error) {
+	m := new(promptLabelModel)
+	err := s.pgdb.NewSelect(m).
+		Where("tenant_id = ?", tenantID).
+		Where("name = ?", name).
+		Where("label = ?", label).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/postgres: find prompt label: %w", err)
+	}
+	return promptLabelFromModel(m), nil
+}
+
+func (s *promptStore) ListLabels(ctx context.Context, tenantID, name string) ([]*prompt.Label, error) {
+	var models []promptLabelModel
+	err := s.pgdb.NewSelect(&models).
+		Where("tenant_id = ?", tenantID).
+		Where("name = ?", name).
+		OrderExpr("label ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/postgres: list prompt labels: %w", err)
+	}
+	labels := make([]*prompt.Label, 0, len(models))
+	for i := range models {
+		labels = append(labels, promptLabelFromModel(&models[i]))
+	}
+	return labels, nil
+}
+
+func promptTemplatesFromModels(models []promptTemplateModel) ([]*prompt.Template, error) {
+	templates := make([]*prompt.Template, 0, len(models))
+	for i := range models {
+		t, err := promptTemplateFromModel(&models[i])
+		if err != nil {
+			return nil, fmt.Errorf("nexus/postgres: convert prompt model: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
@@ -12,7 +12,10 @@ import (
 	"github.com/xraph/grove/drivers/pgdriver"
 	"github.com/xraph/grove/migrate"
 
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/batch"
 	"github.com/xraph/nexus/key"
+	"github.com/xraph/nexus/prompt"
 	"github.com/xraph/nexus/store"
 	"github.com/xraph/nexus/tenant"
 	"github.com/xraph/nexus/usage"
@@ -35,9 +38,12 @@ func New(db *grove.DB) *Store {
 	}
 }
 
-func (s *Store) Tenants() tenant.Store { return &tenantStore{pgdb: s.pgdb} }
-func (s *Store) Keys() key.Store       { return &keyStore{pgdb: s.pgdb} }
-func (s *Store) Usage() usage.Store    { return &usageStore{pgdb: s.pgdb} }
+func (s *Store) Tenants() tenant.Store        { return &tenantStore{pgdb: s.pgdb} }
+func (s *Store) Keys() key.Store              { return &keyStore{pgdb: s.pgdb} }
+func (s *Store) Usage() usage.Store           { return &usageStore{pgdb: s.pgdb} }
+func (s *Store) Prompts() prompt.Store        { return &promptStore{pgdb: s.pgdb} }
+func (s *Store) Batches() batch.Store         { return &batchStore{pgdb: s.pgdb} }
+func (s *Store) Background() background.Store { return &backgroundStore{pgdb: s.pgdb} }
 
 // Migrate runs programmatic migrations via the grove orchestrator.
 func (s *Store) Migrate() error {
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xraph/grove/drivers/pgdriver"
+	"github.com/xraph/grove/migrate"
+
+	"github.com/xraph/nexus/rag"
+)
+
+// VectorIndex returns a rag.Index persisted in nexus_rag_chunks and searched
+// with pgvector's cosine distance. Run MigrateVectorIndex first.
+func (s *Store) VectorIndex() rag.Index { return &vectorIndex{pgdb: s.pgdb} }
+
+// MigrateVectorIndex creates the pgvector extension and the RAG chunk table.
+func (s *Store) MigrateVectorIndex(ctx context.Context) error {
+	orch := migrate.NewOrchestrator(&pgMigrateExecutor{pgdb: s.pgdb}, VectorMigrations)
+	if _, err := orch.Migrate(ctx); err != nil {
+		return fmt.Errorf("nexus/postgres: vector index migration failed: %w", err)
+	}
+	return nil
+}
+
+type vectorIndex struct {
+	pgdb *pgdriver.PgDB
+}
+
+func (v *vectorIndex) Upsert(ctx context.Context, chunks []rag.Chunk) error {
+	for _, c := range chunks {
+		_, err := v.pgdb.Exec(ctx, `INSERT INTO nexus_rag_chunks (id, document_id, tenant_id, content, source, title, metadata, embedding)
+VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8::vector)
+ON CONFLICT (tenant_id, id) DO UPDATE SET document_id = EXCLUDED.document_id,
+    content = EXCLUDED.content, source = EXCLUDED.source, title = EXCLUDED.title,
+    metadata = EXCLUDED.metadata, embedding = EXCLUDED.embedding`,
+			c.ID, c.DocumentID, c.TenantID, c.Content, c.Source, c.Title, metadataJSON(c.Metadata), vectorLiteral(c.Embedding))
+		if err != nil {
+			return fmt.Errorf("nexus/postgres: upsert rag chunk: %w", err)
+		}
+	}
+	return nil
+}
+
+func (v *vectorIndex) Search(ctx context.Context, tenantID string, embedding []float64, k int) ([]rag.Match, error) {
+	// Rows embedded with a different model (other dimensions) cannot be
+	// compared and are skipped.
+	rows, err := v.pgdb.Query(ctx, `SELECT id, document_id, tenant_id, content, source, title, metadata, 1 - (embedding <=> $2::vector)
+FROM nexus_rag_chunks
+WHERE (tenant_id = $1 OR tenant_id = '') AND vector_dims(embedding) = $3
+ORDER BY embedding <=> $2::vector
+LIMIT $4`, tenantID, vectorLiteral(embedding), len(embedding), k)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/postgres: search rag chunks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var matches []rag.Match
+	for rows.Next() {
+		var m rag.Match
+		var metadata []byte
+		c := &m.Chunk
+		if err := rows.Scan(&c.ID, &c.DocumentID, &c.TenantID, &c.Content, &c.Source, &c.Title, &metadata, &m.Score); err != nil {
+			return nil, fmt.Errorf("nexus/postgres: scan rag chunk: %w", err)
+		}
+		if len(metadata) > 0 {
+			if err := json.Unmarshal(metadata, &c.Metadata); err != nil {
+				return nil, fmt.Errorf("nexus/postgres: decode rag metadata: %w", err)
+			}
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("nexus/postgres: search rag chunks: %w", err)
+	}
+	return matches, nil
+}
+
+func (v *vectorIndex) DeleteDocument(ctx context.Context, tenantID, documentID string) error {
+	_, err := v.pgdb.Exec(ctx, `DELETE FROM nexus_rag_chunks WHERE tenant_id = $1 AND document_id = $2`, tenantID, documentID)
+	if err != nil {
+		return fmt.Errorf("nexus/postgres: delete rag document: %w", err)
+	}
+	return nil
+}
+
+// vectorLiteral formats an embedding in pgvector's text input format.
+func vectorLiteral(v []float64) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, f := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(f, 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+func metadataJSON(m map[string]string) string {
+	if m == nil {
+		return "{}"
+	}
+	return mustJSON(m)
+}
@@ -0,0 +1,98 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/xraph/grove"
+	"github.com/xraph/grove/drivers/pgdriver"
+
+	"github.com/xraph/nexus/id"
+	"github.com/xraph/nexus/rag"
+	"github.com/xraph/nexus/store/postgres"
+)
+
+// newVectorIndex connects to the database in NEXUS_TEST_POSTGRES_DSN, which
+// must have the pgvector extension available.
+func newVectorIndex(t *testing.T) rag.Index {
+	t.Helper()
+	dsn := os.Getenv("NEXUS_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("NEXUS_TEST_POSTGRES_DSN not set")
+	}
+	ctx := context.Background()
+	drv := pgdriver.New()
+	if err := drv.Open(ctx, dsn); err != nil {
+		t.Fatal(err)
+	}
+	db, err := grove.Open(drv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := postgres.New(db)
+	t.Cleanup(func() { _ = s.Close() })
+	if err := s.MigrateVectorIndex(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return s.VectorIndex()
+}
+
+func TestVectorIndex_UpsertSearchDelete(t *testing.T) {
+	idx := newVectorIndex(t)
+	ctx := context.Background()
+	// Fresh tenants keep runs against a shared database apart.
+	a, b := id.NewDocumentID().String(), id.NewDocumentID().String()
+	t.Cleanup(func() {
+		_ = idx.DeleteDocument(ctx, a, "doc")
+		_ = idx.DeleteDocument(ctx, b, "doc")
+	})
+
+	if err := idx.Upsert(ctx, []rag.Chunk{
+		{ID: "doc#0", DocumentID: "doc", TenantID: a, Content: "near", Metadata: map[string]string{"lang": "en"}, Embedding: []float64{1, 0.1}},
+		{ID: "doc#1", DocumentID: "doc", TenantID: a, Content: "far", Embedding: []float64{0, 1}},
+		{ID: "doc#0", DocumentID: "doc", TenantID: b, Content: "other tenant", Embedding: []float64{1, 0}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := idx.Search(ctx, a, []float64{1, 0}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var own []rag.Match
+	for _, m := range matches {
+		if m.Chunk.TenantID == b {
+			t.Fatalf("tenant %s saw tenant %s's chunk: %+v", a, b, m.Chunk)
+		}
+		if m.Chunk.TenantID == a {
+			own = append(own, m)
+		}
+	}
+	if len(own) != 2 || own[0].Chunk.Content != "near" || own[0].Chunk.Metadata["lang"] != "en" {
+		t.Fatalf("matches = %+v, want near then far", own)
+	}
+
+	// Upserting an existing chunk replaces it.
+	if err := idx.Upsert(ctx, []rag.Chunk{{ID: "doc#1", DocumentID: "doc", TenantID: a, Content: "moved", Embedding: []float64{1, 0}}}); err != nil {
+		t.Fatal(err)
+	}
+	matches, err = idx.Search(ctx, a, []float64{1, 0}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Chunk.Content != "moved" {
+		t.Fatalf("matches = %+v, want the replaced chunk", matches)
+	}
+
+	if err := idx.DeleteDocument(ctx, a, "doc"); err != nil {
+		t.Fatal(err)
+	}
+	matches, err = idx.Search(ctx, b, []float64{1, 0}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) == 0 || matches[0].Chunk.Content != "other tenant" {
+		t.Fatalf("tenant %s lost its chunk when %s deleted the same document ID: %+v", b, a, matches)
+	}
+}
@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/xraph/grove/drivers/sqlitedriver"
+
+	"github.com/xraph/nexus/batch"
+)
+
+type batchStore struct {
+	sdb *sqlitedriver.SqliteDB
+}
+
+func (s *batchStore) InsertJob(ctx context.Context, j *batch.Job) error {
+	_, err := s.sdb.NewInsert(batchJobToModel(j)).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/sqlite: insert batch: %w", err)
+	}
+	return nil
+}
+
+func (s *batchStore) UpdateJob(ctx context.Context, j *batch.Job) error {
+	_, err := s.sdb.NewUpdate(batchJobToModel(j)).WherePK().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/sqlite: update batch: %w", err)
+	}
+	return nil
+}
+
+func (s *batchStore) FindJob(ctx context.Context, jobID string) (*batch.Job, error) {
+	m := new(batchJobModel)
+	if err := s.sdb.NewSelect(m).Where("id = ?", jobID).Scan(ctx); err != nil {
+		if isNoRows(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/sqlite: find batch: %w", err)
+	}
+	return batchJobFromModel(m)
+}
+
+func (s *batchStore) ListJobs(ctx context.Context, tenantID string) ([]*batch.Job, error) {
+	var models []batchJobModel
+	q := s.sdb.NewSelect(&models)
+	if tenantID != "" {
+		q = q.Where("tenant_id = ?", tenantID)
+	}
+	if err := q.OrderExpr("created_at DESC").Scan(ctx); err != nil {
+		return nil, fmt.Errorf("nexus/sqlite: list batches: %w", err)
+	}
+	return batchJobsFromModels(models)
+}
+
+func (s *batchStore) ListJobsByStatus(ctx context.Context, statuses ...batch.JobStatus) ([]*batch.Job, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	args := make([]any, len(statuses))
+	for i, status := range statuses {
+		args[i] = string(status)
+	}
+	var models []batchJobModel
+	err := s.sdb.NewSelect(&models).
+		Where("status IN ("+strings.Repeat("?, ", len(args)-1)+"?)", args...).
+		OrderExpr("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/sqlite: list batches by status: %w", err)
+	}
+	return batchJobsFromModels(models)
+}
+
+func (s *batchStore) InsertFile(ctx context.Context, f *batch.File) error {
+	_, err := s.sdb.NewInsert(batchFileToModel(f)).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/sqlite: insert file: %w", err)
+	}
+	return nil
+}
+
+func (s *batchStore) FindFile(ctx context.Context, fileID string) (*batch.File, error) {
+	m := new(batchFileModel)
+	if err := s.sdb.NewSelect(m).Where("id = ?", fileID).Scan(ctx); err != nil {
+		if isNoRows(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/sqlite: find file: %w", err)
+	}
+	return batchFileFromModel(m), nil
+}
+
+func (s *batchStore) ListFiles(ctx context.Context, tenantID, purpose string) ([]*batch.File, error) {
+	var models []batchFileModel
+	q := s.sdb.NewSelect(&models).Column("id", "tenant_id", "filename", "purpose", "bytes", "created_at")
+	if tenantID != "" {
+		q = q.Where("tenant_id = ?", tenantID)
+	}
+	if purpose != "" {
+		q = q.Where("purpose = ?", purpose)
+	}
+	if err := q.OrderExpr("created_at DESC").Scan(ctx); err != nil {
+		return nil, fmt.Errorf("nexus/sqlite: list files: %w", err)
+	}
+	files := make([]*batch.File, 0, len(models))
+	for i := range models {
+		files = append(files, batchFileFromModel(&models[i]))
+	}
+	return files, nil
+}
+
+func (s *batchStore) DeleteFile(ctx context.Context, fileID string) error {
+	_, err := s.sdb.NewDelete((*batchFileModel)(nil)).Where("id = ?", fileID).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/sqlite: delete file: %w", err)
+	}
+	return nil
+}
+
+func batchJobsFromModels(models []batchJobModel) ([]*batch.Job, error) {
+	jobs := make([]*batch.Job, 0, len(models))
+	for i := range models {
+		j, err := batchJobFromModel(&models[i])
+		if err != nil {
+			return nil, fmt.Errorf("nexus/sqlite: convert batch model: %w", err)
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
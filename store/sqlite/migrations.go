@@ -100,6 +100,262 @@ CREATE INDEX IF NOT EXISTS idx_usage_created ON usage_records(created_at);
 				return err
 			},
 		},
+		&migrate.Migration{
+			Name:    "create_rag_chunks",
+			Version: "20240101000004",
+			Comment: "Create rag_chunks table",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS rag_chunks (
+    id          TEXT PRIMARY KEY,
+    document_id TEXT NOT NULL DEFAULT '',
+    tenant_id   TEXT NOT NULL DEFAULT '',
+    content     TEXT NOT NULL DEFAULT '',
+    source      TEXT NOT NULL DEFAULT '',
+    title       TEXT NOT NULL DEFAULT '',
+    metadata    TEXT NOT NULL DEFAULT '{}',
+    embedding   TEXT NOT NULL DEFAULT '[]',
+    created_at  TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_rag_chunks_tenant ON rag_chunks(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_rag_chunks_document ON rag_chunks(document_id);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `DROP TABLE IF EXISTS rag_chunks`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_prompts",
+			Version: "20240101000005",
+			Comment: "Create prompt template tables and record prompt versions on usage",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    tenant_id   TEXT NOT NULL DEFAULT '',
+    name        TEXT NOT NULL,
+    version     INTEGER NOT NULL,
+    content     TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    metadata    TEXT NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, name, version)
+);
+
+CREATE TABLE IF NOT EXISTS prompt_labels (
+    tenant_id  TEXT NOT NULL DEFAULT '',
+    name       TEXT NOT NULL,
+    label      TEXT NOT NULL,
+    version    INTEGER NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, name, label)
+);
+
+ALTER TABLE usage_records ADD COLUMN prompt_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE usage_records ADD COLUMN prompt_version INTEGER NOT NULL DEFAULT 0;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP TABLE IF EXISTS prompt_labels;
+DROP TABLE IF EXISTS prompt_templates;
+ALTER TABLE usage_records DROP COLUMN prompt_version;
+ALTER TABLE usage_records DROP COLUMN prompt_id;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_audio_usage",
+			Version: "20240101000006",
+			Comment: "Record audio seconds and synthesized characters on usage",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE usage_records ADD COLUMN audio_seconds REAL NOT NULL DEFAULT 0;
+ALTER TABLE usage_records ADD COLUMN characters INTEGER NOT NULL DEFAULT 0;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE usage_records DROP COLUMN characters;
+ALTER TABLE usage_records DROP COLUMN audio_seconds;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_batches",
+			Version: "20240101000007",
+			Comment: "Create batch job and file tables and record batch IDs on usage",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS batch_files (
+    id         TEXT PRIMARY KEY,
+    tenant_id  TEXT NOT NULL DEFAULT '',
+    filename   TEXT NOT NULL DEFAULT '',
+    purpose    TEXT NOT NULL,
+    bytes      INTEGER NOT NULL DEFAULT 0,
+    content    BLOB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_files_tenant ON batch_files(tenant_id, created_at);
+
+CREATE TABLE IF NOT EXISTS batch_jobs (
+    id                TEXT PRIMARY KEY,
+    tenant_id         TEXT NOT NULL DEFAULT '',
+    key_id            TEXT NOT NULL DEFAULT '',
+    status            TEXT NOT NULL,
+    total_items       INTEGER NOT NULL DEFAULT 0,
+    completed         INTEGER NOT NULL DEFAULT 0,
+    failed            INTEGER NOT NULL DEFAULT 0,
+    endpoint          TEXT NOT NULL,
+    input_file_id     TEXT NOT NULL DEFAULT '',
+    output_file_id    TEXT NOT NULL DEFAULT '',
+    error_file_id     TEXT NOT NULL DEFAULT '',
+    completion_window TEXT NOT NULL DEFAULT '',
+    provider          TEXT NOT NULL DEFAULT '',
+    provider_batch_id TEXT NOT NULL DEFAULT '',
+    errors            TEXT NOT NULL DEFAULT '[]',
+    metadata          TEXT NOT NULL DEFAULT '{}',
+    created_at        TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at        TIMESTAMP,
+    completed_at      TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_jobs_tenant ON batch_jobs(tenant_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_batch_jobs_status ON batch_jobs(status);
+
+ALTER TABLE usage_records ADD COLUMN batch_id TEXT NOT NULL DEFAULT '';
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP TABLE IF EXISTS batch_jobs;
+DROP TABLE IF EXISTS batch_files;
+ALTER TABLE usage_records DROP COLUMN batch_id;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_usage_provider_type",
+			Version: "20240101000008",
+			Comment: "Record the provider type alongside the instance name on usage",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE usage_records ADD COLUMN provider_type TEXT NOT NULL DEFAULT '';
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE usage_records DROP COLUMN provider_type;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_background_responses",
+			Version: "20240101000009",
+			Comment: "Create background response and stream frame tables",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS background_responses (
+    id           TEXT PRIMARY KEY,
+    tenant_id    TEXT NOT NULL DEFAULT '',
+    key_id       TEXT NOT NULL DEFAULT '',
+    status       TEXT NOT NULL,
+    model        TEXT NOT NULL DEFAULT '',
+    endpoint     TEXT NOT NULL DEFAULT '',
+    request      TEXT NOT NULL DEFAULT 'null',
+    result       TEXT NOT NULL DEFAULT 'null',
+    error        TEXT NOT NULL DEFAULT 'null',
+    frames       INTEGER NOT NULL DEFAULT 0,
+    metadata     TEXT NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at   TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_background_responses_status ON background_responses(status);
+CREATE INDEX IF NOT EXISTS idx_background_responses_completed_at ON background_responses(completed_at);
+
+CREATE TABLE IF NOT EXISTS background_frames (
+    response_id TEXT NOT NULL,
+    seq         INTEGER NOT NULL,
+    chunk       TEXT NOT NULL,
+    PRIMARY KEY (response_id, seq)
+);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP TABLE IF EXISTS background_frames;
+DROP TABLE IF EXISTS background_responses;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "key_rag_chunks_by_tenant",
+			Version: "20240101000010",
+			Comment: "Key rag_chunks by tenant and chunk ID so tenants sharing a document ID stay separate",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE rag_chunks_keyed (
+    id          TEXT NOT NULL,
+    document_id TEXT NOT NULL DEFAULT '',
+    tenant_id   TEXT NOT NULL DEFAULT '',
+    content     TEXT NOT NULL DEFAULT '',
+    source      TEXT NOT NULL DEFAULT '',
+    title       TEXT NOT NULL DEFAULT '',
+    metadata    TEXT NOT NULL DEFAULT '{}',
+    embedding   TEXT NOT NULL DEFAULT '[]',
+    created_at  TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (tenant_id, id)
+);
+
+INSERT INTO rag_chunks_keyed SELECT id, document_id, tenant_id, content, source, title, metadata, embedding, created_at FROM rag_chunks;
+DROP TABLE rag_chunks;
+ALTER TABLE rag_chunks_keyed RENAME TO rag_chunks;
+
+CREATE INDEX IF NOT EXISTS idx_rag_chunks_tenant ON rag_chunks(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_rag_chunks_document ON rag_chunks(tenant_id, document_id);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE rag_chunks_unkeyed (
+    id          TEXT PRIMARY KEY,
+    document_id TEXT NOT NULL DEFAULT '',
+    tenant_id   TEXT NOT NULL DEFAULT '',
+    content     TEXT NOT NULL DEFAULT '',
+    source      TEXT NOT NULL DEFAULT '',
+    title       TEXT NOT NULL DEFAULT '',
+    metadata    TEXT NOT NULL DEFAULT '{}',
+    embedding   TEXT NOT NULL DEFAULT '[]',
+    created_at  TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+INSERT OR IGNORE INTO rag_chunks_unkeyed SELECT id, document_id, tenant_id, content, source, title, metadata, embedding, created_at FROM rag_chunks;
+DROP TABLE rag_chunks;
+ALTER TABLE rag_chunks_unkeyed RENAME TO rag_chunks;
+
+CREATE INDEX IF NOT EXISTS idx_rag_chunks_tenant ON rag_chunks(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_rag_chunks_document ON rag_chunks(document_id);
+`)
+				return err
+			},
+		},
 	)
 	return g
 }()
@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xraph/grove/drivers/sqlitedriver"
+
+	"github.com/xraph/nexus/prompt"
+)
+
+type promptStore struct {
+	sdb *sqlitedriver.SqliteDB
+}
+
+func (s *promptStore) InsertVersion(ctx context.Context, t *prompt.Template) error {
+	m := promptTemplateToModel(t)
+	_, err := s.sdb.NewInsert(m).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("nexus/sqlite: insert prompt version: %w", err)
+	}
+	return nil
+}
+
+func (s *promptStore) FindVersion(ctx context.Context, tenantID, name string, version int) (*prompt.Template, error) {
+	m := new(promptTemplateModel)
+	q := s.sdb.NewSelect(m).
+		Where("tenant_id = ?", tenantID).
+		Where("name = ?", name)
+	if version > 0 {
+		q = q.Where("version = ?", version)
+	} else {
+		q = q.OrderExpr("version DESC").Limit(1)
+	}
+	if err := q.Scan(ctx); err != nil {
+		if isNoRows(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/sqlite: find prompt version: %w", err)
+	}
+	return promptTemplateFromModel(m)
+}
+
+func (s *promptStore) ListVersions(ctx context.Context, tenantID, name string) ([]*prompt.Template, error) {
+	var models []promptTemplateModel
+	err := s.sdb.NewSelect(&models).
+		Where("tenant_id = ?", tenantID).
+		Where("name = ?", name).
+		OrderExpr("version DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/sqlite: list prompt versions: %w", err)
+	}
+	return promptTemplatesFromModels(models)
+}
+
+func (s *promptStore) ListLatest(ctx context.Context, tenantID string) ([]*prompt.Template, error) {
+	var models []promptTemplateModel
+	err := s.sdb.NewSelect(&models).
+		Where("tenant_id = ?", tenantID).
+		Where("version = (SELECT MAX(p.version) FROM prompt_templates p WHERE p.tenant_id = prompt_templates.tenant_id AND p.name = prompt_templates.name)").
+		OrderExpr("name ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/sqlite: list prompts: %w", err)
+	}
+	return promptTemplatesFromModels(models)
+}
+
+func (s *promptStore) SetLabel(ctx context.Context, l *prompt.Label) error {
+	_, err := s.sdb.Exec(ctx, `INSERT INTO prompt_labels (tenant_id, name, label, version, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (tenant_id, name, label) DO UPDATE SET version = excluded.version, updated_at = excluded.updated_at`,
+		l.TenantID, l.Name, l.Label, l.Version, l.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("nexus/sqlite: set prompt label: %w", err)
+	}
+	return nil
+}
+
+func (s *promptStore) FindLabel(ctx context.Context, tenantID, name, label string) (*prompt.Label, error) {
+	m := new(promptLabelModel)
+	err := s.sdb.NewSelect(m).
+		Where("tenant_id = ?", tenantID).
+		Where("name = ?", name).
+		Where("label = ?", label).
+		Scan(ctx)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("nexus/sqlite: find prompt label: %w", err)
+	}
+	return promptLabelFromModel(m), nil
+}
+
+func (s *promptStore) ListLabels(ctx context.Context, tenantID, name string) ([]*prompt.Label, error) {
+	var models []promptLabelModel
+	err := s.sdb.NewSelect(&models).
+		Where("tenant_id = ?", tenantID).
+		Where("name = ?", name).
+		OrderExpr("label ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/sqlite: list prompt labels: %w", err)
+	}
+	labels := make([]*prompt.Label, 0, len(models))
+	for i := range models {
+		labels = append(labels, promptLabelFromModel(&models[i]))
+	}
+	return labels, nil
+}
+
+func promptTemplatesFromModels(models []promptTemplateModel) ([]*prompt.Template, error) {
+	templates := make([]*prompt.Template, 0, len(models))
+	for i := range models {
+		t, err := promptTemplateFromModel(&models[i])
+		if err != nil {
+			return nil, fmt.Errorf("nexus/sqlite: convert prompt model: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
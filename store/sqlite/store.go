@@ -12,7 +12,10 @@ import (
 	"github.com/xraph/grove/drivers/sqlitedriver"
 	"github.com/xraph/grove/migrate"
 
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/batch"
 	"github.com/xraph/nexus/key"
+	"github.com/xraph/nexus/prompt"
 	"github.com/xraph/nexus/store"
 	"github.com/xraph/nexus/tenant"
 	"github.com/xraph/nexus/usage"
@@ -35,9 +38,12 @@ func New(db *grove.DB) *Store {
 	}
 }
 
-func (s *Store) Tenants() tenant.Store { return &tenantStore{sdb: s.sdb} }
-func (s *Store) Keys() key.Store       { return &keyStore{sdb: s.sdb} }
-func (s *Store) Usage() usage.Store    { return &usageStore{sdb: s.sdb} }
+func (s *Store) Tenants() tenant.Store        { return &tenantStore{sdb: s.sdb} }
+func (s *Store) Keys() key.Store              { return &keyStore{sdb: s.sdb} }
+func (s *Store) Usage() usage.Store           { return &usageStore{sdb: s.sdb} }
+func (s *Store) Prompts() prompt.Store        { return &promptStore{sdb: s.sdb} }
+func (s *Store) Batches() batch.Store         { return &batchStore{sdb: s.sdb} }
+func (s *Store) Background() background.Store { return &backgroundStore{sdb: s.sdb} }
 
 // Migrate runs programmatic migrations via the grove orchestrator.
 func (s *Store) Migrate() error {
@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/xraph/grove/drivers/sqlitedriver"
+
+	"github.com/xraph/nexus/rag"
+)
+
+// VectorIndex returns a rag.Index persisted in the rag_chunks table.
+// Similarity is computed in process over the tenant's chunks, which suits
+// small and medium corpora.
+func (s *Store) VectorIndex() rag.Index { return &vectorIndex{sdb: s.sdb} }
+
+type vectorIndex struct {
+	sdb *sqlitedriver.SqliteDB
+}
+
+func (v *vectorIndex) Upsert(ctx context.Context, chunks []rag.Chunk) error {
+	for _, c := range chunks {
+		_, err := v.sdb.Exec(ctx, `INSERT INTO rag_chunks (id, document_id, tenant_id, content, source, title, metadata, embedding)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (tenant_id, id) DO UPDATE SET document_id = excluded.document_id,
+    content = excluded.content, source = excluded.source, title = excluded.title,
+    metadata = excluded.metadata, embedding = excluded.embedding`,
+			c.ID, c.DocumentID, c.TenantID, c.Content, c.Source, c.Title, mustJSON(c.Metadata), mustJSON(c.Embedding))
+		if err != nil {
+			return fmt.Errorf("nexus/sqlite: upsert rag chunk: %w", err)
+		}
+	}
+	return nil
+}
+
+func (v *vectorIndex) Search(ctx context.Context, tenantID string, embedding []float64, k int) ([]rag.Match, error) {
+	rows, err := v.sdb.Query(ctx,
+		`SELECT id, document_id, tenant_id, content, source, title, metadata, embedding
+FROM rag_chunks WHERE tenant_id = ? OR tenant_id = ''`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("nexus/sqlite: search rag chunks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var matches []rag.Match
+	for rows.Next() {
+		var c rag.Chunk
+		var metadata, vector string
+		if err := rows.Scan(&c.ID, &c.DocumentID, &c.TenantID, &c.Content, &c.Source, &c.Title, &metadata, &vector); err != nil {
+			return nil, fmt.Errorf("nexus/sqlite: scan rag chunk: %w", err)
+		}
+		if err := json.Unmarshal([]byte(vector), &c.Embedding); err != nil {
+			return nil, fmt.Errorf("nexus/sqlite: decode rag embedding: %w", err)
+		}
+		if metadata != "" && metadata != "null" {
+			if err := json.Unmarshal([]byte(metadata), &c.Metadata); err != nil {
+				return nil, fmt.Errorf("nexus/sqlite: decode rag metadata: %w", err)
+			}
+		}
+		matches = append(matches, rag.Match{Chunk: c, Score: rag.Cosine(embedding, c.Embedding)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("nexus/sqlite: search rag chunks: %w", err)
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+func (v *vectorIndex) DeleteDocument(ctx context.Context, tenantID, documentID string) error {
+	_, err := v.sdb.Exec(ctx, `DELETE FROM rag_chunks WHERE tenant_id = ? AND document_id = ?`, tenantID, documentID)
+	if err != nil {
+		return fmt.Errorf("nexus/sqlite: delete rag document: %w", err)
+	}
+	return nil
+}
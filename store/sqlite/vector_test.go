@@ -0,0 +1,110 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/xraph/grove"
+	"github.com/xraph/grove/drivers/sqlitedriver"
+	_ "github.com/xraph/grove/drivers/sqlitedriver/sqlitemigrate"
+
+	"github.com/xraph/nexus/rag"
+	"github.com/xraph/nexus/store/sqlite"
+)
+
+func newStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	drv := sqlitedriver.New()
+	if err := drv.Open(context.Background(), filepath.Join(t.TempDir(), "nexus.db")); err != nil {
+		t.Fatal(err)
+	}
+	db, err := grove.Open(drv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sqlite.New(db)
+	t.Cleanup(func() { _ = s.Close() })
+	if err := s.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestVectorIndex_TenantsSharingChunkIDsStaySeparate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	idx := newStore(t).VectorIndex()
+
+	chunk := func(tenantID, content string) rag.Chunk {
+		return rag.Chunk{ID: "doc#0", DocumentID: "doc", TenantID: tenantID, Content: content, Embedding: []float64{1, 0}}
+	}
+	if err := idx.Upsert(ctx, []rag.Chunk{chunk("a", "alpha")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Upsert(ctx, []rag.Chunk{chunk("b", "bravo")}); err != nil {
+		t.Fatal(err)
+	}
+
+	for tenantID, want := range map[string]string{"a": "alpha", "b": "bravo"} {
+		matches, err := idx.Search(ctx, tenantID, []float64{1, 0}, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches) != 1 || matches[0].Chunk.Content != want || matches[0].Chunk.TenantID != tenantID {
+			t.Fatalf("tenant %s matches = %+v, want only %q", tenantID, matches, want)
+		}
+	}
+}
+
+func TestVectorIndex_UpsertSearchDelete(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	idx := newStore(t).VectorIndex()
+
+	if err := idx.Upsert(ctx, []rag.Chunk{
+		{ID: "d#0", DocumentID: "d", TenantID: "a", Content: "near", Title: "Doc", Metadata: map[string]string{"lang": "en"}, Embedding: []float64{1, 0.1}},
+		{ID: "d#1", DocumentID: "d", TenantID: "a", Content: "far", Embedding: []float64{0, 1}},
+		{ID: "s#0", DocumentID: "s", Content: "shared", Embedding: []float64{1, 0.5}},
+		{ID: "o#0", DocumentID: "o", TenantID: "b", Content: "other tenant", Embedding: []float64{1, 0}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := idx.Search(ctx, "a", []float64{1, 0}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 || matches[0].Chunk.Content != "near" || matches[1].Chunk.Content != "shared" {
+		t.Fatalf("matches = %+v, want near then shared", matches)
+	}
+	if c := matches[0].Chunk; c.Title != "Doc" || c.Metadata["lang"] != "en" || c.DocumentID != "d" {
+		t.Fatalf("chunk round-trip = %+v", c)
+	}
+	if matches[0].Score <= matches[1].Score {
+		t.Fatalf("scores not descending: %v, %v", matches[0].Score, matches[1].Score)
+	}
+
+	// Upserting an existing chunk replaces it.
+	if err := idx.Upsert(ctx, []rag.Chunk{{ID: "d#1", DocumentID: "d", TenantID: "a", Content: "moved", Embedding: []float64{1, 0}}}); err != nil {
+		t.Fatal(err)
+	}
+	matches, err = idx.Search(ctx, "a", []float64{1, 0}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Chunk.Content != "moved" {
+		t.Fatalf("matches = %+v, want the replaced chunk", matches)
+	}
+
+	if err := idx.DeleteDocument(ctx, "a", "d"); err != nil {
+		t.Fatal(err)
+	}
+	matches, err = idx.Search(ctx, "a", []float64{1, 0}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Chunk.Content != "shared" {
+		t.Fatalf("matches after delete = %+v, want only the shared chunk", matches)
+	}
+}
@@ -2,7 +2,10 @@
 package store
 
 import (
+	"github.com/xraph/nexus/background"
+	"github.com/xraph/nexus/batch"
 	"github.com/xraph/nexus/key"
+	"github.com/xraph/nexus/prompt"
 	"github.com/xraph/nexus/tenant"
 	"github.com/xraph/nexus/usage"
 )
@@ -15,6 +18,9 @@ type Store interface {
 	Tenants() tenant.Store
 	Keys() key.Store
 	Usage() usage.Store
+	Prompts() prompt.Store
+	Batches() batch.Store
+	Background() background.Store
 
 	// Lifecycle
 	Migrate() error
@@ -96,4 +96,11 @@ var (
 	_ OutputTransform = (*OutputAnonymizerTransform)(nil)
 	_ InputTransform  = (*SystemPromptTransform)(nil)
 	_ InputTransform  = (*RAGTransform)(nil)
+	_ InputTransform  = (*PromptCacheTransform)(nil)
+	_ InputTransform  = (*PseudonymizerTransform)(nil)
+	_ InputTransform  = (*StructuredOutputTransform)(nil)
+	_ OutputTransform = (*StructuredOutputTransform)(nil)
+
+	_ StreamingOutputTransform = (*PseudonymizerTransform)(nil)
+	_ StreamFlusher            = (*PseudonymizerTransform)(nil)
 )
@@ -0,0 +1,268 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// validateSchema checks v (a value decoded with encoding/json) against a
+// JSON Schema and returns one message per violation. It supports the subset
+// of JSON Schema used for structured outputs: type, properties, required,
+// additionalProperties, items, enum, const, anyOf/oneOf/allOf, string
+// length and pattern, numeric bounds and array length. Unknown keywords
+// are ignored.
+func validateSchema(schema, v any) []string {
+	var errs []string
+	validateAt(toSchemaMap(schema), v, "$", &errs)
+	return errs
+}
+
+// toSchemaMap normalizes a schema given as a map, raw JSON or any
+// JSON-marshalable value.
+func toSchemaMap(schema any) map[string]any {
+	switch s := schema.(type) {
+	case map[string]any:
+		return s
+	case nil:
+		return nil
+	case json.RawMessage:
+		var m map[string]any
+		if json.Unmarshal(s, &m) == nil {
+			return m
+		}
+		return nil
+	case []byte:
+		return toSchemaMap(json.RawMessage(s))
+	case string:
+		return toSchemaMap(json.RawMessage(s))
+	default:
+		b, err := json.Marshal(s)
+		if err != nil {
+			return nil
+		}
+		return toSchemaMap(json.RawMessage(b))
+	}
+}
+
+func validateAt(schema map[string]any, v any, path string, errs *[]string) {
+	if schema == nil {
+		return
+	}
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, v) {
+		fail("expected %s, got %s", typeNames(t), jsonTypeOf(v))
+		return
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, v) {
+		fail("must equal %v", c)
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %v", enum)
+		}
+	}
+
+	if subs, ok := schema["allOf"].([]any); ok {
+		for _, sub := range subs {
+			validateAt(toSchemaMap(sub), v, path, errs)
+		}
+	}
+	for _, kw := range []string{"anyOf", "oneOf"} {
+		subs, ok := schema[kw].([]any)
+		if !ok {
+			continue
+		}
+		matches := 0
+		for _, sub := range subs {
+			var subErrs []string
+			validateAt(toSchemaMap(sub), v, path, &subErrs)
+			if len(subErrs) == 0 {
+				matches++
+			}
+		}
+		if matches == 0 || (kw == "oneOf" && matches > 1) {
+			fail("must match %s %s", map[string]string{"anyOf": "at least one of", "oneOf": "exactly one of"}[kw], kw)
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		validateObject(schema, val, path, errs)
+	case []any:
+		if lo, ok := number(schema["minItems"]); ok && float64(len(val)) < lo {
+			fail("must have at least %v items", lo)
+		}
+		if hi, ok := number(schema["maxItems"]); ok && float64(len(val)) > hi {
+			fail("must have at most %v items", hi)
+		}
+		if items := toSchemaMap(schema["items"]); items != nil {
+			for i, item := range val {
+				validateAt(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case string:
+		n := float64(len([]rune(val)))
+		if lo, ok := number(schema["minLength"]); ok && n < lo {
+			fail("must be at least %v characters", lo)
+		}
+		if hi, ok := number(schema["maxLength"]); ok && n > hi {
+			fail("must be at most %v characters", hi)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(val) {
+				fail("must match pattern %q", pattern)
+			}
+		}
+	case float64:
+		if lo, ok := number(schema["minimum"]); ok && val < lo {
+			fail("must be >= %v", lo)
+		}
+		if hi, ok := number(schema["maximum"]); ok && val > hi {
+			fail("must be <= %v", hi)
+		}
+		if lo, ok := number(schema["exclusiveMinimum"]); ok && val <= lo {
+			fail("must be > %v", lo)
+		}
+		if hi, ok := number(schema["exclusiveMaximum"]); ok && val >= hi {
+			fail("must be < %v", hi)
+		}
+	}
+}
+
+func validateObject(schema, obj map[string]any, path string, errs *[]string) {
+	props, _ := schema["properties"].(map[string]any)
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok {
+				continue
+			}
+			if _, present := obj[name]; !present {
+				*errs = append(*errs, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		child := path + "." + k
+		if ps, ok := props[k]; ok {
+			validateAt(toSchemaMap(ps), obj[k], child, errs)
+			continue
+		}
+		switch ap := schema["additionalProperties"].(type) {
+		case bool:
+			if !ap {
+				*errs = append(*errs, fmt.Sprintf("%s: unexpected property %q", path, k))
+			}
+		case map[string]any:
+			validateAt(ap, obj[k], child, errs)
+		}
+	}
+}
+
+func matchesType(t, v any) bool {
+	switch tt := t.(type) {
+	case string:
+		return matchesTypeName(tt, v)
+	case []any:
+		for _, name := range tt {
+			if s, ok := name.(string); ok && matchesTypeName(s, v) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func matchesTypeName(name string, v any) bool {
+	switch name {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	default:
+		return true
+	}
+}
+
+func typeNames(t any) string {
+	switch tt := t.(type) {
+	case string:
+		return tt
+	case []any:
+		names := make([]string, 0, len(tt))
+		for _, n := range tt {
+			names = append(names, fmt.Sprint(n))
+		}
+		return strings.Join(names, " or ")
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func jsonTypeOf(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func number(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
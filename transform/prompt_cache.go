@@ -0,0 +1,175 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// maxCacheBreakpoints is the most cache markers a single request may carry
+// (Anthropic's limit; other providers accept fewer, never more).
+const maxCacheBreakpoints = 4
+
+// PromptCacheTransform places prompt-cache breakpoints on large, stable
+// request prefixes so repeated prompts are served from the provider cache.
+//
+// Breakpoints are placed, in order, after the tool list, after the system
+// prompt and after the conversation history preceding the final user turn —
+// each only when the cumulative prefix up to that point reaches the minimum
+// cacheable size. Existing markers are left alone and count toward the
+// four-breakpoint budget. Sizes are estimated at four characters per token.
+type PromptCacheTransform struct {
+	minTokens int
+	ttl       string
+}
+
+// NewPromptCache creates an automatic cache breakpoint transform with a
+// 1024-token minimum prefix and the provider's default TTL.
+func NewPromptCache() *PromptCacheTransform {
+	return &PromptCacheTransform{minTokens: 1024}
+}
+
+// WithMinTokens sets the smallest estimated prefix worth caching.
+func (t *PromptCacheTransform) WithMinTokens(n int) *PromptCacheTransform {
+	t.minTokens = n
+	return t
+}
+
+// WithTTL sets the TTL on placed markers (e.g. "5m", "1h").
+func (t *PromptCacheTransform) WithTTL(ttl string) *PromptCacheTransform {
+	t.ttl = ttl
+	return t
+}
+
+func (t *PromptCacheTransform) Name() string { return "prompt_cache" }
+func (t *PromptCacheTransform) Phase() Phase { return PhaseInput }
+
+func (t *PromptCacheTransform) TransformInput(_ context.Context, req *provider.CompletionRequest) error {
+	if req.CachedContent != "" {
+		return nil
+	}
+	budget := maxCacheBreakpoints - countCacheMarkers(req)
+	if budget <= 0 {
+		return nil
+	}
+
+	// Tools.
+	prefix := 0
+	for i := range req.Tools {
+		prefix += estimateTokens(req.Tools[i].Function.Name) +
+			estimateTokens(req.Tools[i].Function.Description) +
+			estimateJSONTokens(req.Tools[i].Function.Parameters)
+	}
+	if n := len(req.Tools); n > 0 && prefix >= t.minTokens && req.Tools[n-1].CacheControl == nil {
+		req.Tools[n-1].CacheControl = provider.NewCacheControl(t.ttl)
+		budget--
+	}
+
+	// System prompt, including leading system messages.
+	prefix += estimateTokens(req.System)
+	lastSystem := -1
+	for i := range req.Messages {
+		if req.Messages[i].Role != "system" {
+			break
+		}
+		prefix += estimateMessageTokens(&req.Messages[i])
+		lastSystem = i
+	}
+	if budget > 0 && prefix >= t.minTokens {
+		switch {
+		case lastSystem >= 0:
+			if req.Messages[lastSystem].CacheControl == nil {
+				req.Messages[lastSystem].CacheControl = provider.NewCacheControl(t.ttl)
+				budget--
+			}
+		case req.System != "" && req.SystemCacheControl == nil:
+			req.SystemCacheControl = provider.NewCacheControl(t.ttl)
+			budget--
+		}
+	}
+
+	// History before the final user turn.
+	lastUser := -1
+	for i := len(req.Messages) - 1; i > lastSystem; i-- {
+		if req.Messages[i].Role == "user" {
+			lastUser = i
+			break
+		}
+	}
+	if budget <= 0 || lastUser-1 <= lastSystem {
+		return nil
+	}
+	for i := lastSystem + 1; i < lastUser; i++ {
+		prefix += estimateMessageTokens(&req.Messages[i])
+	}
+	if prefix >= t.minTokens && req.Messages[lastUser-1].CacheControl == nil {
+		req.Messages[lastUser-1].CacheControl = provider.NewCacheControl(t.ttl)
+	}
+	return nil
+}
+
+// countCacheMarkers counts the breakpoints already present on req.
+func countCacheMarkers(req *provider.CompletionRequest) int {
+	n := 0
+	if req.SystemCacheControl != nil {
+		n++
+	}
+	for i := range req.Tools {
+		if req.Tools[i].CacheControl != nil {
+			n++
+		}
+	}
+	for i := range req.Messages {
+		m := &req.Messages[i]
+		if m.CacheControl != nil {
+			n++
+		}
+		switch parts := m.Content.(type) {
+		case []provider.ContentPart:
+			for j := range parts {
+				if parts[j].CacheControl != nil {
+					n++
+				}
+			}
+		case []any:
+			for _, p := range parts {
+				if pm, ok := p.(map[string]any); ok && pm["cache_control"] != nil {
+					n++
+				}
+			}
+		}
+	}
+	return n
+}
+
+func estimateTokens(s string) int { return len(s) / 4 }
+
+func estimateJSONTokens(v any) int {
+	if v == nil {
+		return 0
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(b) / 4
+}
+
+func estimateMessageTokens(m *provider.Message) int {
+	n := 0
+	switch c := m.Content.(type) {
+	case string:
+		n = estimateTokens(c)
+	case []provider.ContentPart:
+		for i := range c {
+			n += estimateTokens(c[i].Text)
+		}
+	default:
+		n = estimateJSONTokens(c)
+	}
+	for i := range m.ToolCalls {
+		n += estimateTokens(m.ToolCalls[i].Function.Arguments)
+	}
+	return n
+}
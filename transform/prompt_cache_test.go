@@ -0,0 +1,99 @@
+package transform_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xraph/nexus/provider"
+	"github.com/xraph/nexus/transform"
+)
+
+// big is a message body the transform estimates at n tokens.
+func big(n int) string { return strings.Repeat("abcd", n) }
+
+func TestPromptCache_PlacesBreakpoints(t *testing.T) {
+	t.Parallel()
+	req := &provider.CompletionRequest{
+		Tools: []provider.Tool{
+			{Function: provider.ToolFunction{Name: "a", Description: big(600)}},
+			{Function: provider.ToolFunction{Name: "b", Description: big(600)}},
+		},
+		System: big(600),
+		Messages: []provider.Message{
+			{Role: "user", Content: big(600)},
+			{Role: "assistant", Content: "ok"},
+			{Role: "user", Content: "next"},
+		},
+	}
+	if err := transform.NewPromptCache().WithTTL("1h").TransformInput(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Tools[0].CacheControl != nil {
+		t.Error("marker placed on a tool other than the last")
+	}
+	if cc := req.Tools[1].CacheControl; cc == nil || cc.TTL != "1h" {
+		t.Errorf("tools marker = %+v", cc)
+	}
+	if req.SystemCacheControl == nil {
+		t.Error("system prompt not marked")
+	}
+	if req.Messages[1].CacheControl == nil {
+		t.Error("history before the final user turn not marked")
+	}
+	if req.Messages[2].CacheControl != nil {
+		t.Error("final user turn marked")
+	}
+}
+
+func TestPromptCache_SkipsSmallPrefixes(t *testing.T) {
+	t.Parallel()
+	req := &provider.CompletionRequest{
+		System: "short",
+		Messages: []provider.Message{
+			{Role: "user", Content: "hi"},
+			{Role: "assistant", Content: "hello"},
+			{Role: "user", Content: "bye"},
+		},
+	}
+	if err := transform.NewPromptCache().TransformInput(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if req.HasCacheControl() {
+		t.Fatalf("small request marked: %+v", req)
+	}
+}
+
+func TestPromptCache_RespectsExistingMarkers(t *testing.T) {
+	t.Parallel()
+	marked := func() *provider.CacheControl { return provider.NewCacheControl("") }
+	req := &provider.CompletionRequest{
+		System:             big(2000),
+		SystemCacheControl: marked(),
+		Messages: []provider.Message{
+			{Role: "user", Content: big(10), CacheControl: marked()},
+			{Role: "assistant", Content: big(10), CacheControl: marked()},
+			{Role: "user", Content: big(10), CacheControl: marked()},
+			{Role: "assistant", Content: "ok"},
+			{Role: "user", Content: "next"},
+		},
+	}
+	if err := transform.NewPromptCache().TransformInput(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Messages[3].CacheControl != nil {
+		t.Error("a fifth breakpoint was placed")
+	}
+
+	req = &provider.CompletionRequest{
+		System:        big(2000),
+		CachedContent: "cachedContents/abc",
+		Messages:      []provider.Message{{Role: "user", Content: "hi"}},
+	}
+	if err := transform.NewPromptCache().TransformInput(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if req.HasCacheControl() {
+		t.Error("request served from a cached content was marked")
+	}
+}
@@ -0,0 +1,53 @@
+package transform
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/prompt"
+	"github.com/xraph/nexus/provider"
+)
+
+// State keys written by PromptTemplateTransform on
+// provider.CompletionRequest.State.
+const (
+	// StateKeyPromptID holds the name of the rendered template.
+	StateKeyPromptID = "prompt.id"
+
+	// StateKeyPromptVersion holds the resolved version (int) of the
+	// rendered template.
+	StateKeyPromptVersion = "prompt.version"
+)
+
+// PromptTemplateTransform renders the registry template named by the
+// request's PromptID, resolved by PromptVersion for the requesting tenant,
+// with PromptVariables, and prepends it as a system message. Requests
+// without a PromptID pass through unchanged.
+type PromptTemplateTransform struct {
+	prompts prompt.Service
+}
+
+// NewPromptTemplate creates a prompt template rendering transform.
+func NewPromptTemplate(prompts prompt.Service) *PromptTemplateTransform {
+	return &PromptTemplateTransform{prompts: prompts}
+}
+
+func (t *PromptTemplateTransform) Name() string { return "prompt_template" }
+func (t *PromptTemplateTransform) Phase() Phase { return PhaseInput }
+
+func (t *PromptTemplateTransform) TransformInput(ctx context.Context, req *provider.CompletionRequest) error {
+	if req.PromptID == "" {
+		return nil
+	}
+
+	text, tmpl, err := t.prompts.Render(ctx, pipeline.TenantID(ctx), req.PromptID, string(req.PromptVersion), req.PromptVariables)
+	if err != nil {
+		return fmt.Errorf("prompt_template: %w", err)
+	}
+
+	req.Messages = append([]provider.Message{{Role: "system", Content: text}}, req.Messages...)
+	setRequestState(req, StateKeyPromptID, tmpl.Name)
+	setRequestState(req, StateKeyPromptVersion, tmpl.Version)
+	return nil
+}
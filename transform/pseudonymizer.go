@@ -0,0 +1,348 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/xraph/nexus/provider"
+)
+
+// StateKeyPseudonyms is the provider.CompletionRequest.State key holding the
+// *PseudonymMap built by PseudonymizerTransform for the request.
+const StateKeyPseudonyms = "pseudonymizer.mapping"
+
+// PseudonymizerTransform replaces sensitive entities with stable per-request
+// tokens (EMAIL_1, PHONE_2, PERSON_1) before the provider call and restores
+// the original values in the response, so the model can refer to entities
+// without ever seeing them.
+//
+// The same value always maps to the same token within a request. Restoration
+// covers message content and tool-call arguments, for both complete and
+// streamed responses; tokens split across stream chunks are held back until
+// they are complete.
+type PseudonymizerTransform struct {
+	detectors []entityDetector
+}
+
+type entityDetector struct {
+	kind  string
+	regex *regexp.Regexp
+}
+
+// NewPseudonymizer creates a pseudonymizer with common PII patterns:
+// EMAIL, PHONE, SSN, CREDIT_CARD and IP_ADDRESS.
+func NewPseudonymizer() *PseudonymizerTransform {
+	p := &PseudonymizerTransform{}
+	p.AddPattern("EMAIL", `\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)
+	p.AddPattern("SSN", `\b\d{3}-\d{2}-\d{4}\b`)
+	p.AddPattern("CREDIT_CARD", `\b(?:\d{4}[-\s]?){3}\d{4}\b`)
+	p.AddPattern("PHONE", `\b(?:\+?1[-.\s]?)?\(?\d{3}\)?[-.\s]?\d{3}[-.\s]?\d{4}\b`)
+	p.AddPattern("IP_ADDRESS", `\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	return p
+}
+
+// AddPattern adds a regex detector whose matches are replaced with
+// kind-numbered tokens. kind should be upper-case, e.g. "ACCOUNT_ID".
+func (p *PseudonymizerTransform) AddPattern(kind, pattern string) {
+	p.detectors = append(p.detectors, entityDetector{kind: kind, regex: regexp.MustCompile(pattern)})
+}
+
+// AddTerms adds known literal values (customer names, project codenames)
+// to pseudonymize as kind, e.g. AddTerms("PERSON", "Jane Doe").
+func (p *PseudonymizerTransform) AddTerms(kind string, terms ...string) {
+	quoted := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if t != "" {
+			quoted = append(quoted, regexp.QuoteMeta(t))
+		}
+	}
+	if len(quoted) == 0 {
+		return
+	}
+	p.AddPattern(kind, `\b(?:`+strings.Join(quoted, "|")+`)\b`)
+}
+
+func (p *PseudonymizerTransform) Name() string { return "pseudonymizer" }
+func (p *PseudonymizerTransform) Phase() Phase { return PhaseInput }
+
+// PseudonymMap is the per-request mapping between original values and
+// tokens.
+type PseudonymMap struct {
+	tokens   map[string]string // original -> token
+	values   map[string]string // token -> original
+	counts   map[string]int    // kind -> last number issued
+	replacer *strings.Replacer
+	jsonRepl *strings.Replacer
+	maxLen   int
+
+	// Streaming hold-back: text that may be the start of a token.
+	contentTail string
+	argTails    map[int]string
+	argIDs      map[int]string
+}
+
+func newPseudonymMap() *PseudonymMap {
+	return &PseudonymMap{
+		tokens: make(map[string]string),
+		values: make(map[string]string),
+		counts: make(map[string]int),
+	}
+}
+
+// Original returns the value a token stands for.
+func (m *PseudonymMap) Original(token string) (string, bool) {
+	v, ok := m.values[token]
+	return v, ok
+}
+
+// Len returns the number of distinct entities pseudonymized.
+func (m *PseudonymMap) Len() int { return len(m.values) }
+
+func (m *PseudonymMap) token(kind, value string) string {
+	if t, ok := m.tokens[value]; ok {
+		return t
+	}
+	m.counts[kind]++
+	t := fmt.Sprintf("%s_%d", kind, m.counts[kind])
+	m.tokens[value] = t
+	m.values[t] = value
+	m.replacer, m.jsonRepl = nil, nil
+	if len(t) > m.maxLen {
+		m.maxLen = len(t)
+	}
+	return t
+}
+
+// restore replaces every complete token in s with its original value.
+// inJSON escapes the originals for use inside a JSON string literal.
+func (m *PseudonymMap) restore(s string, inJSON bool) string {
+	if len(m.values) == 0 || s == "" {
+		return s
+	}
+	if m.replacer == nil {
+		// Longest tokens first so EMAIL_12 is not read as EMAIL_1 + "2".
+		toks := make([]string, 0, len(m.values))
+		for t := range m.values {
+			toks = append(toks, t)
+		}
+		sort.Slice(toks, func(i, j int) bool { return len(toks[i]) > len(toks[j]) })
+		plain := make([]string, 0, 2*len(toks))
+		escaped := make([]string, 0, 2*len(toks))
+		for _, t := range toks {
+			v := m.values[t]
+			plain = append(plain, t, v)
+			escaped = append(escaped, t, jsonEscape(v))
+		}
+		m.replacer = strings.NewReplacer(plain...)
+		m.jsonRepl = strings.NewReplacer(escaped...)
+	}
+	if inJSON {
+		return m.jsonRepl.Replace(s)
+	}
+	return m.replacer.Replace(s)
+}
+
+// holdBack splits s into the part that can be restored now and a suffix
+// that may still grow into a token.
+func (m *PseudonymMap) holdBack(s string) (ready, tail string) {
+	start := len(s) - m.maxLen
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(s); i++ {
+		suffix := s[i:]
+		for t := range m.values {
+			if strings.HasPrefix(t, suffix) {
+				return s[:i], suffix
+			}
+		}
+	}
+	return s, ""
+}
+
+func jsonEscape(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return s
+	}
+	return string(b[1 : len(b)-1])
+}
+
+func (p *PseudonymizerTransform) TransformInput(_ context.Context, req *provider.CompletionRequest) error {
+	m := newPseudonymMap()
+	replace := func(s string) string {
+		for _, d := range p.detectors {
+			s = d.regex.ReplaceAllStringFunc(s, func(v string) string { return m.token(d.kind, v) })
+		}
+		return s
+	}
+
+	req.System = replace(req.System)
+	for i := range req.Messages {
+		msg := &req.Messages[i]
+		switch c := msg.Content.(type) {
+		case string:
+			msg.Content = replace(c)
+		case []provider.ContentPart:
+			parts := make([]provider.ContentPart, len(c))
+			for j, part := range c {
+				part.Text = replace(part.Text)
+				parts[j] = part
+			}
+			msg.Content = parts
+		case []any:
+			parts := make([]any, len(c))
+			for j, part := range c {
+				if pm, ok := part.(map[string]any); ok {
+					if text, ok := pm["text"].(string); ok {
+						cp := make(map[string]any, len(pm))
+						for k, v := range pm {
+							cp[k] = v
+						}
+						cp["text"] = replace(text)
+						part = cp
+					}
+				}
+				parts[j] = part
+			}
+			msg.Content = parts
+		}
+		if len(msg.ToolCalls) > 0 {
+			calls := make([]provider.ToolCall, len(msg.ToolCalls))
+			for j, tc := range msg.ToolCalls {
+				tc.Function.Arguments = replace(tc.Function.Arguments)
+				calls[j] = tc
+			}
+			msg.ToolCalls = calls
+		}
+	}
+
+	if m.Len() == 0 {
+		return nil
+	}
+	if req.State == nil {
+		req.State = make(map[string]any)
+	}
+	req.State[StateKeyPseudonyms] = m
+	return nil
+}
+
+func pseudonymsFor(req *provider.CompletionRequest) *PseudonymMap {
+	m, ok := req.State[StateKeyPseudonyms].(*PseudonymMap)
+	if !ok {
+		return nil
+	}
+	return m
+}
+
+// TransformOutput restores original values in a complete response.
+func (p *PseudonymizerTransform) TransformOutput(_ context.Context, req *provider.CompletionRequest, resp *provider.CompletionResponse) error {
+	m := pseudonymsFor(req)
+	if m == nil {
+		return nil
+	}
+	for i := range resp.Choices {
+		msg := &resp.Choices[i].Message
+		if s, ok := msg.Content.(string); ok {
+			msg.Content = m.restore(s, false)
+		}
+		for j := range msg.ToolCalls {
+			msg.ToolCalls[j].Function.Arguments = m.restore(msg.ToolCalls[j].Function.Arguments, true)
+		}
+	}
+	return nil
+}
+
+// TransformChunk restores tokens in a streamed chunk, holding back any
+// trailing text that may be the first half of a token.
+func (p *PseudonymizerTransform) TransformChunk(_ context.Context, req *provider.CompletionRequest, chunk *provider.StreamChunk) (*provider.StreamChunk, error) {
+	m := pseudonymsFor(req)
+	if m == nil {
+		return chunk, nil
+	}
+	out := *chunk
+	final := chunk.FinishReason != ""
+
+	text := m.contentTail + chunk.Delta.Content
+	m.contentTail = ""
+	if !final {
+		text, m.contentTail = m.holdBack(text)
+	}
+	out.Delta.Content = m.restore(text, false)
+
+	if len(chunk.Delta.ToolCalls) > 0 {
+		if m.argTails == nil {
+			m.argTails = make(map[int]string)
+			m.argIDs = make(map[int]string)
+		}
+		calls := make([]provider.ToolCall, len(chunk.Delta.ToolCalls))
+		for i, tc := range chunk.Delta.ToolCalls {
+			if tc.ID != "" {
+				m.argIDs[i] = tc.ID
+			}
+			args := m.argTails[i] + tc.Function.Arguments
+			delete(m.argTails, i)
+			if !final {
+				args, m.argTails[i] = m.holdBack(args)
+			}
+			tc.Function.Arguments = m.restore(args, true)
+			calls[i] = tc
+		}
+		out.Delta.ToolCalls = calls
+	}
+	if final {
+		out.Delta.ToolCalls = append(out.Delta.ToolCalls, m.flushArgs(len(out.Delta.ToolCalls))...)
+	}
+	return &out, nil
+}
+
+// FlushStream releases any held-back text when the stream ends without a
+// finish chunk.
+func (p *PseudonymizerTransform) FlushStream(_ context.Context, req *provider.CompletionRequest) (*provider.StreamChunk, error) {
+	m := pseudonymsFor(req)
+	if m == nil || (m.contentTail == "" && len(m.argTails) == 0) {
+		return nil, nil
+	}
+	chunk := &provider.StreamChunk{
+		Delta: provider.Delta{
+			Content:   m.restore(m.contentTail, false),
+			ToolCalls: m.flushArgs(0),
+		},
+	}
+	m.contentTail = ""
+	return chunk, nil
+}
+
+// flushArgs returns fragments carrying held-back tool-call arguments for
+// slots at or beyond from, positioned so they merge into the right call.
+func (m *PseudonymMap) flushArgs(from int) []provider.ToolCall {
+	maxSlot := -1
+	for i, tail := range m.argTails {
+		if tail != "" && i > maxSlot {
+			maxSlot = i
+		}
+	}
+	if maxSlot < from {
+		// Tails for slots already present in this chunk were merged above.
+		m.argTails = nil
+		return nil
+	}
+	calls := make([]provider.ToolCall, maxSlot+1-from)
+	for i := from; i <= maxSlot; i++ {
+		calls[i-from] = provider.ToolCall{
+			ID:       m.argIDs[i],
+			Function: provider.ToolCallFunc{Arguments: m.restore(m.argTails[i], true)},
+		}
+	}
+	m.argTails = nil
+	return calls
+}
+
+// TransformAccumulated is a no-op; chunks were restored as they streamed.
+func (p *PseudonymizerTransform) TransformAccumulated(_ context.Context, _ *provider.CompletionRequest, _ *provider.CompletionResponse) error {
+	return nil
+}
@@ -3,11 +3,23 @@ package transform
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/xraph/nexus/provider"
 )
 
+// State keys written by RAGTransform on provider.CompletionRequest.State.
+const (
+	// StateKeyRAGChunks holds the []RAGChunk injected into the request.
+	StateKeyRAGChunks = "rag.chunks"
+
+	// StateKeyRAGError holds the retrieval error message when retrieval
+	// failed and the transform is not required.
+	StateKeyRAGError = "rag.error"
+)
+
 // RAGProvider retrieves context for a query.
 type RAGProvider interface {
 	// Retrieve returns relevant context chunks for the query.
@@ -18,31 +30,40 @@ type RAGProvider interface {
 type RAGChunk struct {
 	Content  string  // The text content
 	Source   string  // Where it came from
+	Title    string  // Human-readable source title
 	Score    float64 // Relevance score (0-1)
 	Metadata map[string]string
 }
 
-// RAGTransform injects retrieved context into requests.
+// RAGTransform injects retrieved context into requests and attributes it in
+// the response: each injected chunk is numbered, the model is asked to cite
+// it as [n], and the chunks' sources are returned as
+// provider.CompletionResponse.Citations (or Delta.Citations on the final
+// stream chunk). When the answer cites chunks by number, only those are
+// returned.
 type RAGTransform struct {
-	provider   RAGProvider
-	maxResults int
-	template   string
+	provider    RAGProvider
+	maxResults  int
+	template    string
+	tokenBudget int
+	required    bool
 }
 
 // NewRAG creates a RAG context injection transform.
 func NewRAG(rag RAGProvider) *RAGTransform {
 	return &RAGTransform{
-		provider:   rag,
-		maxResults: 5,
-		template:   defaultRAGTemplate,
+		provider:    rag,
+		maxResults:  5,
+		template:    defaultRAGTemplate,
+		tokenBudget: 2000,
 	}
 }
 
-const defaultRAGTemplate = `Here is relevant context to help answer the user's question:
+const defaultRAGTemplate = `Here is relevant context to help answer the user's question. Each entry is numbered:
 
 %s
 
-Please use this context to inform your response. If the context doesn't contain relevant information, use your general knowledge.`
+Please use this context to inform your response, citing entries you rely on as [1], [2], etc. If the context doesn't contain relevant information, use your general knowledge.`
 
 func (t *RAGTransform) Name() string { return "rag" }
 func (t *RAGTransform) Phase() Phase { return PhaseInput }
@@ -56,20 +77,25 @@ func (t *RAGTransform) TransformInput(ctx context.Context, req *provider.Complet
 
 	chunks, err := t.provider.Retrieve(ctx, query, t.maxResults)
 	if err != nil {
-		// RAG failures are non-fatal — continue without context
+		if t.required {
+			return fmt.Errorf("rag: retrieve: %w", err)
+		}
+		// Non-fatal — continue without context, but record why.
+		setRequestState(req, StateKeyRAGError, err.Error())
 		return nil
 	}
 
+	chunks = t.fitBudget(chunks)
 	if len(chunks) == 0 {
 		return nil
 	}
 
 	// Build context string
-	var parts []string
+	parts := make([]string, 0, len(chunks))
 	for i, chunk := range chunks {
 		part := fmt.Sprintf("[%d] %s", i+1, chunk.Content)
-		if chunk.Source != "" {
-			part += fmt.Sprintf(" (source: %s)", chunk.Source)
+		if src := chunkLabel(chunk); src != "" {
+			part += fmt.Sprintf(" (source: %s)", src)
 		}
 		parts = append(parts, part)
 	}
@@ -93,10 +119,128 @@ func (t *RAGTransform) TransformInput(ctx context.Context, req *provider.Complet
 
 	// Insert at position
 	req.Messages = append(req.Messages[:insertIdx], append([]provider.Message{ragMsg}, req.Messages[insertIdx:]...)...)
+	setRequestState(req, StateKeyRAGChunks, chunks)
 
 	return nil
 }
 
+// fitBudget keeps chunks, in rank order, while their estimated tokens fit
+// the budget. The first chunk is truncated rather than dropped.
+func (t *RAGTransform) fitBudget(chunks []RAGChunk) []RAGChunk {
+	if t.tokenBudget <= 0 {
+		return chunks
+	}
+	used := 0
+	for i, c := range chunks {
+		n := estimateTokens(c.Content)
+		if used+n <= t.tokenBudget {
+			used += n
+			continue
+		}
+		if i == 0 {
+			c.Content = truncateRunes(c.Content, t.tokenBudget*4)
+			return []RAGChunk{c}
+		}
+		return chunks[:i]
+	}
+	return chunks
+}
+
+// TransformOutput attaches the sources of the injected chunks as citations.
+func (t *RAGTransform) TransformOutput(_ context.Context, req *provider.CompletionRequest, resp *provider.CompletionResponse) error {
+	chunks := ragChunksFor(req)
+	if len(chunks) == 0 {
+		return nil
+	}
+	var answer strings.Builder
+	for _, c := range resp.Choices {
+		if s, ok := c.Message.Content.(string); ok {
+			answer.WriteString(s)
+		}
+	}
+	resp.Citations = append(resp.Citations, ragCitations(chunks, answer.String())...)
+	return nil
+}
+
+// TransformChunk attaches citations to the final chunk of a stream. The
+// answer is not buffered, so every injected chunk is cited.
+func (t *RAGTransform) TransformChunk(_ context.Context, req *provider.CompletionRequest, chunk *provider.StreamChunk) (*provider.StreamChunk, error) {
+	if chunk.FinishReason == "" {
+		return chunk, nil
+	}
+	chunks := ragChunksFor(req)
+	if len(chunks) == 0 {
+		return chunk, nil
+	}
+	out := *chunk
+	out.Delta.Citations = append(append([]provider.Citation(nil), chunk.Delta.Citations...), ragCitations(chunks, "")...)
+	return &out, nil
+}
+
+// TransformAccumulated is a no-op; citations were emitted on the final chunk.
+func (t *RAGTransform) TransformAccumulated(_ context.Context, _ *provider.CompletionRequest, _ *provider.CompletionResponse) error {
+	return nil
+}
+
+var citationMarkerRe = regexp.MustCompile(`\[(\d+)\]`)
+
+// ragCitations converts chunks to citations. When answer cites chunks by
+// number, only those are returned; otherwise all are.
+func ragCitations(chunks []RAGChunk, answer string) []provider.Citation {
+	cited := make(map[int]bool)
+	for _, m := range citationMarkerRe.FindAllStringSubmatch(answer, -1) {
+		if n, err := strconv.Atoi(m[1]); err == nil && n >= 1 && n <= len(chunks) {
+			cited[n-1] = true
+		}
+	}
+	citations := make([]provider.Citation, 0, len(chunks))
+	for i, c := range chunks {
+		if len(cited) > 0 && !cited[i] {
+			continue
+		}
+		citations = append(citations, provider.Citation{
+			URL:    c.Source,
+			Title:  c.Title,
+			Quoted: truncateRunes(c.Content, 200),
+		})
+	}
+	return citations
+}
+
+func ragChunksFor(req *provider.CompletionRequest) []RAGChunk {
+	chunks, ok := req.State[StateKeyRAGChunks].([]RAGChunk)
+	if !ok {
+		return nil
+	}
+	return chunks
+}
+
+func chunkLabel(c RAGChunk) string {
+	switch {
+	case c.Title != "" && c.Source != "":
+		return c.Title + ", " + c.Source
+	case c.Title != "":
+		return c.Title
+	default:
+		return c.Source
+	}
+}
+
+func setRequestState(req *provider.CompletionRequest, key string, value any) {
+	if req.State == nil {
+		req.State = make(map[string]any)
+	}
+	req.State[key] = value
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
 // WithMaxResults sets the maximum number of RAG results.
 func (t *RAGTransform) WithMaxResults(n int) *RAGTransform {
 	t.maxResults = n
@@ -110,12 +254,37 @@ func (t *RAGTransform) WithTemplate(tmpl string) *RAGTransform {
 	return t
 }
 
+// WithTokenBudget caps the estimated tokens of injected context; lower
+// ranked chunks are dropped to fit. Zero disables the cap. Default 2000.
+func (t *RAGTransform) WithTokenBudget(tokens int) *RAGTransform {
+	t.tokenBudget = tokens
+	return t
+}
+
+// WithRequired fails the request when retrieval fails. By default the
+// request continues without context and the error is recorded under
+// StateKeyRAGError.
+func (t *RAGTransform) WithRequired(required bool) *RAGTransform {
+	t.required = required
+	return t
+}
+
 func extractLastUserMessage(messages []provider.Message) string {
 	for i := len(messages) - 1; i >= 0; i-- {
-		if messages[i].Role == "user" {
-			if s, ok := messages[i].Content.(string); ok {
-				return s
+		if messages[i].Role != "user" {
+			continue
+		}
+		switch c := messages[i].Content.(type) {
+		case string:
+			return c
+		case []provider.ContentPart:
+			var texts []string
+			for _, p := range c {
+				if p.Text != "" {
+					texts = append(texts, p.Text)
+				}
 			}
+			return strings.Join(texts, "\n")
 		}
 	}
 	return ""
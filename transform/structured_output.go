@@ -0,0 +1,301 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xraph/nexus/model"
+	"github.com/xraph/nexus/pipeline"
+	"github.com/xraph/nexus/provider"
+)
+
+// StateKeyStructuredOutputErrors is the provider.CompletionResponse.State
+// key holding the remaining schema violations ([]string) when a
+// non-strict structured output could not be repaired.
+const StateKeyStructuredOutputErrors = "structured_output.errors"
+
+// ErrStructuredOutput is returned when a strict json_schema response still
+// fails validation after repair and re-asking.
+var ErrStructuredOutput = errors.New("nexus: response does not match json_schema")
+
+// StructuredOutputTransform enforces json_schema response formats for
+// providers that do not honour them strictly.
+//
+// On input, when the request may be served by a provider without native
+// JSON Schema support (provider.Capabilities.JSONSchema), the schema is
+// added to the prompt as instructions. On output, each choice's content is
+// validated; invalid content is first repaired locally (code fences,
+// surrounding prose, trailing commas) and then, up to maxRetries times, the
+// model is re-asked with the validation errors. Re-asks go directly to the
+// provider that served the response and their usage and cost are added to
+// the response's. Streamed responses are not validated.
+//
+// When the schema is strict and the output is still invalid, the transform
+// fails the request with ErrStructuredOutput; otherwise the violations are
+// recorded under StateKeyStructuredOutputErrors.
+type StructuredOutputTransform struct {
+	providers  provider.Registry
+	aliases    model.AliasRegistry
+	maxRetries int
+}
+
+// NewStructuredOutput creates a structured output transform. providers is
+// used to check native schema support and to re-ask; with a nil registry the
+// schema is always injected and invalid output is only repaired locally.
+func NewStructuredOutput(providers provider.Registry) *StructuredOutputTransform {
+	return &StructuredOutputTransform{providers: providers, maxRetries: 2}
+}
+
+// WithMaxRetries sets how many times the model is re-asked.
+func (t *StructuredOutputTransform) WithMaxRetries(n int) *StructuredOutputTransform {
+	t.maxRetries = n
+	return t
+}
+
+// WithAliases resolves model aliases when checking native schema support.
+// Transforms run before alias resolution, so without it an aliased model
+// is never matched to its providers and the schema is always injected.
+func (t *StructuredOutputTransform) WithAliases(aliases model.AliasRegistry) *StructuredOutputTransform {
+	t.aliases = aliases
+	return t
+}
+
+func (t *StructuredOutputTransform) Name() string { return "structured_output" }
+func (t *StructuredOutputTransform) Phase() Phase { return PhaseOutput }
+
+// requestSchema returns the request's JSON Schema, if any.
+func requestSchema(req *provider.CompletionRequest) (schema any, strict bool) {
+	rf := req.ResponseFormat
+	if rf == nil || rf.Type != "json_schema" {
+		return nil, false
+	}
+	if rf.JSONSchema != nil {
+		return rf.JSONSchema.Schema, rf.JSONSchema.Strict
+	}
+	return rf.Schema, false
+}
+
+// TransformInput injects schema instructions when a candidate provider lacks
+// native json_schema support.
+func (t *StructuredOutputTransform) TransformInput(ctx context.Context, req *provider.CompletionRequest) error {
+	schema, _ := requestSchema(req)
+	if schema == nil || t.nativeSupport(ctx, req) {
+		return nil
+	}
+	doc, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil
+	}
+	instructions := "Respond only with a single JSON value that validates against this JSON Schema. " +
+		"Do not wrap it in code fences or add any other text.\n\n" + string(doc)
+
+	insertIdx := 0
+	for i, msg := range req.Messages {
+		if msg.Role != "system" {
+			break
+		}
+		insertIdx = i + 1
+	}
+	// Build a new slice; the caller may still hold req.Messages.
+	msgs := make([]provider.Message, 0, len(req.Messages)+1)
+	msgs = append(msgs, req.Messages[:insertIdx]...)
+	msgs = append(msgs, provider.Message{Role: "system", Content: instructions})
+	req.Messages = append(msgs, req.Messages[insertIdx:]...)
+	return nil
+}
+
+// nativeSupport reports whether every provider that may serve req enforces
+// json_schema itself.
+func (t *StructuredOutputTransform) nativeSupport(ctx context.Context, req *provider.CompletionRequest) bool {
+	if t.providers == nil {
+		return false
+	}
+	var candidates []provider.Provider
+	if req.Provider != "" {
+		if p, ok := t.providers.Get(req.Provider); ok {
+			candidates = append(candidates, p)
+		}
+	} else {
+		// Any of an alias's targets may serve the request.
+		models := []string{req.Model}
+		if t.aliases != nil {
+			if targets, err := t.aliases.Resolve(ctx, req.Model, pipeline.TenantID(ctx)); err == nil && len(targets) > 0 {
+				models = models[:0]
+				for _, target := range targets {
+					models = append(models, target.Model)
+				}
+			}
+		}
+		for _, m := range models {
+			candidates = append(candidates, listingProviders(ctx, t.providers.ForModel(m), m)...)
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+	for _, p := range candidates {
+		if !p.Capabilities().JSONSchema {
+			return false
+		}
+	}
+	return true
+}
+
+// listingProviders narrows providers to those whose catalog lists modelID,
+// as routing does, falling back to all of them when none does.
+func listingProviders(ctx context.Context, providers []provider.Provider, modelID string) []provider.Provider {
+	var matched []provider.Provider
+	for _, p := range providers {
+		models, err := p.Models(ctx)
+		if err != nil {
+			continue
+		}
+		for _, m := range models {
+			if m.ID == modelID {
+				matched = append(matched, p)
+				break
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return providers
+	}
+	return matched
+}
+
+func (t *StructuredOutputTransform) TransformOutput(ctx context.Context, req *provider.CompletionRequest, resp *provider.CompletionResponse) error {
+	schema, strict := requestSchema(req)
+	if schema == nil {
+		return nil
+	}
+
+	var remaining []string
+	for i := range resp.Choices {
+		msg := &resp.Choices[i].Message
+		content, ok := msg.Content.(string)
+		if !ok {
+			continue
+		}
+
+		fixed, errs := validateJSON(schema, content)
+		for attempt := 0; len(errs) > 0 && attempt < t.maxRetries; attempt++ {
+			retry, err := t.reask(ctx, req, resp, content, errs)
+			if err != nil {
+				break
+			}
+			content = retry
+			fixed, errs = validateJSON(schema, content)
+		}
+		if len(errs) > 0 {
+			remaining = append(remaining, errs...)
+			continue
+		}
+		msg.Content = fixed
+	}
+
+	if len(remaining) == 0 {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("%w: %s", ErrStructuredOutput, strings.Join(remaining, "; "))
+	}
+	if resp.State == nil {
+		resp.State = make(map[string]any)
+	}
+	resp.State[StateKeyStructuredOutputErrors] = remaining
+	return nil
+}
+
+// reask asks the serving provider to correct its output.
+func (t *StructuredOutputTransform) reask(ctx context.Context, req *provider.CompletionRequest, resp *provider.CompletionResponse, bad string, errs []string) (string, error) {
+	if t.providers == nil {
+		return "", errors.New("no provider registry")
+	}
+	name := resp.Provider
+	if name == "" {
+		name = req.Provider
+	}
+	p, ok := t.providers.Get(name)
+	if !ok {
+		return "", fmt.Errorf("provider %q not registered", name)
+	}
+
+	retry := *req
+	retry.Stream = false
+	retry.Messages = append(append([]provider.Message(nil), req.Messages...),
+		provider.Message{Role: "assistant", Content: bad},
+		provider.Message{Role: "user", Content: "Your previous response did not match the required JSON Schema:\n- " +
+			strings.Join(errs, "\n- ") + "\n\nReply with the corrected JSON only."},
+	)
+	if resp.Model != "" {
+		retry.Model = resp.Model
+	}
+
+	out, err := p.Complete(ctx, &retry)
+	if err != nil {
+		return "", err
+	}
+	resp.Usage.PromptTokens += out.Usage.PromptTokens
+	resp.Usage.CompletionTokens += out.Usage.CompletionTokens
+	resp.Usage.TotalTokens += out.Usage.TotalTokens
+	resp.Usage.CacheReadTokens += out.Usage.CacheReadTokens
+	resp.Usage.CacheWriteTokens += out.Usage.CacheWriteTokens
+	cost := out.Cost
+	if cost == 0 {
+		if pricing, ok := model.FindPricing(ctx, p, retry.Model); ok {
+			cost = model.EstimateCost(out.Usage, pricing).TotalCost
+		}
+	}
+	resp.Cost += cost
+	if len(out.Choices) == 0 {
+		return "", errors.New("empty response")
+	}
+	content, ok := out.Choices[0].Message.Content.(string)
+	if !ok {
+		return "", errors.New("non-text response")
+	}
+	return content, nil
+}
+
+// validateJSON parses content (repairing it if needed) and validates it.
+// It returns the normalized JSON text and any violations.
+func validateJSON(schema any, content string) (string, []string) {
+	var v any
+	text := content
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		text = repairJSON(content)
+		if err := json.Unmarshal([]byte(text), &v); err != nil {
+			return content, []string{"$: invalid JSON: " + err.Error()}
+		}
+	}
+	if errs := validateSchema(schema, v); len(errs) > 0 {
+		return content, errs
+	}
+	return text, nil
+}
+
+var (
+	codeFenceRe     = regexp.MustCompile("(?s)```(?:json|JSON)?\\s*(.*?)\\s*```")
+	trailingCommaRe = regexp.MustCompile(`,(\s*[}\]])`)
+)
+
+// repairJSON applies cheap fixes for common model mistakes: code fences,
+// prose around the JSON value, trailing commas and smart quotes.
+func repairJSON(s string) string {
+	s = strings.TrimSpace(s)
+	if m := codeFenceRe.FindStringSubmatch(s); m != nil {
+		s = m[1]
+	}
+	if start := strings.IndexAny(s, "{["); start > 0 {
+		s = s[start:]
+	}
+	if end := strings.LastIndexAny(s, "}]"); end >= 0 && end < len(s)-1 {
+		s = s[:end+1]
+	}
+	s = strings.NewReplacer("“", `"`, "”", `"`).Replace(s)
+	return trailingCommaRe.ReplaceAllString(s, "$1")
+}
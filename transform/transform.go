@@ -63,6 +63,15 @@ type StreamingOutputTransform interface {
 	TransformAccumulated(ctx context.Context, req *provider.CompletionRequest, resp *provider.CompletionResponse) error
 }
 
+// StreamFlusher is implemented by streaming output transforms that hold
+// back text between chunks. FlushStream runs once when the upstream stream
+// ends and may return a final chunk carrying the held-back content (nil
+// when there is nothing to release). The chunk passes through the
+// streaming transforms registered after the flusher.
+type StreamFlusher interface {
+	FlushStream(ctx context.Context, req *provider.CompletionRequest) (*provider.StreamChunk, error)
+}
+
 // Registry manages transforms.
 type Registry struct {
 	input            []InputTransform
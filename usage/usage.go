@@ -14,7 +14,8 @@ type Record struct {
 	TenantID         id.TenantID   `json:"tenant_id"`
 	KeyID            id.KeyID      `json:"key_id"`
 	RequestID        id.RequestID  `json:"request_id"`
-	Provider         string        `json:"provider"`
+	Provider         string        `json:"provider"`                // instance that served the request
+	ProviderType     string        `json:"provider_type,omitempty"` // its type, e.g. "openai" for an "openai-eu" instance
 	Model            string        `json:"model"`
 	PromptTokens     int           `json:"prompt_tokens"`
 	CompletionTokens int           `json:"completion_tokens"`
@@ -23,6 +24,11 @@ type Record struct {
 	Latency          time.Duration `json:"latency"`
 	Cached           bool          `json:"cached"`
 	StatusCode       int           `json:"status_code"`
+	PromptID         string        `json:"prompt_id,omitempty"`      // registry template rendered for the request
+	PromptVersion    int           `json:"prompt_version,omitempty"` // its resolved version
+	AudioSeconds     float64       `json:"audio_seconds,omitempty"`  // transcribed audio duration
+	Characters       int           `json:"characters,omitempty"`     // text synthesized to speech
+	BatchID          string        `json:"batch_id,omitempty"`       // batch job the request belonged to
 	CreatedAt        time.Time     `json:"created_at"`
 }
 